@@ -4,7 +4,12 @@
 // Licensed under the MIT License
 package enigoma
 
-import "github.com/coredds/enigoma/internal/alphabet"
+import (
+	"strings"
+	"unicode"
+
+	"github.com/coredds/enigoma/internal/alphabet"
+)
 
 // Common predefined alphabets that can be used with enigoma.
 var (
@@ -86,8 +91,105 @@ var (
 		// Space and common punctuation
 		' ', '.', ',', '!', '?', ';', ':', '-', '\'', '"', '(', ')',
 	}
+
+	// AlphabetArabic contains the 28 Arabic letters, the hamza forms, tāʾ
+	// marbūṭa and alif maqṣūra, and the Arabic-Indic digits
+	// Total: 46 characters
+	AlphabetArabic = []rune{
+		// Letters
+		'ا', 'ب', 'ت', 'ث', 'ج', 'ح', 'خ', 'د', 'ذ', 'ر', 'ز', 'س', 'ش', 'ص',
+		'ض', 'ط', 'ظ', 'ع', 'غ', 'ف', 'ق', 'ك', 'ل', 'م', 'ن', 'ه', 'و', 'ي',
+
+		// Hamza forms, tāʾ marbūṭa and alif maqṣūra
+		'ء', 'آ', 'أ', 'ؤ', 'إ', 'ئ', 'ة', 'ى',
+
+		// Arabic-Indic digits
+		'٠', '١', '٢', '٣', '٤', '٥', '٦', '٧', '٨', '٩',
+	}
+
+	// AlphabetHebrew contains the 22 Hebrew letters, the five final forms and space
+	// Total: 28 characters
+	AlphabetHebrew = []rune{
+		// Letters
+		'א', 'ב', 'ג', 'ד', 'ה', 'ו', 'ז', 'ח', 'ט', 'י', 'כ',
+		'ל', 'מ', 'נ', 'ס', 'ע', 'פ', 'צ', 'ק', 'ר', 'ש', 'ת',
+
+		// Final forms
+		'ך', 'ם', 'ן', 'ף', 'ץ',
+
+		// Space
+		' ',
+	}
+
+	// AlphabetHangul contains every precomposed Hangul syllable, U+AC00 to U+D7A3
+	// Total: 11,172 characters
+	AlphabetHangul = hangulSyllables()
+
+	// AlphabetKana contains Hiragana and Katakana, including small and voiced
+	// kana, with the Japanese comma, full stop, middle dot and long vowel mark
+	// Total: 180 characters
+	AlphabetKana = []rune{
+		// Hiragana
+		'ぁ', 'あ', 'ぃ', 'い', 'ぅ', 'う', 'ぇ', 'え', 'ぉ', 'お', 'か', 'が', 'き', 'ぎ', 'く', 'ぐ',
+		'け', 'げ', 'こ', 'ご', 'さ', 'ざ', 'し', 'じ', 'す', 'ず', 'せ', 'ぜ', 'そ', 'ぞ', 'た', 'だ',
+		'ち', 'ぢ', 'っ', 'つ', 'づ', 'て', 'で', 'と', 'ど', 'な', 'に', 'ぬ', 'ね', 'の', 'は', 'ば',
+		'ぱ', 'ひ', 'び', 'ぴ', 'ふ', 'ぶ', 'ぷ', 'へ', 'べ', 'ぺ', 'ほ', 'ぼ', 'ぽ', 'ま', 'み', 'む',
+		'め', 'も', 'ゃ', 'や', 'ゅ', 'ゆ', 'ょ', 'よ', 'ら', 'り', 'る', 'れ', 'ろ', 'ゎ', 'わ', 'ゐ',
+		'ゑ', 'を', 'ん', 'ゔ', 'ゕ', 'ゖ',
+
+		// Katakana
+		'ァ', 'ア', 'ィ', 'イ', 'ゥ', 'ウ', 'ェ', 'エ', 'ォ', 'オ', 'カ', 'ガ', 'キ', 'ギ', 'ク', 'グ',
+		'ケ', 'ゲ', 'コ', 'ゴ', 'サ', 'ザ', 'シ', 'ジ', 'ス', 'ズ', 'セ', 'ゼ', 'ソ', 'ゾ', 'タ', 'ダ',
+		'チ', 'ヂ', 'ッ', 'ツ', 'ヅ', 'テ', 'デ', 'ト', 'ド', 'ナ', 'ニ', 'ヌ', 'ネ', 'ノ', 'ハ', 'バ',
+		'パ', 'ヒ', 'ビ', 'ピ', 'フ', 'ブ', 'プ', 'ヘ', 'ベ', 'ペ', 'ホ', 'ボ', 'ポ', 'マ', 'ミ', 'ム',
+		'メ', 'モ', 'ャ', 'ヤ', 'ュ', 'ユ', 'ョ', 'ヨ', 'ラ', 'リ', 'ル', 'レ', 'ロ', 'ヮ', 'ワ', 'ヰ',
+		'ヱ', 'ヲ', 'ン', 'ヴ', 'ヵ', 'ヶ', 'ヷ', 'ヸ', 'ヹ', 'ヺ',
+
+		// Punctuation and the long vowel mark
+		'、', '。', '・', 'ー',
+	}
+
+	// AlphabetThai contains the Thai consonants, vowels, tone marks, digits and
+	// punctuation: the whole Thai block except the baht sign
+	// Total: 86 characters
+	AlphabetThai = []rune{
+		// Consonants
+		'ก', 'ข', 'ฃ', 'ค', 'ฅ', 'ฆ', 'ง', 'จ', 'ฉ', 'ช', 'ซ', 'ฌ', 'ญ', 'ฎ', 'ฏ', 'ฐ',
+		'ฑ', 'ฒ', 'ณ', 'ด', 'ต', 'ถ', 'ท', 'ธ', 'น', 'บ', 'ป', 'ผ', 'ฝ', 'พ', 'ฟ', 'ภ',
+		'ม', 'ย', 'ร', 'ฤ', 'ล', 'ฦ', 'ว', 'ศ', 'ษ', 'ส', 'ห', 'ฬ', 'อ', 'ฮ',
+
+		// Vowels, tone marks and signs
+		'ฯ', 'ะ', 'ั', 'า', 'ำ', 'ิ', 'ี', 'ึ', 'ื', 'ุ', 'ู', 'ฺ', 'เ', 'แ',
+		'โ', 'ใ', 'ไ', 'ๅ', 'ๆ', '็', '่', '้', '๊', '๋', '์', 'ํ', '๎',
+
+		// Thai digits and punctuation
+		'๐', '๑', '๒', '๓', '๔', '๕', '๖', '๗', '๘', '๙', '๏', '๚', '๛',
+	}
+
+	// AlphabetBytes contains the 256 runes U+0000 to U+00FF, one per byte
+	// value, for encrypting binary data (see enigma.NewByteEnigma)
+	// Total: 256 characters
+	AlphabetBytes = byteValues()
 )
 
+// hangulSyllables returns the Hangul syllables block in code point order.
+func hangulSyllables() []rune {
+	runes := make([]rune, 0, 0xd7a4-0xac00)
+	for r := rune(0xac00); r <= 0xd7a3; r++ {
+		runes = append(runes, r)
+	}
+	return runes
+}
+
+// byteValues returns the runes U+0000 to U+00FF in order.
+func byteValues() []rune {
+	runes := make([]rune, 256)
+	for i := range runes {
+		runes[i] = rune(i)
+	}
+	return runes
+}
+
 // NewAlphabetFromPredefined creates an alphabet.Alphabet from one of the predefined sets.
 func NewAlphabetFromPredefined(runes []rune) (*alphabet.Alphabet, error) {
 	return alphabet.New(runes)
@@ -97,3 +199,107 @@ func NewAlphabetFromPredefined(runes []rune) (*alphabet.Alphabet, error) {
 func GetAlphabetSize(runes []rune) int {
 	return len(runes)
 }
+
+// PredefinedAlphabet describes one of the named predefined alphabets.
+type PredefinedAlphabet struct {
+	Name        string   // Canonical name accepted by the CLI (e.g. "latin")
+	Aliases     []string // Alternative names
+	Description string
+	Runes       []rune
+}
+
+// Size returns the number of characters in the alphabet.
+func (p PredefinedAlphabet) Size() int {
+	return len(p.Runes)
+}
+
+// ReflectorCompatible reports whether the alphabet can be used with a reflector,
+// which pairs characters and therefore requires an even size.
+func (p PredefinedAlphabet) ReflectorCompatible() bool {
+	return len(p.Runes)%2 == 0
+}
+
+// predefinedAlphabets is the single source of truth for named alphabets.
+var predefinedAlphabets = []PredefinedAlphabet{
+	{Name: "latin", Aliases: []string{"latin-upper"}, Description: "Uppercase Latin letters A-Z (classic Enigma)", Runes: AlphabetLatinUpper},
+	{Name: "latin-lower", Description: "Lowercase Latin letters a-z", Runes: AlphabetLatinLower},
+	{Name: "greek", Description: "Greek letters, upper and lower case (Ελληνικά)", Runes: AlphabetGreek},
+	{Name: "cyrillic", Description: "Russian Cyrillic letters, upper and lower case (Кириллица)", Runes: AlphabetCyrillic},
+	{Name: "arabic", Description: "Arabic letters, hamza forms and Arabic-Indic digits (العربية)", Runes: AlphabetArabic},
+	{Name: "hebrew", Description: "Hebrew letters, final forms and space (עברית)", Runes: AlphabetHebrew},
+	{Name: "hangul", Aliases: []string{"korean"}, Description: "All 11,172 precomposed Hangul syllables (한글)", Runes: AlphabetHangul},
+	{Name: "kana", Aliases: []string{"japanese-kana"}, Description: "Hiragana and Katakana with Japanese punctuation (かな・カナ)", Runes: AlphabetKana},
+	{Name: "thai", Description: "Thai consonants, vowels, tone marks and digits (ภาษาไทย)", Runes: AlphabetThai},
+	{Name: "portuguese", Description: "Brazilian Portuguese letters, accents, space and punctuation (Português)", Runes: AlphabetPortuguese},
+	{Name: "ascii", Description: "All printable ASCII characters, space through tilde", Runes: AlphabetASCIIPrintable},
+	{Name: "alphanumeric", Description: "Digits plus upper and lower case Latin letters", Runes: AlphabetAlphaNumeric},
+	{Name: "digits", Description: "Digits 0-9", Runes: AlphabetDigits},
+	{Name: "bytes", Aliases: []string{"binary"}, Description: "All 256 byte values, for encrypt --binary (not for text)", Runes: AlphabetBytes},
+}
+
+// PredefinedAlphabets returns all named predefined alphabets in display order.
+func PredefinedAlphabets() []PredefinedAlphabet {
+	result := make([]PredefinedAlphabet, len(predefinedAlphabets))
+	copy(result, predefinedAlphabets)
+	return result
+}
+
+// PredefinedAlphabetNames returns the canonical names of all predefined alphabets.
+func PredefinedAlphabetNames() []string {
+	names := make([]string, len(predefinedAlphabets))
+	for i, p := range predefinedAlphabets {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// LookupAlphabet finds a predefined alphabet by name or alias, ignoring case.
+func LookupAlphabet(name string) (PredefinedAlphabet, bool) {
+	for _, p := range predefinedAlphabets {
+		if strings.EqualFold(p.Name, name) {
+			return p, true
+		}
+		for _, alias := range p.Aliases {
+			if strings.EqualFold(alias, name) {
+				return p, true
+			}
+		}
+	}
+	return PredefinedAlphabet{}, false
+}
+
+// AlphabetFromRanges returns every rune in the range tables, such as
+// unicode.Cyrillic or unicode.Nd, in code point order, for use with
+// enigma.WithAlphabet. Large tables make large alphabets: unicode.L alone
+// has well over 100,000 letters.
+func AlphabetFromRanges(tables ...*unicode.RangeTable) ([]rune, error) {
+	alph, err := alphabet.NewFromRanges(tables...)
+	if err != nil {
+		return nil, err
+	}
+	return alph.Runes(), nil
+}
+
+// AlphabetFromCategories returns every rune in the named Unicode general
+// categories, such as "Lu" or "Nd", in code point order.
+func AlphabetFromCategories(names ...string) ([]rune, error) {
+	alph, err := alphabet.NewFromCategories(names...)
+	if err != nil {
+		return nil, err
+	}
+	return alph.Runes(), nil
+}
+
+// ParseAlphabetRanges returns the runes of a list of ranges as taken by
+// 'enigoma keygen --alphabet-ranges': comma-separated categories ("Nd"),
+// scripts ("Cyrillic"), character ranges ("A-Z", "U+0410-U+044F") and single
+// characters ("U+00C7"), where terms joined by "&" keep only the runes in all
+// of them. "Cyrillic&Lu,0-9" is the uppercase Cyrillic letters plus the
+// ASCII digits.
+func ParseAlphabetRanges(spec string) ([]rune, error) {
+	alph, err := alphabet.NewFromRangeSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	return alph.Runes(), nil
+}
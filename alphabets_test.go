@@ -3,7 +3,9 @@
 package enigoma
 
 import (
+	"slices"
 	"testing"
+	"unicode"
 )
 
 func TestAlphabetPortuguese(t *testing.T) {
@@ -151,3 +153,124 @@ func TestAlphabetPortugueseNoDuplicates(t *testing.T) {
 		seen[char] = true
 	}
 }
+
+func TestPredefinedAlphabets(t *testing.T) {
+	all := PredefinedAlphabets()
+	if len(all) != len(PredefinedAlphabetNames()) {
+		t.Fatalf("names and alphabets out of sync")
+	}
+
+	seen := make(map[string]bool)
+	for _, p := range all {
+		if seen[p.Name] {
+			t.Errorf("duplicate alphabet name %q", p.Name)
+		}
+		seen[p.Name] = true
+		if p.Size() == 0 || p.Description == "" {
+			t.Errorf("alphabet %q is missing runes or description", p.Name)
+		}
+		if p.ReflectorCompatible() != (p.Size()%2 == 0) {
+			t.Errorf("alphabet %q reflector compatibility should follow size parity", p.Name)
+		}
+	}
+
+	// Callers must not be able to mutate the registry
+	all[0].Name = "changed"
+	if PredefinedAlphabets()[0].Name == "changed" {
+		t.Error("PredefinedAlphabets should return a copy")
+	}
+}
+
+func TestLookupAlphabet(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected int
+		found    bool
+	}{
+		{"latin", 26, true},
+		{"LATIN-UPPER", 26, true},
+		{"portuguese", 88, true},
+		{"ascii", 95, true},
+		{"arabic", 46, true},
+		{"hebrew", 28, true},
+		{"korean", 11172, true},
+		{"kana", 180, true},
+		{"thai", 86, true},
+		{"klingon", 0, false},
+	}
+
+	for _, tt := range tests {
+		p, ok := LookupAlphabet(tt.name)
+		if ok != tt.found {
+			t.Errorf("LookupAlphabet(%q) found = %v, want %v", tt.name, ok, tt.found)
+			continue
+		}
+		if ok && p.Size() != tt.expected {
+			t.Errorf("LookupAlphabet(%q) size = %d, want %d", tt.name, p.Size(), tt.expected)
+		}
+	}
+}
+
+func TestAlphabetFromRanges(t *testing.T) {
+	runes, err := AlphabetFromRanges(unicode.Greek)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(runes, '\u03a9') {
+		t.Error("the Greek script should include omega")
+	}
+
+	upper, err := AlphabetFromCategories("Lu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(upper, 'A') || slices.Contains(upper, 'a') {
+		t.Error("Lu should hold uppercase letters only")
+	}
+
+	parsed, err := ParseAlphabetRanges("A-Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(parsed, AlphabetLatinUpper) {
+		t.Errorf("A-Z = %q, want the latin alphabet", string(parsed))
+	}
+}
+
+func TestScriptAlphabets(t *testing.T) {
+	tests := []struct {
+		name   string
+		script *unicode.RangeTable
+		sample string
+	}{
+		{"arabic", unicode.Arabic, "\u0645\u0631\u062d\u0628\u0627\u0661\u0662"},
+		{"hebrew", unicode.Hebrew, "\u05e9\u05dc\u05d5\u05dd \u05e2\u05d5\u05dc\u05dd"},
+		{"hangul", unicode.Hangul, "\uc548\ub155\ud558\uc138\uc694"},
+		{"kana", nil, "\u3053\u3093\u306b\u3061\u306f\u3001\u30b3\u30fc\u30d2\u30fc\u3002"},
+		{"thai", unicode.Thai, "\u0e2a\u0e27\u0e31\u0e2a\u0e14\u0e35\u0e52\u0e55"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, ok := LookupAlphabet(tt.name)
+			if !ok {
+				t.Fatalf("alphabet %q is not registered", tt.name)
+			}
+			if !p.ReflectorCompatible() {
+				t.Errorf("alphabet %q has an odd size %d", tt.name, p.Size())
+			}
+			if _, err := NewAlphabetFromPredefined(p.Runes); err != nil {
+				t.Errorf("alphabet %q: %v", tt.name, err)
+			}
+			for _, r := range p.Runes {
+				if tt.script != nil && !unicode.Is(tt.script, r) && r != ' ' {
+					t.Errorf("alphabet %q: %U is not in the script", tt.name, r)
+				}
+			}
+			for _, r := range tt.sample {
+				if !slices.Contains(p.Runes, r) {
+					t.Errorf("alphabet %q does not support %U from the sample", tt.name, r)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+// Package main provides the JavaScript bindings of the WebAssembly build,
+// kept apart from syscall/js so they can be tested with the regular
+// toolchain.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package main
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/coredds/enigoma/pkg/enigma"
+)
+
+// argument is a JavaScript argument: its type name as JavaScript's typeof
+// gives it, and its value when it is a string.
+type argument struct {
+	kind  string
+	value string
+}
+
+// result is the object a binding returns to JavaScript.
+type result map[string]any
+
+// encryptText wraps enigma.EncryptText: encryptText(text) returns
+// {encrypted, config}, config being the settings JSON to decrypt with.
+func encryptText(args []argument) result {
+	text, err := stringArgs(args, "encryptText(text)", 1)
+	if err != nil {
+		return errorResult(err)
+	}
+	encrypted, config, err := enigma.EncryptText(text[0])
+	if err != nil {
+		return errorResult(err)
+	}
+	return result{"encrypted": encrypted, "config": config}
+}
+
+// decryptWithConfig wraps enigma.DecryptWithConfig:
+// decryptWithConfig(encrypted, config) returns {text}.
+func decryptWithConfig(args []argument) result {
+	strs, err := stringArgs(args, "decryptWithConfig(encrypted, config)", 2)
+	if err != nil {
+		return errorResult(err)
+	}
+	text, err := enigma.DecryptWithConfig(strs[0], strs[1])
+	if err != nil {
+		return errorResult(err)
+	}
+	return result{"text": text}
+}
+
+// decryptContainer decrypts the container of an HTML export with a key file
+// the recipient picked: decryptContainer(container, keyFile, fileName,
+// password) returns {text, verified}. The file name selects YAML or TOML by
+// its extension, and the password is only needed for an encrypted key file.
+// A key whose fingerprint differs from the container's is refused, and
+// verified reports whether the container had a plaintext check that passed.
+func decryptContainer(args []argument) result {
+	strs, err := stringArgs(args, "decryptContainer(container, keyFile, fileName, password)", 4)
+	if err != nil {
+		return errorResult(err)
+	}
+	container, err := enigma.ParseContainer(strings.TrimSpace(strs[0]))
+	if err != nil {
+		return errorResult(err)
+	}
+	machine, err := loadKeyFile(strs[1], strs[2], strs[3])
+	if err != nil {
+		return errorResult(err)
+	}
+	if container.KeyFingerprint != "" {
+		fingerprint, err := machine.Fingerprint()
+		if err != nil {
+			return errorResult(err)
+		}
+		if fingerprint != container.KeyFingerprint {
+			return errorResult(fmt.Errorf("%s is not the key this message was encrypted with (fingerprint %s, want %s)", strs[2], fingerprint, container.KeyFingerprint))
+		}
+	}
+	text, err := machine.Decrypt(container.Ciphertext)
+	if err != nil {
+		return errorResult(fmt.Errorf("decryption failed: %v", err))
+	}
+	ok, err := container.VerifyPlaintext(text)
+	if err != nil {
+		return errorResult(err)
+	}
+	if !ok {
+		return errorResult(fmt.Errorf("the decrypted text does not match the message's plaintext check: wrong key or rotor positions"))
+	}
+	return result{"text": text, "verified": container.HasPlaintextCheck()}
+}
+
+// loadKeyFile creates the machine for the contents of a key file, in the
+// format its name's extension gives, JSON otherwise.
+func loadKeyFile(data, name, password string) (*enigma.Enigma, error) {
+	if enigma.IsEncryptedConfig(data) {
+		if password == "" {
+			return nil, fmt.Errorf("%s is encrypted; enter its password", name)
+		}
+		return enigma.NewFromEncryptedJSON(data, password)
+	}
+	switch strings.ToLower(path.Ext(name)) {
+	case ".yaml", ".yml":
+		return enigma.NewFromYAML(data)
+	case ".toml":
+		return enigma.NewFromTOML(data)
+	}
+	return enigma.NewFromJSON(data)
+}
+
+// stringArgs checks that a call passed exactly n strings and returns them.
+func stringArgs(args []argument, usage string, n int) ([]string, error) {
+	if len(args) != n {
+		return nil, fmt.Errorf("usage: %s", usage)
+	}
+	strs := make([]string, n)
+	for i, arg := range args {
+		if arg.kind != "string" {
+			return nil, fmt.Errorf("usage: %s: argument %d is a %s, not a string", usage, i+1, arg.kind)
+		}
+		strs[i] = arg.value
+	}
+	return strs, nil
+}
+
+func errorResult(err error) result {
+	return result{"error": err.Error()}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/coredds/enigoma/pkg/enigma"
+)
+
+func strArgs(values ...string) []argument {
+	args := make([]argument, len(values))
+	for i, v := range values {
+		args[i] = argument{kind: "string", value: v}
+	}
+	return args
+}
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	plaintext := "Hello, W\u00f6rld! \u4f60\u597d"
+	encrypted := encryptText(strArgs(plaintext))
+	if encrypted["error"] != nil {
+		t.Fatalf("encryptText failed: %v", encrypted["error"])
+	}
+	cipher, _ := encrypted["encrypted"].(string)
+	config, _ := encrypted["config"].(string)
+	if cipher == "" || cipher == plaintext || !strings.Contains(config, "schema_version") {
+		t.Fatalf("encryptText = %v", encrypted)
+	}
+
+	decrypted := decryptWithConfig(strArgs(cipher, config))
+	if decrypted["text"] != plaintext || decrypted["error"] != nil {
+		t.Errorf("decryptWithConfig = %v, want text %q", decrypted, plaintext)
+	}
+}
+
+func TestDecryptContainer(t *testing.T) {
+	machine, err := enigma.NewFromPreset("m3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyJSON, err := machine.SaveSettingsToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyYAML, err := machine.SaveSettingsToYAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := machine.SaveSettingsEncrypted("hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fingerprint, _ := machine.Fingerprint()
+	encrypter, _ := machine.Clone()
+	ciphertext, err := encrypter.Encrypt("HELLOWORLD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := enigma.NewContainer(ciphertext)
+	c.KeyFingerprint = fingerprint
+	if err := c.AddPlaintextCheck("HELLOWORLD"); err != nil {
+		t.Fatal(err)
+	}
+	container, _ := c.Marshal()
+
+	for _, args := range [][]string{
+		{container, keyJSON, "key.json", ""},
+		{container, keyYAML, "key.yaml", ""},
+		{container, sealed, "key.json", "hunter2"},
+	} {
+		got := decryptContainer(strArgs(args...))
+		if got["text"] != "HELLOWORLD" || got["verified"] != true {
+			t.Errorf("decryptContainer with %s = %v", args[2], got)
+		}
+	}
+
+	other, _ := enigma.NewFromPreset("m4")
+	otherJSON, _ := other.SaveSettingsToJSON()
+	for _, tt := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{container, otherJSON, "other.json", ""}, "not the key this message was encrypted with"},
+		{[]string{container, sealed, "key.json", ""}, "enter its password"},
+		{[]string{"{}", keyJSON, "key.json", ""}, "not an enigoma container"},
+	} {
+		if msg, _ := decryptContainer(strArgs(tt.args...))["error"].(string); !strings.Contains(msg, tt.want) {
+			t.Errorf("decryptContainer(%s) error = %q, want %q", tt.args[2], msg, tt.want)
+		}
+	}
+}
+
+func TestBindingErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		got  result
+		want string
+	}{
+		{"no arguments", encryptText(nil), "usage: encryptText(text)"},
+		{"too many", encryptText(strArgs("A", "B")), "usage: encryptText(text)"},
+		{"not a string", encryptText([]argument{{kind: "number"}}), "argument 1 is a number, not a string"},
+		{"missing config", decryptWithConfig(strArgs("A")), "usage: decryptWithConfig(encrypted, config)"},
+		{"second not a string", decryptWithConfig([]argument{{kind: "string", value: "A"}, {kind: "undefined"}}), "argument 2 is a undefined"},
+		{"bad config", decryptWithConfig(strArgs("A", "{}")), "failed to load configuration"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, _ := tt.got["error"].(string)
+			if !strings.Contains(msg, tt.want) {
+				t.Errorf("error = %q, want it to contain %q", msg, tt.want)
+			}
+			if len(tt.got) != 1 {
+				t.Errorf("an error result should hold only the error, got %v", tt.got)
+			}
+		})
+	}
+}
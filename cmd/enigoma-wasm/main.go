@@ -0,0 +1,51 @@
+//go:build js && wasm
+
+// Package main provides the WebAssembly build of enigoma, which exposes the
+// library's convenience functions to JavaScript.
+//
+// Build it with 'make wasm', load wasm_exec.js and enigoma.wasm in a page,
+// and call the functions on the global enigoma object once the program runs:
+//
+//	const {encrypted, config} = enigoma.encryptText("Hello, World!");
+//	const {text} = enigoma.decryptWithConfig(encrypted, config);
+//
+// decryptContainer(container, keyFile, fileName, password) decrypts the
+// container of an HTML export, which embeds this module.
+//
+// Every function returns an object; on failure it holds only an error field
+// with the message, instead of throwing.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package main
+
+import (
+	"syscall/js"
+
+	"github.com/coredds/enigoma"
+)
+
+func main() {
+	js.Global().Set("enigoma", js.ValueOf(map[string]any{
+		"version":           enigoma.GetVersion(),
+		"encryptText":       jsFunc(encryptText),
+		"decryptWithConfig": jsFunc(decryptWithConfig),
+		"decryptContainer":  jsFunc(decryptContainer),
+	}))
+	// The functions live as long as the program, so it must not exit
+	select {}
+}
+
+// jsFunc exposes a binding to JavaScript, converting its arguments.
+func jsFunc(binding func(args []argument) result) js.Func {
+	return js.FuncOf(func(this js.Value, values []js.Value) any {
+		args := make([]argument, len(values))
+		for i, v := range values {
+			args[i] = argument{kind: v.Type().String()}
+			if v.Type() == js.TypeString {
+				args[i].value = v.String()
+			}
+		}
+		return map[string]any(binding(args))
+	})
+}
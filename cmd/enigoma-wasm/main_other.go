@@ -0,0 +1,18 @@
+//go:build !(js && wasm)
+
+// Package main provides a stand-in for other platforms, where the
+// WebAssembly build has no JavaScript to serve.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	fmt.Fprintln(os.Stderr, "enigoma-wasm runs in a browser: build it with GOOS=js GOARCH=wasm (see 'make wasm')")
+	os.Exit(1)
+}
@@ -7,7 +7,12 @@ package alphabet
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/coredds/enigoma/internal/random"
 )
 
 // Alphabet represents a character set used by the Enigma machine.
@@ -15,7 +20,13 @@ import (
 type Alphabet struct {
 	runes    []rune
 	runeToID map[rune]int
+	lowToID  [256]int // Index of each rune below 256, or -1; spares the map lookup
 	size     int
+
+	// In grapheme mode each symbol is a grapheme cluster, which the
+	// components see as the rune at the same index; see NewFromSymbols
+	symbols    []string
+	symbolToID map[string]int
 }
 
 // New creates a new Alphabet from the provided runes.
@@ -45,11 +56,30 @@ func New(runes []rune) (*Alphabet, error) {
 		runeToID[r] = i
 	}
 
-	return &Alphabet{
+	a := &Alphabet{
 		runes:    runesCopy,
 		runeToID: runeToID,
 		size:     len(runesCopy),
-	}, nil
+	}
+	for i := range a.lowToID {
+		a.lowToID[i] = -1
+	}
+	for i, r := range runesCopy {
+		if r >= 0 && r < 256 {
+			a.lowToID[r] = i
+		}
+	}
+	return a, nil
+}
+
+// lookup returns the index of r, if the alphabet has it.
+func (a *Alphabet) lookup(r rune) (int, bool) {
+	if r >= 0 && r < 256 {
+		idx := a.lowToID[r]
+		return idx, idx >= 0
+	}
+	idx, ok := a.runeToID[r]
+	return idx, ok
 }
 
 // Size returns the number of characters in the alphabet.
@@ -67,7 +97,7 @@ func (a *Alphabet) Runes() []rune {
 // RuneToIndex converts a rune to its index in the alphabet.
 // Returns an error if the rune is not in the alphabet.
 func (a *Alphabet) RuneToIndex(r rune) (int, error) {
-	idx, exists := a.runeToID[r]
+	idx, exists := a.lookup(r)
 	if !exists {
 		return 0, fmt.Errorf("character %c not found in alphabet", r)
 	}
@@ -85,13 +115,18 @@ func (a *Alphabet) IndexToRune(idx int) (rune, error) {
 
 // Contains checks if a rune is present in the alphabet.
 func (a *Alphabet) Contains(r rune) bool {
-	_, exists := a.runeToID[r]
+	_, exists := a.lookup(r)
 	return exists
 }
 
 // ValidateString checks if all runes in the string are present in the alphabet.
-// Returns the first invalid rune found, or 0 if all are valid.
+// Returns the first invalid rune found, or 0 if all are valid. In grapheme
+// mode it checks grapheme clusters and returns the first rune of the invalid
+// one.
 func (a *Alphabet) ValidateString(s string) (rune, error) {
+	if a.symbols != nil {
+		return a.validateSymbols(s)
+	}
 	for _, r := range s {
 		if !a.Contains(r) {
 			return r, fmt.Errorf("character %c not found in alphabet", r)
@@ -102,40 +137,125 @@ func (a *Alphabet) ValidateString(s string) (rune, error) {
 
 // StringToIndices converts a string to a slice of indices.
 func (a *Alphabet) StringToIndices(s string) ([]int, error) {
-	result := make([]int, 0, len(s))
-	for _, r := range s {
-		idx, err := a.RuneToIndex(r)
-		if err != nil {
-			return nil, err
+	if a.symbols != nil {
+		return a.symbolsToIndices(s)
+	}
+	result, _, err := a.AppendIndices(make([]int, 0, len(s)), s)
+	return result, err
+}
+
+// AppendIndices validates s and appends the index of each of its characters
+// to dst in one pass, so callers can reuse dst from call to call. When a
+// character is not in the alphabet it returns that rune, as ValidateString
+// does, and an error.
+func (a *Alphabet) AppendIndices(dst []int, s string) ([]int, rune, error) {
+	if a.symbols != nil {
+		if r, err := a.validateSymbols(s); err != nil {
+			return dst, r, err
 		}
-		result = append(result, idx)
+		indices, err := a.symbolsToIndices(s)
+		return append(dst, indices...), 0, err
 	}
-	return result, nil
+	for i := 0; i < len(s); {
+		// ASCII needs no UTF-8 decoding
+		r, width := rune(s[i]), 1
+		if r >= utf8.RuneSelf {
+			r, width = utf8.DecodeRuneInString(s[i:])
+		}
+		idx, ok := a.lookup(r)
+		if !ok {
+			return dst, r, fmt.Errorf("character %c not found in alphabet", r)
+		}
+		dst = append(dst, idx)
+		i += width
+	}
+	return dst, 0, nil
 }
 
 // IndicesToString converts a slice of indices to a string.
 func (a *Alphabet) IndicesToString(indices []int) (string, error) {
-	runes := make([]rune, 0, len(indices))
+	if a.symbols != nil {
+		return a.indicesToSymbols(indices)
+	}
+	text, err := a.AppendText(make([]byte, 0, len(indices)), indices)
+	if err != nil {
+		return "", err
+	}
+	return string(text), nil
+}
+
+// AppendText appends the characters at indices to dst as UTF-8, so callers
+// can reuse dst from call to call.
+func (a *Alphabet) AppendText(dst []byte, indices []int) ([]byte, error) {
 	for _, idx := range indices {
-		r, err := a.IndexToRune(idx)
+		if idx < 0 || idx >= a.size {
+			return dst, fmt.Errorf("index %d out of bounds [0, %d)", idx, a.size)
+		}
+		switch {
+		case a.symbols != nil:
+			dst = append(dst, a.symbols[idx]...)
+		case a.runes[idx] < utf8.RuneSelf:
+			dst = append(dst, byte(a.runes[idx]))
+		default:
+			dst = utf8.AppendRune(dst, a.runes[idx])
+		}
+	}
+	return dst, nil
+}
+
+// RandomText returns n characters drawn uniformly from the alphabet, for
+// testing and benchmarking a machine without assuming a language.
+func (a *Alphabet) RandomText(n int) (string, error) {
+	return a.RandomTextFrom(random.Crypto, n)
+}
+
+// RandomTextFrom works like RandomText but draws from src, so a
+// deterministic source produces the same text every time.
+func (a *Alphabet) RandomTextFrom(src random.Source, n int) (string, error) {
+	if n < 0 {
+		return "", fmt.Errorf("length cannot be negative: %d", n)
+	}
+	indices := make([]int, n)
+	for i := range indices {
+		idx, err := src.Intn(a.size)
 		if err != nil {
-			return "", err
+			return "", fmt.Errorf("failed to generate random text: %v", err)
 		}
-		runes = append(runes, r)
+		indices[i] = idx
 	}
-	return string(runes), nil
+	return a.IndicesToString(indices)
 }
 
 // AutoDetectFromText creates an alphabet by analyzing the unique characters in the input text.
 // It automatically handles reflector compatibility by ensuring an even number of characters.
-// nolint:gocyclo // This function is necessarily complex due to alphabet detection logic
 func AutoDetectFromText(text string, options ...AutoDetectOption) (*Alphabet, error) {
+	alph, _, err := AutoDetectFromTextReport(text, options...)
+	return alph, err
+}
+
+// AutoDetectReport describes the adjustments made while detecting an alphabet.
+type AutoDetectReport struct {
+	Padding        rune // Character added to reach an even size, or 0 if none
+	Removed        rune // Character left out to reach an even size, or 0 if none
+	MergedInto     rune // With PadMerge, the character to write instead of Removed
+	Normalized     bool // Line endings or surrounding whitespace were changed
+	SkippedControl int  // Control characters left out of the alphabet
+	Truncated      bool // The size limit was reached before all characters were seen
+}
+
+// AutoDetectFromTextReport works like AutoDetectFromText and also reports
+// what was changed along the way, so callers can surface it to users.
+// nolint:gocyclo // This function is necessarily complex due to alphabet detection logic
+func AutoDetectFromTextReport(text string, options ...AutoDetectOption) (*Alphabet, AutoDetectReport, error) {
+	var report AutoDetectReport
 	if text == "" {
-		return nil, fmt.Errorf("cannot auto-detect alphabet from empty text")
+		return nil, report, fmt.Errorf("cannot auto-detect alphabet from empty text")
 	}
 
 	// Preprocess text to handle common issues
+	original := text
 	text = PreprocessTextForAutoDetection(text)
+	report.Normalized = text != original
 
 	config := &autoDetectConfig{
 		maxSize:        1000, // Default safety limit
@@ -147,56 +267,68 @@ func AutoDetectFromText(text string, options ...AutoDetectOption) (*Alphabet, er
 	for _, opt := range options {
 		opt(config)
 	}
+	if config.normalizer != nil {
+		text = config.normalizer(text)
+	}
+	if config.graphemes {
+		return autoDetectSymbols(text, config, report)
+	}
 
-	// Collect unique runes
+	// Collect unique runes, their counts and the order they first appear in
 	uniqueRunes := make(map[rune]bool)
+	counts := make(map[rune]int)
+	var encountered []rune
 	for _, r := range text {
 		// Skip control characters if configured
 		if config.excludeControl && isControlCharacter(r) {
+			report.SkippedControl++
 			continue
 		}
-		uniqueRunes[r] = true
+		counts[r]++
+		if !uniqueRunes[r] {
+			uniqueRunes[r] = true
+			encountered = append(encountered, r)
+		}
 
 		// Safety limit to prevent performance issues
 		if len(uniqueRunes) >= config.maxSize {
+			report.Truncated = true
 			break
 		}
 	}
 
 	if len(uniqueRunes) == 0 {
-		return nil, fmt.Errorf("no valid characters found in text for alphabet")
-	}
-
-	// Convert to ordered slice (deterministic ordering by Unicode codepoint)
-	runes := make([]rune, 0, len(uniqueRunes))
-	for r := range uniqueRunes {
-		runes = append(runes, r)
+		return nil, report, fmt.Errorf("no valid characters found in text for alphabet")
 	}
 
-	// Sort by Unicode codepoint for deterministic behavior
-	for i := 0; i < len(runes)-1; i++ {
-		for j := i + 1; j < len(runes); j++ {
-			if runes[i] > runes[j] {
-				runes[i], runes[j] = runes[j], runes[i]
+	// Order the runes as configured; every ordering is deterministic
+	runes := make([]rune, len(encountered))
+	copy(runes, encountered)
+	switch config.ordering {
+	case OrderAsEncountered:
+		// Already in order of first appearance
+	case OrderFrequency:
+		// Most frequent first, ties by codepoint
+		sort.SliceStable(runes, func(i, j int) bool {
+			if counts[runes[i]] != counts[runes[j]] {
+				return counts[runes[i]] > counts[runes[j]]
 			}
-		}
+			return runes[i] < runes[j]
+		})
+	default:
+		sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
 	}
 
 	// Ensure even size for reflector compatibility
 	if config.addPadding && len(runes)%2 != 0 {
-		// Find a suitable padding character not in the text
-		paddingChar := rune(' ')
-		for uniqueRunes[paddingChar] {
-			paddingChar++
-			// Safety check to avoid infinite loop
-			if paddingChar > 0x10000 {
-				return nil, fmt.Errorf("unable to find suitable padding character for even-sized alphabet")
-			}
+		var err error
+		if runes, err = evenOut(runes, counts, config, &report); err != nil {
+			return nil, report, err
 		}
-		runes = append(runes, paddingChar)
 	}
 
-	return New(runes)
+	alph, err := New(runes)
+	return alph, report, err
 }
 
 // autoDetectConfig holds configuration for auto-detection
@@ -204,6 +336,210 @@ type autoDetectConfig struct {
 	maxSize        int
 	addPadding     bool
 	excludeControl bool
+	ordering       Ordering
+	padding        PaddingStrategy
+	padCandidates  []rune
+	graphemes      bool
+	normalizer     func(string) string
+}
+
+// PaddingStrategy selects how auto-detection makes an odd-sized alphabet even,
+// as a reflector must pair every character.
+type PaddingStrategy int
+
+const (
+	// PadNextCodepoint adds the first visible character from the space upwards
+	// that the text does not use (the default).
+	PadNextCodepoint PaddingStrategy = iota
+	// PadFromList adds the first unused character from a caller-supplied list.
+	PadFromList
+	// PadDropLeastFrequent leaves out the least frequent character, breaking
+	// ties by the highest codepoint. Text containing it can no longer be
+	// encrypted as is.
+	PadDropLeastFrequent
+	// PadMerge leaves out one of two characters that differ only in case, or a
+	// whitespace character other than the space, and reports which character
+	// to write in its place.
+	PadMerge
+)
+
+// String returns the strategy's name, as accepted by ParsePaddingStrategy.
+func (p PaddingStrategy) String() string {
+	switch p {
+	case PadNextCodepoint:
+		return "codepoint"
+	case PadFromList:
+		return "list"
+	case PadDropLeastFrequent:
+		return "drop"
+	case PadMerge:
+		return "merge"
+	default:
+		return "unknown"
+	}
+}
+
+// ParsePaddingStrategy converts a strategy name (codepoint, list, drop or
+// merge) to a PaddingStrategy.
+func ParsePaddingStrategy(name string) (PaddingStrategy, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "codepoint", "":
+		return PadNextCodepoint, nil
+	case "list":
+		return PadFromList, nil
+	case "drop", "drop-least-frequent":
+		return PadDropLeastFrequent, nil
+	case "merge":
+		return PadMerge, nil
+	default:
+		return PadNextCodepoint, fmt.Errorf("unknown padding strategy: %s. Available: codepoint, list, drop, merge", name)
+	}
+}
+
+// evenOut applies the configured padding strategy to an odd-sized set of
+// runes and records the change in report.
+func evenOut(runes []rune, counts map[rune]int, config *autoDetectConfig, report *AutoDetectReport) ([]rune, error) {
+	switch config.padding {
+	case PadFromList:
+		if len(config.padCandidates) == 0 {
+			return nil, fmt.Errorf("the list padding strategy needs padding candidates")
+		}
+		for _, r := range config.padCandidates {
+			if counts[r] == 0 {
+				report.Padding = r
+				return append(runes, r), nil
+			}
+		}
+		return nil, fmt.Errorf("every padding candidate (%q) already occurs in the text", string(config.padCandidates))
+
+	case PadDropLeastFrequent:
+		drop := runes[0]
+		for _, r := range runes[1:] {
+			if counts[r] < counts[drop] || (counts[r] == counts[drop] && r > drop) {
+				drop = r
+			}
+		}
+		report.Removed = drop
+		return without(runes, drop), nil
+
+	case PadMerge:
+		from, into, ok := mergeCandidate(runes, counts)
+		if !ok {
+			return nil, fmt.Errorf("no two characters can be merged (the text has no case or whitespace variants); choose another padding strategy")
+		}
+		report.Removed, report.MergedInto = from, into
+		return without(runes, from), nil
+
+	default:
+		padding, ok := SuggestPadding(runes)
+		if !ok {
+			return nil, fmt.Errorf("unable to find suitable padding character for even-sized alphabet")
+		}
+		report.Padding = padding
+		return append(runes, padding), nil
+	}
+}
+
+// SuggestPadding returns the character PadNextCodepoint would add to runes:
+// the first visible character from the space upwards that is not among them.
+// Characters that would be invisible in an alphabet, such as DEL or the
+// no-break space, are skipped.
+func SuggestPadding(runes []rune) (rune, bool) {
+	present := make(map[rune]bool, len(runes))
+	for _, r := range runes {
+		present[r] = true
+	}
+	for r := rune(' '); r <= 0x10000; r++ {
+		if !present[r] && unicode.IsPrint(r) {
+			return r, true
+		}
+	}
+	return 0, false
+}
+
+// mergeCandidate finds the least frequent character that can be written as
+// another character of the alphabet: its other case, or a space for other
+// whitespace. Ties go to the higher codepoint.
+func mergeCandidate(runes []rune, counts map[rune]int) (from, into rune, ok bool) {
+	present := make(map[rune]bool, len(runes))
+	for _, r := range runes {
+		present[r] = true
+	}
+	for _, r := range runes {
+		var target rune
+		if unicode.IsSpace(r) && r != ' ' && present[' '] {
+			target = ' '
+		} else {
+			for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+				if present[f] {
+					target = f
+					break
+				}
+			}
+		}
+		if target == 0 {
+			continue
+		}
+		if !ok || counts[r] < counts[from] || (counts[r] == counts[from] && r > from) {
+			from, into, ok = r, target, true
+		}
+	}
+	return from, into, ok
+}
+
+// without returns runes with r removed, keeping the order of the rest.
+func without(runes []rune, r rune) []rune {
+	kept := make([]rune, 0, len(runes)-1)
+	for _, c := range runes {
+		if c != r {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+// Ordering selects how auto-detection orders the characters it finds. The
+// order decides each character's index, so tools that exchange keys must
+// agree on it.
+type Ordering int
+
+const (
+	// OrderCodepoint sorts characters by Unicode codepoint (the default).
+	OrderCodepoint Ordering = iota
+	// OrderFrequency puts the most frequent characters first, breaking ties
+	// by codepoint.
+	OrderFrequency
+	// OrderAsEncountered keeps the order in which characters first appear.
+	OrderAsEncountered
+)
+
+// String returns the ordering's name, as accepted by ParseOrdering.
+func (o Ordering) String() string {
+	switch o {
+	case OrderCodepoint:
+		return "codepoint"
+	case OrderFrequency:
+		return "frequency"
+	case OrderAsEncountered:
+		return "encountered"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseOrdering converts an ordering name (codepoint, frequency or
+// encountered) to an Ordering.
+func ParseOrdering(name string) (Ordering, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "codepoint", "":
+		return OrderCodepoint, nil
+	case "frequency":
+		return OrderFrequency, nil
+	case "encountered", "as-encountered":
+		return OrderAsEncountered, nil
+	default:
+		return OrderCodepoint, fmt.Errorf("unknown alphabet ordering: %s. Available: codepoint, frequency, encountered", name)
+	}
 }
 
 // AutoDetectOption is a function that configures auto-detection behavior
@@ -223,6 +559,38 @@ func WithoutPadding() AutoDetectOption {
 	}
 }
 
+// WithPaddingStrategy sets how an odd-sized alphabet is made even
+func WithPaddingStrategy(strategy PaddingStrategy) AutoDetectOption {
+	return func(config *autoDetectConfig) {
+		config.padding = strategy
+	}
+}
+
+// WithPaddingCandidates selects the PadFromList strategy with the given
+// candidates, tried in order
+func WithPaddingCandidates(candidates []rune) AutoDetectOption {
+	return func(config *autoDetectConfig) {
+		config.padding = PadFromList
+		config.padCandidates = append([]rune(nil), candidates...)
+	}
+}
+
+// WithOrdering sets how the detected characters are ordered
+func WithOrdering(ordering Ordering) AutoDetectOption {
+	return func(config *autoDetectConfig) {
+		config.ordering = ordering
+	}
+}
+
+// WithNormalizer applies a Unicode normalization, such as normalize.NFC, to
+// the text before its characters are collected, so that a character written
+// in two forms is detected once
+func WithNormalizer(normalizer func(string) string) AutoDetectOption {
+	return func(config *autoDetectConfig) {
+		config.normalizer = normalizer
+	}
+}
+
 // WithControlCharacters includes control characters in the alphabet
 func WithControlCharacters() AutoDetectOption {
 	return func(config *autoDetectConfig) {
@@ -1,7 +1,12 @@
 package alphabet
 
 import (
+	"slices"
+	"strings"
 	"testing"
+	"unicode/utf8"
+
+	"github.com/coredds/enigoma/internal/random"
 )
 
 func TestNew(t *testing.T) {
@@ -299,6 +304,41 @@ func TestAlphabet_Roundtrip(t *testing.T) {
 	}
 }
 
+func TestAlphabet_AppendIndicesAndText(t *testing.T) {
+	// Runes on both sides of the 256-entry lookup table
+	alphabet, err := New([]rune{'A', '\u00e9', '\u00ff', '\u0100', '\u4e2d'})
+	if err != nil {
+		t.Fatalf("Failed to create alphabet: %v", err)
+	}
+
+	buf := make([]int, 0, 8)
+	indices, bad, err := alphabet.AppendIndices(buf, "\u4e2dA\u00ff\u0100\u00e9")
+	if err != nil || bad != 0 {
+		t.Fatalf("AppendIndices() = %v, %q, %v", indices, bad, err)
+	}
+	if want := []int{4, 0, 2, 3, 1}; !slices.Equal(indices, want) {
+		t.Errorf("AppendIndices() = %v, want %v", indices, want)
+	}
+	if &indices[0] != &buf[:1][0] {
+		t.Error("AppendIndices() should append to the given buffer")
+	}
+
+	text, err := alphabet.AppendText([]byte("> "), indices)
+	if err != nil || string(text) != "> \u4e2dA\u00ff\u0100\u00e9" {
+		t.Errorf("AppendText() = %q, %v", text, err)
+	}
+
+	if _, bad, err := alphabet.AppendIndices(nil, "A\u00feA"); err == nil || bad != '\u00fe' {
+		t.Errorf("AppendIndices() should reject \u00fe, got %q, %v", bad, err)
+	}
+	if _, bad, err := alphabet.AppendIndices(nil, "A\u4e2eA"); err == nil || bad != '\u4e2e' {
+		t.Errorf("AppendIndices() should reject \u4e2e, got %q, %v", bad, err)
+	}
+	if _, err := alphabet.AppendText(nil, []int{5}); err == nil {
+		t.Error("AppendText() should reject an index out of bounds")
+	}
+}
+
 func TestAlphabet_Runes(t *testing.T) {
 	originalRunes := []rune{'C', 'A', 'B'}
 	alphabet, err := New(originalRunes)
@@ -327,3 +367,188 @@ func TestAlphabet_Runes(t *testing.T) {
 		t.Errorf("Runes() should return a copy, but modification affected original")
 	}
 }
+
+func TestAlphabet_RandomText(t *testing.T) {
+	alphabet, err := New([]rune("αβγδ日本"))
+	if err != nil {
+		t.Fatalf("Failed to create alphabet: %v", err)
+	}
+
+	text, err := alphabet.RandomText(500)
+	if err != nil {
+		t.Fatalf("RandomText() error = %v", err)
+	}
+	if n := utf8.RuneCountInString(text); n != 500 {
+		t.Errorf("RandomText(500) has %d characters", n)
+	}
+	if r, err := alphabet.ValidateString(text); err != nil {
+		t.Errorf("RandomText() produced %c outside the alphabet", r)
+	}
+
+	first, _ := alphabet.RandomTextFrom(random.NewDeterministic([]byte("seed")), 50)
+	second, _ := alphabet.RandomTextFrom(random.NewDeterministic([]byte("seed")), 50)
+	if first != second {
+		t.Errorf("RandomTextFrom() with the same seed differs: %q vs %q", first, second)
+	}
+
+	if text, err := alphabet.RandomText(0); err != nil || text != "" {
+		t.Errorf("RandomText(0) = %q, %v; want empty", text, err)
+	}
+	if _, err := alphabet.RandomText(-1); err == nil {
+		t.Error("RandomText(-1) should fail")
+	}
+}
+
+func TestAutoDetectFromTextReport(t *testing.T) {
+	alph, report, err := AutoDetectFromTextReport("  ABC\r\nD\x01  ")
+	if err != nil {
+		t.Fatalf("AutoDetectFromTextReport failed: %v", err)
+	}
+	if !report.Normalized {
+		t.Error("trimming and CRLF folding should be reported")
+	}
+	if report.SkippedControl != 1 {
+		t.Errorf("SkippedControl = %d, want 1", report.SkippedControl)
+	}
+	// A, B, C, D and \n are five characters, so a space pads the alphabet
+	if report.Padding != ' ' || alph.Size() != 6 {
+		t.Errorf("Padding = %q, size = %d", report.Padding, alph.Size())
+	}
+
+	_, report, _ = AutoDetectFromTextReport("ABCDEF", WithMaxSize(3))
+	if !report.Truncated {
+		t.Error("hitting the size limit should be reported")
+	}
+
+	_, report, _ = AutoDetectFromTextReport("AB")
+	if report != (AutoDetectReport{}) {
+		t.Errorf("clean input should report nothing, got %+v", report)
+	}
+}
+
+func TestAutoDetectOrdering(t *testing.T) {
+	text := "CABBAGE"
+	tests := []struct {
+		ordering Ordering
+		want     string
+	}{
+		{OrderCodepoint, "ABCEG "},
+		{OrderFrequency, "ABCEG "}, // A and B twice, then by codepoint
+		{OrderAsEncountered, "CABGE "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ordering.String(), func(t *testing.T) {
+			alph, err := AutoDetectFromText(text, WithOrdering(tt.ordering))
+			if err != nil {
+				t.Fatalf("AutoDetectFromText() error: %v", err)
+			}
+			if got := string(alph.Runes()); got != tt.want {
+				t.Errorf("alphabet = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	alph, _ := AutoDetectFromText("ZZZYYX", WithOrdering(OrderFrequency))
+	if got := string(alph.Runes()); got != "ZYX " {
+		t.Errorf("frequency ordering = %q, want %q", got, "ZYX ")
+	}
+}
+
+func TestAutoDetectNormalizer(t *testing.T) {
+	// "e" with a combining accent and the precomposed letter are one character
+	text := "cafe\u0301 caf\u00e9"
+	nfc := func(s string) string { return strings.ReplaceAll(s, "e\u0301", "\u00e9") }
+	alph, err := AutoDetectFromText(text, WithNormalizer(nfc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(alph.Runes()), " acf\u00e9!"; got != want {
+		t.Errorf("alphabet = %+q, want %+q", got, want)
+	}
+
+	alph, err = AutoDetectFromText(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alph.Size() != 8 {
+		t.Errorf("without a normalizer both forms should be detected, got %+q", string(alph.Runes()))
+	}
+}
+
+func TestParseOrdering(t *testing.T) {
+	for _, o := range []Ordering{OrderCodepoint, OrderFrequency, OrderAsEncountered} {
+		if got, err := ParseOrdering(o.String()); err != nil || got != o {
+			t.Errorf("ParseOrdering(%q) = %v, %v", o.String(), got, err)
+		}
+	}
+	if _, err := ParseOrdering("alphabetical"); err == nil {
+		t.Error("ParseOrdering() should reject unknown names")
+	}
+}
+
+func TestPaddingStrategies(t *testing.T) {
+	// All 95 printable ASCII characters, with the space inside so it is not trimmed
+	var printable []rune
+	for r := '!'; r <= '~'; r++ {
+		printable = append(printable, r)
+	}
+	printableASCII := string(printable[:10]) + " " + string(printable[10:])
+
+	tests := []struct {
+		name       string
+		text       string
+		opts       []AutoDetectOption
+		want       string
+		padding    rune
+		removed    rune
+		mergedInto rune
+	}{
+		{"codepoint", "ABC", nil, "ABC ", ' ', 0, 0},
+		{"codepoint skips invisible", printableASCII, nil, "", 0xA1, 0, 0}, // not DEL, C1 controls or NBSP
+		{"list", "ABC", []AutoDetectOption{WithPaddingCandidates([]rune("A_"))}, "ABC_", '_', 0, 0},
+		{"drop", "AAABBC", []AutoDetectOption{WithPaddingStrategy(PadDropLeastFrequent)}, "AB", 0, 'C', 0},
+		{"drop tie", "AABBCC", []AutoDetectOption{WithPaddingStrategy(PadDropLeastFrequent)}, "AB", 0, 'C', 0},
+		{"merge case", "aaaAbb", []AutoDetectOption{WithPaddingStrategy(PadMerge)}, "ab", 0, 'A', 'a'},
+		{"merge whitespace", "a b\tcd e", []AutoDetectOption{WithPaddingStrategy(PadMerge)}, " abcde", 0, '\t', ' '},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alph, report, err := AutoDetectFromTextReport(tt.text, tt.opts...)
+			if err != nil {
+				t.Fatalf("AutoDetectFromTextReport() error: %v", err)
+			}
+			if alph.Size()%2 != 0 {
+				t.Errorf("alphabet size %d is odd", alph.Size())
+			}
+			if got := string(alph.Runes()); tt.want != "" && got != tt.want {
+				t.Errorf("alphabet = %q, want %q", got, tt.want)
+			}
+			if report.Padding != tt.padding || report.Removed != tt.removed || report.MergedInto != tt.mergedInto {
+				t.Errorf("report = %+v, want padding %q, removed %q, merged into %q", report, tt.padding, tt.removed, tt.mergedInto)
+			}
+		})
+	}
+}
+
+func TestPaddingStrategyErrors(t *testing.T) {
+	if _, err := AutoDetectFromText("ABC", WithPaddingStrategy(PadFromList)); err == nil {
+		t.Error("the list strategy without candidates should fail")
+	}
+	if _, err := AutoDetectFromText("AB_", WithPaddingCandidates([]rune("_A"))); err == nil {
+		t.Error("the list strategy should fail when every candidate is used")
+	}
+	if _, err := AutoDetectFromText("ABC", WithPaddingStrategy(PadMerge)); err == nil {
+		t.Error("the merge strategy should fail without mergeable characters")
+	}
+}
+
+func TestParsePaddingStrategy(t *testing.T) {
+	for _, p := range []PaddingStrategy{PadNextCodepoint, PadFromList, PadDropLeastFrequent, PadMerge} {
+		if got, err := ParsePaddingStrategy(p.String()); err != nil || got != p {
+			t.Errorf("ParsePaddingStrategy(%q) = %v, %v", p.String(), got, err)
+		}
+	}
+	if _, err := ParsePaddingStrategy("random"); err == nil {
+		t.Error("ParsePaddingStrategy() should reject unknown names")
+	}
+}
@@ -0,0 +1,290 @@
+// Package alphabet provides alphabets of grapheme clusters, whose symbols can
+// be letters with combining marks, flags or emoji sequences of several runes.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package alphabet
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/coredds/enigoma/internal/grapheme"
+)
+
+// placeholderBase is the first rune given to symbols of several runes:
+// Supplementary Private Use Area-A, which text does not normally contain.
+const placeholderBase = 0xf0000
+
+// NewFromSymbols creates an alphabet in grapheme mode: text is split into
+// grapheme clusters rather than runes, and each symbol must be exactly one
+// cluster. Rotors, reflector and plugboard still work with one rune per
+// symbol: a symbol of a single rune is that rune, and each symbol of several
+// runes stands in as a private use rune from U+F0000 upwards, in order. Runes
+// and symbols convert with SymbolToRune and RuneToSymbol.
+//
+// Symbols that would merge into one cluster when written next to each other,
+// such as "e" followed by a lone combining accent, are rejected, since text
+// using them could not be split back into the same symbols.
+func NewFromSymbols(symbols []string) (*Alphabet, error) {
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("alphabet cannot be empty")
+	}
+
+	seen := make(map[string]bool, len(symbols))
+	used := make(map[rune]bool, len(symbols))
+	for _, s := range symbols {
+		if !grapheme.IsCluster(s) {
+			return nil, fmt.Errorf("symbol %q is not a single grapheme cluster", s)
+		}
+		if seen[s] {
+			return nil, fmt.Errorf("duplicate symbol found: %s", s)
+		}
+		seen[s] = true
+		if utf8.RuneCountInString(s) == 1 {
+			r, _ := utf8.DecodeRuneInString(s)
+			used[r] = true
+		}
+	}
+	if a, b, found := grapheme.FirstJoin(symbols); found {
+		return nil, fmt.Errorf("symbols %q and %q merge into one grapheme cluster when written together", a, b)
+	}
+
+	// Give each symbol its rune, skipping placeholders the symbols use
+	runes := make([]rune, len(symbols))
+	next := rune(placeholderBase)
+	for i, s := range symbols {
+		if utf8.RuneCountInString(s) == 1 {
+			runes[i], _ = utf8.DecodeRuneInString(s)
+			continue
+		}
+		for used[next] {
+			next++
+		}
+		if next > utf8.MaxRune {
+			return nil, fmt.Errorf("too many symbols of several runes")
+		}
+		runes[i] = next
+		next++
+	}
+
+	alph, err := New(runes)
+	if err != nil {
+		return nil, err
+	}
+	alph.symbols = make([]string, len(symbols))
+	copy(alph.symbols, symbols)
+	alph.symbolToID = make(map[string]int, len(symbols))
+	for i, s := range symbols {
+		alph.symbolToID[s] = i
+	}
+	return alph, nil
+}
+
+// IsGrapheme reports whether the alphabet is in grapheme mode.
+func (a *Alphabet) IsGrapheme() bool {
+	return a.symbols != nil
+}
+
+// Symbols returns a copy of the symbols in the alphabet. Outside grapheme
+// mode each symbol is one of the runes.
+func (a *Alphabet) Symbols() []string {
+	result := make([]string, a.size)
+	for i := range result {
+		result[i] = a.symbolAt(i)
+	}
+	return result
+}
+
+// symbolAt returns the symbol at idx, which must be in bounds.
+func (a *Alphabet) symbolAt(idx int) string {
+	if a.symbols != nil {
+		return a.symbols[idx]
+	}
+	return string(a.runes[idx])
+}
+
+// SymbolToIndex converts a symbol to its index in the alphabet.
+func (a *Alphabet) SymbolToIndex(s string) (int, error) {
+	if a.symbols != nil {
+		if idx, ok := a.symbolToID[s]; ok {
+			return idx, nil
+		}
+	} else if r, size := utf8.DecodeRuneInString(s); size == len(s) && s != "" {
+		if idx, ok := a.lookup(r); ok {
+			return idx, nil
+		}
+	}
+	return 0, fmt.Errorf("symbol %q not found in alphabet", s)
+}
+
+// IndexToSymbol converts an index to its symbol.
+func (a *Alphabet) IndexToSymbol(idx int) (string, error) {
+	if idx < 0 || idx >= a.size {
+		return "", fmt.Errorf("index %d out of bounds [0, %d)", idx, a.size)
+	}
+	return a.symbolAt(idx), nil
+}
+
+// ContainsSymbol checks if s is one of the alphabet's symbols.
+func (a *Alphabet) ContainsSymbol(s string) bool {
+	_, err := a.SymbolToIndex(s)
+	return err == nil
+}
+
+// SymbolToRune returns the rune standing for symbol s in the components.
+func (a *Alphabet) SymbolToRune(s string) (rune, error) {
+	idx, err := a.SymbolToIndex(s)
+	if err != nil {
+		return 0, err
+	}
+	return a.runes[idx], nil
+}
+
+// RuneToSymbol returns the symbol rune r stands for in the components.
+func (a *Alphabet) RuneToSymbol(r rune) (string, error) {
+	idx, err := a.RuneToIndex(r)
+	if err != nil {
+		return "", err
+	}
+	return a.symbolAt(idx), nil
+}
+
+// Segment splits s into the units the alphabet works with: grapheme clusters
+// in grapheme mode, runes otherwise.
+func (a *Alphabet) Segment(s string) []string {
+	if a.symbols != nil {
+		return grapheme.Split(s)
+	}
+	units := make([]string, 0, len(s))
+	for len(s) > 0 {
+		_, size := utf8.DecodeRuneInString(s)
+		units = append(units, s[:size])
+		s = s[size:]
+	}
+	return units
+}
+
+// validateSymbols is ValidateString in grapheme mode.
+func (a *Alphabet) validateSymbols(s string) (rune, error) {
+	for _, c := range grapheme.Split(s) {
+		if _, ok := a.symbolToID[c]; !ok {
+			r, _ := utf8.DecodeRuneInString(c)
+			return r, fmt.Errorf("symbol %q not found in alphabet", c)
+		}
+	}
+	return 0, nil
+}
+
+// symbolsToIndices is StringToIndices in grapheme mode.
+func (a *Alphabet) symbolsToIndices(s string) ([]int, error) {
+	clusters := grapheme.Split(s)
+	result := make([]int, len(clusters))
+	for i, c := range clusters {
+		idx, ok := a.symbolToID[c]
+		if !ok {
+			return nil, fmt.Errorf("symbol %q not found in alphabet", c)
+		}
+		result[i] = idx
+	}
+	return result, nil
+}
+
+// indicesToSymbols is IndicesToString in grapheme mode.
+func (a *Alphabet) indicesToSymbols(indices []int) (string, error) {
+	var out strings.Builder
+	for _, idx := range indices {
+		if idx < 0 || idx >= a.size {
+			return "", fmt.Errorf("index %d out of bounds [0, %d)", idx, a.size)
+		}
+		out.WriteString(a.symbols[idx])
+	}
+	return out.String(), nil
+}
+
+// WithGraphemeClusters detects an alphabet of grapheme clusters rather than
+// runes; see NewFromSymbols. Only the codepoint and list padding strategies
+// are available, as leaving a symbol out is not.
+func WithGraphemeClusters() AutoDetectOption {
+	return func(config *autoDetectConfig) {
+		config.graphemes = true
+	}
+}
+
+// autoDetectSymbols is AutoDetectFromTextReport with WithGraphemeClusters.
+func autoDetectSymbols(text string, config *autoDetectConfig, report AutoDetectReport) (*Alphabet, AutoDetectReport, error) {
+	counts := make(map[string]int)
+	var symbols []string
+	for _, c := range grapheme.Split(text) {
+		if r, size := utf8.DecodeRuneInString(c); size == len(c) && config.excludeControl && isControlCharacter(r) {
+			report.SkippedControl++
+			continue
+		}
+		counts[c]++
+		if counts[c] == 1 {
+			symbols = append(symbols, c)
+		}
+		if len(symbols) >= config.maxSize {
+			report.Truncated = true
+			break
+		}
+	}
+	if len(symbols) == 0 {
+		return nil, report, fmt.Errorf("no valid characters found in text for alphabet")
+	}
+
+	// UTF-8 strings compare in codepoint order
+	switch config.ordering {
+	case OrderAsEncountered:
+	case OrderFrequency:
+		sort.SliceStable(symbols, func(i, j int) bool {
+			if counts[symbols[i]] != counts[symbols[j]] {
+				return counts[symbols[i]] > counts[symbols[j]]
+			}
+			return symbols[i] < symbols[j]
+		})
+	default:
+		sort.Strings(symbols)
+	}
+
+	if config.addPadding && len(symbols)%2 != 0 {
+		padding, err := symbolPadding(counts, config)
+		if err != nil {
+			return nil, report, err
+		}
+		report.Padding = padding
+		symbols = append(symbols, string(padding))
+	}
+
+	alph, err := NewFromSymbols(symbols)
+	return alph, report, err
+}
+
+// symbolPadding picks the rune that makes an odd-sized alphabet of grapheme
+// clusters even.
+func symbolPadding(counts map[string]int, config *autoDetectConfig) (rune, error) {
+	switch config.padding {
+	case PadFromList:
+		if len(config.padCandidates) == 0 {
+			return 0, fmt.Errorf("the list padding strategy needs padding candidates")
+		}
+		for _, r := range config.padCandidates {
+			if counts[string(r)] == 0 {
+				return r, nil
+			}
+		}
+		return 0, fmt.Errorf("every padding candidate (%q) already occurs in the text", string(config.padCandidates))
+	case PadNextCodepoint:
+		for r := rune(' '); r <= 0x10000; r++ {
+			if counts[string(r)] == 0 && unicode.IsPrint(r) && grapheme.IsCluster(string(r)) {
+				return r, nil
+			}
+		}
+		return 0, fmt.Errorf("unable to find suitable padding character for even-sized alphabet")
+	default:
+		return 0, fmt.Errorf("the %s padding strategy does not apply to grapheme clusters; use codepoint or list", config.padding)
+	}
+}
@@ -0,0 +1,129 @@
+package alphabet
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/coredds/enigoma/internal/random"
+)
+
+func TestNewFromSymbols(t *testing.T) {
+	tests := []struct {
+		name    string
+		symbols []string
+		wantErr string
+	}{
+		{"mixed", []string{"A", "e\u0301", "🇫🇷", "👍\U0001f3fd"}, ""},
+		{"empty", nil, "cannot be empty"},
+		{"two clusters", []string{"A", "AB"}, "not a single grapheme cluster"},
+		{"empty symbol", []string{"A", ""}, "not a single grapheme cluster"},
+		{"duplicate", []string{"e\u0301", "e\u0301"}, "duplicate"},
+		{"joining", []string{"e", "\u0301"}, "merge into one grapheme cluster"},
+		{"lone regional indicator", []string{"🇺", "X"}, "merge into one grapheme cluster"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFromSymbols(tt.symbols)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSymbolRunes(t *testing.T) {
+	// The second symbol of several runes skips the placeholder a symbol uses
+	symbols := []string{"A", "e\u0301", "\U000f0001", "🇫🇷"}
+	alph, err := NewFromSymbols(symbols)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !alph.IsGrapheme() {
+		t.Error("the alphabet should be in grapheme mode")
+	}
+	want := []rune{'A', 0xf0000, 0xf0001, 0xf0002}
+	if got := alph.Runes(); !slices.Equal(got, want) {
+		t.Errorf("runes = %U, want %U", got, want)
+	}
+	if got := alph.Symbols(); !slices.Equal(got, symbols) {
+		t.Errorf("symbols = %q, want %q", got, symbols)
+	}
+	for i, s := range symbols {
+		r, err := alph.SymbolToRune(s)
+		if err != nil || r != want[i] {
+			t.Errorf("SymbolToRune(%q) = %U, %v; want %U", s, r, err, want[i])
+		}
+		back, err := alph.RuneToSymbol(r)
+		if err != nil || back != s {
+			t.Errorf("RuneToSymbol(%U) = %q, %v; want %q", r, back, err, s)
+		}
+	}
+}
+
+func TestGraphemeText(t *testing.T) {
+	alph, err := NewFromSymbols([]string{"c", "a", "f", "e\u0301", " ", "👍\U0001f3fd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := "cafe\u0301 👍\U0001f3fd"
+	indices, err := alph.StringToIndices(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{0, 1, 2, 3, 4, 5}; !slices.Equal(indices, want) {
+		t.Errorf("indices = %v, want %v", indices, want)
+	}
+	back, err := alph.IndicesToString(indices)
+	if err != nil || back != text {
+		t.Errorf("IndicesToString = %q, %v; want %q", back, err, text)
+	}
+
+	// The bare "e" and the thumb without its skin tone are other clusters
+	for _, bad := range []string{"cafe", "👍"} {
+		if _, err := alph.ValidateString(bad); err == nil {
+			t.Errorf("ValidateString(%q) should fail", bad)
+		}
+		if _, err := alph.StringToIndices(bad); err == nil {
+			t.Errorf("StringToIndices(%q) should fail", bad)
+		}
+	}
+	if got := alph.Segment(text); len(got) != 6 {
+		t.Errorf("Segment(%q) = %q, want 6 clusters", text, got)
+	}
+
+	generated, err := alph.RandomTextFrom(random.NewDeterministic([]byte("grapheme")), 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := alph.ValidateString(generated); err != nil {
+		t.Errorf("random text %q should be valid: %v", generated, err)
+	}
+	if n := len(alph.Segment(generated)); n != 50 {
+		t.Errorf("random text has %d symbols, want 50", n)
+	}
+}
+
+func TestAutoDetectGraphemeClusters(t *testing.T) {
+	alph, report, err := AutoDetectFromTextReport("cafe\u0301 👍\U0001f3fd!", WithGraphemeClusters())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{" ", "!", "a", "c", "e\u0301", "f", "👍\U0001f3fd", "\""}
+	if got := alph.Symbols(); !slices.Equal(got, want) {
+		t.Errorf("symbols = %q, want %q", got, want)
+	}
+	if report.Padding != '"' {
+		t.Errorf("padding = %q, want '\"'", report.Padding)
+	}
+
+	if _, _, err := AutoDetectFromTextReport("abc", WithGraphemeClusters(), WithPaddingStrategy(PadDropLeastFrequent)); err == nil {
+		t.Error("dropping a symbol should not be supported")
+	}
+}
@@ -0,0 +1,176 @@
+// Package alphabet provides alphabets built from Unicode range tables,
+// categories and scripts.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package alphabet
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NewFromRanges creates an alphabet of every rune in the range tables, such
+// as unicode.Cyrillic or unicode.Nd, in code point order. A rune in several
+// tables is included once.
+func NewFromRanges(tables ...*unicode.RangeTable) (*Alphabet, error) {
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("no Unicode ranges given")
+	}
+	return New(runesIn(tables, nil))
+}
+
+// NewFromCategories creates an alphabet of every rune in the named Unicode
+// general categories, such as "L" (letters), "Lu" (uppercase letters) or
+// "Nd" (decimal digits), in code point order.
+func NewFromCategories(names ...string) (*Alphabet, error) {
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no Unicode categories given")
+	}
+	tables := make([]*unicode.RangeTable, len(names))
+	for i, name := range names {
+		table, ok := lookupTable(unicode.Categories, name)
+		if !ok {
+			return nil, fmt.Errorf("unknown Unicode category: %s", name)
+		}
+		tables[i] = table
+	}
+	return NewFromRanges(tables...)
+}
+
+// NewFromRangeSpec creates an alphabet from a textual list of ranges, as
+// taken by the CLI. Terms are separated by commas and their runes are
+// combined in code point order. A term is a Unicode category ("Lu", "Nd"), a
+// script ("Cyrillic", "Greek"), a range of characters ("A-Z", "U+0410-U+044F")
+// or a single character ("U+00C7"). Terms joined by "&" keep only the runes
+// in all of them, so "Cyrillic&L,0-9" is the Cyrillic letters plus the ASCII
+// digits. Names are matched ignoring case.
+func NewFromRangeSpec(spec string) (*Alphabet, error) {
+	seen := make(map[rune]bool)
+	var runes []rune
+	for _, term := range strings.Split(spec, ",") {
+		if term = strings.TrimSpace(term); term == "" {
+			continue
+		}
+		factors := strings.Split(term, "&")
+		tables := make([]*unicode.RangeTable, len(factors))
+		for i, factor := range factors {
+			table, err := parseRangeFactor(strings.TrimSpace(factor))
+			if err != nil {
+				return nil, err
+			}
+			tables[i] = table
+		}
+		keep := func(r rune) bool {
+			for _, table := range tables[1:] {
+				if !unicode.Is(table, r) {
+					return false
+				}
+			}
+			return true
+		}
+		for _, r := range runesIn(tables[:1], keep) {
+			if !seen[r] {
+				seen[r] = true
+				runes = append(runes, r)
+			}
+		}
+	}
+	if len(runes) == 0 {
+		return nil, fmt.Errorf("the ranges %q contain no characters", spec)
+	}
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+	return New(runes)
+}
+
+// parseRangeFactor resolves one factor of a range spec to a table.
+func parseRangeFactor(factor string) (*unicode.RangeTable, error) {
+	if factor == "" {
+		return nil, fmt.Errorf("empty term in Unicode ranges")
+	}
+	if table, ok := lookupTable(unicode.Categories, factor); ok {
+		return table, nil
+	}
+	if table, ok := lookupTable(unicode.Scripts, factor); ok {
+		return table, nil
+	}
+
+	lo, hi := factor, factor
+	if runes := []rune(factor); len(runes) == 3 && runes[1] == '-' {
+		lo, hi = string(runes[0]), string(runes[2])
+	} else if before, after, found := strings.Cut(factor, "-"); found {
+		lo, hi = before, after
+	}
+	first, err := parseRangeRune(lo)
+	if err != nil {
+		return nil, fmt.Errorf("unknown Unicode category, script or range: %s", factor)
+	}
+	last, err := parseRangeRune(hi)
+	if err != nil {
+		return nil, fmt.Errorf("unknown Unicode category, script or range: %s", factor)
+	}
+	if first > last {
+		return nil, fmt.Errorf("range %s runs backwards", factor)
+	}
+	return &unicode.RangeTable{R32: []unicode.Range32{{Lo: uint32(first), Hi: uint32(last), Stride: 1}}}, nil
+}
+
+// parseRangeRune parses a range end: a single character or U+XXXX.
+func parseRangeRune(s string) (rune, error) {
+	s = strings.TrimSpace(s)
+	if len(s) > 2 && (s[:2] == "U+" || s[:2] == "u+") {
+		n, err := strconv.ParseUint(s[2:], 16, 32)
+		if err != nil || n > unicode.MaxRune {
+			return 0, fmt.Errorf("invalid code point: %s", s)
+		}
+		return rune(n), nil
+	}
+	if r, size := utf8.DecodeRuneInString(s); size > 0 && size == len(s) && r != utf8.RuneError {
+		return r, nil
+	}
+	return 0, fmt.Errorf("invalid character: %s", s)
+}
+
+// lookupTable finds a table by name, ignoring case.
+func lookupTable(tables map[string]*unicode.RangeTable, name string) (*unicode.RangeTable, bool) {
+	if table, ok := tables[name]; ok {
+		return table, true
+	}
+	for key, table := range tables {
+		if strings.EqualFold(key, name) {
+			return table, true
+		}
+	}
+	return nil, false
+}
+
+// runesIn returns the runes of the tables accepted by keep (all if keep is
+// nil), without duplicates or surrogates and in code point order.
+func runesIn(tables []*unicode.RangeTable, keep func(rune) bool) []rune {
+	seen := make(map[rune]bool)
+	var runes []rune
+	add := func(r rune) {
+		if !seen[r] && utf8.ValidRune(r) && (keep == nil || keep(r)) {
+			seen[r] = true
+			runes = append(runes, r)
+		}
+	}
+	for _, table := range tables {
+		for _, r16 := range table.R16 {
+			for r := rune(r16.Lo); r <= rune(r16.Hi); r += rune(r16.Stride) {
+				add(r)
+			}
+		}
+		for _, r32 := range table.R32 {
+			for r := rune(r32.Lo); r <= rune(r32.Hi); r += rune(r32.Stride) {
+				add(r)
+			}
+		}
+	}
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+	return runes
+}
@@ -0,0 +1,94 @@
+package alphabet
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestNewFromRanges(t *testing.T) {
+	alph, err := NewFromRanges(unicode.Nd, &unicode.RangeTable{R16: []unicode.Range16{{Lo: '0', Hi: '9', Stride: 1}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	runes := alph.Runes()
+	if runes[0] != '0' || runes[9] != '9' {
+		t.Errorf("the ASCII digits should come first, got %q", string(runes[:10]))
+	}
+	for i, r := range runes {
+		if !unicode.IsDigit(r) {
+			t.Fatalf("rune %d (%q) is not a digit", i, r)
+		}
+		if i > 0 && runes[i-1] >= r {
+			t.Fatalf("runes should be in code point order without duplicates at %d", i)
+		}
+	}
+
+	if _, err := NewFromRanges(); err == nil {
+		t.Error("no tables should be rejected")
+	}
+}
+
+func TestNewFromCategories(t *testing.T) {
+	alph, err := NewFromCategories("lu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !alph.Contains('A') || !alph.Contains('\u0416') || alph.Contains('a') {
+		t.Error("Lu should hold the uppercase letters of every script")
+	}
+	if _, err := NewFromCategories("Xx"); err == nil || !strings.Contains(err.Error(), "unknown Unicode category") {
+		t.Errorf("an unknown category should be rejected, got %v", err)
+	}
+}
+
+func TestNewFromRangeSpec(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    string
+		wantErr string
+	}{
+		{spec: "A-F", want: "ABCDEF"},
+		{spec: "0-3, U+0041-U+0043", want: "0123ABC"},
+		{spec: "U+00C7,A-B", want: "AB\u00c7"},
+		{spec: "Greek&Lu&U+0391-U+0395", want: "\u0391\u0392\u0393\u0394\u0395"},
+		{spec: "!--", want: "!\"#$%&'()*+,-"},
+		{spec: "A-C,B-D", want: "ABCD"},
+		{spec: "Z-A", wantErr: "backwards"},
+		{spec: "Klingon", wantErr: "unknown Unicode category, script or range"},
+		{spec: "Lu&", wantErr: "empty term"},
+		{spec: " , ", wantErr: "no characters"},
+		{spec: "Latin&Nd", wantErr: "no characters"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			alph, err := NewFromRangeSpec(tt.spec)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := string(alph.Runes()); got != tt.want {
+				t.Errorf("runes = %+q, want %+q", got, tt.want)
+			}
+		})
+	}
+
+	// Cyrillic letters plus the ASCII digits
+	alph, err := NewFromRangeSpec("Cyrillic&L,0-9")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range alph.Runes() {
+		if !(r >= '0' && r <= '9') && !(unicode.Is(unicode.Cyrillic, r) && unicode.IsLetter(r)) {
+			t.Fatalf("unexpected rune %q", r)
+		}
+	}
+	if !alph.Contains('\u0416') || !alph.Contains('7') || alph.Contains('\u0483') {
+		t.Error("the alphabet should hold Cyrillic letters and digits but not Cyrillic marks")
+	}
+}
@@ -0,0 +1,135 @@
+// Package analysis provides statistical heuristics for telling natural text
+// from Enigma output, used to catch wrong keys and double encryption.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package analysis
+
+import (
+	"unicode"
+)
+
+const (
+	// MinLetters is the number of letters below which statistics are too noisy to judge.
+	MinLetters = 40
+
+	// naturalKappa is the normalized index of coincidence above which letters
+	// are considered language-like. Uniform text scores about 1.0; English
+	// about 1.7 and most European languages between 1.7 and 2.1.
+	naturalKappa = 1.3
+
+	// minPrintable is the share of printable characters expected in natural text.
+	minPrintable = 0.95
+
+	// defaultLetterClasses is used when no alphabet is given (A-Z).
+	defaultLetterClasses = 26
+)
+
+// Assessment summarizes how much a text looks like natural language.
+type Assessment struct {
+	Letters       int     // Letters counted, case-folded
+	IoC           float64 // Index of coincidence over the letters
+	Kappa         float64 // IoC normalized by the number of letter classes (1.0 = uniform)
+	LetterClasses int     // Distinct case-folded letters the alphabet allows
+	Printable     float64 // Share of printable characters (including tab and newlines)
+	Inconclusive  bool    // Too few letters to judge the letter statistics
+	Natural       bool    // Whether the text looks like natural language
+	Reason        string  // Why the text was judged unnatural, if it was
+}
+
+// IndexOfCoincidence returns the probability that two letters drawn from text
+// without replacement are equal, ignoring case and non-letters. It returns 0
+// when text has fewer than two letters.
+func IndexOfCoincidence(text string) float64 {
+	counts, total := letterCounts(text)
+	return coincidence(counts, total)
+}
+
+// PrintableRatio returns the share of runes in text that are printable or
+// ordinary whitespace. The invisible joiners and tags inside emoji sequences
+// count as printable. Empty text counts as fully printable.
+func PrintableRatio(text string) float64 {
+	total, printable := 0, 0
+	for _, r := range text {
+		total++
+		if unicode.IsPrint(r) || r == '\n' || r == '\r' || r == '\t' || isEmojiJoiner(r) {
+			printable++
+		}
+	}
+	if total == 0 {
+		return 1
+	}
+	return float64(printable) / float64(total)
+}
+
+// isEmojiJoiner reports whether r is the zero-width joiner or a tag
+// character, which emoji sequences are written with.
+func isEmojiJoiner(r rune) bool {
+	return r == '\u200d' || (r >= 0xe0020 && r <= 0xe007f)
+}
+
+// Assess judges whether text looks like natural language written with
+// alphabet. The alphabet only sets the baseline for the letter statistics;
+// pass nil for A-Z. Non-printable characters are judged on any length, letter
+// statistics only from MinLetters letters on.
+func Assess(text string, alphabet []rune) Assessment {
+	counts, total := letterCounts(text)
+	a := Assessment{
+		Letters:       total,
+		IoC:           coincidence(counts, total),
+		Printable:     PrintableRatio(text),
+		LetterClasses: letterClasses(alphabet),
+		Natural:       true,
+	}
+	a.Kappa = a.IoC * float64(a.LetterClasses)
+
+	switch {
+	case a.Printable < minPrintable:
+		a.Natural = false
+		a.Reason = "contains many non-printable characters"
+	case total < MinLetters:
+		a.Inconclusive = true
+	case a.Kappa < naturalKappa:
+		a.Natural = false
+		a.Reason = "letter frequencies are close to uniform"
+	}
+	return a
+}
+
+// letterCounts tallies case-folded letters in text.
+func letterCounts(text string) (map[rune]int, int) {
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			counts[unicode.ToLower(r)]++
+			total++
+		}
+	}
+	return counts, total
+}
+
+func coincidence(counts map[rune]int, total int) float64 {
+	if total < 2 {
+		return 0
+	}
+	sum := 0
+	for _, n := range counts {
+		sum += n * (n - 1)
+	}
+	return float64(sum) / float64(total*(total-1))
+}
+
+// letterClasses counts the distinct case-folded letters in alphabet.
+func letterClasses(alphabet []rune) int {
+	seen := make(map[rune]bool)
+	for _, r := range alphabet {
+		if unicode.IsLetter(r) {
+			seen[unicode.ToLower(r)] = true
+		}
+	}
+	if len(seen) < 2 {
+		return defaultLetterClasses
+	}
+	return len(seen)
+}
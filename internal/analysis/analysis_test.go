@@ -0,0 +1,136 @@
+package analysis
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coredds/enigoma/internal/random"
+)
+
+const sampleEnglish = "It was a bright cold day in April, and the clocks were striking thirteen. " +
+	"Winston Smith, his chin nuzzled into his breast in an effort to escape the vile wind, " +
+	"slipped quickly through the glass doors of Victory Mansions."
+
+func TestIndexOfCoincidence(t *testing.T) {
+	if got := IndexOfCoincidence("AAAA"); got != 1 {
+		t.Errorf("IoC of identical letters = %v, want 1", got)
+	}
+	if got := IndexOfCoincidence("ABCD"); got != 0 {
+		t.Errorf("IoC of distinct letters = %v, want 0", got)
+	}
+	if got := IndexOfCoincidence("aA"); got != 1 {
+		t.Errorf("IoC should ignore case, got %v", got)
+	}
+	if got := IndexOfCoincidence("A 1 !"); got != 0 {
+		t.Errorf("IoC of a single letter = %v, want 0", got)
+	}
+	if got := IndexOfCoincidence(sampleEnglish); math.Abs(got-0.066) > 0.015 {
+		t.Errorf("IoC of English sample = %v, want about 0.066", got)
+	}
+}
+
+func TestPrintableRatio(t *testing.T) {
+	if got := PrintableRatio(""); got != 1 {
+		t.Errorf("empty text ratio = %v, want 1", got)
+	}
+	if got := PrintableRatio("Hello,\n\tworld"); got != 1 {
+		t.Errorf("ordinary text ratio = %v, want 1", got)
+	}
+	if got := PrintableRatio("AB\x00\x01"); got != 0.5 {
+		t.Errorf("ratio with control characters = %v, want 0.5", got)
+	}
+}
+
+func TestAssess(t *testing.T) {
+	if a := Assess(sampleEnglish, nil); !a.Natural || a.Inconclusive {
+		t.Errorf("English sample judged unnatural: %+v", a)
+	}
+
+	src := random.NewDeterministic([]byte("analysis"))
+	var b strings.Builder
+	for i := 0; i < 300; i++ {
+		n, _ := src.Intn(26)
+		b.WriteRune(rune('A' + n))
+	}
+	if a := Assess(b.String(), nil); a.Natural || a.Reason == "" {
+		t.Errorf("uniform letters judged natural: %+v", a)
+	}
+
+	if a := Assess("HELLO WORLD", nil); !a.Natural || !a.Inconclusive {
+		t.Errorf("short text should be inconclusive: %+v", a)
+	}
+
+	if a := Assess("HELLO\x00\x01\x02", nil); a.Natural {
+		t.Errorf("control characters should fail even in short text: %+v", a)
+	}
+}
+
+func TestAssessUsesAlphabetBaseline(t *testing.T) {
+	greek := []rune("ΑΒΓΔΕΖΗΘΙΚΛΜΝΞΟΠΡΣΤΥΦΧΨΩαβγδεζηθικλμνξοπρστυφχψω")
+	if a := Assess("abc", greek); a.LetterClasses != 24 {
+		t.Errorf("expected 24 letter classes for Greek, got %d", a.LetterClasses)
+	}
+	if a := Assess("abc", []rune("0123456789")); a.LetterClasses != defaultLetterClasses {
+		t.Errorf("expected default letter classes for a letterless alphabet, got %d", a.LetterClasses)
+	}
+}
+
+func TestEstimateKeyspace(t *testing.T) {
+	k := EstimateKeyspace(26, 3, 10, true)
+
+	// 26! is about 2^88.4, so three rotors are about 265 bits
+	if math.Abs(k.WiringBits-3*88.38) > 0.1 {
+		t.Errorf("wiring bits = %.2f", k.WiringBits)
+	}
+	if math.Abs(k.PositionBits-3*math.Log2(26)) > 1e-9 {
+		t.Errorf("position bits = %.2f", k.PositionBits)
+	}
+	// 25!! = 7905853580625, about 2^42.85
+	if math.Abs(k.ReflectorBits-42.85) > 0.01 {
+		t.Errorf("reflector bits = %.2f", k.ReflectorBits)
+	}
+	// Ten plugboard cables: 150738274937250, about 2^47.1
+	if math.Abs(k.PlugboardBits-47.10) > 0.01 {
+		t.Errorf("plugboard bits = %.2f", k.PlugboardBits)
+	}
+	if k.TotalBits() != k.WiringBits+k.PositionBits+k.ReflectorBits+k.PlugboardBits {
+		t.Error("total does not add up")
+	}
+
+	odd := EstimateKeyspace(95, 8, 15, true)
+	if odd.ReflectorBits != 0 {
+		t.Error("an odd alphabet cannot have a reflector")
+	}
+	if none := EstimateKeyspace(26, 3, 0, false); none.ReflectorBits != 0 || none.PlugboardBits != 0 {
+		t.Errorf("unexpected bits without reflector or plugboard: %+v", none)
+	}
+}
+
+func TestMeasureThroughput(t *testing.T) {
+	calls := 0
+	rate, err := MeasureThroughput(func(string) error { calls++; return nil }, "ABCD", time.Millisecond)
+	if err != nil || rate <= 0 || calls == 0 {
+		t.Errorf("unexpected result %v, %v after %d calls", rate, err, calls)
+	}
+	if _, err := MeasureThroughput(func(string) error { return nil }, "", time.Millisecond); err == nil {
+		t.Error("empty sample should fail")
+	}
+}
+
+func TestMeasureRun(t *testing.T) {
+	stats, err := MeasureRun(func(string) error { return nil }, "AB\u00c7D")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Chars != 4 || stats.Bytes != 5 {
+		t.Errorf("counted %d chars and %d bytes, want 4 and 5", stats.Chars, stats.Bytes)
+	}
+	if stats.Elapsed <= 0 || stats.CharsPerSecond() <= 0 || stats.BytesPerSecond() <= stats.CharsPerSecond() {
+		t.Errorf("unexpected rates: %+v", stats)
+	}
+	if _, err := MeasureRun(func(string) error { return nil }, ""); err == nil {
+		t.Error("empty text should fail")
+	}
+}
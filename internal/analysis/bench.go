@@ -0,0 +1,80 @@
+// Package analysis provides throughput measurement for machine configurations.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package analysis
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+	"unicode/utf8"
+)
+
+// MeasureThroughput runs process over sample repeatedly for at least budget
+// (and at least once) and returns the characters processed per second.
+func MeasureThroughput(process func(string) error, sample string, budget time.Duration) (float64, error) {
+	chars := utf8.RuneCountInString(sample)
+	if chars == 0 {
+		return 0, fmt.Errorf("benchmark sample cannot be empty")
+	}
+
+	total := 0
+	start := time.Now()
+	for {
+		if err := process(sample); err != nil {
+			return 0, err
+		}
+		total += chars
+		if elapsed := time.Since(start); elapsed >= budget {
+			return float64(total) / elapsed.Seconds(), nil
+		}
+	}
+}
+
+// RunStats is the cost of one run of a process over a text.
+type RunStats struct {
+	Chars      int           // Characters processed
+	Bytes      int           // UTF-8 size of the text
+	Elapsed    time.Duration // Wall-clock time of the run
+	Allocs     uint64        // Heap allocations made during the run
+	AllocBytes uint64        // Bytes allocated on the heap during the run
+}
+
+// CharsPerSecond returns the characters processed per second.
+func (s RunStats) CharsPerSecond() float64 {
+	return float64(s.Chars) / s.Elapsed.Seconds()
+}
+
+// BytesPerSecond returns the bytes of UTF-8 text processed per second.
+func (s RunStats) BytesPerSecond() float64 {
+	return float64(s.Bytes) / s.Elapsed.Seconds()
+}
+
+// MeasureRun runs process over text once, timing it and counting the heap
+// allocations it makes. Allocations by other goroutines during the run are
+// counted too.
+func MeasureRun(process func(string) error, text string) (RunStats, error) {
+	chars := utf8.RuneCountInString(text)
+	if chars == 0 {
+		return RunStats{}, fmt.Errorf("benchmark text cannot be empty")
+	}
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	if err := process(text); err != nil {
+		return RunStats{}, err
+	}
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	return RunStats{
+		Chars:      chars,
+		Bytes:      len(text),
+		Elapsed:    elapsed,
+		Allocs:     after.Mallocs - before.Mallocs,
+		AllocBytes: after.TotalAlloc - before.TotalAlloc,
+	}, nil
+}
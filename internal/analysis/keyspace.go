@@ -0,0 +1,52 @@
+// Package analysis provides keyspace estimates for machine configurations.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package analysis
+
+import (
+	"math"
+)
+
+// Keyspace breaks down the size of a configuration's key space, in bits.
+type Keyspace struct {
+	WiringBits    float64 // Rotor wirings, each a free permutation of the alphabet
+	PositionBits  float64 // Starting rotor positions
+	ReflectorBits float64 // Reflector wiring (a fixed-point-free involution)
+	PlugboardBits float64 // Choice of plugboard pairs
+}
+
+// TotalBits returns the combined size of the key space.
+func (k Keyspace) TotalBits() float64 {
+	return k.WiringBits + k.PositionBits + k.ReflectorBits + k.PlugboardBits
+}
+
+// EstimateKeyspace estimates the key space of a machine with randomly wired
+// components, as generated for the security levels. Ring settings are not
+// counted: with freely chosen wirings they only relabel a wiring. This is an
+// upper bound on brute-force effort, not a measure of cryptographic strength.
+func EstimateKeyspace(alphabetSize, rotors, plugboardPairs int, reflector bool) Keyspace {
+	n := alphabetSize
+	k := Keyspace{
+		WiringBits:   float64(rotors) * log2Factorial(n),
+		PositionBits: float64(rotors) * math.Log2(float64(n)),
+	}
+	if reflector && n%2 == 0 {
+		// (n-1)!! = n! / (2^(n/2) (n/2)!)
+		k.ReflectorBits = log2Factorial(n) - float64(n/2) - log2Factorial(n/2)
+	}
+	if p := plugboardPairs; p > 0 && 2*p <= n {
+		// n! / ((n-2p)! p! 2^p)
+		k.PlugboardBits = log2Factorial(n) - log2Factorial(n-2*p) - log2Factorial(p) - float64(p)
+	}
+	return k
+}
+
+// log2Factorial returns log2(n!).
+func log2Factorial(n int) float64 {
+	if n < 2 {
+		return 0
+	}
+	lg, _ := math.Lgamma(float64(n) + 1)
+	return lg / math.Ln2
+}
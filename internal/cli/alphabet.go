@@ -0,0 +1,175 @@
+// Package cli provides the alphabet command for the enigoma CLI.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/coredds/enigoma"
+	"github.com/coredds/enigoma/internal/alphabet"
+	"github.com/coredds/enigoma/internal/normalize"
+	"github.com/spf13/cobra"
+)
+
+// alphabetSampleSize is the number of characters shown in the sample column.
+const alphabetSampleSize = 12
+
+// newAlphabetCommand creates the alphabet command and its subcommands.
+func newAlphabetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "alphabet",
+		Short: "Explore the predefined alphabets and check alphabet files",
+		Long: `Explore the predefined alphabets accepted by --alphabet, and check the
+alphabet files accepted by --alphabet-file.
+
+--check reports duplicate characters, control and invisible characters,
+characters that Unicode normalization would change, and an odd number of
+characters, which a reflector cannot pair (with a suggested padding
+character). --fix writes the cleaned alphabet back to the file: it drops
+control characters, normalizes to NFC, keeps the first of each duplicate and
+appends the padding character if needed.
+
+Examples:
+  enigoma alphabet list
+  enigoma alphabet --check chars.txt
+  enigoma alphabet --check chars.txt --fix`,
+		Args: cobra.NoArgs,
+		RunE: runAlphabetCheck,
+	}
+	cmd.Flags().String("check", "", "Check an alphabet file for problems")
+	cmd.Flags().Bool("fix", false, "With --check, write the cleaned alphabet back to the file")
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List predefined alphabets with size and reflector compatibility",
+		Args:  cobra.NoArgs,
+		RunE:  runAlphabetList,
+	})
+
+	return cmd
+}
+
+func runAlphabetList(cmd *cobra.Command, args []string) error {
+	out := cmd.OutOrStdout()
+
+	fmt.Fprintf(out, "%-14s %-5s %-10s %-30s %s\n", "NAME", "SIZE", "REFLECTOR", "SAMPLE", "DESCRIPTION")
+	for _, p := range enigoma.PredefinedAlphabets() {
+		reflector := "yes"
+		if !p.ReflectorCompatible() {
+			reflector = "no (odd)"
+		}
+		fmt.Fprintf(out, "%-14s %-5d %-10s %s %s\n", p.Name, p.Size(), reflector, padToWidth(alphabetSample(p.Runes), 30), p.Description)
+	}
+
+	fmt.Fprintln(out, "\nUse with: enigoma keygen --alphabet <name>  (encrypt/decrypt also accept 'auto')")
+	fmt.Fprintln(out, "Alphabets marked 'no (odd)' have an odd number of characters and cannot be paired by a reflector;\nkeygen --no-reflector accepts them.")
+	return nil
+}
+
+func runAlphabetCheck(cmd *cobra.Command, args []string) error {
+	path, _ := cmd.Flags().GetString("check")
+	fix, _ := cmd.Flags().GetBool("fix")
+	if path == "" {
+		if fix {
+			return fmt.Errorf("--fix requires --check")
+		}
+		return cmd.Help()
+	}
+
+	fsys := fileSystem(cmd)
+	chars, err := readAlphabetFile(fsys, path)
+	if err != nil {
+		return err
+	}
+	problems, fixed := checkAlphabet(chars)
+
+	out := uiOut(cmd)
+	fmt.Fprintf(out, "Checking alphabet file: %s (%d characters)\n", path, len(chars))
+	for _, problem := range problems {
+		fmt.Fprintf(out, "  ⚠️  %s\n", problem)
+	}
+	if len(problems) == 0 {
+		fmt.Fprintf(out, "✅ Alphabet is %s (%d characters, reflector compatible)\n", paint(colorGreen, "VALID"), len(chars))
+		return nil
+	}
+	if !fix {
+		fmt.Fprintf(out, "❌ Alphabet has %s; --fix writes a cleaned alphabet\n", paint(colorRed, fmt.Sprintf("%d problem(s)", len(problems))))
+		return nil
+	}
+	if len(fixed) == 0 {
+		return fmt.Errorf("alphabet file %s has no characters left to keep", path)
+	}
+	if err := fsys.WriteFile(path, []byte(string(fixed)+"\n"), 0600); err != nil {
+		return fmt.Errorf("failed to write alphabet file: %w", err)
+	}
+	fmt.Fprintf(out, "✅ Fixed %d problem(s): %s now has %d characters\n", len(problems), path, len(fixed))
+	return nil
+}
+
+// checkAlphabet lists the problems of an alphabet file, each with its line,
+// and returns the cleaned alphabet --fix writes: control and invisible
+// characters dropped, the rest normalized to NFC, the first of each duplicate
+// kept, and a padding character appended if the count is odd.
+func checkAlphabet(chars []alphabetChar) (problems []string, fixed []rune) {
+	var kept []rune
+	var prev alphabetChar
+	seen := make(map[rune]int, len(chars))
+	for _, c := range chars {
+		if unicode.IsControl(c.r) || unicode.Is(unicode.Cf, c.r) {
+			problems = append(problems, fmt.Sprintf("line %d: control or invisible character %U", c.line, c.r))
+			continue
+		}
+		if nfc := normalize.NFC(string(c.r)); nfc != string(c.r) {
+			problems = append(problems, fmt.Sprintf("line %d: %q (%U) is not normalized; NFC writes it as %q", c.line, c.r, c.r, nfc))
+		} else if len(kept) > 0 && len([]rune(normalize.NFC(string(prev.r)+string(c.r)))) == 1 {
+			problems = append(problems, fmt.Sprintf("line %d: %q (%U) combines with the %q before it; NFC writes them as %q", c.line, c.r, c.r, prev.r, normalize.NFC(string(prev.r)+string(c.r))))
+		}
+		if first, ok := seen[c.r]; ok {
+			problems = append(problems, fmt.Sprintf("line %d: %q repeats the one on line %d", c.line, c.r, first))
+		} else {
+			seen[c.r] = c.line
+		}
+		kept = append(kept, c.r)
+		prev = c
+	}
+
+	unique := make(map[rune]bool, len(kept))
+	for _, r := range normalize.NFC(string(kept)) {
+		if !unique[r] {
+			unique[r] = true
+			fixed = append(fixed, r)
+		}
+	}
+	if len(fixed)%2 != 0 {
+		if padding, ok := alphabet.SuggestPadding(fixed); ok {
+			problems = append(problems, fmt.Sprintf("%d characters is odd, so a reflector cannot pair them; suggested padding character: %q (%U), or use --no-reflector", len(fixed), padding, padding))
+			fixed = append(fixed, padding)
+		} else {
+			problems = append(problems, fmt.Sprintf("%d characters is odd, so a reflector cannot pair them; remove one, or use --no-reflector", len(fixed)))
+		}
+	}
+	return problems, fixed
+}
+
+// alphabetSample renders the first characters of an alphabet, quoted so
+// spaces and punctuation stay visible.
+func alphabetSample(runes []rune) string {
+	if len(runes) <= alphabetSampleSize {
+		return strconv.Quote(string(runes))
+	}
+	return strconv.Quote(string(runes[:alphabetSampleSize])) + "…"
+}
+
+// alphabetNameList returns the accepted --alphabet values as a comma-separated list.
+func alphabetNameList(includeAuto bool) string {
+	names := enigoma.PredefinedAlphabetNames()
+	if includeAuto {
+		names = append([]string{"auto"}, names...)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,157 @@
+// Package cli provides unit tests for the alphabet command.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/coredds/enigoma"
+)
+
+func TestAlphabetListCommand(t *testing.T) {
+	cmd := NewRootCommand(Options{})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"alphabet", "list"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("alphabet list failed: %v", err)
+	}
+
+	output := out.String()
+	for _, name := range enigoma.PredefinedAlphabetNames() {
+		if !strings.Contains(output, name) {
+			t.Errorf("alphabet list missing %q", name)
+		}
+	}
+	if !strings.Contains(output, `"ABCDEFGHIJKL"…`) {
+		t.Errorf("expected truncated sample for latin, got:\n%s", output)
+	}
+	if !strings.Contains(output, "no (odd)") {
+		t.Errorf("odd-sized alphabets should be flagged, got:\n%s", output)
+	}
+
+	// Wide and combining characters in the sample must not shift the
+	// description column
+	column := -1
+	for _, p := range enigoma.PredefinedAlphabets() {
+		if p.Name != "latin" && p.Name != "hangul" && p.Name != "kana" && p.Name != "thai" {
+			continue
+		}
+		for _, line := range strings.Split(output, "\n") {
+			if i := strings.Index(line, p.Description); strings.HasPrefix(line, p.Name+" ") && i >= 0 {
+				width := displayWidth(line[:i])
+				if column == -1 {
+					column = width
+				} else if width != column {
+					t.Errorf("%s: description starts at column %d, want %d:\n%s", p.Name, width, column, output)
+				}
+			}
+		}
+	}
+}
+
+func TestUnknownAlphabetListsNames(t *testing.T) {
+	cmd := NewRootCommand(Options{})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"keygen", "--alphabet", "klingon"})
+	err := cmd.Execute()
+	if err == nil {
+		t.Fatal("expected error for unknown alphabet")
+	}
+	if !strings.Contains(err.Error(), alphabetNameList(true)) {
+		t.Errorf("error should list available alphabets, got: %v", err)
+	}
+}
+
+func TestKeygenAlphabetRanges(t *testing.T) {
+	fsys := NewMemFS()
+	if _, err := runCLI(fsys, "keygen", "--alphabet-ranges", "Cyrillic&Lu,0-9", "--security", "low", "--output", "key.json"); err != nil {
+		t.Fatalf("keygen failed: %v", err)
+	}
+	machine, err := createMachineFromConfig(fsys, configOptions{}, "key.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := machine.GetAlphabetSize(); size != 196 {
+		t.Errorf("alphabet size = %d, want the 186 uppercase Cyrillic letters and 10 digits", size)
+	}
+	if _, err := machine.Encrypt("\u041f\u0420\u0418\u0412\u0415\u04222025"); err != nil {
+		t.Errorf("Cyrillic capitals and digits should encrypt: %v", err)
+	}
+
+	tests := []struct {
+		args    []string
+		wantErr string
+	}{
+		{[]string{"--alphabet-ranges", "L"}, "more than the 16384"},
+		{[]string{"--alphabet-ranges", "Klingon"}, "unknown Unicode category"},
+		{[]string{"--alphabet-ranges", "A-Z", "--alphabet", "greek"}, "cannot be combined with --alphabet"},
+		{[]string{"--alphabet-ranges", "A-Z", "--preset", "classic"}, "cannot be combined with --preset"},
+	}
+	for _, tt := range tests {
+		if _, err := runCLI(fsys, append([]string{"keygen"}, tt.args...)...); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("keygen %v: error = %v, want one containing %q", tt.args, err, tt.wantErr)
+		}
+	}
+}
+
+func TestAlphabetCheck(t *testing.T) {
+	fsys := NewMemFS()
+	if err := fsys.WriteFile("good.txt", []byte("ABCD\n0123\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	out, err := runCLI(fsys, "alphabet", "--check", "good.txt")
+	if err != nil {
+		t.Fatalf("alphabet --check failed: %v", err)
+	}
+	if !strings.Contains(out, "VALID") {
+		t.Errorf("a clean alphabet should be valid, got:\n%s", out)
+	}
+
+	// A tab, a duplicate A, a decomposed e and an odd count of 5 after cleanup.
+	bad := "AB\tC\nAe\u0301D\n"
+	if err := fsys.WriteFile("bad.txt", []byte(bad), 0600); err != nil {
+		t.Fatal(err)
+	}
+	out, err = runCLI(fsys, "alphabet", "--check", "bad.txt")
+	if err != nil {
+		t.Fatalf("alphabet --check failed: %v", err)
+	}
+	for _, want := range []string{
+		"line 1: control or invisible character U+0009",
+		"line 2: 'A' repeats the one on line 1",
+		"combines with the 'e' before it",
+		"suggested padding character: ' ' (U+0020)",
+		"4 problem(s)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("check output missing %q, got:\n%s", want, out)
+		}
+	}
+	if data, _ := fsys.ReadFile("bad.txt"); string(data) != bad {
+		t.Error("--check without --fix should leave the file alone")
+	}
+
+	if _, err := runCLI(fsys, "alphabet", "--check", "bad.txt", "--fix"); err != nil {
+		t.Fatalf("alphabet --check --fix failed: %v", err)
+	}
+	data, err := fsys.ReadFile("bad.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "ABC\u00e9D \n"; string(data) != want {
+		t.Errorf("fixed alphabet = %q, want %q", data, want)
+	}
+	if out, _ := runCLI(fsys, "alphabet", "--check", "bad.txt"); !strings.Contains(out, "VALID") {
+		t.Errorf("the fixed alphabet should be valid, got:\n%s", out)
+	}
+
+	if _, err := runCLI(fsys, "alphabet", "--fix"); err == nil || !strings.Contains(err.Error(), "--fix requires --check") {
+		t.Errorf("--fix without --check: error = %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+// Package cli provides custom alphabets read from files for the enigoma CLI.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/coredds/enigoma"
+	"github.com/spf13/cobra"
+)
+
+// addAlphabetFileFlag adds --alphabet-file to cmd.
+func addAlphabetFileFlag(cmd *cobra.Command) {
+	cmd.Flags().String("alphabet-file", "", "Read the alphabet from a file of characters, in order (line breaks are ignored)")
+}
+
+// alphabetFileConflicts are the flags that choose the alphabet some other way.
+var alphabetFileConflicts = []string{"alphabet", "alphabet-ranges", "preset", "auto-config", "config"}
+
+// checkAlphabetFileFlag rejects --alphabet-file together with another way
+// of choosing the alphabet.
+func checkAlphabetFileFlag(cmd *cobra.Command) error {
+	if path, _ := cmd.Flags().GetString("alphabet-file"); path == "" {
+		return nil
+	}
+	for _, name := range alphabetFileConflicts {
+		if flag := cmd.Flags().Lookup(name); flag != nil && flag.Changed {
+			return fmt.Errorf("--alphabet-file cannot be combined with --%s", name)
+		}
+	}
+	return nil
+}
+
+// alphabetChar is a character of an alphabet file and the line it is on.
+type alphabetChar struct {
+	r    rune
+	line int
+}
+
+// readAlphabetFile reads the characters of an alphabet file. Every character
+// of the file belongs to the alphabet, spaces included, except line breaks,
+// which let long alphabets span several lines, and a leading byte order mark.
+func readAlphabetFile(fsys FS, path string) ([]alphabetChar, error) {
+	data, err := fsys.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read alphabet file: %w", err)
+	}
+	text := strings.TrimPrefix(string(data), string(byteOrderMark))
+
+	var chars []alphabetChar
+	line := 1
+	for _, r := range text {
+		switch r {
+		case '\n':
+			line++
+		case '\r':
+		default:
+			chars = append(chars, alphabetChar{r: r, line: line})
+		}
+	}
+	if len(chars) == 0 {
+		return nil, fmt.Errorf("alphabet file %s has no characters", path)
+	}
+	return chars, nil
+}
+
+// alphabetFromFile reads the alphabet of --alphabet-file (see
+// readAlphabetFile). Characters must be unique, and with a reflector those it
+// does not leave in place (see --reflector-fixed-points) even in number.
+func alphabetFromFile(cmd *cobra.Command, path string) (enigoma.PredefinedAlphabet, error) {
+	chars, err := readAlphabetFile(fileSystem(cmd), path)
+	if err != nil {
+		return enigoma.PredefinedAlphabet{}, err
+	}
+
+	runes := make([]rune, len(chars))
+	seen := make(map[rune]int, len(chars))
+	for i, c := range chars {
+		if first, ok := seen[c.r]; ok {
+			return enigoma.PredefinedAlphabet{}, fmt.Errorf("alphabet file %s: %q on line %d repeats the one on line %d", path, c.r, c.line, first)
+		}
+		seen[c.r] = c.line
+		runes[i] = c.r
+	}
+	fixedPoints, _ := cmd.Flags().GetInt("reflector-fixed-points")
+	if noReflector, _ := cmd.Flags().GetBool("no-reflector"); !noReflector && (len(runes)-fixedPoints)%2 != 0 {
+		return enigoma.PredefinedAlphabet{}, fmt.Errorf("alphabet file %s has %d characters; a reflector pairs them, so add or remove one (see 'enigoma alphabet --check')", path, len(runes))
+	}
+	return enigoma.PredefinedAlphabet{Name: path, Description: "Alphabet read from " + path, Runes: runes}, nil
+}
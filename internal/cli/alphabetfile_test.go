@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAlphabetFile(t *testing.T) {
+	fsys := NewMemFS()
+	// Line breaks are ignored; the space is part of the alphabet
+	if err := fsys.WriteFile("symbols.txt", []byte("\ufeff\u2600\u2601\u2602\u2603\r\nAB CD012\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runCLI(fsys, "keygen", "--alphabet-file", "symbols.txt", "--security", "low", "--output", "key.json"); err != nil {
+		t.Fatalf("keygen failed: %v", err)
+	}
+	machine, err := createMachineFromConfig(fsys, configOptions{}, "key.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := machine.GetAlphabetSize(); size != 12 {
+		t.Errorf("alphabet size = %d, want 12", size)
+	}
+
+	const text = "\u2600 AB \u2603 0"
+	ciphertext, err := runCLI(fsys, "encrypt", "--text", text, "--config", "key.json")
+	if err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	if out, err := runCLI(fsys, "decrypt", "--text", ciphertext, "--config", "key.json"); err != nil || out != text {
+		t.Errorf("decrypt = %q, %v; want %q", out, err, text)
+	}
+
+	// Both sides derive the same key from a passphrase over the file's alphabet
+	encrypted, err := runCLI(fsys, "encrypt", "--text", text, "--passphrase", "open sesame", "--alphabet-file", "symbols.txt")
+	if err != nil {
+		t.Fatalf("encrypt with a passphrase failed: %v", err)
+	}
+	if out, err := runCLI(fsys, "decrypt", "--text", encrypted, "--passphrase", "open sesame", "--alphabet-file", "symbols.txt"); err != nil || out != text {
+		t.Errorf("decrypt with a passphrase = %q, %v; want %q", out, err, text)
+	}
+}
+
+func TestAlphabetFileErrors(t *testing.T) {
+	fsys := NewMemFS()
+	files := map[string]string{
+		"dup.txt":   "ABC\nDAE",
+		"odd.txt":   "ABC",
+		"empty.txt": "\n\n",
+	}
+	for name, content := range files {
+		if err := fsys.WriteFile(name, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		args    []string
+		wantErr string
+	}{
+		{[]string{"keygen", "--alphabet-file", "dup.txt"}, `'A' on line 2 repeats the one on line 1`},
+		{[]string{"keygen", "--alphabet-file", "odd.txt"}, "has 3 characters"},
+		{[]string{"keygen", "--alphabet-file", "empty.txt"}, "no characters"},
+		{[]string{"keygen", "--alphabet-file", "missing.txt"}, "failed to read alphabet file"},
+		{[]string{"keygen", "--alphabet-file", "odd.txt", "--alphabet", "latin"}, "cannot be combined with --alphabet"},
+		{[]string{"encrypt", "--text", "AB", "--alphabet-file", "odd.txt", "--auto-config", "k.json"}, "cannot be combined with --auto-config"},
+	}
+	for _, tt := range tests {
+		if _, err := runCLI(fsys, tt.args...); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%v: error = %v, want one containing %q", tt.args, err, tt.wantErr)
+		}
+	}
+
+	// An odd alphabet is fine without a reflector
+	if _, err := runCLI(fsys, "keygen", "--alphabet-file", "odd.txt", "--no-reflector", "--security", "low"); err != nil {
+		t.Errorf("an odd alphabet should be accepted with --no-reflector: %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+// Package cli provides the bench command for the enigoma CLI.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/coredds/enigoma/internal/analysis"
+	"github.com/coredds/enigoma/internal/random"
+	"github.com/spf13/cobra"
+)
+
+// newBenchCommand creates the bench command.
+func newBenchCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bench",
+		Short: "Measure encryption throughput and allocations of configurations",
+		Long: `Encrypt --size bytes of random text with each configuration and report
+the time taken, characters and bytes per second, and the heap allocations
+made, one row per configuration.
+
+Configurations are chosen as in 'enigoma compare': each --preset and each
+--security adds a row, security levels use the alphabet from --alphabet, and
+with neither all four security levels are measured.
+
+The size is in bytes of UTF-8 text, with an optional K or M suffix (KiB,
+MiB). The text is drawn from each machine's own alphabet, so wide alphabets
+encrypt fewer characters for the same size. Results vary with load; run
+larger sizes for steadier numbers.
+
+Examples:
+  enigoma bench
+  enigoma bench --preset extreme --size 10MB
+  enigoma bench --security low --security high --alphabet ascii --size 512K`,
+		Args: cobra.NoArgs,
+		RunE: runBench,
+	}
+
+	cmd.Flags().StringSliceP("preset", "p", nil, fmt.Sprintf("Preset to measure (%s); repeatable", strings.Join(presetNames(), ", ")))
+	cmd.Flags().StringSliceP("security", "s", nil, "Security level to measure (low, medium, high, extreme); repeatable")
+	cmd.Flags().StringP("alphabet", "a", "latin", "Alphabet for the --security rows (see 'enigoma alphabet list')")
+	cmd.Flags().String("size", "1MB", "Bytes of text encrypted per configuration (e.g. 10MB, 512K)")
+
+	return cmd
+}
+
+func runBench(cmd *cobra.Command, args []string) error {
+	sizeFlag, _ := cmd.Flags().GetString("size")
+	size, err := parseByteSize(sizeFlag)
+	if err != nil {
+		return fmt.Errorf("invalid --size %q: %v", sizeFlag, err)
+	}
+	columns, err := compareColumns(cmd)
+	if err != nil {
+		return err
+	}
+
+	rows := [][]string{{"CONFIGURATION", "ROTORS", "CHARS", "TIME", "CHARS/S", "BYTES/S", "ALLOCS", "ALLOCATED"}}
+	for _, c := range columns {
+		stats, err := benchRun(c, size)
+		if err != nil {
+			return fmt.Errorf("benchmark of %s failed: %v", c.label, err)
+		}
+		rows = append(rows, []string{
+			c.label,
+			fmt.Sprint(c.machine.GetRotorCount()),
+			fmt.Sprint(stats.Chars),
+			stats.Elapsed.Round(time.Microsecond).String(),
+			formatThroughput(stats.CharsPerSecond()),
+			formatByteRate(stats.BytesPerSecond()),
+			fmt.Sprint(stats.Allocs),
+			formatByteSize(stats.AllocBytes),
+		})
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Encrypting %s of random text per configuration\n\n", formatByteSize(uint64(size)))
+	writeTable(cmd, rows)
+	return nil
+}
+
+// benchRun encrypts about size bytes of text from the column's alphabet on a
+// copy of its machine. The text comes from a fixed deterministic source:
+// it only needs to be cheap to generate and the same from run to run.
+func benchRun(c compareColumn, size int) (analysis.RunStats, error) {
+	clone, err := c.machine.Clone()
+	if err != nil {
+		return analysis.RunStats{}, err
+	}
+	alph, err := machineAlphabet(clone)
+	if err != nil {
+		return analysis.RunStats{}, err
+	}
+	chars := max(int(float64(size)/averageEncodedSize(alph.Runes())), 1)
+	text, err := alph.RandomTextFrom(random.NewDeterministic([]byte("enigoma-bench")), chars)
+	if err != nil {
+		return analysis.RunStats{}, err
+	}
+	return analysis.MeasureRun(func(text string) error {
+		_, err := clone.Encrypt(text)
+		return err
+	}, text)
+}
+
+// formatByteSize renders a byte count with a binary unit prefix, matching
+// the K and M suffixes accepted by parseByteSize.
+func formatByteSize(n uint64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
@@ -0,0 +1,52 @@
+// Package cli provides unit tests for the bench command.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBench(t *testing.T) {
+	out, err := runCLI(NewMemFS(), "bench", "--preset", "classic", "--security", "high", "--alphabet", "ascii", "--size", "2K")
+	if err != nil {
+		t.Fatalf("bench failed: %v", err)
+	}
+	for _, want := range []string{"Encrypting 2.0 KiB", "preset classic", "high / ascii", "CHARS/S", "BYTES/S", "ALLOCS", "chars/s"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if fields := strings.Fields(line); len(fields) > 3 && fields[0] == "preset" && fields[3] != "2048" {
+			t.Errorf("latin text of 2K should be 2048 characters: %q", line)
+		}
+	}
+
+	out, err = runCLI(NewMemFS(), "bench", "--size", "100")
+	if err != nil {
+		t.Fatalf("bench failed: %v", err)
+	}
+	for _, want := range []string{"low / latin", "extreme / latin"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in default benchmark:\n%s", want, out)
+		}
+	}
+
+	if _, err := runCLI(NewMemFS(), "bench", "--size", "lots"); err == nil {
+		t.Error("expected an error for an invalid size")
+	}
+	if _, err := runCLI(NewMemFS(), "bench", "--security", "ultra"); err == nil {
+		t.Error("expected an error for an unknown security level")
+	}
+}
+
+func TestFormatByteSize(t *testing.T) {
+	for n, want := range map[uint64]string{512: "512 B", 2048: "2.0 KiB", 10 << 20: "10.0 MiB"} {
+		if got := formatByteSize(n); got != want {
+			t.Errorf("formatByteSize(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// Package cli provides the --binary mode of the encrypt and decrypt commands.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"fmt"
+
+	"github.com/coredds/enigoma/pkg/enigma"
+	"github.com/spf13/cobra"
+)
+
+// binaryIncompatibleFlags are the text-only flags --binary rejects.
+var binaryIncompatibleFlags = []string{
+	"text", "format", "split", "chain", "auto-config", "config-list", "output-dir",
+	"preset", "alphabet", "verify-plaintext", "preserve-case", "remove-spaces",
+	"uppercase", "letters-only", "alphanumeric-only", "passphrase", "passphrase-file",
+	"shared-secret-env",
+}
+
+// runBinary encrypts or decrypts the raw bytes of --file or stdin and writes
+// them to --output or stdout. The key comes from --config (or --key); encrypt
+// may instead generate a byte-mode key, which it saves to --save-config.
+func runBinary(cmd *cobra.Command, encrypt bool) error {
+	for _, name := range binaryIncompatibleFlags {
+		if flag := cmd.Flags().Lookup(name); flag != nil && flag.Changed {
+			return fmt.Errorf("--%s cannot be combined with --binary", name)
+		}
+	}
+
+	data, err := readBinaryInput(cmd)
+	if err != nil {
+		return err
+	}
+	machine, err := binaryMachine(cmd, encrypt)
+	if err != nil {
+		return err
+	}
+	if err := reportMachineWarnings(cmd, machine); err != nil {
+		return err
+	}
+	if err := checkKeyExpiry(cmd, machine); err != nil {
+		return err
+	}
+	if reset, _ := cmd.Flags().GetBool("reset"); reset {
+		if err := machine.Reset(); err != nil {
+			return fmt.Errorf("failed to reset machine: %v", err)
+		}
+	}
+
+	var output []byte
+	if encrypt {
+		output, err = machine.EncryptBytes(data)
+	} else {
+		output, err = machine.DecryptBytes(data)
+	}
+	if err != nil {
+		return fmt.Errorf("binary mode: %v", err)
+	}
+	if v, _ := cmd.Flags().GetBool("verbose"); v {
+		verb := "Decrypted"
+		if encrypt {
+			verb = "Encrypted"
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "%s %d bytes\n", verb, len(output))
+	}
+
+	if outputFile, _ := cmd.Flags().GetString("output"); outputFile != "" {
+		return fileSystem(cmd).WriteFile(outputFile, output, 0600)
+	}
+	_, err = cmd.OutOrStdout().Write(output)
+	return err
+}
+
+// readBinaryInput reads --file, or stdin, without any text cleanup.
+func readBinaryInput(cmd *cobra.Command) ([]byte, error) {
+	var data []byte
+	if filename, _ := cmd.Flags().GetString("file"); filename != "" {
+		var err error
+		if data, err = fileSystem(cmd).ReadFile(filename); err != nil {
+			return nil, fmt.Errorf("failed to read file %s: %w", filename, err)
+		}
+	} else {
+		piped, err := readPipedInput(cmd)
+		if err != nil {
+			return nil, err
+		}
+		data = []byte(piped)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("no input data provided. Use --file or pipe to stdin")
+	}
+	return data, nil
+}
+
+// binaryMachine loads the key of --config, or for encrypt generates a
+// byte-mode key at --security and saves it to --save-config.
+func binaryMachine(cmd *cobra.Command, encrypt bool) (*enigma.Enigma, error) {
+	if configFile, _ := cmd.Flags().GetString("config"); configFile != "" {
+		machine, err := createMachineFromConfig(fileSystem(cmd), configOptionsFor(cmd), configFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Enigma machine: %v", err)
+		}
+		if !machine.IsByteMode() {
+			return nil, fmt.Errorf("the key in %s has characters above U+00FF and cannot encrypt bytes; create one with 'enigoma keygen --alphabet bytes'", configFile)
+		}
+		return machine, nil
+	}
+
+	savePath, _ := cmd.Flags().GetString("save-config")
+	if !encrypt || savePath == "" {
+		return nil, fmt.Errorf("--binary needs a key: use --config, or --save-config to generate one when encrypting")
+	}
+	level, err := getSecurityLevelFromFlag(cmd)
+	if err != nil {
+		return nil, err
+	}
+	machine, err := enigma.NewByteEnigma(level)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Enigma machine: %v", err)
+	}
+	if err := saveConfigIfRequested(cmd, machine); err != nil {
+		return nil, err
+	}
+	return machine, nil
+}
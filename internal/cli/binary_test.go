@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	fsys := NewMemFS()
+	data := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\xff\xfe")
+	if err := fsys.WriteFile("image.png", data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runCLI(fsys, "encrypt", "--binary", "--file", "image.png", "--output", "image.enc", "--save-config", "key.json", "--security", "low"); err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	encrypted, err := fsys.ReadFile("image.enc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(encrypted) != len(data) || bytes.Equal(encrypted, data) {
+		t.Fatalf("the encrypted file should have the data's length and differ from it: %q", encrypted)
+	}
+
+	out, err := runCLI(fsys, "decrypt", "--binary", "--file", "image.enc", "--config", "key.json")
+	if err != nil {
+		t.Fatalf("decrypt failed: %v", err)
+	}
+	if out != string(data) {
+		t.Errorf("decrypt = %q, want %q", out, data)
+	}
+
+	// Keys made by keygen --alphabet bytes work too
+	if _, err := runCLI(fsys, "keygen", "--alphabet", "bytes", "--security", "low", "--output", "bytes.json"); err != nil {
+		t.Fatalf("keygen failed: %v", err)
+	}
+	if _, err := runCLI(fsys, "encrypt", "--binary", "--file", "image.png", "--config", "bytes.json"); err != nil {
+		t.Errorf("encrypt with a keygen key failed: %v", err)
+	}
+}
+
+func TestBinaryErrors(t *testing.T) {
+	fsys := NewMemFS()
+	if err := fsys.WriteFile("data.bin", []byte{0, 1, 2}, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runCLI(fsys, "keygen", "--alphabet", "greek", "--security", "low", "--output", "greek.json"); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		args    []string
+		wantErr string
+	}{
+		{[]string{"encrypt", "--binary", "--file", "data.bin"}, "needs a key"},
+		{[]string{"decrypt", "--binary", "--file", "data.bin", "--save-config", "k.json"}, "unknown flag"},
+		{[]string{"encrypt", "--binary", "--text", "abc", "--save-config", "k.json"}, "--text cannot be combined with --binary"},
+		{[]string{"encrypt", "--binary", "--file", "data.bin", "--config", "greek.json"}, "above U+00FF"},
+		{[]string{"encrypt", "--binary", "--file", "missing.bin", "--save-config", "k.json"}, "failed to read file"},
+	}
+	for _, tt := range tests {
+		if _, err := runCLI(fsys, tt.args...); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%v: error = %v, want one containing %q", tt.args, err, tt.wantErr)
+		}
+	}
+}
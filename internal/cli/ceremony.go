@@ -0,0 +1,299 @@
+// Package cli provides the interactive key ceremony command for the enigoma CLI.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/coredds/enigoma"
+	"github.com/coredds/enigoma/pkg/enigma"
+	"github.com/spf13/cobra"
+)
+
+// warnWeakContribution flags a ceremony contribution with little estimated entropy.
+const warnWeakContribution enigma.WarningCode = "weak_contribution"
+
+// minContributionBits is the estimated entropy below which a contribution is flagged.
+const minContributionBits = 64
+
+// ceremonyParticipant is one operator's input to a key ceremony.
+type ceremonyParticipant struct {
+	Name         string
+	Contribution string
+	Dice         bool
+}
+
+// digest commits to the participant's name and contribution.
+func (p ceremonyParticipant) digest() [sha256.Size]byte {
+	h := sha256.New()
+	h.Write([]byte("enigoma/ceremony/v1/contribution"))
+	writeLengthPrefixed(h, p.Name)
+	writeLengthPrefixed(h, p.Contribution)
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
+
+// fingerprint is the short form of digest printed in transcripts.
+func (p ceremonyParticipant) fingerprint() string {
+	sum := p.digest()
+	return hex.EncodeToString(sum[:8])
+}
+
+// entropyBits estimates the contribution's entropy: log2(6) bits per die
+// roll, or a conservative 2 bits per character for a passphrase.
+func (p ceremonyParticipant) entropyBits() float64 {
+	if p.Dice {
+		return float64(len(strings.Fields(p.Contribution))) * math.Log2(6)
+	}
+	return float64(len([]rune(p.Contribution))) * 2
+}
+
+// newCeremonyCommand creates the key ceremony command.
+func newCeremonyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ceremony",
+		Short: "Jointly create a key with entropy from several operators",
+		Long: `Guide two or more operators through jointly creating a key.
+
+Each participant enters a name and a contribution: either dice rolls
+(digits 1-6 separated by spaces) or a passphrase. The contributions are
+mixed in order into the deterministic generator, so no single operator
+chooses the key, and running the ceremony again with the same inputs
+reproduces it.
+
+A transcript listing each participant's contribution fingerprint and the
+key fingerprint is printed; with --transcript-dir a copy is also written
+for each participant so everyone can verify the key they receive.
+
+Contributions are read from standard input and are not hidden while typed.
+
+Examples:
+  enigoma ceremony --output team-key.json
+  enigoma ceremony --participants 3 --security high --output team-key.json --transcript-dir transcripts`,
+		RunE: runCeremony,
+	}
+
+	cmd.Flags().Int("participants", 2, "Number of participants (at least 2)")
+	cmd.Flags().StringP("security", "s", "medium", "Security level: low, medium, high, extreme")
+	cmd.Flags().StringP("alphabet", "a", "latin", "Alphabet to use (see 'enigoma alphabet list')")
+	cmd.Flags().StringP("output", "o", "", "Output file for the key (required)")
+	cmd.Flags().String("transcript-dir", "", "Directory to write one transcript per participant")
+	_ = cmd.MarkFlagRequired("output")
+
+	return cmd
+}
+
+func runCeremony(cmd *cobra.Command, args []string) error {
+	count, _ := cmd.Flags().GetInt("participants")
+	if count < 2 {
+		return fmt.Errorf("a ceremony needs at least 2 participants, got %d", count)
+	}
+
+	level, err := getSecurityLevelFromFlag(cmd)
+	if err != nil {
+		return err
+	}
+
+	alphabetName, _ := cmd.Flags().GetString("alphabet")
+	predefined, ok := enigoma.LookupAlphabet(alphabetName)
+	if !ok {
+		return fmt.Errorf("unknown alphabet: %s. Available: %s (see 'enigoma alphabet list')", alphabetName, alphabetNameList(false))
+	}
+
+	out := uiOut(cmd)
+	reader := bufio.NewReader(cmd.InOrStdin())
+	fmt.Fprintf(out, "🔑 Key ceremony for %d participants\n\n", count)
+
+	participants := make([]ceremonyParticipant, 0, count)
+	for i := 1; i <= count; i++ {
+		p, err := askParticipant(reader, out, i, participants)
+		if err != nil {
+			return err
+		}
+		if bits := p.entropyBits(); bits < minContributionBits {
+			if err := reportWarnings(cmd, enigma.Warning{
+				Code:    warnWeakContribution,
+				Message: fmt.Sprintf("contribution from %s has an estimated %.0f bits of entropy (recommended: %d)", p.Name, bits, minContributionBits),
+			}); err != nil {
+				return err
+			}
+		}
+		participants = append(participants, p)
+		fmt.Fprintf(out, "  Recorded contribution %s\n\n", p.fingerprint())
+	}
+
+	machine, err := enigma.NewFromSeed(ceremonySeed(participants), level, enigma.WithAlphabet(predefined.Runes))
+	if err != nil {
+		return fmt.Errorf("failed to create machine: %v", err)
+	}
+	if err := reportMachineWarnings(cmd, machine); err != nil {
+		return err
+	}
+
+	created := now().UTC()
+	names := make([]string, len(participants))
+	for i, p := range participants {
+		names[i] = p.Name
+	}
+	machine.SetMetadata(&enigma.Metadata{
+		CreatedAt:   created.Format(time.RFC3339),
+		CreatedBy:   "enigoma ceremony",
+		Description: "Key ceremony with " + strings.Join(names, ", "),
+	})
+
+	fingerprint, err := machine.Fingerprint()
+	if err != nil {
+		return err
+	}
+	jsonData, err := machine.SaveSettingsToJSON()
+	if err != nil {
+		return fmt.Errorf("failed to save settings: %v", err)
+	}
+
+	fsys, opts := fileSystem(cmd), configOptionsFor(cmd)
+	outputFile, _ := cmd.Flags().GetString("output")
+	if err := writeConfigFile(fsys, opts, outputFile, jsonData); err != nil {
+		return fmt.Errorf("failed to write key file: %v", err)
+	}
+
+	securityName, _ := cmd.Flags().GetString("security")
+	transcript := ceremonyTranscript(participants, created, strings.ToLower(securityName), predefined.Name, fingerprint, outputFile)
+	fmt.Fprint(out, transcript)
+
+	if dir, _ := cmd.Flags().GetString("transcript-dir"); dir != "" {
+		if err := fsys.MkdirAll(dir, 0700); err != nil {
+			return fmt.Errorf("failed to create transcript directory: %v", err)
+		}
+		for i, p := range participants {
+			path := filepath.Join(dir, fmt.Sprintf("transcript-%d-%s.txt", i+1, fileSlug(p.Name)))
+			content := fmt.Sprintf("Prepared for: %s\n%s", p.Name, transcript)
+			if err := writeStringToFile(fsys, content, path); err != nil {
+				return fmt.Errorf("failed to write transcript: %v", err)
+			}
+			fmt.Fprintf(out, "Transcript for %s saved to %s\n", p.Name, path)
+		}
+	}
+
+	return nil
+}
+
+// askParticipant prompts for one participant's name and contribution.
+func askParticipant(reader *bufio.Reader, out io.Writer, n int, previous []ceremonyParticipant) (ceremonyParticipant, error) {
+	fmt.Fprintf(out, "Participant %d name: ", n)
+	name, err := readCeremonyLine(reader)
+	if err != nil {
+		return ceremonyParticipant{}, err
+	}
+	if name == "" {
+		name = fmt.Sprintf("Participant %d", n)
+	}
+	for _, p := range previous {
+		if strings.EqualFold(p.Name, name) {
+			return ceremonyParticipant{}, fmt.Errorf("participant name %q is already taken", name)
+		}
+	}
+
+	fmt.Fprintf(out, "%s, enter dice rolls (e.g. 3 6 1 4 ...) or a passphrase: ", name)
+	contribution, err := readCeremonyLine(reader)
+	if err != nil {
+		return ceremonyParticipant{}, err
+	}
+	if contribution == "" {
+		return ceremonyParticipant{}, fmt.Errorf("contribution from %s cannot be empty", name)
+	}
+
+	return ceremonyParticipant{Name: name, Contribution: contribution, Dice: isDiceRolls(contribution)}, nil
+}
+
+// readCeremonyLine reads one trimmed line, accepting a final line without a newline.
+func readCeremonyLine(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", fmt.Errorf("failed to read input: %v", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// isDiceRolls reports whether s consists only of digits 1-6 and whitespace.
+// Rolls are counted per whitespace-separated field, so "3 6 1" is three rolls.
+func isDiceRolls(s string) bool {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return false
+	}
+	for _, f := range fields {
+		if len(f) != 1 || f[0] < '1' || f[0] > '6' {
+			return false
+		}
+	}
+	return true
+}
+
+// ceremonySeed mixes the participants' commitments, in order, into a seed.
+func ceremonySeed(participants []ceremonyParticipant) []byte {
+	h := sha256.New()
+	h.Write([]byte("enigoma/ceremony/v1/seed"))
+	for _, p := range participants {
+		sum := p.digest()
+		h.Write(sum[:])
+	}
+	return h.Sum(nil)
+}
+
+// ceremonyTranscript renders the shared record of a ceremony.
+func ceremonyTranscript(participants []ceremonyParticipant, created time.Time, security, alphabetName, fingerprint, keyFile string) string {
+	var b strings.Builder
+	fmt.Fprintln(&b, "enigoma key ceremony transcript")
+	fmt.Fprintf(&b, "Date: %s\n", created.Format(time.RFC3339))
+	fmt.Fprintf(&b, "Security: %s\n", security)
+	fmt.Fprintf(&b, "Alphabet: %s\n", alphabetName)
+	fmt.Fprintln(&b, "Participants:")
+	for i, p := range participants {
+		kind := "passphrase"
+		if p.Dice {
+			kind = "dice"
+		}
+		fmt.Fprintf(&b, "  %d. %s (%s, contribution %s)\n", i+1, p.Name, kind, p.fingerprint())
+	}
+	fmt.Fprintf(&b, "Key file: %s\n", keyFile)
+	fmt.Fprintf(&b, "Key fingerprint: %s\n", fingerprint)
+	return b.String()
+}
+
+// writeLengthPrefixed writes s preceded by its length so fields cannot run together.
+func writeLengthPrefixed(w io.Writer, s string) {
+	var n [4]byte
+	binary.BigEndian.PutUint32(n[:], uint32(len(s)))
+	w.Write(n[:])
+	io.WriteString(w, s)
+}
+
+// fileSlug turns a participant name into a safe file name fragment.
+func fileSlug(name string) string {
+	slug := strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			return unicode.ToLower(r)
+		default:
+			return '-'
+		}
+	}, name)
+	slug = strings.Trim(slug, "-")
+	if slug == "" {
+		return "participant"
+	}
+	return slug
+}
@@ -0,0 +1,134 @@
+// Package cli provides unit tests for the key ceremony command.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCeremonyReproducible(t *testing.T) {
+	withClock(t, time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC))
+	input := "Alice\n3 1 4 1 5 2 6 5 3 5 6 2 4 3 1 2 6 4 3 3 2 1 6 5 4 4 1 2\nBob\nthe owls are not what they seem at all\n"
+
+	run := func() (*MemFS, string, string) {
+		t.Helper()
+		fsys := NewMemFS()
+		var out, errOut bytes.Buffer
+		cmd := NewRootCommand(Options{In: strings.NewReader(input), Out: &out, Err: &errOut, FS: fsys})
+		cmd.SetArgs([]string{"ceremony", "--security", "low", "--output", "team.json", "--transcript-dir", "transcripts"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("ceremony failed: %v", err)
+		}
+		data, err := fsys.ReadFile("team.json")
+		if err != nil {
+			t.Fatalf("key file not written: %v", err)
+		}
+		return fsys, string(data), out.String() + errOut.String()
+	}
+
+	fsys, first, output := run()
+	_, second, _ := run()
+	if first != second {
+		t.Error("same contributions produced different keys")
+	}
+	if strings.Contains(output, "weak_contribution") || strings.Contains(output, "Warning") {
+		t.Errorf("unexpected warning for strong contributions:\n%s", output)
+	}
+	for _, want := range []string{"1. Alice (dice", "2. Bob (passphrase", "Key fingerprint: "} {
+		if !strings.Contains(output, want) {
+			t.Errorf("transcript missing %q:\n%s", want, output)
+		}
+	}
+
+	transcript, err := fsys.ReadFile("transcripts/transcript-2-bob.txt")
+	if err != nil {
+		t.Fatalf("participant transcript not written: %v", err)
+	}
+	if !strings.HasPrefix(string(transcript), "Prepared for: Bob\n") {
+		t.Errorf("unexpected transcript:\n%s", transcript)
+	}
+
+	machine, err := createMachineFromConfig(fsys, configOptions{}, "team.json")
+	if err != nil {
+		t.Fatalf("loading ceremony key failed: %v", err)
+	}
+	fingerprint, _ := machine.Fingerprint()
+	if !strings.Contains(output, fingerprint) {
+		t.Errorf("transcript fingerprint does not match key %s", fingerprint)
+	}
+}
+
+func TestCeremonyContributionsMatter(t *testing.T) {
+	key := func(input string) string {
+		t.Helper()
+		fsys := NewMemFS()
+		cmd := NewRootCommand(Options{In: strings.NewReader(input), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, FS: fsys})
+		cmd.SetArgs([]string{"ceremony", "--output", "k.json"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("ceremony failed: %v", err)
+		}
+		machine, err := createMachineFromConfig(fsys, configOptions{}, "k.json")
+		if err != nil {
+			t.Fatalf("loading key failed: %v", err)
+		}
+		fp, _ := machine.Fingerprint()
+		return fp
+	}
+
+	base := key("A\nfirst passphrase\nB\nsecond passphrase\n")
+	if key("A\nfirst passphrase\nB\nsecond passphrasf\n") == base {
+		t.Error("changing one contribution did not change the key")
+	}
+	if key("B\nsecond passphrase\nA\nfirst passphrase\n") == base {
+		t.Error("participant order did not change the key")
+	}
+}
+
+func TestCeremonyWeakAndInvalidInput(t *testing.T) {
+	var errOut bytes.Buffer
+	cmd := NewRootCommand(Options{In: strings.NewReader("A\n1 2 3\nB\nhunter2\n"), Out: &bytes.Buffer{}, Err: &errOut, FS: NewMemFS()})
+	cmd.SetArgs([]string{"ceremony", "--output", "k.json", "--warnings-format", "json"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("ceremony failed: %v", err)
+	}
+	if got := strings.Count(errOut.String(), `"code":"weak_contribution"`); got != 2 {
+		t.Errorf("expected 2 weak contribution warnings, got %d:\n%s", got, errOut.String())
+	}
+
+	for _, tc := range []struct {
+		name  string
+		input string
+		args  []string
+	}{
+		{"one participant", "A\nx\n", []string{"--participants", "1"}},
+		{"empty contribution", "A\n\nB\ny\n", nil},
+		{"duplicate name", "A\nx\na\ny\n", nil},
+		{"input ends early", "A\nx\n", nil},
+	} {
+		cmd := NewRootCommand(Options{In: strings.NewReader(tc.input), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, FS: NewMemFS()})
+		cmd.SetArgs(append([]string{"ceremony", "--output", "k.json"}, tc.args...))
+		if err := cmd.Execute(); err == nil {
+			t.Errorf("%s: expected an error", tc.name)
+		}
+	}
+}
+
+func TestIsDiceRolls(t *testing.T) {
+	for s, want := range map[string]bool{
+		"1 2 3 4 5 6": true,
+		"6":           true,
+		"1 7":         false,
+		"12 3":        false,
+		"hello":       false,
+		"":            false,
+	} {
+		if got := isDiceRolls(s); got != want {
+			t.Errorf("isDiceRolls(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
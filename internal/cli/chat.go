@@ -0,0 +1,315 @@
+// Package cli provides the session-based chat command for the enigoma CLI.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/coredds/enigoma/pkg/enigma"
+	"github.com/spf13/cobra"
+)
+
+// chatFramePrefix starts every framed chat message.
+const chatFramePrefix = "CHAT1:"
+
+// maxChatSkip bounds how far a frame's offset may move the rotors in one go.
+const maxChatSkip = 1 << 24
+
+// chatSessionExtension is appended to the key file name to form the default session file.
+const chatSessionExtension = ".session"
+
+// chatSession is the state persisted between chat messages. Offset counts the
+// characters processed since the key's starting positions; Positions are the
+// rotor positions at that offset, so the next message continues from there.
+type chatSession struct {
+	Fingerprint string `json:"fingerprint"` // Fingerprint of the key the session belongs to
+	Offset      int    `json:"offset"`
+	Positions   []int  `json:"positions"`
+	UpdatedAt   string `json:"updated_at,omitempty"`
+}
+
+// newChatCommand creates the chat command.
+func newChatCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "chat",
+		Short: "Exchange messages with continuously advancing rotors",
+		Long: `Encrypt and decrypt a conversation as one continuous stream.
+
+Both parties load the same key. Each message continues from the rotor
+positions where the previous one stopped instead of resetting, so no two
+messages are encrypted with the same rotor settings. The state is kept in a
+session file (default: <config>.session) between runs.
+
+Messages are framed as CHAT1:<offset>:<base64 ciphertext>. The offset tells
+the receiver where in the stream the message starts, so messages that arrive
+out of order or after a gap still decrypt. Take turns: two messages sent at
+the same offset reuse the same rotor settings.
+
+Without --send or --recv, lines are read from stdin: framed lines are
+decrypted, anything else is encrypted and printed as a frame.
+
+Examples:
+  enigoma chat --config team-key.json --send "MEETATNOON"
+  enigoma chat --config team-key.json --recv "CHAT1:0:..."
+  enigoma chat --config team-key.json --session alice.session`,
+		RunE: runChat,
+	}
+
+	cmd.Flags().String("send", "", "Encrypt one message and print its frame")
+	cmd.Flags().String("recv", "", "Decrypt one framed message")
+	cmd.Flags().String("session", "", "Session file (default: <config>.session)")
+	cmd.Flags().Bool("new-session", false, "Start the stream over from the key's starting positions")
+	cmd.MarkFlagsMutuallyExclusive("send", "recv")
+
+	return cmd
+}
+
+func runChat(cmd *cobra.Command, args []string) error {
+	configFile, _ := cmd.Flags().GetString("config")
+	if configFile == "" {
+		return fmt.Errorf("chat needs a key: use --config key.json")
+	}
+
+	fsys, opts := fileSystem(cmd), configOptionsFor(cmd)
+	machine, err := createMachineFromConfig(fsys, opts, configFile)
+	if err != nil {
+		return err
+	}
+	if err := reportMachineWarnings(cmd, machine); err != nil {
+		return err
+	}
+
+	sessionFile, _ := cmd.Flags().GetString("session")
+	if sessionFile == "" {
+		sessionFile = configFile + chatSessionExtension
+	}
+
+	// Concurrent sends would reuse the same offset; take turns instead
+	return withFileLock(fsys, sessionFile, func() error {
+		return runChatSession(cmd, fsys, machine, sessionFile)
+	})
+}
+
+// runChatSession processes messages and records the session's progress.
+func runChatSession(cmd *cobra.Command, fsys FS, machine *enigma.Enigma, sessionFile string) error {
+	session, err := loadChatSession(cmd, machine, sessionFile)
+	if err != nil {
+		return err
+	}
+	verboseKeyInfo(cmd, machine, session.Fingerprint)
+
+	chat := &chatStream{machine: machine, session: session}
+	save := func() error {
+		session.UpdatedAt = now().UTC().Format(time.RFC3339)
+		data, err := json.MarshalIndent(session, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode session: %v", err)
+		}
+		return writeStringToFile(fsys, string(data), sessionFile)
+	}
+
+	out := cmd.OutOrStdout()
+	if msg, _ := cmd.Flags().GetString("send"); msg != "" {
+		frame, err := chat.send(msg)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, frame)
+		return save()
+	}
+	if frame, _ := cmd.Flags().GetString("recv"); frame != "" {
+		plaintext, err := chat.recv(cmd, frame)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, plaintext)
+		return save()
+	}
+
+	scanner := bufio.NewScanner(cmd.InOrStdin())
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		var result string
+		if strings.HasPrefix(line, chatFramePrefix) {
+			result, err = chat.recv(cmd, line)
+			result = "< " + result
+		} else {
+			result, err = chat.send(line)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, result)
+		if err := save(); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read input: %v", err)
+	}
+	return nil
+}
+
+// loadChatSession reads the session for machine, or starts a new one at the
+// key's starting positions.
+func loadChatSession(cmd *cobra.Command, machine *enigma.Enigma, sessionFile string) (*chatSession, error) {
+	fingerprint, err := machine.Fingerprint()
+	if err != nil {
+		return nil, err
+	}
+	fresh := &chatSession{Fingerprint: fingerprint, Positions: machine.GetCurrentRotorPositions()}
+
+	if restart, _ := cmd.Flags().GetBool("new-session"); restart {
+		return fresh, nil
+	}
+	data, err := fileSystem(cmd).ReadFile(sessionFile)
+	if os.IsNotExist(err) {
+		return fresh, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session file: %v", err)
+	}
+
+	var session chatSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("invalid session file %s: %v", sessionFile, err)
+	}
+	if session.Fingerprint != fingerprint {
+		return nil, fmt.Errorf("session file %s belongs to a different key; use --new-session to start over", sessionFile)
+	}
+	if session.Offset < 0 || len(session.Positions) != machine.GetRotorCount() {
+		return nil, fmt.Errorf("invalid session file %s: corrupt offset or positions", sessionFile)
+	}
+	return &session, nil
+}
+
+// chatStream encrypts and decrypts messages at their offset in the stream.
+type chatStream struct {
+	machine *enigma.Enigma
+	session *chatSession
+}
+
+// send encrypts msg at the session offset and returns its frame.
+func (c *chatStream) send(msg string) (string, error) {
+	offset := c.session.Offset
+	if err := c.seek(offset); err != nil {
+		return "", err
+	}
+	ciphertext, err := c.machine.Encrypt(msg)
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt message: %v", err)
+	}
+	c.advance(offset + len([]rune(msg)))
+	return fmt.Sprintf("%s%d:%s", chatFramePrefix, offset, base64.StdEncoding.EncodeToString([]byte(ciphertext))), nil
+}
+
+// recv decrypts a frame. Frames from before the session offset are decrypted
+// by replaying the stream and leave the session unchanged.
+func (c *chatStream) recv(cmd *cobra.Command, frame string) (string, error) {
+	offset, ciphertext, err := parseChatFrame(frame)
+	if err != nil {
+		return "", err
+	}
+	if v, _ := cmd.Flags().GetBool("verbose"); v {
+		switch {
+		case offset > c.session.Offset:
+			fmt.Fprintf(cmd.ErrOrStderr(), "Skipping %d characters of missed messages\n", offset-c.session.Offset)
+		case offset < c.session.Offset:
+			fmt.Fprintf(cmd.ErrOrStderr(), "Replaying an earlier message at offset %d\n", offset)
+		}
+	}
+
+	if err := c.seek(offset); err != nil {
+		return "", err
+	}
+	plaintext, err := c.machine.Decrypt(ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt message: %v", err)
+	}
+	if end := offset + len([]rune(ciphertext)); end > c.session.Offset {
+		c.advance(end)
+	}
+	return plaintext, nil
+}
+
+// seek puts the machine at offset, continuing from the session positions
+// when possible and replaying from the key's starting positions otherwise.
+func (c *chatStream) seek(offset int) error {
+	from := c.session.Offset
+	if offset >= from {
+		if err := c.machine.SetRotorPositions(c.session.Positions); err != nil {
+			return err
+		}
+	} else {
+		if err := c.machine.Reset(); err != nil {
+			return err
+		}
+		from = 0
+	}
+	return stepMachine(c.machine, offset-from)
+}
+
+// advance records that the stream now continues at offset from the machine's positions.
+func (c *chatStream) advance(offset int) {
+	c.session.Offset = offset
+	c.session.Positions = c.machine.GetCurrentRotorPositions()
+}
+
+// stepMachine advances the rotors by n characters, a chunk at a time.
+func stepMachine(machine *enigma.Enigma, n int) error {
+	if n <= 0 {
+		return nil
+	}
+	if n > maxChatSkip {
+		return fmt.Errorf("message is %d characters ahead of the session; refusing to skip more than %d", n, maxChatSkip)
+	}
+	settings, err := machine.GetSettings()
+	if err != nil {
+		return err
+	}
+	filler := strings.Repeat(string(settings.Alphabet[0]), 4096)
+	for n > 0 {
+		chunk := filler
+		if n < 4096 {
+			chunk = filler[:n*len(string(settings.Alphabet[0]))]
+		}
+		if _, err := machine.Encrypt(chunk); err != nil {
+			return err
+		}
+		n -= len([]rune(chunk))
+	}
+	return nil
+}
+
+// parseChatFrame splits a CHAT1:<offset>:<base64> frame.
+func parseChatFrame(frame string) (int, string, error) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(frame), chatFramePrefix)
+	if !ok {
+		return 0, "", fmt.Errorf("not a chat frame: expected %s<offset>:<ciphertext>", chatFramePrefix)
+	}
+	offsetText, payload, ok := strings.Cut(rest, ":")
+	if !ok {
+		return 0, "", fmt.Errorf("malformed chat frame: missing ciphertext")
+	}
+	offset, err := strconv.Atoi(offsetText)
+	if err != nil || offset < 0 {
+		return 0, "", fmt.Errorf("malformed chat frame: invalid offset %q", offsetText)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return 0, "", fmt.Errorf("malformed chat frame: %v", err)
+	}
+	return offset, string(ciphertext), nil
+}
@@ -0,0 +1,78 @@
+// Package cli provides unit tests for the chat command.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestChatConversation(t *testing.T) {
+	fsys := NewMemFS()
+	run := func(in string, args ...string) (string, error) {
+		t.Helper()
+		var out bytes.Buffer
+		cmd := NewRootCommand(Options{In: strings.NewReader(in), Out: &out, Err: &bytes.Buffer{}, FS: fsys})
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return strings.TrimSuffix(out.String(), "\n"), err
+	}
+	mustRun := func(args ...string) string {
+		t.Helper()
+		out, err := run("", args...)
+		if err != nil {
+			t.Fatalf("%v failed: %v", args, err)
+		}
+		return out
+	}
+
+	mustRun("keygen", "--alphabet", "latin", "--security", "low", "--output", "key.json")
+	key, _ := fsys.ReadFile("key.json")
+	if err := fsys.WriteFile("bob.json", key, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Alice sends twice; the same text must not produce the same ciphertext
+	first := mustRun("chat", "--config", "key.json", "--send", "HELLO")
+	second := mustRun("chat", "--config", "key.json", "--send", "HELLO")
+	if !strings.HasPrefix(first, "CHAT1:0:") || !strings.HasPrefix(second, "CHAT1:5:") {
+		t.Fatalf("unexpected frames %q and %q", first, second)
+	}
+	if first[len("CHAT1:0:"):] == second[len("CHAT1:5:"):] {
+		t.Error("repeated message reused the rotor settings")
+	}
+
+	// Bob receives out of order with his own copy of the key and session
+	if got := mustRun("chat", "--config", "bob.json", "--recv", second); got != "HELLO" {
+		t.Errorf("second message decrypted to %q", got)
+	}
+	if got := mustRun("chat", "--config", "bob.json", "--recv", first); got != "HELLO" {
+		t.Errorf("first message decrypted to %q", got)
+	}
+
+	// Bob replies from where the stream ended; Alice reads it interactively
+	reply := mustRun("chat", "--config", "bob.json", "--send", "HITHERE")
+	if !strings.HasPrefix(reply, "CHAT1:10:") {
+		t.Fatalf("reply should continue the stream, got %q", reply)
+	}
+	out, err := run(reply+"\nBYE\n", "chat", "--config", "key.json")
+	if err != nil {
+		t.Fatalf("interactive chat failed: %v", err)
+	}
+	lines := strings.Split(out, "\n")
+	if len(lines) != 2 || lines[0] != "< HITHERE" || !strings.HasPrefix(lines[1], "CHAT1:17:") {
+		t.Errorf("unexpected interactive output %q", out)
+	}
+
+	// A session cannot be used with another key
+	mustRun("keygen", "--alphabet", "latin", "--security", "low", "--output", "other.json")
+	if _, err := run("", "chat", "--config", "other.json", "--session", "key.json.session", "--send", "HI"); err == nil {
+		t.Error("expected an error for a session from a different key")
+	}
+	if _, err := run("", "chat", "--config", "key.json", "--recv", "HELLO"); err == nil {
+		t.Error("expected an error for an unframed message")
+	}
+}
@@ -7,13 +7,12 @@ package cli
 import (
 	"bytes"
 	"encoding/json"
+	"math/big"
 	"os"
-	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/coredds/enigoma/pkg/enigma"
-	"github.com/spf13/cobra"
 )
 
 // TestRootCommand tests the basic root command functionality.
@@ -34,7 +33,7 @@ func TestRootCommand(t *testing.T) {
 			name:     "help flag",
 			args:     []string{"--help"},
 			wantErr:  false,
-			contains: "A highly customizable, Unicode-capable Enigma machine",
+			contains: "simulates the famous Enigma machine",
 		},
 		{
 			name:     "invalid command",
@@ -50,7 +49,7 @@ func TestRootCommand(t *testing.T) {
 			var out bytes.Buffer
 
 			// Create a new root command for testing
-			cmd := createTestRootCmd()
+			cmd := NewRootCommand(Options{})
 			cmd.SetOut(&out)
 			cmd.SetErr(&out)
 			cmd.SetArgs(tt.args)
@@ -78,8 +77,7 @@ func TestEncryptCommand(t *testing.T) {
 		name    string
 		args    []string
 		wantErr bool
-		setup   func(t *testing.T) string // Returns temp file path if needed
-		cleanup func(string)
+		setup   func(t *testing.T, fsys FS) string // Returns input file path if needed
 	}{
 		{
 			name:    "encrypt with text and preset",
@@ -101,53 +99,36 @@ func TestEncryptCommand(t *testing.T) {
 			args:    []string{"encrypt", "--preset", "classic"},
 			wantErr: true,
 		},
-		// stdin pipeline not supported by our test harness; cover stdin behavior via decrypt tests
 		{
 			name:    "encrypt with file input",
 			args:    []string{"encrypt", "--file", "", "--preset", "classic"},
 			wantErr: false,
-			setup: func(t *testing.T) string {
-				tmpFile, err := os.CreateTemp("", "test-input-*.txt")
-				if err != nil {
-					t.Fatalf("Failed to create temp file: %v", err)
+			setup: func(t *testing.T, fsys FS) string {
+				if err := fsys.WriteFile("input.txt", []byte("HELLOWORLD"), 0600); err != nil {
+					t.Fatalf("Failed to write input file: %v", err)
 				}
-				_, err = tmpFile.WriteString("HELLOWORLD")
-				if err != nil {
-					t.Fatalf("Failed to write to temp file: %v", err)
-				}
-				tmpFile.Close()
-				return tmpFile.Name()
-			},
-			cleanup: func(path string) {
-				os.Remove(path)
+				return "input.txt"
 			},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			var tempFile string
+			fsys := NewMemFS()
 			if tt.setup != nil {
-				tempFile = tt.setup(t)
-				// Replace empty file path with actual temp file
+				inputFile := tt.setup(t, fsys)
+				// Replace empty file path with the input file
 				for i, arg := range tt.args {
 					if arg == "--file" && i+1 < len(tt.args) && tt.args[i+1] == "" {
-						tt.args[i+1] = tempFile
+						tt.args[i+1] = inputFile
 					}
 				}
 			}
 
-			if tt.cleanup != nil {
-				defer tt.cleanup(tempFile)
-			}
-
 			var out bytes.Buffer
-			cmd := createTestRootCmd()
+			cmd := NewRootCommand(Options{FS: fsys})
 			cmd.SetOut(&out)
 			cmd.SetErr(&out)
-			if tt.name == "encrypt via stdin with auto-config" {
-				cmd.SetIn(strings.NewReader(tempFile))
-			}
 			cmd.SetArgs(tt.args)
 
 			err := cmd.Execute()
@@ -196,7 +177,7 @@ func TestDecryptCommand(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			var out bytes.Buffer
-			cmd := createTestRootCmd()
+			cmd := NewRootCommand(Options{})
 			cmd.SetOut(&out)
 			cmd.SetErr(&out)
 			cmd.SetArgs(tt.args)
@@ -253,7 +234,7 @@ func TestKeygenCommand(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			var out bytes.Buffer
-			cmd := createTestRootCmd()
+			cmd := NewRootCommand(Options{})
 			cmd.SetOut(&out)
 			cmd.SetErr(&out)
 			cmd.SetArgs(tt.args)
@@ -296,6 +277,12 @@ func TestPresetCommand(t *testing.T) {
 			wantErr:  false,
 			contains: "classic",
 		},
+		{
+			name:     "describe norway preset",
+			args:     []string{"preset", "--describe", "norway"},
+			wantErr:  false,
+			contains: "Norwegian police",
+		},
 		{
 			name:    "describe invalid preset",
 			args:    []string{"preset", "--describe", "invalid"},
@@ -317,7 +304,7 @@ func TestPresetCommand(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			var out bytes.Buffer
-			cmd := createTestRootCmd()
+			cmd := NewRootCommand(Options{})
 			cmd.SetOut(&out)
 			cmd.SetErr(&out)
 			cmd.SetArgs(tt.args)
@@ -360,17 +347,11 @@ func TestConfigCommand(t *testing.T) {
 		"current_rotor_positions": [0]
 	}`
 
-	tmpFile, err := os.CreateTemp("", "test-config-*.json")
-	if err != nil {
-		t.Fatalf("Failed to create temp config file: %v", err)
-	}
-	defer os.Remove(tmpFile.Name())
-
-	_, err = tmpFile.WriteString(testConfig)
-	if err != nil {
+	fsys := NewMemFS()
+	configFile := "test-config.json"
+	if err := fsys.WriteFile(configFile, []byte(testConfig), 0600); err != nil {
 		t.Fatalf("Failed to write test config: %v", err)
 	}
-	tmpFile.Close()
 
 	tests := []struct {
 		name     string
@@ -380,28 +361,34 @@ func TestConfigCommand(t *testing.T) {
 	}{
 		{
 			name:     "validate config",
-			args:     []string{"config", "--validate", tmpFile.Name()},
+			args:     []string{"config", "--validate", configFile},
 			wantErr:  false,
 			contains: "VALID",
 		},
 		{
 			name:     "show config",
-			args:     []string{"config", "--show", tmpFile.Name()},
+			args:     []string{"config", "--show", configFile},
 			wantErr:  false,
 			contains: "Configuration File",
 		},
 		{
 			name:     "show config detailed",
-			args:     []string{"config", "--show", tmpFile.Name(), "--detailed"},
+			args:     []string{"config", "--show", configFile, "--detailed"},
 			wantErr:  false,
 			contains: "Detailed Settings",
 		},
 		{
 			name:     "test config",
-			args:     []string{"config", "--test", tmpFile.Name()},
+			args:     []string{"config", "--test", configFile, "--text", "HELLOWORLD"},
 			wantErr:  false,
 			contains: "Round-trip",
 		},
+		{
+			name:     "test config with random text",
+			args:     []string{"config", "--test", configFile, "--length", "50"},
+			wantErr:  false,
+			contains: "Round-trip test PASSED",
+		},
 		{
 			name:    "validate nonexistent config",
 			args:    []string{"config", "--validate", "nonexistent.json"},
@@ -409,7 +396,7 @@ func TestConfigCommand(t *testing.T) {
 		},
 		{
 			name:    "convert without output",
-			args:    []string{"config", "--convert", tmpFile.Name()},
+			args:    []string{"config", "--convert", configFile},
 			wantErr: true,
 		},
 	}
@@ -417,7 +404,7 @@ func TestConfigCommand(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			var out bytes.Buffer
-			cmd := createTestRootCmd()
+			cmd := NewRootCommand(Options{FS: fsys})
 			cmd.SetOut(&out)
 			cmd.SetErr(&out)
 			cmd.SetArgs(tt.args)
@@ -441,36 +428,32 @@ func TestConfigCommand(t *testing.T) {
 
 // TestEncryptDecryptRoundTrip tests the full encryption/decryption workflow.
 func TestEncryptDecryptRoundTrip(t *testing.T) {
-	tempDir, err := os.MkdirTemp("", "enigma-test-")
-	if err != nil {
-		t.Fatalf("Failed to create temp dir: %v", err)
-	}
-	defer os.RemoveAll(tempDir)
+	fsys := NewMemFS()
 
 	originalText := "HELLOWORLDTESTMESSAGE"
 
 	// Step 1: Generate a key
-	keyFile := filepath.Join(tempDir, "test-key.json")
+	keyFile := "test-key.json"
 	var out bytes.Buffer
-	cmd := createTestRootCmd()
+	cmd := NewRootCommand(Options{FS: fsys})
 	cmd.SetOut(&out)
 	cmd.SetErr(&out)
 	cmd.SetArgs([]string{"keygen", "--preset", "classic", "--output", keyFile})
 
-	err = cmd.Execute()
+	err := cmd.Execute()
 	if err != nil {
 		t.Fatalf("Failed to generate key: %v", err)
 	}
 
 	// Verify key file was created
-	if _, err := os.Stat(keyFile); os.IsNotExist(err) {
+	if _, err := fsys.Stat(keyFile); os.IsNotExist(err) {
 		t.Fatalf("Key file was not created")
 	}
 
 	// Step 2: Encrypt with the generated key
-	encryptedFile := filepath.Join(tempDir, "encrypted.txt")
+	encryptedFile := "encrypted.txt"
 	out.Reset()
-	cmd = createTestRootCmd()
+	cmd = NewRootCommand(Options{FS: fsys})
 	cmd.SetOut(&out)
 	cmd.SetErr(&out)
 	cmd.SetArgs([]string{"encrypt", "--text", originalText, "--config", keyFile, "--output", encryptedFile})
@@ -481,7 +464,7 @@ func TestEncryptDecryptRoundTrip(t *testing.T) {
 	}
 
 	// Read encrypted content
-	encryptedData, err := os.ReadFile(encryptedFile)
+	encryptedData, err := fsys.ReadFile(encryptedFile)
 	if err != nil {
 		t.Fatalf("Failed to read encrypted file: %v", err)
 	}
@@ -493,9 +476,9 @@ func TestEncryptDecryptRoundTrip(t *testing.T) {
 	}
 
 	// Step 3: Decrypt with the same key
-	decryptedFile := filepath.Join(tempDir, "decrypted.txt")
+	decryptedFile := "decrypted.txt"
 	out.Reset()
-	cmd = createTestRootCmd()
+	cmd = NewRootCommand(Options{FS: fsys})
 	cmd.SetOut(&out)
 	cmd.SetErr(&out)
 	cmd.SetArgs([]string{"decrypt", "--text", encryptedText, "--config", keyFile, "--output", decryptedFile})
@@ -506,7 +489,7 @@ func TestEncryptDecryptRoundTrip(t *testing.T) {
 	}
 
 	// Read decrypted content
-	decryptedData, err := os.ReadFile(decryptedFile)
+	decryptedData, err := fsys.ReadFile(decryptedFile)
 	if err != nil {
 		t.Fatalf("Failed to read decrypted file: %v", err)
 	}
@@ -521,20 +504,15 @@ func TestEncryptDecryptRoundTrip(t *testing.T) {
 func TestEncryptDecryptHexBase64RoundTrip(t *testing.T) {
 	const original = "HELLOWORLD"
 
-	// Create temp dir for config files
-	tempDir, err := os.MkdirTemp("", "enigma-roundtrip-")
-	if err != nil {
-		t.Fatalf("Failed to create temp dir: %v", err)
-	}
-	defer os.RemoveAll(tempDir)
+	fsys := NewMemFS()
 
 	// HEX round-trip using saved config
 	{
-		cfg := filepath.Join(tempDir, "key-hex.json")
+		cfg := "key-hex.json"
 
 		// Encrypt and save config
 		var encryptOutput bytes.Buffer
-		cmd := createTestRootCmd()
+		cmd := NewRootCommand(Options{FS: fsys})
 		cmd.SetOut(&encryptOutput)
 		cmd.SetArgs([]string{"encrypt", "--text", original, "--preset", "classic", "--save-config", cfg, "--format", "hex"})
 		if err := cmd.Execute(); err != nil {
@@ -544,7 +522,7 @@ func TestEncryptDecryptHexBase64RoundTrip(t *testing.T) {
 
 		// Decrypt using the same saved config
 		var decryptOutput bytes.Buffer
-		cmd = createTestRootCmd()
+		cmd = NewRootCommand(Options{FS: fsys})
 		cmd.SetOut(&decryptOutput)
 		cmd.SetArgs([]string{"decrypt", "--text", encryptedHex, "--config", cfg, "--format", "hex"})
 		if err := cmd.Execute(); err != nil {
@@ -558,11 +536,11 @@ func TestEncryptDecryptHexBase64RoundTrip(t *testing.T) {
 
 	// BASE64 round-trip using saved config
 	{
-		cfg := filepath.Join(tempDir, "key-b64.json")
+		cfg := "key-b64.json"
 
 		// Encrypt and save config
 		var encryptOutput bytes.Buffer
-		cmd := createTestRootCmd()
+		cmd := NewRootCommand(Options{FS: fsys})
 		cmd.SetOut(&encryptOutput)
 		cmd.SetArgs([]string{"encrypt", "--text", original, "--preset", "classic", "--save-config", cfg, "--format", "base64"})
 		if err := cmd.Execute(); err != nil {
@@ -572,7 +550,7 @@ func TestEncryptDecryptHexBase64RoundTrip(t *testing.T) {
 
 		// Decrypt using the same saved config
 		var decryptOutput bytes.Buffer
-		cmd = createTestRootCmd()
+		cmd = NewRootCommand(Options{FS: fsys})
 		cmd.SetOut(&decryptOutput)
 		cmd.SetArgs([]string{"decrypt", "--text", encryptedB64, "--config", cfg, "--format", "base64"})
 		if err := cmd.Execute(); err != nil {
@@ -586,7 +564,8 @@ func TestEncryptDecryptHexBase64RoundTrip(t *testing.T) {
 }
 
 func TestSaveConfigFileContents(t *testing.T) {
-	cmd := createTestRootCmd()
+	fsys := NewMemFS()
+	cmd := NewRootCommand(Options{FS: fsys})
 	// Encrypt with a preset and --save-config so a config file is produced
 	encryptArgs := []string{"encrypt", "--text", "HELLOWORLD", "--preset", "classic", "--save-config", "test-config.json"}
 	cmd.SetArgs(encryptArgs)
@@ -595,7 +574,7 @@ func TestSaveConfigFileContents(t *testing.T) {
 	}
 
 	// Verify the config file contents
-	configData, err := os.ReadFile("test-config.json")
+	configData, err := fsys.ReadFile("test-config.json")
 	if err != nil {
 		t.Fatalf("Failed to read config file: %v", err)
 	}
@@ -605,16 +584,14 @@ func TestSaveConfigFileContents(t *testing.T) {
 		t.Fatalf("Failed to unmarshal config file: %v", err)
 	}
 
-	if settings.SchemaVersion != 1 {
-		t.Errorf("Expected schema version 1, got %d", settings.SchemaVersion)
+	if settings.SchemaVersion != enigma.CurrentSchemaVersion {
+		t.Errorf("Expected schema version %d, got %d", enigma.CurrentSchemaVersion, settings.SchemaVersion)
 	}
-
-	// Clean up
-	os.Remove("test-config.json")
 }
 
 func TestAutoConfigJSONOutput(t *testing.T) {
-	cmd := createTestRootCmd()
+	fsys := NewMemFS()
+	cmd := NewRootCommand(Options{FS: fsys})
 	// Encrypt with --auto-config providing the output path directly
 	encryptArgs := []string{"encrypt", "--text", "HELLOWORLD", "--auto-config", "auto-config.json"}
 	cmd.SetArgs(encryptArgs)
@@ -623,7 +600,7 @@ func TestAutoConfigJSONOutput(t *testing.T) {
 	}
 
 	// Verify the auto-config file contents
-	configData, err := os.ReadFile("auto-config.json")
+	configData, err := fsys.ReadFile("auto-config.json")
 	if err != nil {
 		t.Fatalf("Failed to read auto-config file: %v", err)
 	}
@@ -633,166 +610,620 @@ func TestAutoConfigJSONOutput(t *testing.T) {
 		t.Fatalf("Failed to unmarshal auto-config file: %v", err)
 	}
 
-	if settings.SchemaVersion != 1 {
-		t.Errorf("Expected schema version 1, got %d", settings.SchemaVersion)
+	if settings.SchemaVersion != enigma.CurrentSchemaVersion {
+		t.Errorf("Expected schema version %d, got %d", enigma.CurrentSchemaVersion, settings.SchemaVersion)
+	}
+}
+
+func TestAutoConfigAlphabetOrder(t *testing.T) {
+	fsys := NewMemFS()
+	cmd := NewRootCommand(Options{Out: &bytes.Buffer{}, FS: fsys})
+	cmd.SetArgs([]string{"encrypt", "--text", "CABBAGE", "--alphabet-order", "encountered", "--auto-config", "auto.json"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("encrypt with --alphabet-order failed: %v", err)
 	}
 
-	// Clean up
-	os.Remove("auto-config.json")
+	machine, err := createMachineFromConfig(fsys, configOptions{}, "auto.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings, _ := machine.GetSettings()
+	if got := string(settings.Alphabet); got != "CABGE " {
+		t.Errorf("alphabet = %q, want %q", got, "CABGE ")
+	}
+	if got := machine.GetMetadata().AlphabetOrdering; got != "encountered" {
+		t.Errorf("recorded ordering = %q, want encountered", got)
+	}
+
+	cmd = NewRootCommand(Options{Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, FS: fsys})
+	cmd.SetArgs([]string{"encrypt", "--text", "CABBAGE", "--alphabet-order", "alphabetical", "--auto-config", "bad.json"})
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected an error for an unknown ordering")
+	}
 }
 
-// createTestRootCmd creates a fresh root command for testing.
-func createTestRootCmd() *cobra.Command {
-	// Create a new root command to avoid state pollution between tests
-	testRootCmd := &cobra.Command{
-		Use:     "enigoma",
-		Short:   "A highly customizable, Unicode-capable Enigma machine implementation",
-		Version: "0.2.1",
-	}
-
-	// Create fresh command instances to avoid state pollution
-	freshEncryptCmd := createFreshEncryptCmd()
-	freshDecryptCmd := createFreshDecryptCmd()
-	freshKeygenCmd := createFreshKeygenCmd()
-	freshPresetCmd := createFreshPresetCmd()
-	freshConfigCmd := createFreshConfigCmd()
-
-	// Add subcommands
-	testRootCmd.AddCommand(freshEncryptCmd)
-	testRootCmd.AddCommand(freshDecryptCmd)
-	testRootCmd.AddCommand(freshKeygenCmd)
-	testRootCmd.AddCommand(freshPresetCmd)
-	testRootCmd.AddCommand(freshConfigCmd)
-
-	// Global flags
-	testRootCmd.PersistentFlags().BoolP("verbose", "v", false, "Enable verbose output")
-	testRootCmd.PersistentFlags().StringP("config", "c", "", "Configuration file path")
-
-	return testRootCmd
+// TestAutoConfigPaddingStrategy tests the --padding-strategy and --padding-chars flags.
+func TestAutoConfigPaddingStrategy(t *testing.T) {
+	fsys := NewMemFS()
+	if _, err := runCLI(fsys, "encrypt", "--text", "Hello, hello", "--padding-strategy", "merge", "--auto-config", "merged.json", "--output", "merged.txt"); err != nil {
+		t.Fatalf("encrypt with --padding-strategy merge failed: %v", err)
+	}
+	plaintext, err := runCLI(fsys, "decrypt", "--file", "merged.txt", "--config", "merged.json")
+	if err != nil {
+		t.Fatalf("decrypt failed: %v", err)
+	}
+	if plaintext != "Hello, Hello" {
+		t.Errorf("decrypted = %q, want the merged %q", plaintext, "Hello, Hello")
+	}
+
+	if _, err := runCLI(fsys, "encrypt", "--text", "ABC", "--padding-chars", "_", "--auto-config", "listed.json"); err != nil {
+		t.Fatalf("encrypt with --padding-chars failed: %v", err)
+	}
+	machine, err := createMachineFromConfig(fsys, configOptions{}, "listed.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings, _ := machine.GetSettings()
+	if got := string(settings.Alphabet); got != "ABC_" {
+		t.Errorf("alphabet = %q, want %q", got, "ABC_")
+	}
+
+	if _, err := runCLI(fsys, "encrypt", "--text", "AAABBC", "--alphabet", "auto", "--padding-strategy", "drop"); err != nil {
+		t.Errorf("encrypt --alphabet auto with --padding-strategy drop failed: %v", err)
+	}
+	if _, err := runCLI(fsys, "encrypt", "--text", "ABC", "--padding-strategy", "list", "--auto-config", "bad.json"); err == nil {
+		t.Error("expected an error for the list strategy without --padding-chars")
+	}
+	if _, err := runCLI(fsys, "encrypt", "--text", "ABC", "--padding-strategy", "shuffle", "--auto-config", "bad.json"); err == nil {
+		t.Error("expected an error for an unknown padding strategy")
+	}
 }
 
-// Helper functions to create fresh command instances
-func createFreshEncryptCmd() *cobra.Command {
-	cmd := &cobra.Command{
-		Use:   "encrypt",
-		Short: "Encrypt text or files using an Enigma machine",
-		RunE:  runEncrypt,
+// TestKeygenNoReflector tests generating and using an experimental reflector-less key.
+func TestKeygenNoReflector(t *testing.T) {
+	fsys := NewMemFS()
+	var out bytes.Buffer
+	cmd := NewRootCommand(Options{FS: fsys})
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"keygen", "--security", "low", "--no-reflector", "--describe", "--output", "straight.json"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("keygen --no-reflector failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "Reflector: none") {
+		t.Errorf("describe output should flag the missing reflector: %s", out.String())
 	}
 
-	// Input options
-	cmd.Flags().StringP("text", "t", "", "Text to encrypt")
-	cmd.Flags().StringP("file", "f", "", "File to encrypt")
-	cmd.Flags().StringP("output", "o", "", "Output file (default: stdout)")
+	data, _ := fsys.ReadFile("straight.json")
+	if !strings.Contains(string(data), `"reflectorless": true`) {
+		t.Errorf("saved key should be marked reflector-less:\n%s", data)
+	}
 
-	// Machine configuration
-	cmd.Flags().StringP("preset", "p", "", "Use a preset configuration (classic, simple, high, extreme)")
-	cmd.Flags().StringP("alphabet", "a", "auto", "Alphabet to use (auto, latin, greek, cyrillic, portuguese, ascii, alphanumeric)")
-	cmd.Flags().StringP("security", "s", "medium", "Security level (low, medium, high, extreme)")
+	out.Reset()
+	cmd = NewRootCommand(Options{FS: fsys})
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"encrypt", "--text", "STRAIGHTTHROUGH", "--config", "straight.json"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	ciphertext := strings.TrimSuffix(out.String(), "\n")
 
-	// Advanced options
-	cmd.Flags().StringSliceP("rotors", "r", nil, "Rotor positions (e.g., 1,5,12)")
-	cmd.Flags().StringSliceP("plugboard", "", nil, "Plugboard pairs (e.g., A:Z,B:Y)")
-	cmd.Flags().BoolP("reset", "", false, "Reset machine to initial state before encryption")
+	out.Reset()
+	cmd = NewRootCommand(Options{FS: fsys})
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"decrypt", "--text", ciphertext, "--config", "straight.json"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("decrypt failed: %v", err)
+	}
+	if got := strings.TrimSuffix(out.String(), "\n"); got != "STRAIGHTTHROUGH" {
+		t.Errorf("round trip failed: got %q", got)
+	}
 
-	// Configuration workflow
-	cmd.Flags().String("auto-config", "", "Auto-detect alphabet from input and save configuration to file")
-	cmd.Flags().String("save-config", "", "Save generated configuration to file (used with --preset or manual settings)")
+	cmd = NewRootCommand(Options{FS: fsys})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"keygen", "--preset", "classic", "--no-reflector"})
+	if err := cmd.Execute(); err == nil {
+		t.Error("--no-reflector with --preset should fail")
+	}
+}
 
-	// Output formatting
-	cmd.Flags().StringP("format", "", "text", "Output format (text, hex, base64)")
-	cmd.Flags().BoolP("preserve-case", "", false, "Preserve original case (when possible)")
+// TestNoReflectorOddAlphabet tests a reflector-less round trip on the 95
+// printable ASCII characters, which no reflector can pair.
+func TestNoReflectorOddAlphabet(t *testing.T) {
+	fsys := NewMemFS()
+	if _, err := runCLI(fsys, "keygen", "--alphabet", "ascii", "--security", "low", "--output", "reflector.json"); err == nil {
+		t.Error("an odd alphabet should need --no-reflector")
+	}
+	if _, err := runCLI(fsys, "keygen", "--alphabet", "ascii", "--security", "medium", "--no-reflector", "--output", "odd.json"); err != nil {
+		t.Fatalf("keygen --no-reflector failed: %v", err)
+	}
+	machine, err := createMachineFromConfig(fsys, configOptions{}, "odd.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := machine.GetAlphabetSize(); size != 95 || !machine.IsReflectorless() {
+		t.Fatalf("key has %d characters, reflector-less %v; want 95, true", size, machine.IsReflectorless())
+	}
 
-	return cmd
+	const text = "Meet at 10:45, gate #3 (north) ~ bring $20!"
+	ciphertext, err := runCLI(fsys, "encrypt", "--text", text, "--config", "odd.json")
+	if err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	ciphertext = strings.TrimSuffix(ciphertext, "\n")
+	if ciphertext == text {
+		t.Fatal("ciphertext should differ from the text")
+	}
+	plaintext, err := runCLI(fsys, "decrypt", "--text", ciphertext, "--config", "odd.json")
+	if err != nil {
+		t.Fatalf("decrypt failed: %v", err)
+	}
+	if got := strings.TrimSuffix(plaintext, "\n"); got != text {
+		t.Errorf("round trip = %q, want %q", got, text)
+	}
 }
 
-func createFreshDecryptCmd() *cobra.Command {
-	cmd := &cobra.Command{
-		Use:   "decrypt",
-		Short: "Decrypt text or files using an Enigma machine",
-		RunE:  runDecrypt,
+// TestKeygenSeedReproducesKey tests that --seed derives the whole machine.
+func TestKeygenSeedReproducesKey(t *testing.T) {
+	fsys := NewMemFS()
+	fingerprint := func(args ...string) string {
+		t.Helper()
+		if _, err := runCLI(fsys, append([]string{"keygen", "--output", "seeded.json"}, args...)...); err != nil {
+			t.Fatalf("keygen %v failed: %v", args, err)
+		}
+		machine, err := createMachineFromConfig(fsys, configOptions{}, "seeded.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		fp, err := machine.Fingerprint()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return fp
 	}
 
-	// Input options
-	cmd.Flags().StringP("text", "t", "", "Text to decrypt")
-	cmd.Flags().StringP("file", "f", "", "File to decrypt")
-	cmd.Flags().StringP("output", "o", "", "Output file (default: stdout)")
+	first := fingerprint("--security", "high", "--seed", "42")
+	if again := fingerprint("--security", "high", "--seed", "42"); again != first {
+		t.Errorf("keygen --seed 42 should reproduce the key: %s vs %s", first, again)
+	}
+	if other := fingerprint("--security", "high", "--seed", "43"); other == first {
+		t.Error("another seed should give another key")
+	}
+	if _, err := runCLI(fsys, "keygen", "--seed", "42", "--plugboard-random", "3"); err == nil {
+		t.Error("--plugboard-random with --seed should fail")
+	}
+}
 
-	// Machine configuration
-	cmd.Flags().StringP("preset", "p", "", "Use a preset configuration (classic, simple, high, extreme)")
-	cmd.Flags().StringP("alphabet", "a", "auto", "Alphabet to use (auto, latin, greek, cyrillic, portuguese, ascii, alphanumeric)")
-	cmd.Flags().StringP("security", "s", "medium", "Security level (low, medium, high, extreme)")
+// TestConfigStats tests the keyspace report of a configuration file.
+func TestConfigStats(t *testing.T) {
+	fsys := NewMemFS()
+	if _, err := runCLI(fsys, "keygen", "--preset", "m3", "--plugboard", "A:B,C:D,E:F,G:H,I:J,K:L,M:N,O:P,Q:R,S:T", "--output", "m3.json"); err != nil {
+		t.Fatalf("keygen failed: %v", err)
+	}
+	out, err := runCLI(fsys, "config", "--stats", "m3.json")
+	if err != nil {
+		t.Fatalf("config --stats failed: %v", err)
+	}
+	for _, want := range []string{
+		"Rotor Wirings (3 rotors): 6.559e+79",
+		"Rotor Position Combinations: 17576",
+		"Reflector Wirings: 7905853580625",
+		"Plugboard Combinations (10 pairs): 150738274937250",
+		"bits)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("config --stats output missing %q:\n%s", want, out)
+		}
+	}
+	if _, err := runCLI(fsys, "config", "--stats", "missing.json"); err == nil {
+		t.Error("config --stats on a missing file should fail")
+	}
+}
 
-	// Advanced options
-	cmd.Flags().StringSliceP("rotors", "r", nil, "Rotor positions (e.g., 1,5,12)")
-	cmd.Flags().StringSliceP("plugboard", "", nil, "Plugboard pairs (e.g., A:Z,B:Y)")
-	cmd.Flags().BoolP("reset", "", false, "Reset machine to initial state before decryption")
+// TestKeygenStatsExtreme tests --stats where int64 arithmetic overflowed.
+func TestKeygenStatsExtreme(t *testing.T) {
+	out, err := runCLI(NewMemFS(), "keygen", "--security", "extreme", "--alphabet", "ascii", "--no-reflector", "--stats", "--output", "extreme.json")
+	if err != nil {
+		t.Fatalf("keygen --stats failed: %v", err)
+	}
+	for _, want := range []string{
+		"Rotor Wirings (12 rotors): 1.476e+1776 (5900.3 bits)",
+		"Rotor Position Combinations: 5.404e+23 (78.8 bits)",
+		"Reflector Wirings: none",
+		"Plugboard Combinations (20 pairs): 3.189e+50 (167.8 bits)",
+		"Total Keyspace: 2.544e+1850 (6146.9 bits)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("keygen --stats output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Inf") {
+		t.Errorf("keygen --stats overflowed:\n%s", out)
+	}
+}
+
+func TestFormatCount(t *testing.T) {
+	tests := []struct {
+		count *big.Int
+		want  string
+	}{
+		{big.NewInt(1), "1 (0.0 bits)"},
+		{big.NewInt(17576), "17576 (14.1 bits)"},
+		{big.NewInt(999999999999999), "999999999999999 (49.8 bits)"},
+		{big.NewInt(1000000000000000), "1.000e+15 (49.8 bits)"},
+		{new(big.Int).Exp(big.NewInt(10), big.NewInt(5000), nil), "1.000e+5000 (16609.6 bits)"},
+	}
+	for _, tt := range tests {
+		if got := formatCount(tt.count); got != tt.want {
+			t.Errorf("formatCount(%s) = %q, want %q", tt.count, got, tt.want)
+		}
+	}
+}
 
-	// Input format
-	cmd.Flags().StringP("format", "", "text", "Input format (text, hex, base64)")
+// TestKeygenReflectorFixedPoints tests a reflector that leaves characters in place.
+func TestKeygenReflectorFixedPoints(t *testing.T) {
+	fsys := NewMemFS()
+	if err := fsys.WriteFile("odd.txt", []byte("ABCDEFG"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	out, err := runCLI(fsys, "keygen", "--alphabet-file", "odd.txt", "--reflector-fixed-points", "1", "--security", "low", "--describe", "--output", "fixed.json")
+	if err != nil {
+		t.Fatalf("keygen --reflector-fixed-points failed: %v", err)
+	}
+	if !strings.Contains(out, "Reflector Fixed Points: 1") {
+		t.Errorf("describe output should show the fixed points: %s", out)
+	}
+	machine, err := createMachineFromConfig(fsys, configOptions{}, "fixed.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := machine.ReflectorFixedPoints(); n != 1 {
+		t.Errorf("saved key leaves %d characters in place, want 1", n)
+	}
 
-	return cmd
+	for _, args := range [][]string{
+		{"keygen", "--reflector-fixed-points", "1", "--preset", "classic"},
+		{"keygen", "--reflector-fixed-points", "1", "--no-reflector"},
+		{"keygen", "--reflector-fixed-points", "1", "--reflector-pairs", "AB CD"},
+		{"keygen", "--reflector-fixed-points", "2", "--alphabet-file", "odd.txt", "--security", "low"},
+	} {
+		if _, err := runCLI(fsys, args...); err == nil {
+			t.Errorf("%v should fail", args)
+		}
+	}
 }
 
-func createFreshKeygenCmd() *cobra.Command {
-	cmd := &cobra.Command{
-		Use:   "keygen",
-		Short: "Generate random Enigma machine configurations",
-		RunE:  runKeygen,
+// TestKeygenNoPlugboard tests generating a key without a plugboard stage.
+func TestKeygenNoPlugboard(t *testing.T) {
+	fsys := NewMemFS()
+	out, err := runCLI(fsys, "keygen", "--security", "high", "--no-plugboard", "--describe", "--output", "bare.json")
+	if err != nil {
+		t.Fatalf("keygen --no-plugboard failed: %v", err)
+	}
+	if !strings.Contains(out, "Plugboard: none") {
+		t.Errorf("describe output should flag the missing plugboard: %s", out)
+	}
+	data, _ := fsys.ReadFile("bare.json")
+	if !strings.Contains(string(data), `"plugboard_disabled": true`) {
+		t.Errorf("saved key should have the plugboard disabled:\n%s", data)
+	}
+	out, err = runCLI(fsys, "config", "--show", "bare.json")
+	if err != nil || !strings.Contains(out, "Plugboard: none") || !strings.Contains(out, "no plugboard") {
+		t.Errorf("config --show should report no plugboard:\n%s", out)
 	}
 
-	// Machine configuration
-	cmd.Flags().StringP("preset", "p", "", "Base preset to modify (classic, simple, low, medium, high, extreme)")
-	cmd.Flags().StringP("alphabet", "a", "latin", "Alphabet to use (latin, greek, cyrillic, portuguese, ascii, alphanumeric)")
-	cmd.Flags().StringP("security", "s", "medium", "Security level (low, medium, high, extreme)")
+	for _, args := range [][]string{
+		{"--preset", "m3"},
+		{"--plugboard", "A:B"},
+		{"--plugboard-pairs", "3"},
+	} {
+		if _, err := runCLI(fsys, append([]string{"keygen", "--no-plugboard"}, args...)...); err == nil {
+			t.Errorf("--no-plugboard with %v should fail", args)
+		}
+	}
+}
 
-	// Output options
-	cmd.Flags().StringP("output", "o", "", "Output file for the configuration (default: stdout)")
-	cmd.Flags().StringP("save-to", "", "", "Save configuration to file (alias for --output)")
-	cmd.Flags().StringP("format", "f", "json", "Output format (json, yaml)")
+// TestConfigShowProvenance tests that config --show lists component provenance.
+func TestConfigShowProvenance(t *testing.T) {
+	fsys := NewMemFS()
+	show := func(preset string) string {
+		t.Helper()
+		var out bytes.Buffer
+		cmd := NewRootCommand(Options{Out: &out, Err: &bytes.Buffer{}, FS: fsys})
+		cmd.SetArgs([]string{"keygen", "--preset", preset, "--output", preset + ".json"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("keygen failed: %v", err)
+		}
+		out.Reset()
+		cmd = NewRootCommand(Options{Out: &out, Err: &bytes.Buffer{}, FS: fsys})
+		cmd.SetArgs([]string{"config", "--show", preset + ".json"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("config --show failed: %v", err)
+		}
+		return out.String()
+	}
 
-	// Advanced options
-	cmd.Flags().IntP("rotors", "r", 0, "Number of rotors (overrides security level)")
-	cmd.Flags().IntP("plugboard-pairs", "", 0, "Number of plugboard pairs (overrides security level)")
-	cmd.Flags().BoolP("random-positions", "", true, "Generate random rotor positions")
-	cmd.Flags().Int64("seed", 0, "Deterministic seed for rotor positions (optional)")
+	out := show("m4")
+	for _, want := range []string{"Provenance:", "Rotor 1 (Beta): historical:M4/Beta", "Reflector (B-Thin): historical:M4/B-Thin"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+	if out := show("classic"); strings.Contains(out, "Provenance") {
+		t.Errorf("random components have no provenance:\n%s", out)
+	}
+}
 
-	// Information options
-	cmd.Flags().BoolP("describe", "d", false, "Show description of generated configuration")
-	cmd.Flags().BoolP("stats", "", false, "Show statistics about the configuration")
+// TestExamplesRotorEvents tests the live rotor event consumer example.
+func TestExamplesRotorEvents(t *testing.T) {
+	var out bytes.Buffer
+	cmd := NewRootCommand(Options{})
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"examples", "--rotor-events"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("examples --rotor-events failed: %v", err)
+	}
+	for _, want := range []string{"rotor III turnover", "rotor II  double-step", "key 1  E -> "} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
 
-	return cmd
+// TestExamplesRun tests that every runnable example works end to end.
+func TestExamplesRun(t *testing.T) {
+	var out bytes.Buffer
+	cmd := NewRootCommand(Options{Out: &out, Err: &bytes.Buffer{}, FS: NewMemFS()})
+	cmd.SetArgs([]string{"examples", "--run", "all"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("examples --run all failed: %v\n%s", err, out.String())
+	}
+	if got := strings.Count(out.String(), "Verified: decrypted.txt"); got != len(runnableExamples) {
+		t.Errorf("expected %d verified examples, got %d:\n%s", len(runnableExamples), got, out.String())
+	}
+	for _, want := range []string{"$ enigoma encrypt --text \"Hello World!\" --auto-config my-key.json --output message.txt", "decrypted.txt: Hello! Привет! 日本語! 🌟"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	cmd = NewRootCommand(Options{Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, FS: NewMemFS()})
+	cmd.SetArgs([]string{"examples", "--run", "nope"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "unknown example") {
+		t.Errorf("expected an unknown example error, got %v", err)
+	}
+}
+
+// TestRunExampleDetectsWrongResult tests that a broken tutorial fails instead of printing.
+func TestRunExampleDetectsWrongResult(t *testing.T) {
+	fsys := NewMemFS()
+	cmd := NewRootCommand(Options{Out: &bytes.Buffer{}, FS: fsys})
+	broken := runnableExample{
+		name: "broken",
+		steps: []exampleStep{
+			{"Encrypt", []string{"encrypt", "--text", "HELLO", "--auto-config", "@key.json", "--output", "@message.txt"}, ""},
+		},
+		expect: map[string]string{"message.txt": "HELLO"},
+	}
+	if err := runExample(cmd, fsys, ".", broken); err == nil || !strings.Contains(err.Error(), "message.txt holds") {
+		t.Errorf("expected a verification failure, got %v", err)
+	}
+
+	broken.steps[0].args = []string{"decrypt", "--file", "@missing.txt", "--config", "@key.json"}
+	if err := runExample(cmd, fsys, ".", broken); err == nil {
+		t.Error("a failing step should fail the example")
+	}
 }
 
-func createFreshPresetCmd() *cobra.Command {
-	cmd := &cobra.Command{
-		Use:   "preset",
-		Short: "List and describe available Enigma machine presets",
-		RunE:  runPreset,
+// TestKeygenReflectorPairs tests explicit reflector wiring from the command line.
+func TestKeygenReflectorPairs(t *testing.T) {
+	fsys := NewMemFS()
+	cmd := NewRootCommand(Options{FS: fsys})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"keygen", "--security", "low", "--output", "wired.json",
+		"--reflector-pairs", "AY BR CU DH EQ FS GL IP JX KN MO TZ VW"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("keygen --reflector-pairs failed: %v", err)
 	}
 
-	cmd.Flags().BoolP("list", "l", false, "List all available presets")
-	cmd.Flags().StringP("describe", "d", "", "Describe a specific preset (or 'all' for all presets)")
-	cmd.Flags().StringP("export", "e", "", "Export preset configuration to file")
-	cmd.Flags().StringP("output", "o", "", "Output file for exported configuration")
-	cmd.Flags().BoolP("verbose", "v", false, "Show detailed information")
+	machine, err := createMachineFromConfig(fsys, configOptions{}, "wired.json")
+	if err != nil {
+		t.Fatalf("loading wired key failed: %v", err)
+	}
+	settings, _ := machine.GetSettings()
+	// Historical reflector B, written as pairs
+	if settings.ReflectorSpec.Mapping != "YRUHQSLDPXNGOKMIEBFZCWVJAT" {
+		t.Errorf("unexpected reflector mapping %q", settings.ReflectorSpec.Mapping)
+	}
 
-	return cmd
+	for _, tc := range []struct {
+		name string
+		args []string
+	}{
+		{"incomplete", []string{"--reflector-pairs", "AY BR"}},
+		{"odd token", []string{"--reflector-pairs", "AYB"}},
+		{"repeated character", []string{"--reflector-pairs", "AY AB"}},
+		{"no reflector", []string{"--reflector-pairs", "AY", "--no-reflector"}},
+	} {
+		cmd := NewRootCommand(Options{FS: fsys})
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs(append([]string{"keygen", "--security", "low"}, tc.args...))
+		if err := cmd.Execute(); err == nil {
+			t.Errorf("%s: expected an error", tc.name)
+		}
+	}
 }
 
-func createFreshConfigCmd() *cobra.Command {
-	cmd := &cobra.Command{
-		Use:   "config",
-		Short: "Manage Enigma machine configuration files",
-		RunE:  runConfig,
+func TestVerboseKeyFingerprint(t *testing.T) {
+	fsys := NewMemFS()
+	cmd := NewRootCommand(Options{FS: fsys})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"keygen", "--alphabet", "latin", "--security", "low", "--output", "key.json"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("keygen failed: %v", err)
+	}
+	fingerprint, err := fingerprintConfigFile(fsys, configOptions{}, "key.json")
+	if err != nil {
+		t.Fatalf("fingerprint failed: %v", err)
 	}
+	short := shortFingerprint(fingerprint)
+
+	for _, op := range []string{"encrypt", "decrypt"} {
+		var stderr bytes.Buffer
+		cmd := NewRootCommand(Options{FS: fsys})
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&stderr)
+		cmd.SetArgs([]string{op, "--text", "HELLO", "--config", "key.json", "--verbose"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("%s failed: %v", op, err)
+		}
+		want := "Key fingerprint " + short + ", alphabet of 26 characters, 3 rotors"
+		if !strings.Contains(stderr.String(), want) {
+			t.Errorf("%s: expected %q in verbose output, got %q", op, want, stderr.String())
+		}
 
-	cmd.Flags().StringP("validate", "", "", "Validate a configuration file")
-	cmd.Flags().StringP("show", "s", "", "Show configuration details")
-	cmd.Flags().StringP("test", "t", "", "Test configuration with sample text")
-	cmd.Flags().StringP("text", "", "HELLOWORLD", "Text to use for testing")
-	cmd.Flags().StringP("convert", "", "", "Convert/update configuration format")
-	cmd.Flags().StringP("output", "o", "", "Output file for converted configuration")
-	cmd.Flags().BoolP("detailed", "d", false, "Show detailed information")
+		cmd = NewRootCommand(Options{FS: fsys})
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{op, "--text", "hello", "--config", "key.json"})
+		err := cmd.Execute()
+		if err == nil || !strings.Contains(err.Error(), "failed with key "+short) {
+			t.Errorf("%s: expected the key fingerprint in the error, got %v", op, err)
+		}
+	}
+}
+
+// TestRingSettingsFlag tests --ring-settings on encrypt, decrypt and keygen.
+func TestRingSettingsFlag(t *testing.T) {
+	fsys := NewMemFS()
+	out, err := runCLI(fsys, "encrypt", "--preset", "m3", "--ring-settings", "2,2,2", "--text", "AAAAA")
+	if err != nil {
+		t.Fatalf("encrypt with --ring-settings failed: %v", err)
+	}
+	if out != "EWTYX" {
+		t.Errorf("encrypt = %q, want the historical EWTYX", out)
+	}
+	out, err = runCLI(fsys, "decrypt", "--preset", "m3", "--ring-settings", "B,B,B", "--text", "EWTYX")
+	if err != nil || out != "AAAAA" {
+		t.Errorf("decrypt = %q, %v; want AAAAA", out, err)
+	}
+
+	if _, err := runCLI(fsys, "keygen", "--preset", "m3", "--random-positions=false", "--notation", "letters", "--ring-settings", "AET", "--output", "rings.json"); err != nil {
+		t.Fatalf("keygen with --ring-settings failed: %v", err)
+	}
+	machine, err := createMachineFromConfig(fsys, configOptions{}, "rings.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := machine.GetRingSettings(); len(got) != 3 || got[0] != 0 || got[1] != 4 || got[2] != 19 {
+		t.Errorf("saved ring settings = %v, want [0 4 19]", got)
+	}
+
+	if _, err := runCLI(fsys, "encrypt", "--text", "HELLO", "--ring-settings", "1,2,3,4,5,6,7,8", "--auto-config", "auto.json"); err == nil {
+		t.Error("expected an error for the wrong number of ring settings")
+	}
+	if _, err := runCLI(fsys, "encrypt", "--preset", "m3", "--ring-settings", "1,27,3", "--text", "HELLO"); err == nil {
+		t.Error("expected an error for a ring setting outside 1-26")
+	}
+}
+
+// TestPresetEnigmaG tests the Abwehr Enigma G preset end to end.
+func TestPresetEnigmaG(t *testing.T) {
+	fsys := NewMemFS()
+	if _, err := runCLI(fsys, "keygen", "--preset", "g", "--output", "g.json"); err != nil {
+		t.Fatalf("keygen failed: %v", err)
+	}
+	ciphertext, err := runCLI(fsys, "encrypt", "--config", "g.json", "--text", "ABWEHRNACHRICHT")
+	if err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	plaintext, err := runCLI(fsys, "decrypt", "--config", "g.json", "--text", strings.TrimSpace(ciphertext))
+	if err != nil || strings.TrimSpace(plaintext) != "ABWEHRNACHRICHT" {
+		t.Errorf("decrypt = %q, %v; want ABWEHRNACHRICHT", plaintext, err)
+	}
+
+	out, err := runCLI(fsys, "config", "--show", "g.json", "--detailed")
+	if err != nil {
+		t.Fatalf("config --show failed: %v", err)
+	}
+	for _, want := range []string{"Reflector: ID=UKW, rotating", "Entry Wheel: QWERTZUIOASDFGHJKPYXCVBNML", "Stepping: gear", "historical:G-312/I"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+}
+
+// TestPresetEnigmaKFamily tests the Enigma K, Swiss K, Railway and Tirpitz presets.
+func TestPresetEnigmaKFamily(t *testing.T) {
+	fsys := NewMemFS()
+	for _, preset := range []string{"k", "swiss-k", "railway", "tirpitz"} {
+		key := preset + ".json"
+		if _, err := runCLI(fsys, "keygen", "--preset", preset, "--output", key); err != nil {
+			t.Fatalf("keygen --preset %s failed: %v", preset, err)
+		}
+		ciphertext, err := runCLI(fsys, "encrypt", "--config", key, "--text", "FAHRPLANAENDERUNG")
+		if err != nil {
+			t.Fatalf("encrypt failed: %v", err)
+		}
+		plaintext, err := runCLI(fsys, "decrypt", "--config", key, "--text", strings.TrimSpace(ciphertext))
+		if err != nil || strings.TrimSpace(plaintext) != "FAHRPLANAENDERUNG" {
+			t.Errorf("%s: decrypt = %q, %v", preset, plaintext, err)
+		}
+		out, err := runCLI(fsys, "config", "--show", key, "--detailed")
+		if err != nil || !strings.Contains(out, "Reflector: ID=UKW, settable, Position=0") {
+			t.Errorf("%s: expected a settable reflector in:\n%s", preset, out)
+		}
+		if !strings.Contains(out, "Features: lever stepping, settable reflector, entry wheel\n") {
+			t.Errorf("%s: unexpected features in:\n%s", preset, out)
+		}
+	}
+}
+
+// TestKeygenPlugboardRandom tests pinned plugboard pairs with random ones around them.
+func TestKeygenPlugboardRandom(t *testing.T) {
+	fsys := NewMemFS()
+	if _, err := runCLI(fsys, "keygen", "--preset", "m3", "--plugboard", "A:Z", "--plugboard-random", "9", "--uhr", "3", "--output", "demo.json"); err != nil {
+		t.Fatalf("keygen --plugboard-random failed: %v", err)
+	}
+	machine, err := createMachineFromConfig(fsys, configOptions{}, "demo.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings, _ := machine.GetSettings()
+	if machine.GetPlugboardPairCount() != 10 || settings.PlugboardPairs['A'] != 'Z' || !machine.HasUhr() {
+		t.Errorf("expected A:Z among 10 pairs on an Uhr, got %d pairs %v", machine.GetPlugboardPairCount(), settings.PlugboardPairs)
+	}
 
-	return cmd
+	if _, err := runCLI(fsys, "keygen", "--preset", "m3", "--plugboard", "A:Z", "--plugboard-random", "13"); err == nil {
+		t.Error("expected an error when the random pairs do not fit")
+	}
+	if _, err := runCLI(fsys, "keygen", "--plugboard-pairs", "5", "--plugboard-random", "2"); err == nil {
+		t.Error("expected --plugboard-random with --plugboard-pairs to fail")
+	}
+}
+
+// TestKeygenUhr tests keys that route the plugboard through the Uhr.
+func TestKeygenUhr(t *testing.T) {
+	fsys := NewMemFS()
+	pairs := "A:D,C:N,E:T,F:L,G:I,J:V,K:Z,P:U,Q:Y,W:X"
+	if _, err := runCLI(fsys, "keygen", "--preset", "m3", "--plugboard", pairs, "--uhr", "27", "--output", "uhr.json"); err != nil {
+		t.Fatalf("keygen --uhr failed: %v", err)
+	}
+	ciphertext, err := runCLI(fsys, "encrypt", "--config", "uhr.json", "--text", "UHRSTELLUNG")
+	if err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	plaintext, err := runCLI(fsys, "decrypt", "--config", "uhr.json", "--text", strings.TrimSpace(ciphertext))
+	if err != nil || strings.TrimSpace(plaintext) != "UHRSTELLUNG" {
+		t.Errorf("decrypt = %q, %v", plaintext, err)
+	}
+	out, err := runCLI(fsys, "config", "--show", "uhr.json", "--detailed")
+	if err != nil || !strings.Contains(out, "Uhr: Position=27") || !strings.Contains(out, "Features: lever stepping, fixed reflector, Uhr\n") {
+		t.Errorf("expected the Uhr in:\n%s", out)
+	}
+
+	if _, err := runCLI(fsys, "keygen", "--preset", "m3", "--plugboard", "A:B", "--uhr", "0"); err == nil || !strings.Contains(err.Error(), "exactly 10") {
+		t.Errorf("expected an error for one plugboard pair, got %v", err)
+	}
 }
@@ -7,17 +7,37 @@ import (
 	"io"
 	"os"
 	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
 )
 
+// now is the clock used for timestamps and expiry checks (replaced in tests).
+var now = time.Now
+
+// readPipedInput reads all of the command's input stream. When the stream is
+// the process's terminal nothing is read, so interactive runs don't block.
+func readPipedInput(cmd *cobra.Command) (string, error) {
+	in := cmd.InOrStdin()
+	if isTerminalInput(in) {
+		return "", nil
+	}
+
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return "", fmt.Errorf("failed to read stdin: %w", err)
+	}
+	return string(data), nil
+}
+
 // GetInputText reads input text from a file or stdin.
 func GetInputText(filePath string) (string, error) {
 	if filePath == "-" {
 		// Read from stdin
-		info, err := os.Stdin.Stat()
-		if err != nil {
+		if _, err := os.Stdin.Stat(); err != nil {
 			return "", fmt.Errorf("failed to stat stdin: %w", err)
 		}
-		if (info.Mode() & os.ModeCharDevice) != 0 {
+		if isTerminalInput(os.Stdin) {
 			return "", fmt.Errorf("stdin is not a pipe")
 		}
 		input, err := io.ReadAll(os.Stdin)
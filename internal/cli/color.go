@@ -0,0 +1,159 @@
+// Package cli provides color and emoji handling for human-oriented output.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+)
+
+// ANSI styles applied by paint.
+const (
+	colorReset  = "\x1b[0m"
+	colorRed    = "\x1b[31m"
+	colorGreen  = "\x1b[32m"
+	colorYellow = "\x1b[33m"
+	colorBold   = "\x1b[1m"
+)
+
+// colorModes are the values accepted by --color.
+var colorModes = []string{"auto", "always", "never"}
+
+// validateColorMode rejects unknown --color values before a command runs.
+func validateColorMode(cmd *cobra.Command) error {
+	mode, err := cmd.Flags().GetString("color")
+	if err != nil {
+		return nil
+	}
+	for _, m := range colorModes {
+		if strings.EqualFold(mode, m) {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown --color: %s. Available: %s", mode, strings.Join(colorModes, ", "))
+}
+
+// styleEnabled reports whether colors and emoji should be written to w.
+// --color always and never are absolute; in auto mode (the default) styling
+// is used only when w is a terminal that understands ANSI sequences, NO_COLOR
+// is unset or empty and TERM is not "dumb". See https://no-color.org.
+func styleEnabled(cmd *cobra.Command, w io.Writer) bool {
+	mode, _ := cmd.Flags().GetString("color")
+	switch strings.ToLower(mode) {
+	case "always":
+		if isTerminal(w) {
+			enableTerminalStyle(w.(*os.File))
+		}
+		return true
+	case "never":
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return isTerminal(w) && enableTerminalStyle(w.(*os.File))
+}
+
+// uiOut returns the writer for decorative messages on standard output.
+// Ciphertext, plaintext and other data must keep using cmd.OutOrStdout(),
+// because the writer returned here may drop characters.
+func uiOut(cmd *cobra.Command) io.Writer {
+	return uiWriter(cmd, cmd.OutOrStdout())
+}
+
+// uiErr returns the writer for decorative messages on standard error.
+func uiErr(cmd *cobra.Command) io.Writer {
+	return uiWriter(cmd, cmd.ErrOrStderr())
+}
+
+// uiWriter wraps w so that, when styling is disabled, ANSI escape sequences
+// and emoji icons are removed from everything written through it.
+func uiWriter(cmd *cobra.Command, w io.Writer) io.Writer {
+	if styleEnabled(cmd, w) {
+		return w
+	}
+	return plainWriter{w}
+}
+
+// paint wraps s in an ANSI style. Only write the result through a uiWriter,
+// which removes the style again when color is disabled.
+func paint(style, s string) string {
+	return style + s + colorReset
+}
+
+// plainWriter strips styling from each write.
+type plainWriter struct {
+	w io.Writer
+}
+
+func (p plainWriter) Write(b []byte) (int, error) {
+	if _, err := io.WriteString(p.w, stripStyle(string(b))); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// stripStyle removes ANSI escape sequences and emoji icons from s. An icon is
+// an emoji followed by spaces, as in "✅ PASSED"; the spaces go with it so
+// the text stays aligned. Emoji elsewhere, for example inside sample text,
+// are kept.
+func stripStyle(s string) string {
+	if !strings.Contains(s, "\x1b") && !strings.ContainsFunc(s, isEmoji) {
+		return s
+	}
+
+	runes := []rune(s)
+	var b strings.Builder
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r == '\x1b' && i+1 < len(runes) && runes[i+1] == '[' {
+			// Skip a CSI sequence up to its final byte
+			j := i + 2
+			for j < len(runes) && (runes[j] < 0x40 || runes[j] > 0x7e) {
+				j++
+			}
+			i = j
+			continue
+		}
+		if isEmoji(r) {
+			j := i + 1
+			for j < len(runes) && (isEmojiModifier(runes[j]) || runes[j-1] == 0x200D && isEmoji(runes[j])) {
+				j++
+			}
+			if j < len(runes) && runes[j] == ' ' {
+				for j < len(runes) && runes[j] == ' ' {
+					j++
+				}
+				i = j - 1
+				continue
+			}
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// isEmoji reports whether r is in one of the pictograph blocks used for icons.
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F300 && r <= 0x1FAFF: // Pictographs, emoticons, transport, supplemental symbols
+		return true
+	case r >= 0x2600 && r <= 0x27BF: // Miscellaneous symbols and dingbats
+		return true
+	case r == 0x2B50: // Star
+		return true
+	}
+	return false
+}
+
+// isEmojiModifier reports whether r modifies the preceding emoji.
+func isEmojiModifier(r rune) bool {
+	return r == 0xFE0F || r == 0x200D || unicode.Is(unicode.Mn, r)
+}
@@ -0,0 +1,100 @@
+// Package cli provides unit tests for color and emoji handling.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestStripStyle(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain text", "plain text"},
+		{"✅ PASSED\n", "PASSED\n"},
+		{"⚠️  Warning: x", "Warning: x"},
+		{"• ✅ Round-trip", "• Round-trip"},
+		{"\x1b[32mPASSED\x1b[0m", "PASSED"},
+		{"❌ \x1b[31mFAILED\x1b[0m: boom", "FAILED: boom"},
+		{`--text "Hello! 🌟"`, `--text "Hello! 🌟"`},
+		{"日本語! 🌟", "日本語! 🌟"},
+		{"a↔b", "a↔b"},
+	}
+	for _, tt := range tests {
+		if got := stripStyle(tt.in); got != tt.want {
+			t.Errorf("stripStyle(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestStyleEnabled(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.Flags().String("color", "auto", "")
+
+	t.Setenv("NO_COLOR", "")
+	if styleEnabled(cmd, &bytes.Buffer{}) {
+		t.Error("auto mode should not style a non-terminal writer")
+	}
+
+	cmd.Flags().Set("color", "always")
+	if !styleEnabled(cmd, &bytes.Buffer{}) {
+		t.Error("--color always should style any writer")
+	}
+	t.Setenv("NO_COLOR", "1")
+	if !styleEnabled(cmd, &bytes.Buffer{}) {
+		t.Error("--color always should override NO_COLOR")
+	}
+
+	cmd.Flags().Set("color", "auto")
+	if styleEnabled(cmd, os.Stdout) {
+		t.Error("NO_COLOR should disable styling in auto mode")
+	}
+
+	cmd.Flags().Set("color", "never")
+	t.Setenv("NO_COLOR", "")
+	if styleEnabled(cmd, os.Stdout) {
+		t.Error("--color never should disable styling")
+	}
+}
+
+func TestColorFlag(t *testing.T) {
+	run := func(args ...string) (string, error) {
+		t.Helper()
+		var stdout bytes.Buffer
+		cmd := NewRootCommand(Options{Out: &stdout, Err: &bytes.Buffer{}, FS: NewMemFS()})
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return stdout.String(), err
+	}
+
+	t.Setenv("NO_COLOR", "")
+	plain, err := run("test")
+	if err != nil {
+		t.Fatalf("test command failed: %v", err)
+	}
+	if strings.Contains(plain, "✅") || strings.Contains(plain, "\x1b[") {
+		t.Errorf("output to a non-terminal should be plain:\n%s", plain)
+	}
+	if !strings.Contains(plain, "... PASSED\n") {
+		t.Errorf("plain output lost its status words:\n%s", plain)
+	}
+
+	styled, err := run("test", "--color", "always")
+	if err != nil {
+		t.Fatalf("test command failed: %v", err)
+	}
+	if !strings.Contains(styled, "✅ \x1b[32mPASSED\x1b[0m") {
+		t.Errorf("--color always should keep emoji and colors:\n%s", styled)
+	}
+
+	if _, err := run("test", "--color", "sometimes"); err == nil || !strings.Contains(err.Error(), "unknown --color") {
+		t.Errorf("expected an unknown --color error, got %v", err)
+	}
+}
@@ -0,0 +1,211 @@
+// Package cli provides the compare command for the enigoma CLI.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/coredds/enigoma"
+	"github.com/coredds/enigoma/internal/analysis"
+	"github.com/coredds/enigoma/pkg/enigma"
+	"github.com/spf13/cobra"
+)
+
+// compareSampleSize is the length of the text encrypted by the throughput benchmark.
+const compareSampleSize = 1024
+
+// compareColumn is one configuration in a comparison table.
+type compareColumn struct {
+	label    string
+	alphabet string
+	machine  *enigma.Enigma
+}
+
+// newCompareCommand creates the compare command.
+func newCompareCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "compare",
+		Short: "Compare keyspace and speed of configurations side by side",
+		Long: `Compare presets and security levels without encrypting anything.
+
+Each --preset and each --security adds a column; security levels use the
+alphabet from --alphabet. With neither, all four security levels are compared.
+The table lists rotors, plugboard pairs, an estimate of the keyspace in bits
+and the measured encryption throughput on this machine.
+
+The keyspace counts randomly wired rotors, starting positions, the reflector
+and the plugboard. It bounds brute-force effort only: Enigma-style machines
+fall to statistical attacks long before their keyspace is exhausted.
+
+Alphabets with an odd number of characters cannot have a reflector; those
+columns are measured without one.
+
+Examples:
+  enigoma compare
+  enigoma compare --preset classic --security high --alphabet ascii
+  enigoma compare --security low --security extreme --bench 0`,
+		Args: cobra.NoArgs,
+		RunE: runCompare,
+	}
+
+	cmd.Flags().StringSliceP("preset", "p", nil, fmt.Sprintf("Preset to compare (%s); repeatable", strings.Join(presetNames(), ", ")))
+	cmd.Flags().StringSliceP("security", "s", nil, "Security level to compare (low, medium, high, extreme); repeatable")
+	cmd.Flags().StringP("alphabet", "a", "latin", "Alphabet for the --security columns (see 'enigoma alphabet list')")
+	cmd.Flags().Duration("bench", 200*time.Millisecond, "Time spent measuring each column's throughput (0 to skip)")
+
+	return cmd
+}
+
+func runCompare(cmd *cobra.Command, args []string) error {
+	columns, err := compareColumns(cmd)
+	if err != nil {
+		return err
+	}
+
+	budget, _ := cmd.Flags().GetDuration("bench")
+	rows := [][]string{
+		{""}, {"Alphabet"}, {"Rotors"}, {"Plugboard pairs"}, {"Reflector"},
+		{"Rotor wiring bits"}, {"Position bits"}, {"Reflector bits"}, {"Plugboard bits"},
+		{"Total keyspace bits"}, {"Throughput"},
+	}
+	for _, c := range columns {
+		m := c.machine
+		reflector := "yes"
+		if m.IsReflectorless() {
+			reflector = "none"
+		}
+		ks := analysis.EstimateKeyspace(m.GetAlphabetSize(), m.GetRotorCount(), m.GetPlugboardPairCount(), !m.IsReflectorless())
+
+		throughput := "skipped"
+		if budget > 0 {
+			rate, err := benchmarkMachine(m, budget)
+			if err != nil {
+				return fmt.Errorf("benchmark of %s failed: %v", c.label, err)
+			}
+			throughput = formatThroughput(rate)
+		}
+
+		values := []string{
+			c.label,
+			fmt.Sprintf("%s (%d)", c.alphabet, m.GetAlphabetSize()),
+			fmt.Sprint(m.GetRotorCount()),
+			fmt.Sprint(m.GetPlugboardPairCount()),
+			reflector,
+			fmt.Sprintf("%.0f", ks.WiringBits),
+			fmt.Sprintf("%.0f", ks.PositionBits),
+			fmt.Sprintf("%.0f", ks.ReflectorBits),
+			fmt.Sprintf("%.0f", ks.PlugboardBits),
+			fmt.Sprintf("%.0f", ks.TotalBits()),
+			throughput,
+		}
+		for i := range rows {
+			rows[i] = append(rows[i], values[i])
+		}
+	}
+
+	writeTable(cmd, rows)
+	fmt.Fprintln(cmd.OutOrStdout(), "\nKeyspace bits bound brute force only; they are not a measure of cryptographic strength.")
+	return nil
+}
+
+// compareColumns builds the machines chosen by --preset, --security and
+// --alphabet, or all four security levels when neither is given. Security
+// levels on an alphabet too odd for a reflector are built without one.
+func compareColumns(cmd *cobra.Command) ([]compareColumn, error) {
+	presets, _ := cmd.Flags().GetStringSlice("preset")
+	levels, _ := cmd.Flags().GetStringSlice("security")
+	if len(presets) == 0 && len(levels) == 0 {
+		levels = []string{"low", "medium", "high", "extreme"}
+	}
+
+	var columns []compareColumn
+	for _, preset := range presets {
+		machine, err := presetMachine(cmd, preset)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, compareColumn{label: "preset " + strings.ToLower(preset), alphabet: "latin", machine: machine})
+	}
+
+	alphabetName, _ := cmd.Flags().GetString("alphabet")
+	predefined, ok := enigoma.LookupAlphabet(alphabetName)
+	if !ok {
+		return nil, fmt.Errorf("unknown alphabet: %s. Available: %s (see 'enigoma alphabet list')", alphabetName, alphabetNameList(false))
+	}
+	for _, name := range levels {
+		level, err := parseSecurityLevel(name)
+		if err != nil {
+			return nil, err
+		}
+		opts := []enigma.Option{enigma.WithAlphabet(predefined.Runes)}
+		if !predefined.ReflectorCompatible() {
+			opts = append(opts, enigma.WithoutReflector())
+		}
+		machine, err := enigma.New(append(opts, enigma.WithRandomSettings(level))...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build %s: %v", name, err)
+		}
+		columns = append(columns, compareColumn{label: strings.ToLower(name) + " / " + predefined.Name, alphabet: predefined.Name, machine: machine})
+	}
+	return columns, nil
+}
+
+// benchmarkMachine measures encryption throughput on a copy of machine.
+func benchmarkMachine(machine *enigma.Enigma, budget time.Duration) (float64, error) {
+	clone, err := machine.Clone()
+	if err != nil {
+		return 0, err
+	}
+	alph, err := machineAlphabet(clone)
+	if err != nil {
+		return 0, err
+	}
+	sample, err := alph.RandomText(compareSampleSize)
+	if err != nil {
+		return 0, err
+	}
+	return analysis.MeasureThroughput(func(text string) error {
+		_, err := clone.Encrypt(text)
+		return err
+	}, sample, budget)
+}
+
+// formatThroughput renders characters per second with a unit prefix.
+func formatThroughput(rate float64) string {
+	switch {
+	case rate >= 1e6:
+		return fmt.Sprintf("%.1fM chars/s", rate/1e6)
+	case rate >= 1e3:
+		return fmt.Sprintf("%.1fk chars/s", rate/1e3)
+	default:
+		return fmt.Sprintf("%.0f chars/s", rate)
+	}
+}
+
+// writeTable prints rows with each column padded to its widest cell.
+func writeTable(cmd *cobra.Command, rows [][]string) {
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if n := len([]rune(cell)); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	out := cmd.OutOrStdout()
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = cell + strings.Repeat(" ", widths[i]-len([]rune(cell)))
+		}
+		fmt.Fprintln(out, strings.TrimRight(strings.Join(cells, "  "), " "))
+	}
+}
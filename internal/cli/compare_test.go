@@ -0,0 +1,56 @@
+// Package cli provides unit tests for the compare command.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	run := func(args ...string) (string, error) {
+		t.Helper()
+		var out bytes.Buffer
+		cmd := NewRootCommand(Options{FS: NewMemFS()})
+		cmd.SetOut(&out)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs(append([]string{"compare"}, args...))
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	out, err := run("--preset", "classic", "--security", "high", "--alphabet", "ascii", "--bench", "1ms")
+	if err != nil {
+		t.Fatalf("compare failed: %v", err)
+	}
+	for _, want := range []string{"preset classic", "high / ascii", "latin (26)", "ascii (95)", "Total keyspace bits", "chars/s"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if fields := strings.Fields(line); len(fields) == 3 && fields[0] == "Reflector" && fields[2] != "none" {
+			t.Errorf("odd alphabet should be compared without a reflector: %q", line)
+		}
+	}
+
+	out, err = run("--bench", "0")
+	if err != nil {
+		t.Fatalf("compare failed: %v", err)
+	}
+	for _, want := range []string{"low / latin", "extreme / latin", "skipped"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in default comparison:\n%s", want, out)
+		}
+	}
+
+	if _, err := run("--security", "ultra"); err == nil {
+		t.Error("expected an error for an unknown security level")
+	}
+	if _, err := run("--preset", "bogus"); err == nil {
+		t.Error("expected an error for an unknown preset")
+	}
+}
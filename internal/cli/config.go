@@ -6,16 +6,20 @@ package cli
 
 import (
 	"fmt"
-	"os"
+	"io"
+	"strconv"
+	"strings"
 
 	"github.com/coredds/enigoma/pkg/enigma"
 	"github.com/spf13/cobra"
 )
 
-var configCmd = &cobra.Command{
-	Use:   "config",
-	Short: "Manage Enigma machine configuration files",
-	Long: `Manage Enigma machine configuration files.
+// newConfigCommand creates the config command.
+func newConfigCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage Enigma machine configuration files",
+		Long: `Manage Enigma machine configuration files.
 
 This command helps validate, inspect, and manipulate configuration files
 used by the enigoma CLI and library.
@@ -23,19 +27,71 @@ used by the enigoma CLI and library.
 Examples:
   enigoma config --validate my-config.json
   enigoma config --show my-config.json
+  enigoma config --stats my-config.json
   enigoma config --test my-config.json --text "Hello World"
-  enigoma config --convert old-config.json --output new-config.json`,
-	RunE: runConfig,
-}
+  enigoma config --test my-config.json --length 1000
+  enigoma config --convert old-config.json --output new-config.json
+  enigoma config --convert my-config.json --output my-config.yaml
+  enigoma config --convert my-config.toml --format json --output my-config.cfg
+  enigoma config --history my-config.json
+  enigoma config --history my-config.json --restore 20250102T150405Z
+  enigoma config --rekey old.json --to-alphabet alphanumeric --output new.json
+  enigoma config --edit my-config.json --set-position I=Q --set-ring III=5
+  enigoma config --qr my-config.json --output my-config.png
+  enigoma config --from-qr my-config.png --output my-config.json
+  enigoma config --gen-signing-key signing.pem
+  enigoma config --sign my-config.json --signing-key signing.pem
+  enigoma encrypt --config my-config.json --signing-key signing.pem.pub --require-signed --text "Hello"
+
+Configuration files may be JSON, YAML or TOML. The format is taken from the
+extension (.json, .yaml/.yml, .toml) and, for other names, from the contents.
+--convert writes the format of the --output extension unless --format says
+otherwise.
+
+--qr draws the configuration as a PNG QR code holding its compact binary
+(protobuf) settings, so another machine can pick it up by scanning it;
+--from-qr reads such an image back. --from-qr reads clean, upright images
+like those --qr writes, not camera photos. The code holds the key unencrypted.
+
+--sign adds a signature to the configuration's metadata, made with an HMAC
+secret (any key file of at least 16 bytes) or an Ed25519 key from
+--gen-signing-key. Every command checks the signature of signed
+configurations on load: Ed25519 signatures against --signing-key (which may
+be the .pub file) or, without it, the public key they carry; HMAC signatures
+when --signing-key is given. --require-signed rejects configurations without
+a valid signature. Changing a signed configuration removes its signature.
+
+Whenever a command overwrites an existing configuration file, the previous
+version is kept under .enigoma-history/ next to it.`,
+		RunE: runConfig,
+	}
 
-func init() {
-	configCmd.Flags().StringP("validate", "", "", "Validate a configuration file")
-	configCmd.Flags().StringP("show", "s", "", "Show configuration details")
-	configCmd.Flags().StringP("test", "t", "", "Test configuration with sample text")
-	configCmd.Flags().StringP("text", "", "Hello World", "Text to use for testing")
-	configCmd.Flags().StringP("convert", "", "", "Convert/update configuration format")
-	configCmd.Flags().StringP("output", "o", "", "Output file for converted configuration")
-	configCmd.Flags().BoolP("detailed", "d", false, "Show detailed information")
+	cmd.Flags().StringP("validate", "", "", "Validate a configuration file")
+	cmd.Flags().StringP("show", "s", "", "Show configuration details")
+	cmd.Flags().String("stats", "", "Show the keyspace of a configuration file (rotor wirings, positions, plugboard, total bits)")
+	cmd.Flags().StringP("test", "t", "", "Test configuration with sample text")
+	cmd.Flags().StringP("text", "", "", "Text to use for testing (default: random text from the key's alphabet)")
+	cmd.Flags().IntP("length", "n", 100, "Length of the random test text")
+	cmd.Flags().StringP("convert", "", "", "Convert a configuration file to the format of --output (json, yaml, toml)")
+	cmd.Flags().StringP("format", "f", "", "Format of the converted configuration (json, yaml, toml; default: from the --output extension)")
+	cmd.Flags().StringP("output", "o", "", "Output file for converted configuration")
+	cmd.Flags().BoolP("detailed", "d", false, "Show detailed information")
+	cmd.Flags().String("history", "", "List timestamped backups of a configuration file")
+	cmd.Flags().String("restore", "", "Restore the backup with this timestamp (use with --history)")
+	cmd.Flags().String("rekey", "", "Generate a new key over another alphabet, keeping this key's metadata (use with --to-alphabet and --output)")
+	cmd.Flags().String("to-alphabet", "", "Alphabet of the rekeyed configuration ("+alphabetNameList(false)+")")
+	cmd.Flags().Bool("keep-shape", false, "Keep the old key's rotor count and plugboard pair count when rekeying")
+	cmd.Flags().String("security", "medium", "Security level of the rekeyed configuration without --keep-shape (low, medium, high, extreme)")
+	cmd.Flags().String("edit", "", "Change rotors of a configuration file in place (use with --set-position and --set-ring; --output writes elsewhere)")
+	cmd.Flags().String("qr", "", "Write a configuration file as a PNG QR code (use with --output)")
+	cmd.Flags().String("from-qr", "", "Read a configuration from a PNG QR code written by --qr")
+	cmd.Flags().Int("qr-scale", 8, "Pixels per QR code module")
+	cmd.Flags().String("sign", "", "Sign a configuration file in place with --signing-key (--output writes elsewhere)")
+	cmd.Flags().String("gen-signing-key", "", "Write a new Ed25519 signing key to this file and its public key to <file>.pub")
+	addRotorOverrideFlags(cmd)
+	addNotationFlag(cmd, notationIndex)
+
+	return cmd
 }
 
 func runConfig(cmd *cobra.Command, args []string) error {
@@ -43,8 +99,17 @@ func runConfig(cmd *cobra.Command, args []string) error {
 
 	validate, _ := cmd.Flags().GetString("validate")
 	show, _ := cmd.Flags().GetString("show")
+	stats, _ := cmd.Flags().GetString("stats")
 	test, _ := cmd.Flags().GetString("test")
 	convert, _ := cmd.Flags().GetString("convert")
+	history, _ := cmd.Flags().GetString("history")
+	restore, _ := cmd.Flags().GetString("restore")
+	rekey, _ := cmd.Flags().GetString("rekey")
+	edit, _ := cmd.Flags().GetString("edit")
+	qrFile, _ := cmd.Flags().GetString("qr")
+	fromQR, _ := cmd.Flags().GetString("from-qr")
+	sign, _ := cmd.Flags().GetString("sign")
+	genSigningKey, _ := cmd.Flags().GetString("gen-signing-key")
 
 	// Handle different operations
 	if validate != "" {
@@ -55,6 +120,10 @@ func runConfig(cmd *cobra.Command, args []string) error {
 		return showConfig(show, cmd)
 	}
 
+	if stats != "" {
+		return showConfigStats(stats, cmd)
+	}
+
 	if test != "" {
 		return testConfig(test, cmd)
 	}
@@ -63,39 +132,91 @@ func runConfig(cmd *cobra.Command, args []string) error {
 		return convertConfig(convert, cmd)
 	}
 
+	if restore != "" {
+		if history == "" {
+			return fmt.Errorf("--restore requires --history <config file>")
+		}
+		return restoreConfig(history, restore, cmd)
+	}
+
+	if history != "" {
+		return showConfigHistory(history, cmd)
+	}
+
+	if rekey != "" {
+		return rekeyConfig(rekey, cmd)
+	}
+
+	if edit != "" {
+		return editConfig(edit, cmd)
+	}
+
+	if qrFile != "" {
+		return exportConfigQR(qrFile, cmd)
+	}
+
+	if fromQR != "" {
+		return importConfigQR(fromQR, cmd)
+	}
+
+	if genSigningKey != "" {
+		return generateSigningKey(genSigningKey, cmd)
+	}
+
+	if sign != "" {
+		return signConfig(sign, cmd)
+	}
+
 	// Default: show help if no operation specified
 	return cmd.Help()
 }
 
+// showConfigStats prints the keyspace statistics of a configuration file.
+func showConfigStats(configFile string, cmd *cobra.Command) error {
+	machine, err := createMachineFromConfig(fileSystem(cmd), configOptionsFor(cmd), configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %v", err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Configuration: %s\n", configFile)
+	showConfigurationStats(machine, cmd)
+	return nil
+}
+
 func validateConfig(configFile string, cmd *cobra.Command) error {
 	fmt.Fprintf(cmd.OutOrStdout(), "Validating configuration file: %s\n", configFile)
 
-	// Try to read and parse the configuration
-	data, err := os.ReadFile(configFile)
-	if err != nil {
+	// Make sure the configuration can be read at all
+	if _, err := fileSystem(cmd).Stat(configFile); err != nil {
 		return fmt.Errorf("failed to read config file: %v", err)
 	}
 
 	// Validate by attempting to load configuration
 	if err := validateConfigFile(configFile, cmd); err != nil {
-		fmt.Fprintf(cmd.OutOrStdout(), "❌ Configuration is INVALID: %v\n", err)
+		fmt.Fprintf(uiOut(cmd), "❌ Configuration is %s: %v\n", paint(colorRed, "INVALID"), err)
 		return nil
 	}
 
 	// Try to create machine from configuration
-	machine, err := enigma.NewFromJSON(string(data))
+	machine, err := loadConfig(fileSystem(cmd), configOptionsFor(cmd), configFile)
 	if err != nil {
-		fmt.Fprintf(cmd.OutOrStdout(), "❌ Configuration is INVALID (machine creation): %v\n", err)
+		fmt.Fprintf(uiOut(cmd), "❌ Configuration is %s (machine creation): %v\n", paint(colorRed, "INVALID"), err)
 		return nil
 	}
 
 	// Additional validation
-	fmt.Fprintf(cmd.OutOrStdout(), "✅ Configuration is VALID\n")
-	fmt.Fprintf(cmd.OutOrStdout(), "   Schema Version: %d\n", 1) // Currently only v1 is supported
+	fmt.Fprintf(uiOut(cmd), "✅ Configuration is %s\n", paint(colorGreen, "VALID"))
+	caps := machine.Capabilities()
+	if from := machine.MigratedFromSchema(); from != 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "   Schema Version: %d (upgraded from %d on load; config --convert rewrites it)\n", caps.SchemaVersion, from)
+	} else {
+		fmt.Fprintf(cmd.OutOrStdout(), "   Schema Version: %d\n", caps.SchemaVersion)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "   Features: %s\n", describeCapabilities(caps))
+	fmt.Fprintf(cmd.OutOrStdout(), "   Signature: %s\n", describeSignature(fileSystem(cmd), configOptionsFor(cmd), machine))
 	fmt.Fprintf(cmd.OutOrStdout(), "   Alphabet Size: %d characters\n", machine.GetAlphabetSize())
 	fmt.Fprintf(cmd.OutOrStdout(), "   Rotors: %d\n", machine.GetRotorCount())
 	fmt.Fprintf(cmd.OutOrStdout(), "   Plugboard Pairs: %d\n", machine.GetPlugboardPairCount())
-	fmt.Fprintf(cmd.OutOrStdout(), "   Current Rotor Positions: %v\n", machine.GetCurrentRotorPositions())
+	fmt.Fprintf(cmd.OutOrStdout(), "   Current Rotor Positions: %v (%s)\n", machine.GetCurrentRotorPositions(), string(machine.GetRotorPositionsAsRunes()))
 
 	return nil
 }
@@ -103,14 +224,8 @@ func validateConfig(configFile string, cmd *cobra.Command) error {
 func showConfig(configFile string, cmd *cobra.Command) error {
 	detailed, _ := cmd.Flags().GetBool("detailed")
 
-	// Read configuration
-	data, err := os.ReadFile(configFile)
-	if err != nil {
-		return fmt.Errorf("failed to read config file: %v", err)
-	}
-
 	// Create machine from configuration
-	machine, err := enigma.NewFromJSON(string(data))
+	machine, err := loadConfig(fileSystem(cmd), configOptionsFor(cmd), configFile)
 	if err != nil {
 		return fmt.Errorf("failed to parse configuration: %v", err)
 	}
@@ -120,20 +235,30 @@ func showConfig(configFile string, cmd *cobra.Command) error {
 	fmt.Fprintf(cmd.OutOrStdout(), "==========================================\n")
 	fmt.Fprintf(cmd.OutOrStdout(), "Alphabet Size: %d characters\n", machine.GetAlphabetSize())
 	fmt.Fprintf(cmd.OutOrStdout(), "Rotors: %d\n", machine.GetRotorCount())
-	fmt.Fprintf(cmd.OutOrStdout(), "Plugboard Pairs: %d\n", machine.GetPlugboardPairCount())
-	fmt.Fprintf(cmd.OutOrStdout(), "Current Rotor Positions: %v\n", machine.GetCurrentRotorPositions())
+	if machine.IsPlugboardDisabled() {
+		fmt.Fprintf(cmd.OutOrStdout(), "Plugboard: none\n")
+	} else {
+		fmt.Fprintf(cmd.OutOrStdout(), "Plugboard Pairs: %d\n", machine.GetPlugboardPairCount())
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Current Rotor Positions: %v (%s)\n", machine.GetCurrentRotorPositions(), string(machine.GetRotorPositionsAsRunes()))
+	fmt.Fprintf(cmd.OutOrStdout(), "Features: %s\n", describeCapabilities(machine.Capabilities()))
+	fmt.Fprintf(cmd.OutOrStdout(), "Signature: %s\n", describeSignature(fileSystem(cmd), configOptionsFor(cmd), machine))
+
+	settings, err := machine.GetSettings()
+	if err != nil {
+		return fmt.Errorf("failed to get settings: %v", err)
+	}
+	showProvenance(cmd.OutOrStdout(), settings)
 
 	if detailed {
 		fmt.Fprintf(cmd.OutOrStdout(), "\nDetailed Settings:\n")
 		fmt.Fprintf(cmd.OutOrStdout(), "------------------\n")
 
-		// Get full settings
-		settings, err := machine.GetSettings()
-		if err != nil {
-			return fmt.Errorf("failed to get detailed settings: %v", err)
+		if len(settings.Symbols) > 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "Alphabet: %s (grapheme clusters)\n", strings.Join(settings.Symbols, " "))
+		} else {
+			fmt.Fprintf(cmd.OutOrStdout(), "Alphabet: %s\n", string(settings.Alphabet))
 		}
-
-		fmt.Fprintf(cmd.OutOrStdout(), "Alphabet: %s\n", string(settings.Alphabet))
 		fmt.Fprintf(cmd.OutOrStdout(), "Rotor Count: %d\n", len(settings.RotorSpecs))
 
 		for i, rotor := range settings.RotorSpecs {
@@ -141,8 +266,36 @@ func showConfig(configFile string, cmd *cobra.Command) error {
 				i+1, rotor.ID, rotor.Position, rotor.RingSetting)
 		}
 
-		fmt.Fprintf(cmd.OutOrStdout(), "Reflector: ID=%s\n", settings.ReflectorSpec.ID)
-		fmt.Fprintf(cmd.OutOrStdout(), "Plugboard Pairs: %d\n", len(settings.PlugboardPairs))
+		if settings.Reflectorless {
+			fmt.Fprintf(cmd.OutOrStdout(), "Reflector: none (experimental, non-historical)\n")
+		} else if settings.ReflectorSpec.Rotating {
+			fmt.Fprintf(cmd.OutOrStdout(), "Reflector: ID=%s, rotating, Position=%d\n", settings.ReflectorSpec.ID, settings.ReflectorSpec.Position)
+		} else if settings.ReflectorSpec.Settable {
+			fmt.Fprintf(cmd.OutOrStdout(), "Reflector: ID=%s, settable, Position=%d\n", settings.ReflectorSpec.ID, settings.ReflectorSpec.Position)
+		} else if settings.ReflectorSpec.FixedPoints > 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "Reflector: ID=%s, %d fixed points\n", settings.ReflectorSpec.ID, settings.ReflectorSpec.FixedPoints)
+		} else {
+			fmt.Fprintf(cmd.OutOrStdout(), "Reflector: ID=%s\n", settings.ReflectorSpec.ID)
+		}
+		if settings.EntryWheel != "" {
+			fmt.Fprintf(cmd.OutOrStdout(), "Entry Wheel: %s\n", settings.EntryWheel)
+		}
+		if settings.Uhr {
+			fmt.Fprintf(cmd.OutOrStdout(), "Uhr: Position=%d\n", settings.UhrPosition)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Stepping: %s\n", settings.SteppingMode)
+		fmt.Fprintf(cmd.OutOrStdout(), "Unknown Characters: %s\n", settings.UnknownCharPolicy)
+		if settings.Normalization != enigma.NormalizationNone {
+			fmt.Fprintf(cmd.OutOrStdout(), "Normalization: %s\n", strings.ToUpper(settings.Normalization.String()))
+		}
+		if settings.CaseFolding {
+			fmt.Fprintf(cmd.OutOrStdout(), "Case Folding: on\n")
+		}
+		if settings.PlugboardDisabled {
+			fmt.Fprintf(cmd.OutOrStdout(), "Plugboard: none\n")
+		} else {
+			fmt.Fprintf(cmd.OutOrStdout(), "Plugboard Pairs: %d\n", len(settings.PlugboardPairs))
+		}
 
 		if len(settings.PlugboardPairs) > 0 {
 			fmt.Fprintf(cmd.OutOrStdout(), "  Pairs: ")
@@ -156,19 +309,85 @@ func showConfig(configFile string, cmd *cobra.Command) error {
 	return nil
 }
 
-func testConfig(configFile string, cmd *cobra.Command) error {
-	testText, _ := cmd.Flags().GetString("text")
+// describeCapabilities summarizes the features of a machine on one line,
+// e.g. "lever stepping, settable reflector, entry wheel". Static rotors are
+// numbered from 1, as in the detailed settings.
+func describeCapabilities(caps enigma.Capabilities) string {
+	features := []string{caps.SteppingMode.String() + " stepping"}
+	if caps.SteppingMode == enigma.SteppingNone {
+		features[0] = "no stepping"
+	}
+	features = append(features, caps.Reflector.String()+" reflector")
+	if caps.Reflector == enigma.ReflectorNone {
+		features[1] = "no reflector"
+	}
+	if caps.EntryWheel {
+		features = append(features, "entry wheel")
+	}
+	if caps.NoPlugboard {
+		features = append(features, "no plugboard")
+	}
+	if caps.Uhr {
+		features = append(features, "Uhr")
+	}
+	if caps.HasStaticRotors() && caps.SteppingMode != enigma.SteppingNone {
+		numbers := make([]string, len(caps.StaticRotors))
+		for i, idx := range caps.StaticRotors {
+			numbers[i] = strconv.Itoa(idx + 1)
+		}
+		features = append(features, "static rotors "+strings.Join(numbers, ", "))
+	}
+	return strings.Join(features, ", ")
+}
 
-	fmt.Fprintf(cmd.OutOrStdout(), "Testing configuration: %s\n", configFile)
-	fmt.Fprintf(cmd.OutOrStdout(), "Test text: %s\n", testText)
-	fmt.Fprintf(cmd.OutOrStdout(), "========================\n")
+// showProvenance lists where each rotor and reflector wiring came from. Keys
+// without any provenance print nothing.
+func showProvenance(w io.Writer, settings *enigma.EnigmaSettings) {
+	var lines []string
+	for i, spec := range settings.RotorSpecs {
+		if !spec.Provenance.IsZero() {
+			lines = append(lines, fmt.Sprintf("  Rotor %d (%s): %s", i+1, spec.ID, spec.Provenance))
+		}
+	}
+	if !settings.Reflectorless && !settings.ReflectorSpec.Provenance.IsZero() {
+		lines = append(lines, fmt.Sprintf("  Reflector (%s): %s", settings.ReflectorSpec.ID, settings.ReflectorSpec.Provenance))
+	}
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "Provenance:")
+	for _, line := range lines {
+		fmt.Fprintln(w, line)
+	}
+}
 
+func testConfig(configFile string, cmd *cobra.Command) error {
 	// Create machine from configuration
-	machine, err := createMachineFromConfig(configFile)
+	machine, err := createMachineFromConfig(fileSystem(cmd), configOptionsFor(cmd), configFile)
 	if err != nil {
 		return fmt.Errorf("failed to create machine from config: %v", err)
 	}
 
+	// Random text from the key's own alphabet works for any configuration
+	testText, _ := cmd.Flags().GetString("text")
+	if testText == "" {
+		length, _ := cmd.Flags().GetInt("length")
+		if length <= 0 {
+			return fmt.Errorf("--length must be positive, got %d", length)
+		}
+		alph, err := machineAlphabet(machine)
+		if err != nil {
+			return err
+		}
+		if testText, err = alph.RandomText(length); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Testing configuration: %s\n", configFile)
+	fmt.Fprintf(cmd.OutOrStdout(), "Test text: %s\n", testText)
+	fmt.Fprintf(cmd.OutOrStdout(), "========================\n")
+
 	// Test encryption
 	encrypted, err := machine.Encrypt(testText)
 	if err != nil {
@@ -191,9 +410,9 @@ func testConfig(configFile string, cmd *cobra.Command) error {
 
 	// Verify round-trip
 	if testText == decrypted {
-		fmt.Fprintf(cmd.OutOrStdout(), "✅ Round-trip test PASSED\n")
+		fmt.Fprintf(uiOut(cmd), "✅ Round-trip test %s\n", paint(colorGreen, "PASSED"))
 	} else {
-		fmt.Fprintf(cmd.OutOrStdout(), "❌ Round-trip test FAILED\n")
+		fmt.Fprintf(uiOut(cmd), "❌ Round-trip test %s\n", paint(colorRed, "FAILED"))
 		fmt.Fprintf(cmd.OutOrStdout(), "   Expected: %s\n", testText)
 		fmt.Fprintf(cmd.OutOrStdout(), "   Got:      %s\n", decrypted)
 	}
@@ -208,27 +427,118 @@ func convertConfig(configFile string, cmd *cobra.Command) error {
 		return fmt.Errorf("output file required for conversion (use --output)")
 	}
 
-	fmt.Fprintf(cmd.OutOrStdout(), "Converting configuration: %s → %s\n", configFile, outputFile)
+	format, err := outputConfigFormat(cmd, outputFile)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Converting configuration: %s → %s (%s)\n", configFile, outputFile, strings.ToUpper(format))
+
+	// Converting in place reads and rewrites the same file, so hold the lock throughout
+	fsys, opts := fileSystem(cmd), configOptionsFor(cmd)
+	var upgradedFrom int
+	err = withFileLock(fsys, outputFile, func() error {
+		// Read and validate input configuration; older schemas are upgraded on load
+		machine, err := createMachineFromConfig(fsys, opts, configFile)
+		if err != nil {
+			return fmt.Errorf("failed to read input configuration: %v", err)
+		}
+		upgradedFrom = machine.MigratedFromSchema()
 
-	// Read and validate input configuration
-	machine, err := createMachineFromConfig(configFile)
+		// Export to the new format
+		jsonData, err := machine.SaveSettingsToJSON()
+		if err != nil {
+			return fmt.Errorf("failed to convert configuration: %v", err)
+		}
+		data, err := convertConfigText(jsonData, format)
+		if err != nil {
+			return fmt.Errorf("failed to convert configuration: %v", err)
+		}
+
+		// Write to output file
+		if err := writeConfigFileLocked(fsys, opts, outputFile, data); err != nil {
+			return fmt.Errorf("failed to write converted configuration: %v", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to read input configuration: %v", err)
+		return err
 	}
 
-	// Export to new format (currently just re-export as JSON)
-	jsonData, err := machine.SaveSettingsToJSON()
+	fmt.Fprintf(uiOut(cmd), "✅ Configuration converted successfully\n")
+	if upgradedFrom != 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "Upgraded settings schema v%d → v%d\n", upgradedFrom, enigma.CurrentSchemaVersion)
+	}
+
+	return nil
+}
+
+// editConfig applies --set-position and --set-ring to a configuration file,
+// rewriting it (after a backup) or writing the result to --output.
+func editConfig(configFile string, cmd *cobra.Command) error {
+	if !hasRotorOverrides(cmd) {
+		return fmt.Errorf("nothing to edit: pass --set-position or --set-ring")
+	}
+	outputFile, _ := cmd.Flags().GetString("output")
+	if outputFile == "" {
+		outputFile = configFile
+	}
+
+	// Editing in place reads and rewrites the same file, so hold the lock throughout
+	fsys, opts := fileSystem(cmd), configOptionsFor(cmd)
+	err := withFileLock(fsys, outputFile, func() error {
+		machine, err := createMachineFromConfig(fsys, opts, configFile)
+		if err != nil {
+			return fmt.Errorf("failed to read configuration: %v", err)
+		}
+		if err := applyRotorOverrides(cmd, machine); err != nil {
+			return err
+		}
+		if err := dropStaleSignature(cmd, machine); err != nil {
+			return err
+		}
+		jsonData, err := machine.SaveSettingsToJSON()
+		if err != nil {
+			return fmt.Errorf("failed to serialize configuration: %v", err)
+		}
+		if err := writeConfigFileLocked(fsys, opts, outputFile, jsonData); err != nil {
+			return fmt.Errorf("failed to write configuration: %v", err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Rotor positions: %s\n", machinePositions(cmd, machine))
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to convert configuration: %v", err)
+		return err
 	}
 
-	// Write to output file
-	err = writeStringToFile(jsonData, outputFile)
+	fmt.Fprintf(uiOut(cmd), "✅ Configuration updated: %s\n", outputFile)
+	return nil
+}
+
+func showConfigHistory(configFile string, cmd *cobra.Command) error {
+	backups, err := listConfigBackups(fileSystem(cmd), configFile)
 	if err != nil {
-		return fmt.Errorf("failed to write converted configuration: %v", err)
+		return fmt.Errorf("failed to read history: %v", err)
 	}
 
-	fmt.Fprintf(cmd.OutOrStdout(), "✅ Configuration converted successfully\n")
+	if len(backups) == 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "No backups found for %s\n", configFile)
+		return nil
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Backups of %s (oldest first):\n", configFile)
+	for _, b := range backups {
+		fmt.Fprintf(cmd.OutOrStdout(), "  %-22s %6d bytes  %s\n", b.Timestamp, b.Size, b.Path)
+	}
+	return nil
+}
+
+func restoreConfig(configFile, timestamp string, cmd *cobra.Command) error {
+	restored, err := restoreConfigBackup(fileSystem(cmd), configOptionsFor(cmd), configFile, timestamp)
+	if err != nil {
+		return fmt.Errorf("failed to restore configuration: %v", err)
+	}
 
+	fmt.Fprintf(uiOut(cmd), "✅ Restored %s from backup %s\n", configFile, restored)
 	return nil
 }
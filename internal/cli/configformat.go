@@ -0,0 +1,145 @@
+// Package cli provides JSON, YAML and TOML configuration files.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/coredds/enigoma/internal/textconfig"
+	"github.com/coredds/enigoma/pkg/enigma"
+	"github.com/spf13/cobra"
+)
+
+// Configuration file formats.
+const (
+	configJSON = "json"
+	configYAML = "yaml"
+	configTOML = "toml"
+)
+
+// tomlLine matches a TOML key assignment or table header at the start of a line.
+var tomlLine = regexp.MustCompile(`(?m)^[ \t]*(\[\[?[A-Za-z0-9_"-]|[A-Za-z0-9_-]+[ \t]*=)`)
+
+// parseConfigFormat validates a --format value for configuration files.
+func parseConfigFormat(format string) (string, error) {
+	switch f := strings.ToLower(format); f {
+	case configJSON, configYAML, configTOML:
+		return f, nil
+	case "yml":
+		return configYAML, nil
+	}
+	return "", fmt.Errorf("unknown configuration format: %s. Available: json, yaml, toml", format)
+}
+
+// outputConfigFormat returns the format for a configuration written to
+// outputFile: --format when given, else the format named by the extension,
+// else JSON. A --format that contradicts the extension is an error.
+func outputConfigFormat(cmd *cobra.Command, outputFile string) (string, error) {
+	implied := configFormatForPath(outputFile)
+	value, _ := cmd.Flags().GetString("format")
+	if !cmd.Flags().Changed("format") || value == "" {
+		if implied != "" {
+			return implied, nil
+		}
+		return configJSON, nil
+	}
+	format, err := parseConfigFormat(value)
+	if err != nil {
+		return "", err
+	}
+	if implied != "" && implied != format {
+		return "", fmt.Errorf("--format %s does not match the extension of %s", value, outputFile)
+	}
+	return format, nil
+}
+
+// configFormatForPath returns the format implied by a file extension, or ""
+// when the extension names none.
+func configFormatForPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return configJSON
+	case ".yaml", ".yml":
+		return configYAML
+	case ".toml":
+		return configTOML
+	}
+	return ""
+}
+
+// sniffConfigFormat guesses the format of configuration contents: JSON starts
+// with a brace, TOML with assignments or table headers, anything else is YAML.
+func sniffConfigFormat(data []byte) string {
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, []byte(string(byteOrderMark))))
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return configJSON
+	case tomlLine.Match(trimmed):
+		return configTOML
+	}
+	return configYAML
+}
+
+// configFormatOf returns the format of a configuration file, from its
+// extension when that names one and from its contents otherwise.
+func configFormatOf(path string, data []byte) string {
+	if format := configFormatForPath(path); format != "" {
+		return format
+	}
+	return sniffConfigFormat(data)
+}
+
+// parseConfig creates the machine for plaintext configuration contents.
+func parseConfig(data []byte, format string, limits enigma.Limits) (*enigma.Enigma, error) {
+	switch format {
+	case configYAML:
+		return enigma.NewFromYAMLWithLimits(string(data), limits)
+	case configTOML:
+		return enigma.NewFromTOMLWithLimits(string(data), limits)
+	}
+	return enigma.NewFromJSONWithLimits(string(data), limits)
+}
+
+// convertConfigText rewrites configuration contents in another format. Keys
+// keep their order. Contents already in that format, encrypted configurations
+// and an empty format are returned unchanged.
+func convertConfigText(content, format string) (string, error) {
+	from := sniffConfigFormat([]byte(content))
+	if format == "" || format == from || enigma.IsEncryptedConfig(content) {
+		return content, nil
+	}
+
+	var (
+		n   *textconfig.Node
+		err error
+	)
+	switch from {
+	case configYAML:
+		n, err = textconfig.FromYAML([]byte(content), enigma.DefaultLimits.MaxDepth)
+	case configTOML:
+		n, err = textconfig.FromTOML([]byte(content), enigma.DefaultLimits.MaxDepth)
+	default:
+		n, err = textconfig.FromJSON([]byte(content))
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s configuration: %v", strings.ToUpper(from), err)
+	}
+
+	switch format {
+	case configYAML:
+		return string(n.YAML()), nil
+	case configTOML:
+		data, err := n.TOML()
+		if err != nil {
+			return "", fmt.Errorf("failed to encode TOML configuration: %v", err)
+		}
+		return string(data), nil
+	}
+	return string(n.JSON()), nil
+}
@@ -0,0 +1,167 @@
+// Package cli provides unit tests for YAML and TOML configuration files.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/coredds/enigoma/pkg/enigma"
+)
+
+func TestKeygenFormats(t *testing.T) {
+	fsys := NewMemFS()
+	if _, err := runCLI(fsys, "keygen", "--preset", "m3", "--output", "key.yaml"); err != nil {
+		t.Fatalf("keygen failed: %v", err)
+	}
+	data, _ := fsys.ReadFile("key.yaml")
+	if sniffConfigFormat(data) != configYAML || !strings.Contains(string(data), "alphabet:") {
+		t.Fatalf("a .yaml output should be YAML:\n%s", data)
+	}
+
+	out, err := runCLI(fsys, "keygen", "--preset", "m3", "--format", "toml")
+	if err != nil {
+		t.Fatalf("keygen --format toml failed: %v", err)
+	}
+	if !strings.Contains(out, "[[rotor_specs]]") {
+		t.Errorf("--format toml should print TOML:\n%s", out)
+	}
+
+	if _, err := runCLI(fsys, "keygen", "--preset", "m3", "--format", "yaml", "--output", "key.toml"); err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("a --format contradicting the extension should fail, got %v", err)
+	}
+	if _, err := runCLI(fsys, "keygen", "--preset", "m3", "--format", "ini"); err == nil {
+		t.Error("an unknown --format should fail")
+	}
+}
+
+func TestConfigByExtensionAndContents(t *testing.T) {
+	fsys := NewMemFS()
+	if _, err := runCLI(fsys, "keygen", "--preset", "m3", "--output", "key.json"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runCLI(fsys, "keygen", "--preset", "m3", "--format", "toml", "--output", "key.cfg"); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := fsys.ReadFile("key.cfg")
+	if sniffConfigFormat(data) != configTOML {
+		t.Fatalf("--format toml should write TOML to a file without a known extension:\n%s", data)
+	}
+
+	for _, path := range []string{"key.json", "key.cfg"} {
+		out, err := runCLI(fsys, "encrypt", "--config", path, "--text", "HELLO")
+		if err != nil {
+			t.Fatalf("encrypt with %s failed: %v", path, err)
+		}
+		if strings.TrimSpace(out) == "" {
+			t.Errorf("encrypt with %s printed nothing", path)
+		}
+	}
+}
+
+func TestConfigConvertFormats(t *testing.T) {
+	fsys := NewMemFS()
+	if _, err := runCLI(fsys, "keygen", "--security", "medium", "--output", "key.json"); err != nil {
+		t.Fatal(err)
+	}
+	original, err := createMachineFromConfig(fsys, configOptions{}, "key.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := original.Fingerprint()
+
+	steps := [][2]string{{"key.json", "key.yml"}, {"key.yml", "key.toml"}, {"key.toml", "back.json"}}
+	for _, step := range steps {
+		if _, err := runCLI(fsys, "config", "--convert", step[0], "--output", step[1]); err != nil {
+			t.Fatalf("converting %s to %s failed: %v", step[0], step[1], err)
+		}
+		data, _ := fsys.ReadFile(step[1])
+		if got := sniffConfigFormat(data); got != configFormatForPath(step[1]) {
+			t.Errorf("%s holds %s", step[1], got)
+		}
+		converted, err := createMachineFromConfig(fsys, configOptions{}, step[1])
+		if err != nil {
+			t.Fatalf("loading %s failed: %v", step[1], err)
+		}
+		if got, _ := converted.Fingerprint(); got != want {
+			t.Errorf("converting to %s changed the key", step[1])
+		}
+	}
+
+	if _, err := runCLI(fsys, "config", "--convert", "key.toml", "--format", "yaml", "--output", "key.txt"); err != nil {
+		t.Fatalf("convert --format yaml failed: %v", err)
+	}
+	data, _ := fsys.ReadFile("key.txt")
+	if sniffConfigFormat(data) != configYAML {
+		t.Errorf("--format yaml should write YAML:\n%s", data)
+	}
+}
+
+func TestConfigConvertUpgradesSchema(t *testing.T) {
+	fsys := NewMemFS()
+	if _, err := runCLI(fsys, "keygen", "--preset", "m3", "--output", "key.json"); err != nil {
+		t.Fatal(err)
+	}
+	// Rewrite the key in the version 1 layout, which leaves the defaults out
+	data, _ := fsys.ReadFile("key.json")
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	doc["schema_version"] = 1
+	for _, field := range []string{"reflectorless", "stepping_mode", "entry_wheel", "plugboard_disabled", "uhr_enabled", "uhr_position", "unknown_char_policy"} {
+		delete(doc, field)
+	}
+	legacy, _ := json.Marshal(doc)
+	if err := fsys.WriteFile("old.json", legacy, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runCLI(fsys, "config", "--validate", "old.json")
+	if err != nil || !strings.Contains(out, "upgraded from 1") {
+		t.Errorf("validate should report the upgrade on load, got %v:\n%s", err, out)
+	}
+	out, err = runCLI(fsys, "config", "--convert", "old.json", "--output", "new.json")
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	if !strings.Contains(out, "Upgraded settings schema v1 → v2") {
+		t.Errorf("convert should report the upgrade:\n%s", out)
+	}
+	converted, _ := fsys.ReadFile("new.json")
+	var settings enigma.EnigmaSettings
+	if err := json.Unmarshal(converted, &settings); err != nil {
+		t.Fatal(err)
+	}
+	if settings.SchemaVersion != enigma.CurrentSchemaVersion || !strings.Contains(string(converted), `"stepping_mode": "lever"`) {
+		t.Errorf("convert should write schema version %d:\n%s", enigma.CurrentSchemaVersion, converted)
+	}
+
+	original, _ := createMachineFromConfig(fsys, configOptions{}, "key.json")
+	upgraded, err := createMachineFromConfig(fsys, configOptions{}, "new.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := original.Fingerprint()
+	if got, _ := upgraded.Fingerprint(); got != want {
+		t.Error("the upgrade should keep the key")
+	}
+}
+
+func TestSniffConfigFormat(t *testing.T) {
+	tests := map[string]string{
+		"\ufeff{\"alphabet\": []}": configJSON,
+		"alphabet = [\"A\"]\n":     configTOML,
+		"# key\n[[rotor_specs]]\n": configTOML,
+		"alphabet: [A, B]\n":       configYAML,
+		"alphabet:\n  - \"=\"\n":   configYAML,
+	}
+	for input, want := range tests {
+		if got := sniffConfigFormat([]byte(input)); got != want {
+			t.Errorf("sniffConfigFormat(%q) = %s, want %s", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+// Package cli provides the options for reading and writing configuration files.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// configOptions holds the global flags that apply to every configuration file
+// a command reads or writes: the password that encrypts them, and the key and
+// policy their signatures are checked with. The zero value reads and writes
+// plaintext, and checks signed configurations only against the public key
+// they carry.
+type configOptions struct {
+	password      string // --config-password or ENIGOMA_CONFIG_PASSWORD
+	signingKey    string // Key file from --signing-key or ENIGOMA_SIGNING_KEY
+	requireSigned bool   // --require-signed
+}
+
+// configOptionsFor returns the configuration options of the running command.
+func configOptionsFor(cmd *cobra.Command) configOptions {
+	password, _ := cmd.Flags().GetString("config-password")
+	if password == "" {
+		password = os.Getenv(configPasswordEnv)
+	}
+	keyFile, _ := cmd.Flags().GetString("signing-key")
+	if keyFile == "" {
+		keyFile = os.Getenv(signingKeyEnv)
+	}
+	require, _ := cmd.Flags().GetBool("require-signed")
+	return configOptions{password: password, signingKey: keyFile, requireSigned: require}
+}
@@ -0,0 +1,59 @@
+// Package cli provides password-protected configuration files for the enigoma CLI.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"fmt"
+
+	"github.com/coredds/enigoma/pkg/enigma"
+)
+
+// configPasswordEnv supplies the configuration password when --config-password
+// is not given, keeping it out of the process list.
+const configPasswordEnv = "ENIGOMA_CONFIG_PASSWORD"
+
+// decodeConfig creates the machine for the contents of the configuration file
+// at path, decrypting it with opts.password when it is encrypted. Plaintext
+// files are JSON, YAML or TOML according to configFormatOf. The signature is
+// checked as verifyConfigSignature describes.
+func decodeConfig(fsys FS, opts configOptions, path string, data []byte, limits enigma.Limits) (*enigma.Enigma, error) {
+	var machine *enigma.Enigma
+	var err error
+	if !enigma.IsEncryptedConfig(string(data)) {
+		machine, err = parseConfig(data, configFormatOf(path, data), limits)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if opts.password == "" {
+			return nil, fmt.Errorf("the configuration is encrypted; pass --config-password or set %s", configPasswordEnv)
+		}
+		machine, err = enigma.NewFromEncryptedJSONWithLimits(string(data), opts.password, limits)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt: %v", err)
+		}
+	}
+	if err := verifyConfigSignature(fsys, opts, machine); err != nil {
+		return nil, err
+	}
+	return machine, nil
+}
+
+// encryptConfigIfRequested seals a configuration in any format with
+// opts.password. Content is returned unchanged without a password.
+func encryptConfigIfRequested(opts configOptions, content string) (string, error) {
+	if opts.password == "" || enigma.IsEncryptedConfig(content) {
+		return content, nil
+	}
+	machine, err := parseConfig([]byte(content), sniffConfigFormat([]byte(content)), enigma.DefaultLimits)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := machine.SaveSettingsEncrypted(opts.password)
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt configuration: %v", err)
+	}
+	return sealed + "\n", nil
+}
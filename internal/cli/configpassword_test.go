@@ -0,0 +1,85 @@
+// Package cli provides unit tests for password-protected configuration files.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/coredds/enigoma/pkg/enigma"
+)
+
+func TestConfigPassword(t *testing.T) {
+	fsys := NewMemFS()
+	if _, err := runCLI(fsys, "keygen", "--security", "low", "--output", "sealed.json", "--config-password", "hunter2"); err != nil {
+		t.Fatalf("keygen failed: %v", err)
+	}
+	data, _ := fsys.ReadFile("sealed.json")
+	if !enigma.IsEncryptedConfig(string(data)) {
+		t.Fatalf("keygen --config-password should write an encrypted configuration:\n%s", data)
+	}
+
+	ciphertext, err := runCLI(fsys, "encrypt", "--config", "sealed.json", "--config-password", "hunter2", "--text", "HELLO")
+	if err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	ciphertext = strings.TrimSuffix(ciphertext, "\n")
+
+	t.Setenv(configPasswordEnv, "hunter2")
+	plaintext, err := runCLI(fsys, "decrypt", "--config", "sealed.json", "--text", ciphertext)
+	if err != nil {
+		t.Fatalf("decrypt with %s failed: %v", configPasswordEnv, err)
+	}
+	if got := strings.TrimSuffix(plaintext, "\n"); got != "HELLO" {
+		t.Errorf("decrypt = %q", got)
+	}
+
+	t.Setenv(configPasswordEnv, "")
+	if _, err := runCLI(fsys, "decrypt", "--config", "sealed.json", "--text", ciphertext); err == nil || !strings.Contains(err.Error(), "--config-password") {
+		t.Errorf("a missing password should be explained, got %v", err)
+	}
+	if _, err := runCLI(fsys, "decrypt", "--config", "sealed.json", "--config-password", "wrong", "--text", ciphertext); err == nil || !strings.Contains(err.Error(), "wrong password") {
+		t.Errorf("a wrong password should fail, got %v", err)
+	}
+}
+
+func TestConfigPasswordConvert(t *testing.T) {
+	fsys := NewMemFS()
+	if _, err := runCLI(fsys, "keygen", "--preset", "m3", "--output", "plain.json"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runCLI(fsys, "config", "--convert", "plain.json", "--output", "sealed.json", "--config-password", "hunter2"); err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	data, _ := fsys.ReadFile("sealed.json")
+	if !enigma.IsEncryptedConfig(string(data)) {
+		t.Fatal("converting with a password should encrypt the output")
+	}
+	plain, err := createMachineFromConfig(fsys, configOptions{}, "plain.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := createMachineFromConfig(fsys, configOptions{password: "hunter2"}, "sealed.json")
+	if err != nil {
+		t.Fatalf("loading the converted configuration failed: %v", err)
+	}
+	want, _ := plain.Fingerprint()
+	if got, _ := sealed.Fingerprint(); got != want {
+		t.Error("conversion should keep the key")
+	}
+}
+
+func TestConfigPasswordWithoutLocking(t *testing.T) {
+	fsys := NewMemFS()
+	if _, err := runCLI(fsys, "keygen", "--preset", "m3", "--output", "sealed.json", "--config-password", "x", "--no-lock"); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := fsys.ReadFile("sealed.json"); !enigma.IsEncryptedConfig(string(data)) {
+		t.Fatal("--no-lock should not drop the configuration password")
+	}
+	if _, err := runCLI(fsys, "encrypt", "--config", "sealed.json", "--config-password", "x", "--no-lock", "--text", "HELLO"); err != nil {
+		t.Errorf("reading under --no-lock failed: %v", err)
+	}
+}
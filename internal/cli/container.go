@@ -0,0 +1,81 @@
+// Package cli provides .enig container handling for encrypt and decrypt.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/coredds/enigoma/pkg/enigma"
+	"github.com/spf13/cobra"
+)
+
+// wantsContainer reports whether encrypt output should be wrapped in a container,
+// either explicitly (--format enig, --verify-plaintext) or via a .enig output path.
+func wantsContainer(cmd *cobra.Command) bool {
+	if format, _ := cmd.Flags().GetString("format"); strings.EqualFold(format, "enig") {
+		return true
+	}
+	if verify, _ := cmd.Flags().GetBool("verify-plaintext"); verify {
+		return true
+	}
+	output, _ := cmd.Flags().GetString("output")
+	return strings.HasSuffix(strings.ToLower(output), enigma.ContainerExtension)
+}
+
+// buildContainer wraps ciphertext in a serialized container recording the key
+// fingerprint, adding a salted plaintext check when --verify-plaintext is set.
+func buildContainer(cmd *cobra.Command, ciphertext, plaintext, fingerprint string) (string, error) {
+	container := enigma.NewContainer(ciphertext)
+	container.KeyFingerprint = fingerprint
+	if verify, _ := cmd.Flags().GetBool("verify-plaintext"); verify {
+		if err := container.AddPlaintextCheck(plaintext); err != nil {
+			return "", err
+		}
+	}
+	return container.Marshal()
+}
+
+// unwrapContainer extracts the ciphertext from a container, on its own or in
+// an HTML export. Input that is neither is returned unchanged with a nil
+// container.
+func unwrapContainer(cmd *cobra.Command, text string) (*enigma.Container, string, error) {
+	format, _ := cmd.Flags().GetString("format")
+	if embedded, ok := extractHTMLContainer(text); ok {
+		text, format = embedded, "enig"
+	}
+	if !strings.EqualFold(format, "enig") && !enigma.IsContainer(text) {
+		return nil, text, nil
+	}
+
+	container, err := enigma.ParseContainer(strings.TrimSpace(text))
+	if err != nil {
+		return nil, "", err
+	}
+	return container, container.Ciphertext, nil
+}
+
+// verifyContainerPlaintext checks the decrypted text against the container's
+// plaintext check, if it has one.
+func verifyContainerPlaintext(cmd *cobra.Command, container *enigma.Container, plaintext string) error {
+	if container == nil || !container.HasPlaintextCheck() {
+		return nil
+	}
+
+	ok, err := container.VerifyPlaintext(plaintext)
+	if err != nil {
+		return fmt.Errorf("plaintext verification failed: %v", err)
+	}
+	if !ok {
+		return fmt.Errorf("plaintext verification failed: the decrypted text does not match the original.\n\nSuggestions:\n" +
+			"• Make sure you are using the same configuration file used for encryption\n" +
+			"• Check that the rotor positions (--rotors) and --reset match the encryption run")
+	}
+
+	if v, _ := cmd.Flags().GetBool("verbose"); v {
+		fmt.Fprintln(cmd.ErrOrStderr(), "Plaintext check verified")
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+// Package cli provides unit tests for .enig container handling.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/coredds/enigoma/pkg/enigma"
+)
+
+func TestContainerVerifyPlaintextRoundTrip(t *testing.T) {
+	fsys := NewMemFS()
+	key := "key.json"
+	msg := "msg.enig"
+
+	cmd := NewRootCommand(Options{FS: fsys})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"encrypt", "--text", "ATTACKATDAWN", "--preset", "classic", "--save-config", key, "--output", msg, "--verify-plaintext"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+
+	data, err := fsys.ReadFile(msg)
+	if err != nil {
+		t.Fatalf("reading container failed: %v", err)
+	}
+	container, err := enigma.ParseContainer(string(data))
+	if err != nil {
+		t.Fatalf("output is not a container: %v", err)
+	}
+	if !container.HasPlaintextCheck() {
+		t.Fatal("--verify-plaintext should store a plaintext check")
+	}
+
+	// Correct key: decrypts and verifies
+	cmd = NewRootCommand(Options{FS: fsys})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"decrypt", "--file", msg, "--config", key})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("decrypt failed: %v", err)
+	}
+	if out.String() != "ATTACKATDAWN" {
+		t.Errorf("expected ATTACKATDAWN, got %q", out.String())
+	}
+
+	// Wrong key: verification fails instead of printing garbage
+	wrongKey := "wrong.json"
+	cmd = NewRootCommand(Options{FS: fsys})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"keygen", "--preset", "high", "--output", wrongKey})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("keygen failed: %v", err)
+	}
+
+	cmd = NewRootCommand(Options{FS: fsys})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"decrypt", "--file", msg, "--config", wrongKey})
+	err = cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "plaintext verification failed") {
+		t.Errorf("expected plaintext verification failure, got %v", err)
+	}
+}
+
+func TestContainerWithoutPlaintextCheck(t *testing.T) {
+	cmd := NewRootCommand(Options{})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"encrypt", "--text", "HELLO", "--preset", "classic", "--format", "enig"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+
+	container, err := enigma.ParseContainer(out.String())
+	if err != nil {
+		t.Fatalf("--format enig should produce a container: %v", err)
+	}
+	if container.HasPlaintextCheck() {
+		t.Error("plaintext check must be opt-in")
+	}
+}
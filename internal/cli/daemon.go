@@ -0,0 +1,446 @@
+// Package cli provides the daemon and client commands for the enigoma CLI.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/coredds/enigoma"
+	"github.com/coredds/enigoma/pkg/enigma"
+	"github.com/spf13/cobra"
+)
+
+// socketEnv overrides the default daemon socket path.
+const socketEnv = "ENIGOMA_SOCKET"
+
+// maxDaemonRequest bounds the size of one request line.
+const maxDaemonRequest = 16 << 20
+
+// daemonRequest is one line sent to the daemon. Op is ping, keys, encrypt or
+// decrypt; Key names a loaded key like --key does and may be empty when the
+// daemon holds a single key.
+type daemonRequest struct {
+	Op                  string `json:"op"`
+	Key                 string `json:"key,omitempty"`
+	Text                string `json:"text,omitempty"`
+	KeepTrailingNewline bool   `json:"keep_trailing_newline,omitempty"`
+}
+
+// daemonResponse is the daemon's answer to one request.
+type daemonResponse struct {
+	OK      bool             `json:"ok"`
+	Error   string           `json:"error,omitempty"`
+	Text    string           `json:"text,omitempty"`
+	Version string           `json:"version,omitempty"`
+	Keys    []daemonKeyEntry `json:"keys,omitempty"`
+}
+
+// daemonKeyEntry describes a loaded key in a keys response.
+type daemonKeyEntry struct {
+	Name        string `json:"name"`
+	Fingerprint string `json:"fingerprint"`
+	Description string `json:"description,omitempty"`
+}
+
+// daemonKey is a key held by the daemon. The machine stays at the key's
+// starting positions; every request works on a clone of it.
+type daemonKey struct {
+	daemonKeyEntry
+	machine  *enigma.Enigma
+	alphabet []rune
+}
+
+// daemonKeys are the keys a daemon serves, sorted by name.
+type daemonKeys []*daemonKey
+
+// newDaemonCommand creates the daemon command.
+func newDaemonCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "Keep keys loaded and serve encrypt/decrypt requests on a local socket",
+		Long: `Load keys once and answer encrypt and decrypt requests over a Unix socket.
+
+Scripts that encrypt many small messages pay for parsing the key on every
+invocation, and pass key paths on the command line. The daemon reads the keys
+at startup and keeps them in memory; 'enigoma client' sends it requests.
+Every request starts from the key's own rotor positions, exactly like a
+separate 'enigoma encrypt --config' run.
+
+With --config or --key the daemon serves that key; otherwise it serves every
+key in the key directory (see 'enigoma key list'). The socket is created
+private to the user. Stop the daemon with Ctrl+C or SIGTERM.
+
+The protocol is one JSON object per line in each direction:
+  {"op":"encrypt","key":"work","text":"HELLO"}  ->  {"ok":true,"text":"..."}
+  {"op":"keys"}                                 ->  {"ok":true,"keys":[...]}
+  {"op":"ping"}                                 ->  {"ok":true,"version":"..."}
+Errors are answered with {"ok":false,"error":"..."}.
+
+Examples:
+  enigoma daemon --socket /run/user/1000/enigoma.sock
+  enigoma daemon --key work
+  enigoma client encrypt --name work --text "HELLO"`,
+		Args: cobra.NoArgs,
+		RunE: runDaemon,
+	}
+
+	addSocketFlag(cmd)
+
+	return cmd
+}
+
+// newClientCommand creates the client command.
+func newClientCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "client <encrypt|decrypt|keys|ping>",
+		Short: "Send a request to a running enigoma daemon",
+		Long: `Send one request to 'enigoma daemon' and print the answer.
+
+encrypt and decrypt read the text from --text, --file or stdin and print the
+result like the encrypt and decrypt commands. As with them, a final newline
+and invisible characters outside the key's alphabet are dropped. keys lists
+the keys the daemon holds and ping checks that it is running.
+
+Examples:
+  enigoma client ping
+  enigoma client keys
+  enigoma client encrypt --name work --text "HELLO"
+  echo "HELLO" | enigoma client encrypt --name fp:ab12cd
+  enigoma client decrypt --name work --file message.txt`,
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: []string{"encrypt", "decrypt", "keys", "ping"},
+		RunE:      runClient,
+	}
+
+	addSocketFlag(cmd)
+	cmd.Flags().StringP("name", "n", "", "Key held by the daemon, by name or fp:<fingerprint prefix> (default: its only key)")
+	cmd.Flags().StringP("text", "t", "", "Text to process")
+	cmd.Flags().StringP("file", "f", "", "File to read the text from")
+	cmd.Flags().Duration("timeout", 10*time.Second, "How long to wait for the daemon")
+	addInputCleanupFlags(cmd)
+
+	return cmd
+}
+
+// addSocketFlag registers --socket, shared by daemon and client.
+func addSocketFlag(cmd *cobra.Command) {
+	cmd.Flags().String("socket", "", "Socket path (default: $"+socketEnv+", else enigoma.sock in $XDG_RUNTIME_DIR or the temp directory)")
+}
+
+// socketPath returns --socket or the default socket path.
+func socketPath(cmd *cobra.Command) string {
+	if path, _ := cmd.Flags().GetString("socket"); path != "" {
+		return path
+	}
+	if path := os.Getenv(socketEnv); path != "" {
+		return path
+	}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "enigoma.sock")
+	}
+	name := "enigoma.sock"
+	if uid := os.Getuid(); uid >= 0 {
+		// The temp directory is shared between users on Unix
+		name = "enigoma-" + strconv.Itoa(uid) + ".sock"
+	}
+	return filepath.Join(os.TempDir(), name)
+}
+
+func runDaemon(cmd *cobra.Command, args []string) error {
+	keys, err := loadDaemonKeys(cmd)
+	if err != nil {
+		return err
+	}
+
+	path := socketPath(cmd)
+	ln, err := listenSocket(path)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintf(uiErr(cmd), "🔌 Serving %d key(s) on %s\n", len(keys), path)
+	for _, k := range keys {
+		fmt.Fprintf(uiErr(cmd), "   %-20s %s\n", k.Name, shortFingerprint(k.Fingerprint))
+	}
+	err = serveDaemon(ctx, ln, keys)
+	fmt.Fprintln(uiErr(cmd), "👋 Daemon stopped")
+	return err
+}
+
+// loadDaemonKeys loads the key named by --config (or --key), or else every
+// key in the key directory.
+func loadDaemonKeys(cmd *cobra.Command) (daemonKeys, error) {
+	fsys, opts := fileSystem(cmd), configOptionsFor(cmd)
+	var files []string
+	if configFile, _ := cmd.Flags().GetString("config"); configFile != "" {
+		files = []string{configFile}
+	} else {
+		dir, err := keyDir()
+		if err != nil {
+			return nil, err
+		}
+		if files, err = keyFiles(fsys, dir); err != nil {
+			return nil, err
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no keys to serve: %s is empty. Use --config or --key, or save keys there", dir)
+		}
+	}
+
+	var keys daemonKeys
+	for _, file := range files {
+		machine, err := createMachineFromConfig(fsys, opts, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %v", file, err)
+		}
+		if err := reportMachineWarnings(cmd, machine); err != nil {
+			return nil, err
+		}
+		fingerprint, err := machine.Fingerprint()
+		if err != nil {
+			return nil, fmt.Errorf("failed to fingerprint %s: %v", file, err)
+		}
+		settings, err := machine.GetSettings()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read machine settings: %v", err)
+		}
+		keys = append(keys, &daemonKey{
+			daemonKeyEntry: daemonKeyEntry{
+				Name:        strings.TrimSuffix(filepath.Base(file), ".json"),
+				Fingerprint: fingerprint,
+				Description: machine.GetMetadata().Description,
+			},
+			machine:  machine,
+			alphabet: settings.Alphabet,
+		})
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Name < keys[j].Name })
+	return keys, nil
+}
+
+// listenSocket listens on the Unix socket at path, readable and writable by
+// the user only. A socket left behind by a daemon that did not shut down
+// cleanly is replaced; one a daemon still answers on is not, and neither is
+// anything at path that is not a socket.
+func listenSocket(path string) (net.Listener, error) {
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket; choose another --socket path", path)
+		}
+		if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("a daemon is already listening on %s", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket %s: %w", path, err)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to check socket path %s: %w", path, err)
+	}
+	ln, err := listenPrivateSocket(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+	return ln, nil
+}
+
+// serveDaemon answers requests on ln until ctx is done. Closing the listener
+// removes the socket file.
+func serveDaemon(ctx context.Context, ln net.Listener, keys daemonKeys) error {
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to accept connection: %w", err)
+		}
+		go keys.serveConn(conn)
+	}
+}
+
+// serveConn answers the requests of one connection until it is closed.
+func (keys daemonKeys) serveConn(conn net.Conn) {
+	defer conn.Close()
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 64*1024), maxDaemonRequest)
+	enc := json.NewEncoder(conn)
+	for scanner.Scan() {
+		var req daemonRequest
+		var resp daemonResponse
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			resp = daemonResponse{Error: fmt.Sprintf("invalid request: %v", err)}
+		} else {
+			resp = keys.handle(req)
+		}
+		if enc.Encode(resp) != nil {
+			return
+		}
+	}
+	if errors.Is(scanner.Err(), bufio.ErrTooLong) {
+		enc.Encode(daemonResponse{Error: fmt.Sprintf("request exceeds %d bytes", maxDaemonRequest)})
+	}
+}
+
+// handle answers one request.
+func (keys daemonKeys) handle(req daemonRequest) daemonResponse {
+	switch req.Op {
+	case "ping":
+		return daemonResponse{OK: true, Version: enigoma.GetVersion()}
+	case "keys":
+		entries := make([]daemonKeyEntry, len(keys))
+		for i, k := range keys {
+			entries[i] = k.daemonKeyEntry
+		}
+		return daemonResponse{OK: true, Keys: entries}
+	case "encrypt", "decrypt":
+		k, err := keys.lookup(req.Key)
+		if err != nil {
+			return daemonResponse{Error: err.Error()}
+		}
+		machine, err := k.machine.Clone()
+		if err != nil {
+			return daemonResponse{Error: fmt.Sprintf("failed to prepare key %s: %v", k.Name, err)}
+		}
+		text, _ := fitTextToAlphabet(req.Text, k.alphabet, req.KeepTrailingNewline)
+		if req.Op == "encrypt" {
+			text, err = machine.Encrypt(text)
+		} else {
+			text, err = machine.Decrypt(text)
+		}
+		if err != nil {
+			return daemonResponse{Error: fmt.Sprintf("%s failed: %v", req.Op, err)}
+		}
+		return daemonResponse{OK: true, Text: text}
+	default:
+		return daemonResponse{Error: fmt.Sprintf("unknown op %q (want ping, keys, encrypt or decrypt)", req.Op)}
+	}
+}
+
+// lookup finds a key by name or fp:<fingerprint prefix>. An empty reference
+// means the only key.
+func (keys daemonKeys) lookup(ref string) (*daemonKey, error) {
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = k.Name
+	}
+	if ref == "" {
+		if len(keys) == 1 {
+			return keys[0], nil
+		}
+		return nil, fmt.Errorf("the daemon holds %d keys; name one: %s", len(keys), strings.Join(names, ", "))
+	}
+
+	if prefix, ok := strings.CutPrefix(ref, fingerprintKeyPrefix); ok {
+		var found *daemonKey
+		for _, k := range keys {
+			if prefix != "" && strings.HasPrefix(k.Fingerprint, strings.ToLower(prefix)) {
+				if found != nil {
+					return nil, fmt.Errorf("fingerprint prefix %q matches more than one key", prefix)
+				}
+				found = k
+			}
+		}
+		if found == nil {
+			return nil, fmt.Errorf("no key with fingerprint prefix %q", prefix)
+		}
+		return found, nil
+	}
+
+	name := strings.TrimSuffix(ref, ".json")
+	for _, k := range keys {
+		if k.Name == name {
+			return k, nil
+		}
+	}
+	return nil, fmt.Errorf("no key named %q (the daemon holds: %s)", ref, strings.Join(names, ", "))
+}
+
+func runClient(cmd *cobra.Command, args []string) error {
+	op := args[0]
+	req := daemonRequest{Op: op}
+	switch op {
+	case "ping", "keys":
+	case "encrypt", "decrypt":
+		text, err := readInputText(cmd)
+		if err != nil {
+			return err
+		}
+		if text == "" {
+			return fmt.Errorf("no input provided. Use --text, --file, or pipe text to stdin")
+		}
+		req.Text = text
+		req.Key, _ = cmd.Flags().GetString("name")
+		req.KeepTrailingNewline, _ = cmd.Flags().GetBool("keep-trailing-newline")
+	default:
+		return fmt.Errorf("unknown request %q (want encrypt, decrypt, keys or ping)", op)
+	}
+
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	resp, err := sendDaemonRequest(socketPath(cmd), req, timeout)
+	if err != nil {
+		return err
+	}
+	if !resp.OK {
+		return fmt.Errorf("daemon: %s", resp.Error)
+	}
+
+	out := cmd.OutOrStdout()
+	switch op {
+	case "ping":
+		fmt.Fprintf(uiOut(cmd), "✅ Daemon is running (enigoma %s)\n", resp.Version)
+	case "keys":
+		fmt.Fprintf(out, "%-20s %-14s %s\n", "NAME", "FINGERPRINT", "DESCRIPTION")
+		for _, k := range resp.Keys {
+			fmt.Fprintf(out, "%-20s %-14s %s\n", k.Name, shortFingerprint(k.Fingerprint), k.Description)
+		}
+	default:
+		fmt.Fprint(out, resp.Text)
+	}
+	return nil
+}
+
+// sendDaemonRequest sends req to the daemon at path and reads its response.
+func sendDaemonRequest(path string, req daemonRequest, timeout time.Duration) (*daemonResponse, error) {
+	conn, err := net.DialTimeout("unix", path, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("cannot reach the daemon on %s (start one with 'enigoma daemon'): %w", path, err)
+	}
+	defer conn.Close()
+	if timeout > 0 {
+		conn.SetDeadline(time.Now().Add(timeout))
+	}
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	var resp daemonResponse
+	if err := json.NewDecoder(io.LimitReader(conn, maxDaemonRequest*2)).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("failed to read the daemon's response: %w", err)
+	}
+	return &resp, nil
+}
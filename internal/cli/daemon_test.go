@@ -0,0 +1,147 @@
+// Package cli provides unit tests for the daemon and client commands.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDaemonAndClient(t *testing.T) {
+	fsys := NewMemFS()
+	for _, name := range []string{"work", "home"} {
+		if _, err := runCLI(fsys, "keygen", "--preset", "m3", "--output", name+".json"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	socket := filepath.Join(t.TempDir(), "d.sock")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		cmd := NewRootCommand(Options{Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, FS: fsys})
+		cmd.SetArgs([]string{"daemon", "--config", "work.json", "--socket", socket})
+		done <- cmd.ExecuteContext(ctx)
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := runCLI(fsys, "client", "ping", "--socket", socket); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the daemon did not start")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	want, err := runCLI(fsys, "encrypt", "--config", "work.json", "--text", "HELLOWORLD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every request starts from the key's positions, like a separate run
+	for i := 0; i < 2; i++ {
+		got, err := runCLI(fsys, "client", "encrypt", "--socket", socket, "--text", "HELLOWORLD")
+		if err != nil || got != want {
+			t.Fatalf("client encrypt = %q, %v; want %q", got, err, want)
+		}
+	}
+	// A final newline outside the alphabet is dropped, as by decrypt
+	if got, err := runCLI(fsys, "client", "decrypt", "--socket", socket, "--name", "work", "--text", want+"\n"); err != nil || got != "HELLOWORLD" {
+		t.Errorf("client decrypt = %q, %v", got, err)
+	}
+
+	out, err := runCLI(fsys, "client", "keys", "--socket", socket)
+	if err != nil || !strings.Contains(out, "work") || strings.Contains(out, "home") {
+		t.Errorf("client keys = %q, %v; want only work", out, err)
+	}
+	for _, args := range [][]string{
+		{"client", "encrypt", "--socket", socket, "--name", "home", "--text", "A"},
+		{"client", "encrypt", "--socket", socket, "--text", "lowercase"},
+		{"client", "encrypt", "--socket", socket},
+		{"client", "reload", "--socket", socket},
+	} {
+		if _, err := runCLI(fsys, args...); err == nil {
+			t.Errorf("%v: expected an error", args)
+		}
+	}
+
+	if _, err := runCLI(fsys, "daemon", "--config", "home.json", "--socket", socket); err == nil || !strings.Contains(err.Error(), "already listening") {
+		t.Errorf("expected a second daemon to be refused, got %v", err)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("daemon returned %v", err)
+	}
+	if _, err := os.Stat(socket); !os.IsNotExist(err) {
+		t.Errorf("the socket should be removed on shutdown: %v", err)
+	}
+	if _, err := runCLI(fsys, "client", "ping", "--socket", socket); err == nil || !strings.Contains(err.Error(), "cannot reach") {
+		t.Errorf("expected an error without a daemon, got %v", err)
+	}
+}
+
+func TestDaemonKeysLookup(t *testing.T) {
+	keys := daemonKeys{
+		{daemonKeyEntry: daemonKeyEntry{Name: "a", Fingerprint: "ab12cd"}},
+		{daemonKeyEntry: daemonKeyEntry{Name: "b", Fingerprint: "ab99ff"}},
+	}
+	for ref, want := range map[string]string{"a": "a", "b.json": "b", "fp:AB99": "b"} {
+		if k, err := keys.lookup(ref); err != nil || k.Name != want {
+			t.Errorf("lookup(%q) = %v, %v; want %s", ref, k, err, want)
+		}
+	}
+	for _, ref := range []string{"", "c", "fp:ab", "fp:ff", "fp:"} {
+		if _, err := keys.lookup(ref); err == nil {
+			t.Errorf("lookup(%q): expected an error", ref)
+		}
+	}
+	if k, err := keys[:1].lookup(""); err != nil || k.Name != "a" {
+		t.Errorf("the only key should be the default, got %v, %v", k, err)
+	}
+
+	if resp := keys.handle(daemonRequest{Op: "shutdown"}); resp.OK || !strings.Contains(resp.Error, "unknown op") {
+		t.Errorf("handle(shutdown) = %+v", resp)
+	}
+}
+
+func TestListenSocketReplacesStaleSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stale.sock")
+	// A socket left behind by a daemon that was killed
+	stale, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale.SetUnlinkOnClose(false)
+	stale.Close()
+
+	ln, err := listenSocket(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	if info, err := os.Stat(path); err != nil || info.Mode()&os.ModeSocket == 0 || info.Mode().Perm() != 0600 {
+		t.Errorf("socket = %v, %v; want a private socket", info, err)
+	}
+}
+
+func TestListenSocketKeepsOtherFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key.json")
+	if err := os.WriteFile(path, []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := listenSocket(path); err == nil || !strings.Contains(err.Error(), "not a socket") {
+		t.Errorf("listenSocket on a regular file = %v, want a refusal", err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "{}" {
+		t.Errorf("the file should be left alone, got %q, %v", data, err)
+	}
+}
@@ -8,17 +8,17 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"fmt"
-	"io"
-	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
 )
 
-var decryptCmd = &cobra.Command{
-	Use:   "decrypt",
-	Short: "Decrypt text or files using an Enigma machine",
-	Long: `Decrypt ciphertext using a configured Enigma machine.
+// newDecryptCommand creates the decrypt command.
+func newDecryptCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "decrypt",
+		Short: "Decrypt text or files using an Enigma machine",
+		Long: `Decrypt ciphertext using a configured Enigma machine.
 
 IMPORTANT: Always use the same configuration file that was used for encryption!
 
@@ -29,6 +29,19 @@ RECOMMENDED WORKFLOW:
   # Step 2: Decrypt with the same config  
   enigoma decrypt --text "ENCRYPTED_OUTPUT" --config my-key.json
 
+AUTOMATIC KEY LOOKUP:
+  enigoma decrypt --file encrypted.txt         # Uses encrypted.txt.key or a
+                                               # matching config in the same folder
+
+SHARED SECRET:
+  enigoma decrypt --text "CIPHER" --shared-secret-env ENIGOMA_SECRET
+  enigoma decrypt --text "CIPHER" --shared-secret-env ENIGOMA_SECRET --shared-secret-date 2025-03-14
+  # Pass the sender's UTC day when decrypting an older message
+
+PASSPHRASE:
+  enigoma decrypt --text "CIPHER" --passphrase-file secret.txt
+  # Use the sender's --security and --alphabet
+
 INPUT METHODS:
   enigoma decrypt --text "CIPHER"              # Direct text
   enigoma decrypt --file encrypted.txt         # From file
@@ -38,52 +51,95 @@ INPUT FORMATS:
   enigoma decrypt --text "CIPHER" --config key.json                    # Plain text
   enigoma decrypt --text "48656c6c6f" --format hex --config key.json   # Hex input
   enigoma decrypt --text "SGVsbG8=" --format base64 --config key.json  # Base64 input
+  enigoma decrypt --file msg.enig --config key.json                    # .enig container
+  enigoma decrypt --file message.html --config key.json                # HTML export
+
+BINARY FILES:
+  enigoma decrypt --binary --file photo.jpg.enc --output photo.jpg --config bytes-key.json
+
+SPLIT MESSAGES:
+  enigoma decrypt --config key.json --file msg.1.txt msg.2.txt msg.3.txt
+  enigoma decrypt --config key.json --file parts.txt   # all parts in one stream
+  # Parts written by 'encrypt --split' are put back in order; a missing part is an error
+
+  Containers created with --verify-plaintext are checked automatically, so a wrong
+  key or wrong rotor positions produce an error instead of garbage output.
 
 TROUBLESHOOTING:
   • "Character not found" error? Use the config file from encryption
   • Different result than expected? Check you're using the right config file
   • Spaces in cipher text? They may not belong - try --remove-spaces
+  • "Output does not look like natural text"? Check the key, the --format, and
+    that the text was not encrypted twice (--no-heuristics disables the check)
 
 LEGACY MODE (not recommended):
   enigoma decrypt --text "CIPHER" --preset classic  # Unreliable - presets are random`,
-	RunE: runDecrypt,
-}
+		RunE: runDecrypt,
+	}
 
-func init() {
 	// Input options
-	decryptCmd.Flags().StringP("text", "t", "", "Text to decrypt")
-	decryptCmd.Flags().StringP("file", "f", "", "File to decrypt")
-	decryptCmd.Flags().StringP("output", "o", "", "Output file (default: stdout)")
+	cmd.Flags().StringP("text", "t", "", "Text to decrypt")
+	cmd.Flags().StringP("file", "f", "", "File to decrypt")
+	cmd.Flags().StringP("output", "o", "", "Output file (default: stdout)")
+	cmd.Flags().Bool("binary", false, "Decrypt the raw bytes of --file or stdin written by 'encrypt --binary'")
 
 	// Machine configuration
-	decryptCmd.Flags().StringP("preset", "p", "", "Use a preset configuration (classic, simple, high, extreme)")
-	decryptCmd.Flags().StringP("alphabet", "a", "auto", "Alphabet to use (auto, latin, greek, cyrillic, portuguese, ascii, alphanumeric)")
-	decryptCmd.Flags().StringP("security", "s", "medium", "Security level (low, medium, high, extreme)")
+	cmd.Flags().StringP("preset", "p", "", fmt.Sprintf("Use a preset configuration (%s)", strings.Join(presetNames(), ", ")))
+	cmd.Flags().StringP("alphabet", "a", "auto", "Alphabet to use ("+alphabetNameList(true)+")")
+	addAlphabetFileFlag(cmd)
+	cmd.Flags().StringP("security", "s", "medium", "Security level (low, medium, high, extreme)")
 
 	// Advanced options
-	decryptCmd.Flags().StringSliceP("rotors", "r", nil, "Rotor positions (e.g., 1,5,12)")
-	decryptCmd.Flags().StringSliceP("plugboard", "", nil, "Plugboard pairs (e.g., A:Z,B:Y)")
-	decryptCmd.Flags().BoolP("reset", "", false, "Reset machine to initial state before decryption")
+	addRotorPositionsFlag(cmd)
+	addRotorOverrideFlags(cmd)
+	cmd.Flags().StringSliceP("plugboard", "", nil, "Plugboard pairs (e.g., A:Z,B:Y, or 1:26,2:25 with --notation numbers)")
+	cmd.Flags().StringSlice("ring-settings", nil, "Ring settings, numbered from 1 (e.g., 1,5,20 or A,E,T)")
+	addNotationFlag(cmd, notationIndex)
+	cmd.Flags().BoolP("reset", "", false, "Reset machine to initial state before decryption")
+	cmd.Flags().Bool("enforce-expiry", false, "Fail instead of warning when the key has expired")
+	addSharedSecretFlags(cmd)
 
 	// Input preprocessing (for legacy workflows)
-	decryptCmd.Flags().BoolP("remove-spaces", "", false, "Remove spaces from input text")
-	decryptCmd.Flags().BoolP("uppercase", "", false, "Convert input to uppercase")
-	decryptCmd.Flags().BoolP("letters-only", "", false, "Keep only letters (A-Z, a-z)")
-	decryptCmd.Flags().BoolP("alphanumeric-only", "", false, "Keep only letters and numbers")
+	cmd.Flags().BoolP("remove-spaces", "", false, "Remove spaces from input text")
+	cmd.Flags().BoolP("uppercase", "", false, "Convert input to uppercase")
+	cmd.Flags().BoolP("letters-only", "", false, "Keep only letters (A-Z, a-z)")
+	cmd.Flags().BoolP("alphanumeric-only", "", false, "Keep only letters and numbers")
+	cmd.Flags().Bool("preserve-case", false, "Restore the case of ciphertext encrypted with --preserve-case by a key that does not record it")
+	addInputCleanupFlags(cmd)
 
 	// Input format
-	decryptCmd.Flags().StringP("format", "", "text", "Input format (text, hex, base64)")
+	cmd.Flags().StringP("format", "", "text", "Input format (text, hex, base64, enig, html)")
+
+	// Reporting
+	cmd.Flags().Bool("summary", false, "Print rotor travel statistics to stderr after decrypting")
+	cmd.Flags().Bool("json", false, "Print the summary as JSON (implies --summary)")
+	cmd.Flags().Bool("no-heuristics", false, "Don't warn when the output does not look like natural text")
+
+	return cmd
 }
 
 func runDecrypt(cmd *cobra.Command, args []string) error {
 	setupVerbose(cmd)
 
+	if binary, _ := cmd.Flags().GetBool("binary"); binary {
+		return runBinary(cmd, false)
+	}
+	if err := checkAlphabetFileFlag(cmd); err != nil {
+		return err
+	}
+
 	// Get input text
-	text, err := getInputTextForDecrypt(cmd)
+	text, err := getInputTextForDecrypt(cmd, args)
 	if err != nil {
 		return fmt.Errorf("failed to get input text: %v", err)
 	}
 
+	// Unwrap .enig containers (detected automatically)
+	container, text, err := unwrapContainer(cmd, text)
+	if err != nil {
+		return fmt.Errorf("failed to read container: %v", err)
+	}
+
 	if text == "" {
 		return fmt.Errorf("no input text provided. Use --text, --file, or pipe to stdin")
 	}
@@ -95,11 +151,33 @@ func runDecrypt(cmd *cobra.Command, args []string) error {
 	if err := prevalidateOperation(cmd, text); err != nil {
 		return err
 	}
+	if err := validatePlugboardFlag(cmd); err != nil {
+		return err
+	}
 
 	// Create Enigma machine
-	machine, err := createMachineFromFlags(cmd, text)
+	machine, err := createMachineForDecrypt(cmd, container, text)
 	if err != nil {
-		return enhanceDecryptionError(err, text, cmd)
+		return enhanceDecryptionError(err, text, "", cmd)
+	}
+	if err := applyPreserveCaseFlag(cmd, machine); err != nil {
+		return err
+	}
+	if err := applyRotorOverrides(cmd, machine); err != nil {
+		return err
+	}
+
+	// Surface non-fatal conditions, then warn about (or reject) expired keys
+	if err := reportMachineWarnings(cmd, machine); err != nil {
+		return err
+	}
+	if err := checkKeyExpiry(cmd, machine); err != nil {
+		return err
+	}
+
+	// Drop a file's final newline and invisible characters the key doesn't use
+	if text, err = fitInputToAlphabet(cmd, text, machine); err != nil {
+		return err
 	}
 
 	// Reset machine if requested
@@ -109,48 +187,64 @@ func runDecrypt(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Identify the key before the rotors move
+	fingerprint, err := machine.Fingerprint()
+	if err != nil {
+		return fmt.Errorf("failed to fingerprint configuration: %v", err)
+	}
+	verboseKeyInfo(cmd, machine, fingerprint)
+
 	// Decrypt text (same as encrypt due to Enigma's reciprocal nature)
 	decrypted, err := machine.Decrypt(text)
 	if err != nil {
-		return enhanceDecryptionError(err, text, cmd)
+		return enhanceDecryptionError(err, text, fingerprint, cmd)
 	}
 
-	// Write output (decrypt always outputs as text)
-	return writeOutput(decrypted, cmd)
-}
+	// Confirm the result against the container's plaintext check
+	if err := verifyContainerPlaintext(cmd, container, decrypted); err != nil {
+		return err
+	}
 
-func getInputTextForDecrypt(cmd *cobra.Command) (string, error) {
-	// Check for direct text input
-	if text, _ := cmd.Flags().GetString("text"); text != "" {
-		return parseInputFormat(text, cmd)
+	// Warn when the result looks like ciphertext rather than language
+	if err := checkDecryptedOutput(cmd, machine, container, decrypted); err != nil {
+		return err
 	}
 
-	// Check for file input
-	if filename, _ := cmd.Flags().GetString("file"); filename != "" {
-		data, err := os.ReadFile(filename)
-		if err != nil {
-			return "", fmt.Errorf("failed to read file %s: %w", filename, err)
-		}
-		return parseInputFormat(string(data), cmd)
+	// Write output (decrypt always outputs as text)
+	if err := writeOutput(decrypted, cmd); err != nil {
+		return err
 	}
 
-	// Read from stdin if piped
-	if stat, err := os.Stdin.Stat(); err == nil && (stat.Mode()&os.ModeCharDevice) == 0 {
-		data, err := io.ReadAll(os.Stdin)
-		if err != nil {
-			return "", fmt.Errorf("failed to read stdin: %w", err)
+	return maybeWriteSummary(cmd, "decrypted", machine, text, decrypted)
+}
+
+// getInputTextForDecrypt reads the ciphertext, reassembles it when it was
+// split into parts, and decodes its --format. Part files beyond --file are
+// given as arguments.
+func getInputTextForDecrypt(cmd *cobra.Command, args []string) (string, error) {
+	var text string
+	var err error
+	inputFile, _ := cmd.Flags().GetString("file")
+	inputText, _ := cmd.Flags().GetString("text")
+	if len(args) == 0 || inputFile != "" || inputText != "" {
+		if text, err = readInputText(cmd); err != nil {
+			return "", err
 		}
-		return parseInputFormat(string(data), cmd)
 	}
-
-	return "", nil
+	if text, err = readPartFiles(cmd, text, args); err != nil || text == "" {
+		return "", err
+	}
+	if text, err = joinParts(text); err != nil {
+		return "", err
+	}
+	return parseInputFormat(text, cmd)
 }
 
 func parseInputFormat(text string, cmd *cobra.Command) (string, error) {
 	format, _ := cmd.Flags().GetString("format")
 
 	switch strings.ToLower(format) {
-	case "text", "":
+	case "text", "", "enig", "html":
 		return text, nil
 	case "hex":
 		decoded, err := hex.DecodeString(strings.TrimSpace(text))
@@ -165,7 +259,7 @@ func parseInputFormat(text string, cmd *cobra.Command) (string, error) {
 		}
 		return string(decoded), nil
 	default:
-		return "", fmt.Errorf("unknown format: %s. Available: text, hex, base64", format)
+		return "", fmt.Errorf("unknown format: %s. Available: text, hex, base64, enig, html", format)
 	}
 }
 
@@ -180,14 +274,16 @@ func preprocessInputForDecrypt(cmd *cobra.Command, text string) string {
 	result = applyCharacterFilteringDecrypt(cmd, result)
 
 	if verbose, _ := cmd.Flags().GetBool("verbose"); verbose && result != text {
-		fmt.Fprintf(cmd.ErrOrStderr(), "Input preprocessed: %q -> %q\n", text, result)
+		fmt.Fprintf(cmd.ErrOrStderr(), "Input preprocessed: %s\n", quotedPreview(cmd, text, 20))
+		fmt.Fprintf(cmd.ErrOrStderr(), "                 -> %s\n", quotedPreview(cmd, result, 20))
 	}
 
 	return result
 }
 
 // enhanceDecryptionError provides helpful suggestions when decryption fails
-func enhanceDecryptionError(err error, text string, cmd *cobra.Command) error {
+// and names the key by fingerprint when it is known.
+func enhanceDecryptionError(err error, text, fingerprint string, cmd *cobra.Command) error {
 	errStr := err.Error()
 
 	// Check for character not found in alphabet errors
@@ -214,7 +310,7 @@ func enhanceDecryptionError(err error, text string, cmd *cobra.Command) error {
 		}
 
 		suggestionText := strings.Join(suggestions, "\n")
-		return fmt.Errorf("decryption failed: %v\n\nSuggestions:\n%s", err, suggestionText)
+		return fmt.Errorf("decryption failed%s: %v\n\nSuggestions:\n%s", keyLabel(fingerprint), err, suggestionText)
 	}
 
 	return fmt.Errorf("decryption failed: %v", err)
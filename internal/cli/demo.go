@@ -13,10 +13,12 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var demoCmd = &cobra.Command{
-	Use:   "demo",
-	Short: "Interactive demonstration of enigoma features",
-	Long: `Interactive demonstration showing enigoma's key features and capabilities.
+// newDemoCommand creates the demo command.
+func newDemoCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "demo",
+		Short: "Interactive demonstration of enigoma features",
+		Long: `Interactive demonstration showing enigoma's key features and capabilities.
 
 This command runs a series of demonstrations to help you understand:
 • Basic encryption and decryption
@@ -27,32 +29,49 @@ This command runs a series of demonstrations to help you understand:
 
 Perfect for new users to see enigoma in action!
 
+Use --typing-speed to watch ciphertext appear one keystroke at a time,
+as an operator reading lamps would have seen it.
+
 Example:
-  enigoma demo`,
-	RunE: runDemo,
+  enigoma demo
+  enigoma demo --typing-speed 8`,
+		RunE: runDemo,
+	}
+
+	cmd.Flags().Float64("typing-speed", 0, "Simulated typing rate in characters per second (0 disables)")
+	cmd.Flags().Float64("typing-jitter", 0.3, "Random variation applied to each keystroke delay (0-1)")
+
+	return cmd
 }
 
 func runDemo(cmd *cobra.Command, args []string) error {
-	fmt.Printf("🎯 Welcome to the enigoma Interactive Demo!\n")
-	fmt.Printf("Version: %s\n\n", enigoma.GetVersion())
+	out := uiOut(cmd)
+	typingSpeed, _ := cmd.Flags().GetFloat64("typing-speed")
+	typingJitter, _ := cmd.Flags().GetFloat64("typing-jitter")
+	typist := newTypingSimulator(out, typingSpeed, typingJitter)
+
+	fmt.Fprintf(out, "🎯 Welcome to the enigoma Interactive Demo!\n")
+	fmt.Fprintf(out, "Version: %s\n\n", enigoma.GetVersion())
 
 	// Demo 1: Basic Encryption
-	fmt.Println("📝 Demo 1: Basic Encryption & Decryption")
-	fmt.Println("=========================================")
+	fmt.Fprintln(out, "📝 Demo 1: Basic Encryption & Decryption")
+	fmt.Fprintln(out, "=========================================")
 
 	message := "HELLOWORLD"
-	fmt.Printf("Original message: %q\n", message)
+	fmt.Fprintf(out, "Original message: %q\n", message)
 
 	machine, err := enigma.NewEnigmaClassic()
 	if err != nil {
 		return fmt.Errorf("failed to create machine: %v", err)
 	}
 
-	encrypted, err := machine.Encrypt(message)
+	encrypted, err := typist.typeOut(machine, "Typing:    ", func() (string, error) {
+		return machine.Encrypt(message)
+	})
 	if err != nil {
 		return fmt.Errorf("encryption failed: %v", err)
 	}
-	fmt.Printf("Encrypted: %q\n", encrypted)
+	fmt.Fprintf(out, "Encrypted: %q\n", encrypted)
 
 	if err := machine.Reset(); err != nil {
 		return fmt.Errorf("failed to reset machine: %v", err)
@@ -61,17 +80,17 @@ func runDemo(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("decryption failed: %v", err)
 	}
-	fmt.Printf("Decrypted: %q\n", decrypted)
-	fmt.Printf("✅ Round-trip successful: %t\n\n", message == decrypted)
+	fmt.Fprintf(out, "Decrypted: %q\n", decrypted)
+	fmt.Fprintf(out, "✅ Round-trip successful: %t\n\n", message == decrypted)
 
 	time.Sleep(1 * time.Second)
 
 	// Demo 2: Unicode Support
-	fmt.Println("🌍 Demo 2: Unicode & Multi-Language Support")
-	fmt.Println("===========================================")
+	fmt.Fprintln(out, "🌍 Demo 2: Unicode & Multi-Language Support")
+	fmt.Fprintln(out, "===========================================")
 
 	unicodeMessage := "Olá! Привет! 日本語! 🌟"
-	fmt.Printf("Unicode message: %q\n", unicodeMessage)
+	fmt.Fprintf(out, "Unicode message: %q\n", unicodeMessage)
 
 	// Use auto-detection for Unicode
 	unicodeMachine, err := enigma.NewFromText(unicodeMessage, enigma.Medium)
@@ -79,11 +98,13 @@ func runDemo(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create Unicode machine: %v", err)
 	}
 
-	encryptedUnicode, err := unicodeMachine.Encrypt(unicodeMessage)
+	encryptedUnicode, err := typist.typeOut(unicodeMachine, "Typing:    ", func() (string, error) {
+		return unicodeMachine.Encrypt(unicodeMessage)
+	})
 	if err != nil {
 		return fmt.Errorf("Unicode encryption failed: %v", err)
 	}
-	fmt.Printf("Encrypted: %q\n", encryptedUnicode)
+	fmt.Fprintf(out, "Encrypted: %q\n", encryptedUnicode)
 
 	if err := unicodeMachine.Reset(); err != nil {
 		return fmt.Errorf("failed to reset Unicode machine: %v", err)
@@ -92,22 +113,22 @@ func runDemo(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("Unicode decryption failed: %v", err)
 	}
-	fmt.Printf("Decrypted: %q\n", decryptedUnicode)
-	fmt.Printf("✅ Unicode round-trip successful: %t\n", unicodeMessage == decryptedUnicode)
-	fmt.Printf("📊 Auto-detected alphabet size: %d characters\n\n", unicodeMachine.GetAlphabetSize())
+	fmt.Fprintf(out, "Decrypted: %q\n", decryptedUnicode)
+	fmt.Fprintf(out, "✅ Unicode round-trip successful: %t\n", unicodeMessage == decryptedUnicode)
+	fmt.Fprintf(out, "📊 Auto-detected alphabet size: %d characters\n\n", unicodeMachine.GetAlphabetSize())
 
 	time.Sleep(1 * time.Second)
 
 	// Demo 3: Security Levels
-	fmt.Println("🛡️  Demo 3: Security Levels")
-	fmt.Println("===========================")
+	fmt.Fprintln(out, "🛡️  Demo 3: Security Levels")
+	fmt.Fprintln(out, "===========================")
 
 	testMessage := "SECRETMESSAGE"
 	levels := []enigma.SecurityLevel{enigma.Low, enigma.Medium, enigma.High, enigma.Extreme}
 	levelNames := []string{"Low", "Medium", "High", "Extreme"}
 
 	for i, level := range levels {
-		fmt.Printf("%s Security:\n", levelNames[i])
+		fmt.Fprintf(out, "%s Security:\n", levelNames[i])
 
 		secMachine, err := enigma.New(
 			enigma.WithAlphabet(enigoma.AlphabetLatinUpper),
@@ -117,59 +138,59 @@ func runDemo(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("failed to create %s security machine: %v", levelNames[i], err)
 		}
 
-		fmt.Printf("  • Rotors: %d\n", secMachine.GetRotorCount())
-		fmt.Printf("  • Plugboard pairs: %d\n", secMachine.GetPlugboardPairCount())
+		fmt.Fprintf(out, "  • Rotors: %d\n", secMachine.GetRotorCount())
+		fmt.Fprintf(out, "  • Plugboard pairs: %d\n", secMachine.GetPlugboardPairCount())
 
 		secEncrypted, _ := secMachine.Encrypt(testMessage)
-		fmt.Printf("  • Encrypted: %q\n", secEncrypted)
+		fmt.Fprintf(out, "  • Encrypted: %q\n", secEncrypted)
 
 		if err := secMachine.Reset(); err != nil {
 			return fmt.Errorf("failed to reset %s security machine: %v", levelNames[i], err)
 		}
 		secDecrypted, _ := secMachine.Decrypt(secEncrypted)
-		fmt.Printf("  • ✅ Round-trip: %t\n\n", testMessage == secDecrypted)
+		fmt.Fprintf(out, "  • ✅ Round-trip: %t\n\n", testMessage == secDecrypted)
 	}
 
 	time.Sleep(1 * time.Second)
 
 	// Demo 4: Convenience Functions
-	fmt.Println("⚡ Demo 4: Zero-Config Convenience Functions")
-	fmt.Println("==========================================")
+	fmt.Fprintln(out, "⚡ Demo 4: Zero-Config Convenience Functions")
+	fmt.Fprintln(out, "==========================================")
 
 	quickMessage := "Quick encryption test"
-	fmt.Printf("Message: %q\n", quickMessage)
+	fmt.Fprintf(out, "Message: %q\n", quickMessage)
 
 	// Use the new convenience function
 	quickEncrypted, quickConfig, err := enigma.EncryptText(quickMessage)
 	if err != nil {
 		return fmt.Errorf("quick encryption failed: %v", err)
 	}
-	fmt.Printf("Encrypted: %q\n", quickEncrypted)
-	fmt.Printf("Config size: %d bytes\n", len(quickConfig))
+	fmt.Fprintf(out, "Encrypted: %q\n", quickEncrypted)
+	fmt.Fprintf(out, "Config size: %d bytes\n", len(quickConfig))
 
 	// Decrypt using the config
 	quickDecrypted, err := enigma.DecryptWithConfig(quickEncrypted, quickConfig)
 	if err != nil {
 		return fmt.Errorf("quick decryption failed: %v", err)
 	}
-	fmt.Printf("Decrypted: %q\n", quickDecrypted)
-	fmt.Printf("✅ Zero-config round-trip: %t\n\n", quickMessage == quickDecrypted)
+	fmt.Fprintf(out, "Decrypted: %q\n", quickDecrypted)
+	fmt.Fprintf(out, "✅ Zero-config round-trip: %t\n\n", quickMessage == quickDecrypted)
 
 	// Summary
-	fmt.Println("🎉 Demo Complete!")
-	fmt.Println("================")
-	fmt.Println("You've seen:")
-	fmt.Println("• ✅ Basic encryption/decryption")
-	fmt.Println("• ✅ Unicode and multi-language support")
-	fmt.Println("• ✅ Different security levels")
-	fmt.Println("• ✅ Zero-config convenience functions")
-	fmt.Println()
-	fmt.Println("Next steps:")
-	fmt.Println("• Try: enigoma wizard (interactive setup)")
-	fmt.Println("• Try: enigoma examples (copy-paste ready examples)")
-	fmt.Println("• Try: enigoma encrypt --text \"Your text\" --auto-config key.json")
-	fmt.Println()
-	fmt.Println("Happy encrypting! 🔐")
+	fmt.Fprintln(out, "🎉 Demo Complete!")
+	fmt.Fprintln(out, "================")
+	fmt.Fprintln(out, "You've seen:")
+	fmt.Fprintln(out, "• ✅ Basic encryption/decryption")
+	fmt.Fprintln(out, "• ✅ Unicode and multi-language support")
+	fmt.Fprintln(out, "• ✅ Different security levels")
+	fmt.Fprintln(out, "• ✅ Zero-config convenience functions")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Next steps:")
+	fmt.Fprintln(out, "• Try: enigoma wizard (interactive setup)")
+	fmt.Fprintln(out, "• Try: enigoma examples (copy-paste ready examples)")
+	fmt.Fprintln(out, "• Try: enigoma encrypt --text \"Your text\" --auto-config key.json")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "🔐 Happy encrypting!")
 
 	return nil
 }
@@ -6,8 +6,7 @@ package cli
 
 import (
 	"fmt"
-	"io"
-	"os"
+	"strconv"
 	"strings"
 
 	"encoding/base64"
@@ -19,10 +18,12 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var encryptCmd = &cobra.Command{
-	Use:   "encrypt",
-	Short: "Encrypt text or files using an Enigma machine",
-	Long: `Encrypt plaintext using a configured Enigma machine.
+// newEncryptCommand creates the encrypt command.
+func newEncryptCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "encrypt",
+		Short: "Encrypt text or files using an Enigma machine",
+		Long: `Encrypt plaintext using a configured Enigma machine.
 
 QUICK START (Recommended):
   enigoma encrypt --text "Hello World!" --auto-config my-key.json
@@ -36,6 +37,10 @@ HANDLING SPECIAL CHARACTERS:
   • Mixed case? Use --auto-config or --uppercase with presets  
   • Special symbols? Use --auto-config or --alphabet ascii
 
+With --auto-config (or --save-config) and --output, a small "<output>.key"
+sidecar is written next to the ciphertext so that 'enigoma decrypt --file'
+can find the matching configuration by itself.
+
 INPUT METHODS:
   enigoma encrypt --text "Hello World"           # Direct text
   enigoma encrypt --file input.txt               # From file
@@ -47,51 +52,148 @@ CONFIGURATION OPTIONS:
   enigoma encrypt --text "Hello" --alphabet ascii         # Manual alphabet
   enigoma encrypt --text "Hello" --config existing.json   # Existing config
 
+ACCENTS AND EMOJI:
+  enigoma encrypt --text "Café 👍🏽" --auto-config key.json --graphemes
+  # Each user-perceived character (é, 👍🏽) is one symbol, however many
+  # codepoints it is written with
+  enigoma encrypt --text "Café" --auto-config key.json --normalize nfc
+  # "é" typed precomposed or with a combining accent is the same character
+
+CHINESE AND JAPANESE TEXT:
+  enigoma encrypt --text "こんにちは" --transliterate romaji --auto-config key.json
+  enigoma encrypt --file zh.txt --transliterate pinyin --transliterate-table pinyin.txt --auto-config key.json
+  # One-way: decryption returns the romanized text, not the original script
+
+SHARED SECRET (no key files):
+  export ENIGOMA_SECRET="correct horse battery staple"
+  enigoma encrypt --text "Meet at noon" --shared-secret-env ENIGOMA_SECRET
+  enigoma decrypt --text "..." --shared-secret-env ENIGOMA_SECRET --shared-secret-date 2025-03-14
+  # Both sides derive the same machine for the UTC day; it changes daily
+
+PASSPHRASE (no key files):
+  enigoma encrypt --text "Meet at noon" --passphrase-file secret.txt
+  enigoma decrypt --text "..." --passphrase-file secret.txt
+  # The same passphrase, --security and --alphabet always give the same machine
+
+MULTIPLE RECIPIENTS:
+  enigoma encrypt --file memo.txt --config-list keys/*.json --output-dir out/
+  # One output per key, named by the key's fingerprint (e.g. out/3f9a0c1b2d4e.txt)
+
+CONTAINER OUTPUT:
+  enigoma encrypt --text "HELLO" --config key.json --output msg.enig
+  enigoma encrypt --text "HELLO" --config key.json --output msg.enig --verify-plaintext
+
+  --verify-plaintext stores a salted hash of the plaintext so decrypt can detect a
+  wrong key or wrong rotor positions. Anyone holding the container can test guesses
+  offline, so avoid it for short or predictable messages.
+
+HTML EXPORT:
+  make wasm
+  enigoma encrypt --text "HELLO" --config key.json --output message.html --wasm-dir build/wasm
+  # A standalone page showing the key fingerprint and the container that decrypts
+  # in the browser with the key file; 'enigoma decrypt --file message.html' reads it too
+
+SEVERAL FORMATS AT ONCE:
+  enigoma encrypt --text "HELLO" --config key.json --format text,hex,base64 --output msg
+  # Writes msg.txt, msg.hex and msg.b64 from a single encryption pass, so all
+  # three hold the same ciphertext (enig adds msg.enig)
+
+SPLIT FOR TRANSMISSION:
+  enigoma encrypt --file memo.txt --config key.json --split 140
+  enigoma encrypt --file memo.txt --config key.json --split 140 --output msg.txt
+  # Parts of at most 140 characters, each headed "(2/5)"; with --output one file
+  # per part (msg.1.txt, msg.2.txt, ...). decrypt reassembles them
+  enigoma encrypt --file memo.txt --config key.json --split 140 --chain
+  # Each header also carries a hash linking the part to the one before it, so
+  # decrypt names any part that was altered or swapped
+
+BINARY FILES:
+  enigoma encrypt --binary --file photo.jpg --output photo.jpg.enc --save-config bytes-key.json
+  enigoma decrypt --binary --file photo.jpg.enc --output photo.jpg --config bytes-key.json
+  # Enciphers raw bytes with a key over all 256 byte values ('keygen --alphabet bytes')
+
+ROTORS BY ID:
+  enigoma encrypt --file memo.txt --config key.json --set-position I=Q --set-ring III=5
+  # Changes only the named rotors, whatever their number and order in the key
+
 PREPROCESSING (for presets):
   --remove-spaces     Remove spaces from input
   --uppercase         Convert to uppercase  
   --letters-only      Keep only A-Z, a-z
-  --alphanumeric-only Keep only letters and numbers`,
-	RunE: runEncrypt,
-}
+  --alphanumeric-only Keep only letters and numbers
+  --preserve-case     Encipher lowercase on an uppercase key, keeping the case`,
+		RunE: runEncrypt,
+	}
 
-func init() {
 	// Input options
-	encryptCmd.Flags().StringP("text", "t", "", "Text to encrypt")
-	encryptCmd.Flags().StringP("file", "f", "", "File to encrypt")
-	encryptCmd.Flags().StringP("output", "o", "", "Output file (default: stdout)")
+	cmd.Flags().StringP("text", "t", "", "Text to encrypt")
+	cmd.Flags().StringP("file", "f", "", "File to encrypt")
+	cmd.Flags().StringP("output", "o", "", "Output file (default: stdout)")
+	cmd.Flags().Bool("binary", false, "Encrypt the raw bytes of --file or stdin with a byte-mode key (images, archives)")
 
 	// Machine configuration
-	encryptCmd.Flags().StringP("preset", "p", "", "Use a preset configuration (classic, simple, high, extreme)")
-	encryptCmd.Flags().StringP("alphabet", "a", "auto", "Alphabet to use (auto, latin, greek, cyrillic, portuguese, ascii, alphanumeric)")
-	encryptCmd.Flags().StringP("security", "s", "medium", "Security level (low, medium, high, extreme)")
+	cmd.Flags().StringP("preset", "p", "", fmt.Sprintf("Use a preset configuration (%s)", strings.Join(presetNames(), ", ")))
+	cmd.Flags().StringP("alphabet", "a", "auto", "Alphabet to use ("+alphabetNameList(true)+")")
+	addAlphabetFileFlag(cmd)
+	cmd.Flags().String("alphabet-order", "codepoint", "Order of an auto-detected alphabet (codepoint, frequency, encountered)")
+	cmd.Flags().String("padding-strategy", "codepoint", "How an odd-sized auto-detected alphabet is made even (codepoint, list, drop, merge)")
+	cmd.Flags().String("padding-chars", "", "Padding candidates, tried in order, for --padding-strategy list (implies it)")
+	cmd.Flags().Bool("graphemes", false, "Auto-detect an alphabet of grapheme clusters, so accented letters and emoji are enciphered whole")
+	cmd.Flags().String("normalize", "none", "Unicode normalization of an auto-detected alphabet and every text it enciphers (none, nfc, nfd)")
+	cmd.Flags().StringP("security", "s", "medium", "Security level (low, medium, high, extreme)")
 
 	// Advanced options
-	encryptCmd.Flags().StringSliceP("rotors", "r", nil, "Rotor positions (e.g., 1,5,12)")
-	encryptCmd.Flags().StringSliceP("plugboard", "", nil, "Plugboard pairs (e.g., A:Z,B:Y)")
-	encryptCmd.Flags().BoolP("reset", "", false, "Reset machine to initial state before encryption")
+	addRotorPositionsFlag(cmd)
+	addRotorOverrideFlags(cmd)
+	cmd.Flags().StringSliceP("plugboard", "", nil, "Plugboard pairs (e.g., A:Z,B:Y, or 1:26,2:25 with --notation numbers)")
+	cmd.Flags().StringSlice("ring-settings", nil, "Ring settings, numbered from 1 (e.g., 1,5,20 or A,E,T)")
+	addNotationFlag(cmd, notationIndex)
+	cmd.Flags().BoolP("reset", "", false, "Reset machine to initial state before encryption")
+	cmd.Flags().Bool("enforce-expiry", false, "Fail instead of warning when the key has expired")
+	addSharedSecretFlags(cmd)
 
 	// Configuration workflow
-	encryptCmd.Flags().String("auto-config", "", "Auto-detect alphabet from input and save configuration to file")
-	encryptCmd.Flags().String("save-config", "", "Save generated configuration to file (used with --preset or manual settings)")
+	cmd.Flags().String("auto-config", "", "Auto-detect alphabet from input and save configuration to file")
+	cmd.Flags().String("save-config", "", "Save generated configuration to file (used with --preset or manual settings)")
+	cmd.Flags().StringSlice("config-list", nil, "Encrypt for several recipients, one configuration each (files or glob patterns)")
+	cmd.Flags().String("output-dir", "", "Directory for --config-list outputs, named by key fingerprint")
 
 	// Input preprocessing
-	encryptCmd.Flags().BoolP("remove-spaces", "", false, "Remove spaces from input text")
-	encryptCmd.Flags().BoolP("uppercase", "", false, "Convert input to uppercase")
-	encryptCmd.Flags().BoolP("letters-only", "", false, "Keep only letters (A-Z, a-z)")
-	encryptCmd.Flags().BoolP("alphanumeric-only", "", false, "Keep only letters and numbers")
+	cmd.Flags().BoolP("remove-spaces", "", false, "Remove spaces from input text")
+	cmd.Flags().BoolP("uppercase", "", false, "Convert input to uppercase")
+	cmd.Flags().BoolP("letters-only", "", false, "Keep only letters (A-Z, a-z)")
+	cmd.Flags().BoolP("alphanumeric-only", "", false, "Keep only letters and numbers")
+	addTransliterateFlags(cmd)
+	addInputCleanupFlags(cmd)
 
 	// Output formatting
-	encryptCmd.Flags().StringP("format", "", "text", "Output format (text, hex, base64)")
-	encryptCmd.Flags().BoolP("preserve-case", "", false, "Preserve original case (when possible)")
+	cmd.Flags().StringP("format", "", "text", "Output format (text, hex, base64, enig, html), or a comma-separated list written to one file each")
+	cmd.Flags().String("wasm-dir", "", "Directory with enigoma.wasm and wasm_exec.js from 'make wasm', embedded in HTML output so it decrypts in the browser; required for HTML (or set "+wasmDirEnv+")")
+	cmd.Flags().Bool("verify-plaintext", false, "Store a salted plaintext hash in the .enig container so decryption can be verified")
+	cmd.Flags().Int("split", 0, "Split the output into numbered parts of at most this many characters")
+	cmd.Flags().Bool("chain", false, "Link --split parts with a hash chain so decrypt detects altered or swapped parts")
+	cmd.Flags().BoolP("preserve-case", "", false, "Encipher lowercase letters on an uppercase alphabet and keep their case in the output (saved with the configuration)")
+
+	// Reporting
+	cmd.Flags().Bool("summary", false, "Print rotor travel statistics to stderr after encrypting")
+	cmd.Flags().Bool("json", false, "Print the summary as JSON (implies --summary)")
+
+	return cmd
 }
 
 // nolint:gocyclo // This function handles multiple encryption paths
 func runEncrypt(cmd *cobra.Command, args []string) error {
 	setupVerbose(cmd)
 
+	if binary, _ := cmd.Flags().GetBool("binary"); binary {
+		return runBinary(cmd, true)
+	}
+	if err := checkAlphabetFileFlag(cmd); err != nil {
+		return err
+	}
+
 	// Get input text
-	text, err := getInputText(cmd)
+	text, err := readInputText(cmd)
 	if err != nil {
 		return fmt.Errorf("failed to get input text: %v", err)
 	}
@@ -100,6 +202,12 @@ func runEncrypt(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no input text provided. Use --text, --file, or pipe to stdin")
 	}
 
+	// Romanize CJK input so it fits a small alphabet
+	text, err = applyTransliteration(cmd, text)
+	if err != nil {
+		return err
+	}
+
 	// Apply input preprocessing
 	text = preprocessInput(cmd, text)
 
@@ -112,16 +220,53 @@ func runEncrypt(cmd *cobra.Command, args []string) error {
 	if err := prevalidateOperation(cmd, text); err != nil {
 		return err
 	}
+	formats, err := outputFormats(cmd)
+	if err != nil {
+		return err
+	}
+	if err := validateMultiFormat(cmd, formats); err != nil {
+		return err
+	}
+	if err := validateSplit(cmd, formats); err != nil {
+		return err
+	}
+	if err := validateRotorOverrides(cmd); err != nil {
+		return err
+	}
+	if err := validatePlugboardFlag(cmd); err != nil {
+		return err
+	}
+
+	// Broadcast to several recipients, each with their own configuration
+	configs, err := recipientConfigs(cmd, args)
+	if err != nil {
+		return err
+	}
+	if len(configs) > 0 {
+		return encryptForRecipients(cmd, text, configs)
+	}
 
 	// Create Enigma machine with configuration-first workflow
 	var machine *enigma.Enigma
 
 	// 1) Use explicit config if provided
 	if configFile, _ := cmd.Flags().GetString("config"); configFile != "" {
-		machine, err = createMachineFromConfig(configFile)
+		machine, err = createMachineFromConfig(fileSystem(cmd), configOptionsFor(cmd), configFile)
 		if err != nil {
 			return fmt.Errorf("failed to create Enigma machine: %v", err)
 		}
+	} else if hasPassphraseFlag(cmd) {
+		// Machine derived from a passphrase, no key file needed
+		machine, err = createMachineFromPassphraseFlags(cmd)
+		if err != nil {
+			return fmt.Errorf("failed to derive Enigma machine: %v", err)
+		}
+	} else if envName, _ := cmd.Flags().GetString("shared-secret-env"); envName != "" {
+		// Daily machine derived from a shared secret, no key file needed
+		machine, err = createMachineFromSharedSecret(cmd, envName)
+		if err != nil {
+			return fmt.Errorf("failed to derive Enigma machine: %v", err)
+		}
 	} else if autoConfigPath, _ := cmd.Flags().GetString("auto-config"); autoConfigPath != "" {
 		// 2) Auto-generate configuration from input text
 		machine, err = createMachineWithAutoConfig(cmd, text, autoConfigPath)
@@ -130,21 +275,54 @@ func runEncrypt(cmd *cobra.Command, args []string) error {
 		}
 	} else if preset, _ := cmd.Flags().GetString("preset"); preset != "" {
 		// 3) Preset (optionally save config)
-		machine, err = createMachineFromPreset(preset)
+		machine, err = presetMachine(cmd, preset)
 		if err != nil {
 			return fmt.Errorf("failed to create Enigma machine: %v", err)
 		}
-		if savePath, _ := cmd.Flags().GetString("save-config"); savePath != "" {
-			if err := saveMachineConfig(machine, savePath); err != nil {
-				return fmt.Errorf("failed to save configuration: %v", err)
-			}
+		if err := applyPositionFlags(cmd, machine, "rotors"); err != nil {
+			return err
+		}
+		if err := applyPreserveCaseFlag(cmd, machine); err != nil {
+			return err
+		}
+		if err := saveConfigIfRequested(cmd, machine); err != nil {
+			return err
 		}
 	} else {
-		// 4) Manual flags
+		// 4) Manual flags (optionally save config)
 		machine, err = createMachineFromSettings(cmd, text)
 		if err != nil {
 			return fmt.Errorf("failed to create Enigma machine: %v", err)
 		}
+		if err := applyPreserveCaseFlag(cmd, machine); err != nil {
+			return err
+		}
+		if err := saveConfigIfRequested(cmd, machine); err != nil {
+			return err
+		}
+	}
+
+	// Fold case on keys loaded or derived above, too
+	if err := applyPreserveCaseFlag(cmd, machine); err != nil {
+		return err
+	}
+
+	// Set individual rotors by ID
+	if err := applyRotorOverrides(cmd, machine); err != nil {
+		return err
+	}
+
+	// Surface non-fatal conditions, then warn about (or reject) expired keys
+	if err := reportMachineWarnings(cmd, machine); err != nil {
+		return err
+	}
+	if err := checkKeyExpiry(cmd, machine); err != nil {
+		return err
+	}
+
+	// Drop a file's final newline and invisible characters the key can't encrypt
+	if text, err = fitInputToAlphabet(cmd, text, machine); err != nil {
+		return err
 	}
 
 	// Reset machine if requested
@@ -154,111 +332,110 @@ func runEncrypt(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Fingerprint the key before the rotors move, for containers and sidecars
+	fingerprint, err := machine.Fingerprint()
+	if err != nil {
+		return fmt.Errorf("failed to fingerprint configuration: %v", err)
+	}
+	verboseKeyInfo(cmd, machine, fingerprint)
+
 	// Encrypt text
 	encrypted, err := machine.Encrypt(text)
 	if err != nil {
-		return enhanceEncryptionError(err, text, cmd)
+		return enhanceEncryptionError(err, text, fingerprint, cmd)
+	}
+
+	// Several formats share this one encryption pass
+	if len(formats) > 1 {
+		if err := writeMultiFormat(cmd, formats, encrypted, text, fingerprint); err != nil {
+			return err
+		}
+		return maybeWriteSummary(cmd, "encrypted", machine, text, encrypted)
 	}
 
-	// Format output
-	formatted, err := formatOutput(encrypted, cmd)
+	// Format output, wrapping it in a .enig container or an HTML page if requested
+	var formatted string
+	if wantsHTML(cmd) {
+		formatted, err = buildHTMLExport(cmd, encrypted, text, fingerprint)
+	} else if wantsContainer(cmd) {
+		formatted, err = buildContainer(cmd, encrypted, text, fingerprint)
+	} else {
+		formatted, err = formatOutput(encrypted, cmd)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to format output: %v", err)
 	}
 
-	// Write output
-	return writeOutput(formatted, cmd)
-}
-
-func getInputText(cmd *cobra.Command) (string, error) {
-	// Check for direct text input
-	if text, _ := cmd.Flags().GetString("text"); text != "" {
-		return text, nil
+	// Split for transmission, writing each part's sidecar itself
+	if split, _ := cmd.Flags().GetInt("split"); split > 0 {
+		if err := writeSplitOutput(cmd, formatted, fingerprint); err != nil {
+			return err
+		}
+		return maybeWriteSummary(cmd, "encrypted", machine, text, encrypted)
 	}
 
-	// Check for file input
-	if filename, _ := cmd.Flags().GetString("file"); filename != "" {
-		data, err := os.ReadFile(filename)
-		if err != nil {
-			return "", fmt.Errorf("failed to read file %s: %w", filename, err)
-		}
-		return string(data), nil
+	// Write output
+	if err := writeOutput(formatted, cmd); err != nil {
+		return err
 	}
 
-	// Read from stdin if piped
-	if stat, err := os.Stdin.Stat(); err == nil && (stat.Mode()&os.ModeCharDevice) == 0 {
-		data, err := io.ReadAll(os.Stdin)
-		if err != nil {
-			return "", fmt.Errorf("failed to read stdin: %w", err)
-		}
-		return string(data), nil
+	// Point decrypt at the generated configuration
+	if err := maybeWriteSidecar(cmd, fingerprint); err != nil {
+		return err
 	}
 
-	return "", nil
+	return maybeWriteSummary(cmd, "encrypted", machine, text, encrypted)
 }
 
 func createMachineFromFlags(cmd *cobra.Command, inputText string) (*enigma.Enigma, error) {
 	// Check if config file is specified
 	if configFile, _ := cmd.Flags().GetString("config"); configFile != "" {
-		return createMachineFromConfig(configFile)
+		return createMachineFromConfig(fileSystem(cmd), configOptionsFor(cmd), configFile)
+	}
+
+	// Check for a passphrase or shared secret (decrypt only; keygen has no such flags)
+	if hasPassphraseFlag(cmd) {
+		return createMachineFromPassphraseFlags(cmd)
+	}
+	if envName, _ := cmd.Flags().GetString("shared-secret-env"); envName != "" {
+		return createMachineFromSharedSecret(cmd, envName)
 	}
 
 	// Check for preset
 	if preset, _ := cmd.Flags().GetString("preset"); preset != "" {
-		return createMachineFromPreset(preset)
+		machine, err := presetMachine(cmd, preset)
+		if err != nil {
+			return nil, err
+		}
+		if err := applyPositionFlags(cmd, machine, "rotors"); err != nil {
+			return nil, err
+		}
+		return machine, nil
 	}
 
 	// Create machine from individual flags
 	return createMachineFromSettings(cmd, inputText)
 }
 
-func createMachineFromConfig(configFile string) (*enigma.Enigma, error) {
-	data, err := os.ReadFile(configFile)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %v", err)
-	}
-
-	return enigma.NewFromJSON(string(data))
+func createMachineFromConfig(fsys FS, opts configOptions, configFile string) (*enigma.Enigma, error) {
+	return loadConfig(fsys, opts, configFile)
 }
 
+// createMachineFromPreset creates the machine of a built-in preset or of one
+// a program registered with enigma.RegisterPreset.
 func createMachineFromPreset(preset string) (*enigma.Enigma, error) {
-	switch strings.ToLower(preset) {
-	case "classic":
-		return enigma.NewEnigmaClassic()
-	case "m3":
-		return enigma.NewEnigmaM3()
-	case "m4":
-		return enigma.NewEnigmaM4()
-	case "simple":
-		return enigma.NewEnigmaSimple(enigoma.AlphabetLatinUpper)
-	case "low":
-		return enigma.New(
-			enigma.WithAlphabet(enigoma.AlphabetLatinUpper),
-			enigma.WithRandomSettings(enigma.Low),
-		)
-	case "medium":
-		return enigma.New(
-			enigma.WithAlphabet(enigoma.AlphabetLatinUpper),
-			enigma.WithRandomSettings(enigma.Medium),
-		)
-	case "high":
-		return enigma.New(
-			enigma.WithAlphabet(enigoma.AlphabetLatinUpper),
-			enigma.WithRandomSettings(enigma.High),
-		)
-	case "extreme":
-		return enigma.New(
-			enigma.WithAlphabet(enigoma.AlphabetLatinUpper),
-			enigma.WithRandomSettings(enigma.Extreme),
-		)
-	default:
-		return nil, fmt.Errorf("unknown preset: %s. Available: classic, m3, m4, simple, low, medium, high, extreme", preset)
+	if info := findPreset(preset); info != nil {
+		preset = info.Name // Resolve aliases
+	}
+	if _, ok := enigma.LookupPreset(preset); !ok {
+		return nil, fmt.Errorf("unknown preset: %s. Available: %s", preset, strings.Join(presetNames(), ", "))
 	}
+	return enigma.NewFromPreset(preset)
 }
 
 func createMachineFromSettings(cmd *cobra.Command, inputText string) (*enigma.Enigma, error) {
 	// Get alphabet
-	alphabet, err := getAlphabetFromFlag(cmd, inputText)
+	alphabetOpt, err := getAlphabetFromFlag(cmd, inputText)
 	if err != nil {
 		return nil, err
 	}
@@ -270,23 +447,31 @@ func createMachineFromSettings(cmd *cobra.Command, inputText string) (*enigma.En
 	}
 
 	// Create machine with basic settings
-	machine, err := enigma.New(
-		enigma.WithAlphabet(alphabet),
-		enigma.WithRandomSettings(securityLevel),
-	)
+	opts := []enigma.Option{alphabetOpt}
+	if noReflector, _ := cmd.Flags().GetBool("no-reflector"); noReflector {
+		opts = append(opts, enigma.WithoutReflector())
+	}
+	if fixedPoints, _ := cmd.Flags().GetInt("reflector-fixed-points"); fixedPoints != 0 {
+		opts = append(opts, enigma.WithReflectorFixedPoints(fixedPoints))
+	}
+	if noPlugboard, _ := cmd.Flags().GetBool("no-plugboard"); noPlugboard {
+		opts = append(opts, enigma.WithoutPlugboard())
+	}
+	if cmd.Flags().Changed("seed") {
+		// keygen --seed derives the whole machine, not just its positions
+		seed, _ := cmd.Flags().GetInt64("seed")
+		opts = append(opts, enigma.WithDeterministicRandomSettings(securityLevel, seed))
+	} else {
+		opts = append(opts, enigma.WithRandomSettings(securityLevel))
+	}
+	machine, err := enigma.New(opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	// Apply rotor positions if specified
-	if rotorPositions, _ := cmd.Flags().GetStringSlice("rotors"); len(rotorPositions) > 0 {
-		positions, err := parseRotorPositions(rotorPositions)
-		if err != nil {
-			return nil, fmt.Errorf("invalid rotor positions: %v", err)
-		}
-		if err := machine.SetRotorPositions(positions); err != nil {
-			return nil, fmt.Errorf("failed to set rotor positions: %v", err)
-		}
+	// Apply rotor positions and plugboard pairs if specified
+	if err := applyPositionFlags(cmd, machine, "rotors"); err != nil {
+		return nil, err
 	}
 
 	if v, _ := cmd.Flags().GetBool("verbose"); v {
@@ -295,7 +480,19 @@ func createMachineFromSettings(cmd *cobra.Command, inputText string) (*enigma.En
 	return machine, nil
 }
 
-func getAlphabetFromFlag(cmd *cobra.Command, inputText string) ([]rune, error) {
+// getAlphabetFromFlag returns the option setting the --alphabet alphabet,
+// auto-detected from inputText for "auto".
+func getAlphabetFromFlag(cmd *cobra.Command, inputText string) (enigma.Option, error) {
+	if spec, _ := cmd.Flags().GetString("alphabet-ranges"); spec != "" {
+		return alphabetFromRanges(spec)
+	}
+	if path, _ := cmd.Flags().GetString("alphabet-file"); path != "" {
+		custom, err := alphabetFromFile(cmd, path)
+		if err != nil {
+			return nil, err
+		}
+		return enigma.WithAlphabet(custom.Runes), nil
+	}
 	alphabetName, _ := cmd.Flags().GetString("alphabet")
 
 	switch strings.ToLower(alphabetName) {
@@ -303,39 +500,99 @@ func getAlphabetFromFlag(cmd *cobra.Command, inputText string) ([]rune, error) {
 		if inputText == "" {
 			return nil, fmt.Errorf("alphabet=auto requires input text. Provide --text/--file or pipe via stdin, or use --auto-config to save a reusable configuration")
 		}
-		detected, err := alphabet.AutoDetectFromText(inputText)
+		detectOpts, err := detectOptionsFromFlags(cmd)
 		if err != nil {
-			return nil, fmt.Errorf("auto-detect alphabet: %w", err)
+			return nil, err
 		}
-		if v, _ := cmd.Flags().GetBool("verbose"); v {
-			fmt.Fprintf(cmd.ErrOrStderr(), "Auto-detected alphabet size: %d\n", detected.Size())
-		}
-		return detected.Runes(), nil
-	case "latin", "latin-upper":
-		return enigoma.AlphabetLatinUpper, nil
-	case "latin-lower":
-		return enigoma.AlphabetLatinLower, nil
-	case "greek":
-		return enigoma.AlphabetGreek, nil
-	case "cyrillic":
-		return enigoma.AlphabetCyrillic, nil
-	case "portuguese":
-		return enigoma.AlphabetPortuguese, nil
-	case "ascii":
-		return enigoma.AlphabetASCIIPrintable, nil
-	case "alphanumeric":
-		return enigoma.AlphabetAlphaNumeric, nil
-	case "digits":
-		return enigoma.AlphabetDigits, nil
+		return func(e *enigma.Enigma) error {
+			if err := enigma.WithDetectedAlphabet(inputText, detectOpts...)(e); err != nil {
+				return err
+			}
+			if v, _ := cmd.Flags().GetBool("verbose"); v {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Auto-detected alphabet size: %d\n", e.GetAlphabetSize())
+			}
+			return nil
+		}, nil
 	default:
-		return nil, fmt.Errorf("unknown alphabet: %s. Available: auto, latin, greek, cyrillic, portuguese, ascii, alphanumeric, digits", alphabetName)
+		predefined, ok := enigoma.LookupAlphabet(alphabetName)
+		if !ok {
+			return nil, fmt.Errorf("unknown alphabet: %s. Available: %s (see 'enigoma alphabet list')", alphabetName, alphabetNameList(true))
+		}
+		return enigma.WithAlphabet(predefined.Runes), nil
 	}
 }
 
-func getSecurityLevelFromFlag(cmd *cobra.Command) (enigma.SecurityLevel, error) {
-	securityName, _ := cmd.Flags().GetString("security")
+// alphabetFromRanges builds the alphabet of --alphabet-ranges, refusing one
+// too large for the saved configuration to load again.
+func alphabetFromRanges(spec string) (enigma.Option, error) {
+	runes, err := enigoma.ParseAlphabetRanges(spec)
+	if err != nil {
+		return nil, fmt.Errorf("--alphabet-ranges: %v", err)
+	}
+	if max := enigma.DefaultLimits.MaxAlphabetSize; len(runes) > max {
+		return nil, fmt.Errorf("--alphabet-ranges: %q has %d characters, more than the %d a configuration may hold; narrow it with \"&\", e.g. \"Cyrillic&L\"", spec, len(runes), max)
+	}
+	return enigma.WithAlphabet(runes), nil
+}
 
-	switch strings.ToLower(securityName) {
+// detectOptionsFromFlags reads the auto-detection flags: --alphabet-order,
+// --padding-strategy, --padding-chars, --graphemes and --normalize, and
+// --preserve-case where the command has it.
+func detectOptionsFromFlags(cmd *cobra.Command) ([]enigma.DetectOption, error) {
+	orderName, _ := cmd.Flags().GetString("alphabet-order")
+	ordering, err := enigma.ParseAlphabetOrdering(orderName)
+	if err != nil {
+		return nil, err
+	}
+	strategyName, _ := cmd.Flags().GetString("padding-strategy")
+	strategy, err := enigma.ParsePaddingStrategy(strategyName)
+	if err != nil {
+		return nil, err
+	}
+	opts := []enigma.DetectOption{enigma.WithAlphabetOrdering(ordering), enigma.WithPaddingStrategy(strategy)}
+
+	chars, _ := cmd.Flags().GetString("padding-chars")
+	switch {
+	case chars != "":
+		if cmd.Flags().Changed("padding-strategy") && strategy != enigma.PadFromList {
+			return nil, fmt.Errorf("--padding-chars only applies to --padding-strategy list")
+		}
+		opts = append(opts, enigma.WithPaddingCandidates([]rune(chars)))
+	case strategy == enigma.PadFromList:
+		return nil, fmt.Errorf("--padding-strategy list requires --padding-chars")
+	}
+	if graphemes, _ := cmd.Flags().GetBool("graphemes"); graphemes {
+		opts = append(opts, enigma.WithGraphemeClusters())
+	}
+	formName, _ := cmd.Flags().GetString("normalize")
+	form, err := enigma.ParseNormalization(formName)
+	if err != nil {
+		return nil, err
+	}
+	if form != enigma.NormalizationNone {
+		opts = append(opts, enigma.WithTextNormalization(form))
+	}
+	if preserve, _ := cmd.Flags().GetBool("preserve-case"); preserve {
+		opts = append(opts, enigma.WithFoldedCase())
+	}
+	return opts, nil
+}
+
+// applyPreserveCaseFlag turns on case folding for --preserve-case, so that
+// lowercase letters encipher on an uppercase alphabet and keep their case.
+func applyPreserveCaseFlag(cmd *cobra.Command, machine *enigma.Enigma) error {
+	if preserve, _ := cmd.Flags().GetBool("preserve-case"); !preserve || machine.IsCaseFolding() {
+		return nil
+	}
+	if err := machine.SetCaseFolding(true); err != nil {
+		return fmt.Errorf("--preserve-case: %v", err)
+	}
+	return nil
+}
+
+// parseSecurityLevel converts a security level name.
+func parseSecurityLevel(name string) (enigma.SecurityLevel, error) {
+	switch strings.ToLower(name) {
 	case "low":
 		return enigma.Low, nil
 	case "medium":
@@ -345,31 +602,22 @@ func getSecurityLevelFromFlag(cmd *cobra.Command) (enigma.SecurityLevel, error)
 	case "extreme":
 		return enigma.Extreme, nil
 	default:
-		return enigma.Medium, fmt.Errorf("unknown security level: %s. Available: low, medium, high, extreme", securityName)
-	}
-}
-
-func parseRotorPositions(positions []string) ([]int, error) {
-	result := make([]int, len(positions))
-	for i, pos := range positions {
-		var err error
-		result[i], err = parseIntFromString(pos)
-		if err != nil {
-			return nil, fmt.Errorf("invalid position '%s': %v", pos, err)
-		}
+		return enigma.Medium, fmt.Errorf("unknown security level: %s. Available: low, medium, high, extreme", name)
 	}
-	return result, nil
 }
 
-func parseIntFromString(s string) (int, error) {
-	var result int
-	_, err := fmt.Sscanf(strings.TrimSpace(s), "%d", &result)
-	return result, err
+func getSecurityLevelFromFlag(cmd *cobra.Command) (enigma.SecurityLevel, error) {
+	securityName, _ := cmd.Flags().GetString("security")
+	return parseSecurityLevel(securityName)
 }
 
 func formatOutput(text string, cmd *cobra.Command) (string, error) {
 	format, _ := cmd.Flags().GetString("format")
+	return formatCiphertext(text, format)
+}
 
+// formatCiphertext encodes ciphertext as text, hex or base64.
+func formatCiphertext(text, format string) (string, error) {
 	switch strings.ToLower(format) {
 	case "text", "":
 		return text, nil
@@ -378,7 +626,7 @@ func formatOutput(text string, cmd *cobra.Command) (string, error) {
 	case "base64":
 		return base64.StdEncoding.EncodeToString([]byte(text)), nil
 	default:
-		return "", fmt.Errorf("unknown format: %s. Available: text, hex, base64", format)
+		return "", fmt.Errorf("unknown format: %s. Available: text, hex, base64, enig, html", format)
 	}
 }
 
@@ -390,63 +638,66 @@ func writeOutput(text string, cmd *cobra.Command) error {
 		return nil
 	}
 
-	return os.WriteFile(outputFile, []byte(text), 0600)
+	return fileSystem(cmd).WriteFile(outputFile, []byte(text), 0600)
 }
 
 // createMachineWithAutoConfig builds an Enigma machine by auto-detecting the alphabet
 // from the provided text, applies random settings per selected security level, and saves
 // the resulting configuration JSON to the provided path.
 func createMachineWithAutoConfig(cmd *cobra.Command, text string, savePath string) (*enigma.Enigma, error) {
-	// Auto-detect alphabet from input text
-	detectedAlphabet, err := alphabet.AutoDetectFromText(text)
+	// Get security level
+	securityLevel, err := getSecurityLevelFromFlag(cmd)
 	if err != nil {
-		return nil, fmt.Errorf("auto-detect alphabet: %w", err)
+		return nil, err
 	}
 
-	// Get security level
-	securityLevel, err := getSecurityLevelFromFlag(cmd)
+	detectOpts, err := detectOptionsFromFlags(cmd)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create machine
-	machine, err := enigma.New(
-		enigma.WithAlphabet(detectedAlphabet.Runes()),
-		enigma.WithRandomSettings(securityLevel),
-	)
+	// Auto-detect alphabet from input text; adjustments become machine warnings
+	machine, err := enigma.NewFromText(text, securityLevel, detectOpts...)
 	if err != nil {
 		return nil, err
 	}
 
-	// Apply rotor positions if specified
-	if rotorPositions, _ := cmd.Flags().GetStringSlice("rotors"); len(rotorPositions) > 0 {
-		positions, err := parseRotorPositions(rotorPositions)
-		if err != nil {
-			return nil, fmt.Errorf("invalid rotor positions: %v", err)
-		}
-		if err := machine.SetRotorPositions(positions); err != nil {
-			return nil, fmt.Errorf("failed to set rotor positions: %v", err)
-		}
+	// Apply rotor positions and plugboard pairs if specified
+	if err := applyPositionFlags(cmd, machine, "rotors"); err != nil {
+		return nil, err
 	}
 
 	// Save configuration
-	if err := saveMachineConfig(machine, savePath); err != nil {
+	if err := saveMachineConfig(fileSystem(cmd), configOptionsFor(cmd), machine, savePath); err != nil {
 		return nil, err
 	}
 
 	if v, _ := cmd.Flags().GetBool("verbose"); v {
-		fmt.Fprintf(cmd.ErrOrStderr(), "Auto-detected alphabet with %d characters\n", len(detectedAlphabet.Runes()))
+		fmt.Fprintf(cmd.ErrOrStderr(), "Auto-detected alphabet with %d characters\n", machine.GetAlphabetSize())
 		fmt.Fprintf(cmd.ErrOrStderr(), "Auto-generated configuration saved to: %s\n", savePath)
 	}
 	return machine, nil
 }
 
-func saveMachineConfig(machine *enigma.Enigma, path string) error {
+// saveConfigIfRequested saves a preset or generated machine to --save-config,
+// after the flags that adjust it have been applied.
+func saveConfigIfRequested(cmd *cobra.Command, machine *enigma.Enigma) error {
+	savePath, _ := cmd.Flags().GetString("save-config")
+	if savePath == "" {
+		return nil
+	}
+	if err := saveMachineConfig(fileSystem(cmd), configOptionsFor(cmd), machine, savePath); err != nil {
+		return fmt.Errorf("failed to save configuration: %v", err)
+	}
+	return nil
+}
+
+func saveMachineConfig(fsys FS, opts configOptions, machine *enigma.Enigma, path string) error {
 	jsonData, err := machine.SaveSettingsToJSON()
 	if err != nil {
 		return fmt.Errorf("serialize configuration: %w", err)
 	}
-	if err := os.WriteFile(path, []byte(jsonData), 0600); err != nil {
+	if err := writeConfigFile(fsys, opts, path, jsonData); err != nil {
 		return fmt.Errorf("write configuration to %s: %w", path, err)
 	}
 	return nil
@@ -463,14 +714,16 @@ func preprocessInput(cmd *cobra.Command, text string) string {
 	result = applyCharacterFiltering(cmd, result)
 
 	if verbose, _ := cmd.Flags().GetBool("verbose"); verbose && result != text {
-		fmt.Fprintf(cmd.ErrOrStderr(), "Input preprocessed: %q -> %q\n", text, result)
+		fmt.Fprintf(cmd.ErrOrStderr(), "Input preprocessed: %s\n", quotedPreview(cmd, text, 20))
+		fmt.Fprintf(cmd.ErrOrStderr(), "                 -> %s\n", quotedPreview(cmd, result, 20))
 	}
 
 	return result
 }
 
 // enhanceEncryptionError provides helpful suggestions when encryption fails
-func enhanceEncryptionError(err error, text string, cmd *cobra.Command) error {
+// and names the key by fingerprint when it is known.
+func enhanceEncryptionError(err error, text, fingerprint string, cmd *cobra.Command) error {
 	errStr := err.Error()
 
 	// Check for character not found in alphabet errors
@@ -484,7 +737,7 @@ func enhanceEncryptionError(err error, text string, cmd *cobra.Command) error {
 
 		if preset != "" && preset != "auto" {
 			suggestions = append(suggestions, fmt.Sprintf("• Preset '%s' uses a limited alphabet. Try --auto-config instead:", preset))
-			suggestions = append(suggestions, fmt.Sprintf("  enigoma encrypt --text %q --auto-config my-key.json", text))
+			suggestions = append(suggestions, fmt.Sprintf("  enigoma encrypt %s --auto-config my-key.json", suggestedInputArg(cmd, text)))
 		}
 
 		if alphabet == "latin" || alphabet == "latin-upper" {
@@ -498,7 +751,7 @@ func enhanceEncryptionError(err error, text string, cmd *cobra.Command) error {
 		// Always suggest auto-config as the simplest solution
 		if len(suggestions) == 0 {
 			suggestions = append(suggestions, "• Try auto-detecting the alphabet:")
-			suggestions = append(suggestions, fmt.Sprintf("  enigoma encrypt --text %q --auto-config my-key.json", text))
+			suggestions = append(suggestions, fmt.Sprintf("  enigoma encrypt %s --auto-config my-key.json", suggestedInputArg(cmd, text)))
 		}
 
 		// Add preprocessing suggestions
@@ -510,12 +763,21 @@ func enhanceEncryptionError(err error, text string, cmd *cobra.Command) error {
 		}
 
 		suggestionText := strings.Join(suggestions, "\n")
-		return fmt.Errorf("encryption failed: %v\n\nSuggestions:\n%s", err, suggestionText)
+		return fmt.Errorf("encryption failed%s: %v\n\nSuggestions:\n%s", keyLabel(fingerprint), err, suggestionText)
 	}
 
 	return fmt.Errorf("encryption failed: %v", err)
 }
 
+// suggestedInputArg quotes short text for a copy-paste suggestion; text too
+// long for one line is replaced by a --file placeholder.
+func suggestedInputArg(cmd *cobra.Command, text string) string {
+	if quoted := quotedPreview(cmd, text, 48); quoted == strconv.Quote(text) {
+		return "--text " + quoted
+	}
+	return "--file input.txt"
+}
+
 // hasLowercase checks if the text contains lowercase letters
 func hasLowercase(text string) bool {
 	for _, r := range text {
@@ -6,14 +6,18 @@ package cli
 
 import (
 	"fmt"
+	"io"
 
+	"github.com/coredds/enigoma/pkg/enigma"
 	"github.com/spf13/cobra"
 )
 
-var examplesCmd = &cobra.Command{
-	Use:   "examples",
-	Short: "Show copy-paste ready examples for common use cases",
-	Long: `Show copy-paste ready examples for common enigoma use cases.
+// newExamplesCommand creates the examples command.
+func newExamplesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "examples",
+		Short: "Show copy-paste ready examples for common use cases",
+		Long: `Show copy-paste ready examples for common enigoma use cases.
 
 This command provides practical examples you can copy and paste to get started quickly.
 All examples are tested and ready to use!
@@ -25,138 +29,217 @@ Categories:
 • File operations
 • Advanced configurations
 
+Use --run to execute tutorials end to end instead of just printing them. Each
+one generates its keys, encrypts, decrypts and checks the result in a
+temporary directory, printing every command and file as it goes; the command
+fails if any result is wrong. Available: ` + runnableExampleNames() + `.
+--keep leaves the directory in place for a closer look.
+
+Use --rotor-events (or --run rotor-events) to run a live example that consumes
+the rotor event callbacks (stepping, turnover and double-step) the way a GUI
+or TUI would drive animations and sounds.
+
 Example:
-  enigoma examples`,
-	RunE: runExamples,
+  enigoma examples
+  enigoma examples --run quick-start
+  enigoma examples --run all --keep
+  enigoma examples --rotor-events`,
+		RunE: runExamples,
+	}
+
+	cmd.Flags().Bool("rotor-events", false, "Run a live example consuming rotor stepping events")
+	cmd.Flags().String("run", "", "Execute an example end to end and verify it ("+runnableExampleNames()+")")
+	cmd.Flags().Bool("keep", false, "Keep the --run work directory instead of deleting it")
+
+	return cmd
 }
 
 func runExamples(cmd *cobra.Command, args []string) error {
-	fmt.Println("📚 enigoma Copy-Paste Examples")
-	fmt.Println("==============================")
-	fmt.Println()
+	out := uiOut(cmd)
+	if rotorEvents, _ := cmd.Flags().GetBool("rotor-events"); rotorEvents {
+		return runRotorEventsExample(out)
+	}
+	if name, _ := cmd.Flags().GetString("run"); name != "" {
+		return runExamplesByName(cmd, name)
+	}
+
+	fmt.Fprintln(out, "📚 enigoma Copy-Paste Examples")
+	fmt.Fprintln(out, "==============================")
+	fmt.Fprintln(out)
 
 	// Basic Examples
-	fmt.Println("🚀 QUICK START")
-	fmt.Println("--------------")
-	fmt.Println("# Simplest possible usage (auto-detects everything):")
-	fmt.Println(`enigoma encrypt --text "Hello World!" --auto-config my-key.json`)
-	fmt.Println(`enigoma decrypt --text "ENCRYPTED_OUTPUT" --config my-key.json`)
-	fmt.Println()
-	fmt.Println("# Interactive wizard for beginners:")
-	fmt.Println(`enigoma wizard`)
-	fmt.Println()
+	fmt.Fprintln(out, "🚀 QUICK START")
+	fmt.Fprintln(out, "--------------")
+	fmt.Fprintln(out, "# Simplest possible usage (auto-detects everything):")
+	fmt.Fprintln(out, `enigoma encrypt --text "Hello World!" --auto-config my-key.json`)
+	fmt.Fprintln(out, `enigoma decrypt --text "ENCRYPTED_OUTPUT" --config my-key.json`)
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "# Interactive wizard for beginners:")
+	fmt.Fprintln(out, `enigoma wizard`)
+	fmt.Fprintln(out)
 
 	// Unicode Examples
-	fmt.Println("🌍 UNICODE & INTERNATIONAL TEXT")
-	fmt.Println("-------------------------------")
-	fmt.Println("# Portuguese with accents:")
-	fmt.Println(`enigoma encrypt --text "Olá mundo! Como você está?" --auto-config pt-key.json`)
-	fmt.Println()
-	fmt.Println("# Mixed languages:")
-	fmt.Println(`enigoma encrypt --text "Hello! Привет! 日本語! 🌟" --auto-config mixed-key.json`)
-	fmt.Println()
-	fmt.Println("# Greek text:")
-	fmt.Println(`enigoma encrypt --text "Αβγδε ζητα θικλμ" --auto-config greek-key.json`)
-	fmt.Println()
+	fmt.Fprintln(out, "🌍 UNICODE & INTERNATIONAL TEXT")
+	fmt.Fprintln(out, "-------------------------------")
+	fmt.Fprintln(out, "# Portuguese with accents:")
+	fmt.Fprintln(out, `enigoma encrypt --text "Olá mundo! Como você está?" --auto-config pt-key.json`)
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "# Mixed languages:")
+	fmt.Fprintln(out, `enigoma encrypt --text "Hello! Привет! 日本語! 🌟" --auto-config mixed-key.json`)
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "# Greek text:")
+	fmt.Fprintln(out, `enigoma encrypt --text "Αβγδε ζητα θικλμ" --auto-config greek-key.json`)
+	fmt.Fprintln(out)
 
 	// Security Examples
-	fmt.Println("🛡️  SECURITY LEVELS")
-	fmt.Println("------------------")
-	fmt.Println("# Low security (3 rotors, 2 plugboard pairs):")
-	fmt.Println(`enigoma encrypt --text "HELLO" --preset classic --save-config classic-key.json`)
-	fmt.Println()
-	fmt.Println("# High security (8 rotors, 15 plugboard pairs):")
-	fmt.Println(`enigoma keygen --security high --output high-key.json`)
-	fmt.Println(`enigoma encrypt --text "TOP SECRET" --config high-key.json`)
-	fmt.Println()
-	fmt.Println("# Maximum security (12 rotors, 20 plugboard pairs):")
-	fmt.Println(`enigoma keygen --security extreme --output extreme-key.json`)
-	fmt.Println(`enigoma encrypt --text "CLASSIFIED" --config extreme-key.json`)
-	fmt.Println()
+	fmt.Fprintln(out, "🛡️  SECURITY LEVELS")
+	fmt.Fprintln(out, "------------------")
+	fmt.Fprintln(out, "# Low security (3 rotors, 2 plugboard pairs):")
+	fmt.Fprintln(out, `enigoma encrypt --text "HELLO" --preset classic --save-config classic-key.json`)
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "# High security (8 rotors, 15 plugboard pairs):")
+	fmt.Fprintln(out, `enigoma keygen --security high --output high-key.json`)
+	fmt.Fprintln(out, `enigoma encrypt --text "TOPSECRET" --config high-key.json`)
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "# Maximum security (12 rotors, 20 plugboard pairs):")
+	fmt.Fprintln(out, `enigoma keygen --security extreme --output extreme-key.json`)
+	fmt.Fprintln(out, `enigoma encrypt --text "CLASSIFIED" --config extreme-key.json`)
+	fmt.Fprintln(out)
 
 	// File Operations
-	fmt.Println("📁 FILE OPERATIONS")
-	fmt.Println("------------------")
-	fmt.Println("# Encrypt a file:")
-	fmt.Println(`enigoma encrypt --file document.txt --auto-config doc-key.json --output encrypted.txt`)
-	fmt.Println()
-	fmt.Println("# Decrypt a file:")
-	fmt.Println(`enigoma decrypt --file encrypted.txt --config doc-key.json --output decrypted.txt`)
-	fmt.Println()
-	fmt.Println("# Pipe operations:")
-	fmt.Println(`echo "Secret message" | enigoma encrypt --auto-config pipe-key.json`)
-	fmt.Println(`echo "ENCRYPTED" | enigoma decrypt --config pipe-key.json`)
-	fmt.Println()
+	fmt.Fprintln(out, "📁 FILE OPERATIONS")
+	fmt.Fprintln(out, "------------------")
+	fmt.Fprintln(out, "# Encrypt a file:")
+	fmt.Fprintln(out, `enigoma encrypt --file document.txt --auto-config doc-key.json --output encrypted.txt`)
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "# Decrypt a file:")
+	fmt.Fprintln(out, `enigoma decrypt --file encrypted.txt --config doc-key.json --output decrypted.txt`)
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "# Pipe operations:")
+	fmt.Fprintln(out, `echo "Secret message" | enigoma encrypt --auto-config pipe-key.json`)
+	fmt.Fprintln(out, `echo "ENCRYPTED" | enigoma decrypt --config pipe-key.json`)
+	fmt.Fprintln(out)
 
 	// Output Formats
-	fmt.Println("📊 OUTPUT FORMATS")
-	fmt.Println("----------------")
-	fmt.Println("# Base64 output:")
-	fmt.Println(`enigoma encrypt --text "Hello" --auto-config key.json --format base64`)
-	fmt.Println(`enigoma decrypt --text "SGVsbG8=" --config key.json --format base64`)
-	fmt.Println()
-	fmt.Println("# Hex output:")
-	fmt.Println(`enigoma encrypt --text "Hello" --auto-config key.json --format hex`)
-	fmt.Println(`enigoma decrypt --text "48656c6c6f" --config key.json --format hex`)
-	fmt.Println()
+	fmt.Fprintln(out, "📊 OUTPUT FORMATS")
+	fmt.Fprintln(out, "----------------")
+	fmt.Fprintln(out, "# Base64 output:")
+	fmt.Fprintln(out, `enigoma encrypt --text "Hello" --auto-config key.json --format base64`)
+	fmt.Fprintln(out, `enigoma decrypt --text "SGVsbG8=" --config key.json --format base64`)
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "# Hex output:")
+	fmt.Fprintln(out, `enigoma encrypt --text "Hello" --auto-config key.json --format hex`)
+	fmt.Fprintln(out, `enigoma decrypt --text "48656c6c6f" --config key.json --format hex`)
+	fmt.Fprintln(out)
 
 	// Troubleshooting
-	fmt.Println("🔧 TROUBLESHOOTING")
-	fmt.Println("------------------")
-	fmt.Println("# If you get 'character not found' errors with presets:")
-	fmt.Println(`enigoma encrypt --text "Hello World!" --preset classic --remove-spaces --uppercase`)
-	fmt.Println("# Or better yet, use auto-config:")
-	fmt.Println(`enigoma encrypt --text "Hello World!" --auto-config key.json`)
-	fmt.Println()
-	fmt.Println("# Validate a configuration file:")
-	fmt.Println(`enigoma config --validate my-key.json`)
-	fmt.Println()
-	fmt.Println("# Test a configuration:")
-	fmt.Println(`enigoma config --test my-key.json --text "TEST MESSAGE"`)
-	fmt.Println()
+	fmt.Fprintln(out, "🔧 TROUBLESHOOTING")
+	fmt.Fprintln(out, "------------------")
+	fmt.Fprintln(out, "# If you get 'character not found' errors with presets:")
+	fmt.Fprintln(out, `enigoma encrypt --text "Hello World!" --preset classic --remove-spaces --uppercase`)
+	fmt.Fprintln(out, "# Or better yet, use auto-config:")
+	fmt.Fprintln(out, `enigoma encrypt --text "Hello World!" --auto-config key.json`)
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "# Validate a configuration file:")
+	fmt.Fprintln(out, `enigoma config --validate my-key.json`)
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "# Test a configuration:")
+	fmt.Fprintln(out, `enigoma config --test my-key.json --text "TEST MESSAGE"`)
+	fmt.Fprintln(out)
 
 	// Advanced Examples
-	fmt.Println("⚙️  ADVANCED USAGE")
-	fmt.Println("-----------------")
-	fmt.Println("# Custom alphabet:")
-	fmt.Println(`enigoma keygen --alphabet ascii --security medium --output custom-key.json`)
-	fmt.Println()
-	fmt.Println("# Historical presets:")
-	fmt.Println(`enigoma preset --describe m3`)
-	fmt.Println(`enigoma encrypt --text "ENIGMA" --preset m3 --save-config m3-key.json`)
-	fmt.Println()
-	fmt.Println("# Verbose output for debugging:")
-	fmt.Println(`enigoma encrypt --text "Debug me" --auto-config debug-key.json --verbose`)
-	fmt.Println()
+	fmt.Fprintln(out, "⚙️  ADVANCED USAGE")
+	fmt.Fprintln(out, "-----------------")
+	fmt.Fprintln(out, "# Custom alphabet:")
+	fmt.Fprintln(out, `enigoma keygen --alphabet alphanumeric --security medium --output custom-key.json`)
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "# Historical presets:")
+	fmt.Fprintln(out, `enigoma preset --describe m3`)
+	fmt.Fprintln(out, `enigoma encrypt --text "ENIGMA" --preset m3 --save-config m3-key.json`)
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "# Verbose output for debugging:")
+	fmt.Fprintln(out, `enigoma encrypt --text "Debug me" --auto-config debug-key.json --verbose`)
+	fmt.Fprintln(out)
 
 	// Library Examples
-	fmt.Println("📖 LIBRARY USAGE (Go Code)")
-	fmt.Println("--------------------------")
-	fmt.Println("```go")
-	fmt.Println("// Simplest possible usage:")
-	fmt.Println(`encrypted, config, err := enigma.EncryptText("Hello World!")`)
-	fmt.Println(`decrypted, err := enigma.DecryptWithConfig(encrypted, config)`)
-	fmt.Println()
-	fmt.Println("// Auto-detection with custom security:")
-	fmt.Println(`machine, err := enigma.NewFromText("Your text", enigma.High)`)
-	fmt.Println(`encrypted, err := machine.Encrypt("Your text")`)
-	fmt.Println()
-	fmt.Println("// Classic Enigma:")
-	fmt.Println(`machine, err := enigma.NewEnigmaClassic()`)
-	fmt.Println(`encrypted, err := machine.Encrypt("HELLO WORLD")`)
-	fmt.Println("```")
-	fmt.Println()
+	fmt.Fprintln(out, "📖 LIBRARY USAGE (Go Code)")
+	fmt.Fprintln(out, "--------------------------")
+	fmt.Fprintln(out, "```go")
+	fmt.Fprintln(out, "// Simplest possible usage:")
+	fmt.Fprintln(out, `encrypted, config, err := enigma.EncryptText("Hello World!")`)
+	fmt.Fprintln(out, `decrypted, err := enigma.DecryptWithConfig(encrypted, config)`)
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "// Auto-detection with custom security:")
+	fmt.Fprintln(out, `machine, err := enigma.NewFromText("Your text", enigma.High)`)
+	fmt.Fprintln(out, `encrypted, err := machine.Encrypt("Your text")`)
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "// Classic Enigma:")
+	fmt.Fprintln(out, `machine, err := enigma.NewEnigmaClassic()`)
+	fmt.Fprintln(out, `encrypted, err := machine.Encrypt("HELLO WORLD")`)
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "// Animate rotors or play sounds (see: enigoma examples --rotor-events):")
+	fmt.Fprintln(out, `machine.SetRotorEventCallback(func(ev enigma.RotorEvent) {`)
+	fmt.Fprintln(out, `    fmt.Println("rotor", ev.RotorID, ev.Kind, "->", string(ev.Window))`)
+	fmt.Fprintln(out, `})`)
+	fmt.Fprintln(out, "```")
+	fmt.Fprintln(out)
 
 	// Footer
-	fmt.Println("💡 TIPS")
-	fmt.Println("------")
-	fmt.Println("• Always use --auto-config for new projects (it's the easiest!)")
-	fmt.Println("• Save your configuration files - you need them to decrypt!")
-	fmt.Println("• Use --verbose to see what's happening under the hood")
-	fmt.Println("• Try 'enigoma demo' for an interactive demonstration")
-	fmt.Println("• Use 'enigoma wizard' if you're new to enigoma")
-	fmt.Println()
-	fmt.Println("🔗 More help: enigoma [command] --help")
+	fmt.Fprintln(out, "💡 TIPS")
+	fmt.Fprintln(out, "------")
+	fmt.Fprintln(out, "• Always use --auto-config for new projects (it's the easiest!)")
+	fmt.Fprintln(out, "• Save your configuration files - you need them to decrypt!")
+	fmt.Fprintln(out, "• Use --verbose to see what's happening under the hood")
+	fmt.Fprintln(out, "• Try 'enigoma demo' for an interactive demonstration")
+	fmt.Fprintln(out, "• Run 'enigoma examples --run all' to watch these examples work end to end")
+	fmt.Fprintln(out, "• Use 'enigoma wizard' if you're new to enigoma")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "🔗 More help: enigoma [command] --help")
 
 	return nil
 }
+
+// runRotorEventsExample encrypts a short message on an M3 set just before a
+// turnover and prints each rotor event as a front-end would consume it.
+func runRotorEventsExample(out io.Writer) error {
+	machine, err := enigma.NewEnigmaM3()
+	if err != nil {
+		return fmt.Errorf("failed to create M3 machine: %v", err)
+	}
+	// Window ADU: the first key carries the middle rotor onto its notch,
+	// so the second key shows the double-step anomaly.
+	if err := machine.SetRotorPositions([]int{0, 3, 20}); err != nil {
+		return fmt.Errorf("failed to set rotor positions: %v", err)
+	}
+
+	fmt.Fprintln(out, "🎛️  ROTOR EVENTS (Enigma M3, window ADU)")
+	fmt.Fprintln(out, "----------------------------------------")
+	machine.SetRotorEventCallback(func(ev enigma.RotorEvent) {
+		fmt.Fprintf(out, "  key %d  rotor %-3s %-11s window %c  %s\n",
+			ev.Index+1, ev.RotorID, ev.Kind, ev.Window, rotorEventCue(ev.Kind))
+	})
+	machine.SetStepCallback(func(info enigma.StepInfo) {
+		fmt.Fprintf(out, "  key %d  %c -> %c\n", info.Index+1, info.Input, info.Output)
+	})
+
+	if _, err := machine.Encrypt("ENIGMA"); err != nil {
+		return fmt.Errorf("failed to encrypt example text: %v", err)
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "💡 A GUI would animate on 'stepped', play a heavier click on 'turnover'")
+	fmt.Fprintln(out, "   and flag 'double-step' to explain the middle rotor's extra move.")
+	return nil
+}
+
+// rotorEventCue suggests the sound or animation a front-end might use.
+func rotorEventCue(kind enigma.RotorEventKind) string {
+	switch kind {
+	case enigma.RotorTurnover:
+		return "♪ clunk (notch carries next rotor)"
+	case enigma.RotorDoubleStep:
+		return "♪ double click (middle rotor double-steps)"
+	default:
+		return "♪ click"
+	}
+}
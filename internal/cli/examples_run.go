@@ -0,0 +1,250 @@
+// Package cli provides runnable, self-verifying examples for the examples command.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// exampleStep is one enigoma invocation of a runnable example.
+type exampleStep struct {
+	comment string   // Printed as "# ..." above the command
+	args    []string // Arguments after "enigoma"; "@name" is a file in the work directory
+	show    string   // File printed after the command runs
+}
+
+// runnableExample is a tutorial that --run executes end to end. After the
+// steps, every file in expect must hold exactly the given content.
+type runnableExample struct {
+	name   string
+	title  string
+	files  map[string]string // Written to the work directory before the steps
+	steps  []exampleStep
+	expect map[string]string
+}
+
+// runnableExamples are the tutorials available to --run, in display order.
+var runnableExamples = []runnableExample{
+	{
+		name:  "quick-start",
+		title: "Encrypt and decrypt with an auto-detected key",
+		steps: []exampleStep{
+			{"Encrypt; the alphabet is detected from the text and the key saved", []string{"encrypt", "--text", "Hello World!", "--auto-config", "@my-key.json", "--output", "@message.txt"}, "message.txt"},
+			{"Decrypt with the saved key", []string{"decrypt", "--file", "@message.txt", "--config", "@my-key.json", "--output", "@decrypted.txt"}, "decrypted.txt"},
+		},
+		expect: map[string]string{"decrypted.txt": "Hello World!"},
+	},
+	{
+		name:  "unicode",
+		title: "Mixed scripts and emoji",
+		steps: []exampleStep{
+			{"Any Unicode text works with an auto-detected alphabet", []string{"encrypt", "--text", "Hello! Привет! 日本語! 🌟", "--auto-config", "@mixed-key.json", "--output", "@message.txt"}, "message.txt"},
+			{"Decrypt with the saved key", []string{"decrypt", "--file", "@message.txt", "--config", "@mixed-key.json", "--output", "@decrypted.txt"}, "decrypted.txt"},
+		},
+		expect: map[string]string{"decrypted.txt": "Hello! Привет! 日本語! 🌟"},
+	},
+	{
+		name:  "security",
+		title: "Generate a high-security key first",
+		steps: []exampleStep{
+			{"8 rotors and 13 plugboard pairs on the Latin alphabet (A-Z)", []string{"keygen", "--security", "high", "--output", "@high-key.json"}, ""},
+			{"The Latin alphabet has no spaces, so the text has none either", []string{"encrypt", "--text", "TOPSECRET", "--config", "@high-key.json", "--output", "@message.txt"}, "message.txt"},
+			{"Decrypt with the same key", []string{"decrypt", "--file", "@message.txt", "--config", "@high-key.json", "--output", "@decrypted.txt"}, "decrypted.txt"},
+		},
+		expect: map[string]string{"decrypted.txt": "TOPSECRET"},
+	},
+	{
+		name:  "files",
+		title: "Encrypt and decrypt files",
+		files: map[string]string{"document.txt": "Meet me at the old mill at dawn.\n"},
+		steps: []exampleStep{
+			{"Encrypt a file; its trailing newline is not part of the message", []string{"encrypt", "--file", "@document.txt", "--auto-config", "@doc-key.json", "--output", "@encrypted.txt"}, "encrypted.txt"},
+			{"Decrypt it again", []string{"decrypt", "--file", "@encrypted.txt", "--config", "@doc-key.json", "--output", "@decrypted.txt"}, "decrypted.txt"},
+		},
+		expect: map[string]string{"decrypted.txt": "Meet me at the old mill at dawn."},
+	},
+	{
+		name:  "formats",
+		title: "Base64 output for email and chat",
+		steps: []exampleStep{
+			{"Base64 survives systems that mangle unusual characters", []string{"encrypt", "--text", "Hello", "--auto-config", "@key.json", "--format", "base64", "--output", "@message.b64"}, "message.b64"},
+			{"Decrypt with the same --format", []string{"decrypt", "--file", "@message.b64", "--config", "@key.json", "--format", "base64", "--output", "@decrypted.txt"}, "decrypted.txt"},
+		},
+		expect: map[string]string{"decrypted.txt": "Hello"},
+	},
+}
+
+// runnableExampleNames lists the values accepted by --run.
+func runnableExampleNames() string {
+	names := []string{"all"}
+	for _, ex := range runnableExamples {
+		names = append(names, ex.name)
+	}
+	return strings.Join(append(names, "rotor-events"), ", ")
+}
+
+// runExamplesByName runs the named example (or all of them) in a temporary
+// work directory and fails if any result differs from what it promises.
+func runExamplesByName(cmd *cobra.Command, name string) error {
+	out := uiOut(cmd)
+	if name == "rotor-events" {
+		return runRotorEventsExample(out)
+	}
+
+	var selected []runnableExample
+	for _, ex := range runnableExamples {
+		if name == "all" || ex.name == name {
+			selected = append(selected, ex)
+		}
+	}
+	if len(selected) == 0 {
+		return fmt.Errorf("unknown example: %s. Available: %s", name, runnableExampleNames())
+	}
+
+	fsys := fileSystem(cmd)
+	dir, cleanup, err := exampleWorkDir(fsys)
+	if err != nil {
+		return err
+	}
+	if keep, _ := cmd.Flags().GetBool("keep"); keep {
+		fmt.Fprintf(out, "Work directory: %s (kept)\n\n", dir)
+	} else {
+		defer cleanup()
+	}
+
+	for _, ex := range selected {
+		exDir := filepath.Join(dir, ex.name)
+		if err := fsys.MkdirAll(exDir, 0700); err != nil {
+			return fmt.Errorf("failed to create work directory: %w", err)
+		}
+		if err := runExample(cmd, fsys, exDir, ex); err != nil {
+			return fmt.Errorf("example %s failed: %v", ex.name, err)
+		}
+	}
+	return nil
+}
+
+// runExample executes one example's steps and checks its expectations.
+// Commands, their output and file contents are data and bypass uiOut, which
+// could strip emoji from them.
+func runExample(cmd *cobra.Command, fsys FS, dir string, ex runnableExample) error {
+	out, raw := uiOut(cmd), cmd.OutOrStdout()
+	fmt.Fprintf(out, "📘 %s: %s\n", ex.name, ex.title)
+	fmt.Fprintln(out, strings.Repeat("-", len(ex.name)+len(ex.title)+2))
+
+	for _, name := range sortedKeys(ex.files) {
+		content := ex.files[name]
+		if err := fsys.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		fmt.Fprintf(raw, "# %s contains: %q\n", name, content)
+	}
+
+	for _, step := range ex.steps {
+		display := make([]string, len(step.args))
+		actual := make([]string, len(step.args))
+		for i, arg := range step.args {
+			if file, ok := strings.CutPrefix(arg, "@"); ok {
+				display[i], actual[i] = file, filepath.Join(dir, file)
+			} else {
+				display[i], actual[i] = shellQuote(arg), arg
+			}
+		}
+
+		fmt.Fprintf(out, "# %s\n", step.comment)
+		fmt.Fprintf(raw, "$ enigoma %s\n", strings.Join(display, " "))
+
+		var captured bytes.Buffer
+		root := NewRootCommand(Options{In: strings.NewReader(""), Out: &captured, Err: &captured, FS: fsys})
+		root.SilenceUsage = true
+		root.SilenceErrors = true
+		root.SetArgs(actual)
+		runErr := root.Execute()
+		// Show the work directory's files by their short names
+		for _, line := range strings.Split(strings.TrimRight(captured.String(), "\n"), "\n") {
+			if line != "" {
+				fmt.Fprintf(raw, "  %s\n", strings.ReplaceAll(line, dir+string(filepath.Separator), ""))
+			}
+		}
+		if runErr != nil {
+			return runErr
+		}
+
+		if step.show != "" {
+			data, err := fsys.ReadFile(filepath.Join(dir, step.show))
+			if err != nil {
+				return fmt.Errorf("expected output file %s: %w", step.show, err)
+			}
+			fmt.Fprintf(raw, "  %s: %s\n", step.show, string(data))
+		}
+	}
+
+	for name, want := range ex.expect {
+		data, err := fsys.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return fmt.Errorf("expected output file %s: %w", name, err)
+		}
+		if string(data) != want {
+			return fmt.Errorf("%s holds %q, want %q", name, string(data), want)
+		}
+	}
+	fmt.Fprintf(out, "✅ Verified: %s\n\n", strings.Join(sortedKeys(ex.expect), ", "))
+	return nil
+}
+
+// exampleWorkDir creates a fresh directory for the examples' files. On the
+// host filesystem it is a temporary directory that cleanup removes; other
+// filesystems get a timestamped directory, which cleanup leaves in place.
+func exampleWorkDir(fsys FS) (string, func(), error) {
+	if isHostFS(fsys) {
+		dir, err := os.MkdirTemp("", "enigoma-examples-")
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to create work directory: %w", err)
+		}
+		return dir, func() { os.RemoveAll(dir) }, nil
+	}
+
+	dir := filepath.Join(os.TempDir(), "enigoma-examples-"+now().UTC().Format("20060102T150405.000000000"))
+	if err := fsys.MkdirAll(dir, 0700); err != nil {
+		return "", nil, fmt.Errorf("failed to create work directory: %w", err)
+	}
+	return dir, func() {}, nil
+}
+
+// isHostFS reports whether fsys is the operating system's filesystem.
+func isHostFS(fsys FS) bool {
+	switch f := fsys.(type) {
+	case OSFS:
+		return true
+	case noLockFS:
+		return isHostFS(f.FS)
+	}
+	return false
+}
+
+// shellQuote quotes arg for display when a shell would split or expand it.
+func shellQuote(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t\"'$!*?&|;<>()`\\") {
+		return arg
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`").Replace(arg) + `"`
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,122 @@
+// Package cli provides multi-format output for the encrypt command.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// formatExtensions maps each output format to the suffix of its file when
+// several formats are written at once.
+var formatExtensions = map[string]string{
+	"text":   ".txt",
+	"hex":    ".hex",
+	"base64": ".b64",
+	"enig":   ".enig",
+	"html":   htmlExtension,
+}
+
+// outputFormats parses --format, which holds one format or a comma-separated
+// list. Duplicates are dropped and the order is kept.
+func outputFormats(cmd *cobra.Command) ([]string, error) {
+	value, _ := cmd.Flags().GetString("format")
+	var formats []string
+	seen := make(map[string]bool)
+	for _, f := range strings.Split(value, ",") {
+		f = strings.ToLower(strings.TrimSpace(f))
+		if f == "" {
+			f = "text"
+		}
+		if _, ok := formatExtensions[f]; !ok {
+			return nil, fmt.Errorf("unknown format: %s. Available: text, hex, base64, enig, html", f)
+		}
+		if !seen[f] {
+			seen[f] = true
+			formats = append(formats, f)
+		}
+	}
+	return formats, nil
+}
+
+// validateMultiFormat checks the flags that must accompany several formats,
+// before anything is encrypted or written.
+func validateMultiFormat(cmd *cobra.Command, formats []string) error {
+	if len(formats) < 2 {
+		return nil
+	}
+	if configs, _ := cmd.Flags().GetStringSlice("config-list"); len(configs) > 0 {
+		return fmt.Errorf("--config-list writes a single format; pass one --format")
+	}
+	if output, _ := cmd.Flags().GetString("output"); output == "" {
+		return fmt.Errorf("several formats need --output, which names the files (e.g. --output message writes message.txt, message.hex, ...)")
+	}
+	if verify, _ := cmd.Flags().GetBool("verify-plaintext"); verify && !containsString(formats, "enig") && !containsString(formats, "html") {
+		return fmt.Errorf("--verify-plaintext needs enig or html among the formats")
+	}
+	return nil
+}
+
+// multiFormatPath returns the file for one format: --output without its
+// extension, plus the format's suffix.
+func multiFormatPath(output, format string) string {
+	return strings.TrimSuffix(output, filepath.Ext(output)) + formatExtensions[format]
+}
+
+// writeMultiFormat writes the ciphertext of a single encryption pass once per
+// format. Encrypting again for each format would advance the rotors further,
+// so the files would not hold the same ciphertext.
+func writeMultiFormat(cmd *cobra.Command, formats []string, ciphertext, plaintext, fingerprint string) error {
+	fsys := fileSystem(cmd)
+	output, _ := cmd.Flags().GetString("output")
+	configFile, _ := cmd.Flags().GetString("auto-config")
+	if configFile == "" {
+		configFile, _ = cmd.Flags().GetString("save-config")
+	}
+
+	for _, format := range formats {
+		var (
+			formatted string
+			err       error
+		)
+		switch format {
+		case "enig":
+			formatted, err = buildContainer(cmd, ciphertext, plaintext, fingerprint)
+		case "html":
+			formatted, err = buildHTMLExport(cmd, ciphertext, plaintext, fingerprint)
+		default:
+			formatted, err = formatCiphertext(ciphertext, format)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to format output as %s: %v", format, err)
+		}
+
+		path := multiFormatPath(output, format)
+		if err := fsys.WriteFile(path, []byte(formatted), 0600); err != nil {
+			return fmt.Errorf("failed to write %s: %v", path, err)
+		}
+		// Point decrypt at the generated configuration from every file
+		if configFile != "" {
+			if err := writeSidecar(fsys, path, configFile, fingerprint); err != nil {
+				return err
+			}
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%s -> %s\n", format, path)
+	}
+	return nil
+}
+
+// containsString reports whether list contains s.
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
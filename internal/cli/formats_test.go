@@ -0,0 +1,108 @@
+// Package cli provides unit tests for multi-format encrypt output.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOutputFormats(t *testing.T) {
+	cmd := newEncryptCommand()
+	for value, want := range map[string][]string{
+		"":                  {"text"},
+		"hex":               {"hex"},
+		"Text, HEX,base64":  {"text", "hex", "base64"},
+		"enig,text,enig":    {"enig", "text"},
+		"base64,hex,base64": {"base64", "hex"},
+	} {
+		cmd.Flags().Set("format", value)
+		got, err := outputFormats(cmd)
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("%q: got %v, %v; want %v", value, got, err, want)
+		}
+	}
+
+	cmd.Flags().Set("format", "text,rot13")
+	if _, err := outputFormats(cmd); err == nil || !strings.Contains(err.Error(), "unknown format: rot13") {
+		t.Errorf("expected an unknown format error, got %v", err)
+	}
+}
+
+func TestEncryptMultipleFormats(t *testing.T) {
+	fsys := NewMemFS()
+	run := func(args ...string) (string, error) {
+		var stdout bytes.Buffer
+		cmd := NewRootCommand(Options{Out: &stdout, Err: &bytes.Buffer{}, FS: fsys})
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return stdout.String(), err
+	}
+
+	out, err := run("encrypt", "--text", "Attack at dawn", "--auto-config", "key.json",
+		"--format", "text,hex,base64,enig", "--output", "msg.txt")
+	if err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	for _, want := range []string{"text -> msg.txt", "hex -> msg.hex", "base64 -> msg.b64", "enig -> msg.enig"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+
+	read := func(name string) string {
+		t.Helper()
+		data, err := fsys.ReadFile(name)
+		if err != nil {
+			t.Fatalf("missing %s: %v", name, err)
+		}
+		return string(data)
+	}
+	ciphertext := read("msg.txt")
+	if got := read("msg.hex"); got != hex.EncodeToString([]byte(ciphertext)) {
+		t.Errorf("hex file does not encode the same ciphertext: %s", got)
+	}
+	if got := read("msg.b64"); got != base64.StdEncoding.EncodeToString([]byte(ciphertext)) {
+		t.Errorf("base64 file does not encode the same ciphertext: %s", got)
+	}
+
+	// Every file decrypts on its own, finding the key through its sidecar
+	for file, format := range map[string]string{"msg.txt": "text", "msg.hex": "hex", "msg.b64": "base64", "msg.enig": "enig"} {
+		got, err := run("decrypt", "--file", file, "--format", format)
+		if err != nil {
+			t.Errorf("decrypt %s failed: %v", file, err)
+			continue
+		}
+		if got != "Attack at dawn" {
+			t.Errorf("decrypt %s = %q", file, got)
+		}
+	}
+}
+
+func TestEncryptMultipleFormatsErrors(t *testing.T) {
+	fsys := NewMemFS()
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--format", "text,hex"}, "several formats need --output"},
+		{[]string{"--format", "text,hex", "--output", "msg", "--verify-plaintext"}, "--verify-plaintext needs enig"},
+		{[]string{"--format", "text,hex", "--config-list", "*.json", "--output-dir", "out"}, "--config-list writes a single format"},
+		{[]string{"--format", "text,morse", "--output", "msg"}, "unknown format: morse"},
+	} {
+		cmd := NewRootCommand(Options{Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, FS: fsys})
+		cmd.SetArgs(append([]string{"encrypt", "--text", "HELLO", "--preset", "classic"}, tc.args...))
+		if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%v: expected %q, got %v", tc.args, tc.want, err)
+		}
+	}
+	if files, _ := fsys.ReadDir("."); len(files) != 0 {
+		t.Errorf("nothing should be written when the flags are rejected, found %d entries", len(files))
+	}
+}
@@ -0,0 +1,264 @@
+// Package cli provides the filesystem abstraction used by the enigoma CLI.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// FS is the filesystem the CLI reads configurations and inputs from and
+// writes outputs, sidecars and backups to. OSFS is used unless another
+// implementation is supplied through Options.
+type FS interface {
+	ReadFile(name string) ([]byte, error)
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	Stat(name string) (fs.FileInfo, error)
+	ReadDir(name string) ([]fs.DirEntry, error)
+	MkdirAll(path string, perm fs.FileMode) error
+	Glob(pattern string) ([]string, error)
+}
+
+type fsContextKey struct{}
+
+// withFileSystem attaches fsys to cmd's context so that fileSystem can find it.
+func withFileSystem(cmd *cobra.Command, fsys FS) {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cmd.SetContext(context.WithValue(ctx, fsContextKey{}, fsys))
+}
+
+// fileSystem returns the FS for the running command, defaulting to OSFS.
+// Under --no-lock the returned FS takes no file locks. How configuration
+// files are decrypted and checked is up to configOptionsFor, not the FS.
+func fileSystem(cmd *cobra.Command) FS {
+	if ctx := cmd.Context(); ctx != nil {
+		if fsys, ok := ctx.Value(fsContextKey{}).(FS); ok {
+			return unlockedIfRequested(cmd, fsys)
+		}
+	}
+	return unlockedIfRequested(cmd, OSFS{})
+}
+
+// OSFS is the FS backed by the host operating system.
+type OSFS struct{}
+
+// ReadFile reads the named file.
+func (OSFS) ReadFile(name string) ([]byte, error) { return os.ReadFile(name) }
+
+// WriteFile writes data to the named file, creating it if necessary.
+func (OSFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
+
+// Stat returns file information for name.
+func (OSFS) Stat(name string) (fs.FileInfo, error) { return os.Stat(name) }
+
+// ReadDir returns the entries of the named directory sorted by name.
+func (OSFS) ReadDir(name string) ([]fs.DirEntry, error) { return os.ReadDir(name) }
+
+// MkdirAll creates a directory along with any missing parents.
+func (OSFS) MkdirAll(path string, perm fs.FileMode) error { return os.MkdirAll(path, perm) }
+
+// Glob returns the names of all files matching pattern.
+func (OSFS) Glob(pattern string) ([]string, error) { return filepath.Glob(pattern) }
+
+// MemFS is an in-memory FS. Directories exist implicitly once a file is
+// written beneath them, or explicitly after MkdirAll. It is safe for
+// concurrent use.
+type MemFS struct {
+	mu    sync.RWMutex
+	files map[string]memFile
+	dirs  map[string]bool
+	locks map[string]chan struct{} // Held by Lock
+}
+
+type memFile struct {
+	data    []byte
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+// NewMemFS returns an empty in-memory filesystem.
+func NewMemFS() *MemFS {
+	return &MemFS{files: make(map[string]memFile), dirs: make(map[string]bool)}
+}
+
+func memPath(name string) string {
+	return path.Clean(filepath.ToSlash(name))
+}
+
+// ReadFile reads the named file.
+func (m *MemFS) ReadFile(name string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	f, ok := m.files[memPath(name)]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return append([]byte(nil), f.data...), nil
+}
+
+// WriteFile writes data to the named file. Like os.WriteFile, the parent
+// directory must already exist; "." and "/" always do.
+func (m *MemFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p := memPath(name)
+	if dir := path.Dir(p); !m.isDir(dir) {
+		return &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	if m.isDir(p) {
+		return &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	m.files[p] = memFile{data: append([]byte(nil), data...), mode: perm, modTime: time.Now()}
+	return nil
+}
+
+// Stat returns file information for name.
+func (m *MemFS) Stat(name string) (fs.FileInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	p := memPath(name)
+	if f, ok := m.files[p]; ok {
+		return memInfo{name: path.Base(p), size: int64(len(f.data)), mode: f.mode, modTime: f.modTime}, nil
+	}
+	if m.isDir(p) {
+		return memInfo{name: path.Base(p), mode: fs.ModeDir | 0700}, nil
+	}
+	return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+}
+
+// ReadDir returns the entries of the named directory sorted by name.
+func (m *MemFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	dir := memPath(name)
+	if !m.isDir(dir) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	children := make(map[string]fs.FileInfo)
+	for p, f := range m.files {
+		if child, ok := childOf(dir, p); ok {
+			if child == path.Base(p) {
+				children[child] = memInfo{name: child, size: int64(len(f.data)), mode: f.mode, modTime: f.modTime}
+			} else {
+				children[child] = memInfo{name: child, mode: fs.ModeDir | 0700}
+			}
+		}
+	}
+	for p := range m.dirs {
+		if child, ok := childOf(dir, p); ok {
+			if _, seen := children[child]; !seen {
+				children[child] = memInfo{name: child, mode: fs.ModeDir | 0700}
+			}
+		}
+	}
+
+	entries := make([]fs.DirEntry, 0, len(children))
+	for _, info := range children {
+		entries = append(entries, fs.FileInfoToDirEntry(info))
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+// MkdirAll creates a directory along with any missing parents.
+func (m *MemFS) MkdirAll(name string, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for p := memPath(name); p != "." && p != "/"; p = path.Dir(p) {
+		if _, ok := m.files[p]; ok {
+			return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrExist}
+		}
+		m.dirs[p] = true
+	}
+	return nil
+}
+
+// Glob returns the names of all files matching pattern, sorted.
+func (m *MemFS) Glob(pattern string) ([]string, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	pattern = filepath.ToSlash(pattern)
+	var matches []string
+	for p := range m.files {
+		if ok, _ := path.Match(pattern, p); ok {
+			matches = append(matches, filepath.FromSlash(p))
+		}
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// isDir reports whether p names a directory. Callers must hold m.mu.
+func (m *MemFS) isDir(p string) bool {
+	if p == "." || p == "/" || m.dirs[p] {
+		return true
+	}
+	prefix := p + "/"
+	for name := range m.files {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// childOf returns the first path element of p below dir.
+func childOf(dir, p string) (string, bool) {
+	var rest string
+	switch dir {
+	case ".":
+		if strings.HasPrefix(p, "/") {
+			return "", false
+		}
+		rest = p
+	case "/":
+		if !strings.HasPrefix(p, "/") {
+			return "", false
+		}
+		rest = p[1:]
+	default:
+		if !strings.HasPrefix(p, dir+"/") {
+			return "", false
+		}
+		rest = p[len(dir)+1:]
+	}
+	if rest == "" {
+		return "", false
+	}
+	child, _, _ := strings.Cut(rest, "/")
+	return child, true
+}
+
+type memInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (i memInfo) Name() string       { return i.name }
+func (i memInfo) Size() int64        { return i.size }
+func (i memInfo) Mode() fs.FileMode  { return i.mode }
+func (i memInfo) ModTime() time.Time { return i.modTime }
+func (i memInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i memInfo) Sys() any           { return nil }
@@ -0,0 +1,85 @@
+// Package cli provides unit tests for the CLI filesystem abstraction.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestMemFS(t *testing.T) {
+	fsys := NewMemFS()
+
+	if err := fsys.WriteFile("keys/a.json", []byte("a"), 0600); !os.IsNotExist(err) {
+		t.Errorf("writing into a missing directory should fail with not-exist, got %v", err)
+	}
+	if err := fsys.MkdirAll("keys/old", 0700); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	for _, name := range []string{"keys/b.json", "keys/a.json", "keys/notes.txt", "keys/old/c.json"} {
+		if err := fsys.WriteFile(name, []byte(name), 0600); err != nil {
+			t.Fatalf("WriteFile(%s) failed: %v", name, err)
+		}
+	}
+
+	data, err := fsys.ReadFile("./keys/../keys/a.json")
+	if err != nil || string(data) != "keys/a.json" {
+		t.Errorf("ReadFile should clean paths, got %q, %v", data, err)
+	}
+	if _, err := fsys.ReadFile("missing.json"); !os.IsNotExist(err) {
+		t.Errorf("missing file should report not-exist, got %v", err)
+	}
+
+	info, err := fsys.Stat("keys")
+	if err != nil || !info.IsDir() {
+		t.Errorf("keys should be a directory, got %v, %v", info, err)
+	}
+	info, err = fsys.Stat("keys/b.json")
+	if err != nil || info.IsDir() || info.Size() != int64(len("keys/b.json")) {
+		t.Errorf("unexpected file info %v, %v", info, err)
+	}
+
+	entries, err := fsys.ReadDir("keys")
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if want := []string{"a.json", "b.json", "notes.txt", "old"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("ReadDir = %v, want %v", names, want)
+	}
+
+	matches, err := fsys.Glob("keys/*.json")
+	if err != nil {
+		t.Fatalf("Glob failed: %v", err)
+	}
+	if want := []string{"keys/a.json", "keys/b.json"}; !reflect.DeepEqual(matches, want) {
+		t.Errorf("Glob = %v, want %v", matches, want)
+	}
+	if _, err := fsys.Glob("keys/[.json"); err == nil {
+		t.Error("malformed pattern should fail")
+	}
+}
+
+func TestOptionsFSIsolatesCommands(t *testing.T) {
+	fsys := NewMemFS()
+	cmd := NewRootCommand(Options{FS: fsys})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"keygen", "--preset", "classic", "--output", "virtual-key.json"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("keygen failed: %v", err)
+	}
+
+	if _, err := fsys.Stat("virtual-key.json"); err != nil {
+		t.Errorf("key should be written to the injected FS: %v", err)
+	}
+	if _, err := os.Stat("virtual-key.json"); !os.IsNotExist(err) {
+		t.Errorf("key must not touch the real filesystem, got %v", err)
+	}
+}
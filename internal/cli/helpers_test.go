@@ -0,0 +1,17 @@
+// Package cli provides helpers shared by the command tests.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import "bytes"
+
+// runCLI runs the root command with args on fsys and returns what it wrote
+// to stdout. Stderr is discarded.
+func runCLI(fsys FS, args ...string) (string, error) {
+	var stdout bytes.Buffer
+	cmd := NewRootCommand(Options{Out: &stdout, Err: &bytes.Buffer{}, FS: fsys})
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return stdout.String(), err
+}
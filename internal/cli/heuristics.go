@@ -0,0 +1,48 @@
+// Package cli provides plausibility checks on decrypted output.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"fmt"
+
+	"github.com/coredds/enigoma/internal/analysis"
+	"github.com/coredds/enigoma/pkg/enigma"
+	"github.com/spf13/cobra"
+)
+
+// warnOutputNotNatural flags decrypted output that looks like ciphertext or binary data.
+const warnOutputNotNatural enigma.WarningCode = "output_not_natural"
+
+// checkDecryptedOutput warns when decrypted text does not look like natural
+// language, which usually means a wrong key, a wrong --format, or text that
+// was encrypted twice. Verified containers and --no-heuristics skip the check.
+func checkDecryptedOutput(cmd *cobra.Command, machine *enigma.Enigma, container *enigma.Container, output string) error {
+	if off, _ := cmd.Flags().GetBool("no-heuristics"); off {
+		return nil
+	}
+	if container != nil && container.HasPlaintextCheck() {
+		return nil
+	}
+
+	var alphabet []rune
+	if settings, err := machine.GetSettings(); err == nil {
+		alphabet = settings.Alphabet
+	}
+
+	assessment := analysis.Assess(output, alphabet)
+	if v, _ := cmd.Flags().GetBool("verbose"); v {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Output heuristics: %d letters, IoC %.4f (%.2fx uniform), %.0f%% printable\n",
+			assessment.Letters, assessment.IoC, assessment.Kappa, assessment.Printable*100)
+	}
+	if assessment.Natural {
+		return nil
+	}
+
+	return reportWarnings(cmd, enigma.Warning{
+		Code: warnOutputNotNatural,
+		Message: fmt.Sprintf("output does not look like natural text (%s) — possibly wrong key, wrong format, or double encryption. Use --no-heuristics to silence this check",
+			assessment.Reason),
+	})
+}
@@ -0,0 +1,47 @@
+// Package cli provides unit tests for decrypted output heuristics.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDecryptOutputHeuristics(t *testing.T) {
+	fsys := NewMemFS()
+	run := func(args ...string) (string, string) {
+		t.Helper()
+		var stdout, stderr bytes.Buffer
+		cmd := NewRootCommand(Options{FS: fsys})
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&stderr)
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("%v failed: %v", args, err)
+		}
+		return strings.TrimSuffix(stdout.String(), "\n"), stderr.String()
+	}
+
+	run("keygen", "--alphabet", "latin", "--security", "low", "--output", "key.json")
+	plaintext := "ITWASABRIGHTCOLDDAYINAPRILANDTHECLOCKSWERESTRIKINGTHIRTEEN" +
+		"WINSTONSMITHHISCHINNUZZLEDINTOHISBREASTINANEFFORTTOESCAPETHEVILEWIND" +
+		"SLIPPEDQUICKLYTHROUGHTHEGLASSDOORSOFVICTORYMANSIONS"
+	once, _ := run("encrypt", "--text", plaintext, "--config", "key.json")
+	twice, _ := run("encrypt", "--text", once, "--config", "key.json")
+
+	if out, stderr := run("decrypt", "--text", once, "--config", "key.json"); out != plaintext || stderr != "" {
+		t.Errorf("correct decryption should be silent, got %q with stderr %q", out, stderr)
+	}
+
+	_, stderr := run("decrypt", "--text", twice, "--config", "key.json", "--warnings-format", "json")
+	if !strings.Contains(stderr, `"code":"output_not_natural"`) {
+		t.Errorf("expected an output_not_natural warning for double encryption, got %q", stderr)
+	}
+
+	if _, stderr := run("decrypt", "--text", twice, "--config", "key.json", "--no-heuristics"); stderr != "" {
+		t.Errorf("--no-heuristics should silence the check, got %q", stderr)
+	}
+}
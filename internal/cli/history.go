@@ -0,0 +1,193 @@
+// Package cli provides timestamped backups for configuration files.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// historyDirName is the directory, next to each config file, holding its backups.
+const historyDirName = ".enigoma-history"
+
+// historyTimeFormat is the UTC timestamp embedded in backup file names.
+const historyTimeFormat = "20060102T150405Z"
+
+// configBackup describes one saved copy of a configuration file.
+type configBackup struct {
+	Timestamp string
+	Path      string
+	Size      int64
+}
+
+// writeConfigFile writes a configuration file, first backing up any existing
+// contents so an overwrite never loses a key. The file is locked meanwhile.
+// Plaintext JSON is converted to the format named by the extension of path,
+// and the result is encrypted when opts has a password.
+func writeConfigFile(fsys FS, opts configOptions, path, content string) error {
+	if err := ensureKeyDir(fsys, path); err != nil {
+		return err
+	}
+	return withFileLock(fsys, path, func() error {
+		return writeConfigFileLocked(fsys, opts, path, content)
+	})
+}
+
+// writeConfigFileLocked is writeConfigFile for callers that already hold the
+// lock on path.
+func writeConfigFileLocked(fsys FS, opts configOptions, path, content string) error {
+	content, err := encryptConfigIfRequested(opts, content)
+	if err != nil {
+		return err
+	}
+	// Commands produce JSON; restored backups are already in the file's format
+	if sniffConfigFormat([]byte(content)) == configJSON {
+		if content, err = convertConfigText(content, configFormatForPath(path)); err != nil {
+			return err
+		}
+	}
+	if _, err := backupConfigFile(fsys, path); err != nil {
+		return fmt.Errorf("failed to back up %s: %v", path, err)
+	}
+	return writeStringToFile(fsys, content, path)
+}
+
+// backupConfigFile copies path into its history directory. It returns the
+// backup path, or "" when there was nothing to back up.
+func backupConfigFile(fsys FS, path string) (string, error) {
+	data, err := fsys.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	dir := historyDir(path)
+	if err := fsys.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+
+	stamp := now().UTC().Format(historyTimeFormat)
+	backup := filepath.Join(dir, backupFileName(path, stamp))
+	for n := 1; fileExists(fsys, backup); n++ {
+		backup = filepath.Join(dir, backupFileName(path, fmt.Sprintf("%s-%d", stamp, n)))
+	}
+
+	if err := fsys.WriteFile(backup, data, 0600); err != nil {
+		return "", err
+	}
+	return backup, nil
+}
+
+// listConfigBackups returns the backups of path, oldest first.
+func listConfigBackups(fsys FS, path string) ([]configBackup, error) {
+	entries, err := fsys.ReadDir(historyDir(path))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	prefix, ext := backupNameParts(path)
+	var backups []configBackup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext)
+		if _, err := time.Parse(historyTimeFormat, strings.SplitN(stamp, "-", 2)[0]); err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		backups = append(backups, configBackup{
+			Timestamp: stamp,
+			Path:      filepath.Join(historyDir(path), name),
+			Size:      info.Size(),
+		})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Timestamp < backups[j].Timestamp
+	})
+	return backups, nil
+}
+
+// restoreConfigBackup replaces path with the backup matching timestamp (or a
+// unique prefix of it). The current contents are backed up first.
+func restoreConfigBackup(fsys FS, opts configOptions, path, timestamp string) (string, error) {
+	var restored string
+	err := withFileLock(fsys, path, func() error {
+		var err error
+		restored, err = restoreConfigBackupLocked(fsys, opts, path, timestamp)
+		return err
+	})
+	return restored, err
+}
+
+// restoreConfigBackupLocked is restoreConfigBackup for callers that hold the
+// lock on path.
+func restoreConfigBackupLocked(fsys FS, opts configOptions, path, timestamp string) (string, error) {
+	backups, err := listConfigBackups(fsys, path)
+	if err != nil {
+		return "", err
+	}
+
+	var matches []configBackup
+	for _, b := range backups {
+		if b.Timestamp == timestamp {
+			matches = []configBackup{b}
+			break
+		}
+		if strings.HasPrefix(b.Timestamp, timestamp) {
+			matches = append(matches, b)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no backup of %s matches %q. Use --history %s to list backups", path, timestamp, path)
+	case 1:
+	default:
+		return "", fmt.Errorf("timestamp %q is ambiguous (%d backups match)", timestamp, len(matches))
+	}
+
+	data, err := fsys.ReadFile(matches[0].Path)
+	if err != nil {
+		return "", err
+	}
+	if err := writeConfigFileLocked(fsys, opts, path, string(data)); err != nil {
+		return "", err
+	}
+	return matches[0].Timestamp, nil
+}
+
+func historyDir(path string) string {
+	return filepath.Join(filepath.Dir(path), historyDirName)
+}
+
+func backupNameParts(path string) (prefix, ext string) {
+	base := filepath.Base(path)
+	ext = filepath.Ext(base)
+	return strings.TrimSuffix(base, ext) + ".", ext
+}
+
+func backupFileName(path, stamp string) string {
+	prefix, ext := backupNameParts(path)
+	return prefix + stamp + ext
+}
+
+func fileExists(fsys FS, path string) bool {
+	_, err := fsys.Stat(path)
+	return err == nil
+}
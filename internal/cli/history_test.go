@@ -0,0 +1,146 @@
+// Package cli provides unit tests for configuration history.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// withClock pins the CLI clock for the duration of a test, cycling through times.
+func withClock(t *testing.T, times ...time.Time) {
+	t.Helper()
+	original := now
+	i := 0
+	now = func() time.Time {
+		current := times[i%len(times)]
+		i++
+		return current
+	}
+	t.Cleanup(func() { now = original })
+}
+
+func TestWriteConfigFileKeepsBackups(t *testing.T) {
+	fsys := NewMemFS()
+	path := filepath.Join("keys", "key.json")
+	if err := fsys.MkdirAll("keys", 0700); err != nil {
+		t.Fatal(err)
+	}
+	withClock(t,
+		time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC),
+		time.Date(2025, 1, 3, 9, 0, 0, 0, time.UTC),
+	)
+
+	for _, content := range []string{"v1", "v2", "v3"} {
+		if err := writeConfigFile(fsys, configOptions{}, path, content); err != nil {
+			t.Fatalf("writeConfigFile failed: %v", err)
+		}
+	}
+
+	backups, err := listConfigBackups(fsys, path)
+	if err != nil {
+		t.Fatalf("listConfigBackups failed: %v", err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("expected 2 backups, got %d", len(backups))
+	}
+	if backups[0].Timestamp != "20250102T150405Z" || backups[1].Timestamp != "20250103T090000Z" {
+		t.Errorf("unexpected timestamps: %s, %s", backups[0].Timestamp, backups[1].Timestamp)
+	}
+
+	data, _ := fsys.ReadFile(backups[0].Path)
+	if string(data) != "v1" {
+		t.Errorf("oldest backup should hold v1, got %q", data)
+	}
+}
+
+func TestBackupTimestampCollision(t *testing.T) {
+	fsys := NewMemFS()
+	path := filepath.Join("keys", "key.json")
+	if err := fsys.MkdirAll("keys", 0700); err != nil {
+		t.Fatal(err)
+	}
+	withClock(t, time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC))
+
+	for _, content := range []string{"a", "b", "c"} {
+		if err := writeConfigFile(fsys, configOptions{}, path, content); err != nil {
+			t.Fatalf("writeConfigFile failed: %v", err)
+		}
+	}
+
+	backups, err := listConfigBackups(fsys, path)
+	if err != nil {
+		t.Fatalf("listConfigBackups failed: %v", err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("colliding timestamps must not overwrite backups, got %d", len(backups))
+	}
+}
+
+func TestConfigHistoryAndRestoreCommand(t *testing.T) {
+	fsys := NewMemFS()
+	path := filepath.Join("keys", "key.json")
+	if err := fsys.MkdirAll("keys", 0700); err != nil {
+		t.Fatal(err)
+	}
+	withClock(t,
+		time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC),
+		time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC),
+	)
+
+	if err := writeConfigFile(fsys, configOptions{}, path, "original"); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeConfigFile(fsys, configOptions{}, path, "accidental overwrite"); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	cmd := NewRootCommand(Options{FS: fsys})
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"config", "--history", path})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("config --history failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "20250102T150405Z") {
+		t.Errorf("history listing missing backup: %s", out.String())
+	}
+
+	out.Reset()
+	cmd = NewRootCommand(Options{FS: fsys})
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"config", "--history", path, "--restore", "20250102"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("config --restore failed: %v", err)
+	}
+
+	data, _ := fsys.ReadFile(path)
+	if string(data) != "original" {
+		t.Errorf("restore should bring back the original, got %q", data)
+	}
+
+	// The overwritten version is itself preserved by the restore
+	backups, _ := listConfigBackups(fsys, path)
+	if len(backups) != 2 {
+		t.Errorf("expected restore to back up current contents, got %d backups", len(backups))
+	}
+
+	cmd = NewRootCommand(Options{FS: fsys})
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"config", "--restore", "20250102"})
+	if err := cmd.Execute(); err == nil {
+		t.Errorf("--restore without --history should fail")
+	}
+
+	cmd = NewRootCommand(Options{FS: fsys})
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"config", "--history", path, "--restore", "1999"})
+	if err := cmd.Execute(); err == nil {
+		t.Errorf("restoring an unknown timestamp should fail")
+	}
+}
@@ -0,0 +1,209 @@
+// Package cli provides the standalone HTML export of an encrypted message.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// htmlExtension is the output file extension that selects the HTML export.
+const htmlExtension = ".html"
+
+// wasmDirEnv names the 'make wasm' output directory when --wasm-dir is not
+// given.
+const wasmDirEnv = "ENIGOMA_WASM_DIR"
+
+// htmlDecryptor is the WebAssembly build an HTML export embeds to decrypt in
+// the browser: the module as a base64 JavaScript string literal, and the
+// wasm_exec.js loader Go ships for it.
+type htmlDecryptor struct {
+	Module template.JS
+	Loader template.JS
+}
+
+// htmlContainerOpen and htmlContainerClose delimit the container in an HTML
+// export, so decrypt can read the page directly.
+const (
+	htmlContainerOpen  = `<pre id="enigoma-container">`
+	htmlContainerClose = `</pre>`
+)
+
+// htmlExportTemplate is a single page with no external resources: it shows
+// the key fingerprint and the .enig container, lets the recipient pick the
+// key file and decrypt in the page itself, and offers the container for
+// download so it can also be decrypted with the CLI.
+var htmlExportTemplate = template.Must(template.New("message").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="generator" content="enigoma {{.Version}}">
+<title>Encrypted message</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 48rem; margin: 2rem auto; padding: 0 1rem; color: #222; }
+pre { background: #f4f4f4; padding: 1rem; white-space: pre-wrap; word-break: break-all; }
+code { background: #f4f4f4; padding: 0 .25rem; }
+dt { font-weight: bold; }
+.error { color: #b00; }
+</style>
+</head>
+<body>
+<h1>Encrypted message</h1>
+<dl>
+<dt>Key fingerprint</dt>
+<dd><code>{{.Fingerprint}}</code></dd>
+<dt>Created</dt>
+<dd>{{.Created}}</dd>
+</dl>
+<p>This message was encrypted with enigoma. To read it you need the key file
+whose fingerprint is shown above. Save this page (or the container below as
+<code>message.enig</code>) and run:</p>
+<pre>enigoma decrypt --file message.html --config key.json</pre>
+<h2>Decrypt in this browser</h2>
+<p>Or pick the key file here. It is read and the message decrypted inside this
+page; nothing is sent anywhere, so this works offline.</p>
+<p><label>Key file <input type="file" id="key-file" accept=".json,.yaml,.yml,.toml"></label></p>
+<p><label>Password, for an encrypted key file <input type="password" id="key-password"></label></p>
+<p><button type="button" id="decrypt" disabled>Decrypt</button></p>
+<p id="decrypt-status" class="error"></p>
+<pre id="plaintext" hidden></pre>
+<h2>Container</h2>
+` + htmlContainerOpen + `{{.Container}}` + htmlContainerClose + `
+<p><button type="button" id="download">Download message.enig</button></p>
+<script>
+document.getElementById("download").addEventListener("click", function () {
+  var text = document.getElementById("enigoma-container").textContent;
+  var link = document.createElement("a");
+  link.href = URL.createObjectURL(new Blob([text], {type: "application/json"}));
+  link.download = "message.enig";
+  link.click();
+});
+</script>
+<script id="enigoma-decryptor">{{.Decryptor.Loader}}</script>
+<script>
+(function () {
+  var status = document.getElementById("decrypt-status");
+  var module = Uint8Array.from(atob({{.Decryptor.Module}}), function (c) { return c.charCodeAt(0); });
+  var go = new Go();
+  WebAssembly.instantiate(module, go.importObject).then(function (result) {
+    go.run(result.instance);
+    document.getElementById("decrypt").disabled = false;
+  }, function (err) {
+    status.textContent = "This browser cannot run the decryptor: " + err;
+  });
+
+  document.getElementById("decrypt").addEventListener("click", function () {
+    var file = document.getElementById("key-file").files[0];
+    var output = document.getElementById("plaintext");
+    if (!file) {
+      status.textContent = "Choose the key file first.";
+      return;
+    }
+    file.text().then(function (key) {
+      var container = document.getElementById("enigoma-container").textContent;
+      var result = enigoma.decryptContainer(container, key, file.name, document.getElementById("key-password").value);
+      status.className = result.error ? "error" : "";
+      status.textContent = result.error || (result.verified ? "Decrypted; the text matches the message's plaintext check." : "Decrypted.");
+      output.textContent = result.error ? "" : result.text;
+      output.hidden = !!result.error;
+    });
+  });
+})();
+</script>
+</body>
+</html>
+`))
+
+// wantsHTML reports whether encrypt output should be an HTML export, either
+// explicitly (--format html) or via a .html output path.
+func wantsHTML(cmd *cobra.Command) bool {
+	if format, _ := cmd.Flags().GetString("format"); strings.EqualFold(format, "html") {
+		return true
+	}
+	output, _ := cmd.Flags().GetString("output")
+	return strings.HasSuffix(strings.ToLower(output), htmlExtension)
+}
+
+// buildHTMLExport renders a standalone page around the ciphertext's
+// container, which carries the key fingerprint and, with --verify-plaintext,
+// the plaintext check.
+func buildHTMLExport(cmd *cobra.Command, ciphertext, plaintext, fingerprint string) (string, error) {
+	container, err := buildContainer(cmd, ciphertext, plaintext, fingerprint)
+	if err != nil {
+		return "", err
+	}
+	decryptor, err := readHTMLDecryptor(cmd)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	err = htmlExportTemplate.Execute(&buf, map[string]any{
+		"Version":     cmd.Root().Version,
+		"Fingerprint": fingerprint,
+		"Created":     now().UTC().Format("2006-01-02 15:04 MST"),
+		"Container":   container,
+		"Decryptor":   decryptor,
+	})
+	if err != nil {
+		return "", fmt.Errorf("render HTML export: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// readHTMLDecryptor reads the WebAssembly build from --wasm-dir or
+// ENIGOMA_WASM_DIR. A page without it could not decrypt in the browser, so
+// the export fails when neither is set.
+func readHTMLDecryptor(cmd *cobra.Command) (*htmlDecryptor, error) {
+	dir, _ := cmd.Flags().GetString("wasm-dir")
+	if dir == "" {
+		dir = os.Getenv(wasmDirEnv)
+	}
+	if dir == "" {
+		return nil, fmt.Errorf("HTML export embeds the WebAssembly decryptor: build it with 'make wasm' and pass --wasm-dir build/wasm (or set %s)", wasmDirEnv)
+	}
+	fsys := fileSystem(cmd)
+	module, err := fsys.ReadFile(filepath.Join(dir, "enigoma.wasm"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the WebAssembly decryptor: %v (build it with 'make wasm')", err)
+	}
+	if !bytes.HasPrefix(module, []byte("\x00asm")) {
+		return nil, fmt.Errorf("%s is not a WebAssembly module", filepath.Join(dir, "enigoma.wasm"))
+	}
+	loader, err := fsys.ReadFile(filepath.Join(dir, "wasm_exec.js"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the WebAssembly loader: %v", err)
+	}
+	// The loader goes into the page as is, so it must not end its script element
+	if strings.Contains(strings.ToLower(string(loader)), "</script") {
+		return nil, fmt.Errorf("%s cannot be embedded in a page", filepath.Join(dir, "wasm_exec.js"))
+	}
+	return &htmlDecryptor{
+		Module: template.JS(`"` + base64.StdEncoding.EncodeToString(module) + `"`),
+		Loader: template.JS(loader),
+	}, nil
+}
+
+// extractHTMLContainer returns the container embedded in an HTML export, or
+// false when text is not one.
+func extractHTMLContainer(text string) (string, bool) {
+	_, rest, ok := strings.Cut(text, htmlContainerOpen)
+	if !ok {
+		return "", false
+	}
+	container, _, ok := strings.Cut(rest, htmlContainerClose)
+	if !ok {
+		return "", false
+	}
+	return html.UnescapeString(container), true
+}
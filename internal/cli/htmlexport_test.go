@@ -0,0 +1,142 @@
+// Package cli provides unit tests for the HTML export.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"encoding/base64"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Stand-ins for the files 'make wasm' builds.
+const (
+	fakeWASMModule = "\x00asm\x01\x00\x00\x00"
+	fakeWASMLoader = "// wasm_exec.js\nglobalThis.Go = class {};"
+)
+
+// withFakeDecryptor writes a stand-in WebAssembly build to the wasm
+// directory of fsys and points ENIGOMA_WASM_DIR at it.
+func withFakeDecryptor(t *testing.T, fsys *MemFS) {
+	t.Helper()
+	if err := fsys.MkdirAll("wasm", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.WriteFile(filepath.Join("wasm", "enigoma.wasm"), []byte(fakeWASMModule), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.WriteFile(filepath.Join("wasm", "wasm_exec.js"), []byte(fakeWASMLoader), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(wasmDirEnv, "wasm")
+}
+
+func TestHTMLExportRoundTrip(t *testing.T) {
+	fsys := NewMemFS()
+	withFakeDecryptor(t, fsys)
+	if _, err := runCLI(fsys, "keygen", "--preset", "m3", "--output", "key.json"); err != nil {
+		t.Fatalf("keygen failed: %v", err)
+	}
+	fingerprint, err := fingerprintConfigFile(fsys, configOptions{}, "key.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Ciphertext over an alphabet with "<", ">" and "/" must be escaped in the page
+	plaintext := "ATTACK</pre><script>alert(1)</script>AT DAWN"
+	if _, err := runCLI(fsys, "encrypt", "--auto-config", "ascii.json", "--text", plaintext, "--output", "message.html", "--verify-plaintext"); err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	data, err := fsys.ReadFile("message.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := string(data)
+	if !strings.HasPrefix(page, "<!DOCTYPE html>") || strings.Count(page, htmlContainerClose) != 3 {
+		t.Fatalf("unexpected page:\n%s", page)
+	}
+
+	out, err := runCLI(fsys, "decrypt", "--config", "ascii.json", "--file", "message.html")
+	if err != nil || out != plaintext {
+		t.Errorf("decrypt = %q, %v; want %q", out, err, plaintext)
+	}
+
+	// --format html writes a page to stdout and shows the fingerprint
+	out, err = runCLI(fsys, "encrypt", "--config", "key.json", "--text", "HELLO", "--format", "html")
+	if err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	if !strings.Contains(out, "<code>"+fingerprint+"</code>") {
+		t.Errorf("page does not show the key fingerprint %s", fingerprint)
+	}
+	if _, ok := extractHTMLContainer(out); !ok {
+		t.Error("page does not embed a container")
+	}
+}
+
+func TestHTMLExportMultiFormat(t *testing.T) {
+	fsys := NewMemFS()
+	withFakeDecryptor(t, fsys)
+	if _, err := runCLI(fsys, "keygen", "--preset", "m3", "--output", "key.json"); err != nil {
+		t.Fatalf("keygen failed: %v", err)
+	}
+	if _, err := runCLI(fsys, "encrypt", "--config", "key.json", "--text", "HELLO", "--format", "text,html", "--output", "msg"); err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	text, _ := fsys.ReadFile("msg.txt")
+	page, _ := fsys.ReadFile("msg.html")
+	container, ok := extractHTMLContainer(string(page))
+	if !ok || !strings.Contains(container, `"ciphertext": "`+string(text)+`"`) {
+		t.Errorf("msg.html should hold the ciphertext of msg.txt (%s):\n%s", text, container)
+	}
+}
+
+func TestHTMLExportEmbedsDecryptor(t *testing.T) {
+	fsys := NewMemFS()
+	if _, err := runCLI(fsys, "keygen", "--preset", "m3", "--output", "key.json"); err != nil {
+		t.Fatalf("keygen failed: %v", err)
+	}
+	withFakeDecryptor(t, fsys)
+	t.Setenv(wasmDirEnv, "")
+
+	page, err := runCLI(fsys, "encrypt", "--config", "key.json", "--text", "HELLO", "--format", "html", "--wasm-dir", "wasm")
+	if err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	for _, want := range []string{
+		`<script id="enigoma-decryptor">` + fakeWASMLoader + `</script>`,
+		`atob("` + base64.StdEncoding.EncodeToString([]byte(fakeWASMModule)) + `")`,
+		`<input type="file" id="key-file"`,
+		"enigoma.decryptContainer(",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page does not contain %q", want)
+		}
+	}
+	out, err := runCLI(fsys, "decrypt", "--config", "key.json", "--text", page)
+	if err != nil || out != "HELLO" {
+		t.Errorf("decrypt = %q, %v; the page should still decrypt with the CLI", out, err)
+	}
+
+	// A page that cannot decrypt in the browser is not written at all
+	for _, args := range [][]string{{"--format", "html"}, {"--output", "message.html"}} {
+		_, err := runCLI(fsys, append([]string{"encrypt", "--config", "key.json", "--text", "HELLO"}, args...)...)
+		if err == nil || !strings.Contains(err.Error(), "--wasm-dir") {
+			t.Errorf("%v without a WebAssembly build: error = %v, want one naming --wasm-dir", args, err)
+		}
+	}
+	if _, err := fsys.Stat("message.html"); err == nil {
+		t.Error("no page should be written without a WebAssembly build")
+	}
+	if _, err := runCLI(fsys, "encrypt", "--config", "key.json", "--text", "HELLO", "--format", "html", "--wasm-dir", "missing"); err == nil || !strings.Contains(err.Error(), "make wasm") {
+		t.Errorf("a missing build should be explained, got %v", err)
+	}
+	if err := fsys.WriteFile(filepath.Join("wasm", "enigoma.wasm"), []byte("not wasm"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runCLI(fsys, "encrypt", "--config", "key.json", "--text", "HELLO", "--format", "html", "--wasm-dir", "wasm"); err == nil || !strings.Contains(err.Error(), "not a WebAssembly module") {
+		t.Errorf("a file that is not a module should be refused, got %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+// Package cli provides input reading and cleanup shared by encrypt and decrypt.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/coredds/enigoma/pkg/enigma"
+	"github.com/spf13/cobra"
+)
+
+// byteOrderMark is the UTF-8 BOM some editors put at the start of text files.
+const byteOrderMark = '\uFEFF'
+
+// invisibleRunes are characters that editors do not show. Left in the input
+// they fail alphabet validation with an error about a character the user
+// cannot see.
+var invisibleRunes = map[rune]string{
+	byteOrderMark: "byte order mark",
+	'\u00AD':      "soft hyphen",
+	'\u200B':      "zero-width space",
+	'\u200C':      "zero-width non-joiner",
+	'\u200D':      "zero-width joiner",
+	'\u200E':      "left-to-right mark",
+	'\u200F':      "right-to-left mark",
+	'\u2060':      "word joiner",
+}
+
+// addInputCleanupFlags registers the flags controlling input cleanup.
+func addInputCleanupFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("keep-trailing-newline", false, "Keep a file's final newline even when the key's alphabet has no newline")
+}
+
+// readInputText returns the input from --text, --file or stdin. A UTF-8 byte
+// order mark at the start of a file or stdin is never part of the message
+// and is dropped.
+func readInputText(cmd *cobra.Command) (string, error) {
+	if text, _ := cmd.Flags().GetString("text"); text != "" {
+		return text, nil
+	}
+
+	var text string
+	if filename, _ := cmd.Flags().GetString("file"); filename != "" {
+		data, err := fileSystem(cmd).ReadFile(filename)
+		if err != nil {
+			return "", fmt.Errorf("failed to read file %s: %w", filename, err)
+		}
+		text = string(data)
+	} else {
+		data, err := readPipedInput(cmd)
+		if err != nil {
+			return "", err
+		}
+		text = data
+	}
+
+	if trimmed := strings.TrimPrefix(text, string(byteOrderMark)); trimmed != text {
+		if verbose, _ := cmd.Flags().GetBool("verbose"); verbose {
+			fmt.Fprintln(cmd.ErrOrStderr(), "Removed the UTF-8 byte order mark at the start of the input")
+		}
+		text = trimmed
+	}
+	return text, nil
+}
+
+// fitInputToAlphabet drops what cannot be part of a message for machine's
+// alphabet but is easily left in a file: the final line ending (unless
+// --keep-trailing-newline), the carriage returns of Windows (CRLF) line
+// endings and invisible characters. Characters the alphabet contains are
+// always kept, so ciphertext that legitimately ends in a newline still
+// decrypts. Removals are reported in verbose mode.
+func fitInputToAlphabet(cmd *cobra.Command, text string, machine *enigma.Enigma) (string, error) {
+	settings, err := machine.GetSettings()
+	if err != nil {
+		return "", fmt.Errorf("failed to read machine settings: %v", err)
+	}
+	// Symbols of grapheme clusters may be written with invisible runes, such
+	// as the joiners of emoji sequences, which are then kept
+	runes := settings.Alphabet
+	if len(settings.Symbols) > 0 {
+		runes = []rune(strings.Join(settings.Symbols, ""))
+	}
+	keep, _ := cmd.Flags().GetBool("keep-trailing-newline")
+	text, removed := fitTextToAlphabet(text, runes, keep)
+	if verbose, _ := cmd.Flags().GetBool("verbose"); verbose && len(removed) > 0 {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Removed from input (not in the key's alphabet): %s\n", strings.Join(removed, ", "))
+	}
+	return text, nil
+}
+
+// fitTextToAlphabet does the cleanup of fitInputToAlphabet for alphabet and
+// returns the cleaned text with a description of each removal.
+func fitTextToAlphabet(text string, alphabet []rune, keepTrailingNewline bool) (string, []string) {
+	inAlphabet := make(map[rune]bool, len(alphabet))
+	for _, r := range alphabet {
+		inAlphabet[r] = true
+	}
+
+	var removed []string
+	if !keepTrailingNewline {
+		for _, ending := range []string{"\r\n", "\n"} {
+			if strings.HasSuffix(text, ending) && !strings.ContainsFunc(ending, func(r rune) bool { return inAlphabet[r] }) {
+				text = strings.TrimSuffix(text, ending)
+				removed = append(removed, "trailing newline")
+				break
+			}
+		}
+	}
+
+	counts := make(map[string]int)
+	if !inAlphabet['\r'] {
+		// Text written on Windows means the same as on other systems
+		if n := strings.Count(text, "\r\n"); n > 0 {
+			text = strings.ReplaceAll(text, "\r\n", "\n")
+			counts["carriage return"] = n
+		}
+	}
+	text = strings.Map(func(r rune) rune {
+		if name, ok := invisibleRunes[r]; ok && !inAlphabet[r] {
+			counts[name]++
+			return -1
+		}
+		return r
+	}, text)
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		removed = append(removed, fmt.Sprintf("%d %s(s)", counts[name], name))
+	}
+
+	return text, removed
+}
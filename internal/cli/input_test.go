@@ -0,0 +1,169 @@
+// Package cli provides unit tests for input reading and cleanup.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/coredds/enigoma/pkg/enigma"
+)
+
+func TestFileInputWithBOMAndTrailingNewline(t *testing.T) {
+	fsys := NewMemFS()
+	run := func(args ...string) (string, string, error) {
+		var stdout, stderr bytes.Buffer
+		cmd := NewRootCommand(Options{Out: &stdout, Err: &stderr, FS: fsys})
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return stdout.String(), stderr.String(), err
+	}
+	if _, _, err := run("keygen", "--preset", "classic", "--output", "key.json"); err != nil {
+		t.Fatalf("keygen failed: %v", err)
+	}
+	if err := fsys.WriteFile("msg.txt", []byte("\uFEFFATTACK\u200BATDAWN\r\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, stderr, err := run("encrypt", "--file", "msg.txt", "--config", "key.json", "--output", "msg.enc", "--verbose")
+	if err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	for _, want := range []string{"Removed the UTF-8 byte order mark", "trailing newline", "1 zero-width space(s)"} {
+		if !strings.Contains(stderr, want) {
+			t.Errorf("verbose output should mention %q:\n%s", want, stderr)
+		}
+	}
+
+	// An editor adds a final newline to the ciphertext file as well
+	ciphertext, _ := fsys.ReadFile("msg.enc")
+	if err := fsys.WriteFile("msg.enc", append(ciphertext, '\n'), 0600); err != nil {
+		t.Fatal(err)
+	}
+	out, _, err := run("decrypt", "--file", "msg.enc", "--config", "key.json")
+	if err != nil {
+		t.Fatalf("decrypt failed: %v", err)
+	}
+	if out != "ATTACKATDAWN" {
+		t.Errorf("decrypted %q, want ATTACKATDAWN", out)
+	}
+
+	_, _, err = run("encrypt", "--file", "msg.txt", "--config", "key.json", "--keep-trailing-newline")
+	if err == nil {
+		t.Error("--keep-trailing-newline should leave the newline for the alphabet to reject")
+	}
+}
+
+func TestFitInputToAlphabetKeepsAlphabetCharacters(t *testing.T) {
+	machine, err := enigma.New(enigma.WithAlphabet([]rune("AB\n\u200B")), enigma.WithRandomSettings(enigma.Low))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd := newDecryptCommand()
+	cmd.SetErr(&bytes.Buffer{})
+
+	// Newlines and zero-width spaces are ciphertext characters for this key
+	if got, err := fitInputToAlphabet(cmd, "A\u200BB\n", machine); err != nil || got != "A\u200BB\n" {
+		t.Errorf("got %q, %v; characters of the alphabet must be kept", got, err)
+	}
+	if got, _ := fitInputToAlphabet(cmd, "A\u2060B\u00AD", machine); got != "AB" {
+		t.Errorf("got %q, want invisible characters outside the alphabet removed", got)
+	}
+
+	// Windows line endings become newlines unless CR is in the alphabet
+	if got, _ := fitInputToAlphabet(cmd, "A\r\nB\r\nA", machine); got != "A\nB\nA" {
+		t.Errorf("got %q, want CRLF normalized to LF", got)
+	}
+	crMachine, err := enigma.New(enigma.WithAlphabet([]rune("AB\r\n")), enigma.WithRandomSettings(enigma.Low))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := fitInputToAlphabet(cmd, "A\r\nB", crMachine); got != "A\r\nB" {
+		t.Errorf("got %q, a CR in the alphabet must be kept", got)
+	}
+}
+
+func TestGraphemeKeyKeepsJoiners(t *testing.T) {
+	fsys := NewMemFS()
+	const text = "Hi 👨\u200d👩\u200d👧 cafe\u0301!"
+	if err := fsys.WriteFile("msg.txt", []byte(text+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runCLI(fsys, "encrypt", "--file", "msg.txt", "--auto-config", "key.json", "--graphemes", "--output", "msg.enc"); err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	machine, err := createMachineFromConfig(fsys, configOptions{}, "key.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !machine.IsGraphemeAlphabet() {
+		t.Fatal("--graphemes should detect an alphabet of grapheme clusters")
+	}
+
+	// The joiners inside the family emoji are part of a symbol, not noise
+	out, err := runCLI(fsys, "decrypt", "--file", "msg.enc", "--config", "key.json")
+	if err != nil {
+		t.Fatalf("decrypt failed: %v", err)
+	}
+	if out != text {
+		t.Errorf("decrypt = %q, want %q", out, text)
+	}
+}
+
+func TestEncryptNormalize(t *testing.T) {
+	fsys := NewMemFS()
+	if _, err := runCLI(fsys, "encrypt", "--text", "Cafe\u0301 caf\u00e9", "--auto-config", "key.json", "--normalize", "nfc"); err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	machine, err := createMachineFromConfig(fsys, configOptions{}, "key.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := machine.GetNormalization(); got != enigma.NormalizationNFC {
+		t.Errorf("normalization = %v, want nfc", got)
+	}
+
+	// Either form of the accent enciphers with the saved key
+	ciphertext, err := machine.Encrypt("cafe\u0301")
+	if err != nil {
+		t.Fatalf("the decomposed accent should be accepted: %v", err)
+	}
+	out, err := runCLI(fsys, "decrypt", "--text", ciphertext, "--config", "key.json")
+	if err != nil {
+		t.Fatalf("decrypt failed: %v", err)
+	}
+	if want := "caf\u00e9"; out != want {
+		t.Errorf("decrypt = %+q, want %+q", out, want)
+	}
+
+	if _, err := runCLI(fsys, "encrypt", "--text", "abc", "--normalize", "nfkc"); err == nil {
+		t.Error("an unknown normalization form should be rejected")
+	}
+}
+
+func TestEncryptPreserveCase(t *testing.T) {
+	fsys := NewMemFS()
+	const text = "AttackAtDawn"
+	ciphertext, err := runCLI(fsys, "encrypt", "--text", text, "--preset", "classic", "--preserve-case", "--save-config", "key.json")
+	if err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	machine, err := createMachineFromConfig(fsys, configOptions{}, "key.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !machine.IsCaseFolding() {
+		t.Error("case folding should be saved with the configuration")
+	}
+	// The classic alphabet is all letters, so the case survives
+	out, err := runCLI(fsys, "decrypt", "--text", ciphertext, "--config", "key.json")
+	if err != nil {
+		t.Fatalf("decrypt failed: %v", err)
+	}
+	if out != text {
+		t.Errorf("decrypt = %q, want %q", out, text)
+	}
+}
@@ -0,0 +1,203 @@
+// Package cli provides the key directory, --key lookup and the key command.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// keyDirEnv overrides the key directory, ~/.enigoma/keys by default.
+const keyDirEnv = "ENIGOMA_KEY_DIR"
+
+// fingerprintKeyPrefix marks a --key value as a fingerprint prefix.
+const fingerprintKeyPrefix = "fp:"
+
+// newKeyCommand creates the key command and its subcommands.
+func newKeyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "key",
+		Short: "List and locate keys in the key directory",
+		Long: `List and locate keys kept in the key directory, ~/.enigoma/keys
+(or $ENIGOMA_KEY_DIR).
+
+Any command that takes --config also takes --key, which names a key in the
+directory instead of a path: --key work uses work.json, and --key fp:ab12cd
+uses the key whose fingerprint starts with ab12cd.
+
+Examples:
+  enigoma keygen --preset m3 --output ~/.enigoma/keys/work.json
+  enigoma key list
+  enigoma encrypt --key work --text "Hello"
+  enigoma decrypt --key fp:ab12cd --file message.txt`,
+	}
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List the keys in the key directory",
+		Args:  cobra.NoArgs,
+		RunE:  runKeyList,
+	}
+	cmd.AddCommand(list)
+
+	path := &cobra.Command{
+		Use:   "path <name | fp:prefix>",
+		Short: "Print the file a key name or fingerprint prefix refers to",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runKeyPath,
+	}
+	cmd.AddCommand(path)
+
+	return cmd
+}
+
+func runKeyList(cmd *cobra.Command, args []string) error {
+	dir, err := keyDir()
+	if err != nil {
+		return err
+	}
+	fsys, opts := fileSystem(cmd), configOptionsFor(cmd)
+	files, err := keyFiles(fsys, dir)
+	if err != nil {
+		return err
+	}
+
+	out := uiOut(cmd)
+	if len(files) == 0 {
+		fmt.Fprintf(out, "No keys in %s. Save one there with: enigoma keygen --output %s\n",
+			dir, filepath.Join(dir, "name.json"))
+		return nil
+	}
+
+	fmt.Fprintf(out, "%-20s %-14s %s\n", "NAME", "FINGERPRINT", "DESCRIPTION")
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".json")
+		machine, err := createMachineFromConfig(fsys, opts, file)
+		if err != nil {
+			fmt.Fprintf(out, "%-20s %-14s ❌ %s: %v\n", name, "-", paint(colorRed, "unreadable"), err)
+			continue
+		}
+		fingerprint, err := machine.Fingerprint()
+		if err != nil {
+			return fmt.Errorf("failed to fingerprint %s: %v", file, err)
+		}
+		fmt.Fprintf(out, "%-20s %-14s %s\n", name, shortFingerprint(fingerprint), machine.GetMetadata().Description)
+	}
+	return nil
+}
+
+func runKeyPath(cmd *cobra.Command, args []string) error {
+	path, err := resolveKey(fileSystem(cmd), configOptionsFor(cmd), args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), path)
+	return nil
+}
+
+// keyDir returns the directory named keys are kept in.
+func keyDir() (string, error) {
+	if dir := os.Getenv(keyDirEnv); dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot locate the key directory (set %s): %v", keyDirEnv, err)
+	}
+	return filepath.Join(home, ".enigoma", "keys"), nil
+}
+
+// ensureKeyDir creates the key directory, private to the user, when path is
+// a key being saved into it for the first time.
+func ensureKeyDir(fsys FS, path string) error {
+	dir, err := keyDir()
+	if err != nil || filepath.Clean(filepath.Dir(path)) != filepath.Clean(dir) {
+		return nil
+	}
+	if err := fsys.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create key directory %s: %v", dir, err)
+	}
+	return nil
+}
+
+// keyFiles lists the *.json files in the key directory. A missing directory
+// holds no keys.
+func keyFiles(fsys FS, dir string) ([]string, error) {
+	if _, err := fsys.Stat(dir); os.IsNotExist(err) {
+		return nil, nil
+	}
+	return fsys.Glob(filepath.Join(dir, "*.json"))
+}
+
+// resolveKey maps a --key value to a configuration file in the key
+// directory: "work" and "work.json" name work.json, and "fp:ab12cd" names the
+// only key whose fingerprint starts with ab12cd.
+func resolveKey(fsys FS, opts configOptions, ref string) (string, error) {
+	dir, err := keyDir()
+	if err != nil {
+		return "", err
+	}
+
+	if prefix, ok := strings.CutPrefix(ref, fingerprintKeyPrefix); ok {
+		return resolveKeyFingerprint(fsys, opts, dir, strings.ToLower(prefix))
+	}
+
+	if ref == "" || strings.ContainsAny(ref, `/\`) {
+		return "", fmt.Errorf("invalid key name %q: use a name from 'enigoma key list', or --config for a path", ref)
+	}
+	path := filepath.Join(dir, strings.TrimSuffix(ref, ".json")+".json")
+	if _, err := fsys.Stat(path); err != nil {
+		return "", fmt.Errorf("no key named %q in %s (see 'enigoma key list')", ref, dir)
+	}
+	return path, nil
+}
+
+// resolveKeyFingerprint finds the key in dir whose fingerprint starts with prefix.
+func resolveKeyFingerprint(fsys FS, opts configOptions, dir, prefix string) (string, error) {
+	if len(prefix) < 4 || strings.Trim(prefix, "0123456789abcdef") != "" {
+		return "", fmt.Errorf("invalid fingerprint prefix %q: use at least 4 hex digits", prefix)
+	}
+	files, err := keyFiles(fsys, dir)
+	if err != nil {
+		return "", err
+	}
+
+	var matches []string
+	for _, file := range files {
+		if fp, err := fingerprintConfigFile(fsys, opts, file); err == nil && strings.HasPrefix(fp, prefix) {
+			matches = append(matches, file)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no key in %s has a fingerprint starting with %s", dir, prefix)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("fingerprint prefix %s matches %d keys (%s); use a longer prefix",
+			prefix, len(matches), strings.Join(matches, ", "))
+	}
+}
+
+// applyKeyFlag resolves --key and sets --config to the key's file, so every
+// command that reads --config accepts --key as well.
+func applyKeyFlag(cmd *cobra.Command) error {
+	ref, _ := cmd.Flags().GetString("key")
+	if ref == "" {
+		return nil
+	}
+	if config, _ := cmd.Flags().GetString("config"); config != "" {
+		return fmt.Errorf("use either --key or --config, not both")
+	}
+	path, err := resolveKey(fileSystem(cmd), configOptionsFor(cmd), ref)
+	if err != nil {
+		return err
+	}
+	return cmd.Flags().Set("config", path)
+}
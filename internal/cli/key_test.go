@@ -0,0 +1,100 @@
+// Package cli provides unit tests for the key directory and --key lookup.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestKeyFlagByName(t *testing.T) {
+	t.Setenv(keyDirEnv, "keys")
+	fsys := NewMemFS()
+	if _, err := runCLI(fsys, "keygen", "--preset", "m3", "--positions", "AAA", "--output", filepath.Join("keys", "work.json")); err != nil {
+		t.Fatalf("keygen failed: %v", err)
+	}
+
+	for _, ref := range []string{"work", "work.json"} {
+		out, err := runCLI(fsys, "encrypt", "--key", ref, "--text", "AAAAA")
+		if err != nil {
+			t.Fatalf("encrypt --key %s failed: %v", ref, err)
+		}
+		if strings.TrimSpace(out) != "BDZGO" {
+			t.Errorf("encrypt --key %s = %q, want BDZGO", ref, out)
+		}
+	}
+
+	if _, err := runCLI(fsys, "encrypt", "--key", "home", "--text", "A"); err == nil || !strings.Contains(err.Error(), `no key named "home"`) {
+		t.Errorf("unknown key error = %v", err)
+	}
+	if _, err := runCLI(fsys, "encrypt", "--key", "../work", "--text", "A"); err == nil {
+		t.Error("expected an error for a key name with a path")
+	}
+	if _, err := runCLI(fsys, "encrypt", "--key", "work", "--config", "other.json", "--text", "A"); err == nil {
+		t.Error("expected an error for --key with --config")
+	}
+}
+
+func TestKeyFlagByFingerprint(t *testing.T) {
+	t.Setenv(keyDirEnv, "keys")
+	fsys := NewMemFS()
+	for _, name := range []string{"a", "b"} {
+		if _, err := runCLI(fsys, "keygen", "--alphabet", "latin", "--output", filepath.Join("keys", name+".json")); err != nil {
+			t.Fatalf("keygen failed: %v", err)
+		}
+	}
+	fp, err := fingerprintConfigFile(fsys, configOptions{}, filepath.Join("keys", "b.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runCLI(fsys, "key", "path", "fp:"+fp[:8])
+	if err != nil {
+		t.Fatalf("key path failed: %v", err)
+	}
+	if strings.TrimSpace(out) != filepath.Join("keys", "b.json") {
+		t.Errorf("key path fp:%s = %q, want keys/b.json", fp[:8], out)
+	}
+	if _, err := runCLI(fsys, "encrypt", "--key", "fp:"+strings.ToUpper(fp[:8]), "--text", "HELLO"); err != nil {
+		t.Errorf("encrypt with an uppercase fingerprint prefix failed: %v", err)
+	}
+
+	for _, ref := range []string{"fp:ab", "fp:xyz123", "fp:" + strings.Repeat("0", 64)} {
+		if _, err := runCLI(fsys, "key", "path", ref); err == nil {
+			t.Errorf("key path %s: expected an error", ref)
+		}
+	}
+}
+
+func TestKeyList(t *testing.T) {
+	t.Setenv(keyDirEnv, "keys")
+	fsys := NewMemFS()
+	out, err := runCLI(fsys, "key", "list")
+	if err != nil {
+		t.Fatalf("key list failed: %v", err)
+	}
+	if !strings.Contains(out, "No keys in keys") {
+		t.Errorf("empty key directory output:\n%s", out)
+	}
+
+	if _, err := runCLI(fsys, "keygen", "--preset", "m3", "--output", filepath.Join("keys", "work.json")); err != nil {
+		t.Fatalf("keygen failed: %v", err)
+	}
+	if err := writeStringToFile(fsys, "{}", filepath.Join("keys", "broken.json")); err != nil {
+		t.Fatal(err)
+	}
+	out, err = runCLI(fsys, "key", "list")
+	if err != nil {
+		t.Fatalf("key list failed: %v", err)
+	}
+	fp, _ := fingerprintConfigFile(fsys, configOptions{}, filepath.Join("keys", "work.json"))
+	if !strings.Contains(out, "work") || !strings.Contains(out, shortFingerprint(fp)) {
+		t.Errorf("key list should show the key and its fingerprint:\n%s", out)
+	}
+	if !strings.Contains(out, "broken") || !strings.Contains(out, "unreadable") {
+		t.Errorf("key list should flag unreadable files:\n%s", out)
+	}
+}
@@ -6,16 +6,22 @@ package cli
 
 import (
 	"fmt"
+	"math/big"
 	"os"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/coredds/enigoma/pkg/enigma"
 	"github.com/spf13/cobra"
 )
 
-var keygenCmd = &cobra.Command{
-	Use:   "keygen",
-	Short: "Generate random Enigma machine configurations",
-	Long: `Generate random Enigma machine configurations with specified parameters.
+// newKeygenCommand creates the keygen command.
+func newKeygenCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "keygen",
+		Short: "Generate random Enigma machine configurations",
+		Long: `Generate random Enigma machine configurations with specified parameters.
 
 The generated configuration can be saved to a file and used later with the
 --config flag in encrypt/decrypt commands.
@@ -23,43 +29,185 @@ The generated configuration can be saved to a file and used later with the
 Examples:
   enigoma keygen --security high --alphabet latin --output my-key.json
   enigoma keygen --preset classic --output classic-key.json
-  enigoma keygen --security extreme --alphabet portuguese --save-to keys/extreme-pt.json`,
-	RunE: runKeygen,
-}
+  enigoma keygen --security extreme --alphabet portuguese --save-to keys/extreme-pt.json
+  enigoma keygen --security high --expires-in 90d --output quarterly-key.json
+  enigoma keygen --security low --reflector-pairs "AY BR CU DH EQ FS GL IP JX KN MO TZ VW"
+  enigoma keygen --preset m3 --plugboard A:Z --plugboard-random 9 --output demo-key.json
+  enigoma keygen --passphrase-env ENIGOMA_PASSPHRASE --security high --output shared.json
+  enigoma keygen --security high --output my-key.yaml
+  enigoma keygen --preset m3 --format toml
+  enigoma keygen --alphabet-ranges "Cyrillic&Lu,0-9" --output cyrillic-key.json
+  enigoma keygen --alphabet-file symbols.txt --output symbols-key.json
+  enigoma keygen --security high --seed 42 --output demo-key.json
+
+--reflector-pairs wires the reflector by hand: list every character of the
+alphabet exactly once, two characters per pair, separated by spaces or commas.
+
+--seed derives the whole machine (wiring, notches, reflector, plugboard pairs,
+positions and ring settings) from a number, so the same command always
+prints the same key; with --preset it only chooses the rotor positions. A
+seed is easy to guess: use it for tests and demonstrations, not for secrets.
+
+--no-reflector generates an experimental, non-historical machine whose signal
+passes through the rotors only once, so letters may encrypt to themselves.
+Such keys are not reciprocal: always use 'decrypt' to reverse 'encrypt'.
+
+--reflector-fixed-points N lets the generated reflector leave N characters in
+place, so they may encrypt to themselves while the machine stays reciprocal.
+An odd N pairs an odd-sized alphabet.
+
+--passphrase-env derives the whole machine (wiring, reflector, plugboard and
+positions) from the passphrase in an environment variable. Anyone running the
+same command with the same passphrase, --security and --alphabet gets the same
+key, so the JSON never has to be exchanged. "auto" means portuguese here.
+
+--alphabet-ranges builds the alphabet from Unicode instead of a named one:
+comma-separated general categories (Lu, Nd), scripts (Cyrillic, Greek),
+character ranges (A-Z, U+0410-U+044F) and single characters (U+00C7), in code
+point order. Terms joined by "&" keep only the characters in all of them, so
+"Cyrillic&Lu,0-9" is the uppercase Cyrillic letters plus the ASCII digits.
+A reflector needs an even number of characters; add or remove one, or use
+--no-reflector.
+
+--alphabet-file reads the alphabet from a file: every character in it, in
+order, spaces included; line breaks are ignored, so a long alphabet may span
+several lines. Characters must be unique and, with a reflector, even in number.
+
+--no-plugboard generates a machine without a plugboard stage, like the
+Enigma G and K. It cannot be combined with plugboard pairs or --uhr.
+
+--uhr attaches the Uhr, which connects exactly ten plugboard pairs through a
+40-position rotating switch instead of swapping them, e.g.
+  enigoma keygen --preset m3 --plugboard A:D,C:N,E:T,F:L,G:I,J:V,K:Z,P:U,Q:Y,W:X --uhr 27 -o uhr.json`,
+		RunE: runKeygen,
+	}
 
-func init() {
 	// Machine configuration
-	keygenCmd.Flags().StringP("preset", "p", "", "Base preset to modify (classic, simple, low, medium, high, extreme)")
-	keygenCmd.Flags().StringP("alphabet", "a", "latin", "Alphabet to use (latin, greek, cyrillic, portuguese, ascii, alphanumeric)")
-	keygenCmd.Flags().StringP("security", "s", "medium", "Security level (low, medium, high, extreme)")
+	cmd.Flags().StringP("preset", "p", "", fmt.Sprintf("Base preset to modify (%s)", strings.Join(presetNames(), ", ")))
+	cmd.Flags().StringP("alphabet", "a", "latin", "Alphabet to use ("+alphabetNameList(false)+")")
+	addAlphabetFileFlag(cmd)
+	cmd.Flags().String("alphabet-ranges", "", "Build the alphabet from Unicode categories, scripts and ranges (e.g. \"Cyrillic&Lu,0-9\")")
+	cmd.Flags().StringP("security", "s", "medium", "Security level (low, medium, high, extreme)")
 
 	// Output options
-	keygenCmd.Flags().StringP("output", "o", "", "Output file for the configuration (default: stdout)")
-	keygenCmd.Flags().StringP("save-to", "", "", "Save configuration to file (alias for --output)")
-	keygenCmd.Flags().StringP("format", "f", "json", "Output format (json, yaml)")
+	cmd.Flags().StringP("output", "o", "", "Output file for the configuration (default: stdout)")
+	cmd.Flags().StringP("save-to", "", "", "Save configuration to file (alias for --output)")
+	cmd.Flags().StringP("format", "f", "json", "Output format (json, yaml, toml; default: from the --output extension)")
 
 	// Advanced options
-	keygenCmd.Flags().IntP("rotors", "r", 0, "Number of rotors (overrides security level)")
-	keygenCmd.Flags().IntP("plugboard-pairs", "", 0, "Number of plugboard pairs (overrides security level)")
-	keygenCmd.Flags().BoolP("random-positions", "", true, "Generate random rotor positions")
-	keygenCmd.Flags().Int64("seed", 0, "Deterministic seed for rotor positions (optional)")
+	cmd.Flags().IntP("rotors", "r", 0, "Number of rotors (overrides security level)")
+	cmd.Flags().IntP("plugboard-pairs", "", 0, "Number of plugboard pairs (overrides security level)")
+	cmd.Flags().BoolP("random-positions", "", true, "Generate random rotor positions")
+	cmd.Flags().Int64("seed", 0, "Derive the whole machine from this seed, or only the rotor positions with --preset")
+	cmd.Flags().StringSlice("positions", nil, "Exact starting rotor positions (e.g., 0,3,20, or ADU with --notation letters)")
+	cmd.Flags().StringSlice("plugboard", nil, "Exact plugboard pairs, replacing generated ones (e.g., A:Z,B:Y, or 1:26,2:25 with --notation numbers)")
+	cmd.Flags().Int("plugboard-random", 0, "Add this many random plugboard pairs to the --plugboard pairs")
+	cmd.Flags().StringSlice("ring-settings", nil, "Ring settings, numbered from 1 (e.g., 1,5,20 or A,E,T)")
+	addNotationFlag(cmd, notationIndex)
+	cmd.Flags().String("expires-in", "", "Record an expiry date in the key metadata (e.g. 90d, 12w)")
+	cmd.Flags().Bool("no-reflector", false, "Experimental: omit the reflector (non-historical, not reciprocal)")
+	cmd.Flags().Bool("no-plugboard", false, "Omit the plugboard stage entirely")
+	cmd.Flags().String("reflector-pairs", "", "Exact reflector wiring as pairs (e.g. \"AY BR CU ...\")")
+	cmd.Flags().Int("reflector-fixed-points", 0, "Characters the generated reflector leaves in place (non-historical)")
+	cmd.Flags().Int("uhr", 0, "Route the ten plugboard pairs through the Uhr at this position (0-39)")
+	cmd.Flags().String("passphrase-env", "", "Derive the machine from the passphrase in this environment variable (e.g. ENIGOMA_PASSPHRASE)")
 
 	// Information options
-	keygenCmd.Flags().BoolP("describe", "d", false, "Show description of generated configuration")
-	keygenCmd.Flags().BoolP("stats", "", false, "Show statistics about the configuration")
+	cmd.Flags().BoolP("describe", "d", false, "Show description of generated configuration")
+	cmd.Flags().BoolP("stats", "", false, "Show statistics about the configuration")
+
+	return cmd
 }
 
 func runKeygen(cmd *cobra.Command, args []string) error {
 	setupVerbose(cmd)
 
+	noReflector, _ := cmd.Flags().GetBool("no-reflector")
+	if preset, _ := cmd.Flags().GetString("preset"); noReflector && preset != "" {
+		return fmt.Errorf("--no-reflector cannot be combined with --preset; use --security instead")
+	}
+	if cmd.Flags().Changed("reflector-fixed-points") {
+		for _, name := range []string{"preset", "no-reflector", "reflector-pairs"} {
+			if cmd.Flags().Changed(name) {
+				return fmt.Errorf("--reflector-fixed-points cannot be combined with --%s", name)
+			}
+		}
+	}
+	if noPlugboard, _ := cmd.Flags().GetBool("no-plugboard"); noPlugboard {
+		if preset, _ := cmd.Flags().GetString("preset"); preset != "" {
+			return fmt.Errorf("--no-plugboard cannot be combined with --preset; use --security instead")
+		}
+		for _, name := range []string{"plugboard", "plugboard-pairs", "plugboard-random", "uhr"} {
+			if cmd.Flags().Changed(name) {
+				return fmt.Errorf("--%s cannot be combined with --no-plugboard", name)
+			}
+		}
+	}
+	passphraseEnv, _ := cmd.Flags().GetString("passphrase-env")
+	if passphraseEnv != "" {
+		for _, name := range []string{"preset", "rotors", "plugboard", "plugboard-pairs", "plugboard-random", "random-positions", "seed", "ring-settings", "no-reflector", "no-plugboard", "reflector-pairs", "reflector-fixed-points", "uhr"} {
+			if cmd.Flags().Changed(name) {
+				return fmt.Errorf("--%s cannot be combined with --passphrase-env", name)
+			}
+		}
+	}
+	if err := checkAlphabetFileFlag(cmd); err != nil {
+		return err
+	}
+	if cmd.Flags().Changed("alphabet-ranges") {
+		for _, name := range []string{"alphabet", "preset", "passphrase-env"} {
+			if cmd.Flags().Changed(name) {
+				return fmt.Errorf("--alphabet-ranges cannot be combined with --%s", name)
+			}
+		}
+	}
+	if cmd.Flags().Changed("plugboard-random") && cmd.Flags().Changed("seed") {
+		return fmt.Errorf("--plugboard-random cannot be combined with --seed, which would not reproduce its pairs; pin pairs with --plugboard instead")
+	}
+	if cmd.Flags().Changed("plugboard-random") && cmd.Flags().Changed("plugboard-pairs") {
+		return fmt.Errorf("--plugboard-random cannot be combined with --plugboard-pairs; pin pairs with --plugboard instead")
+	}
+
 	// Create machine based on parameters
-	machine, err := createMachineFromFlags(cmd, "")
+	var (
+		machine *enigma.Enigma
+		err     error
+	)
+	if passphraseEnv != "" {
+		passphrase := os.Getenv(passphraseEnv)
+		if passphrase == "" {
+			return fmt.Errorf("environment variable %s is empty or unset", passphraseEnv)
+		}
+		machine, err = createMachineFromPassphrase(cmd, passphrase, "passphrase in "+passphraseEnv)
+	} else {
+		machine, err = createMachineFromFlags(cmd, "")
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create Enigma machine: %v", err)
 	}
+	if err := reportMachineWarnings(cmd, machine); err != nil {
+		return err
+	}
+
+	// Replace the generated reflector with explicit wiring
+	if pairsFlag, _ := cmd.Flags().GetString("reflector-pairs"); pairsFlag != "" {
+		if noReflector {
+			return fmt.Errorf("--reflector-pairs cannot be combined with --no-reflector")
+		}
+		pairs, err := parseReflectorPairs(pairsFlag)
+		if err != nil {
+			return fmt.Errorf("invalid --reflector-pairs: %v", err)
+		}
+		if err := enigma.WithReflectorPairs(pairs)(machine); err != nil {
+			return err
+		}
+	}
 
 	// Apply rotor positions if requested
-	if randomPos, _ := cmd.Flags().GetBool("random-positions"); randomPos {
+	// A passphrase or seeded machine already has its derived positions
+	preset, _ := cmd.Flags().GetString("preset")
+	seeded := cmd.Flags().Changed("seed") && preset == ""
+	if randomPos, _ := cmd.Flags().GetBool("random-positions"); randomPos && passphraseEnv == "" && !seeded {
 		if cmd.Flags().Changed("seed") {
 			seed, _ := cmd.Flags().GetInt64("seed")
 			if err := enigma.WithRandomRotorPositionsSeed(seed)(machine); err != nil {
@@ -72,7 +220,39 @@ func runKeygen(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Machine is ready for configuration export
+	// Explicit positions and pairs override generated ones
+	if err := applyPositionFlags(cmd, machine, "positions"); err != nil {
+		return err
+	}
+
+	// Random pairs around the pinned --plugboard pairs
+	if cmd.Flags().Changed("plugboard-random") {
+		count, _ := cmd.Flags().GetInt("plugboard-random")
+		fixed := map[rune]rune{}
+		if pairValues, _ := cmd.Flags().GetStringSlice("plugboard"); len(pairValues) > 0 {
+			settings, err := machine.GetSettings()
+			if err != nil {
+				return fmt.Errorf("failed to read machine settings: %v", err)
+			}
+			fixed = settings.PlugboardPairs
+		}
+		if err := enigma.WithPlugboardConfigurationAndRandom(fixed, count)(machine); err != nil {
+			return fmt.Errorf("invalid --plugboard-random: %v", err)
+		}
+	}
+
+	// The Uhr takes over the final plugboard pairs
+	if cmd.Flags().Changed("uhr") {
+		position, _ := cmd.Flags().GetInt("uhr")
+		if err := enigma.WithUhr(position)(machine); err != nil {
+			return fmt.Errorf("invalid --uhr: %v", err)
+		}
+	}
+
+	// Record key lifecycle metadata
+	if err := applyKeyMetadata(cmd, machine); err != nil {
+		return err
+	}
 
 	// Show description if requested
 	if describe, _ := cmd.Flags().GetBool("describe"); describe {
@@ -84,22 +264,33 @@ func runKeygen(cmd *cobra.Command, args []string) error {
 		showConfigurationStats(machine, cmd)
 	}
 
-	// Convert to JSON
+	// Serialize in the requested format
+	outputFile, _ := cmd.Flags().GetString("output")
+	if outputFile == "" {
+		outputFile, _ = cmd.Flags().GetString("save-to")
+	}
+	format, err := outputConfigFormat(cmd, outputFile)
+	if err != nil {
+		return err
+	}
 	jsonData, err := machine.SaveSettingsToJSON()
 	if err != nil {
 		return fmt.Errorf("failed to serialize settings: %v", err)
 	}
-
-	// Output the configuration
-	outputFile, _ := cmd.Flags().GetString("output")
-	if outputFile == "" {
-		outputFile, _ = cmd.Flags().GetString("save-to")
+	data, err := convertConfigText(jsonData, format)
+	if err != nil {
+		return fmt.Errorf("failed to serialize settings: %v", err)
 	}
 
+	// Output the configuration
 	if outputFile == "" {
-		fmt.Fprint(cmd.OutOrStdout(), jsonData)
+		sealed, err := encryptConfigIfRequested(configOptionsFor(cmd), data)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(cmd.OutOrStdout(), sealed)
 	} else {
-		err := writeStringToFile(jsonData, outputFile)
+		err := writeConfigFile(fileSystem(cmd), configOptionsFor(cmd), outputFile, data)
 		if err != nil {
 			return fmt.Errorf("failed to write configuration to file: %v", err)
 		}
@@ -109,71 +300,100 @@ func runKeygen(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// applyKeyMetadata stamps the creation time and optional expiry onto a new key.
+func applyKeyMetadata(cmd *cobra.Command, machine *enigma.Enigma) error {
+	meta := machine.GetMetadata()
+	if meta == nil {
+		meta = &enigma.Metadata{}
+	}
+	created := now().UTC()
+	meta.CreatedAt = created.Format(time.RFC3339)
+	meta.CreatedBy = "enigoma keygen"
+
+	if expiresIn, _ := cmd.Flags().GetString("expires-in"); expiresIn != "" {
+		d, err := parseLongDuration(expiresIn)
+		if err != nil {
+			return fmt.Errorf("invalid --expires-in: %v", err)
+		}
+		meta.ExpiresAt = created.Add(d).Format(time.RFC3339)
+	}
+
+	machine.SetMetadata(meta)
+	return nil
+}
+
 func showConfigurationDescription(machine *enigma.Enigma, cmd *cobra.Command) {
 	fmt.Fprintf(cmd.OutOrStdout(), "Configuration Description:\n")
 	fmt.Fprintf(cmd.OutOrStdout(), "  Alphabet Size: %d characters\n", machine.GetAlphabetSize())
 	fmt.Fprintf(cmd.OutOrStdout(), "  Rotors: %d\n", machine.GetRotorCount())
-	fmt.Fprintf(cmd.OutOrStdout(), "  Plugboard Pairs: %d\n", machine.GetPlugboardPairCount())
-	fmt.Fprintf(cmd.OutOrStdout(), "  Current Rotor Positions: %v\n", machine.GetCurrentRotorPositions())
+	if machine.IsPlugboardDisabled() {
+		fmt.Fprintf(cmd.OutOrStdout(), "  Plugboard: none\n")
+	} else {
+		fmt.Fprintf(cmd.OutOrStdout(), "  Plugboard Pairs: %d\n", machine.GetPlugboardPairCount())
+	}
+	if machine.IsReflectorless() {
+		fmt.Fprintf(cmd.OutOrStdout(), "  Reflector: none (experimental, non-historical)\n")
+	} else if n := machine.ReflectorFixedPoints(); n > 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "  Reflector Fixed Points: %d\n", n)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "  Current Rotor Positions: %s\n", machinePositions(cmd, machine))
 	fmt.Fprintf(cmd.OutOrStdout(), "\n")
 }
 
+// showConfigurationStats prints the keyspace of the machine's shape (see
+// enigma.KeyspaceReport).
 func showConfigurationStats(machine *enigma.Enigma, cmd *cobra.Command) {
-	alphabetSize := machine.GetAlphabetSize()
-	rotorCount := machine.GetRotorCount()
-	plugboardPairs := machine.GetPlugboardPairCount()
-
-	// Calculate approximate keyspace (simplified calculation)
-	rotorCombinations := calculateFactorial(rotorCount)
-	rotorPositions := calculatePower(alphabetSize, rotorCount)
-	plugboardCombinations := calculatePlugboardCombinations(alphabetSize, plugboardPairs)
-
-	fmt.Fprintf(cmd.OutOrStdout(), "Configuration Statistics:\n")
-	fmt.Fprintf(cmd.OutOrStdout(), "  Rotor Combinations: ~%g\n", float64(rotorCombinations))
-	fmt.Fprintf(cmd.OutOrStdout(), "  Rotor Position Combinations: %d\n", rotorPositions)
-	fmt.Fprintf(cmd.OutOrStdout(), "  Plugboard Combinations: ~%g\n", float64(plugboardCombinations))
-	fmt.Fprintf(cmd.OutOrStdout(), "  Approximate Total Keyspace: ~%g\n",
-		float64(rotorCombinations)*float64(rotorPositions)*float64(plugboardCombinations))
-	fmt.Fprintf(cmd.OutOrStdout(), "\n")
-}
+	r := machine.KeyspaceReport()
+	out := cmd.OutOrStdout()
 
-func calculateFactorial(n int) int64 {
-	if n <= 1 {
-		return 1
-	}
-	result := int64(1)
-	for i := 2; i <= n; i++ {
-		result *= int64(i)
+	fmt.Fprintf(out, "Configuration Statistics:\n")
+	fmt.Fprintf(out, "  Rotor Wirings (%d rotors): %s\n", r.RotorCount, formatCount(r.RotorWirings))
+	fmt.Fprintf(out, "  Rotor Position Combinations: %s\n", formatCount(r.RotorPositions))
+	if machine.IsReflectorless() {
+		fmt.Fprintf(out, "  Reflector Wirings: none\n")
+	} else {
+		fmt.Fprintf(out, "  Reflector Wirings: %s\n", formatCount(r.ReflectorWirings))
 	}
-	return result
+	fmt.Fprintf(out, "  Plugboard Combinations (%d pairs): %s\n", r.PlugboardPairs, formatCount(r.PlugboardCombinations))
+	fmt.Fprintf(out, "  Total Keyspace: %s\n", formatCount(r.Total))
+	fmt.Fprintf(out, "\n")
 }
 
-func calculatePower(base, exp int) int {
-	if exp == 0 {
-		return 1
-	}
-	result := 1
-	for i := 0; i < exp; i++ {
-		result *= base
+// formatCount writes a count in full up to 15 digits and in scientific
+// notation beyond, followed by its size in bits. Counts are never converted
+// to a machine integer or float, which the largest keyspaces overflow.
+func formatCount(x *big.Int) string {
+	bits := enigma.KeyspaceBits(x)
+	if s := x.String(); len(s) <= 15 {
+		return fmt.Sprintf("%s (%.1f bits)", s, bits)
 	}
-	return result
+	return fmt.Sprintf("%s (%.1f bits)", new(big.Float).SetInt(x).Text('e', 3), bits)
 }
 
-func calculatePlugboardCombinations(alphabetSize, pairs int) int64 {
-	if pairs == 0 {
-		return 1
+// parseReflectorPairs parses pairs such as "AY BR CU" (spaces or commas between
+// pairs) into a map with one entry per pair.
+func parseReflectorPairs(s string) (map[rune]rune, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no pairs given")
 	}
-	// Simplified calculation: C(n,2k) for k pairs from n characters
-	// This is a rough approximation
-	available := alphabetSize
-	combinations := int64(1)
-	for i := 0; i < pairs; i++ {
-		combinations *= int64(available * (available - 1) / 2)
-		available -= 2
+
+	pairs := make(map[rune]rune, len(fields))
+	for _, field := range fields {
+		runes := []rune(field)
+		if len(runes) != 2 {
+			return nil, fmt.Errorf("pair %q must have exactly two characters", field)
+		}
+		if _, dup := pairs[runes[0]]; dup {
+			return nil, fmt.Errorf("character %c appears in more than one pair", runes[0])
+		}
+		pairs[runes[0]] = runes[1]
 	}
-	return combinations
+	return pairs, nil
 }
 
-func writeStringToFile(content, filename string) error {
-	return os.WriteFile(filename, []byte(content), 0600)
+func writeStringToFile(fsys FS, content, filename string) error {
+	return fsys.WriteFile(filename, []byte(content), 0600)
 }
@@ -0,0 +1,208 @@
+// Package cli provides the keyring command and key expiry checks.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/coredds/enigoma/pkg/enigma"
+	"github.com/spf13/cobra"
+)
+
+// newKeyringCommand creates the keyring command and its subcommands.
+func newKeyringCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "keyring",
+		Short: "Inspect a collection of key files",
+		Long: `Inspect a collection of key (configuration) files.
+
+Keys generated with 'enigoma keygen --expires-in 90d' carry an expiry date in
+their metadata. Encrypting or decrypting with an expired key prints a warning,
+or fails when --enforce-expiry is given.
+
+Examples:
+  enigoma keyring status
+  enigoma keyring status keys/ --warn-within 30d
+  enigoma keyring status work.json personal.json`,
+	}
+
+	status := &cobra.Command{
+		Use:   "status [files or directories...]",
+		Short: "Summarize key ages and upcoming expirations",
+		RunE:  runKeyringStatus,
+	}
+	status.Flags().String("warn-within", "14d", "Flag keys expiring within this period (e.g. 7d, 2w, 48h)")
+	cmd.AddCommand(status)
+
+	return cmd
+}
+
+// keyStatus summarizes the lifecycle of one key file.
+type keyStatus struct {
+	Path      string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	Err       error
+}
+
+func runKeyringStatus(cmd *cobra.Command, args []string) error {
+	warnWithinStr, _ := cmd.Flags().GetString("warn-within")
+	warnWithin, err := parseLongDuration(warnWithinStr)
+	if err != nil {
+		return fmt.Errorf("invalid --warn-within: %v", err)
+	}
+
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+
+	var statuses []keyStatus
+	for _, arg := range args {
+		found, err := collectKeyStatuses(fileSystem(cmd), configOptionsFor(cmd), arg)
+		if err != nil {
+			return err
+		}
+		statuses = append(statuses, found...)
+	}
+
+	out := uiOut(cmd)
+	if len(statuses) == 0 {
+		fmt.Fprintln(out, "No key files found.")
+		return nil
+	}
+
+	current := now()
+	var expired, expiring int
+	fmt.Fprintf(out, "%-30s %-10s %-28s %s\n", "KEY", "AGE", "EXPIRES", "STATUS")
+	for _, st := range statuses {
+		if st.Err != nil {
+			fmt.Fprintf(out, "%-30s %-10s %-28s ❌ %s: %v\n", st.Path, "-", "-", paint(colorRed, "unreadable"), st.Err)
+			continue
+		}
+
+		age := "-"
+		if !st.CreatedAt.IsZero() {
+			age = formatDays(current.Sub(st.CreatedAt))
+		}
+
+		expires, status := "never", "✅ ok"
+		if !st.ExpiresAt.IsZero() {
+			remaining := st.ExpiresAt.Sub(current)
+			switch {
+			case remaining <= 0:
+				expires = fmt.Sprintf("%s (%s ago)", st.ExpiresAt.Format("2006-01-02"), formatDays(-remaining))
+				status = "❌ " + paint(colorRed, "expired - rotate now")
+				expired++
+			case remaining <= warnWithin:
+				expires = fmt.Sprintf("%s (in %s)", st.ExpiresAt.Format("2006-01-02"), formatDays(remaining))
+				status = "⚠️  " + paint(colorYellow, "expiring soon")
+				expiring++
+			default:
+				expires = fmt.Sprintf("%s (in %s)", st.ExpiresAt.Format("2006-01-02"), formatDays(remaining))
+			}
+		}
+		fmt.Fprintf(out, "%-30s %-10s %-28s %s\n", st.Path, age, expires, status)
+	}
+
+	fmt.Fprintf(out, "\n%d key(s): %d expired, %d expiring within %s\n", len(statuses), expired, expiring, warnWithinStr)
+	return nil
+}
+
+// collectKeyStatuses reads a single key file, or every *.json key in a directory.
+func collectKeyStatuses(fsys FS, opts configOptions, path string) ([]keyStatus, error) {
+	info, err := fsys.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot access %s: %v", path, err)
+	}
+
+	if !info.IsDir() {
+		return []keyStatus{readKeyStatus(fsys, opts, path)}, nil
+	}
+
+	matches, err := fsys.Glob(filepath.Join(path, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var statuses []keyStatus
+	for _, match := range matches {
+		st := readKeyStatus(fsys, opts, match)
+		if st.Err != nil {
+			continue // Not every JSON file in a directory is a key
+		}
+		statuses = append(statuses, st)
+	}
+	return statuses, nil
+}
+
+func readKeyStatus(fsys FS, opts configOptions, path string) keyStatus {
+	st := keyStatus{Path: path}
+
+	machine, err := createMachineFromConfig(fsys, opts, path)
+	if err != nil {
+		st.Err = err
+		return st
+	}
+
+	meta := machine.GetMetadata()
+	if st.CreatedAt, err = meta.CreatedTime(); err != nil {
+		st.Err = err
+		return st
+	}
+	if st.ExpiresAt, err = meta.ExpiryTime(); err != nil {
+		st.Err = err
+	}
+	return st
+}
+
+// checkKeyExpiry warns about (or with --enforce-expiry rejects) expired keys.
+func checkKeyExpiry(cmd *cobra.Command, machine *enigma.Enigma) error {
+	meta := machine.GetMetadata()
+	expired, err := meta.IsExpired(now())
+	if err != nil {
+		return fmt.Errorf("key metadata: %v", err)
+	}
+	if !expired {
+		return nil
+	}
+
+	if enforce, _ := cmd.Flags().GetBool("enforce-expiry"); enforce {
+		return fmt.Errorf("key expired on %s (--enforce-expiry is set). Generate a new key with: enigoma keygen --expires-in 90d --output new-key.json", meta.ExpiresAt)
+	}
+	return reportWarnings(cmd, enigma.Warning{
+		Code:    enigma.WarnKeyExpired,
+		Message: fmt.Sprintf("this key expired on %s. Consider rotating to a new key.", meta.ExpiresAt),
+	})
+}
+
+// parseLongDuration extends time.ParseDuration with day ("d") and week ("w") units.
+func parseLongDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if strings.HasSuffix(s, suffix) {
+			n, err := strconv.Atoi(strings.TrimSuffix(s, suffix))
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q (use e.g. 30d, 2w, 12h)", s)
+	}
+	return d, nil
+}
+
+// formatDays renders a duration as a whole number of days (or hours when short).
+func formatDays(d time.Duration) string {
+	if d < 24*time.Hour {
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
@@ -0,0 +1,146 @@
+// Package cli provides unit tests for key expiry and the keyring command.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coredds/enigoma/pkg/enigma"
+)
+
+func TestParseLongDuration(t *testing.T) {
+	tests := []struct {
+		input     string
+		expected  time.Duration
+		expectErr bool
+	}{
+		{"90d", 90 * 24 * time.Hour, false},
+		{"2w", 14 * 24 * time.Hour, false},
+		{"36h", 36 * time.Hour, false},
+		{"d", 0, true},
+		{"-3d", 0, true},
+		{"soon", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseLongDuration(tt.input)
+		if tt.expectErr {
+			if err == nil {
+				t.Errorf("parseLongDuration(%q) expected error", tt.input)
+			}
+			continue
+		}
+		if err != nil || got != tt.expected {
+			t.Errorf("parseLongDuration(%q) = %v, %v; want %v", tt.input, got, err, tt.expected)
+		}
+	}
+}
+
+// writeTestKey saves a key with the given metadata into dir and returns its path.
+func writeTestKey(t *testing.T, fsys FS, dir, name string, meta *enigma.Metadata) string {
+	t.Helper()
+	machine, err := enigma.NewEnigmaClassic()
+	if err != nil {
+		t.Fatalf("NewEnigmaClassic failed: %v", err)
+	}
+	machine.SetMetadata(meta)
+	data, err := machine.SaveSettingsToJSON()
+	if err != nil {
+		t.Fatalf("SaveSettingsToJSON failed: %v", err)
+	}
+	path := filepath.Join(dir, name)
+	if err := writeStringToFile(fsys, data, path); err != nil {
+		t.Fatalf("writing key failed: %v", err)
+	}
+	return path
+}
+
+func TestEncryptWithExpiredKey(t *testing.T) {
+	withClock(t, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+	fsys := NewMemFS()
+	key := writeTestKey(t, fsys, ".", "old.json", &enigma.Metadata{ExpiresAt: "2025-01-01T00:00:00Z"})
+
+	// Without enforcement the operation succeeds with a warning
+	cmd := NewRootCommand(Options{FS: fsys})
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	cmd.SetArgs([]string{"encrypt", "--text", "HELLO", "--config", key})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("encrypt with expired key should only warn: %v", err)
+	}
+	if !strings.Contains(stderr.String(), "expired on 2025-01-01") {
+		t.Errorf("expected expiry warning on stderr, got %q", stderr.String())
+	}
+
+	// With enforcement it fails
+	cmd = NewRootCommand(Options{FS: fsys})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"decrypt", "--text", "HELLO", "--config", key, "--enforce-expiry"})
+	if err := cmd.Execute(); err == nil {
+		t.Error("decrypt with expired key and --enforce-expiry should fail")
+	}
+}
+
+func TestKeygenExpiresIn(t *testing.T) {
+	withClock(t, time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+	fsys := NewMemFS()
+	path := "new.json"
+
+	cmd := NewRootCommand(Options{FS: fsys})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"keygen", "--preset", "classic", "--expires-in", "30d", "--output", path})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("keygen failed: %v", err)
+	}
+
+	machine, err := createMachineFromConfig(fsys, configOptions{}, path)
+	if err != nil {
+		t.Fatalf("loading generated key failed: %v", err)
+	}
+	meta := machine.GetMetadata()
+	if meta == nil || meta.CreatedAt != "2025-01-01T12:00:00Z" || meta.ExpiresAt != "2025-01-31T12:00:00Z" {
+		t.Errorf("unexpected key metadata: %+v", meta)
+	}
+}
+
+func TestKeyringStatus(t *testing.T) {
+	withClock(t, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+	fsys := NewMemFS()
+	dir := "keys"
+	if err := fsys.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	writeTestKey(t, fsys, dir, "expired.json", &enigma.Metadata{CreatedAt: "2025-01-01T00:00:00Z", ExpiresAt: "2025-05-01T00:00:00Z"})
+	writeTestKey(t, fsys, dir, "soon.json", &enigma.Metadata{CreatedAt: "2025-03-01T00:00:00Z", ExpiresAt: "2025-06-05T00:00:00Z"})
+	writeTestKey(t, fsys, dir, "fine.json", &enigma.Metadata{ExpiresAt: "2026-01-01T00:00:00Z"})
+	writeTestKey(t, fsys, dir, "forever.json", nil)
+	if err := writeStringToFile(fsys, `{"not": "a key"}`, filepath.Join(dir, "notes.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := NewRootCommand(Options{FS: fsys})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"keyring", "status", dir})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("keyring status failed: %v", err)
+	}
+
+	output := out.String()
+	for _, want := range []string{"expired - rotate now", "expiring soon", "never", "151d", "4 key(s): 1 expired, 1 expiring within 14d"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("keyring status output missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "notes.json") {
+		t.Errorf("non-key JSON files should be skipped:\n%s", output)
+	}
+}
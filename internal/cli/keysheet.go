@@ -0,0 +1,210 @@
+// Package cli provides the keysheet command for historical monthly key sheets.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/coredds/enigoma/pkg/enigma"
+	"github.com/coredds/enigoma/pkg/keysheet"
+	"github.com/spf13/cobra"
+)
+
+// newKeysheetCommand creates the keysheet command and its subcommands.
+func newKeysheetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "keysheet",
+		Short: "Generate and use historical monthly key sheets",
+		Long: `Generate and use monthly key sheets (Tagesschlüssel) like those issued to
+the historical Enigma networks. Each day of the month lists a rotor order
+(Walzenlage), ring settings (Ringstellung), ten plugboard connections
+(Steckerverbindungen) and four Kenngruppen.
+
+Sheets configure a historical Enigma M3 over A-Z. They are for interoperating
+with other Enigma simulators and for teaching, not for protecting secrets.
+
+Examples:
+  enigoma keysheet generate --month 2025-03 --network Heer --output march.txt
+  enigoma keysheet print march.txt --date 2025-03-14
+  enigoma keysheet select march.txt --date 2025-03-14 --positions QEV --output today.json`,
+	}
+
+	generate := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate a key sheet for one month",
+		Args:  cobra.NoArgs,
+		RunE:  runKeysheetGenerate,
+	}
+	generate.Flags().String("month", "", "Month the sheet covers, as YYYY-MM (default: the current month)")
+	generate.Flags().String("network", "", "Name of the key net printed on the sheet (e.g. Heer)")
+	generate.Flags().String("seed", "", "Derive the sheet from this seed instead of crypto/rand, so it can be regenerated")
+	generate.Flags().StringP("output", "o", "", "Output file (default: stdout)")
+	cmd.AddCommand(generate)
+
+	printCmd := &cobra.Command{
+		Use:   "print <sheet>",
+		Short: "Print a key sheet, or one day's key",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runKeysheetPrint,
+	}
+	printCmd.Flags().String("date", "", "Print only the key for this date (YYYY-MM-DD, or 'today')")
+	cmd.AddCommand(printCmd)
+
+	selectCmd := &cobra.Command{
+		Use:   "select <sheet>",
+		Short: "Write a configuration file for one day's key",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runKeysheetSelect,
+	}
+	selectCmd.Flags().String("date", "today", "Date whose key to select (YYYY-MM-DD, or 'today')")
+	selectCmd.Flags().StringSlice("positions", nil, "Starting rotor positions, the message key (e.g. QEV; default: AAA)")
+	addNotationFlag(selectCmd, notationLetters)
+	selectCmd.Flags().StringP("output", "o", "", "Output configuration file (required)")
+	cmd.AddCommand(selectCmd)
+
+	return cmd
+}
+
+func runKeysheetGenerate(cmd *cobra.Command, args []string) error {
+	year, month := now().Year(), now().Month()
+	if value, _ := cmd.Flags().GetString("month"); value != "" {
+		t, err := time.Parse("2006-01", value)
+		if err != nil {
+			return fmt.Errorf("invalid --month %q (want YYYY-MM)", value)
+		}
+		year, month = t.Year(), t.Month()
+	}
+	network, _ := cmd.Flags().GetString("network")
+
+	var (
+		sheet *keysheet.Sheet
+		err   error
+	)
+	if seed, _ := cmd.Flags().GetString("seed"); seed != "" {
+		sheet, err = keysheet.GenerateSeeded(network, year, month, []byte(seed))
+	} else {
+		sheet, err = keysheet.Generate(network, year, month)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to generate key sheet: %v", err)
+	}
+
+	outputFile, _ := cmd.Flags().GetString("output")
+	if outputFile == "" {
+		fmt.Fprint(cmd.OutOrStdout(), sheet.Format())
+		return nil
+	}
+	if err := writeStringToFile(fileSystem(cmd), sheet.Format(), outputFile); err != nil {
+		return fmt.Errorf("failed to write key sheet: %v", err)
+	}
+	fmt.Fprintf(uiOut(cmd), "✅ Key sheet for %04d-%02d saved to %s\n", year, month, outputFile)
+	return nil
+}
+
+func runKeysheetPrint(cmd *cobra.Command, args []string) error {
+	sheet, err := readKeysheet(cmd, args[0])
+	if err != nil {
+		return err
+	}
+	value, _ := cmd.Flags().GetString("date")
+	if value == "" {
+		fmt.Fprint(cmd.OutOrStdout(), sheet.Format())
+		return nil
+	}
+	date, err := parseKeysheetDate(value)
+	if err != nil {
+		return err
+	}
+	key, err := sheet.KeyFor(date)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Date:         %s\n", date.Format("2006-01-02"))
+	fmt.Fprintf(out, "Reflector:    %s\n", sheet.Reflector)
+	fmt.Fprintf(out, "Walzenlage:   %s\n", strings.Join(key.Rotors[:], " "))
+	fmt.Fprintf(out, "Ringstellung: %02d %02d %02d\n", key.Rings[0], key.Rings[1], key.Rings[2])
+	fmt.Fprintf(out, "Stecker:      %s\n", strings.Join(key.Plugboard, " "))
+	fmt.Fprintf(out, "Kenngruppen:  %s\n", strings.Join(key.Kenngruppen[:], " "))
+	return nil
+}
+
+func runKeysheetSelect(cmd *cobra.Command, args []string) error {
+	outputFile, _ := cmd.Flags().GetString("output")
+	if outputFile == "" {
+		return fmt.Errorf("output file required for the selected key (use --output)")
+	}
+	sheet, err := readKeysheet(cmd, args[0])
+	if err != nil {
+		return err
+	}
+	value, _ := cmd.Flags().GetString("date")
+	date, err := parseKeysheetDate(value)
+	if err != nil {
+		return err
+	}
+	key, err := sheet.KeyFor(date)
+	if err != nil {
+		return err
+	}
+	machine, err := key.Machine(sheet.Reflector)
+	if err != nil {
+		return err
+	}
+	if err := applyPositionFlags(cmd, machine, "positions"); err != nil {
+		return err
+	}
+
+	description := "Daily key for " + date.Format("2006-01-02")
+	if sheet.Network != "" {
+		description = sheet.Network + " daily key for " + date.Format("2006-01-02")
+	}
+	machine.SetMetadata(&enigma.Metadata{
+		Description: description,
+		CreatedAt:   now().UTC().Format(time.RFC3339),
+		CreatedBy:   "enigoma keysheet select",
+	})
+
+	jsonData, err := machine.SaveSettingsToJSON()
+	if err != nil {
+		return fmt.Errorf("failed to serialize settings: %v", err)
+	}
+	if err := writeConfigFile(fileSystem(cmd), configOptionsFor(cmd), outputFile, jsonData); err != nil {
+		return fmt.Errorf("failed to write configuration: %v", err)
+	}
+	fmt.Fprintf(uiOut(cmd), "✅ Key for %s (%s, rings %02d %02d %02d) saved to %s\n",
+		date.Format("2006-01-02"), strings.Join(key.Rotors[:], " "),
+		key.Rings[0], key.Rings[1], key.Rings[2], outputFile)
+	return nil
+}
+
+// readKeysheet reads and parses a key sheet file.
+func readKeysheet(cmd *cobra.Command, path string) (*keysheet.Sheet, error) {
+	data, err := fileSystem(cmd).ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key sheet: %v", err)
+	}
+	sheet, err := keysheet.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid key sheet %s: %v", path, err)
+	}
+	return sheet, nil
+}
+
+// parseKeysheetDate parses a YYYY-MM-DD date, or "today" in local time.
+func parseKeysheetDate(value string) (time.Time, error) {
+	if value == "today" {
+		return now(), nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --date %q (want YYYY-MM-DD or 'today')", value)
+	}
+	return date, nil
+}
@@ -0,0 +1,66 @@
+// Package cli provides unit tests for the keysheet command.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKeysheetGenerateAndSelect(t *testing.T) {
+	fsys := NewMemFS()
+	if _, err := runCLI(fsys, "keysheet", "generate", "--month", "2025-03", "--network", "Heer", "--seed", "test", "--output", "march.txt"); err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	again, err := runCLI(fsys, "keysheet", "generate", "--month", "2025-03", "--network", "Heer", "--seed", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved, _ := fsys.ReadFile("march.txt")
+	if again != string(saved) {
+		t.Error("the same seed printed a different sheet")
+	}
+
+	day, err := runCLI(fsys, "keysheet", "print", "march.txt", "--date", "2025-03-14")
+	if err != nil {
+		t.Fatalf("print failed: %v", err)
+	}
+	if !strings.Contains(day, "Walzenlage:") || !strings.Contains(day, "Kenngruppen:") {
+		t.Errorf("print output = %q", day)
+	}
+
+	if _, err := runCLI(fsys, "keysheet", "select", "march.txt", "--date", "2025-03-14", "--positions", "QEV", "--output", "today.json"); err != nil {
+		t.Fatalf("select failed: %v", err)
+	}
+	machine, err := createMachineFromConfig(fsys, configOptions{}, "today.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := machine.GetCurrentRotorPositions(); len(got) != 3 || got[0] != 16 || got[1] != 4 || got[2] != 21 {
+		t.Errorf("positions = %v, want [16 4 21]", got)
+	}
+	if meta := machine.GetMetadata(); meta == nil || meta.Description != "Heer daily key for 2025-03-14" {
+		t.Errorf("metadata = %+v", meta)
+	}
+}
+
+func TestKeysheetSelectErrors(t *testing.T) {
+	fsys := NewMemFS()
+	if _, err := runCLI(fsys, "keysheet", "generate", "--month", "2025-03", "--output", "march.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runCLI(fsys, "keysheet", "select", "march.txt", "--date", "2025-03-14"); err == nil {
+		t.Error("expected an error without --output")
+	}
+	if _, err := runCLI(fsys, "keysheet", "select", "march.txt", "--date", "2025-04-01", "--output", "k.json"); err == nil {
+		t.Error("expected an error for a date outside the sheet")
+	}
+	if _, err := runCLI(fsys, "keysheet", "select", "march.txt", "--date", "14.03.2025", "--output", "k.json"); err == nil {
+		t.Error("expected an error for a malformed date")
+	}
+	if _, err := runCLI(fsys, "keysheet", "generate", "--month", "March"); err == nil {
+		t.Error("expected an error for a malformed month")
+	}
+}
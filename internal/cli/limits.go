@@ -0,0 +1,72 @@
+// Package cli provides the size limits applied to configuration files.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/coredds/enigoma/pkg/enigma"
+)
+
+// maxConfigSizeEnv overrides the maximum configuration file size, in bytes
+// or with a K or M suffix (e.g. 16M). A negative value disables the limit.
+const maxConfigSizeEnv = "ENIGOMA_MAX_CONFIG_SIZE"
+
+// configLimits returns enigma.DefaultLimits with the configuration size taken
+// from ENIGOMA_MAX_CONFIG_SIZE when it is set.
+func configLimits() (enigma.Limits, error) {
+	limits := enigma.DefaultLimits
+	value := strings.TrimSpace(os.Getenv(maxConfigSizeEnv))
+	if value == "" {
+		return limits, nil
+	}
+	size, err := parseByteSize(value)
+	if err != nil {
+		return limits, fmt.Errorf("invalid %s %q: %v", maxConfigSizeEnv, value, err)
+	}
+	limits.MaxConfigBytes = size
+	return limits, nil
+}
+
+// parseByteSize parses a byte count with an optional K or M suffix (KiB, MiB).
+func parseByteSize(value string) (int, error) {
+	multiplier := 1
+	upper := strings.TrimSuffix(strings.ToUpper(value), "B")
+	switch {
+	case strings.HasSuffix(upper, "K"):
+		multiplier, upper = 1<<10, strings.TrimSuffix(upper, "K")
+	case strings.HasSuffix(upper, "M"):
+		multiplier, upper = 1<<20, strings.TrimSuffix(upper, "M")
+	}
+	n, err := strconv.Atoi(upper)
+	if err != nil {
+		return 0, fmt.Errorf("want a number of bytes, optionally with a K or M suffix")
+	}
+	if n == 0 {
+		return 0, fmt.Errorf("size cannot be zero")
+	}
+	return n * multiplier, nil
+}
+
+// loadConfig reads a configuration file and creates its machine within
+// configLimits, decrypting and checking it according to opts. Oversized files are rejected from their size on disk, before
+// they are read.
+func loadConfig(fsys FS, opts configOptions, path string) (*enigma.Enigma, error) {
+	limits, err := configLimits()
+	if err != nil {
+		return nil, err
+	}
+	if info, err := fsys.Stat(path); err == nil && limits.MaxConfigBytes > 0 && info.Size() > int64(limits.MaxConfigBytes) {
+		return nil, &enigma.LimitError{Limit: "config size", Value: int(info.Size()), Max: limits.MaxConfigBytes}
+	}
+	data, err := fsys.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %v", err)
+	}
+	return decodeConfig(fsys, opts, path, data, limits)
+}
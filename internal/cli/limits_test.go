@@ -0,0 +1,54 @@
+// Package cli provides unit tests for configuration size limits.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/coredds/enigoma/pkg/enigma"
+)
+
+func TestConfigSizeLimit(t *testing.T) {
+	fsys := NewMemFS()
+	if _, err := runCLI(fsys, "keygen", "--alphabet", "latin", "--output", "key.json"); err != nil {
+		t.Fatalf("keygen failed: %v", err)
+	}
+
+	t.Setenv(maxConfigSizeEnv, "100")
+	_, err := createMachineFromConfig(fsys, configOptions{}, "key.json")
+	var limitErr *enigma.LimitError
+	if !errors.As(err, &limitErr) || limitErr.Max != 100 {
+		t.Fatalf("error = %v, want a config size *LimitError", err)
+	}
+	if _, err := runCLI(fsys, "encrypt", "--config", "key.json", "--text", "HELLO"); err == nil || !strings.Contains(err.Error(), "exceeds the limit") {
+		t.Errorf("encrypt error = %v, want the size limit", err)
+	}
+
+	t.Setenv(maxConfigSizeEnv, "1M")
+	if _, err := createMachineFromConfig(fsys, configOptions{}, "key.json"); err != nil {
+		t.Errorf("1M limit rejected a small key: %v", err)
+	}
+
+	t.Setenv(maxConfigSizeEnv, "lots")
+	if _, err := createMachineFromConfig(fsys, configOptions{}, "key.json"); err == nil {
+		t.Error("expected an error for an invalid size")
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	tests := map[string]int{"512": 512, "64k": 64 << 10, "16M": 16 << 20, "2MB": 2 << 20, "-1": -1}
+	for value, want := range tests {
+		if got, err := parseByteSize(value); err != nil || got != want {
+			t.Errorf("parseByteSize(%q) = %d, %v; want %d", value, got, err, want)
+		}
+	}
+	for _, value := range []string{"", "0", "1G", "K"} {
+		if _, err := parseByteSize(value); err == nil {
+			t.Errorf("parseByteSize(%q): expected an error", value)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+// Package cli provides advisory locking of key and session files.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// lockSuffix is appended to a file's name to form its lock file.
+const lockSuffix = ".lock"
+
+var (
+	// lockTimeout bounds how long a command waits for another to release a file.
+	lockTimeout = 10 * time.Second
+	// lockPollInterval is how often a held lock is retried.
+	lockPollInterval = 25 * time.Millisecond
+)
+
+// Locker is implemented by filesystems that support advisory file locks. The
+// CLI locks a key or session file while it reads, modifies and writes it, so
+// concurrent invocations (a cron job and a user, say) take turns instead of
+// overwriting each other's changes. Filesystems without Lock are not locked.
+type Locker interface {
+	// Lock blocks until the caller holds the lock on name, or fails. The
+	// returned function releases it.
+	Lock(name string) (unlock func() error, err error)
+}
+
+// withFileLock runs fn while holding the lock on path. Nothing is locked when
+// the filesystem is not a Locker, which includes any filesystem returned by
+// fileSystem under --no-lock.
+func withFileLock(fsys FS, path string, fn func() error) error {
+	locker, ok := fsys.(Locker)
+	if !ok {
+		return fn()
+	}
+	unlock, err := locker.Lock(path)
+	if err != nil {
+		return err
+	}
+	err = fn()
+	if uerr := unlock(); err == nil && uerr != nil {
+		err = fmt.Errorf("failed to release lock on %s: %v", path, uerr)
+	}
+	return err
+}
+
+// noLockFS hides the Locker of the filesystem it wraps, for --no-lock.
+type noLockFS struct {
+	FS
+}
+
+// unlockedIfRequested wraps fsys in noLockFS when --no-lock is set.
+func unlockedIfRequested(cmd *cobra.Command, fsys FS) FS {
+	if noLock, _ := cmd.Flags().GetBool("no-lock"); noLock {
+		return noLockFS{fsys}
+	}
+	return fsys
+}
+
+// errLockTimeout explains a lock that could not be taken in time.
+func errLockTimeout(name string) error {
+	return fmt.Errorf("%s is in use by another enigoma command; gave up after %v (pass --no-lock to skip locking)", name, lockTimeout)
+}
+
+// Lock takes an advisory lock on name by locking name.lock with the
+// operating system (flock, or LockFileEx on Windows). The operating system
+// releases the lock when its process exits, so a crashed command does not
+// block its file, and no process has to decide that a lock is stale.
+func (OSFS) Lock(name string) (func() error, error) {
+	lockPath := name + lockSuffix
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := tryLockPath(lockPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock %s: %w", name, err)
+		}
+		if f != nil {
+			// The process ID is for people wondering who holds the file
+			if f.Truncate(0) == nil {
+				fmt.Fprintf(f, "%d\n", os.Getpid())
+			}
+			return func() error { return releaseLockFile(f, lockPath) }, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, errLockTimeout(name)
+		}
+		time.Sleep(lockPollInterval)
+	}
+}
+
+// tryLockPath opens the lock file at lockPath and locks it without waiting.
+// It returns nil when another process holds the lock, or when the file it
+// locked is no longer the one at lockPath because the previous holder
+// removed it on release; a lock on a removed file guards nothing.
+func tryLockPath(lockPath string) (*os.File, error) {
+	f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+	locked, err := tryLockFile(f)
+	if err != nil || !locked {
+		f.Close()
+		return nil, err
+	}
+	opened, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if current, err := os.Stat(lockPath); err != nil || !os.SameFile(opened, current) {
+		unlockFile(f)
+		f.Close()
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		return nil, nil
+	}
+	return f, nil
+}
+
+// Lock takes an in-process lock on name. Lock files are not created, so
+// locking leaves the file listing unchanged.
+func (m *MemFS) Lock(name string) (func() error, error) {
+	p := memPath(name)
+	m.mu.Lock()
+	if m.locks == nil {
+		m.locks = make(map[string]chan struct{})
+	}
+	sem, ok := m.locks[p]
+	if !ok {
+		sem = make(chan struct{}, 1)
+		m.locks[p] = sem
+	}
+	m.mu.Unlock()
+
+	timer := time.NewTimer(lockTimeout)
+	defer timer.Stop()
+	select {
+	case sem <- struct{}{}:
+		return func() error { <-sem; return nil }, nil
+	case <-timer.C:
+		return nil, errLockTimeout(name)
+	}
+}
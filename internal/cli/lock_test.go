@@ -0,0 +1,251 @@
+// Package cli provides unit tests for advisory file locking.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// withLockTimeout shortens lockTimeout for the duration of a test.
+func withLockTimeout(t *testing.T, d time.Duration) {
+	t.Helper()
+	old := lockTimeout
+	lockTimeout = d
+	t.Cleanup(func() { lockTimeout = old })
+}
+
+func TestOSFSLock(t *testing.T) {
+	withLockTimeout(t, 100*time.Millisecond)
+	name := filepath.Join(t.TempDir(), "key.json")
+
+	unlock, err := OSFS{}.Lock(name)
+	if err != nil {
+		t.Fatalf("first lock failed: %v", err)
+	}
+	if _, err := os.Stat(name + lockSuffix); err != nil {
+		t.Errorf("lock file missing: %v", err)
+	}
+	if _, err := (OSFS{}).Lock(name); err == nil || !strings.Contains(err.Error(), "--no-lock") {
+		t.Errorf("expected a timeout while the lock is held, got %v", err)
+	}
+	if err := unlock(); err != nil {
+		t.Fatalf("unlock failed: %v", err)
+	}
+
+	unlock, err = OSFS{}.Lock(name)
+	if err != nil {
+		t.Fatalf("lock after release failed: %v", err)
+	}
+	unlock()
+
+	if _, err := os.Stat(name + lockSuffix); !os.IsNotExist(err) {
+		t.Errorf("the lock file should be removed on release, got %v", err)
+	}
+
+	// A lock file left behind by a crashed process is not locked, so it is
+	// taken over at once
+	if err := os.WriteFile(name+lockSuffix, []byte("12345\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	unlock, err = OSFS{}.Lock(name)
+	if err != nil {
+		t.Fatalf("leftover lock file was not taken over: %v", err)
+	}
+	unlock()
+}
+
+func TestOSFSLockContention(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "key.json")
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		holders int
+		maxSeen int
+	)
+	// Each release removes the lock file while others wait on it, so this
+	// also covers waiters that lock a file which is no longer at the path
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				err := withFileLock(OSFS{}, name, func() error {
+					mu.Lock()
+					holders++
+					if holders > maxSeen {
+						maxSeen = holders
+					}
+					mu.Unlock()
+					time.Sleep(100 * time.Microsecond)
+					mu.Lock()
+					holders--
+					mu.Unlock()
+					return nil
+				})
+				if err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if maxSeen != 1 {
+		t.Errorf("%d goroutines held the lock at once", maxSeen)
+	}
+}
+
+func TestMemFSLockContention(t *testing.T) {
+	fsys := NewMemFS()
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		holders int
+		maxSeen int
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := withFileLock(fsys, "key.json", func() error {
+				mu.Lock()
+				holders++
+				if holders > maxSeen {
+					maxSeen = holders
+				}
+				mu.Unlock()
+				time.Sleep(time.Millisecond)
+				mu.Lock()
+				holders--
+				mu.Unlock()
+				return nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if maxSeen != 1 {
+		t.Errorf("%d goroutines held the lock at once", maxSeen)
+	}
+}
+
+func TestNoLockFlag(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("no-lock", false, "")
+	withFileSystem(cmd, NewMemFS())
+
+	if _, ok := fileSystem(cmd).(Locker); !ok {
+		t.Error("MemFS should lock by default")
+	}
+	cmd.Flags().Set("no-lock", "true")
+	if _, ok := fileSystem(cmd).(Locker); ok {
+		t.Error("--no-lock should disable locking")
+	}
+}
+
+// slowFS delays reads, widening the window in which unlocked
+// read-modify-write cycles overlap.
+type slowFS struct {
+	*MemFS
+}
+
+func (s slowFS) ReadFile(name string) ([]byte, error) {
+	data, err := s.MemFS.ReadFile(name)
+	time.Sleep(5 * time.Millisecond)
+	return data, err
+}
+
+func TestConcurrentChatSends(t *testing.T) {
+	fsys := slowFS{NewMemFS()}
+	run := func(args ...string) (string, error) {
+		var stdout bytes.Buffer
+		cmd := NewRootCommand(Options{Out: &stdout, Err: &bytes.Buffer{}, FS: fsys})
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return stdout.String(), err
+	}
+	if _, err := run("keygen", "--preset", "classic", "--output", "key.json"); err != nil {
+		t.Fatalf("keygen failed: %v", err)
+	}
+
+	// Without locking, concurrent sends read the same session and reuse offsets
+	const senders = 8
+	frames := make([]string, senders)
+	var wg sync.WaitGroup
+	for i := range frames {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			out, err := run("chat", "--config", "key.json", "--send", "HELLO")
+			if err != nil {
+				t.Errorf("send %d failed: %v", i, err)
+			}
+			frames[i] = strings.TrimSpace(out)
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[int]bool)
+	for _, frame := range frames {
+		offset, _, err := parseChatFrame(frame)
+		if err != nil {
+			t.Fatalf("bad frame %q: %v", frame, err)
+		}
+		if seen[offset] {
+			t.Errorf("offset %d was used twice", offset)
+		}
+		seen[offset] = true
+	}
+
+	data, err := fsys.ReadFile("key.json" + chatSessionExtension)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var session chatSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		t.Fatal(err)
+	}
+	if session.Offset != senders*len("HELLO") {
+		t.Errorf("session offset %d, want %d", session.Offset, senders*len("HELLO"))
+	}
+}
+
+func TestConfigConvertWaitsForLock(t *testing.T) {
+	withLockTimeout(t, 50*time.Millisecond)
+	fsys := NewMemFS()
+	run := func(args ...string) error {
+		cmd := NewRootCommand(Options{Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, FS: fsys})
+		cmd.SetArgs(args)
+		return cmd.Execute()
+	}
+	if err := run("keygen", "--preset", "classic", "--output", "key.json"); err != nil {
+		t.Fatalf("keygen failed: %v", err)
+	}
+
+	unlock, err := fsys.Lock("key.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := run("config", "--convert", "key.json", "--output", "key.json"); err == nil || !strings.Contains(err.Error(), "in use") {
+		t.Errorf("expected convert to wait for the lock and fail, got %v", err)
+	}
+	if err := run("config", "--convert", "key.json", "--output", "key.json", "--no-lock"); err != nil {
+		t.Errorf("--no-lock should bypass the held lock: %v", err)
+	}
+	unlock()
+	if err := run("config", "--convert", "key.json", "--output", "key.json"); err != nil {
+		t.Errorf("convert after release failed: %v", err)
+	}
+}
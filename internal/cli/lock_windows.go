@@ -0,0 +1,66 @@
+//go:build windows
+
+// Package cli provides LockFileEx-based lock files for Windows.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	// lockfileFailImmediately and lockfileExclusiveLock are the LockFileEx
+	// flags for a non-blocking exclusive lock.
+	lockfileFailImmediately = 0x1
+	lockfileExclusiveLock   = 0x2
+	// errorLockViolation is returned when another handle holds the lock.
+	errorLockViolation syscall.Errno = 33
+)
+
+var (
+	procLockFileEx   = kernel32.NewProc("LockFileEx")
+	procUnlockFileEx = kernel32.NewProc("UnlockFileEx")
+)
+
+// tryLockFile locks the first byte of f exclusively without waiting. It
+// reports false when another process holds it.
+func tryLockFile(f *os.File) (bool, error) {
+	var overlapped syscall.Overlapped
+	r, _, err := procLockFileEx.Call(f.Fd(), lockfileExclusiveLock|lockfileFailImmediately, 0, 1, 0,
+		uintptr(unsafe.Pointer(&overlapped)))
+	if r != 0 {
+		return true, nil
+	}
+	if errors.Is(err, errorLockViolation) || errors.Is(err, syscall.ERROR_IO_PENDING) {
+		return false, nil
+	}
+	return false, err
+}
+
+// unlockFile releases the lock on the first byte of f.
+func unlockFile(f *os.File) error {
+	var overlapped syscall.Overlapped
+	r, _, err := procUnlockFileEx.Call(f.Fd(), 0, 1, 0, uintptr(unsafe.Pointer(&overlapped)))
+	if r == 0 {
+		return err
+	}
+	return nil
+}
+
+// releaseLockFile releases the lock held through f and then tries to remove
+// the lock file at path. Windows cannot remove a file another process has
+// open, so the removal only succeeds when no one is waiting for the lock;
+// otherwise the file is left for the next holder to remove.
+func releaseLockFile(f *os.File, path string) error {
+	err := unlockFile(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	os.Remove(path)
+	return err
+}
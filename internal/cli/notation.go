@@ -0,0 +1,334 @@
+// Package cli provides rotor position and plugboard notations.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/coredds/enigoma/pkg/enigma"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// positionNotation selects how rotor positions and plugboard pairs are
+// written on the command line and in human-readable output.
+type positionNotation string
+
+const (
+	// notationIndex is zero-based numbering: 0 is the first alphabet character.
+	// Values outside the alphabet wrap around.
+	notationIndex positionNotation = "index"
+	// notationLetters uses the alphabet characters shown in the rotor windows
+	// and printed on the plugboard, e.g. ADU or A:Z.
+	notationLetters positionNotation = "letters"
+	// notationNumbers is one-based numbering as on numbered Wehrmacht rings:
+	// 1 is the first alphabet character, 26 the last on a Latin machine.
+	notationNumbers positionNotation = "numbers"
+)
+
+// addNotationFlag registers --notation on a command that reads or prints
+// rotor positions or plugboard pairs.
+func addNotationFlag(cmd *cobra.Command, def positionNotation) {
+	cmd.Flags().String("notation", string(def), "Notation for rotor positions and plugboard pairs (index, letters, numbers)")
+}
+
+// addRotorPositionsFlag registers --rotors, the starting rotor positions of
+// encrypt and decrypt, with --positions accepted as an alias.
+func addRotorPositionsFlag(cmd *cobra.Command) {
+	cmd.Flags().StringSliceP("rotors", "r", nil, "Rotor positions (e.g., 1,5,12 or ADU; alias --positions)")
+	cmd.Flags().SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
+		if name == "positions" {
+			name = "rotors"
+		}
+		return pflag.NormalizedName(name)
+	})
+}
+
+// getNotationFromFlag returns the notation chosen with --notation.
+func getNotationFromFlag(cmd *cobra.Command) (positionNotation, error) {
+	name, err := cmd.Flags().GetString("notation")
+	if err != nil || name == "" {
+		return notationIndex, nil
+	}
+	switch n := positionNotation(strings.ToLower(name)); n {
+	case notationIndex, notationLetters, notationNumbers:
+		return n, nil
+	default:
+		return "", fmt.Errorf("unknown notation: %s. Available: index, letters, numbers", name)
+	}
+}
+
+// parsePosition converts one position to a zero-based alphabet index.
+func (n positionNotation) parsePosition(value string, alphabet []rune) (int, error) {
+	if n == notationLetters {
+		r := []rune(value)
+		if len(r) != 1 {
+			return 0, fmt.Errorf("expected a single character, got '%s'", value)
+		}
+		for i, c := range alphabet {
+			if c == r[0] {
+				return i, nil
+			}
+		}
+		return 0, fmt.Errorf("'%s' is not in the alphabet", value)
+	}
+
+	v, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("expected a number, got '%s'", value)
+	}
+	if n == notationNumbers {
+		if v < 1 || v > len(alphabet) {
+			return 0, fmt.Errorf("%d is outside 1-%d", v, len(alphabet))
+		}
+		return v - 1, nil
+	}
+	// Index notation wraps around the alphabet, as it always has
+	return ((v % len(alphabet)) + len(alphabet)) % len(alphabet), nil
+}
+
+// parsePositions converts flag values to zero-based positions. In letters
+// notation a value may hold several positions at once, as in --rotors ADU.
+// Index notation reads a value that is not a number as letters, so ADU works
+// without --notation too.
+func (n positionNotation) parsePositions(values []string, alphabet []rune) ([]int, error) {
+	var result []int
+	for _, value := range values {
+		read := n
+		if _, err := strconv.Atoi(strings.TrimSpace(value)); err != nil && n == notationIndex {
+			read = notationLetters
+		}
+		parts := []string{value}
+		if read == notationLetters && len([]rune(value)) > 1 {
+			parts = strings.Split(value, "")
+		}
+		for _, part := range parts {
+			pos, err := read.parsePosition(part, alphabet)
+			if err != nil {
+				return nil, fmt.Errorf("invalid position '%s': %v", part, err)
+			}
+			result = append(result, pos)
+		}
+	}
+	return result, nil
+}
+
+// parseRingSettings converts --ring-settings values to zero-based ring
+// settings. Rings are numbered from 1 as on the historical machines, so "1,5,20"
+// and "A,E,T" are the same settings; a number is read as a number unless the
+// notation is letters. In letters notation a value may hold several rings, as
+// in --ring-settings AET.
+func (n positionNotation) parseRingSettings(values []string, alphabet []rune) ([]int, error) {
+	var result []int
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		parts := []string{value}
+		if n == notationLetters && len([]rune(value)) > 1 {
+			parts = strings.Split(value, "")
+		}
+		for _, part := range parts {
+			read := notationLetters
+			if _, err := strconv.Atoi(part); err == nil && n != notationLetters {
+				read = notationNumbers
+			}
+			ring, err := read.parsePosition(part, alphabet)
+			if err != nil {
+				return nil, fmt.Errorf("invalid ring setting '%s': %v", part, err)
+			}
+			result = append(result, ring)
+		}
+	}
+	return result, nil
+}
+
+// parsePlugboardPairs converts flag values such as "A:Z" (letters) or "1:26"
+// (numbers) to a plugboard map listing both directions. Index notation reads
+// pairs as characters, the form --plugboard has always documented.
+func (n positionNotation) parsePlugboardPairs(values []string, alphabet []rune) (map[rune]rune, error) {
+	if n == notationIndex {
+		n = notationLetters
+	}
+	pairs := make(map[rune]rune)
+	for _, value := range values {
+		left, right, ok := strings.Cut(value, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid plugboard pair '%s': expected two positions separated by ':'", value)
+		}
+		a, err := n.parsePosition(left, alphabet)
+		if err != nil {
+			return nil, fmt.Errorf("invalid plugboard pair '%s': %v", value, err)
+		}
+		b, err := n.parsePosition(right, alphabet)
+		if err != nil {
+			return nil, fmt.Errorf("invalid plugboard pair '%s': %v", value, err)
+		}
+		x, y := alphabet[a], alphabet[b]
+		if x == y {
+			return nil, fmt.Errorf("invalid plugboard pair '%s': a character cannot be paired with itself", value)
+		}
+		if _, used := pairs[x]; used {
+			return nil, fmt.Errorf("invalid plugboard pair '%s': '%c' is already connected", value, x)
+		}
+		if _, used := pairs[y]; used {
+			return nil, fmt.Errorf("invalid plugboard pair '%s': '%c' is already connected", value, y)
+		}
+		pairs[x], pairs[y] = y, x
+	}
+	return pairs, nil
+}
+
+// formatPositions renders zero-based positions in the notation.
+func (n positionNotation) formatPositions(positions []int, alphabet []rune) string {
+	switch n {
+	case notationLetters:
+		var b strings.Builder
+		for _, p := range positions {
+			if p >= 0 && p < len(alphabet) {
+				b.WriteRune(alphabet[p])
+			} else {
+				b.WriteRune('?')
+			}
+		}
+		return b.String()
+	case notationNumbers:
+		shifted := make([]int, len(positions))
+		for i, p := range positions {
+			shifted[i] = p + 1
+		}
+		return fmt.Sprint(shifted)
+	default:
+		return fmt.Sprint(positions)
+	}
+}
+
+// applyRingSettingsFlag sets the ring settings given in --ring-settings.
+func applyRingSettingsFlag(cmd *cobra.Command, machine *enigma.Enigma) error {
+	ringValues, _ := cmd.Flags().GetStringSlice("ring-settings")
+	if len(ringValues) == 0 {
+		return nil
+	}
+	n, err := getNotationFromFlag(cmd)
+	if err != nil {
+		return err
+	}
+	settings, err := machine.GetSettings()
+	if err != nil {
+		return fmt.Errorf("failed to read machine settings: %v", err)
+	}
+	rings, err := n.parseRingSettings(ringValues, settings.Alphabet)
+	if err != nil {
+		return err
+	}
+	if err := machine.SetRingSettings(rings); err != nil {
+		return fmt.Errorf("failed to set ring settings: %v", err)
+	}
+	return nil
+}
+
+// machinePositions renders a machine's current rotor positions in the
+// notation chosen with --notation.
+func machinePositions(cmd *cobra.Command, machine *enigma.Enigma) string {
+	positions := machine.GetCurrentRotorPositions()
+	n, err := getNotationFromFlag(cmd)
+	if err != nil || n == notationIndex {
+		return fmt.Sprint(positions)
+	}
+	settings, err := machine.GetSettings()
+	if err != nil {
+		return fmt.Sprint(positions)
+	}
+	return n.formatPositions(positions, settings.Alphabet)
+}
+
+// applyPositionFlags sets the ring settings given in --ring-settings, the
+// rotor positions given in positionsFlag and the plugboard pairs given in
+// --plugboard, read in the chosen notation. Explicit plugboard pairs replace
+// the machine's existing pairs.
+func applyPositionFlags(cmd *cobra.Command, machine *enigma.Enigma, positionsFlag string) error {
+	n, err := getNotationFromFlag(cmd)
+	if err != nil {
+		return err
+	}
+	if err := applyRingSettingsFlag(cmd, machine); err != nil {
+		return err
+	}
+	if values, _ := cmd.Flags().GetStringSlice(positionsFlag); len(values) > 0 {
+		settings, err := machine.GetSettings()
+		if err != nil {
+			return fmt.Errorf("failed to read machine settings: %v", err)
+		}
+		positions, err := n.parsePositions(values, settings.Alphabet)
+		if err != nil {
+			return fmt.Errorf("invalid rotor positions: %v", err)
+		}
+		if err := machine.SetRotorPositions(positions); err != nil {
+			return fmt.Errorf("failed to set rotor positions: %v", err)
+		}
+	}
+	return applyPlugboardFlag(cmd, machine)
+}
+
+// applyPlugboardFlag replaces the machine's plugboard pairs with those given
+// in --plugboard, read in the chosen notation and checked against the
+// machine's alphabet.
+func applyPlugboardFlag(cmd *cobra.Command, machine *enigma.Enigma) error {
+	pairValues, _ := cmd.Flags().GetStringSlice("plugboard")
+	if len(pairValues) == 0 {
+		return nil
+	}
+	if machine.IsPlugboardDisabled() {
+		return fmt.Errorf("--plugboard cannot be used on a machine without a plugboard (--no-plugboard)")
+	}
+	if machine.HasUhr() {
+		return fmt.Errorf("--plugboard cannot rewire a plugboard that goes through the Uhr")
+	}
+	n, err := getNotationFromFlag(cmd)
+	if err != nil {
+		return err
+	}
+	settings, err := machine.GetSettings()
+	if err != nil {
+		return fmt.Errorf("failed to read machine settings: %v", err)
+	}
+	pairs, err := n.parsePlugboardPairs(pairValues, settings.Alphabet)
+	if err != nil {
+		return err
+	}
+	if err := enigma.WithPlugboardConfiguration(pairs)(machine); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validatePlugboardFlag rejects --plugboard when the machine comes from an
+// existing key: a configuration file, a passphrase or a shared secret.
+func validatePlugboardFlag(cmd *cobra.Command) error {
+	for _, flag := range []string{"config", "config-list", "shared-secret-env"} {
+		if cmd.Flags().Lookup(flag) == nil {
+			continue
+		}
+		if value, _ := cmd.Flags().GetString(flag); value != "" {
+			return rejectPlugboardFlagFor(cmd, "--"+flag)
+		}
+		if values, _ := cmd.Flags().GetStringSlice(flag); len(values) > 0 {
+			return rejectPlugboardFlagFor(cmd, "--"+flag)
+		}
+	}
+	if hasPassphraseFlag(cmd) {
+		return rejectPlugboardFlagFor(cmd, "--passphrase")
+	}
+	return nil
+}
+
+// rejectPlugboardFlagFor rejects --plugboard for a machine that comes from an
+// existing key (source names it, e.g. "--config"): the key decides the wiring.
+func rejectPlugboardFlagFor(cmd *cobra.Command, source string) error {
+	if pairValues, _ := cmd.Flags().GetStringSlice("plugboard"); len(pairValues) > 0 {
+		return fmt.Errorf("--plugboard cannot be combined with %s, whose key sets the plugboard; use it with --preset or generated settings", source)
+	}
+	return nil
+}
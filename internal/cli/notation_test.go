@@ -0,0 +1,265 @@
+// Package cli provides unit tests for rotor position and plugboard notations.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bytes"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/coredds/enigoma"
+	"github.com/coredds/enigoma/pkg/enigma"
+)
+
+func TestParsePositions(t *testing.T) {
+	latin := enigoma.AlphabetLatinUpper
+	tests := []struct {
+		notation positionNotation
+		values   []string
+		want     []int
+	}{
+		{notationIndex, []string{"0", "3", "20"}, []int{0, 3, 20}},
+		{notationIndex, []string{"27", "-1"}, []int{1, 25}},
+		{notationIndex, []string{"ADU"}, []int{0, 3, 20}},
+		{notationLetters, []string{"ADU"}, []int{0, 3, 20}},
+		{notationLetters, []string{"A", "D", "U"}, []int{0, 3, 20}},
+		{notationNumbers, []string{"1", "4", "21"}, []int{0, 3, 20}},
+		{notationNumbers, []string{"26"}, []int{25}},
+	}
+	for _, tt := range tests {
+		got, err := tt.notation.parsePositions(tt.values, latin)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s %v: got %v, %v; want %v", tt.notation, tt.values, got, err, tt.want)
+		}
+	}
+
+	for _, bad := range []struct {
+		notation positionNotation
+		value    string
+	}{
+		{notationNumbers, "0"},
+		{notationNumbers, "27"},
+		{notationNumbers, "A"},
+		{notationLetters, "a"},
+		{notationIndex, "x"},
+	} {
+		if _, err := bad.notation.parsePositions([]string{bad.value}, latin); err == nil {
+			t.Errorf("%s %q should be rejected", bad.notation, bad.value)
+		}
+	}
+}
+
+func TestParsePlugboardPairs(t *testing.T) {
+	latin := enigoma.AlphabetLatinUpper
+	want := map[rune]rune{'A': 'Z', 'Z': 'A', 'B': 'Y', 'Y': 'B'}
+
+	for notation, values := range map[positionNotation][]string{
+		notationIndex:   {"A:Z", "B:Y"},
+		notationLetters: {"A:Z", "B:Y"},
+		notationNumbers: {"1:26", "2:25"},
+	} {
+		got, err := notation.parsePlugboardPairs(values, latin)
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("%s %v: got %v, %v", notation, values, got, err)
+		}
+	}
+
+	for _, bad := range [][]string{{"AZ"}, {"A:A"}, {"A:Z", "Z:B"}, {"A:?"}} {
+		if _, err := notationLetters.parsePlugboardPairs(bad, latin); err == nil {
+			t.Errorf("%v should be rejected", bad)
+		}
+	}
+}
+
+func TestFormatPositions(t *testing.T) {
+	latin := enigoma.AlphabetLatinUpper
+	positions := []int{0, 3, 20}
+	for notation, want := range map[positionNotation]string{
+		notationIndex:   "[0 3 20]",
+		notationLetters: "ADU",
+		notationNumbers: "[1 4 21]",
+	} {
+		if got := notation.formatPositions(positions, latin); got != want {
+			t.Errorf("%s: got %q, want %q", notation, got, want)
+		}
+	}
+}
+
+func TestKeygenNotation(t *testing.T) {
+	fsys := NewMemFS()
+	keygen := func(args ...string) (*enigma.Enigma, string) {
+		t.Helper()
+		var stdout bytes.Buffer
+		cmd := NewRootCommand(Options{Out: &stdout, Err: &bytes.Buffer{}, FS: fsys})
+		cmd.SetArgs(append([]string{"keygen", "--preset", "classic", "--output", "key.json"}, args...))
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("keygen %v failed: %v", args, err)
+		}
+		machine, err := createMachineFromConfig(fsys, configOptions{}, "key.json")
+		if err != nil {
+			t.Fatalf("failed to load key: %v", err)
+		}
+		return machine, stdout.String()
+	}
+
+	for _, args := range [][]string{
+		{"--positions", "0,3,20"},
+		{"--positions", "ADU", "--notation", "letters"},
+		{"--positions", "1,4,21", "--notation", "numbers"},
+	} {
+		machine, _ := keygen(args...)
+		if got := machine.GetCurrentRotorPositions(); !reflect.DeepEqual(got, []int{0, 3, 20}) {
+			t.Errorf("%v: positions %v, want [0 3 20]", args, got)
+		}
+	}
+
+	machine, out := keygen("--positions", "1,4,21", "--plugboard", "1:26", "--notation", "numbers", "--describe")
+	settings, err := machine.GetSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[rune]rune{'A': 'Z', 'Z': 'A'}; !reflect.DeepEqual(settings.PlugboardPairs, want) {
+		t.Errorf("plugboard %v, want %v", settings.PlugboardPairs, want)
+	}
+	if !strings.Contains(out, "Current Rotor Positions: [1 4 21]") {
+		t.Errorf("describe should use numbers notation:\n%s", out)
+	}
+
+	cmd := NewRootCommand(Options{Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, FS: fsys})
+	cmd.SetArgs([]string{"keygen", "--preset", "classic", "--notation", "roman"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "unknown notation") {
+		t.Errorf("expected an unknown notation error, got %v", err)
+	}
+}
+
+func TestEncryptSummaryNotation(t *testing.T) {
+	var stderr bytes.Buffer
+	cmd := NewRootCommand(Options{Out: &bytes.Buffer{}, Err: &stderr, FS: NewMemFS()})
+	cmd.SetArgs([]string{"encrypt", "--text", "HELLO", "--alphabet", "latin", "--security", "low",
+		"--rotors", "AAA", "--plugboard", "A:B", "--notation", "letters", "--summary"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	// Notches are random, so only the form of the positions is known
+	if !regexp.MustCompile(`final positions: [A-Z]{3}\n`).MatchString(stderr.String()) {
+		t.Errorf("summary should print letters:\n%s", stderr.String())
+	}
+}
+
+func TestPositionsAlias(t *testing.T) {
+	fsys := NewMemFS()
+	if _, err := runCLI(fsys, "keygen", "--preset", "m3", "--positions", "AAA", "--output", "m3.json"); err != nil {
+		t.Fatalf("keygen failed: %v", err)
+	}
+	ciphertext, err := runCLI(fsys, "encrypt", "--preset", "m3", "--positions", "AAA", "--text", "AAAAA")
+	if err != nil {
+		t.Fatalf("encrypt --positions failed: %v", err)
+	}
+	if strings.TrimSpace(ciphertext) != "BDZGO" {
+		t.Errorf("encrypt --positions AAA = %q, want BDZGO", ciphertext)
+	}
+	plaintext, err := runCLI(fsys, "decrypt", "--preset", "m3", "--positions", "A,A,A", "--text", "BDZGO")
+	if err != nil || strings.TrimSpace(plaintext) != "AAAAA" {
+		t.Errorf("decrypt --positions A,A,A = %q, %v; want AAAAA", plaintext, err)
+	}
+
+	out, err := runCLI(fsys, "config", "--show", "m3.json")
+	if err != nil {
+		t.Fatalf("config --show failed: %v", err)
+	}
+	if !strings.Contains(out, "Current Rotor Positions: [0 0 0] (AAA)") {
+		t.Errorf("config --show should print the positions as letters:\n%s", out)
+	}
+}
+
+func TestPositionsOnPresets(t *testing.T) {
+	fsys := NewMemFS()
+	ciphertext, err := runCLI(fsys, "encrypt", "--preset", "m3", "--positions", "ADU", "--text", "AAAAA",
+		"--save-config", "adu.json")
+	if err != nil {
+		t.Fatalf("encrypt --positions failed: %v", err)
+	}
+	ciphertext = strings.TrimSpace(ciphertext)
+	if ciphertext == "BDZGO" {
+		t.Error("--positions ADU should not encrypt like AAA")
+	}
+	plaintext, err := runCLI(fsys, "decrypt", "--preset", "m3", "--positions", "ADU", "--text", ciphertext)
+	if err != nil || strings.TrimSpace(plaintext) != "AAAAA" {
+		t.Errorf("decrypt --positions ADU = %q, %v; want AAAAA", plaintext, err)
+	}
+
+	// The saved configuration starts where the message did
+	saved, err := createMachineFromConfig(fsys, configOptions{}, "adu.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := saved.GetCurrentRotorPositions(); !reflect.DeepEqual(got, []int{0, 3, 20}) {
+		t.Errorf("saved positions %v, want [0 3 20]", got)
+	}
+	plaintext, err = runCLI(fsys, "decrypt", "--config", "adu.json", "--text", ciphertext)
+	if err != nil || strings.TrimSpace(plaintext) != "AAAAA" {
+		t.Errorf("decrypt --config = %q, %v; want AAAAA", plaintext, err)
+	}
+}
+
+func TestPlugboardFlagOnPresets(t *testing.T) {
+	fsys := NewMemFS()
+	ciphertext, err := runCLI(fsys, "encrypt", "--preset", "m3", "--positions", "AAA", "--plugboard", "A:Z,B:Y",
+		"--text", "AAAAA", "--save-config", "wired.json")
+	if err != nil {
+		t.Fatalf("encrypt --plugboard failed: %v", err)
+	}
+	ciphertext = strings.TrimSpace(ciphertext)
+	if ciphertext == "BDZGO" {
+		t.Error("--plugboard should change the ciphertext of the preset")
+	}
+	plaintext, err := runCLI(fsys, "decrypt", "--preset", "m3", "--positions", "AAA", "--plugboard", "A:Z,B:Y", "--text", ciphertext)
+	if err != nil || strings.TrimSpace(plaintext) != "AAAAA" {
+		t.Errorf("decrypt --plugboard = %q, %v; want AAAAA", plaintext, err)
+	}
+
+	saved, err := createMachineFromConfig(fsys, configOptions{}, "wired.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings, _ := saved.GetSettings()
+	if settings.PlugboardPairs['A'] != 'Z' || settings.PlugboardPairs['Y'] != 'B' || len(settings.PlugboardPairs) != 4 {
+		t.Errorf("--save-config should keep the pairs, got %v", settings.PlugboardPairs)
+	}
+
+	// Generated machines save their configuration too
+	if _, err := runCLI(fsys, "encrypt", "--alphabet", "latin", "--security", "low", "--plugboard", "C:D",
+		"--text", "HELLO", "--save-config", "manual.json"); err != nil {
+		t.Fatalf("encrypt with manual settings failed: %v", err)
+	}
+	manual, err := createMachineFromConfig(fsys, configOptions{}, "manual.json")
+	if err != nil {
+		t.Fatalf("--save-config with manual settings should write the key: %v", err)
+	}
+	if settings, _ := manual.GetSettings(); settings.PlugboardPairs['C'] != 'D' {
+		t.Errorf("the saved manual key should have C:D, got %v", settings.PlugboardPairs)
+	}
+}
+
+func TestPlugboardFlagErrors(t *testing.T) {
+	fsys := NewMemFS()
+	if _, err := runCLI(fsys, "keygen", "--preset", "m3", "--output", "m3.json"); err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string][]string{
+		"not in alphabet": {"encrypt", "--preset", "m3", "--plugboard", "A:é", "--text", "HELLO"},
+		"repeated letter": {"encrypt", "--preset", "m3", "--plugboard", "A:B,B:C", "--text", "HELLO"},
+		"with a config":   {"encrypt", "--config", "m3.json", "--plugboard", "A:B", "--text", "HELLO"},
+		"decrypt config":  {"decrypt", "--config", "m3.json", "--plugboard", "A:B", "--text", "HELLO"},
+		"no plugboard":    {"encrypt", "--alphabet", "latin", "--no-plugboard", "--plugboard", "A:B", "--text", "HELLO"},
+	}
+	for name, args := range tests {
+		if _, err := runCLI(fsys, args...); err == nil {
+			t.Errorf("%s: %v should fail", name, args)
+		}
+	}
+}
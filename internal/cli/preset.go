@@ -8,13 +8,16 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/coredds/enigoma/pkg/enigma"
 	"github.com/spf13/cobra"
 )
 
-var presetCmd = &cobra.Command{
-	Use:   "preset",
-	Short: "List and describe available Enigma machine presets",
-	Long: `List and describe available Enigma machine presets.
+// newPresetCommand creates the preset command.
+func newPresetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "preset",
+		Short: "List and describe available Enigma machine presets",
+		Long: `List and describe available Enigma machine presets.
 
 Presets provide quick configuration templates for common use cases,
 from historical accuracy to high security applications.
@@ -23,16 +26,23 @@ Examples:
   enigoma preset --list
   enigoma preset --describe classic
   enigoma preset --describe all
-  enigoma preset --export classic --output classic-config.json`,
-	RunE: runPreset,
-}
+  enigoma preset --export classic --output classic-config.json
+
+Custom presets are configuration files dropped into the preset directory,
+~/.config/enigoma/presets (or --preset-dir, or $ENIGOMA_PRESET_DIR):
+myteam.json there is listed with the built-in presets and used as
+--preset myteam. The configuration's description is shown in the list.
+Built-in presets win over custom presets of the same name.`,
+		RunE: runPreset,
+	}
 
-func init() {
-	presetCmd.Flags().BoolP("list", "l", false, "List all available presets")
-	presetCmd.Flags().StringP("describe", "d", "", "Describe a specific preset (or 'all' for all presets)")
-	presetCmd.Flags().StringP("export", "e", "", "Export preset configuration to file")
-	presetCmd.Flags().StringP("output", "o", "", "Output file for exported configuration")
-	presetCmd.Flags().BoolP("verbose", "v", false, "Show detailed information")
+	cmd.Flags().BoolP("list", "l", false, "List all available presets")
+	cmd.Flags().StringP("describe", "d", "", "Describe a specific preset (or 'all' for all presets)")
+	cmd.Flags().StringP("export", "e", "", "Export preset configuration to file")
+	cmd.Flags().StringP("output", "o", "", "Output file for exported configuration")
+	cmd.Flags().BoolP("verbose", "v", false, "Show detailed information")
+
+	return cmd
 }
 
 func runPreset(cmd *cobra.Command, args []string) error {
@@ -70,10 +80,21 @@ func listPresets(cmd *cobra.Command) error {
 	for _, preset := range presets {
 		fmt.Fprintf(cmd.OutOrStdout(), "  %-12s - %s\n", preset.Name, preset.Description)
 	}
+	if registered := registeredPresets(); len(registered) > 0 {
+		fmt.Fprintln(cmd.OutOrStdout())
+		fmt.Fprintln(cmd.OutOrStdout(), "Registered Presets:")
+		fmt.Fprintln(cmd.OutOrStdout())
+		for _, name := range registered {
+			fmt.Fprintf(cmd.OutOrStdout(), "  %-12s - Added by this program\n", name)
+		}
+	}
+	if err := listCustomPresets(cmd); err != nil {
+		return err
+	}
 
-	fmt.Println()
-	fmt.Println("Use 'enigoma preset --describe <name>' for detailed information.")
-	fmt.Println("Use 'enigoma preset --export <name>' to generate configuration files.")
+	fmt.Fprintln(cmd.OutOrStdout())
+	fmt.Fprintln(cmd.OutOrStdout(), "Use 'enigoma preset --describe <name>' for detailed information.")
+	fmt.Fprintln(cmd.OutOrStdout(), "Use 'enigoma preset --export <name>' to generate configuration files.")
 
 	return nil
 }
@@ -94,7 +115,23 @@ func describePresets(presetName string, cmd *cobra.Command) error {
 
 	preset := findPreset(presetName)
 	if preset == nil {
-		return fmt.Errorf("unknown preset: %s. Use --list to see available presets", presetName)
+		if _, ok := enigma.LookupPreset(presetName); ok {
+			machine, err := createMachineFromPreset(presetName)
+			if err != nil {
+				return err
+			}
+			describeMachinePreset(cmd, strings.ToLower(presetName)+" (registered)", machine)
+			fmt.Fprintln(cmd.OutOrStdout())
+			return nil
+		}
+		custom, err := findCustomPreset(cmd, presetName)
+		if err != nil {
+			return err
+		}
+		if custom == nil {
+			return fmt.Errorf("unknown preset: %s. Use --list to see available presets", presetName)
+		}
+		return describeCustomPreset(cmd, custom)
 	}
 
 	describePreset(*preset, verbose, cmd)
@@ -130,7 +167,7 @@ func describePreset(preset PresetInfo, verbose bool, cmd *cobra.Command) {
 
 func exportPreset(presetName string, cmd *cobra.Command) error {
 	// Create machine with preset
-	machine, err := createMachineFromPreset(presetName)
+	machine, err := presetMachine(cmd, presetName)
 	if err != nil {
 		return fmt.Errorf("failed to create machine from preset: %v", err)
 	}
@@ -146,7 +183,7 @@ func exportPreset(presetName string, cmd *cobra.Command) error {
 	if outputFile == "" {
 		fmt.Fprint(cmd.OutOrStdout(), jsonData)
 	} else {
-		err := writeStringToFile(jsonData, outputFile)
+		err := writeConfigFile(fileSystem(cmd), configOptionsFor(cmd), outputFile, jsonData)
 		if err != nil {
 			return fmt.Errorf("failed to write configuration to file: %v", err)
 		}
@@ -156,8 +193,13 @@ func exportPreset(presetName string, cmd *cobra.Command) error {
 	return nil
 }
 
+// PresetInfo describes a built-in preset. The machines come from the
+// library's preset registry (enigma.NewFromPreset); this table adds what the
+// CLI shows about them. preset --list and --export and the wizard all read
+// it, so a preset added here and to the registry is available everywhere.
 type PresetInfo struct {
 	Name               string
+	Aliases            []string // Other names accepted by --preset
 	Description        string
 	UseCase            string
 	SecurityLevel      string
@@ -215,6 +257,91 @@ func getAvailablePresets() []PresetInfo {
 			ComplexityRating:   "2",
 			Notes:              "Used by German Navy with 4 rotors including a thin Beta rotor",
 		},
+		{
+			Name:               "g",
+			Description:        "Historically accurate Abwehr Enigma G-312",
+			UseCase:            "Historical simulation, intelligence service research",
+			SecurityLevel:      "Low",
+			AlphabetName:       "Latin Uppercase",
+			AlphabetSize:       26,
+			RotorCount:         3,
+			PlugboardPairs:     0,
+			HistoricalAccuracy: true,
+			RecommendedFor:     "Historical accuracy, Abwehr simulation",
+			ComplexityRating:   "2",
+			Notes:              "Gear-driven rotors with many notches, a rotating reflector and a QWERTZ entry wheel",
+		},
+		{
+			Name:               "k",
+			Description:        "Historically accurate commercial Enigma K",
+			UseCase:            "Historical simulation, commercial machine research",
+			SecurityLevel:      "Low",
+			AlphabetName:       "Latin Uppercase",
+			AlphabetSize:       26,
+			RotorCount:         3,
+			PlugboardPairs:     0,
+			HistoricalAccuracy: true,
+			RecommendedFor:     "Historical accuracy, pre-war commercial Enigma",
+			ComplexityRating:   "2",
+			Notes:              "No plugboard, a settable reflector and a QWERTZ entry wheel",
+		},
+		{
+			Name:               "swiss-k",
+			Description:        "Historically accurate Swiss Army Enigma K",
+			UseCase:            "Historical simulation, Swiss Army research",
+			SecurityLevel:      "Low",
+			AlphabetName:       "Latin Uppercase",
+			AlphabetSize:       26,
+			RotorCount:         3,
+			PlugboardPairs:     0,
+			HistoricalAccuracy: true,
+			RecommendedFor:     "Historical accuracy, Swiss Army simulation",
+			ComplexityRating:   "2",
+			Notes:              "Enigma K with the rotors the Swiss rewired in 1939",
+		},
+		{
+			Name:               "railway",
+			Aliases:            []string{"rocket"},
+			Description:        "Historically accurate German Railway (Rocket) Enigma",
+			UseCase:            "Historical simulation, Reichsbahn research",
+			SecurityLevel:      "Low",
+			AlphabetName:       "Latin Uppercase",
+			AlphabetSize:       26,
+			RotorCount:         3,
+			PlugboardPairs:     0,
+			HistoricalAccuracy: true,
+			RecommendedFor:     "Historical accuracy, Reichsbahn simulation",
+			ComplexityRating:   "2",
+			Notes:              "Enigma K with the Reichsbahn's own rotor and reflector wirings",
+		},
+		{
+			Name:               "norway",
+			Description:        "Historically accurate Norway Enigma",
+			UseCase:            "Historical simulation, post-war Norwegian police research",
+			SecurityLevel:      "Low",
+			AlphabetName:       "Latin Uppercase",
+			AlphabetSize:       26,
+			RotorCount:         3,
+			PlugboardPairs:     0,
+			HistoricalAccuracy: true,
+			RecommendedFor:     "Historical accuracy, Norenigma simulation",
+			ComplexityRating:   "2",
+			Notes:              "Enigma I with rotors and reflector rewired for the Norwegian police",
+		},
+		{
+			Name:               "tirpitz",
+			Description:        "Historically accurate Enigma T (Tirpitz)",
+			UseCase:            "Historical simulation, Japanese navy research",
+			SecurityLevel:      "Low",
+			AlphabetName:       "Latin Uppercase",
+			AlphabetSize:       26,
+			RotorCount:         3,
+			PlugboardPairs:     0,
+			HistoricalAccuracy: true,
+			RecommendedFor:     "Historical accuracy, German-Japanese naval simulation",
+			ComplexityRating:   "3",
+			Notes:              "Enigma K for Japan: five-notch rotors, a scrambled entry wheel and a settable reflector",
+		},
 		{
 			Name:               "simple",
 			Description:        "Basic Enigma with standard settings",
@@ -229,6 +356,34 @@ func getAvailablePresets() []PresetInfo {
 			ComplexityRating:   "3",
 			Notes:              "Good balance of security and performance",
 		},
+		{
+			Name:               "low",
+			Description:        "Random low-security configuration",
+			UseCase:            "Demonstrations, quick experiments",
+			SecurityLevel:      "Low",
+			AlphabetName:       "Latin Uppercase",
+			AlphabetSize:       26,
+			RotorCount:         3,
+			PlugboardPairs:     2,
+			HistoricalAccuracy: false,
+			RecommendedFor:     "Demonstrations, puzzles",
+			ComplexityRating:   "2",
+			Notes:              "Same as keygen --security low",
+		},
+		{
+			Name:               "medium",
+			Description:        "Random medium-security configuration",
+			UseCase:            "General purpose, moderate security",
+			SecurityLevel:      "Medium",
+			AlphabetName:       "Latin Uppercase",
+			AlphabetSize:       26,
+			RotorCount:         5,
+			PlugboardPairs:     8,
+			HistoricalAccuracy: false,
+			RecommendedFor:     "General encryption, file protection",
+			ComplexityRating:   "3",
+			Notes:              "Same as keygen --security medium",
+		},
 		{
 			Name:               "high",
 			Description:        "High-security configuration",
@@ -266,10 +421,51 @@ func findPreset(name string) *PresetInfo {
 		if strings.EqualFold(preset.Name, name) {
 			return &preset
 		}
+		for _, alias := range preset.Aliases {
+			if strings.EqualFold(alias, name) {
+				return &preset
+			}
+		}
 	}
 	return nil
 }
 
+// presetNames returns the names of the built-in presets in listing order,
+// followed by those registered by the program (see registeredPresets).
+func presetNames() []string {
+	presets := getAvailablePresets()
+	names := make([]string, len(presets))
+	for i, preset := range presets {
+		names[i] = preset.Name
+	}
+	return append(names, registeredPresets()...)
+}
+
+// registeredPresets returns the presets a program embedding the CLI added to
+// the library's registry with enigma.RegisterPreset, which the preset table
+// does not describe.
+func registeredPresets() []string {
+	var names []string
+	for _, name := range enigma.Presets() {
+		if findPreset(name) == nil && !isPresetAlias(name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// isPresetAlias reports whether name is an alias in the preset table.
+func isPresetAlias(name string) bool {
+	for _, preset := range getAvailablePresets() {
+		for _, alias := range preset.Aliases {
+			if strings.EqualFold(alias, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func boolToYesNo(b bool) string {
 	if b {
 		return "Yes"
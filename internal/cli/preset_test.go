@@ -5,6 +5,8 @@
 package cli
 
 import (
+	"bytes"
+	"fmt"
 	"strings"
 	"testing"
 
@@ -232,3 +234,119 @@ func TestPresetExport(t *testing.T) {
 		})
 	}
 }
+
+// TestPresetTableIsComplete checks that every listed preset can be built and
+// that aliases resolve to their preset.
+func TestPresetTableIsComplete(t *testing.T) {
+	for _, preset := range getAvailablePresets() {
+		if _, ok := enigma.LookupPreset(preset.Name); !ok {
+			t.Errorf("preset %s is not in the library's preset registry", preset.Name)
+			continue
+		}
+		machine, err := createMachineFromPreset(strings.ToUpper(preset.Name))
+		if err != nil {
+			t.Errorf("preset %s: %v", preset.Name, err)
+			continue
+		}
+		if got := machine.GetRotorCount(); got != preset.RotorCount {
+			t.Errorf("preset %s has %d rotors, its description says %d", preset.Name, got, preset.RotorCount)
+		}
+	}
+
+	if p := findPreset("rocket"); p == nil || p.Name != "railway" {
+		t.Errorf("findPreset(rocket) = %v, want railway", p)
+	}
+	_, err := createMachineFromPreset("enigma-z")
+	if err == nil || !strings.Contains(err.Error(), strings.Join(presetNames(), ", ")) {
+		t.Errorf("unknown preset error should list the presets, got %v", err)
+	}
+
+	// Every --preset flag lists the whole table in its help
+	names := strings.Join(presetNames(), ", ")
+	root := NewRootCommand(Options{Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, FS: NewMemFS()})
+	for _, sub := range root.Commands() {
+		if flag := sub.Flags().Lookup("preset"); flag != nil && !strings.Contains(flag.Usage, names) {
+			t.Errorf("%s --preset help should list %s, got %q", sub.Name(), names, flag.Usage)
+		}
+	}
+}
+
+// TestWizardPresets runs the encryption wizard with the historical presets.
+func TestWizardPresets(t *testing.T) {
+	presets := getAvailablePresets()
+	for i, preset := range presets {
+		if preset.Name != "m3" && preset.Name != "m4" && preset.Name != "tirpitz" {
+			continue
+		}
+		fsys := NewMemFS()
+		input := fmt.Sprintf("1\n1\nHELLO\n2\nkey\n%d\n", i+1)
+		cmd := NewRootCommand(Options{In: strings.NewReader(input), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, FS: fsys})
+		cmd.SetArgs([]string{"wizard"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("wizard with preset %s: %v", preset.Name, err)
+		}
+
+		saved, err := createMachineFromConfig(fsys, configOptions{}, "key.json")
+		if err != nil {
+			t.Fatalf("preset %s: %v", preset.Name, err)
+		}
+		want, err := createMachineFromPreset(preset.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := saved.Encrypt("WIZARD")
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, err := want.Encrypt("WIZARD")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != expected {
+			t.Errorf("wizard saved a different machine than preset %s: %q, want %q", preset.Name, got, expected)
+		}
+	}
+}
+
+// TestRegisteredPresets checks that presets a program registers with the
+// library are usable and listed by the CLI.
+func TestRegisteredPresets(t *testing.T) {
+	if _, ok := enigma.LookupPreset("cli-test-drill"); !ok {
+		err := enigma.RegisterPreset("cli-test-drill", func() (*enigma.Enigma, error) {
+			machine, err := enigma.NewEnigmaM3()
+			if err != nil {
+				return nil, err
+			}
+			return machine, machine.SetRotorOrder([]int{2, 1, 0})
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	fsys := NewMemFS()
+
+	want, err := enigma.NewFromPreset("cli-test-drill")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := want.Encrypt("AAAAA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := runCLI(fsys, "encrypt", "--preset", "cli-test-drill", "--text", "AAAAA")
+	if err != nil || strings.TrimSpace(out) != expected {
+		t.Errorf("encrypt --preset cli-test-drill = %q (%v), want %s", out, err, expected)
+	}
+
+	out, err = runCLI(fsys, "preset", "--list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "Registered Presets") || !strings.Contains(out, "cli-test-drill") {
+		t.Errorf("preset --list does not show the registered preset:\n%s", out)
+	}
+	out, err = runCLI(fsys, "preset", "--describe", "cli-test-drill")
+	if err != nil || !strings.Contains(out, "cli-test-drill (registered)") {
+		t.Errorf("preset --describe cli-test-drill = %q (%v)", out, err)
+	}
+}
@@ -0,0 +1,172 @@
+// Package cli provides custom presets loaded from the preset directory.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/coredds/enigoma/pkg/enigma"
+	"github.com/spf13/cobra"
+)
+
+// presetDirEnv overrides the preset directory when --preset-dir is not given.
+const presetDirEnv = "ENIGOMA_PRESET_DIR"
+
+// customPreset is a configuration file in the preset directory, usable as
+// --preset <name>.
+type customPreset struct {
+	name string
+	path string
+}
+
+// presetDir returns the directory custom presets are read from: --preset-dir,
+// $ENIGOMA_PRESET_DIR, or enigoma/presets in the user's configuration
+// directory (~/.config/enigoma/presets on Linux).
+func presetDir(cmd *cobra.Command) (string, error) {
+	if dir, _ := cmd.Flags().GetString("preset-dir"); dir != "" {
+		return dir, nil
+	}
+	if dir := os.Getenv(presetDirEnv); dir != "" {
+		return dir, nil
+	}
+	config, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot locate the preset directory (pass --preset-dir or set %s): %v", presetDirEnv, err)
+	}
+	return filepath.Join(config, "enigoma", "presets"), nil
+}
+
+// customPresets lists the *.json configurations in the preset directory,
+// sorted by name. A missing directory holds no presets, and so does one that
+// cannot be located unless --preset-dir names it.
+func customPresets(cmd *cobra.Command) ([]customPreset, error) {
+	dir, err := presetDir(cmd)
+	if err != nil {
+		return nil, nil
+	}
+	fsys := fileSystem(cmd)
+	if _, err := fsys.Stat(dir); os.IsNotExist(err) {
+		return nil, nil
+	}
+	files, err := fsys.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list presets in %s: %v", dir, err)
+	}
+	presets := make([]customPreset, len(files))
+	for i, file := range files {
+		presets[i] = customPreset{name: strings.TrimSuffix(filepath.Base(file), ".json"), path: file}
+	}
+	return presets, nil
+}
+
+// findCustomPreset returns the custom preset called name, or nil.
+func findCustomPreset(cmd *cobra.Command, name string) (*customPreset, error) {
+	presets, err := customPresets(cmd)
+	if err != nil {
+		return nil, err
+	}
+	for _, preset := range presets {
+		if strings.EqualFold(preset.name, name) {
+			return &preset, nil
+		}
+	}
+	return nil, nil
+}
+
+// isBuiltinPreset reports whether name is a built-in preset or one a program
+// registered with enigma.RegisterPreset; custom presets cannot replace them.
+func isBuiltinPreset(name string) bool {
+	_, ok := enigma.LookupPreset(name)
+	return ok || findPreset(name) != nil
+}
+
+// presetMachine creates the machine of a --preset value: a built-in or
+// registered preset or, failing that, a custom preset from the preset
+// directory.
+func presetMachine(cmd *cobra.Command, name string) (*enigma.Enigma, error) {
+	if isBuiltinPreset(name) {
+		return createMachineFromPreset(name)
+	}
+	custom, err := findCustomPreset(cmd, name)
+	if err != nil {
+		return nil, err
+	}
+	if custom == nil {
+		available := strings.Join(presetNames(), ", ")
+		if presets, _ := customPresets(cmd); len(presets) > 0 {
+			names := make([]string, len(presets))
+			for i, preset := range presets {
+				names[i] = preset.name
+			}
+			available += "; custom: " + strings.Join(names, ", ")
+		}
+		return nil, fmt.Errorf("unknown preset: %s. Available: %s", name, available)
+	}
+	machine, err := createMachineFromConfig(fileSystem(cmd), configOptionsFor(cmd), custom.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load preset %s from %s: %v", custom.name, custom.path, err)
+	}
+	return machine, nil
+}
+
+// listCustomPresets adds the custom presets to preset --list.
+func listCustomPresets(cmd *cobra.Command) error {
+	presets, err := customPresets(cmd)
+	if err != nil {
+		return err
+	}
+	if len(presets) == 0 {
+		return nil
+	}
+	dir, _ := presetDir(cmd)
+	fmt.Fprintln(cmd.OutOrStdout())
+	fmt.Fprintf(cmd.OutOrStdout(), "Custom Presets (%s):\n", dir)
+	fmt.Fprintln(cmd.OutOrStdout())
+	for _, preset := range presets {
+		if isBuiltinPreset(preset.name) {
+			fmt.Fprintf(cmd.OutOrStdout(), "  %-12s - hidden by the built-in preset of the same name\n", preset.name)
+			continue
+		}
+		machine, err := createMachineFromConfig(fileSystem(cmd), configOptionsFor(cmd), preset.path)
+		if err != nil {
+			fmt.Fprintf(cmd.OutOrStdout(), "  %-12s - unreadable: %v\n", preset.name, err)
+			continue
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "  %-12s - %s\n", preset.name, presetDescription(machine))
+	}
+	return nil
+}
+
+// describeCustomPreset describes a custom preset for preset --describe.
+func describeCustomPreset(cmd *cobra.Command, preset *customPreset) error {
+	machine, err := createMachineFromConfig(fileSystem(cmd), configOptionsFor(cmd), preset.path)
+	if err != nil {
+		return fmt.Errorf("failed to load preset %s from %s: %v", preset.name, preset.path, err)
+	}
+	describeMachinePreset(cmd, preset.name+" (custom)", machine)
+	fmt.Fprintf(cmd.OutOrStdout(), "File: %s\n\n", preset.path)
+	return nil
+}
+
+// describeMachinePreset describes a preset the preset table knows nothing
+// about from the machine it builds.
+func describeMachinePreset(cmd *cobra.Command, title string, machine *enigma.Enigma) {
+	fmt.Fprintf(cmd.OutOrStdout(), "Preset: %s\n", title)
+	fmt.Fprintf(cmd.OutOrStdout(), "Description: %s\n", presetDescription(machine))
+	fmt.Fprintf(cmd.OutOrStdout(), "Alphabet Size: %d characters\n", machine.GetAlphabetSize())
+	fmt.Fprintf(cmd.OutOrStdout(), "Rotors: %d\n", machine.GetRotorCount())
+	fmt.Fprintf(cmd.OutOrStdout(), "Plugboard Pairs: %d\n", machine.GetPlugboardPairCount())
+}
+
+// presetDescription returns the metadata description of a preset's machine.
+func presetDescription(machine *enigma.Enigma) string {
+	if meta := machine.GetMetadata(); meta != nil && meta.Description != "" {
+		return meta.Description
+	}
+	return "Custom configuration"
+}
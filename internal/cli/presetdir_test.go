@@ -0,0 +1,117 @@
+// Package cli provides unit tests for custom presets in the preset directory.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// saveCustomPreset saves the M3 at AAA as the custom preset name in dir,
+// described as description.
+func saveCustomPreset(t *testing.T, fsys FS, dir, name, description string) {
+	t.Helper()
+	if err := fsys.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name+".json")
+	if _, err := runCLI(fsys, "keygen", "--preset", "m3", "--positions", "AAA", "--output", path); err != nil {
+		t.Fatalf("keygen failed: %v", err)
+	}
+	machine, err := createMachineFromConfig(fsys, configOptions{}, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta := machine.GetMetadata()
+	meta.Description = description
+	machine.SetMetadata(meta)
+	data, err := machine.SaveSettingsToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writeStringToFile(fsys, data, path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCustomPresets(t *testing.T) {
+	t.Setenv(presetDirEnv, "presets")
+	fsys := NewMemFS()
+	saveCustomPreset(t, fsys, "presets", "myteam", "Team key for drills")
+
+	out, err := runCLI(fsys, "encrypt", "--preset", "myteam", "--text", "AAAAA")
+	if err != nil {
+		t.Fatalf("encrypt --preset myteam failed: %v", err)
+	}
+	if strings.TrimSpace(out) != "BDZGO" {
+		t.Errorf("encrypt --preset myteam = %q, want BDZGO", out)
+	}
+
+	out, err = runCLI(fsys, "preset", "--list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "Custom Presets (presets)") || !strings.Contains(out, "myteam") || !strings.Contains(out, "Team key for drills") {
+		t.Errorf("preset --list does not show the custom preset:\n%s", out)
+	}
+
+	out, err = runCLI(fsys, "preset", "--describe", "myteam")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "Preset: myteam (custom)") || !strings.Contains(out, "Rotors: 3") {
+		t.Errorf("preset --describe myteam:\n%s", out)
+	}
+
+	if _, err := runCLI(fsys, "preset", "--export", "myteam", "--output", "copy.json"); err != nil {
+		t.Fatal(err)
+	}
+	out, err = runCLI(fsys, "decrypt", "--config", "copy.json", "--text", "BDZGO")
+	if err != nil || strings.TrimSpace(out) != "AAAAA" {
+		t.Errorf("exported custom preset decrypted BDZGO as %q (%v)", out, err)
+	}
+}
+
+func TestCustomPresetDirFlag(t *testing.T) {
+	t.Setenv(presetDirEnv, "unused")
+	fsys := NewMemFS()
+	saveCustomPreset(t, fsys, "team-presets", "drill", "")
+
+	if _, err := runCLI(fsys, "encrypt", "--preset", "drill", "--text", "A"); err == nil || !strings.Contains(err.Error(), "unknown preset") {
+		t.Errorf("expected an unknown preset error outside --preset-dir, got %v", err)
+	}
+	out, err := runCLI(fsys, "encrypt", "--preset-dir", "team-presets", "--preset", "drill", "--text", "AAAAA")
+	if err != nil || strings.TrimSpace(out) != "BDZGO" {
+		t.Errorf("encrypt --preset-dir team-presets --preset drill = %q (%v)", out, err)
+	}
+	_, err = runCLI(fsys, "encrypt", "--preset-dir", "team-presets", "--preset", "nope", "--text", "A")
+	if err == nil || !strings.Contains(err.Error(), "custom: drill") {
+		t.Errorf("unknown preset error should list custom presets, got %v", err)
+	}
+}
+
+func TestCustomPresetCannotShadowBuiltin(t *testing.T) {
+	t.Setenv(presetDirEnv, "presets")
+	fsys := NewMemFS()
+	if err := fsys.MkdirAll("presets", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runCLI(fsys, "keygen", "--preset", "m4", "--output", filepath.Join("presets", "m3.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runCLI(fsys, "encrypt", "--preset", "m3", "--text", "AAAAA")
+	if err != nil || strings.TrimSpace(out) != "BDZGO" {
+		t.Errorf("built-in m3 should win over presets/m3.json, got %q (%v)", out, err)
+	}
+	out, err = runCLI(fsys, "preset", "--list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "hidden by the built-in preset") {
+		t.Errorf("preset --list should flag the shadowed custom preset:\n%s", out)
+	}
+}
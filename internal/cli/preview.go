@@ -0,0 +1,150 @@
+// Package cli provides width-aware text previews for CLI output.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultTerminalWidth = 80
+	minPreviewWidth      = 16
+	previewEllipsis      = "…"
+)
+
+// preview shortens text for display so it fits on one terminal line, unless
+// --no-truncate is set. reserved is the number of columns already used by the
+// surrounding label.
+func preview(cmd *cobra.Command, text string, reserved int) string {
+	if noTruncate, _ := cmd.Flags().GetBool("no-truncate"); noTruncate {
+		return text
+	}
+	kept, omitted := truncateToWidth(text, previewWidth(reserved))
+	return kept + omittedMarker(omitted)
+}
+
+// quotedPreview is preview for values shown with %q. The text is cut before
+// quoting so escape sequences are never split.
+func quotedPreview(cmd *cobra.Command, text string, reserved int) string {
+	if noTruncate, _ := cmd.Flags().GetBool("no-truncate"); noTruncate {
+		return strconv.Quote(text)
+	}
+	kept, omitted := truncateToWidth(text, previewWidth(reserved)-2)
+	return strconv.Quote(kept) + omittedMarker(omitted)
+}
+
+// previewWidth returns the columns available after reserved ones are used.
+func previewWidth(reserved int) int {
+	width := terminalWidth() - reserved
+	if width < minPreviewWidth {
+		width = minPreviewWidth
+	}
+	return width
+}
+
+// truncateToWidth cuts text at a rune boundary so that, together with the
+// omitted-characters marker, it occupies at most maxWidth terminal columns.
+// It returns the kept prefix and the number of runes removed.
+func truncateToWidth(text string, maxWidth int) (string, int) {
+	if displayWidth(text) <= maxWidth {
+		return text, 0
+	}
+
+	runes := []rune(text)
+	// Reserve room for the marker using the worst case (every rune omitted).
+	budget := maxWidth - displayWidth(omittedMarker(len(runes)))
+
+	// Zero-width runes such as combining marks never exceed the budget, so
+	// they always stay attached to the base character before them.
+	used, kept := 0, 0
+	for _, r := range runes {
+		w := runeWidth(r)
+		if used+w > budget {
+			break
+		}
+		used += w
+		kept++
+	}
+
+	return string(runes[:kept]), len(runes) - kept
+}
+
+// omittedMarker describes how many characters a preview left out.
+func omittedMarker(omitted int) string {
+	if omitted == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s (+%d chars)", previewEllipsis, omitted)
+}
+
+// displayWidth returns the number of terminal columns text occupies.
+func displayWidth(text string) int {
+	width := 0
+	for _, r := range text {
+		width += runeWidth(r)
+	}
+	return width
+}
+
+// padToWidth pads text with spaces to width terminal columns. Unlike %-*s,
+// which counts runes, it lines up wide and combining characters.
+func padToWidth(text string, width int) string {
+	if pad := width - displayWidth(text); pad > 0 {
+		return text + strings.Repeat(" ", pad)
+	}
+	return text
+}
+
+// runeWidth approximates the terminal column width of r: zero for combining
+// and control characters, two for East Asian wide and fullwidth characters
+// and most emoji, one otherwise.
+func runeWidth(r rune) int {
+	switch {
+	case r == 0, unicode.Is(unicode.Mn, r), unicode.Is(unicode.Me, r), unicode.Is(unicode.Cf, r):
+		return 0
+	case unicode.IsControl(r):
+		return 0
+	case isWideRune(r):
+		return 2
+	default:
+		return 1
+	}
+}
+
+// isWideRune reports whether r is rendered in two columns.
+func isWideRune(r rune) bool {
+	switch {
+	case r >= 0x1100 && r <= 0x115F, // Hangul Jamo
+		r >= 0x2E80 && r <= 0x303E, // CJK radicals, punctuation
+		r >= 0x3041 && r <= 0x33FF, // Kana, CJK symbols
+		r >= 0x3400 && r <= 0x4DBF, // CJK extension A
+		r >= 0x4E00 && r <= 0x9FFF, // CJK unified ideographs
+		r >= 0xA000 && r <= 0xA4CF, // Yi
+		r >= 0xAC00 && r <= 0xD7A3, // Hangul syllables
+		r >= 0xF900 && r <= 0xFAFF, // CJK compatibility ideographs
+		r >= 0xFE30 && r <= 0xFE4F, // CJK compatibility forms
+		r >= 0xFF00 && r <= 0xFF60, // Fullwidth forms
+		r >= 0xFFE0 && r <= 0xFFE6,
+		r >= 0x1F300 && r <= 0x1F64F, // Emoji and pictographs
+		r >= 0x1F900 && r <= 0x1F9FF,
+		r >= 0x20000 && r <= 0x3FFFD: // CJK extensions B and later
+		return true
+	}
+	return false
+}
+
+// terminalWidth returns the terminal width from $COLUMNS, or a default.
+func terminalWidth() int {
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return defaultTerminalWidth
+}
@@ -0,0 +1,90 @@
+// Package cli provides unit tests for text previews.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateToWidth(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		width    int
+		expected string
+		omitted  int
+	}{
+		{"fits", "HELLO", 10, "HELLO", 0},
+		{"latin", strings.Repeat("A", 40), 20, strings.Repeat("A", 7), 33},
+		{"cyrillic", strings.Repeat("Ж", 40), 20, strings.Repeat("Ж", 7), 33},
+		{"wide", strings.Repeat("漢", 40), 20, strings.Repeat("漢", 3), 37},
+		{"combining marks", strings.Repeat("e\u0301", 20), 16, strings.Repeat("e\u0301", 3), 34},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kept, omitted := truncateToWidth(tt.text, tt.width)
+			if kept != tt.expected || omitted != tt.omitted {
+				t.Errorf("truncateToWidth() = %q, %d; want %q, %d", kept, omitted, tt.expected, tt.omitted)
+			}
+			if !utf8.ValidString(kept) {
+				t.Errorf("truncated text is not valid UTF-8: %q", kept)
+			}
+			if w := displayWidth(kept + omittedMarker(omitted)); w > tt.width && omitted > 0 {
+				t.Errorf("preview is %d columns wide, limit %d", w, tt.width)
+			}
+		})
+	}
+}
+
+func TestRuneWidth(t *testing.T) {
+	tests := []struct {
+		r     rune
+		width int
+	}{
+		{'A', 1},
+		{'Ж', 1},
+		{'漢', 2},
+		{'한', 2},
+		{'\u0301', 0},
+		{'\n', 0},
+	}
+	for _, tt := range tests {
+		if got := runeWidth(tt.r); got != tt.width {
+			t.Errorf("runeWidth(%q) = %d, want %d", tt.r, got, tt.width)
+		}
+	}
+}
+
+func TestSummaryPreviewNoTruncate(t *testing.T) {
+	t.Setenv("COLUMNS", "40")
+	text := strings.Repeat("HELLO", 40)
+
+	for _, noTruncate := range []bool{false, true} {
+		cmd := NewRootCommand(Options{})
+		var stdout, stderr bytes.Buffer
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&stderr)
+		args := []string{"encrypt", "--text", text, "--preset", "classic", "--summary"}
+		if noTruncate {
+			args = append(args, "--no-truncate")
+		}
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("encrypt failed: %v", err)
+		}
+
+		truncated := strings.Contains(stderr.String(), "(+")
+		if truncated == noTruncate {
+			t.Errorf("noTruncate=%v: unexpected preview %q", noTruncate, stderr.String())
+		}
+		if noTruncate && !strings.Contains(stderr.String(), stdout.String()) {
+			t.Error("--no-truncate should show the full output")
+		}
+	}
+}
@@ -0,0 +1,159 @@
+// Package cli provides QR code export and import of configurations.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"fmt"
+	"image/png"
+	"io"
+	"strings"
+
+	"github.com/coredds/enigoma/internal/qr"
+	"github.com/coredds/enigoma/pkg/enigma"
+	"github.com/spf13/cobra"
+)
+
+// qrPayloadPrefix starts the text carried by a configuration QR code. The
+// rest is the deflated protobuf settings in unpadded URL-safe base64, so
+// phone scanners show it as copyable text.
+const qrPayloadPrefix = "ENIGOMA1:"
+
+// warnUnencryptedQR flags that a QR code exposes the key it carries.
+const warnUnencryptedQR enigma.WarningCode = "unencrypted_qr"
+
+// qrLevel is the error correction level of configuration QR codes.
+const qrLevel = qr.M
+
+// encodeQRPayload returns the QR text for a machine's settings.
+func encodeQRPayload(machine *enigma.Enigma) (string, error) {
+	settings, err := machine.SaveSettingsToProto()
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize settings: %v", err)
+	}
+	var buf bytes.Buffer
+	w, _ := flate.NewWriter(&buf, flate.BestCompression) // The level is valid
+	if _, err := w.Write(settings); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return qrPayloadPrefix + base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// decodeQRPayload creates the machine for the text of a configuration QR code.
+func decodeQRPayload(payload string, limits enigma.Limits) (*enigma.Enigma, error) {
+	encoded, ok := strings.CutPrefix(strings.TrimSpace(payload), qrPayloadPrefix)
+	if !ok {
+		return nil, fmt.Errorf("the QR code does not hold an enigoma configuration")
+	}
+	compressed, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("malformed configuration QR code: %v", err)
+	}
+	// Read one byte past the limit to tell an oversized configuration apart
+	limit := int64(limits.MaxConfigBytes)
+	if limit <= 0 {
+		limit = int64(enigma.DefaultLimits.MaxConfigBytes)
+	}
+	settings, err := io.ReadAll(io.LimitReader(flate.NewReader(bytes.NewReader(compressed)), limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("malformed configuration QR code: %v", err)
+	}
+	if int64(len(settings)) > limit {
+		return nil, &enigma.LimitError{Limit: "config size", Value: len(settings), Max: int(limit)}
+	}
+	return enigma.NewFromProto(settings)
+}
+
+// exportConfigQR writes the configuration in configFile as a PNG QR code.
+func exportConfigQR(configFile string, cmd *cobra.Command) error {
+	outputFile, _ := cmd.Flags().GetString("output")
+	if outputFile == "" {
+		return fmt.Errorf("output file required for the QR code (use --output key.png)")
+	}
+	scale, _ := cmd.Flags().GetInt("qr-scale")
+	if scale < 1 {
+		return fmt.Errorf("--qr-scale must be at least 1")
+	}
+
+	fsys, opts := fileSystem(cmd), configOptionsFor(cmd)
+	machine, err := createMachineFromConfig(fsys, opts, configFile)
+	if err != nil {
+		return fmt.Errorf("failed to read configuration: %v", err)
+	}
+	payload, err := encodeQRPayload(machine)
+	if err != nil {
+		return err
+	}
+	code, err := qr.Encode([]byte(payload), qrLevel)
+	if err != nil {
+		return fmt.Errorf("configuration is too large for a QR code: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, code.Image(scale)); err != nil {
+		return fmt.Errorf("failed to encode PNG: %v", err)
+	}
+	if err := fsys.WriteFile(outputFile, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write QR code: %v", err)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "QR code: version %d, %d×%d modules, %d bytes of data\n",
+		code.Version, code.Size(), code.Size(), len(payload))
+	fmt.Fprintf(uiOut(cmd), "✅ QR code saved to: %s\n", outputFile)
+	return reportWarnings(cmd, enigma.Warning{
+		Code:    warnUnencryptedQR,
+		Message: "the QR code holds the key unencrypted; share it only with its intended holders",
+	})
+}
+
+// importConfigQR reads the configuration QR code in imageFile and writes the
+// configuration to --output, or prints it as JSON.
+func importConfigQR(imageFile string, cmd *cobra.Command) error {
+	fsys, opts := fileSystem(cmd), configOptionsFor(cmd)
+	data, err := fsys.ReadFile(imageFile)
+	if err != nil {
+		return fmt.Errorf("failed to read image: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to decode %s as PNG: %v", imageFile, err)
+	}
+	payload, err := qr.Decode(img)
+	if err != nil {
+		return fmt.Errorf("failed to read QR code: %v", err)
+	}
+	limits, err := configLimits()
+	if err != nil {
+		return err
+	}
+	machine, err := decodeQRPayload(string(payload), limits)
+	if err != nil {
+		return err
+	}
+	jsonData, err := machine.SaveSettingsToJSON()
+	if err != nil {
+		return fmt.Errorf("failed to serialize settings: %v", err)
+	}
+
+	outputFile, _ := cmd.Flags().GetString("output")
+	if outputFile == "" {
+		sealed, err := encryptConfigIfRequested(opts, jsonData)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(cmd.OutOrStdout(), sealed)
+		return nil
+	}
+	if err := writeConfigFile(fsys, opts, outputFile, jsonData); err != nil {
+		return fmt.Errorf("failed to write configuration: %v", err)
+	}
+	fmt.Fprintf(uiOut(cmd), "✅ Configuration imported to: %s\n", outputFile)
+	return nil
+}
@@ -0,0 +1,95 @@
+// Package cli provides unit tests for QR code export and import of configurations.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/coredds/enigoma/pkg/enigma"
+)
+
+func TestConfigQRRoundTrip(t *testing.T) {
+	fsys := NewMemFS()
+	if _, err := runCLI(fsys, "keygen", "--security", "high", "--output", "key.json"); err != nil {
+		t.Fatal(err)
+	}
+	out, err := runCLI(fsys, "config", "--qr", "key.json", "--output", "key.png", "--qr-scale", "4")
+	if err != nil {
+		t.Fatalf("config --qr failed: %v", err)
+	}
+	if !strings.Contains(out, "QR code: version") {
+		t.Errorf("config --qr should describe the code:\n%s", out)
+	}
+	data, _ := fsys.ReadFile("key.png")
+	if _, err := png.Decode(bytes.NewReader(data)); err != nil {
+		t.Fatalf("key.png is not a PNG: %v", err)
+	}
+
+	if _, err := runCLI(fsys, "config", "--from-qr", "key.png", "--output", "scanned.yaml"); err != nil {
+		t.Fatalf("config --from-qr failed: %v", err)
+	}
+	original, err := createMachineFromConfig(fsys, configOptions{}, "key.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanned, err := createMachineFromConfig(fsys, configOptions{}, "scanned.yaml")
+	if err != nil {
+		t.Fatalf("loading the scanned configuration failed: %v", err)
+	}
+	want, _ := original.Fingerprint()
+	if got, _ := scanned.Fingerprint(); got != want {
+		t.Error("the QR round trip should keep the key")
+	}
+
+	printed, err := runCLI(fsys, "config", "--from-qr", "key.png")
+	if err != nil {
+		t.Fatalf("config --from-qr to stdout failed: %v", err)
+	}
+	if _, err := enigma.NewFromJSON(printed); err != nil {
+		t.Errorf("config --from-qr without --output should print JSON: %v", err)
+	}
+}
+
+func TestConfigQRErrors(t *testing.T) {
+	fsys := NewMemFS()
+	if _, err := runCLI(fsys, "keygen", "--preset", "m3", "--output", "key.json"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runCLI(fsys, "config", "--qr", "key.json"); err == nil {
+		t.Error("--qr without --output should fail")
+	}
+	if _, err := runCLI(fsys, "config", "--from-qr", "key.json"); err == nil || !strings.Contains(err.Error(), "PNG") {
+		t.Errorf("--from-qr on a non-image should fail, got %v", err)
+	}
+}
+
+func TestDecodeQRPayload(t *testing.T) {
+	machine, err := enigma.NewEnigmaM4()
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := encodeQRPayload(machine)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(payload, qrPayloadPrefix) {
+		t.Fatalf("payload %q lacks the prefix", payload)
+	}
+	if _, err := decodeQRPayload(payload, enigma.DefaultLimits); err != nil {
+		t.Errorf("decodeQRPayload failed: %v", err)
+	}
+
+	if _, err := decodeQRPayload("https://example.com", enigma.DefaultLimits); err == nil {
+		t.Error("a foreign QR code should be rejected")
+	}
+	small := enigma.DefaultLimits
+	small.MaxConfigBytes = 16
+	if _, err := decodeQRPayload(payload, small); err == nil || !strings.Contains(err.Error(), "config size") {
+		t.Errorf("an oversized payload should hit the size limit, got %v", err)
+	}
+}
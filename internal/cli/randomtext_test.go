@@ -0,0 +1,86 @@
+// Package cli provides unit tests for the random-text command.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestRandomTextFromConfig(t *testing.T) {
+	fsys := NewMemFS()
+	cmd := NewRootCommand(Options{Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, FS: fsys})
+	cmd.SetArgs([]string{"keygen", "--alphabet", "greek", "--preset", "classic", "--output", "key.json"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("keygen failed: %v", err)
+	}
+	machine, err := createMachineFromConfig(fsys, configOptions{}, "key.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	alph, err := machineAlphabet(machine)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runCLI(fsys, "random-text", "--config", "key.json", "--length", "500")
+	if err != nil {
+		t.Fatalf("random-text failed: %v", err)
+	}
+	text := strings.TrimSuffix(out, "\n")
+	if n := utf8.RuneCountInString(text); n != 500 {
+		t.Errorf("got %d characters, want 500", n)
+	}
+	if r, err := alph.ValidateString(text); err != nil {
+		t.Errorf("%c is not in the key's alphabet", r)
+	}
+	if _, err := machine.Encrypt(text); err != nil {
+		t.Errorf("random text should encrypt under its key: %v", err)
+	}
+}
+
+func TestRandomTextSeedAndOutput(t *testing.T) {
+	fsys := NewMemFS()
+	first, err := runCLI(fsys, "random-text", "--alphabet", "latin", "--length", "40", "--seed", "7")
+	if err != nil {
+		t.Fatalf("random-text failed: %v", err)
+	}
+	second, _ := runCLI(fsys, "random-text", "--alphabet", "latin", "--length", "40", "--seed", "7")
+	if first != second {
+		t.Errorf("same seed gave %q and %q", first, second)
+	}
+
+	out, err := runCLI(fsys, "random-text", "--alphabet", "latin", "--length", "40", "--seed", "7", "--output", "sample.txt")
+	if err != nil {
+		t.Fatalf("random-text --output failed: %v", err)
+	}
+	if !strings.Contains(out, "Wrote 40 random characters to sample.txt") {
+		t.Errorf("unexpected output: %q", out)
+	}
+	data, err := fsys.ReadFile("sample.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data)+"\n" != first {
+		t.Errorf("file holds %q, want %q", data, first)
+	}
+}
+
+func TestRandomTextErrors(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{nil, "requires --config or --alphabet"},
+		{[]string{"--alphabet", "klingon"}, "unknown alphabet"},
+		{[]string{"--alphabet", "latin", "--length", "0"}, "--length must be positive"},
+	} {
+		if _, err := runCLI(NewMemFS(), append([]string{"random-text"}, tc.args...)...); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%v: expected %q, got %v", tc.args, tc.want, err)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// Package cli provides multi-recipient encryption for the encrypt command.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+// recipientConfigs expands --config-list (which may contain glob patterns) plus
+// any positional arguments left over when the shell expanded the glob itself.
+func recipientConfigs(cmd *cobra.Command, args []string) ([]string, error) {
+	patterns, _ := cmd.Flags().GetStringSlice("config-list")
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	patterns = append(patterns, args...)
+
+	fsys := fileSystem(cmd)
+	var configs []string
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		matches, err := fsys.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %v", pattern, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no configuration files match %q", pattern)
+		}
+		for _, match := range matches {
+			if !seen[match] {
+				seen[match] = true
+				configs = append(configs, match)
+			}
+		}
+	}
+	return configs, nil
+}
+
+// encryptForRecipients encrypts text once per configuration, writing each
+// result to --output-dir named by the configuration's fingerprint.
+func encryptForRecipients(cmd *cobra.Command, text string, configs []string) error {
+	outputDir, _ := cmd.Flags().GetString("output-dir")
+	if outputDir == "" {
+		return fmt.Errorf("--config-list requires --output-dir")
+	}
+	if output, _ := cmd.Flags().GetString("output"); output != "" {
+		return fmt.Errorf("--output cannot be combined with --config-list; use --output-dir")
+	}
+	fsys, opts := fileSystem(cmd), configOptionsFor(cmd)
+	if err := fsys.MkdirAll(outputDir, 0750); err != nil {
+		return fmt.Errorf("failed to create output directory: %v", err)
+	}
+
+	page := wantsHTML(cmd)
+	container := wantsContainer(cmd)
+	ext := ".txt"
+	if page {
+		ext = htmlExtension
+	} else if container {
+		ext = ".enig"
+	}
+
+	out := cmd.OutOrStdout()
+	for _, configFile := range configs {
+		machine, err := createMachineFromConfig(fsys, opts, configFile)
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %v", configFile, err)
+		}
+		if err := applyRotorOverrides(cmd, machine); err != nil {
+			return fmt.Errorf("%s: %v", configFile, err)
+		}
+		if err := reportMachineWarnings(cmd, machine); err != nil {
+			return err
+		}
+		if err := checkKeyExpiry(cmd, machine); err != nil {
+			return fmt.Errorf("%s: %v", configFile, err)
+		}
+
+		fingerprint, err := machine.Fingerprint()
+		if err != nil {
+			return fmt.Errorf("failed to fingerprint %s: %v", configFile, err)
+		}
+		verboseKeyInfo(cmd, machine, fingerprint)
+
+		input, err := fitInputToAlphabet(cmd, text, machine)
+		if err != nil {
+			return err
+		}
+		encrypted, err := machine.Encrypt(input)
+		if err != nil {
+			return fmt.Errorf("%s: %v", configFile, enhanceEncryptionError(err, input, fingerprint, cmd))
+		}
+
+		var formatted string
+		if page {
+			formatted, err = buildHTMLExport(cmd, encrypted, input, fingerprint)
+		} else if container {
+			formatted, err = buildContainer(cmd, encrypted, input, fingerprint)
+		} else {
+			formatted, err = formatOutput(encrypted, cmd)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to format output: %v", err)
+		}
+
+		path := filepath.Join(outputDir, shortFingerprint(fingerprint)+ext)
+		if err := fsys.WriteFile(path, []byte(formatted), 0600); err != nil {
+			return fmt.Errorf("failed to write %s: %v", path, err)
+		}
+		fmt.Fprintf(out, "%s -> %s\n", configFile, path)
+	}
+
+	fmt.Fprintf(out, "Encrypted for %d recipient(s) in %s\n", len(configs), outputDir)
+	return nil
+}
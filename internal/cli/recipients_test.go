@@ -0,0 +1,102 @@
+// Package cli provides unit tests for multi-recipient encryption.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+)
+
+func TestEncryptConfigList(t *testing.T) {
+	fsys := NewMemFS()
+	keysDir := "keys"
+	outDir := "out"
+	if err := fsys.MkdirAll(keysDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+
+	keys := make(map[string]string) // fingerprint prefix -> key path
+	for _, name := range []string{"alice.json", "bob.json", "carol.json"} {
+		path := filepath.Join(keysDir, name)
+		cmd := NewRootCommand(Options{FS: fsys})
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetArgs([]string{"keygen", "--preset", "high", "--output", path})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("keygen failed: %v", err)
+		}
+		fp, err := fingerprintConfigFile(fsys, configOptions{}, path)
+		if err != nil {
+			t.Fatalf("fingerprint failed: %v", err)
+		}
+		keys[shortFingerprint(fp)] = path
+	}
+
+	// Quoted glob pattern plus an explicit duplicate that must be ignored
+	cmd := NewRootCommand(Options{FS: fsys})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"encrypt", "--text", "MEETATNOON",
+		"--config-list", filepath.Join(keysDir, "*.json"),
+		"--config-list", filepath.Join(keysDir, "bob.json"),
+		"--output-dir", outDir})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("encrypt --config-list failed: %v", err)
+	}
+
+	entries, err := fsys.ReadDir(outDir)
+	if err != nil {
+		t.Fatalf("reading output dir failed: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 outputs, got %d", len(entries))
+	}
+
+	for _, entry := range entries {
+		prefix := entry.Name()[:len(entry.Name())-len(".txt")]
+		key, ok := keys[prefix]
+		if !ok {
+			t.Errorf("output %s is not named after a key fingerprint", entry.Name())
+			continue
+		}
+
+		cmd := NewRootCommand(Options{FS: fsys})
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetArgs([]string{"decrypt", "--file", filepath.Join(outDir, entry.Name()), "--config", key})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("decrypt failed: %v", err)
+		}
+		if out.String() != "MEETATNOON" {
+			t.Errorf("%s decrypted to %q", entry.Name(), out.String())
+		}
+	}
+}
+
+func TestEncryptConfigListRequiresOutputDir(t *testing.T) {
+	fsys := NewMemFS()
+	cmd := NewRootCommand(Options{FS: fsys})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"encrypt", "--text", "HELLO", "--config-list", "missing/*.json"})
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected error when no configuration matches")
+	}
+
+	key := "key.json"
+	cmd = NewRootCommand(Options{FS: fsys})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"keygen", "--preset", "classic", "--output", key})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("keygen failed: %v", err)
+	}
+
+	cmd = NewRootCommand(Options{FS: fsys})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"encrypt", "--text", "HELLO", "--config-list", key})
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected error when --output-dir is missing")
+	}
+}
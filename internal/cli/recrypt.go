@@ -0,0 +1,364 @@
+// Package cli provides the recrypt command, which moves ciphertext from one
+// key to another.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/coredds/enigoma/pkg/enigma"
+	"github.com/spf13/cobra"
+)
+
+// recryptChunkSize is the number of characters recrypted at a time.
+const recryptChunkSize = 4096
+
+// recryptKeys holds the two machines of a recrypt run and their fingerprints
+// before any encryption.
+type recryptKeys struct {
+	from, to            *enigma.Enigma
+	fromFingerprint     string
+	toFingerprint       string
+	toConfig            string
+	keepTrailingNewline bool
+}
+
+// newRecryptCommand creates the recrypt command.
+func newRecryptCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "recrypt",
+		Short: "Re-encrypt ciphertext from one key to another",
+		Long: `Re-encrypt ciphertext from one key to another in a single pass.
+
+Each chunk is decrypted with the --old-config key and encrypted again with
+the --new-config key straight away, so the plaintext stays in memory and is
+never written to disk. Use it to rotate keys, or with 'enigoma config --rekey'
+to move messages to a key over another alphabet.
+
+--in may name a directory: every file in it is recrypted into the --out
+directory under the same name, each from the keys' starting positions. Key
+sidecars (*.key) are not copied; a recrypted file whose original had one gets
+a new sidecar pointing at --new-config.
+
+.enig containers are recrypted in place: the key fingerprint is checked
+against --old-config and replaced, and the plaintext check is kept, since the
+plaintext does not change.
+
+Every plaintext character must be in the new key's alphabet; recrypt stops
+at the first one that is not and names its offset.
+
+Examples:
+  enigoma recrypt --in cipher.txt --old-config old.json --new-config new.json --out cipher2.txt
+  enigoma recrypt --in messages/ --old-config old.json --new-config new.json --out rotated/
+  cat cipher.txt | enigoma recrypt --old-config old.json --new-config new.json`,
+		Args: cobra.NoArgs,
+		RunE: runRecrypt,
+	}
+
+	cmd.Flags().String("old-config", "", "Configuration the ciphertext was encrypted with")
+	cmd.Flags().String("new-config", "", "Configuration to re-encrypt with")
+	cmd.Flags().StringP("in", "i", "", "Ciphertext file, or a directory of them (default: stdin)")
+	cmd.Flags().StringP("out", "o", "", "Output file, or a directory with a directory --in (default: stdout)")
+	cmd.Flags().StringP("text", "t", "", "Ciphertext to recrypt")
+	addInputCleanupFlags(cmd)
+
+	return cmd
+}
+
+func runRecrypt(cmd *cobra.Command, args []string) error {
+	setupVerbose(cmd)
+
+	oldConfig, _ := cmd.Flags().GetString("old-config")
+	newConfig, _ := cmd.Flags().GetString("new-config")
+	if oldConfig == "" || newConfig == "" {
+		return fmt.Errorf("recrypt requires --old-config and --new-config")
+	}
+
+	fsys := fileSystem(cmd)
+	keys, err := loadRecryptKeys(fsys, configOptionsFor(cmd), oldConfig, newConfig)
+	if err != nil {
+		return err
+	}
+	if err := checkKeyExpiry(cmd, keys.to); err != nil {
+		return err
+	}
+	keys.keepTrailingNewline, _ = cmd.Flags().GetBool("keep-trailing-newline")
+
+	in, _ := cmd.Flags().GetString("in")
+	out, _ := cmd.Flags().GetString("out")
+	if in != "" {
+		if info, err := fsys.Stat(in); err == nil && info.IsDir() {
+			return recryptDirectory(cmd, fsys, keys, in, out)
+		}
+		if out == "" {
+			data, err := fsys.ReadFile(in)
+			if err != nil {
+				return fmt.Errorf("failed to read file %s: %w", in, err)
+			}
+			return recryptToStdout(cmd, keys, bytes.NewReader(data))
+		}
+		count, err := recryptFile(fsys, keys, in, out)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(uiOut(cmd), "✅ Recrypted %d characters to %s\n", count, out)
+		return nil
+	}
+
+	var r io.Reader
+	if text, _ := cmd.Flags().GetString("text"); text != "" {
+		r = strings.NewReader(text)
+	} else {
+		r = cmd.InOrStdin()
+		if isTerminalInput(r) {
+			return fmt.Errorf("no input provided. Use --in, --text, or pipe ciphertext to stdin")
+		}
+	}
+	if out == "" {
+		return recryptToStdout(cmd, keys, r)
+	}
+
+	var buf strings.Builder
+	count, err := recryptReader(keys, r, &buf)
+	if err != nil {
+		return err
+	}
+	if err := writeStringToFile(fsys, buf.String(), out); err != nil {
+		return fmt.Errorf("failed to write recrypted text: %v", err)
+	}
+	fmt.Fprintf(uiOut(cmd), "✅ Recrypted %d characters to %s\n", count, out)
+	return nil
+}
+
+// loadRecryptKeys loads both configurations and fingerprints them.
+func loadRecryptKeys(fsys FS, opts configOptions, oldConfig, newConfig string) (*recryptKeys, error) {
+	from, err := createMachineFromConfig(fsys, opts, oldConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load --old-config: %v", err)
+	}
+	to, err := createMachineFromConfig(fsys, opts, newConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load --new-config: %v", err)
+	}
+	fromFingerprint, err := from.Fingerprint()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fingerprint --old-config: %v", err)
+	}
+	toFingerprint, err := to.Fingerprint()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fingerprint --new-config: %v", err)
+	}
+	return &recryptKeys{
+		from:            from,
+		to:              to,
+		fromFingerprint: fromFingerprint,
+		toFingerprint:   toFingerprint,
+		toConfig:        newConfig,
+	}, nil
+}
+
+// recryptToStdout writes the recrypted text to stdout as it is produced.
+func recryptToStdout(cmd *cobra.Command, keys *recryptKeys, r io.Reader) error {
+	if _, err := recryptReader(keys, r, cmd.OutOrStdout()); err != nil {
+		return err
+	}
+	fmt.Fprintln(cmd.OutOrStdout())
+	return nil
+}
+
+// recryptDirectory recrypts every file in dir into outDir. Both machines are
+// reset before each file, since every file was encrypted from the old key's
+// starting positions.
+func recryptDirectory(cmd *cobra.Command, fsys FS, keys *recryptKeys, dir, outDir string) error {
+	if outDir == "" {
+		return fmt.Errorf("a directory --in needs an --out directory")
+	}
+	if filepath.Clean(outDir) == filepath.Clean(dir) {
+		return fmt.Errorf("--out must differ from --in, so a failed run leaves the originals untouched")
+	}
+	entries, err := fsys.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read directory %s: %w", dir, err)
+	}
+	if err := fsys.MkdirAll(outDir, 0700); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", outDir, err)
+	}
+
+	files := 0
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || strings.HasSuffix(name, sidecarExtension) {
+			continue
+		}
+		keys.from.Reset()
+		keys.to.Reset()
+
+		in, out := filepath.Join(dir, name), filepath.Join(outDir, name)
+		if _, err := recryptFile(fsys, keys, in, out); err != nil {
+			return fmt.Errorf("%s: %v", in, err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%s -> %s\n", in, out)
+		files++
+	}
+	fmt.Fprintf(uiOut(cmd), "✅ Recrypted %d files into %s\n", files, outDir)
+	return nil
+}
+
+// recryptFile recrypts the file in into out. When in has a key sidecar, out
+// gets one pointing at the new configuration.
+func recryptFile(fsys FS, keys *recryptKeys, in, out string) (int, error) {
+	data, err := fsys.ReadFile(in)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read file %s: %w", in, err)
+	}
+	var buf strings.Builder
+	count, err := recryptReader(keys, bytes.NewReader(data), &buf)
+	if err != nil {
+		return 0, err
+	}
+	if err := writeStringToFile(fsys, buf.String(), out); err != nil {
+		return 0, fmt.Errorf("failed to write recrypted text: %v", err)
+	}
+	if fileExists(fsys, sidecarPath(in)) {
+		if err := writeSidecar(fsys, out, keys.toConfig, keys.toFingerprint); err != nil {
+			return 0, err
+		}
+	}
+	return count, nil
+}
+
+// recryptReader recrypts r into w. Input starting with '{' is read whole and
+// handled as an .enig container; anything else is streamed.
+func recryptReader(keys *recryptKeys, r io.Reader, w io.Writer) (int, error) {
+	br := bufio.NewReader(r)
+	if first, err := br.Peek(1); err != nil || first[0] != '{' {
+		return recryptStream(keys.from, keys.to, br, w, keys.keepTrailingNewline)
+	}
+
+	data, err := io.ReadAll(br)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read ciphertext: %v", err)
+	}
+	if !enigma.IsContainer(string(data)) {
+		return recryptStream(keys.from, keys.to, bytes.NewReader(data), w, keys.keepTrailingNewline)
+	}
+	container, err := enigma.ParseContainer(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, err
+	}
+	if container.KeyFingerprint != "" && container.KeyFingerprint != keys.fromFingerprint {
+		return 0, fmt.Errorf("container was encrypted with key %s, not --old-config (%s)",
+			shortFingerprint(container.KeyFingerprint), shortFingerprint(keys.fromFingerprint))
+	}
+
+	var ciphertext strings.Builder
+	count, err := recryptStream(keys.from, keys.to, strings.NewReader(container.Ciphertext), &ciphertext, keys.keepTrailingNewline)
+	if err != nil {
+		return 0, err
+	}
+	container.Ciphertext = ciphertext.String()
+	container.KeyFingerprint = keys.toFingerprint
+	marshaled, err := container.Marshal()
+	if err != nil {
+		return 0, err
+	}
+	if _, err := io.WriteString(w, marshaled); err != nil {
+		return 0, fmt.Errorf("failed to write recrypted text: %v", err)
+	}
+	return count, nil
+}
+
+// recryptStream decrypts r with from and encrypts the result with to, one
+// chunk at a time, writing the new ciphertext to w. Like encrypt and decrypt
+// it skips invisible characters and the final line ending when the old
+// alphabet lacks them. It returns the number of characters recrypted.
+func recryptStream(from, to *enigma.Enigma, r io.Reader, w io.Writer, keepTrailingNewline bool) (int, error) {
+	fromAlphabet, err := machineAlphabet(from)
+	if err != nil {
+		return 0, err
+	}
+	toAlphabet, err := machineAlphabet(to)
+	if err != nil {
+		return 0, err
+	}
+
+	br := bufio.NewReader(r)
+	chunk := make([]rune, 0, recryptChunkSize)
+	offsets := make([]int, 0, recryptChunkSize)
+	count := 0
+	flush := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+		plaintext, err := from.Decrypt(string(chunk))
+		if err != nil {
+			return fmt.Errorf("decryption failed at character %d: %v", offsets[0], err)
+		}
+		for i, c := range []rune(plaintext) {
+			if !toAlphabet.Contains(c) {
+				return fmt.Errorf("character %d decrypts to %q, which is not in the new key's alphabet", offsets[i], c)
+			}
+		}
+		ciphertext, err := to.Encrypt(plaintext)
+		if err != nil {
+			return fmt.Errorf("encryption failed at character %d: %v", offsets[0], err)
+		}
+		if _, err := io.WriteString(w, ciphertext); err != nil {
+			return fmt.Errorf("failed to write recrypted text: %v", err)
+		}
+		count += len(chunk)
+		chunk = chunk[:0]
+		offsets = offsets[:0]
+		return nil
+	}
+
+	for offset := 0; ; offset++ {
+		c, _, err := br.ReadRune()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return count, fmt.Errorf("failed to read ciphertext at character %d: %v", offset, err)
+		}
+
+		if !fromAlphabet.Contains(c) {
+			if _, ok := invisibleRunes[c]; ok {
+				continue
+			}
+			if !keepTrailingNewline && isFinalLineEnding(br, c) {
+				break
+			}
+			return count, fmt.Errorf("character %d (%q) is not in the old key's alphabet. Make sure --old-config is the key the text was encrypted with", offset, c)
+		}
+
+		chunk = append(chunk, c)
+		offsets = append(offsets, offset)
+		if len(chunk) == recryptChunkSize {
+			if err := flush(); err != nil {
+				return count, err
+			}
+		}
+	}
+	return count, flush()
+}
+
+// isFinalLineEnding reports whether c, just read from br, starts the line
+// ending that closes the input.
+func isFinalLineEnding(br *bufio.Reader, c rune) bool {
+	switch c {
+	case '\n':
+		_, err := br.Peek(1)
+		return err == io.EOF
+	case '\r':
+		next, err := br.Peek(2)
+		return err == io.EOF && len(next) == 1 && next[0] == '\n'
+	}
+	return false
+}
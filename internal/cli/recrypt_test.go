@@ -0,0 +1,201 @@
+// Package cli provides unit tests for the recrypt command.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/coredds/enigoma/pkg/enigma"
+)
+
+func TestRecryptRoundTrip(t *testing.T) {
+	fsys := NewMemFS()
+	if _, err := runCLI(fsys, "keygen", "--alphabet", "latin", "--output", "old.json"); err != nil {
+		t.Fatalf("keygen failed: %v", err)
+	}
+	if _, err := runCLI(fsys, "config", "--rekey", "old.json", "--to-alphabet", "alphanumeric", "--output", "new.json"); err != nil {
+		t.Fatalf("rekey failed: %v", err)
+	}
+
+	plaintext := strings.Repeat("ATTACKATDAWN", 500)
+	old, _ := createMachineFromConfig(fsys, configOptions{}, "old.json")
+	ciphertext, err := old.Encrypt(plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writeStringToFile(fsys, "\uFEFF"+ciphertext+"\n", "message.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := runCLI(fsys, "recrypt", "--in", "message.txt", "--old-config", "old.json", "--new-config", "new.json", "--out", "message.new.txt"); err != nil {
+		t.Fatalf("recrypt failed: %v", err)
+	}
+	recrypted, err := fsys.ReadFile("message.new.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rekeyed, _ := createMachineFromConfig(fsys, configOptions{}, "new.json")
+	decrypted, err := rekeyed.Decrypt(string(recrypted))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decrypted != plaintext {
+		t.Errorf("recrypted text decrypts to %.20q..., want %.20q...", decrypted, plaintext)
+	}
+}
+
+func TestRecryptPlaintextOutsideNewAlphabet(t *testing.T) {
+	from, err := enigma.NewEnigmaSimple([]rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	to, err := enigma.NewEnigmaSimple([]rune("ABCDEF"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ciphertext, _ := from.Encrypt("ABCZ")
+	from.Reset()
+
+	_, err = recryptStream(from, to, strings.NewReader(ciphertext), &bytes.Buffer{}, false)
+	if err == nil || !strings.Contains(err.Error(), "character 3") {
+		t.Errorf("expected an error naming character 3, got %v", err)
+	}
+}
+
+func TestRecryptRejectsForeignCharacters(t *testing.T) {
+	fsys := NewMemFS()
+	if _, err := runCLI(fsys, "keygen", "--alphabet", "latin", "--output", "old.json"); err != nil {
+		t.Fatalf("keygen failed: %v", err)
+	}
+	_, err := runCLI(fsys, "recrypt", "--old-config", "old.json", "--new-config", "old.json", "--text", "AB\nCD")
+	if err == nil || !strings.Contains(err.Error(), "character 2") {
+		t.Errorf("expected an error naming character 2, got %v", err)
+	}
+}
+
+// rotationKeys writes old.json and new.json over the Latin alphabet.
+func rotationKeys(t *testing.T, fsys FS) (old, rotated *enigma.Enigma) {
+	t.Helper()
+	for _, name := range []string{"old.json", "new.json"} {
+		if _, err := runCLI(fsys, "keygen", "--alphabet", "latin", "--output", name); err != nil {
+			t.Fatalf("keygen failed: %v", err)
+		}
+	}
+	old, _ = createMachineFromConfig(fsys, configOptions{}, "old.json")
+	rotated, _ = createMachineFromConfig(fsys, configOptions{}, "new.json")
+	return old, rotated
+}
+
+func TestRecryptDirectory(t *testing.T) {
+	fsys := NewMemFS()
+	old, rotated := rotationKeys(t, fsys)
+
+	if err := fsys.MkdirAll("messages", 0700); err != nil {
+		t.Fatal(err)
+	}
+	messages := map[string]string{"a.txt": "FIRSTMESSAGE", "b.txt": "SECONDMESSAGE"}
+	for name, plaintext := range messages {
+		old.Reset()
+		ciphertext, _ := old.Encrypt(plaintext)
+		if err := writeStringToFile(fsys, ciphertext, "messages/"+name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old.Reset()
+	fingerprint, _ := old.Fingerprint()
+	if err := writeSidecar(fsys, "messages/a.txt", "old.json", fingerprint); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runCLI(fsys, "recrypt", "--in", "messages", "--old-config", "old.json", "--new-config", "new.json", "--out", "rotated")
+	if err != nil {
+		t.Fatalf("recrypt failed: %v", err)
+	}
+	if !strings.Contains(out, "Recrypted 2 files") {
+		t.Errorf("expected a summary of 2 files, got:\n%s", out)
+	}
+	for name, plaintext := range messages {
+		data, err := fsys.ReadFile("rotated/" + name)
+		if err != nil {
+			t.Fatalf("missing rotated/%s: %v", name, err)
+		}
+		rotated.Reset()
+		if got, _ := rotated.Decrypt(string(data)); got != plaintext {
+			t.Errorf("rotated/%s decrypts to %q, want %q", name, got, plaintext)
+		}
+	}
+
+	// Only the file that had a sidecar gets one, pointing at the new key
+	sidecar, err := readSidecar(fsys, "rotated/a.txt.key")
+	if err != nil {
+		t.Fatalf("expected a sidecar for rotated/a.txt: %v", err)
+	}
+	rotated.Reset()
+	if want, _ := rotated.Fingerprint(); sidecar.Fingerprint != want {
+		t.Errorf("sidecar fingerprint = %s, want the new key's", sidecar.Fingerprint)
+	}
+	if fileExists(fsys, "rotated/b.txt.key") || fileExists(fsys, "rotated/a.txt.key.key") {
+		t.Error("unexpected sidecar in the output directory")
+	}
+}
+
+func TestRecryptDirectoryNeedsSeparateOut(t *testing.T) {
+	fsys := NewMemFS()
+	rotationKeys(t, fsys)
+	if err := fsys.MkdirAll("messages", 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeStringToFile(fsys, "ABC", "messages/a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runCLI(fsys, "recrypt", "--in", "messages", "--old-config", "old.json", "--new-config", "new.json"); err == nil {
+		t.Error("expected an error without --out")
+	}
+	if _, err := runCLI(fsys, "recrypt", "--in", "messages", "--old-config", "old.json", "--new-config", "new.json", "--out", "messages/"); err == nil {
+		t.Error("expected an error when --out is --in")
+	}
+}
+
+func TestRecryptContainer(t *testing.T) {
+	fsys := NewMemFS()
+	old, rotated := rotationKeys(t, fsys)
+
+	plaintext := "KEEPTHECHECK"
+	fingerprint, _ := old.Fingerprint()
+	ciphertext, _ := old.Encrypt(plaintext)
+	container := enigma.NewContainer(ciphertext)
+	container.KeyFingerprint = fingerprint
+	if err := container.AddPlaintextCheck(plaintext); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := container.Marshal()
+	if err := writeStringToFile(fsys, data, "message.enig"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := runCLI(fsys, "recrypt", "--in", "message.enig", "--old-config", "old.json", "--new-config", "new.json", "--out", "message2.enig"); err != nil {
+		t.Fatalf("recrypt failed: %v", err)
+	}
+	out, _ := fsys.ReadFile("message2.enig")
+	recrypted, err := enigma.ParseContainer(string(out))
+	if err != nil {
+		t.Fatalf("output is not a container: %v", err)
+	}
+	if want, _ := rotated.Fingerprint(); recrypted.KeyFingerprint != want {
+		t.Errorf("container fingerprint = %s, want the new key's", recrypted.KeyFingerprint)
+	}
+	decrypted, _ := rotated.Decrypt(recrypted.Ciphertext)
+	if ok, err := recrypted.VerifyPlaintext(decrypted); err != nil || !ok {
+		t.Errorf("plaintext check should still verify: ok=%v err=%v", ok, err)
+	}
+
+	// The container names its key, so the wrong old key is caught up front
+	_, err = runCLI(fsys, "recrypt", "--in", "message2.enig", "--old-config", "old.json", "--new-config", "new.json", "--out", "message3.enig")
+	if err == nil || !strings.Contains(err.Error(), "not --old-config") {
+		t.Errorf("expected a key mismatch error, got %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+// Package cli provides config --rekey, which moves a key to another alphabet.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/coredds/enigoma"
+	"github.com/coredds/enigoma/pkg/enigma"
+	"github.com/spf13/cobra"
+)
+
+// rekeyConfig writes a new key over the --to-alphabet alphabet. The old key's
+// description and tags are kept; the creation record, preset and expiry
+// describe the old key and are not. The reflector mode is kept too, and with
+// --keep-shape so are the rotor count and plugboard pair count.
+func rekeyConfig(configFile string, cmd *cobra.Command) error {
+	alphabetName, _ := cmd.Flags().GetString("to-alphabet")
+	if alphabetName == "" {
+		return fmt.Errorf("--rekey requires --to-alphabet")
+	}
+	predefined, ok := enigoma.LookupAlphabet(alphabetName)
+	if !ok {
+		return fmt.Errorf("unknown alphabet: %s. Available: %s", alphabetName, alphabetNameList(false))
+	}
+	outputFile, _ := cmd.Flags().GetString("output")
+	if outputFile == "" {
+		return fmt.Errorf("output file required for rekeying (use --output)")
+	}
+
+	fsys, configOpts := fileSystem(cmd), configOptionsFor(cmd)
+	old, err := createMachineFromConfig(fsys, configOpts, configFile)
+	if err != nil {
+		return fmt.Errorf("failed to read configuration to rekey: %v", err)
+	}
+
+	opts := []enigma.Option{enigma.WithAlphabet(predefined.Runes)}
+	if old.IsPlugboardDisabled() {
+		opts = append(opts, enigma.WithoutPlugboard())
+	}
+	if old.IsReflectorless() {
+		opts = append(opts, enigma.WithoutReflector())
+	} else if len(predefined.Runes)%2 != 0 {
+		return fmt.Errorf("the %s alphabet has an odd number of characters (%d), which a reflector cannot pair. Rekey to an even-sized alphabet, or from a key made with --no-reflector",
+			predefined.Name, len(predefined.Runes))
+	}
+	if keepShape, _ := cmd.Flags().GetBool("keep-shape"); keepShape {
+		opts = append(opts, enigma.WithRandomComponents(old.GetRotorCount(), old.GetPlugboardPairCount()))
+	} else {
+		level, err := getSecurityLevelFromFlag(cmd)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, enigma.WithRandomSettings(level))
+	}
+	machine, err := enigma.New(opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create rekeyed machine: %v", err)
+	}
+	if err := reportMachineWarnings(cmd, machine); err != nil {
+		return err
+	}
+
+	meta := old.GetMetadata()
+	if meta == nil {
+		meta = &enigma.Metadata{}
+	}
+	meta.CreatedAt = now().UTC().Format(time.RFC3339)
+	meta.CreatedBy = "enigoma config --rekey"
+	meta.Preset = ""
+	meta.ExpiresAt = ""
+	meta.Signature = nil // The new key needs its own signature
+	machine.SetMetadata(meta)
+
+	jsonData, err := machine.SaveSettingsToJSON()
+	if err != nil {
+		return fmt.Errorf("failed to serialize settings: %v", err)
+	}
+	if err := writeConfigFile(fsys, configOpts, outputFile, jsonData); err != nil {
+		return fmt.Errorf("failed to write rekeyed configuration: %v", err)
+	}
+
+	fmt.Fprintf(uiOut(cmd), "✅ Rekeyed %s → %s (%s alphabet, %d rotors, %d plugboard pairs)\n",
+		configFile, outputFile, predefined.Name, machine.GetRotorCount(), machine.GetPlugboardPairCount())
+	return nil
+}
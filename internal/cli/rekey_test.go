@@ -0,0 +1,70 @@
+// Package cli provides unit tests for config --rekey.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRekeyKeepsMetadataAndShape(t *testing.T) {
+	fsys := NewMemFS()
+	if _, err := runCLI(fsys, "keygen", "--alphabet", "latin", "--security", "high", "--expires-in", "30d", "--output", "old.json"); err != nil {
+		t.Fatalf("keygen failed: %v", err)
+	}
+	old, err := createMachineFromConfig(fsys, configOptions{}, "old.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta := old.GetMetadata()
+	meta.Description = "team key"
+	meta.Tags = []string{"ops"}
+	old.SetMetadata(meta)
+	data, _ := old.SaveSettingsToJSON()
+	if err := writeStringToFile(fsys, data, "old.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := runCLI(fsys, "config", "--rekey", "old.json", "--to-alphabet", "alphanumeric", "--keep-shape", "--output", "new.json"); err != nil {
+		t.Fatalf("rekey failed: %v", err)
+	}
+	rekeyed, err := createMachineFromConfig(fsys, configOptions{}, "new.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rekeyed.GetAlphabetSize() != 62 {
+		t.Errorf("alphabet size = %d, want 62", rekeyed.GetAlphabetSize())
+	}
+	if rekeyed.GetRotorCount() != old.GetRotorCount() || rekeyed.GetPlugboardPairCount() != old.GetPlugboardPairCount() {
+		t.Errorf("shape = %d rotors/%d pairs, want %d/%d", rekeyed.GetRotorCount(), rekeyed.GetPlugboardPairCount(),
+			old.GetRotorCount(), old.GetPlugboardPairCount())
+	}
+	got := rekeyed.GetMetadata()
+	if got.Description != "team key" || len(got.Tags) != 1 || got.Tags[0] != "ops" {
+		t.Errorf("metadata not kept: %+v", got)
+	}
+	if got.ExpiresAt != "" || got.CreatedBy != "enigoma config --rekey" {
+		t.Errorf("creation record not renewed: %+v", got)
+	}
+}
+
+func TestRekeyInvalidArguments(t *testing.T) {
+	fsys := NewMemFS()
+	if _, err := runCLI(fsys, "keygen", "--output", "old.json"); err != nil {
+		t.Fatalf("keygen failed: %v", err)
+	}
+	if _, err := runCLI(fsys, "config", "--rekey", "old.json", "--output", "new.json"); err == nil {
+		t.Error("expected an error without --to-alphabet")
+	}
+	if _, err := runCLI(fsys, "config", "--rekey", "old.json", "--to-alphabet", "alphanumeric"); err == nil {
+		t.Error("expected an error without --output")
+	}
+	if _, err := runCLI(fsys, "config", "--rekey", "old.json", "--to-alphabet", "klingon", "--output", "new.json"); err == nil {
+		t.Error("expected an error for an unknown alphabet")
+	}
+	if _, err := runCLI(fsys, "config", "--rekey", "old.json", "--to-alphabet", "ascii", "--output", "new.json"); err == nil || !strings.Contains(err.Error(), "odd") {
+		t.Errorf("expected an odd-alphabet error, got %v", err)
+	}
+}
@@ -6,15 +6,30 @@ package cli
 
 import (
 	"fmt"
+	"io"
 
 	"github.com/coredds/enigoma"
+	"github.com/coredds/enigoma/pkg/enigma"
 	"github.com/spf13/cobra"
 )
 
-var rootCmd = &cobra.Command{
-	Use:   "enigoma",
-	Short: "A highly customizable, Unicode-capable Enigma machine implementation",
-	Long: `enigoma is a Go library and CLI tool that simulates the famous Enigma machine 
+// Options configures the IO of a command tree created by NewRootCommand.
+// Nil fields fall back to the process's standard streams and the OS filesystem.
+type Options struct {
+	In  io.Reader
+	Out io.Writer
+	Err io.Writer
+	FS  FS // Filesystem for configurations, inputs and outputs
+}
+
+// NewRootCommand returns a fully wired enigoma command tree. Every call builds
+// fresh commands, so several trees can coexist (for example when embedding
+// enigoma as a subcommand of another tool, or in tests) without sharing state.
+func NewRootCommand(opts Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "enigoma",
+		Short: "A highly customizable, Unicode-capable Enigma machine implementation",
+		Long: `enigoma is a Go library and CLI tool that simulates the famous Enigma machine 
 used during World War II, with modern enhancements including Unicode support,
 configurable complexity, and modular design.
 
@@ -23,35 +38,100 @@ Examples:
   enigoma decrypt --file encrypted.txt --config my-enigma.json
   enigoma keygen --security high --alphabet latin --output my-key.json
   enigoma preset --list`,
-	Version: enigoma.GetVersion(),
-}
+		Version: enigoma.GetVersion(),
+	}
 
-// Execute runs the root command and handles errors.
-func Execute() error {
-	return rootCmd.Execute()
-}
+	if opts.In != nil {
+		cmd.SetIn(opts.In)
+	}
+	if opts.Out != nil {
+		cmd.SetOut(opts.Out)
+	}
+	if opts.Err != nil {
+		cmd.SetErr(opts.Err)
+	}
+	// Runs before every subcommand, including when this tree is embedded
+	// under another tool's root
+	fsys := opts.FS
+	cmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if fsys != nil {
+			withFileSystem(cmd, fsys)
+		}
+		if err := applyKeyFlag(cmd); err != nil {
+			return err
+		}
+		return validateColorMode(cmd)
+	}
 
-func init() {
 	// Add subcommands
-	rootCmd.AddCommand(encryptCmd)
-	rootCmd.AddCommand(decryptCmd)
-	rootCmd.AddCommand(keygenCmd)
-	rootCmd.AddCommand(presetCmd)
-	rootCmd.AddCommand(configCmd)
-	rootCmd.AddCommand(wizardCmd)
-	rootCmd.AddCommand(demoCmd)
-	rootCmd.AddCommand(examplesCmd)
-	rootCmd.AddCommand(testCmd)
+	cmd.AddCommand(newEncryptCommand())
+	cmd.AddCommand(newDecryptCommand())
+	cmd.AddCommand(newKeygenCommand())
+	cmd.AddCommand(newPresetCommand())
+	cmd.AddCommand(newConfigCommand())
+	cmd.AddCommand(newWizardCommand())
+	cmd.AddCommand(newDemoCommand())
+	cmd.AddCommand(newExamplesCommand())
+	cmd.AddCommand(newTestCommand())
+	cmd.AddCommand(newKeyringCommand())
+	cmd.AddCommand(newKeyCommand())
+	cmd.AddCommand(newAlphabetCommand())
+	cmd.AddCommand(newCeremonyCommand())
+	cmd.AddCommand(newChatCommand())
+	cmd.AddCommand(newCompareCommand())
+	cmd.AddCommand(newSuggestCommand())
+	cmd.AddCommand(newBenchCommand())
+	cmd.AddCommand(newTestVectorsCommand())
+	cmd.AddCommand(newRandomTextCommand())
+	cmd.AddCommand(newRecryptCommand())
+	cmd.AddCommand(newKeysheetCommand())
+	cmd.AddCommand(newTraceCommand())
+	cmd.AddCommand(newDaemonCommand())
+	cmd.AddCommand(newClientCommand())
+	cmd.AddCommand(newServeCommand())
 
 	// Global flags
-	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Enable verbose output")
-	rootCmd.PersistentFlags().StringP("config", "c", "", "Configuration file path")
+	cmd.PersistentFlags().BoolP("verbose", "v", false, "Enable verbose output")
+	cmd.PersistentFlags().StringP("config", "c", "", "Configuration file path")
+	cmd.PersistentFlags().String("config-password", "", "Password of encrypted configuration files; new configurations are saved encrypted (or set "+configPasswordEnv+")")
+	cmd.PersistentFlags().String("signing-key", "", "Key file that signs configurations (config --sign) and verifies them on load (or set "+signingKeyEnv+")")
+	cmd.PersistentFlags().Bool("require-signed", false, "Refuse configuration files without a valid signature")
+	cmd.PersistentFlags().String("preset-dir", "", "Directory of custom presets, *.json configurations usable as --preset <name> (or set "+presetDirEnv+"; default ~/.config/enigoma/presets)")
+	cmd.PersistentFlags().String("key", "", "Key from the key directory, by name or fp:<fingerprint prefix> (see 'enigoma key list')")
+	cmd.PersistentFlags().Bool("no-truncate", false, "Show long text in full instead of a one-line preview")
+	cmd.PersistentFlags().String("warnings-format", "text", "How to print warnings on stderr (text, json, none)")
+	cmd.PersistentFlags().Bool("no-lock", false, "Don't lock key and session files while updating them")
+	cmd.PersistentFlags().String("color", "auto", "Use colors and emoji in messages (auto, always, never); auto honors NO_COLOR")
+
+	return cmd
+}
+
+// Execute runs the root command and handles errors.
+func Execute() error {
+	return NewRootCommand(Options{}).Execute()
 }
 
 // setupVerbose configures verbose logging if enabled.
 func setupVerbose(cmd *cobra.Command) {
 	verbose, _ := cmd.Flags().GetBool("verbose")
 	if verbose {
-		fmt.Println("Verbose mode enabled")
+		fmt.Fprintln(cmd.OutOrStdout(), "Verbose mode enabled")
+	}
+}
+
+// verboseKeyInfo reports which key is about to be used, in verbose mode, so a
+// mismatch between the two ends of a conversation is easy to spot.
+func verboseKeyInfo(cmd *cobra.Command, machine *enigma.Enigma, fingerprint string) {
+	if verbose, _ := cmd.Flags().GetBool("verbose"); verbose {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Key fingerprint %s, alphabet of %d characters, %d rotors\n",
+			shortFingerprint(fingerprint), machine.GetAlphabetSize(), machine.GetRotorCount())
+	}
+}
+
+// keyLabel names the key in error messages, or returns "" when it is unknown.
+func keyLabel(fingerprint string) string {
+	if fingerprint == "" {
+		return ""
 	}
+	return " with key " + shortFingerprint(fingerprint)
 }
@@ -0,0 +1,58 @@
+// Package cli provides unit tests for the embeddable root command.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewRootCommandInjectedIO(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "key.json")
+
+	var encrypted bytes.Buffer
+	cmd := NewRootCommand(Options{In: strings.NewReader("HELLOWORLD"), Out: &encrypted, Err: &bytes.Buffer{}})
+	cmd.SetArgs([]string{"encrypt", "--auto-config", keyFile})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("encrypt from injected stdin failed: %v", err)
+	}
+	ciphertext := strings.TrimSuffix(encrypted.String(), "\n")
+	if ciphertext == "" || ciphertext == "HELLOWORLD" {
+		t.Fatalf("expected ciphertext on injected stdout, got %q", encrypted.String())
+	}
+
+	var decrypted bytes.Buffer
+	cmd = NewRootCommand(Options{In: strings.NewReader(ciphertext), Out: &decrypted, Err: &bytes.Buffer{}})
+	cmd.SetArgs([]string{"decrypt", "--config", keyFile})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("decrypt from injected stdin failed: %v", err)
+	}
+	if got := strings.TrimSuffix(decrypted.String(), "\n"); got != "HELLOWORLD" {
+		t.Errorf("round trip through injected IO = %q, want HELLOWORLD", got)
+	}
+}
+
+func TestNewRootCommandIndependentTrees(t *testing.T) {
+	first := NewRootCommand(Options{Out: &bytes.Buffer{}})
+	first.SetArgs([]string{"encrypt", "--text", "HELLO", "--preset", "classic", "--format", "hex"})
+	if err := first.Execute(); err != nil {
+		t.Fatalf("first execution failed: %v", err)
+	}
+
+	// Flags parsed by one tree must not leak into another.
+	second := NewRootCommand(Options{})
+	encrypt, _, err := second.Find([]string{"encrypt"})
+	if err != nil {
+		t.Fatalf("encrypt command not found: %v", err)
+	}
+	if format, _ := encrypt.Flags().GetString("format"); format != "text" {
+		t.Errorf("fresh tree has format %q, want default text", format)
+	}
+	if text, _ := encrypt.Flags().GetString("text"); text != "" {
+		t.Errorf("fresh tree has text %q, want empty", text)
+	}
+}
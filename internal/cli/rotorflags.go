@@ -0,0 +1,154 @@
+// Package cli provides per-rotor position and ring flags addressed by rotor ID.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/coredds/enigoma/pkg/enigma"
+	"github.com/spf13/cobra"
+)
+
+// addRotorOverrideFlags registers --set-position and --set-ring, which name
+// rotors by ID instead of relying on their order.
+func addRotorOverrideFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("set-position", nil, "Set one rotor's position by rotor ID, e.g. I=Q or III=5 (repeatable)")
+	cmd.Flags().StringSlice("set-ring", nil, "Set one rotor's ring setting by rotor ID, numbered from 1, e.g. III=5 or II=B (repeatable)")
+}
+
+// hasRotorOverrides reports whether --set-position or --set-ring was given.
+func hasRotorOverrides(cmd *cobra.Command) bool {
+	positions, _ := cmd.Flags().GetStringSlice("set-position")
+	rings, _ := cmd.Flags().GetStringSlice("set-ring")
+	return len(positions) > 0 || len(rings) > 0
+}
+
+// validateRotorOverrides rejects --set-position and --set-ring on an encrypt
+// run that saves its key: the saved key would not match the ciphertext. Use
+// config --edit to change a saved key.
+func validateRotorOverrides(cmd *cobra.Command) error {
+	if !hasRotorOverrides(cmd) {
+		return nil
+	}
+	for _, flag := range []string{"auto-config", "save-config"} {
+		if path, _ := cmd.Flags().GetString(flag); path != "" {
+			return fmt.Errorf("--set-position and --set-ring cannot be combined with --%s; edit the saved key with 'enigoma config --edit %s'", flag, path)
+		}
+	}
+	return nil
+}
+
+// applyRotorOverrides applies --set-ring and then --set-position to the
+// rotors they name, leaving the other rotors as they are. Values are read in
+// the --notation of the command.
+func applyRotorOverrides(cmd *cobra.Command, machine *enigma.Enigma) error {
+	if !hasRotorOverrides(cmd) {
+		return nil
+	}
+	n, err := getNotationFromFlag(cmd)
+	if err != nil {
+		return err
+	}
+	settings, err := machine.GetSettings()
+	if err != nil {
+		return fmt.Errorf("failed to read machine settings: %v", err)
+	}
+	ids := make([]string, len(settings.RotorSpecs))
+	for i, spec := range settings.RotorSpecs {
+		ids[i] = spec.ID
+	}
+
+	ringValues, _ := cmd.Flags().GetStringSlice("set-ring")
+	if len(ringValues) > 0 {
+		rings := machine.GetRingSettings()
+		err := forEachRotorOverride(ringValues, ids, func(i int, value string) error {
+			parsed, err := n.parseRingSettings([]string{value}, settings.Alphabet)
+			if err != nil {
+				return err
+			}
+			if len(parsed) != 1 {
+				return fmt.Errorf("expected one ring setting, got '%s'", value)
+			}
+			rings[i] = parsed[0]
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("invalid --set-ring: %v", err)
+		}
+		if err := machine.SetRingSettings(rings); err != nil {
+			return fmt.Errorf("failed to set ring settings: %v", err)
+		}
+	}
+
+	positionValues, _ := cmd.Flags().GetStringSlice("set-position")
+	if len(positionValues) > 0 {
+		positions := machine.GetCurrentRotorPositions()
+		err := forEachRotorOverride(positionValues, ids, func(i int, value string) error {
+			parsed, err := n.parsePositions([]string{value}, settings.Alphabet)
+			if err != nil {
+				return err
+			}
+			if len(parsed) != 1 {
+				return fmt.Errorf("expected one position, got '%s'", value)
+			}
+			positions[i] = parsed[0]
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("invalid --set-position: %v", err)
+		}
+		if err := machine.SetRotorPositions(positions); err != nil {
+			return fmt.Errorf("failed to set rotor positions: %v", err)
+		}
+	}
+	return nil
+}
+
+// forEachRotorOverride splits each ID=value assignment, resolves the ID
+// against the machine's rotor IDs and calls set with the rotor's index.
+func forEachRotorOverride(assignments, ids []string, set func(i int, value string) error) error {
+	seen := make(map[int]bool)
+	for _, assignment := range assignments {
+		id, value, ok := strings.Cut(assignment, "=")
+		id, value = strings.TrimSpace(id), strings.TrimSpace(value)
+		if !ok || id == "" || value == "" {
+			return fmt.Errorf("%q is not ID=value", assignment)
+		}
+		i, err := rotorIndexByID(ids, id)
+		if err != nil {
+			return err
+		}
+		if seen[i] {
+			return fmt.Errorf("rotor %s is set twice", id)
+		}
+		seen[i] = true
+		if err := set(i, value); err != nil {
+			return fmt.Errorf("rotor %s: %v", id, err)
+		}
+	}
+	return nil
+}
+
+// rotorIndexByID returns the index of the rotor with the given ID. An exact
+// match wins; otherwise the ID is matched ignoring case. IDs shared by
+// several rotors cannot be addressed.
+func rotorIndexByID(ids []string, id string) (int, error) {
+	for _, fold := range []bool{false, true} {
+		match := -1
+		for i, candidate := range ids {
+			if candidate == id || (fold && strings.EqualFold(candidate, id)) {
+				if match >= 0 {
+					return 0, fmt.Errorf("rotor ID %s is used by more than one rotor (%s)", id, strings.Join(ids, ", "))
+				}
+				match = i
+			}
+		}
+		if match >= 0 {
+			return match, nil
+		}
+	}
+	return 0, fmt.Errorf("no rotor with ID %s; this key's rotors are %s", id, strings.Join(ids, ", "))
+}
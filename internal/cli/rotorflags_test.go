@@ -0,0 +1,103 @@
+// Package cli provides unit tests for the per-rotor flags.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/coredds/enigoma/pkg/enigma"
+)
+
+func TestRotorIndexByID(t *testing.T) {
+	ids := []string{"I", "II", "III", "beta"}
+	for id, want := range map[string]int{"I": 0, "III": 2, "Beta": 3, "BETA": 3} {
+		if got, err := rotorIndexByID(ids, id); err != nil || got != want {
+			t.Errorf("rotorIndexByID(%q) = %d, %v; want %d", id, got, err, want)
+		}
+	}
+	if _, err := rotorIndexByID(ids, "IV"); err == nil || !strings.Contains(err.Error(), "I, II, III, beta") {
+		t.Errorf("expected the key's rotor IDs in the error, got %v", err)
+	}
+	if _, err := rotorIndexByID([]string{"X", "x", "X"}, "X"); err == nil {
+		t.Error("expected an error for an ID shared by two rotors")
+	}
+	// An exact match is not ambiguous with a case-folded one
+	if got, err := rotorIndexByID([]string{"a", "A"}, "A"); err != nil || got != 1 {
+		t.Errorf("rotorIndexByID(A) = %d, %v; want 1", got, err)
+	}
+}
+
+func TestEncryptSetRotorByID(t *testing.T) {
+	want, _ := enigma.NewEnigmaM3()
+	want.SetRingSettings([]int{0, 0, 4})
+	want.SetRotorPositions([]int{16, 0, 0})
+	ciphertext, err := want.Encrypt("HELLOWORLD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fsys := NewMemFS()
+	got, err := runCLI(fsys, "encrypt", "--preset", "m3", "--text", "HELLOWORLD", "--set-position", "I=Q", "--set-ring", "III=5")
+	if err != nil || got != ciphertext {
+		t.Errorf("encrypt = %q, %v; want %q", got, err, ciphertext)
+	}
+
+	for _, args := range [][]string{
+		{"--set-position", "IV=A"},
+		{"--set-position", "I=Q,I=R"},
+		{"--set-ring", "II"},
+	} {
+		args = append([]string{"encrypt", "--preset", "m3", "--text", "HELLO"}, args...)
+		if _, err := runCLI(fsys, args...); err == nil {
+			t.Errorf("%v: expected an error", args)
+		}
+	}
+	if _, err := runCLI(fsys, "encrypt", "--preset", "m3", "--text", "HELLO", "--set-ring", "I=2", "--save-config", "k.json"); err == nil {
+		t.Error("expected --set-ring with --save-config to fail")
+	}
+}
+
+func TestConfigEditRotors(t *testing.T) {
+	fsys := NewMemFS()
+	if _, err := runCLI(fsys, "keygen", "--preset", "m3", "--output", "key.json"); err != nil {
+		t.Fatalf("keygen failed: %v", err)
+	}
+	before, err := createMachineFromConfig(fsys, configOptions{}, "key.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := before.GetRotorPositionsAsRunes()
+	want[1] = 'D'
+	rings := before.GetRingSettings()
+	rings[0] = 1
+
+	out, err := runCLI(fsys, "config", "--edit", "key.json", "--set-position", "ii=D", "--set-ring", "I=B", "--notation", "letters")
+	if err != nil {
+		t.Fatalf("config --edit failed: %v", err)
+	}
+	if !strings.Contains(out, "Rotor positions: "+string(want)) {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+
+	machine, err := createMachineFromConfig(fsys, configOptions{}, "key.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(machine.GetRotorPositionsAsRunes()); got != string(want) {
+		t.Errorf("saved positions = %s, want %s", got, string(want))
+	}
+	if got := machine.GetRingSettings(); fmt.Sprint(got) != fmt.Sprint(rings) {
+		t.Errorf("saved ring settings = %v, want %v", got, rings)
+	}
+	if backups, _ := listConfigBackups(fsys, "key.json"); len(backups) != 1 {
+		t.Errorf("expected one backup of the edited key, got %d", len(backups))
+	}
+
+	if _, err := runCLI(fsys, "config", "--edit", "key.json"); err == nil {
+		t.Error("expected an error when nothing is edited")
+	}
+}
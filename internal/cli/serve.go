@@ -0,0 +1,450 @@
+// Package cli provides the serve command for the enigoma CLI.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/coredds/enigoma"
+	"github.com/coredds/enigoma/pkg/enigma"
+	"github.com/spf13/cobra"
+)
+
+// serveShutdownTimeout bounds how long the server waits for requests in
+// flight when it is stopped.
+const serveShutdownTimeout = 5 * time.Second
+
+// serveTextRequest is the body of an encrypt or decrypt request. Key names a
+// loaded key like the daemon's requests do.
+type serveTextRequest struct {
+	Key                 string `json:"key,omitempty"`
+	Text                string `json:"text"`
+	KeepTrailingNewline bool   `json:"keep_trailing_newline,omitempty"`
+}
+
+// serveKeygenRequest is the body of a keygen request: a preset, or a
+// security level with an optional alphabet.
+type serveKeygenRequest struct {
+	Preset   string `json:"preset,omitempty"`
+	Security string `json:"security,omitempty"`
+	Alphabet string `json:"alphabet,omitempty"`
+}
+
+// serveResponse is the body of every answer. Config holds the settings of a
+// generated key in the format of 'enigoma keygen'.
+type serveResponse struct {
+	OK          bool            `json:"ok"`
+	Error       string          `json:"error,omitempty"`
+	Text        string          `json:"text,omitempty"`
+	Config      json.RawMessage `json:"config,omitempty"`
+	Fingerprint string          `json:"fingerprint,omitempty"`
+	Presets     []servePreset   `json:"presets,omitempty"`
+	Stats       *serveStats     `json:"stats,omitempty"`
+}
+
+// servePreset describes a preset in a presets response.
+type servePreset struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// apiServer answers the API requests. Its keyring is replaced as a whole on
+// reload, so a request sees either the old keys or the new ones.
+type apiServer struct {
+	keyring   atomic.Pointer[serveKeyring]
+	apiKey    string
+	poolSize  int
+	startedAt time.Time
+	reloads   atomic.Int64
+	inFlight  atomic.Int64
+}
+
+// newAPIServer warms the pools for keys. A non-empty apiKey is required on
+// every request.
+func newAPIServer(keys daemonKeys, apiKey string, poolSize int) (*apiServer, error) {
+	s := &apiServer{apiKey: apiKey, poolSize: poolSize, startedAt: now().UTC()}
+	ring, err := newServeKeyring(keys, poolSize)
+	if err != nil {
+		return nil, err
+	}
+	s.keyring.Store(ring)
+	return s, nil
+}
+
+// reload warms pools for keys and swaps them in. Requests in flight finish
+// on the keys they started with; a failed reload leaves the old keys in place.
+func (s *apiServer) reload(keys daemonKeys) error {
+	ring, err := newServeKeyring(keys, s.poolSize)
+	if err != nil {
+		return err
+	}
+	s.keyring.Store(ring)
+	s.reloads.Add(1)
+	return nil
+}
+
+// newServeCommand creates the serve command.
+func newServeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve encrypt, decrypt and keygen over an HTTP JSON API",
+		Long: `Load keys once and answer HTTP requests, so programs in any language can
+use the machine without shelling out.
+
+Keys are chosen as for 'enigoma daemon': with --config or --key the server
+holds that key, otherwise every key in the key directory. Every request
+starts from the key's own rotor positions, exactly like a separate
+'enigoma encrypt --config' run.
+
+Endpoints (request and response bodies are JSON):
+  POST /encrypt   {"key":"work","text":"HELLO"}          ->  {"ok":true,"text":"..."}
+  POST /decrypt   {"key":"work","text":"..."}            ->  {"ok":true,"text":"HELLO"}
+  POST /keygen    {"security":"high","alphabet":"latin"} ->  {"ok":true,"config":{...},"fingerprint":"..."}
+  GET  /presets                                          ->  {"ok":true,"presets":[...]}
+  GET  /stats                                            ->  {"ok":true,"stats":{...}}
+The key may be left out when the server holds a single key. Errors are
+answered with {"ok":false,"error":"..."} and a 4xx status.
+
+Every key has a pool of --pool-size machines built at startup, so requests
+don't wait for one; /stats shows how often the pools ran dry. On Unix,
+SIGHUP loads the keys again and swaps them in: requests already running
+finish on the old keys, and if the new keys fail to load the old ones stay.
+
+With --api-key-env every request must carry the key from that environment
+variable, as "Authorization: Bearer <key>" or "X-API-Key: <key>". The server
+speaks plain HTTP: put it behind a TLS proxy before exposing it beyond the
+local machine. Stop it with Ctrl+C or SIGTERM.
+
+Examples:
+  enigoma serve --config key.json
+  ENIGOMA_API_KEY=s3cret enigoma serve --addr :8080 --api-key-env ENIGOMA_API_KEY
+  curl -d '{"text":"HELLO"}' localhost:8080/encrypt
+  kill -HUP <pid>    # reload the keys`,
+		Args: cobra.NoArgs,
+		RunE: runServe,
+	}
+
+	cmd.Flags().String("addr", "localhost:8080", "Address to listen on (e.g. :8080 for all interfaces)")
+	cmd.Flags().String("api-key-env", "", "Require the API key in this environment variable on every request (e.g. ENIGOMA_API_KEY)")
+	cmd.Flags().Int("pool-size", 0, "Machines kept ready per key (default one per CPU)")
+
+	return cmd
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	var apiKey string
+	if envName, _ := cmd.Flags().GetString("api-key-env"); envName != "" {
+		if apiKey = os.Getenv(envName); apiKey == "" {
+			return fmt.Errorf("environment variable %s is empty or unset", envName)
+		}
+	}
+	poolSize, _ := cmd.Flags().GetInt("pool-size")
+	if poolSize < 0 {
+		return fmt.Errorf("--pool-size cannot be negative")
+	}
+	if poolSize == 0 {
+		poolSize = runtime.GOMAXPROCS(0)
+	}
+	keys, err := loadDaemonKeys(cmd)
+	if err != nil {
+		return err
+	}
+	server, err := newAPIServer(keys, apiKey, poolSize)
+	if err != nil {
+		return err
+	}
+
+	addr, _ := cmd.Flags().GetString("addr")
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintf(uiErr(cmd), "🌐 Serving %d key(s) on http://%s, %d machine(s) ready per key\n", len(keys), ln.Addr(), poolSize)
+	for _, k := range keys {
+		fmt.Fprintf(uiErr(cmd), "   %-20s %s\n", k.Name, shortFingerprint(k.Fingerprint))
+	}
+	if apiKey == "" {
+		fmt.Fprintln(uiErr(cmd), "⚠️  No --api-key-env: requests are not authenticated")
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	reloaded := make(chan struct{})
+	go func() {
+		defer close(reloaded)
+		reloadOnSignal(ctx, cmd, server, hup)
+	}()
+
+	err = serveHTTP(ctx, ln, server.handler())
+	stop()
+	<-reloaded
+	fmt.Fprintln(uiErr(cmd), "👋 Server stopped")
+	return err
+}
+
+// reloadOnSignal reloads the server's keys on every signal from hup until
+// ctx is done.
+func reloadOnSignal(ctx context.Context, cmd *cobra.Command, server *apiServer, hup <-chan os.Signal) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+		}
+		keys, err := loadDaemonKeys(cmd)
+		if err == nil {
+			err = server.reload(keys)
+		}
+		if err != nil {
+			fmt.Fprintf(uiErr(cmd), "❌ Reload failed, still serving the old keys: %v\n", err)
+			continue
+		}
+		fmt.Fprintf(uiErr(cmd), "🔄 Reloaded %d key(s)\n", len(keys))
+		for _, k := range keys {
+			fmt.Fprintf(uiErr(cmd), "   %-20s %s\n", k.Name, shortFingerprint(k.Fingerprint))
+		}
+	}
+}
+
+// serveHTTP answers requests on ln until ctx is done, then lets requests in
+// flight finish.
+func serveHTTP(ctx context.Context, ln net.Listener, handler http.Handler) error {
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	errc := make(chan error, 1)
+	go func() { errc <- server.Serve(ln) }()
+
+	select {
+	case err := <-errc:
+		return fmt.Errorf("server failed: %w", err)
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to stop the server: %w", err)
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("server failed: %w", err)
+	}
+	return nil
+}
+
+// handler routes the API endpoints.
+func (s *apiServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/encrypt", func(w http.ResponseWriter, r *http.Request) { s.serveText(w, r, "encrypt") })
+	mux.HandleFunc("/decrypt", func(w http.ResponseWriter, r *http.Request) { s.serveText(w, r, "decrypt") })
+	mux.HandleFunc("/keygen", serveKeygen)
+	mux.HandleFunc("/presets", servePresets)
+	mux.HandleFunc("/stats", s.serveStats)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeServeError(w, http.StatusNotFound, fmt.Sprintf("no endpoint %s (want /encrypt, /decrypt, /keygen, /presets or /stats)", r.URL.Path))
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.inFlight.Add(1)
+		defer s.inFlight.Add(-1)
+		w.Header().Set("Server", "enigoma/"+enigoma.GetVersion())
+		if s.apiKey != "" && !validAPIKey(r, s.apiKey) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeServeError(w, http.StatusUnauthorized, "missing or invalid API key")
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// validAPIKey reports whether r carries apiKey in its Authorization or
+// X-API-Key header, comparing in constant time.
+func validAPIKey(r *http.Request, apiKey string) bool {
+	given := r.Header.Get("X-API-Key")
+	if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		given = bearer
+	}
+	return subtle.ConstantTimeCompare([]byte(given), []byte(apiKey)) == 1
+}
+
+// serveText answers an encrypt or decrypt request on a machine from the
+// key's pool, which starts at the key's positions like the daemon's clones.
+func (s *apiServer) serveText(w http.ResponseWriter, r *http.Request, op string) {
+	var req serveTextRequest
+	if !readServeRequest(w, r, &req) {
+		return
+	}
+	ring := s.keyring.Load()
+	k, err := ring.keys.lookup(req.Key)
+	if err != nil {
+		writeServeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	pool := ring.pools[k]
+	machine, err := pool.get()
+	if err != nil {
+		writeServeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer pool.put(machine)
+	text, _ := fitTextToAlphabet(req.Text, k.alphabet, req.KeepTrailingNewline)
+	if op == "encrypt" {
+		text, err = machine.Encrypt(text)
+	} else {
+		text, err = machine.Decrypt(text)
+	}
+	if err != nil {
+		writeServeError(w, http.StatusBadRequest, fmt.Sprintf("%s failed: %v", op, err))
+		return
+	}
+	writeServeResponse(w, http.StatusOK, serveResponse{OK: true, Text: text})
+}
+
+// serveKeygen generates a key from a preset, or a security level and
+// alphabet, with random rotor positions as 'enigoma keygen' does.
+func serveKeygen(w http.ResponseWriter, r *http.Request) {
+	var req serveKeygenRequest
+	if !readServeRequest(w, r, &req) {
+		return
+	}
+	machine, err := keygenMachine(req)
+	if err != nil {
+		writeServeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := enigma.WithRandomRotorPositions()(machine); err != nil {
+		writeServeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to set random rotor positions: %v", err))
+		return
+	}
+	config, err := machine.SaveSettingsToJSON()
+	if err != nil {
+		writeServeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to save settings: %v", err))
+		return
+	}
+	fingerprint, err := machine.Fingerprint()
+	if err != nil {
+		writeServeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to fingerprint the key: %v", err))
+		return
+	}
+	writeServeResponse(w, http.StatusOK, serveResponse{OK: true, Config: json.RawMessage(config), Fingerprint: fingerprint})
+}
+
+// keygenMachine builds the machine a keygen request asks for.
+func keygenMachine(req serveKeygenRequest) (*enigma.Enigma, error) {
+	if req.Preset != "" {
+		if req.Security != "" || req.Alphabet != "" {
+			return nil, fmt.Errorf("preset cannot be combined with security or alphabet")
+		}
+		return createMachineFromPreset(req.Preset)
+	}
+
+	security := req.Security
+	if security == "" {
+		security = "medium"
+	}
+	level, err := parseSecurityLevel(security)
+	if err != nil {
+		return nil, err
+	}
+	alphabetName := req.Alphabet
+	if alphabetName == "" {
+		alphabetName = "latin"
+	}
+	predefined, ok := enigoma.LookupAlphabet(alphabetName)
+	if !ok {
+		return nil, fmt.Errorf("unknown alphabet: %s. Available: %s", alphabetName, alphabetNameList(false))
+	}
+	opts := []enigma.Option{enigma.WithAlphabet(predefined.Runes)}
+	if !predefined.ReflectorCompatible() {
+		opts = append(opts, enigma.WithoutReflector())
+	}
+	machine, err := enigma.New(append(opts, enigma.WithRandomSettings(level))...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Enigma machine: %v", err)
+	}
+	return machine, nil
+}
+
+// servePresets lists the presets with their descriptions.
+func servePresets(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeServeError(w, http.StatusMethodNotAllowed, "use GET")
+		return
+	}
+	var presets []servePreset
+	for _, preset := range getAvailablePresets() {
+		presets = append(presets, servePreset{Name: preset.Name, Description: preset.Description})
+	}
+	for _, name := range registeredPresets() {
+		presets = append(presets, servePreset{Name: name, Description: "Added by this program"})
+	}
+	writeServeResponse(w, http.StatusOK, serveResponse{OK: true, Presets: presets})
+}
+
+// serveStats reports the pools of the keys being served.
+func (s *apiServer) serveStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeServeError(w, http.StatusMethodNotAllowed, "use GET")
+		return
+	}
+	ring := s.keyring.Load()
+	writeServeResponse(w, http.StatusOK, serveResponse{OK: true, Stats: &serveStats{
+		StartedAt: s.startedAt.Format(time.RFC3339),
+		LoadedAt:  ring.loadedAt.Format(time.RFC3339),
+		Reloads:   s.reloads.Load(),
+		InFlight:  s.inFlight.Load() - 1, // Not counting this request
+		PoolSize:  s.poolSize,
+		Keys:      ring.stats(),
+	}})
+}
+
+// readServeRequest decodes the JSON body of a POST request into v. It
+// answers the error itself and reports false when the request is unusable.
+func readServeRequest(w http.ResponseWriter, r *http.Request, v any) bool {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeServeError(w, http.StatusMethodNotAllowed, "use POST")
+		return false
+	}
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxDaemonRequest))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeServeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request exceeds %d bytes", maxDaemonRequest))
+		} else {
+			writeServeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request: %v", err))
+		}
+		return false
+	}
+	return true
+}
+
+func writeServeError(w http.ResponseWriter, status int, message string) {
+	writeServeResponse(w, status, serveResponse{Error: message})
+}
+
+func writeServeResponse(w http.ResponseWriter, status int, resp serveResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
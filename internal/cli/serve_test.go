@@ -0,0 +1,202 @@
+// Package cli provides unit tests for the serve command.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/coredds/enigoma/pkg/enigma"
+)
+
+func serveTestKeys(t *testing.T) daemonKeys {
+	t.Helper()
+	machine, err := enigma.NewFromPreset("m3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings, err := machine.GetSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return daemonKeys{{daemonKeyEntry: daemonKeyEntry{Name: "work"}, machine: machine, alphabet: settings.Alphabet}}
+}
+
+func serveTestServer(t *testing.T, keys daemonKeys, apiKey string) *apiServer {
+	t.Helper()
+	server, err := newAPIServer(keys, apiKey, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return server
+}
+
+func serveRequest(t *testing.T, handler http.Handler, method, path, body string, header map[string]string) (int, serveResponse) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	var resp serveResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("%s %s: invalid response %q: %v", method, path, rec.Body.String(), err)
+	}
+	return rec.Code, resp
+}
+
+func TestServeEncryptDecrypt(t *testing.T) {
+	keys := serveTestKeys(t)
+	handler := serveTestServer(t, keys, "").handler()
+
+	want, err := keys[0].machine.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cipher, err := want.Encrypt("HELLOWORLD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every request starts from the key's positions, including once the
+	// pool has run dry and machines are reused
+	for i := 0; i < 4; i++ {
+		code, resp := serveRequest(t, handler, http.MethodPost, "/encrypt", `{"key":"work","text":"HELLOWORLD"}`, nil)
+		if code != http.StatusOK || !resp.OK || resp.Text != cipher {
+			t.Fatalf("encrypt = %d %+v; want %q", code, resp, cipher)
+		}
+	}
+	code, resp := serveRequest(t, handler, http.MethodPost, "/decrypt", `{"text":"`+cipher+`\n"}`, nil)
+	if code != http.StatusOK || resp.Text != "HELLOWORLD" {
+		t.Errorf("decrypt = %d %+v", code, resp)
+	}
+
+	for _, tc := range []struct {
+		method, path, body string
+		status             int
+	}{
+		{http.MethodGet, "/encrypt", "", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/encrypt", `{"key":"home","text":"A"}`, http.StatusNotFound},
+		{http.MethodPost, "/encrypt", `{"text":"lowercase"}`, http.StatusBadRequest},
+		{http.MethodPost, "/encrypt", `{"txt":"A"}`, http.StatusBadRequest},
+		{http.MethodPost, "/encrypt", `not json`, http.StatusBadRequest},
+		{http.MethodGet, "/reload", "", http.StatusNotFound},
+	} {
+		code, resp := serveRequest(t, handler, tc.method, tc.path, tc.body, nil)
+		if code != tc.status || resp.OK || resp.Error == "" {
+			t.Errorf("%s %s %s = %d %+v; want %d with an error", tc.method, tc.path, tc.body, code, resp, tc.status)
+		}
+	}
+}
+
+func TestServeKeygenAndPresets(t *testing.T) {
+	handler := serveTestServer(t, serveTestKeys(t), "").handler()
+
+	code, resp := serveRequest(t, handler, http.MethodPost, "/keygen", `{"security":"low","alphabet":"greek"}`, nil)
+	if code != http.StatusOK || resp.Fingerprint == "" {
+		t.Fatalf("keygen = %d %+v", code, resp)
+	}
+	machine, err := enigma.NewFromJSON(string(resp.Config))
+	if err != nil {
+		t.Fatalf("generated config does not load: %v", err)
+	}
+	if fingerprint, _ := machine.Fingerprint(); fingerprint != resp.Fingerprint {
+		t.Errorf("fingerprint = %s; want %s", resp.Fingerprint, fingerprint)
+	}
+
+	if code, resp := serveRequest(t, handler, http.MethodPost, "/keygen", `{"preset":"m4"}`, nil); code != http.StatusOK || len(resp.Config) == 0 {
+		t.Errorf("keygen preset = %d %+v", code, resp)
+	}
+	for _, body := range []string{`{"security":"ultra"}`, `{"alphabet":"klingon"}`, `{"preset":"m4","security":"low"}`, `{"preset":"nope"}`} {
+		if code, _ := serveRequest(t, handler, http.MethodPost, "/keygen", body, nil); code != http.StatusBadRequest {
+			t.Errorf("keygen %s = %d; want 400", body, code)
+		}
+	}
+
+	code, resp = serveRequest(t, handler, http.MethodGet, "/presets", "", nil)
+	if code != http.StatusOK || len(resp.Presets) == 0 || resp.Presets[0].Name != "classic" {
+		t.Errorf("presets = %d %+v", code, resp)
+	}
+}
+
+func TestServeAPIKey(t *testing.T) {
+	handler := serveTestServer(t, serveTestKeys(t), "s3cret").handler()
+	for _, header := range []map[string]string{
+		nil,
+		{"X-API-Key": "wrong"},
+		{"Authorization": "Bearer wrong"},
+	} {
+		if code, _ := serveRequest(t, handler, http.MethodGet, "/presets", "", header); code != http.StatusUnauthorized {
+			t.Errorf("presets with %v = %d; want 401", header, code)
+		}
+	}
+	for _, header := range []map[string]string{
+		{"X-API-Key": "s3cret"},
+		{"Authorization": "Bearer s3cret"},
+	} {
+		if code, _ := serveRequest(t, handler, http.MethodGet, "/presets", "", header); code != http.StatusOK {
+			t.Errorf("presets with %v = %d; want 200", header, code)
+		}
+	}
+}
+
+func TestServeStatsAndReload(t *testing.T) {
+	server := serveTestServer(t, serveTestKeys(t), "")
+	handler := server.handler()
+	for i := 0; i < 3; i++ {
+		if code, resp := serveRequest(t, handler, http.MethodPost, "/encrypt", `{"text":"HELLO"}`, nil); code != http.StatusOK {
+			t.Fatalf("encrypt = %d %+v", code, resp)
+		}
+	}
+	code, resp := serveRequest(t, handler, http.MethodGet, "/stats", "", nil)
+	if code != http.StatusOK || resp.Stats == nil || len(resp.Stats.Keys) != 1 {
+		t.Fatalf("stats = %d %+v", code, resp)
+	}
+	// Machines go back to the pool, so sequential requests never run it dry
+	if got := resp.Stats.Keys[0]; got.Name != "work" || got.Idle != 2 || got.Hits != 3 || got.Misses != 0 {
+		t.Errorf("key stats = %+v; want 2 idle, 3 hits and no misses", got)
+	}
+	if resp.Stats.PoolSize != 2 || resp.Stats.Reloads != 0 || resp.Stats.InFlight != 0 {
+		t.Errorf("stats = %+v", resp.Stats)
+	}
+
+	// A request that started before the reload finishes on the old key
+	old := server.keyring.Load()
+	machine, err := old.pools[old.keys[0]].get()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	home := serveTestKeys(t)
+	home[0].Name = "home"
+	if err := server.reload(home); err != nil {
+		t.Fatal(err)
+	}
+	if code, _ := serveRequest(t, handler, http.MethodPost, "/encrypt", `{"key":"work","text":"HELLO"}`, nil); code != http.StatusNotFound {
+		t.Errorf("the old key should be gone after the reload, got %d", code)
+	}
+	if code, _ := serveRequest(t, handler, http.MethodPost, "/encrypt", `{"key":"home","text":"HELLO"}`, nil); code != http.StatusOK {
+		t.Errorf("the new key should be served after the reload, got %d", code)
+	}
+	if _, err := machine.Encrypt("HELLO"); err != nil {
+		t.Errorf("the old machine should still work: %v", err)
+	}
+	old.pools[old.keys[0]].put(machine)
+
+	_, resp = serveRequest(t, handler, http.MethodGet, "/stats", "", nil)
+	if resp.Stats.Reloads != 1 || resp.Stats.Keys[0].Name != "home" || resp.Stats.Keys[0].Hits != 1 {
+		t.Errorf("stats after the reload = %+v", resp.Stats)
+	}
+}
+
+func TestServeRequiresAPIKeyValue(t *testing.T) {
+	t.Setenv("ENIGOMA_TEST_API_KEY", "")
+	if _, err := runCLI(NewMemFS(), "serve", "--api-key-env", "ENIGOMA_TEST_API_KEY"); err == nil || !strings.Contains(err.Error(), "empty or unset") {
+		t.Errorf("expected an unset API key to be refused, got %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+// Package cli provides the machine pools and key reloading of the serve command.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/coredds/enigoma/pkg/enigma"
+)
+
+// machinePool keeps clones of a key's machine ready, so a request doesn't
+// wait for one to be built. A machine goes back into the pool rewound to the
+// key's starting state; when the pool runs dry, requests clone a new one.
+type machinePool struct {
+	key    *daemonKey
+	start  enigma.MachineState
+	idle   chan *enigma.Enigma
+	hits   atomic.Int64 // Requests served from the pool
+	misses atomic.Int64 // Requests that had to clone a machine
+}
+
+// newMachinePool clones size machines for key up front.
+func newMachinePool(key *daemonKey, size int) (*machinePool, error) {
+	p := &machinePool{key: key, start: key.machine.SaveState(), idle: make(chan *enigma.Enigma, size)}
+	for i := 0; i < size; i++ {
+		machine, err := key.machine.Clone()
+		if err != nil {
+			return nil, fmt.Errorf("failed to prepare key %s: %v", key.Name, err)
+		}
+		p.idle <- machine
+	}
+	return p, nil
+}
+
+// get returns a machine at the key's starting state.
+func (p *machinePool) get() (*enigma.Enigma, error) {
+	select {
+	case machine := <-p.idle:
+		p.hits.Add(1)
+		return machine, nil
+	default:
+		p.misses.Add(1)
+		machine, err := p.key.machine.Clone()
+		if err != nil {
+			return nil, fmt.Errorf("failed to prepare key %s: %v", p.key.Name, err)
+		}
+		return machine, nil
+	}
+}
+
+// put rewinds machine and returns it to the pool, or drops it when the pool
+// is full or the machine cannot be rewound.
+func (p *machinePool) put(machine *enigma.Enigma) {
+	if machine.RestoreState(p.start) != nil {
+		return
+	}
+	select {
+	case p.idle <- machine:
+	default:
+	}
+}
+
+// serveKeyring is one generation of the keys a server holds. A reload builds
+// a new keyring; requests already running keep the one they started with.
+type serveKeyring struct {
+	keys     daemonKeys
+	pools    map[*daemonKey]*machinePool
+	loadedAt time.Time
+}
+
+// newServeKeyring warms a pool of poolSize machines for every key.
+func newServeKeyring(keys daemonKeys, poolSize int) (*serveKeyring, error) {
+	ring := &serveKeyring{keys: keys, pools: make(map[*daemonKey]*machinePool, len(keys)), loadedAt: now().UTC()}
+	for _, k := range keys {
+		pool, err := newMachinePool(k, poolSize)
+		if err != nil {
+			return nil, err
+		}
+		ring.pools[k] = pool
+	}
+	return ring, nil
+}
+
+// serveStats is the body of a stats response.
+type serveStats struct {
+	StartedAt string          `json:"started_at"`
+	LoadedAt  string          `json:"loaded_at"` // When the keys were last loaded
+	Reloads   int64           `json:"reloads"`
+	InFlight  int64           `json:"in_flight"`
+	PoolSize  int             `json:"pool_size"`
+	Keys      []serveKeyStats `json:"keys"`
+}
+
+// serveKeyStats describes the pool of one key. Hits and misses count the
+// requests since the keys were last loaded.
+type serveKeyStats struct {
+	Name        string `json:"name"`
+	Fingerprint string `json:"fingerprint"`
+	Idle        int    `json:"idle"`
+	Hits        int64  `json:"hits"`
+	Misses      int64  `json:"misses"`
+}
+
+// stats reports the state of the keyring's pools.
+func (ring *serveKeyring) stats() []serveKeyStats {
+	stats := make([]serveKeyStats, len(ring.keys))
+	for i, k := range ring.keys {
+		pool := ring.pools[k]
+		stats[i] = serveKeyStats{
+			Name:        k.Name,
+			Fingerprint: k.Fingerprint,
+			Idle:        len(pool.idle),
+			Hits:        pool.hits.Load(),
+			Misses:      pool.misses.Load(),
+		}
+	}
+	return stats
+}
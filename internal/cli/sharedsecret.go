@@ -0,0 +1,131 @@
+// Package cli provides shared-secret daily keys for the enigoma CLI.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/coredds/enigoma"
+	"github.com/coredds/enigoma/pkg/enigma"
+	"github.com/spf13/cobra"
+)
+
+// addSharedSecretFlags registers the shared-secret and passphrase flags on
+// encrypt and decrypt.
+func addSharedSecretFlags(cmd *cobra.Command) {
+	cmd.Flags().String("shared-secret-env", "", "Derive the day's machine from the secret in this environment variable (e.g. ENIGOMA_SECRET)")
+	cmd.Flags().String("shared-secret-date", "", "UTC day for --shared-secret-env, as YYYY-MM-DD (default: today)")
+	cmd.Flags().String("passphrase", "", "Derive the machine from this passphrase (visible to other local users; prefer --passphrase-file)")
+	cmd.Flags().String("passphrase-file", "", "Derive the machine from the passphrase on the first line of this file")
+}
+
+// hasPassphraseFlag reports whether --passphrase or --passphrase-file was given.
+func hasPassphraseFlag(cmd *cobra.Command) bool {
+	file, _ := cmd.Flags().GetString("passphrase-file")
+	return cmd.Flags().Changed("passphrase") || file != ""
+}
+
+// createMachineFromPassphraseFlags derives the machine from --passphrase or
+// --passphrase-file. A passphrase file holds the passphrase on its first line;
+// the line ending is not part of it.
+func createMachineFromPassphraseFlags(cmd *cobra.Command) (*enigma.Enigma, error) {
+	for _, name := range []string{"preset", "shared-secret-env"} {
+		if value, _ := cmd.Flags().GetString(name); value != "" {
+			return nil, fmt.Errorf("--%s cannot be combined with a passphrase", name)
+		}
+	}
+
+	passphrase, _ := cmd.Flags().GetString("passphrase")
+	source := "--passphrase"
+	if file, _ := cmd.Flags().GetString("passphrase-file"); file != "" {
+		if cmd.Flags().Changed("passphrase") {
+			return nil, fmt.Errorf("--passphrase cannot be combined with --passphrase-file")
+		}
+		data, err := fileSystem(cmd).ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read passphrase file: %v", err)
+		}
+		passphrase, _, _ = strings.Cut(string(data), "\n")
+		passphrase = strings.TrimSuffix(passphrase, "\r")
+		source = "passphrase file " + file
+	}
+	if passphrase == "" {
+		return nil, fmt.Errorf("%s is empty", source)
+	}
+	return createMachineFromPassphrase(cmd, passphrase, source)
+}
+
+// createMachineFromSharedSecret derives the machine for a UTC day from a secret
+// held in an environment variable. Both parties must use the same --security
+// and --alphabet.
+func createMachineFromSharedSecret(cmd *cobra.Command, envName string) (*enigma.Enigma, error) {
+	secret := os.Getenv(envName)
+	if secret == "" {
+		return nil, fmt.Errorf("environment variable %s is empty or unset", envName)
+	}
+
+	day := now()
+	if dateFlag, _ := cmd.Flags().GetString("shared-secret-date"); dateFlag != "" {
+		parsed, err := time.Parse("2006-01-02", dateFlag)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --shared-secret-date %q: expected YYYY-MM-DD", dateFlag)
+		}
+		day = parsed
+	}
+
+	level, err := getSecurityLevelFromFlag(cmd)
+	if err != nil {
+		return nil, err
+	}
+	predefined, err := derivedKeyAlphabet(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	if v, _ := cmd.Flags().GetBool("verbose"); v {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Using shared-secret key for %s (alphabet %s)\n", day.UTC().Format("2006-01-02"), predefined.Name)
+	}
+	return enigma.NewFromSharedSecret(secret, day, level, enigma.WithAlphabet(predefined.Runes))
+}
+
+// createMachineFromPassphrase derives a machine from a passphrase read from
+// source. Both parties must use the same --security and --alphabet.
+func createMachineFromPassphrase(cmd *cobra.Command, passphrase, source string) (*enigma.Enigma, error) {
+	level, err := getSecurityLevelFromFlag(cmd)
+	if err != nil {
+		return nil, err
+	}
+	predefined, err := derivedKeyAlphabet(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	if v, _ := cmd.Flags().GetBool("verbose"); v {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Deriving the key from the %s (alphabet %s)\n", source, predefined.Name)
+	}
+	return enigma.NewFromPassphrase(passphrase, predefined.Runes, level)
+}
+
+// derivedKeyAlphabet returns the --alphabet (or --alphabet-file) of a
+// derived key. "auto" means the portuguese alphabet (letters, accents, space
+// and punctuation, and reflector-compatible), since detecting the alphabet
+// from the text would differ between plaintext and ciphertext.
+func derivedKeyAlphabet(cmd *cobra.Command) (enigoma.PredefinedAlphabet, error) {
+	if path, _ := cmd.Flags().GetString("alphabet-file"); path != "" {
+		return alphabetFromFile(cmd, path)
+	}
+	alphabetName, _ := cmd.Flags().GetString("alphabet")
+	if strings.EqualFold(alphabetName, "auto") {
+		alphabetName = "portuguese"
+	}
+	predefined, ok := enigoma.LookupAlphabet(alphabetName)
+	if !ok {
+		return predefined, fmt.Errorf("unknown alphabet: %s. Available: %s (see 'enigoma alphabet list')", alphabetName, alphabetNameList(false))
+	}
+	return predefined, nil
+}
@@ -0,0 +1,176 @@
+// Package cli provides unit tests for shared-secret daily keys.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSharedSecretRoundTrip(t *testing.T) {
+	t.Setenv("ENIGOMA_TEST_SECRET", "correct horse battery staple")
+	withClock(t, time.Date(2025, 3, 14, 21, 0, 0, 0, time.UTC))
+
+	var out bytes.Buffer
+	cmd := NewRootCommand(Options{FS: NewMemFS()})
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"encrypt", "--text", "Meet at noon!", "--shared-secret-env", "ENIGOMA_TEST_SECRET"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	ciphertext := strings.TrimSuffix(out.String(), "\n")
+	if ciphertext == "Meet at noon!" {
+		t.Fatal("text was not encrypted")
+	}
+
+	decrypt := func(extra ...string) string {
+		t.Helper()
+		var out bytes.Buffer
+		cmd := NewRootCommand(Options{FS: NewMemFS()})
+		cmd.SetOut(&out)
+		cmd.SetArgs(append([]string{"decrypt", "--text", ciphertext, "--shared-secret-env", "ENIGOMA_TEST_SECRET"}, extra...))
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("decrypt failed: %v", err)
+		}
+		return strings.TrimSuffix(out.String(), "\n")
+	}
+
+	if got := decrypt(); got != "Meet at noon!" {
+		t.Errorf("same-day decrypt = %q", got)
+	}
+	if got := decrypt("--shared-secret-date", "2025-03-14"); got != "Meet at noon!" {
+		t.Errorf("explicit-date decrypt = %q", got)
+	}
+	if got := decrypt("--shared-secret-date", "2025-03-15"); got == "Meet at noon!" {
+		t.Error("the next day's machine should not decrypt today's message")
+	}
+}
+
+func TestSharedSecretErrors(t *testing.T) {
+	t.Setenv("ENIGOMA_EMPTY_SECRET", "")
+	t.Setenv("ENIGOMA_TEST_SECRET", "s3cret")
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"unset variable", []string{"--shared-secret-env", "ENIGOMA_EMPTY_SECRET"}},
+		{"bad date", []string{"--shared-secret-env", "ENIGOMA_TEST_SECRET", "--shared-secret-date", "14/03/2025"}},
+		{"unknown alphabet", []string{"--shared-secret-env", "ENIGOMA_TEST_SECRET", "--alphabet", "klingon"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewRootCommand(Options{FS: NewMemFS()})
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+			cmd.SetArgs(append([]string{"encrypt", "--text", "HELLO"}, tt.args...))
+			if err := cmd.Execute(); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+// TestKeygenPassphrase tests that keygen --passphrase-env gives both parties
+// the same key.
+func TestKeygenPassphrase(t *testing.T) {
+	t.Setenv("ENIGOMA_TEST_PASSPHRASE", "correct horse battery staple")
+	fsys := NewMemFS()
+	for _, name := range []string{"alice.json", "bob.json"} {
+		if _, err := runCLI(fsys, "keygen", "--passphrase-env", "ENIGOMA_TEST_PASSPHRASE", "--security", "high", "--output", name); err != nil {
+			t.Fatalf("keygen --passphrase-env failed: %v", err)
+		}
+	}
+	alice, err := createMachineFromConfig(fsys, configOptions{}, "alice.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := createMachineFromConfig(fsys, configOptions{}, "bob.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	aliceFP, _ := alice.Fingerprint()
+	bobFP, _ := bob.Fingerprint()
+	if aliceFP != bobFP {
+		t.Error("the same passphrase should give the same key")
+	}
+
+	t.Setenv("ENIGOMA_TEST_PASSPHRASE", "a different passphrase")
+	if _, err := runCLI(fsys, "keygen", "--passphrase-env", "ENIGOMA_TEST_PASSPHRASE", "--security", "high", "--output", "eve.json"); err != nil {
+		t.Fatal(err)
+	}
+	eve, err := createMachineFromConfig(fsys, configOptions{}, "eve.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if eveFP, _ := eve.Fingerprint(); eveFP == aliceFP {
+		t.Error("a different passphrase should give a different key")
+	}
+
+	for _, args := range [][]string{
+		{"--preset", "m3"},
+		{"--rotors", "5"},
+		{"--seed", "42"},
+		{"--no-plugboard"},
+	} {
+		if _, err := runCLI(fsys, append([]string{"keygen", "--passphrase-env", "ENIGOMA_TEST_PASSPHRASE"}, args...)...); err == nil {
+			t.Errorf("--passphrase-env with %v should fail", args)
+		}
+	}
+	t.Setenv("ENIGOMA_TEST_PASSPHRASE", "")
+	if _, err := runCLI(fsys, "keygen", "--passphrase-env", "ENIGOMA_TEST_PASSPHRASE"); err == nil {
+		t.Error("an empty passphrase should fail")
+	}
+}
+
+// TestPassphraseRoundTrip tests that encrypt and decrypt derive the same
+// machine from --passphrase and --passphrase-file.
+func TestPassphraseRoundTrip(t *testing.T) {
+	fsys := NewMemFS()
+	if err := fsys.WriteFile("secret.txt", []byte("correct horse battery staple\r\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ciphertext, err := runCLI(fsys, "encrypt", "--text", "Meet at noon!", "--passphrase", "correct horse battery staple", "--security", "high")
+	if err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	ciphertext = strings.TrimSuffix(ciphertext, "\n")
+	if ciphertext == "Meet at noon!" {
+		t.Fatal("text was not encrypted")
+	}
+
+	plaintext, err := runCLI(fsys, "decrypt", "--text", ciphertext, "--passphrase-file", "secret.txt", "--security", "high")
+	if err != nil {
+		t.Fatalf("decrypt failed: %v", err)
+	}
+	if got := strings.TrimSuffix(plaintext, "\n"); got != "Meet at noon!" {
+		t.Errorf("decrypt = %q", got)
+	}
+	if other, err := runCLI(fsys, "decrypt", "--text", ciphertext, "--passphrase", "wrong", "--security", "high"); err == nil && strings.TrimSuffix(other, "\n") == "Meet at noon!" {
+		t.Error("a different passphrase should not decrypt the message")
+	}
+}
+
+func TestPassphraseErrors(t *testing.T) {
+	fsys := NewMemFS()
+	if err := fsys.WriteFile("empty.txt", []byte("\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"--passphrase", ""},
+		{"--passphrase-file", "empty.txt"},
+		{"--passphrase-file", "missing.txt"},
+		{"--passphrase", "a", "--passphrase-file", "empty.txt"},
+		{"--passphrase", "a", "--preset", "m3"},
+		{"--passphrase", "a", "--alphabet", "klingon"},
+	} {
+		if _, err := runCLI(fsys, append([]string{"encrypt", "--text", "HELLO"}, args...)...); err == nil {
+			t.Errorf("encrypt %v should fail", args)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// Package cli provides key sidecar files that let decrypt find the right configuration.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/coredds/enigoma/pkg/enigma"
+	"github.com/spf13/cobra"
+)
+
+// sidecarExtension is appended to a ciphertext file name to form its sidecar.
+const sidecarExtension = ".key"
+
+// keySidecar points from a ciphertext file to the configuration that produced it.
+type keySidecar struct {
+	Config      string `json:"config"`      // Path to the configuration, relative to the sidecar when possible
+	Fingerprint string `json:"fingerprint"` // Fingerprint of the configuration before encryption
+}
+
+// sidecarPath returns the sidecar file name for a ciphertext file.
+func sidecarPath(ciphertextFile string) string {
+	return ciphertextFile + sidecarExtension
+}
+
+// maybeWriteSidecar writes a sidecar next to the output file when encrypt
+// generated a configuration (--auto-config or --save-config).
+func maybeWriteSidecar(cmd *cobra.Command, fingerprint string) error {
+	outputFile, _ := cmd.Flags().GetString("output")
+	if outputFile == "" {
+		return nil
+	}
+
+	configFile, _ := cmd.Flags().GetString("auto-config")
+	if configFile == "" {
+		configFile, _ = cmd.Flags().GetString("save-config")
+	}
+	if configFile == "" {
+		return nil
+	}
+
+	return writeSidecar(fileSystem(cmd), outputFile, configFile, fingerprint)
+}
+
+func writeSidecar(fsys FS, ciphertextFile, configFile, fingerprint string) error {
+	config := configFile
+	if abs, err := filepath.Abs(configFile); err == nil {
+		if dir, err := filepath.Abs(filepath.Dir(ciphertextFile)); err == nil {
+			if rel, err := filepath.Rel(dir, abs); err == nil {
+				config = rel
+			}
+		}
+	}
+	// Forward slashes keep the sidecar usable on every OS
+	config = filepath.ToSlash(config)
+
+	data, err := json.MarshalIndent(keySidecar{Config: config, Fingerprint: fingerprint}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("serialize key sidecar: %w", err)
+	}
+	path := sidecarPath(ciphertextFile)
+	if err := writeStringToFile(fsys, string(data), path); err != nil {
+		return fmt.Errorf("write key sidecar %s: %w", path, err)
+	}
+	return nil
+}
+
+func readSidecar(fsys FS, path string) (*keySidecar, error) {
+	data, err := fsys.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var sidecar keySidecar
+	if err := json.Unmarshal(data, &sidecar); err != nil {
+		return nil, fmt.Errorf("invalid key sidecar %s: %w", path, err)
+	}
+	return &sidecar, nil
+}
+
+// createMachineForDecrypt builds the decryption machine. When neither --config
+// nor --preset is given, it first tries to locate the configuration for --file
+// through its sidecar or the container's key fingerprint.
+func createMachineForDecrypt(cmd *cobra.Command, container *enigma.Container, text string) (*enigma.Enigma, error) {
+	configFile, _ := cmd.Flags().GetString("config")
+	preset, _ := cmd.Flags().GetString("preset")
+	inputFile, _ := cmd.Flags().GetString("file")
+	sharedSecret, _ := cmd.Flags().GetString("shared-secret-env")
+
+	fsys, opts := fileSystem(cmd), configOptionsFor(cmd)
+	if configFile == "" && preset == "" && sharedSecret == "" && !hasPassphraseFlag(cmd) && inputFile != "" {
+		var want string
+		if container != nil {
+			want = container.KeyFingerprint
+		}
+		path, fingerprint, err := locateConfigForFile(fsys, opts, inputFile, want)
+		if err != nil {
+			return nil, err
+		}
+		if path != "" {
+			if err := rejectPlugboardFlagFor(cmd, "the configuration found for "+inputFile); err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(uiErr(cmd), "🔑 Using configuration %s (fingerprint %s)\n", path, shortFingerprint(fingerprint))
+			return createMachineFromConfig(fsys, opts, path)
+		}
+	}
+
+	return createMachineFromFlags(cmd, text)
+}
+
+// locateConfigForFile finds the configuration for a ciphertext file. The sidecar
+// is tried first; otherwise configurations in the same directory are matched by
+// fingerprint. An empty path means nothing matched.
+func locateConfigForFile(fsys FS, opts configOptions, ciphertextFile, want string) (string, string, error) {
+	if sidecar, err := readSidecar(fsys, sidecarPath(ciphertextFile)); err == nil {
+		if want == "" {
+			want = sidecar.Fingerprint
+		}
+		config := filepath.FromSlash(sidecar.Config)
+		if !filepath.IsAbs(config) {
+			config = filepath.Join(filepath.Dir(ciphertextFile), config)
+		}
+		if fp, err := fingerprintConfigFile(fsys, opts, config); err == nil && fp == want {
+			return config, fp, nil
+		}
+	} else if !os.IsNotExist(err) {
+		return "", "", err
+	}
+
+	if want == "" {
+		return "", "", nil
+	}
+
+	candidates, err := fsys.Glob(filepath.Join(filepath.Dir(ciphertextFile), "*.json"))
+	if err != nil {
+		return "", "", err
+	}
+	for _, candidate := range candidates {
+		if fp, err := fingerprintConfigFile(fsys, opts, candidate); err == nil && fp == want {
+			return candidate, fp, nil
+		}
+	}
+	return "", "", nil
+}
+
+// fingerprintConfigFile loads a configuration file and returns its fingerprint.
+func fingerprintConfigFile(fsys FS, opts configOptions, path string) (string, error) {
+	machine, err := createMachineFromConfig(fsys, opts, path)
+	if err != nil {
+		return "", err
+	}
+	return machine.Fingerprint()
+}
+
+// shortFingerprint abbreviates a fingerprint for display and file names.
+func shortFingerprint(fingerprint string) string {
+	if len(fingerprint) > 12 {
+		return fingerprint[:12]
+	}
+	return fingerprint
+}
@@ -0,0 +1,124 @@
+// Package cli provides unit tests for key sidecar lookup.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDecryptFindsConfigViaSidecar(t *testing.T) {
+	fsys := NewMemFS()
+	dir := "msgs"
+	if err := fsys.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	key := filepath.Join(dir, "my-key.json")
+	msg := filepath.Join(dir, "encrypted.txt")
+
+	cmd := NewRootCommand(Options{FS: fsys})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"encrypt", "--text", "Hello World!", "--auto-config", key, "--output", msg})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+
+	sidecar, err := readSidecar(fsys, sidecarPath(msg))
+	if err != nil {
+		t.Fatalf("expected sidecar next to ciphertext: %v", err)
+	}
+	if sidecar.Config != "my-key.json" {
+		t.Errorf("sidecar should reference the config relative to itself, got %q", sidecar.Config)
+	}
+
+	cmd = NewRootCommand(Options{FS: fsys})
+	var out, errOut bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	cmd.SetArgs([]string{"decrypt", "--file", msg})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("decrypt without --config failed: %v", err)
+	}
+	if out.String() != "Hello World!" {
+		t.Errorf("expected %q, got %q", "Hello World!", out.String())
+	}
+	if !strings.Contains(errOut.String(), "my-key.json") {
+		t.Errorf("decrypt should report which configuration it used, got %q", errOut.String())
+	}
+}
+
+func TestDecryptMatchesContainerFingerprint(t *testing.T) {
+	fsys := NewMemFS()
+	dir := "msgs"
+	if err := fsys.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	msg := filepath.Join(dir, "msg.enig")
+
+	// Several candidate keys in the same directory
+	var keys []string
+	for _, name := range []string{"a.json", "b.json", "c.json"} {
+		path := filepath.Join(dir, name)
+		cmd := NewRootCommand(Options{FS: fsys})
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetArgs([]string{"keygen", "--preset", "high", "--output", path})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("keygen failed: %v", err)
+		}
+		keys = append(keys, path)
+	}
+
+	cmd := NewRootCommand(Options{FS: fsys})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"encrypt", "--text", "RENDEZVOUS", "--config", keys[1], "--output", msg})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	if _, err := fsys.Stat(sidecarPath(msg)); !os.IsNotExist(err) {
+		t.Error("no sidecar should be written when using an existing --config")
+	}
+
+	cmd = NewRootCommand(Options{FS: fsys})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"decrypt", "--file", msg})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("decrypt failed: %v", err)
+	}
+	if out.String() != "RENDEZVOUS" {
+		t.Errorf("expected RENDEZVOUS, got %q", out.String())
+	}
+}
+
+func TestSidecarPathInOtherDirectory(t *testing.T) {
+	fsys := NewMemFS()
+	for _, dir := range []string{"keys", "msgs"} {
+		if err := fsys.MkdirAll(dir, 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	key := filepath.Join("keys", "team.json")
+	msg := filepath.Join("msgs", "note.txt")
+	if _, err := runCLI(fsys, "encrypt", "--text", "Hello World!", "--auto-config", key, "--output", msg); err != nil {
+		t.Fatal(err)
+	}
+
+	// Written with forward slashes on every OS, so the pair can be copied
+	// between Windows and Unix
+	sidecar, err := readSidecar(fsys, sidecarPath(msg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sidecar.Config != "../keys/team.json" {
+		t.Errorf("sidecar config = %q, want ../keys/team.json", sidecar.Config)
+	}
+	if out, err := runCLI(fsys, "decrypt", "--file", msg); err != nil || out != "Hello World!" {
+		t.Errorf("decrypt = %q, %v", out, err)
+	}
+}
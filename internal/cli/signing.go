@@ -0,0 +1,177 @@
+// Package cli provides signed configuration files for the enigoma CLI.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/coredds/enigoma/pkg/enigma"
+	"github.com/spf13/cobra"
+)
+
+// signingKeyEnv names the signing key file when --signing-key is not given.
+const signingKeyEnv = "ENIGOMA_SIGNING_KEY"
+
+// warnSignatureRemoved flags a signed configuration rewritten without its signature.
+const warnSignatureRemoved enigma.WarningCode = "signature_removed"
+
+// readSigningKey reads the signing key named by --signing-key or
+// ENIGOMA_SIGNING_KEY, or returns nil when neither is set.
+func readSigningKey(fsys FS, opts configOptions) (*enigma.SigningKey, error) {
+	if opts.signingKey == "" {
+		return nil, nil
+	}
+	data, err := fsys.ReadFile(opts.signingKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key: %v", err)
+	}
+	key, err := enigma.ParseSigningKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing key %s: %v", opts.signingKey, err)
+	}
+	return key, nil
+}
+
+// verifyConfigSignature checks the signature of a freshly loaded machine.
+// Signed configurations are always checked when they can be: Ed25519
+// signatures against the signing key or, without one, the public key they
+// carry, and HMAC signatures when the key is given. With --require-signed,
+// unsigned and unverifiable configurations are rejected.
+func verifyConfigSignature(fsys FS, opts configOptions, machine *enigma.Enigma) error {
+	if !machine.IsSigned() {
+		if opts.requireSigned {
+			return fmt.Errorf("the configuration is not signed (--require-signed); sign it with 'enigoma config --sign'")
+		}
+		return nil
+	}
+	key, err := readSigningKey(fsys, opts)
+	if err != nil {
+		return err
+	}
+	sig := machine.GetMetadata().Signature
+	if key == nil && sig.Algorithm == enigma.SignatureHMACSHA256 && !opts.requireSigned {
+		return nil // Nothing to check an HMAC with
+	}
+	if err := machine.VerifySignature(key); err != nil {
+		if key == nil && !errors.Is(err, enigma.ErrBadSignature) {
+			return fmt.Errorf("cannot verify the signature: %v; pass --signing-key or set %s", err, signingKeyEnv)
+		}
+		return fmt.Errorf("signature check failed: %v", err)
+	}
+	return nil
+}
+
+// describeSignature summarizes a machine's signature for config --show and
+// --validate, checking it with the signing key when one is given.
+func describeSignature(fsys FS, opts configOptions, machine *enigma.Enigma) string {
+	if !machine.IsSigned() {
+		return "none"
+	}
+	sig := machine.GetMetadata().Signature
+	desc := fmt.Sprintf("%s, key %s", sig.Algorithm, sig.KeyID)
+	key, err := readSigningKey(fsys, opts)
+	if err != nil {
+		return desc + " (" + err.Error() + ")"
+	}
+	switch err := machine.VerifySignature(key); {
+	case err == nil && key != nil:
+		return desc + " (verified with the signing key)"
+	case err == nil:
+		return desc + " (intact; pass --signing-key to check who signed it)"
+	case key == nil && !errors.Is(err, enigma.ErrBadSignature):
+		return desc + " (not verified; pass --signing-key)"
+	default:
+		return desc + " (INVALID: " + err.Error() + ")"
+	}
+}
+
+// signConfig signs a configuration file with --signing-key, rewriting it
+// (after a backup) or writing the result to --output.
+func signConfig(configFile string, cmd *cobra.Command) error {
+	fsys, opts := fileSystem(cmd), configOptionsFor(cmd)
+	key, err := readSigningKey(fsys, opts)
+	if err != nil {
+		return err
+	}
+	if key == nil {
+		return fmt.Errorf("signing needs a key: pass --signing-key or set %s (create one with --gen-signing-key)", signingKeyEnv)
+	}
+	outputFile, _ := cmd.Flags().GetString("output")
+	if outputFile == "" {
+		outputFile = configFile
+	}
+
+	// Signing in place reads and rewrites the same file, so hold the lock throughout
+	err = withFileLock(fsys, outputFile, func() error {
+		// Check the old signature only against itself: re-signing replaces it
+		machine, err := createMachineFromConfig(fsys, configOptions{password: opts.password}, configFile)
+		if err != nil {
+			return fmt.Errorf("failed to read configuration: %v", err)
+		}
+		if err := machine.Sign(key); err != nil {
+			return fmt.Errorf("failed to sign configuration: %v", err)
+		}
+		jsonData, err := machine.SaveSettingsToJSON()
+		if err != nil {
+			return fmt.Errorf("failed to serialize configuration: %v", err)
+		}
+		if err := writeConfigFileLocked(fsys, opts, outputFile, jsonData); err != nil {
+			return fmt.Errorf("failed to write signed configuration: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Signature: %s, key %s\n", key.Algorithm(), key.ID())
+	fmt.Fprintf(uiOut(cmd), "✅ Configuration signed: %s\n", outputFile)
+	return nil
+}
+
+// generateSigningKey writes a new Ed25519 signing key to keyFile and its
+// public key to keyFile.pub, for verifiers that must not be able to sign.
+func generateSigningKey(keyFile string, cmd *cobra.Command) error {
+	fsys := fileSystem(cmd)
+	if _, err := fsys.Stat(keyFile); err == nil {
+		return fmt.Errorf("%s already exists; refusing to overwrite a signing key", keyFile)
+	}
+	key, err := enigma.GenerateEd25519SigningKey()
+	if err != nil {
+		return err
+	}
+	private, err := key.MarshalPrivatePEM()
+	if err != nil {
+		return err
+	}
+	public, err := key.MarshalPublicPEM()
+	if err != nil {
+		return err
+	}
+	if err := fsys.WriteFile(keyFile, private, 0600); err != nil {
+		return fmt.Errorf("failed to write signing key: %v", err)
+	}
+	if err := fsys.WriteFile(keyFile+".pub", public, 0644); err != nil {
+		return fmt.Errorf("failed to write public key: %v", err)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Key ID: %s\n", key.ID())
+	fmt.Fprintf(uiOut(cmd), "✅ Signing key saved to %s, public key to %s.pub\n", keyFile, keyFile)
+	return nil
+}
+
+// dropStaleSignature removes the signature of a machine that was changed
+// before saving, and warns that the result needs signing again.
+func dropStaleSignature(cmd *cobra.Command, machine *enigma.Enigma) error {
+	if !machine.IsSigned() {
+		return nil
+	}
+	machine.RemoveSignature()
+	return reportWarnings(cmd, enigma.Warning{
+		Code:    warnSignatureRemoved,
+		Message: "the configuration changed, so its signature was removed; sign it again with 'enigoma config --sign'",
+	})
+}
@@ -0,0 +1,144 @@
+// Package cli provides unit tests for signed configuration files.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestConfigSignEd25519(t *testing.T) {
+	fsys := NewMemFS()
+	if _, err := runCLI(fsys, "keygen", "--preset", "m3", "--output", "key.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runCLI(fsys, "config", "--gen-signing-key", "signing.pem"); err != nil {
+		t.Fatalf("--gen-signing-key failed: %v", err)
+	}
+	if _, err := runCLI(fsys, "config", "--gen-signing-key", "signing.pem"); err == nil {
+		t.Error("--gen-signing-key should not overwrite a key")
+	}
+	if _, err := runCLI(fsys, "encrypt", "--config", "key.yaml", "--text", "HELLO", "--require-signed"); err == nil || !strings.Contains(err.Error(), "not signed") {
+		t.Errorf("--require-signed should reject an unsigned key, got %v", err)
+	}
+
+	out, err := runCLI(fsys, "config", "--sign", "key.yaml", "--signing-key", "signing.pem")
+	if err != nil {
+		t.Fatalf("config --sign failed: %v", err)
+	}
+	if !strings.Contains(out, "Signature: ed25519") {
+		t.Errorf("config --sign should name the algorithm:\n%s", out)
+	}
+	data, _ := fsys.ReadFile("key.yaml")
+	if sniffConfigFormat(data) != configYAML || !strings.Contains(string(data), "signature:") {
+		t.Errorf("signing should keep the YAML format and add the signature:\n%s", data)
+	}
+
+	// The public key verifies, and so does the key the file carries
+	for _, args := range [][]string{
+		{"encrypt", "--config", "key.yaml", "--text", "HELLO", "--require-signed", "--signing-key", "signing.pem.pub"},
+		{"encrypt", "--config", "key.yaml", "--text", "HELLO", "--require-signed"},
+	} {
+		if _, err := runCLI(fsys, args...); err != nil {
+			t.Errorf("%v failed: %v", args, err)
+		}
+	}
+	out, err = runCLI(fsys, "config", "--validate", "key.yaml", "--signing-key", "signing.pem.pub")
+	if err != nil || !strings.Contains(out, "verified with the signing key") {
+		t.Errorf("validate should report the verified signature, got %v:\n%s", err, out)
+	}
+
+	// A tampered file is rejected before any text is processed
+	tampered := strings.Replace(string(data), "ring_setting: 0", "ring_setting: 1", 1)
+	if tampered == string(data) {
+		t.Fatalf("no ring setting to tamper with:\n%s", data)
+	}
+	if err := fsys.WriteFile("key.yaml", []byte(tampered), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runCLI(fsys, "encrypt", "--config", "key.yaml", "--text", "HELLO"); err == nil || !strings.Contains(err.Error(), "signature") {
+		t.Errorf("a tampered signed key should be rejected, got %v", err)
+	}
+}
+
+func TestConfigSignHMAC(t *testing.T) {
+	fsys := NewMemFS()
+	if _, err := runCLI(fsys, "keygen", "--security", "low", "--output", "key.json"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.WriteFile("secret.key", []byte("correct horse battery staple\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.WriteFile("other.key", []byte("another shared secret value\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runCLI(fsys, "config", "--sign", "key.json", "--output", "signed.json", "--signing-key", "secret.key"); err != nil {
+		t.Fatalf("config --sign failed: %v", err)
+	}
+	var doc map[string]any
+	data, _ := fsys.ReadFile("signed.json")
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc["metadata"].(map[string]any)["signature"].(map[string]any)["algorithm"] != "hmac-sha256" {
+		t.Errorf("a raw secret should sign with HMAC:\n%s", data)
+	}
+
+	if _, err := runCLI(fsys, "encrypt", "--config", "signed.json", "--text", "HELLO", "--signing-key", "secret.key", "--require-signed"); err != nil {
+		t.Errorf("the right secret should verify: %v", err)
+	}
+	if _, err := runCLI(fsys, "encrypt", "--config", "signed.json", "--text", "HELLO", "--signing-key", "other.key"); err == nil {
+		t.Error("another secret should fail verification")
+	}
+	if _, err := runCLI(fsys, "encrypt", "--config", "signed.json", "--text", "HELLO", "--require-signed"); err == nil || !strings.Contains(err.Error(), "--signing-key") {
+		t.Errorf("--require-signed without the HMAC key should fail, got %v", err)
+	}
+	if _, err := runCLI(fsys, "encrypt", "--config", "signed.json", "--text", "HELLO"); err != nil {
+		t.Errorf("without a key or --require-signed an HMAC-signed key should load: %v", err)
+	}
+
+	// Editing drops the signature instead of leaving a stale one
+	if _, err := runCLI(fsys, "config", "--edit", "signed.json", "--set-position", "R1=B"); err != nil {
+		t.Fatalf("config --edit failed: %v", err)
+	}
+	data, _ = fsys.ReadFile("signed.json")
+	if strings.Contains(string(data), "signature") {
+		t.Errorf("editing should remove the signature:\n%s", data)
+	}
+	if _, err := runCLI(fsys, "config", "--sign", "key.json"); err == nil || !strings.Contains(err.Error(), "needs a key") {
+		t.Errorf("--sign without a key should fail, got %v", err)
+	}
+}
+
+func TestConfigOptionsSignatureCheck(t *testing.T) {
+	fsys := NewMemFS()
+	if _, err := runCLI(fsys, "keygen", "--preset", "m3", "--output", "key.json"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runCLI(fsys, "config", "--gen-signing-key", "old.pem"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := createMachineFromConfig(fsys, configOptions{requireSigned: true}, "key.json"); err == nil || !strings.Contains(err.Error(), "not signed") {
+		t.Errorf("requireSigned should reject an unsigned configuration, got %v", err)
+	}
+
+	// Re-signing with another key replaces the old signature
+	if _, err := runCLI(fsys, "config", "--sign", "key.json", "--signing-key", "old.pem"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runCLI(fsys, "config", "--gen-signing-key", "new.pem"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := createMachineFromConfig(fsys, configOptions{signingKey: "new.pem.pub"}, "key.json"); err == nil {
+		t.Fatal("a signature by another key should fail the check")
+	}
+	if _, err := runCLI(fsys, "config", "--sign", "key.json", "--signing-key", "new.pem"); err != nil {
+		t.Fatalf("re-signing failed: %v", err)
+	}
+	if _, err := createMachineFromConfig(fsys, configOptions{signingKey: "new.pem.pub", requireSigned: true}, "key.json"); err != nil {
+		t.Errorf("the new signature should verify: %v", err)
+	}
+}
@@ -0,0 +1,23 @@
+//go:build !windows
+
+// Package cli provides private Unix sockets for Unix-like systems.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"net"
+	"syscall"
+)
+
+// listenPrivateSocket listens on a Unix socket at path that only the user
+// can connect to. The socket is created under a umask that leaves it mode
+// 0600, so there is no moment when it is open to others; changing the mode
+// afterwards would leave one. The umask is process-wide, which is why the
+// daemon listens before it starts serving.
+func listenPrivateSocket(path string) (net.Listener, error) {
+	old := syscall.Umask(0177)
+	defer syscall.Umask(old)
+	return net.Listen("unix", path)
+}
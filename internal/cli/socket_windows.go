@@ -0,0 +1,16 @@
+//go:build windows
+
+// Package cli provides Unix sockets for Windows.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import "net"
+
+// listenPrivateSocket listens on a Unix socket at path. Windows has no umask
+// and ignores Unix modes on sockets; access follows the ACL of the directory
+// the socket is created in.
+func listenPrivateSocket(path string) (net.Listener, error) {
+	return net.Listen("unix", path)
+}
@@ -0,0 +1,219 @@
+// Package cli provides splitting of ciphertext into numbered parts for transmission.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// partHeader matches the line that opens each part, e.g. "(2/5)", or
+// "(2/5 9f86d081884c7d65)" when the parts are chained.
+var partHeader = regexp.MustCompile(`(?m)^\((\d+)/(\d+)(?: ([0-9a-f]{16}))?\)\n`)
+
+// splitParts cuts text into parts of at most size characters, each opened by
+// a "(i/n)" header line and closed by a newline. With chain, each header also
+// carries a link of the hash chain.
+func splitParts(text string, size int, chain bool) []string {
+	runes := []rune(text)
+	total := (len(runes) + size - 1) / size
+	if total == 0 {
+		total = 1
+	}
+	parts := make([]string, total)
+	link := chainSeed(total)
+	for i := range parts {
+		end := min((i+1)*size, len(runes))
+		body := string(runes[i*size : end])
+		if chain {
+			link = chainLink(link, body)
+			parts[i] = fmt.Sprintf("(%d/%d %s)\n%s\n", i+1, total, link, body)
+		} else {
+			parts[i] = fmt.Sprintf("(%d/%d)\n%s\n", i+1, total, body)
+		}
+	}
+	return parts
+}
+
+// chainSeed starts the hash chain of a message of total parts, so a chain
+// cannot be cut short without the headers' part count changing too.
+func chainSeed(total int) string {
+	return chainLink("", fmt.Sprintf("enigoma parts %d", total))
+}
+
+// chainLink returns the link for a part: the first 16 hex digits of the
+// SHA-256 of the previous link and the part's body. A link commits to every
+// part before it, so an altered, swapped or substituted part breaks the chain
+// from that part on. The chain is unkeyed: it catches transmission errors
+// and careless tampering, not a forger who recomputes it.
+func chainLink(previous, body string) string {
+	sum := sha256.Sum256([]byte(previous + "\n" + body))
+	return hex.EncodeToString(sum[:8])
+}
+
+// joinParts reassembles text written by splitParts. The parts may arrive in
+// any order and a part received twice is used once; text without part
+// headers is returned unchanged. Chained parts are checked against their
+// links once they are in order.
+func joinParts(text string) (string, error) {
+	headers := partHeader.FindAllStringSubmatchIndex(text, -1)
+	if len(headers) == 0 || strings.TrimSpace(text[:headers[0][0]]) != "" {
+		return text, nil
+	}
+
+	total := 0
+	chained := headers[0][6] >= 0
+	parts := make(map[int]string)
+	links := make(map[int]string)
+	for i, h := range headers {
+		index, _ := strconv.Atoi(text[h[2]:h[3]])
+		n, _ := strconv.Atoi(text[h[4]:h[5]])
+		if total == 0 {
+			total = n
+		}
+		if n != total || index < 1 || index > total {
+			return "", fmt.Errorf("part (%d/%d) does not belong to a message of %d parts", index, n, total)
+		}
+		if (h[6] >= 0) != chained {
+			return "", fmt.Errorf("part %d of %d is mixed with parts from another message: only some parts carry a hash chain", index, total)
+		}
+		if chained {
+			links[index] = text[h[6]:h[7]]
+		}
+
+		end := len(text)
+		if i+1 < len(headers) {
+			end = headers[i+1][0]
+		}
+		body := strings.TrimSuffix(text[h[1]:end], "\n")
+		if previous, ok := parts[index]; ok && previous != body {
+			return "", fmt.Errorf("part (%d/%d) was received twice with different contents", index, total)
+		}
+		parts[index] = body
+	}
+
+	var missing []string
+	var joined strings.Builder
+	for index := 1; index <= total; index++ {
+		body, ok := parts[index]
+		if !ok {
+			missing = append(missing, strconv.Itoa(index))
+			continue
+		}
+		joined.WriteString(body)
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("missing part %s of %d", strings.Join(missing, ", "), total)
+	}
+	if chained {
+		if err := checkChain(parts, links, total); err != nil {
+			return "", err
+		}
+	}
+	return joined.String(), nil
+}
+
+// checkChain recomputes the hash chain over the ordered parts and names the
+// first part whose link does not match.
+func checkChain(parts, links map[int]string, total int) error {
+	link := chainSeed(total)
+	for index := 1; index <= total; index++ {
+		link = chainLink(link, parts[index])
+		if link != links[index] {
+			if index == 1 {
+				return fmt.Errorf("part 1 of %d fails the hash chain: it was altered in transit", total)
+			}
+			return fmt.Errorf("part %d of %d fails the hash chain: it was altered, or part %d is not the one sent before it", index, total, index-1)
+		}
+	}
+	return nil
+}
+
+// validateSplit checks --split against the other output flags.
+func validateSplit(cmd *cobra.Command, formats []string) error {
+	size, _ := cmd.Flags().GetInt("split")
+	chain, _ := cmd.Flags().GetBool("chain")
+	switch {
+	case size == 0 && chain:
+		return fmt.Errorf("--chain links the parts of --split; pass --split too")
+	case size == 0:
+		return nil
+	case size < 0:
+		return fmt.Errorf("--split must be a positive number of characters")
+	case len(formats) > 1:
+		return fmt.Errorf("--split writes a single format; pass one --format")
+	}
+	if configs, _ := cmd.Flags().GetStringSlice("config-list"); len(configs) > 0 {
+		return fmt.Errorf("--split cannot be combined with --config-list")
+	}
+	return nil
+}
+
+// partPath returns the file for part i of --output: msg.txt becomes msg.1.txt.
+func partPath(output string, i int) string {
+	ext := filepath.Ext(output)
+	return fmt.Sprintf("%s.%d%s", strings.TrimSuffix(output, ext), i, ext)
+}
+
+// writeSplitOutput writes formatted output as parts of at most --split
+// characters: to stdout as one stream, or with --output to one file per part,
+// each with its own key sidecar when encrypt generated the configuration.
+func writeSplitOutput(cmd *cobra.Command, formatted, fingerprint string) error {
+	size, _ := cmd.Flags().GetInt("split")
+	chain, _ := cmd.Flags().GetBool("chain")
+	parts := splitParts(formatted, size, chain)
+
+	output, _ := cmd.Flags().GetString("output")
+	if output == "" {
+		fmt.Fprint(cmd.OutOrStdout(), strings.Join(parts, ""))
+		return nil
+	}
+
+	fsys := fileSystem(cmd)
+	configFile, _ := cmd.Flags().GetString("auto-config")
+	if configFile == "" {
+		configFile, _ = cmd.Flags().GetString("save-config")
+	}
+	for i, part := range parts {
+		path := partPath(output, i+1)
+		if err := fsys.WriteFile(path, []byte(part), 0600); err != nil {
+			return fmt.Errorf("failed to write %s: %v", path, err)
+		}
+		if configFile != "" {
+			if err := writeSidecar(fsys, path, configFile, fingerprint); err != nil {
+				return err
+			}
+		}
+		fmt.Fprintf(uiErr(cmd), "Part %d/%d -> %s\n", i+1, len(parts), path)
+	}
+	return nil
+}
+
+// readPartFiles appends the part files named as arguments to decrypt to the
+// input read so far. joinParts puts the parts in order, so the files may be
+// given in any order.
+func readPartFiles(cmd *cobra.Command, first string, files []string) (string, error) {
+	fsys := fileSystem(cmd)
+	var combined strings.Builder
+	combined.WriteString(first)
+	for _, file := range files {
+		data, err := fsys.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("failed to read file %s: %w", file, err)
+		}
+		if combined.Len() > 0 && !strings.HasSuffix(combined.String(), "\n") {
+			combined.WriteString("\n")
+		}
+		combined.Write(data)
+	}
+	return combined.String(), nil
+}
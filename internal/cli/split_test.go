@@ -0,0 +1,128 @@
+// Package cli provides unit tests for splitting ciphertext into parts.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitAndJoinParts(t *testing.T) {
+	text := "HELLO WORLD\nSECOND LINE ÄÖÜ"
+	parts := splitParts(text, 5, false)
+	if len(parts) != 6 || parts[1] != "(2/6)\n WORL\n" {
+		t.Fatalf("unexpected parts: %q", parts)
+	}
+
+	// Out of order, with a retransmitted part
+	shuffled := []string{parts[3], parts[0], parts[5], parts[1], parts[2], parts[1], parts[4]}
+	got, err := joinParts(strings.Join(shuffled, ""))
+	if err != nil || got != text {
+		t.Errorf("joinParts = %q, %v; want %q", got, err, text)
+	}
+
+	if got, err := joinParts("NO PARTS HERE"); err != nil || got != "NO PARTS HERE" {
+		t.Errorf("unsplit text changed: %q, %v", got, err)
+	}
+	if _, err := joinParts(parts[0] + parts[2]); err == nil || !strings.Contains(err.Error(), "missing part 2, 4, 5, 6 of 6") {
+		t.Errorf("expected missing parts error, got %v", err)
+	}
+	if _, err := joinParts(parts[0] + "(2/3)\nXX\n"); err == nil {
+		t.Error("expected an error for a part of another message")
+	}
+}
+
+func TestEncryptSplitRoundTrip(t *testing.T) {
+	fsys := NewMemFS()
+	if _, err := runCLI(fsys, "keygen", "--preset", "m3", "--output", "key.json"); err != nil {
+		t.Fatalf("keygen failed: %v", err)
+	}
+	plaintext := strings.Repeat("ATTACKATDAWN", 30)
+
+	// One stream on stdout
+	stream, err := runCLI(fsys, "encrypt", "--config", "key.json", "--text", plaintext, "--split", "140")
+	if err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	if !strings.HasPrefix(stream, "(1/3)\n") || !strings.Contains(stream, "\n(3/3)\n") {
+		t.Fatalf("unexpected split output:\n%s", stream)
+	}
+	fsys.WriteFile("stream.txt", []byte(stream), 0600)
+	if out, err := runCLI(fsys, "decrypt", "--config", "key.json", "--file", "stream.txt"); err != nil || out != plaintext {
+		t.Errorf("decrypt stream = %q, %v", out, err)
+	}
+
+	// One file per part, given in any order
+	if _, err := runCLI(fsys, "encrypt", "--config", "key.json", "--text", plaintext, "--split", "300", "--format", "hex", "--output", "msg.txt"); err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	for _, name := range []string{"msg.1.txt", "msg.2.txt", "msg.3.txt"} {
+		if _, err := fsys.Stat(name); err != nil {
+			t.Errorf("expected %s: %v", name, err)
+		}
+	}
+	out, err := runCLI(fsys, "decrypt", "--config", "key.json", "--format", "hex", "--file", "msg.3.txt", "msg.1.txt", "msg.2.txt")
+	if err != nil || out != plaintext {
+		t.Errorf("decrypt parts = %q, %v", out, err)
+	}
+	if _, err := runCLI(fsys, "decrypt", "--config", "key.json", "--format", "hex", "msg.1.txt", "msg.3.txt"); err == nil || !strings.Contains(err.Error(), "missing part 2") {
+		t.Errorf("expected missing part error, got %v", err)
+	}
+
+	if _, err := runCLI(fsys, "encrypt", "--config", "key.json", "--text", "HI", "--split", "-1"); err == nil {
+		t.Error("expected an error for a negative --split")
+	}
+}
+
+func TestChainedParts(t *testing.T) {
+	text := "ATTACKATDAWNHOLDTHEBRIDGE"
+	parts := splitParts(text, 5, true)
+	if !partHeader.MatchString(parts[0]) || !strings.HasPrefix(parts[0], "(1/5 ") {
+		t.Fatalf("unexpected chained header: %q", parts[0])
+	}
+	if got, err := joinParts(parts[4] + parts[2] + parts[0] + parts[1] + parts[3]); err != nil || got != text {
+		t.Errorf("joinParts = %q, %v; want %q", got, err, text)
+	}
+
+	// An altered body breaks the chain at that part
+	altered := append([]string(nil), parts...)
+	altered[2] = strings.Replace(altered[2], "WNHOL", "WNHXL", 1)
+	if _, err := joinParts(strings.Join(altered, "")); err == nil || !strings.Contains(err.Error(), "part 3 of 5 fails the hash chain") {
+		t.Errorf("expected a chain error for part 3, got %v", err)
+	}
+
+	// Relabelling swapped parts does not fool the chain
+	swapped := append([]string(nil), parts...)
+	swapped[1] = strings.Replace(parts[3], "(4/5 ", "(2/5 ", 1)
+	swapped[3] = strings.Replace(parts[1], "(2/5 ", "(4/5 ", 1)
+	if _, err := joinParts(strings.Join(swapped, "")); err == nil || !strings.Contains(err.Error(), "part 2 of 5") {
+		t.Errorf("expected a chain error for part 2, got %v", err)
+	}
+
+	// A missing part is still named
+	if _, err := joinParts(parts[0] + parts[1] + parts[3] + parts[4]); err == nil || !strings.Contains(err.Error(), "missing part 3 of 5") {
+		t.Errorf("expected missing part 3, got %v", err)
+	}
+
+	// Chained and unchained parts do not mix
+	plain := splitParts(text, 5, false)
+	if _, err := joinParts(parts[0] + plain[1] + parts[2] + parts[3] + parts[4]); err == nil {
+		t.Error("expected an error for mixed chained and unchained parts")
+	}
+}
+
+func TestEncryptChainRequiresSplit(t *testing.T) {
+	fsys := NewMemFS()
+	if _, err := runCLI(fsys, "encrypt", "--preset", "m3", "--text", "HELLO", "--chain"); err == nil || !strings.Contains(err.Error(), "--split") {
+		t.Errorf("expected --chain without --split to fail, got %v", err)
+	}
+	out, err := runCLI(fsys, "encrypt", "--preset", "m3", "--text", "HELLOWORLD", "--split", "4", "--chain")
+	if err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	if len(partHeader.FindAllString(out, -1)) != 3 || !strings.HasPrefix(out, "(1/3 ") {
+		t.Errorf("unexpected chained output:\n%s", out)
+	}
+}
@@ -0,0 +1,148 @@
+// Package cli provides the suggest command for the enigoma CLI.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/coredds/enigoma"
+	"github.com/coredds/enigoma/internal/analysis"
+	"github.com/coredds/enigoma/pkg/enigma"
+	"github.com/spf13/cobra"
+)
+
+// suggestLevels are the security levels tried by suggest, weakest first.
+var suggestLevels = []string{"low", "medium", "high", "extreme"}
+
+// newSuggestCommand creates the suggest command.
+func newSuggestCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "suggest",
+		Short: "Recommend a security level that meets a throughput target",
+		Long: `Benchmark every security level briefly on this machine and recommend the
+highest one that still encrypts at least --target-throughput.
+
+The target is in bytes per second of UTF-8 text, with an optional K or M
+suffix (KiB, MiB) and an optional "/s" (e.g. 1MB/s, 512K). Throughput in
+characters is converted with the average encoded size of the alphabet's
+characters. The recommendation ends with the keygen command that creates
+such a key.
+
+Measurements vary with load; leave some headroom in the target.
+
+Examples:
+  enigoma suggest --target-throughput 1MB/s --alphabet ascii
+  enigoma suggest --target-throughput 256K --alphabet portuguese --bench 500ms`,
+		Args: cobra.NoArgs,
+		RunE: runSuggest,
+	}
+
+	cmd.Flags().String("target-throughput", "", "Minimum encryption throughput in bytes per second (e.g. 1MB/s, 512K)")
+	cmd.Flags().StringP("alphabet", "a", "latin", "Alphabet of the suggested key (see 'enigoma alphabet list')")
+	cmd.Flags().Duration("bench", 200*time.Millisecond, "Time spent measuring each security level")
+
+	return cmd
+}
+
+func runSuggest(cmd *cobra.Command, args []string) error {
+	targetFlag, _ := cmd.Flags().GetString("target-throughput")
+	if targetFlag == "" {
+		return fmt.Errorf("suggest requires --target-throughput")
+	}
+	target, err := parseByteSize(strings.TrimSuffix(strings.TrimSpace(targetFlag), "/s"))
+	if err != nil {
+		return fmt.Errorf("invalid --target-throughput %q: %v", targetFlag, err)
+	}
+	budget, _ := cmd.Flags().GetDuration("bench")
+	if budget <= 0 {
+		return fmt.Errorf("--bench must be positive")
+	}
+
+	alphabetName, _ := cmd.Flags().GetString("alphabet")
+	predefined, ok := enigoma.LookupAlphabet(alphabetName)
+	if !ok {
+		return fmt.Errorf("unknown alphabet: %s. Available: %s (see 'enigoma alphabet list')", alphabetName, alphabetNameList(false))
+	}
+	bytesPerChar := averageEncodedSize(predefined.Runes)
+
+	rows := [][]string{{"LEVEL", "ROTORS", "PLUGBOARD", "KEYSPACE BITS", "THROUGHPUT", "BYTES/S", "TARGET"}}
+	best := ""
+	for _, name := range suggestLevels {
+		level, _ := parseSecurityLevel(name)
+		opts := []enigma.Option{enigma.WithAlphabet(predefined.Runes)}
+		if !predefined.ReflectorCompatible() {
+			opts = append(opts, enigma.WithoutReflector())
+		}
+		machine, err := enigma.New(append(opts, enigma.WithRandomSettings(level))...)
+		if err != nil {
+			return fmt.Errorf("failed to build %s: %v", name, err)
+		}
+		rate, err := benchmarkMachine(machine, budget)
+		if err != nil {
+			return fmt.Errorf("benchmark of %s failed: %v", name, err)
+		}
+
+		byteRate := rate * bytesPerChar
+		meets := "no"
+		if byteRate >= float64(target) {
+			meets, best = "yes", name
+		}
+		ks := analysis.EstimateKeyspace(machine.GetAlphabetSize(), machine.GetRotorCount(), machine.GetPlugboardPairCount(), !machine.IsReflectorless())
+		rows = append(rows, []string{
+			name,
+			fmt.Sprint(machine.GetRotorCount()),
+			fmt.Sprint(machine.GetPlugboardPairCount()),
+			fmt.Sprintf("%.0f", ks.TotalBits()),
+			formatThroughput(rate),
+			formatByteRate(byteRate),
+			meets,
+		})
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Target: %s with the %s alphabet\n\n", formatByteRate(float64(target)), predefined.Name)
+	writeTable(cmd, rows)
+	fmt.Fprintln(out)
+	if best == "" {
+		return fmt.Errorf("no security level reaches %s on this machine; lower the target or use a smaller alphabet", formatByteRate(float64(target)))
+	}
+
+	keygen := fmt.Sprintf("enigoma keygen --security %s --alphabet %s", best, predefined.Name)
+	if !predefined.ReflectorCompatible() {
+		keygen += " --no-reflector"
+	}
+	fmt.Fprintf(out, "Suggested security level: %s\n", best)
+	fmt.Fprintf(out, "  %s --output key.json\n", keygen)
+	return nil
+}
+
+// averageEncodedSize returns the mean UTF-8 length of the alphabet's
+// characters, in bytes.
+func averageEncodedSize(runes []rune) float64 {
+	if len(runes) == 0 {
+		return 1
+	}
+	total := 0
+	for _, r := range runes {
+		total += utf8.RuneLen(r)
+	}
+	return float64(total) / float64(len(runes))
+}
+
+// formatByteRate renders bytes per second with a binary unit prefix, matching
+// the K and M suffixes accepted by parseByteSize.
+func formatByteRate(rate float64) string {
+	switch {
+	case rate >= 1<<20:
+		return fmt.Sprintf("%.1f MiB/s", rate/(1<<20))
+	case rate >= 1<<10:
+		return fmt.Sprintf("%.1f KiB/s", rate/(1<<10))
+	default:
+		return fmt.Sprintf("%.0f B/s", rate)
+	}
+}
@@ -0,0 +1,53 @@
+// Package cli provides unit tests for the suggest command.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSuggest(t *testing.T) {
+	out, err := runCLI(NewMemFS(), "suggest", "--target-throughput", "1/s", "--alphabet", "ascii", "--bench", "1ms")
+	if err != nil {
+		t.Fatalf("suggest failed: %v", err)
+	}
+	for _, want := range []string{"low", "extreme", "KEYSPACE BITS", "Suggested security level: extreme", "enigoma keygen --security extreme --alphabet ascii --no-reflector"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+
+	out, err = runCLI(NewMemFS(), "suggest", "--target-throughput", "1", "--bench", "1ms")
+	if err != nil {
+		t.Fatalf("suggest failed: %v", err)
+	}
+	if !strings.Contains(out, "--alphabet latin --output") {
+		t.Errorf("an even alphabet should keep its reflector:\n%s", out)
+	}
+
+	if _, err := runCLI(NewMemFS(), "suggest", "--target-throughput", "1000000M", "--bench", "1ms"); err == nil || !strings.Contains(err.Error(), "no security level") {
+		t.Errorf("an unreachable target should fail, got %v", err)
+	}
+	for _, args := range [][]string{
+		{},
+		{"--target-throughput", "fast"},
+		{"--target-throughput", "1M", "--alphabet", "klingon"},
+		{"--target-throughput", "1M", "--bench", "0"},
+	} {
+		if _, err := runCLI(NewMemFS(), append([]string{"suggest"}, args...)...); err == nil {
+			t.Errorf("suggest %v should fail", args)
+		}
+	}
+}
+
+func TestAverageEncodedSize(t *testing.T) {
+	if got := averageEncodedSize([]rune("AB")); got != 1 {
+		t.Errorf("ASCII should average 1 byte, got %v", got)
+	}
+	if got := averageEncodedSize([]rune("Aé")); got != 1.5 {
+		t.Errorf("got %v, want 1.5", got)
+	}
+}
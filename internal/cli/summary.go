@@ -0,0 +1,94 @@
+// Package cli provides the post-operation summary for encrypt and decrypt.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/coredds/enigoma/pkg/enigma"
+	"github.com/spf13/cobra"
+)
+
+// operationSummary reports how far the rotors travelled during one run.
+type operationSummary struct {
+	Operation      string   `json:"operation"`
+	Characters     int      `json:"characters"`
+	RotorIDs       []string `json:"rotor_ids"`
+	RotorSteps     []int    `json:"rotor_steps"`
+	FinalPositions []int    `json:"final_positions"`
+
+	positions string // FinalPositions in the --notation of the run, for String
+}
+
+// newOperationSummary collects rotor statistics from a machine after processing text.
+func newOperationSummary(operation string, machine *enigma.Enigma, text string) (*operationSummary, error) {
+	settings, err := machine.GetSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read machine settings: %v", err)
+	}
+
+	ids := make([]string, len(settings.RotorSpecs))
+	for i, spec := range settings.RotorSpecs {
+		ids[i] = spec.ID
+	}
+
+	return &operationSummary{
+		Operation:      operation,
+		Characters:     utf8.RuneCountInString(text),
+		RotorIDs:       ids,
+		RotorSteps:     machine.GetRotorStepCounts(),
+		FinalPositions: machine.GetCurrentRotorPositions(),
+	}, nil
+}
+
+// String renders the summary as a single human-readable line.
+func (s *operationSummary) String() string {
+	steps := make([]string, len(s.RotorSteps))
+	for i, count := range s.RotorSteps {
+		id := fmt.Sprintf("R%d", i+1)
+		if i < len(s.RotorIDs) && s.RotorIDs[i] != "" {
+			id = s.RotorIDs[i]
+		}
+		steps[i] = fmt.Sprintf("%s=%d", id, count)
+	}
+	positions := s.positions
+	if positions == "" {
+		positions = fmt.Sprint(s.FinalPositions)
+	}
+	return fmt.Sprintf("Summary: %s %d characters | rotor steps: %s | final positions: %s",
+		s.Operation, s.Characters, strings.Join(steps, " "), positions)
+}
+
+// maybeWriteSummary prints the summary to stderr when --summary or --json is set.
+// In text mode a one-line preview of the output follows the summary.
+func maybeWriteSummary(cmd *cobra.Command, operation string, machine *enigma.Enigma, text, output string) error {
+	wantSummary, _ := cmd.Flags().GetBool("summary")
+	wantJSON, _ := cmd.Flags().GetBool("json")
+	if !wantSummary && !wantJSON {
+		return nil
+	}
+
+	summary, err := newOperationSummary(operation, machine, text)
+	if err != nil {
+		return err
+	}
+
+	if wantJSON {
+		data, err := json.Marshal(summary)
+		if err != nil {
+			return fmt.Errorf("failed to encode summary: %v", err)
+		}
+		fmt.Fprintln(cmd.ErrOrStderr(), string(data))
+		return nil
+	}
+
+	summary.positions = machinePositions(cmd, machine)
+	fmt.Fprintln(cmd.ErrOrStderr(), summary.String())
+	fmt.Fprintf(cmd.ErrOrStderr(), "Output: %s\n", preview(cmd, output, len("Output: ")))
+	return nil
+}
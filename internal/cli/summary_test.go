@@ -0,0 +1,82 @@
+// Package cli provides unit tests for the operation summary.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestEncryptSummary tests the --summary and --json reporting flags.
+func TestEncryptSummary(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{
+			name:     "text summary",
+			args:     []string{"encrypt", "--text", "HELLOWORLD", "--preset", "m3", "--summary"},
+			contains: "Summary: encrypted 10 characters | rotor steps: I=0 II=0 III=10 | final positions: [0 0 10]\nOutput: ILBDAAMTAZ",
+		},
+		{
+			name:     "no summary by default",
+			args:     []string{"encrypt", "--text", "HELLOWORLD", "--preset", "m3"},
+			contains: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			cmd := NewRootCommand(Options{})
+			cmd.SetOut(&stdout)
+			cmd.SetErr(&stderr)
+			cmd.SetArgs(tt.args)
+
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			got := strings.TrimSpace(stderr.String())
+			if got != tt.contains {
+				t.Errorf("stderr = %q, want %q", got, tt.contains)
+			}
+			if strings.Contains(stdout.String(), "Summary") {
+				t.Errorf("summary must not be written to stdout")
+			}
+		})
+	}
+}
+
+// TestDecryptSummaryJSON tests the machine-readable summary.
+func TestDecryptSummaryJSON(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	cmd := NewRootCommand(Options{})
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	cmd.SetArgs([]string{"decrypt", "--text", strings.Repeat("A", 30), "--preset", "m3", "--json"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var summary operationSummary
+	if err := json.Unmarshal(stderr.Bytes(), &summary); err != nil {
+		t.Fatalf("summary is not valid JSON: %v (%q)", err, stderr.String())
+	}
+
+	if summary.Operation != "decrypted" || summary.Characters != 30 {
+		t.Errorf("unexpected summary header: %+v", summary)
+	}
+	if len(summary.RotorSteps) != 3 || summary.RotorSteps[2] != 30 || summary.RotorSteps[1] != 1 {
+		t.Errorf("unexpected rotor steps: %v", summary.RotorSteps)
+	}
+	if len(summary.FinalPositions) != 3 || summary.FinalPositions[2] != 4 {
+		t.Errorf("unexpected final positions: %v", summary.FinalPositions)
+	}
+}
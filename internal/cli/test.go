@@ -12,10 +12,12 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var testCmd = &cobra.Command{
-	Use:   "test",
-	Short: "Test enigoma installation and functionality",
-	Long: `Test enigoma installation and core functionality.
+// newTestCommand creates the test command.
+func newTestCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "test",
+		Short: "Test enigoma installation and functionality",
+		Long: `Test enigoma installation and core functionality.
 
 This command runs a series of tests to verify that enigoma is working correctly:
 • Basic encryption/decryption round-trip
@@ -28,110 +30,114 @@ Perfect for verifying your installation or troubleshooting issues.
 
 Example:
   enigoma test`,
-	RunE: runTest,
+		RunE: runTest,
+	}
+
+	return cmd
 }
 
 func runTest(cmd *cobra.Command, args []string) error {
-	fmt.Printf("🧪 Testing enigoma Installation\n")
-	fmt.Printf("Version: %s\n", enigoma.GetVersion())
-	fmt.Println("==============================")
-	fmt.Println()
+	out := uiOut(cmd)
+	fmt.Fprintf(out, "🧪 Testing enigoma Installation\n")
+	fmt.Fprintf(out, "Version: %s\n", enigoma.GetVersion())
+	fmt.Fprintln(out, "==============================")
+	fmt.Fprintln(out)
 
 	var passed, failed int
 
 	// Test 1: Basic Functionality
-	fmt.Print("📝 Basic encryption/decryption... ")
+	fmt.Fprint(out, "📝 Basic encryption/decryption... ")
 	if err := testBasicEncryption(); err != nil {
-		fmt.Printf("❌ FAILED: %v\n", err)
+		fmt.Fprintf(out, "❌ %s: %v\n", paint(colorRed, "FAILED"), err)
 		failed++
 	} else {
-		fmt.Println("✅ PASSED")
+		fmt.Fprintln(out, "✅ "+paint(colorGreen, "PASSED"))
 		passed++
 	}
 
 	// Test 2: Unicode Support
-	fmt.Print("🌍 Unicode support... ")
+	fmt.Fprint(out, "🌍 Unicode support... ")
 	if err := testUnicodeSupport(); err != nil {
-		fmt.Printf("❌ FAILED: %v\n", err)
+		fmt.Fprintf(out, "❌ %s: %v\n", paint(colorRed, "FAILED"), err)
 		failed++
 	} else {
-		fmt.Println("✅ PASSED")
+		fmt.Fprintln(out, "✅ "+paint(colorGreen, "PASSED"))
 		passed++
 	}
 
 	// Test 3: Auto-Detection
-	fmt.Print("🎯 Auto-detection... ")
+	fmt.Fprint(out, "🎯 Auto-detection... ")
 	if err := testAutoDetection(); err != nil {
-		fmt.Printf("❌ FAILED: %v\n", err)
+		fmt.Fprintf(out, "❌ %s: %v\n", paint(colorRed, "FAILED"), err)
 		failed++
 	} else {
-		fmt.Println("✅ PASSED")
+		fmt.Fprintln(out, "✅ "+paint(colorGreen, "PASSED"))
 		passed++
 	}
 
 	// Test 4: Configuration Serialization
-	fmt.Print("💾 Configuration serialization... ")
+	fmt.Fprint(out, "💾 Configuration serialization... ")
 	if err := testConfigSerialization(); err != nil {
-		fmt.Printf("❌ FAILED: %v\n", err)
+		fmt.Fprintf(out, "❌ %s: %v\n", paint(colorRed, "FAILED"), err)
 		failed++
 	} else {
-		fmt.Println("✅ PASSED")
+		fmt.Fprintln(out, "✅ "+paint(colorGreen, "PASSED"))
 		passed++
 	}
 
 	// Test 5: Security Levels
-	fmt.Print("🛡️  Security levels... ")
+	fmt.Fprint(out, "🛡️  Security levels... ")
 	if err := testSecurityLevels(); err != nil {
-		fmt.Printf("❌ FAILED: %v\n", err)
+		fmt.Fprintf(out, "❌ %s: %v\n", paint(colorRed, "FAILED"), err)
 		failed++
 	} else {
-		fmt.Println("✅ PASSED")
+		fmt.Fprintln(out, "✅ "+paint(colorGreen, "PASSED"))
 		passed++
 	}
 
 	// Test 6: Convenience Functions
-	fmt.Print("⚡ Convenience functions... ")
+	fmt.Fprint(out, "⚡ Convenience functions... ")
 	if err := testConvenienceFunctions(); err != nil {
-		fmt.Printf("❌ FAILED: %v\n", err)
+		fmt.Fprintf(out, "❌ %s: %v\n", paint(colorRed, "FAILED"), err)
 		failed++
 	} else {
-		fmt.Println("✅ PASSED")
+		fmt.Fprintln(out, "✅ "+paint(colorGreen, "PASSED"))
 		passed++
 	}
 
 	// Test 7: Historical Presets
-	fmt.Print("🏛️  Historical presets... ")
+	fmt.Fprint(out, "🏛️  Historical presets... ")
 	if err := testHistoricalPresets(); err != nil {
-		fmt.Printf("❌ FAILED: %v\n", err)
+		fmt.Fprintf(out, "❌ %s: %v\n", paint(colorRed, "FAILED"), err)
 		failed++
 	} else {
-		fmt.Println("✅ PASSED")
+		fmt.Fprintln(out, "✅ "+paint(colorGreen, "PASSED"))
 		passed++
 	}
 
 	// Summary
-	fmt.Println()
-	fmt.Println("📊 TEST RESULTS")
-	fmt.Println("===============")
-	fmt.Printf("✅ Passed: %d\n", passed)
-	fmt.Printf("❌ Failed: %d\n", failed)
-	fmt.Printf("📈 Success Rate: %.1f%%\n", float64(passed)/float64(passed+failed)*100)
-	fmt.Println()
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "📊 "+paint(colorBold, "TEST RESULTS"))
+	fmt.Fprintln(out, "===============")
+	fmt.Fprintf(out, "✅ Passed: %d\n", passed)
+	fmt.Fprintf(out, "❌ Failed: %d\n", failed)
+	fmt.Fprintf(out, "📈 Success Rate: %.1f%%\n", float64(passed)/float64(passed+failed)*100)
+	fmt.Fprintln(out)
 
 	if failed == 0 {
-		fmt.Println("🎉 All tests passed! enigoma is working perfectly.")
-		fmt.Println()
-		fmt.Println("Ready to use:")
-		fmt.Println("• enigoma encrypt --text \"Your message\" --auto-config key.json")
-		fmt.Println("• enigoma wizard (for interactive setup)")
-		fmt.Println("• enigoma examples (for copy-paste examples)")
+		fmt.Fprintln(out, "🎉 All tests passed! enigoma is working perfectly.")
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "Ready to use:")
+		fmt.Fprintln(out, "• enigoma encrypt --text \"Your message\" --auto-config key.json")
+		fmt.Fprintln(out, "• enigoma wizard (for interactive setup)")
+		fmt.Fprintln(out, "• enigoma examples (for copy-paste examples)")
 	} else {
-		fmt.Printf("⚠️  %d test(s) failed. enigoma may not be working correctly.\n", failed)
-		fmt.Println()
-		fmt.Println("Troubleshooting:")
-		fmt.Println("• Check your Go version (requires Go 1.23+)")
-		fmt.Println("• Try reinstalling: go install github.com/coredds/enigoma/cmd/enigoma@latest")
-		fmt.Println("• Report issues at: https://github.com/coredds/enigoma/issues")
+		fmt.Fprintf(out, "⚠️  %d test(s) failed. enigoma may not be working correctly.\n", failed)
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "Troubleshooting:")
+		fmt.Fprintln(out, "• Check your Go version (requires Go 1.23+)")
+		fmt.Fprintln(out, "• Try reinstalling: go install github.com/coredds/enigoma/cmd/enigoma@latest")
+		fmt.Fprintln(out, "• Report issues at: https://github.com/coredds/enigoma/issues")
 		return fmt.Errorf("test suite failed with %d failures", failed)
 	}
 
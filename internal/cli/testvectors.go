@@ -0,0 +1,241 @@
+// Package cli provides the testvectors command for the enigoma CLI.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/coredds/enigoma"
+	"github.com/coredds/enigoma/internal/alphabet"
+	"github.com/coredds/enigoma/internal/random"
+	"github.com/coredds/enigoma/pkg/enigma"
+	"github.com/spf13/cobra"
+)
+
+// testVectorFormat identifies the test vector file layout.
+const testVectorFormat = "enigoma-testvectors/1"
+
+// testVectorFile is a machine, an input and the machine's observable
+// behaviour for every character of it.
+type testVectorFile struct {
+	Format         string           `json:"format"`
+	Generator      string           `json:"generator"`
+	Preset         string           `json:"preset,omitempty"`
+	Settings       json.RawMessage  `json:"settings"` // Configuration file at the starting positions
+	StartPositions []int            `json:"start_positions"`
+	StartWindow    string           `json:"start_window"`
+	Input          string           `json:"input"`
+	Output         string           `json:"output"`
+	Steps          []testVectorStep `json:"steps"`
+}
+
+// testVectorStep records one key press. Positions are zero-based and taken
+// after the rotors stepped for this character; Window shows the same
+// positions as alphabet characters.
+type testVectorStep struct {
+	Index     int    `json:"index"`
+	Input     string `json:"input"`
+	Output    string `json:"output"`
+	Positions []int  `json:"positions"`
+	Window    string `json:"window"`
+}
+
+// newTestVectorsCommand creates the testvectors command.
+func newTestVectorsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "testvectors",
+		Short: "Generate or check rotor stepping test vectors",
+		Long: `Generate test vectors for porting enigoma to other languages, or check them.
+
+A vector file holds the complete machine (the same JSON as a configuration
+file), the starting positions, an input text, and for every character the
+output and the rotor positions after stepping. An implementation that
+reproduces every step handles wiring, ring settings, notches, turnover and
+the double-step anomaly the same way enigoma does.
+
+The input is --text, or --length characters drawn from the alphabet with a
+seeded generator, so the same flags always produce the same file.
+--positions is read in --notation, which defaults to letters here.
+
+--check replays a vector file against this build and reports the first step
+that differs.
+
+Examples:
+  enigoma testvectors --preset m3 --positions AAA --length 100 --output vectors.json
+  enigoma testvectors --config my-key.json --text "HELLOWORLD"
+  enigoma testvectors --check vectors.json`,
+		Args: cobra.NoArgs,
+		RunE: runTestVectors,
+	}
+
+	cmd.Flags().StringP("preset", "p", "m3", fmt.Sprintf("Machine to describe (%s); ignored with --config", strings.Join(presetNames(), ", ")))
+	cmd.Flags().StringSlice("positions", nil, "Starting rotor positions (e.g., AAA; default: the machine's own)")
+	addNotationFlag(cmd, notationLetters)
+	cmd.Flags().StringP("text", "t", "", "Input text (default: --length generated characters)")
+	cmd.Flags().IntP("length", "n", 100, "Number of generated input characters")
+	cmd.Flags().Int64("seed", 1, "Seed for the generated input")
+	cmd.Flags().StringP("output", "o", "", "Output file (default: stdout)")
+	cmd.Flags().String("check", "", "Verify a vector file instead of generating one")
+
+	return cmd
+}
+
+func runTestVectors(cmd *cobra.Command, args []string) error {
+	if path, _ := cmd.Flags().GetString("check"); path != "" {
+		return checkTestVectors(cmd, path)
+	}
+
+	fsys, opts := fileSystem(cmd), configOptionsFor(cmd)
+	var (
+		machine *enigma.Enigma
+		preset  string
+		err     error
+	)
+	if configFile, _ := cmd.Flags().GetString("config"); configFile != "" {
+		machine, err = createMachineFromConfig(fsys, opts, configFile)
+	} else {
+		preset, _ = cmd.Flags().GetString("preset")
+		machine, err = presetMachine(cmd, preset)
+	}
+	if err != nil {
+		return err
+	}
+	if err := reportMachineWarnings(cmd, machine); err != nil {
+		return err
+	}
+	if err := applyPositionFlags(cmd, machine, "positions"); err != nil {
+		return err
+	}
+	if machine.IsGraphemeAlphabet() {
+		return fmt.Errorf("test vectors record one rune per key press, so they cannot be generated for an alphabet of grapheme clusters")
+	}
+
+	settingsJSON, err := machine.SaveSettingsToJSON()
+	if err != nil {
+		return fmt.Errorf("failed to serialize settings: %v", err)
+	}
+	settings, err := machine.GetSettings()
+	if err != nil {
+		return fmt.Errorf("failed to read machine settings: %v", err)
+	}
+
+	input, _ := cmd.Flags().GetString("text")
+	if input == "" {
+		length, _ := cmd.Flags().GetInt("length")
+		seed, _ := cmd.Flags().GetInt64("seed")
+		if input, err = generateVectorInput(settings.Alphabet, length, seed); err != nil {
+			return err
+		}
+	}
+
+	start := machine.GetCurrentRotorPositions()
+	vectors := testVectorFile{
+		Format:         testVectorFormat,
+		Generator:      "enigoma " + enigoma.GetVersion(),
+		Preset:         preset,
+		Settings:       json.RawMessage(settingsJSON),
+		StartPositions: start,
+		StartWindow:    notationLetters.formatPositions(start, settings.Alphabet),
+		Input:          input,
+	}
+	machine.SetStepCallback(func(info enigma.StepInfo) {
+		vectors.Steps = append(vectors.Steps, testVectorStep{
+			Index:     info.Index,
+			Input:     string(info.Input),
+			Output:    string(info.Output),
+			Positions: info.Positions,
+			Window:    notationLetters.formatPositions(info.Positions, settings.Alphabet),
+		})
+	})
+	if vectors.Output, err = machine.Encrypt(input); err != nil {
+		return fmt.Errorf("failed to encrypt input: %v", err)
+	}
+
+	data, err := json.MarshalIndent(vectors, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode test vectors: %v", err)
+	}
+	data = append(data, '\n')
+
+	outputFile, _ := cmd.Flags().GetString("output")
+	if outputFile == "" {
+		_, err := cmd.OutOrStdout().Write(data)
+		return err
+	}
+	if err := writeStringToFile(fsys, string(data), outputFile); err != nil {
+		return fmt.Errorf("failed to write test vectors: %v", err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Wrote %d test vectors to %s\n", len(vectors.Steps), outputFile)
+	return nil
+}
+
+// generateVectorInput draws length characters from alphabet, reproducibly for a seed.
+func generateVectorInput(runes []rune, length int, seed int64) (string, error) {
+	if length <= 0 {
+		return "", fmt.Errorf("--length must be positive, got %d", length)
+	}
+	alph, err := alphabet.New(runes)
+	if err != nil {
+		return "", err
+	}
+	return alph.RandomTextFrom(random.NewDeterministic([]byte("testvectors:"+strconv.FormatInt(seed, 10))), length)
+}
+
+// checkTestVectors replays a vector file one key press at a time.
+func checkTestVectors(cmd *cobra.Command, path string) error {
+	data, err := fileSystem(cmd).ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read test vectors: %v", err)
+	}
+	var vectors testVectorFile
+	if err := json.Unmarshal(data, &vectors); err != nil {
+		return fmt.Errorf("invalid test vector file: %v", err)
+	}
+	if vectors.Format != testVectorFormat {
+		return fmt.Errorf("unsupported test vector format %q (expected %s)", vectors.Format, testVectorFormat)
+	}
+
+	machine, err := enigma.NewFromJSON(string(vectors.Settings))
+	if err != nil {
+		return fmt.Errorf("invalid machine in test vectors: %v", err)
+	}
+	if err := machine.SetRotorPositions(vectors.StartPositions); err != nil {
+		return fmt.Errorf("invalid start positions in test vectors: %v", err)
+	}
+
+	if len(vectors.Steps) != utf8.RuneCountInString(vectors.Input) {
+		return fmt.Errorf("invalid test vector file: %d steps for %d input characters", len(vectors.Steps), utf8.RuneCountInString(vectors.Input))
+	}
+
+	var got enigma.StepInfo
+	var outputs strings.Builder
+	machine.SetStepCallback(func(info enigma.StepInfo) { got = info })
+	for i, step := range vectors.Steps {
+		if utf8.RuneCountInString(step.Input) != 1 {
+			return fmt.Errorf("step %d: input must be one character, got %q", i, step.Input)
+		}
+		output, err := machine.Encrypt(step.Input)
+		if err != nil {
+			return fmt.Errorf("step %d: %v", i, err)
+		}
+		if output != step.Output {
+			return fmt.Errorf("step %d: %q encrypted to %q, vectors expect %q (positions %v)", i, step.Input, output, step.Output, got.Positions)
+		}
+		if fmt.Sprint(got.Positions) != fmt.Sprint(step.Positions) {
+			return fmt.Errorf("step %d: rotors at %v after stepping, vectors expect %v", i, got.Positions, step.Positions)
+		}
+		outputs.WriteString(output)
+	}
+	if outputs.String() != vectors.Output {
+		return fmt.Errorf("steps produce %q, but the vectors' output is %q", outputs.String(), vectors.Output)
+	}
+
+	fmt.Fprintf(uiOut(cmd), "✅ All %d test vectors in %s match\n", len(vectors.Steps), path)
+	return nil
+}
@@ -0,0 +1,121 @@
+// Package cli provides unit tests for the testvectors command.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestTestVectorsGenerateAndCheck(t *testing.T) {
+	fsys := NewMemFS()
+	out, err := runCLI(fsys, "testvectors", "--preset", "m3", "--positions", "AAA", "--length", "100", "--output", "vectors.json")
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	if !strings.Contains(out, "Wrote 100 test vectors to vectors.json") {
+		t.Errorf("unexpected output: %q", out)
+	}
+
+	data, err := fsys.ReadFile("vectors.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var vectors testVectorFile
+	if err := json.Unmarshal(data, &vectors); err != nil {
+		t.Fatalf("invalid vector file: %v", err)
+	}
+	if vectors.StartWindow != "AAA" || len(vectors.Steps) != 100 || len(vectors.Input) != 100 {
+		t.Fatalf("unexpected vectors: start %q, %d steps, input %q", vectors.StartWindow, len(vectors.Steps), vectors.Input)
+	}
+	// The rightmost rotor steps before the first character is encrypted
+	if vectors.Steps[0].Window != "AAB" {
+		t.Errorf("first step window %q, want AAB", vectors.Steps[0].Window)
+	}
+
+	out, err = runCLI(fsys, "testvectors", "--check", "vectors.json")
+	if err != nil {
+		t.Fatalf("check failed: %v", err)
+	}
+	if !strings.Contains(out, "All 100 test vectors in vectors.json match") {
+		t.Errorf("unexpected check output: %q", out)
+	}
+
+	// The same flags produce the same file
+	again, err := runCLI(fsys, "testvectors", "--preset", "m3", "--positions", "AAA", "--length", "100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != string(data) {
+		t.Error("generation is not reproducible")
+	}
+}
+
+func TestTestVectorsText(t *testing.T) {
+	out, err := runCLI(NewMemFS(), "testvectors", "--preset", "m3", "--positions", "1,1,1", "--notation", "numbers", "--text", "HELLOWORLD")
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	var vectors testVectorFile
+	if err := json.Unmarshal([]byte(out), &vectors); err != nil {
+		t.Fatalf("invalid vector file: %v", err)
+	}
+	if vectors.Input != "HELLOWORLD" || len(vectors.Steps) != 10 || vectors.StartWindow != "AAA" {
+		t.Errorf("unexpected vectors: input %q, %d steps, start %q", vectors.Input, len(vectors.Steps), vectors.StartWindow)
+	}
+}
+
+func TestTestVectorsCheckDetectsMismatch(t *testing.T) {
+	fsys := NewMemFS()
+	if _, err := runCLI(fsys, "testvectors", "--preset", "m3", "--positions", "ADU", "--length", "20", "--output", "vectors.json"); err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	data, err := fsys.ReadFile("vectors.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tamper := func(edit func(*testVectorFile)) string {
+		var vectors testVectorFile
+		if err := json.Unmarshal(data, &vectors); err != nil {
+			t.Fatal(err)
+		}
+		edit(&vectors)
+		tampered, err := json.Marshal(vectors)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := fsys.WriteFile("tampered.json", tampered, 0600); err != nil {
+			t.Fatal(err)
+		}
+		_, err = runCLI(fsys, "testvectors", "--check", "tampered.json")
+		if err == nil {
+			t.Fatal("check should fail on tampered vectors")
+		}
+		return err.Error()
+	}
+
+	msg := tamper(func(v *testVectorFile) {
+		if v.Steps[7].Output == "A" {
+			v.Steps[7].Output = "B"
+		} else {
+			v.Steps[7].Output = "A"
+		}
+	})
+	if !strings.Contains(msg, "step 7") {
+		t.Errorf("expected the output mismatch at step 7, got %q", msg)
+	}
+
+	msg = tamper(func(v *testVectorFile) { v.Steps[3].Positions[2]++ })
+	if !strings.Contains(msg, "step 3") || !strings.Contains(msg, "rotors at") {
+		t.Errorf("expected the position mismatch at step 3, got %q", msg)
+	}
+
+	msg = tamper(func(v *testVectorFile) { v.Format = "other/1" })
+	if !strings.Contains(msg, "unsupported test vector format") {
+		t.Errorf("expected a format error, got %q", msg)
+	}
+}
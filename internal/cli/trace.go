@@ -0,0 +1,148 @@
+// Package cli provides the trace command for the enigoma CLI.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/coredds/enigoma/pkg/enigma"
+	"github.com/spf13/cobra"
+)
+
+// newTraceCommand creates the trace command.
+func newTraceCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trace",
+		Short: "Show the signal path of every character through the machine",
+		Long: `Encrypt text one key press at a time and show where the signal goes.
+
+Each row is one character: the key pressed, the rotor windows after the
+rotors stepped, the contact the signal leaves each component by, and the lamp
+that lights. Columns follow the signal: plugboard (or Uhr), entry wheel,
+the rotors from right to left, the reflector, the rotors from left to right,
+and back out. Rotor columns are headed by rotor ID.
+
+The machine is not changed: keys are read, never written.
+
+Examples:
+  enigoma trace --preset m3 --positions AAA --text HELLO
+  enigoma trace --config key.json --text "ATTACK AT DAWN"
+  enigoma trace --key alice --text HELLO`,
+		Args: cobra.NoArgs,
+		RunE: runTrace,
+	}
+
+	cmd.Flags().StringP("text", "t", "", "Text to trace")
+	cmd.Flags().StringP("preset", "p", "m3", fmt.Sprintf("Machine to trace (%s); ignored with --config", strings.Join(presetNames(), ", ")))
+	cmd.Flags().StringSlice("positions", nil, "Starting rotor positions (e.g., AAA; default: the machine's own)")
+	addNotationFlag(cmd, notationLetters)
+
+	return cmd
+}
+
+func runTrace(cmd *cobra.Command, args []string) error {
+	text, _ := cmd.Flags().GetString("text")
+	if text == "" {
+		return fmt.Errorf("trace requires --text")
+	}
+
+	var (
+		machine *enigma.Enigma
+		err     error
+	)
+	if configFile, _ := cmd.Flags().GetString("config"); configFile != "" {
+		machine, err = createMachineFromConfig(fileSystem(cmd), configOptionsFor(cmd), configFile)
+	} else {
+		preset, _ := cmd.Flags().GetString("preset")
+		machine, err = presetMachine(cmd, preset)
+	}
+	if err != nil {
+		return err
+	}
+	if err := reportMachineWarnings(cmd, machine); err != nil {
+		return err
+	}
+	if err := applyPositionFlags(cmd, machine, "positions"); err != nil {
+		return err
+	}
+
+	n, err := getNotationFromFlag(cmd)
+	if err != nil {
+		return err
+	}
+	settings, err := machine.GetSettings()
+	if err != nil {
+		return fmt.Errorf("failed to read machine settings: %v", err)
+	}
+	traces, output, err := machine.EncryptTraced(text)
+	if err != nil {
+		return err
+	}
+
+	writeTraceTable(cmd.OutOrStdout(), traces, settings, n)
+	fmt.Fprintf(cmd.OutOrStdout(), "\nOutput: %s\n", output)
+	return nil
+}
+
+// writeTraceTable prints one row per traced character, with a column for
+// each stage of the signal path.
+func writeTraceTable(out io.Writer, traces []enigma.TraceStep, settings *enigma.EnigmaSettings, n positionNotation) {
+	if len(traces) == 0 {
+		return
+	}
+
+	header := []string{"#", "KEY", "ROTORS"}
+	for _, stage := range traces[0].Stages {
+		header = append(header, traceStageLabel(stage, settings))
+	}
+	header = append(header, "LAMP")
+
+	rows := make([][]string, len(traces))
+	for i, step := range traces {
+		row := []string{strconv.Itoa(step.Index + 1), string(step.Input), n.formatPositions(step.Positions, settings.Alphabet)}
+		for _, stage := range step.Stages {
+			row = append(row, string(settings.Alphabet[stage.Output]))
+		}
+		rows[i] = append(row, string(step.Output))
+	}
+
+	widths := make([]int, len(header))
+	for _, row := range append([][]string{header}, rows...) {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+	for _, row := range append([][]string{header}, rows...) {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = cell + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+		}
+		fmt.Fprintln(out, strings.TrimRight(strings.Join(cells, "  "), " "))
+	}
+}
+
+// traceStageLabel names the column of a stage: the rotor or reflector ID, or
+// the component.
+func traceStageLabel(stage enigma.TraceStage, settings *enigma.EnigmaSettings) string {
+	switch stage.Kind {
+	case enigma.TracePlugboard, enigma.TracePlugboardReturn:
+		if settings.Uhr {
+			return "UHR"
+		}
+		return "PLUG"
+	case enigma.TraceEntryWheel, enigma.TraceEntryWheelReturn:
+		return "ETW"
+	case enigma.TraceRotorForward, enigma.TraceRotorBackward:
+		return settings.RotorSpecs[stage.Rotor].ID
+	case enigma.TraceReflector:
+		return settings.ReflectorSpec.ID
+	default:
+		return stage.Kind.String()
+	}
+}
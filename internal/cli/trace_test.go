@@ -0,0 +1,54 @@
+// Package cli provides unit tests for the trace command.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTraceCommand(t *testing.T) {
+	fsys := NewMemFS()
+	out, err := runCLI(fsys, "trace", "--preset", "m3", "--positions", "AAA", "--text", "AA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("expected a header, two rows and the output, got:\n%s", out)
+	}
+	if got := strings.Fields(lines[0]); strings.Join(got, " ") != "# KEY ROTORS PLUG III II I B I II III PLUG LAMP" {
+		t.Errorf("header = %v", got)
+	}
+	// The textbook path of A with rotors I II III at AAB
+	if got := strings.Join(strings.Fields(lines[1]), " "); got != "1 A AAB A C D F S S E B B B" {
+		t.Errorf("first row = %q", got)
+	}
+	if lines[4] != "Output: BD" {
+		t.Errorf("last line = %q, want Output: BD", lines[4])
+	}
+
+	// Tracing never writes the key
+	if _, err := runCLI(fsys, "keygen", "--preset", "m3", "--output", "key.json"); err != nil {
+		t.Fatal(err)
+	}
+	before, _ := fsys.ReadFile("key.json")
+	if _, err := runCLI(fsys, "trace", "--config", "key.json", "--text", "HELLO"); err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := fsys.ReadFile("key.json"); string(after) != string(before) {
+		t.Error("trace changed the key")
+	}
+
+	for _, args := range [][]string{
+		{"trace"},
+		{"trace", "--text", "hello"},
+		{"trace", "--preset", "nope", "--text", "A"},
+	} {
+		if _, err := runCLI(fsys, args...); err == nil {
+			t.Errorf("%v: expected an error", args)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// Package cli provides CJK transliteration for the encrypt command.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/coredds/enigoma/pkg/enigma"
+	"github.com/coredds/enigoma/pkg/translit"
+	"github.com/spf13/cobra"
+)
+
+// warnTransliterationIncomplete flags CJK characters the transliterator could not read.
+const warnTransliterationIncomplete enigma.WarningCode = "transliteration_incomplete"
+
+// addTransliterateFlags registers the transliteration flags.
+func addTransliterateFlags(cmd *cobra.Command) {
+	cmd.Flags().String("transliterate", "", "Romanize CJK input before encrypting ("+strings.Join(translit.Names(), ", ")+", pinyin); one-way")
+	cmd.Flags().String("transliterate-table", "", "Reading table for --transliterate (required for pinyin): lines of 'character reading'")
+}
+
+// applyTransliteration romanizes text when --transliterate is set. The result
+// decrypts to the romanized text, not the original script.
+func applyTransliteration(cmd *cobra.Command, text string) (string, error) {
+	name, _ := cmd.Flags().GetString("transliterate")
+	tablePath, _ := cmd.Flags().GetString("transliterate-table")
+	if name == "" {
+		if tablePath != "" {
+			return "", fmt.Errorf("--transliterate-table needs --transliterate")
+		}
+		return text, nil
+	}
+
+	var t translit.Transliterator
+	if tablePath != "" {
+		data, err := fileSystem(cmd).ReadFile(tablePath)
+		if err != nil {
+			return "", fmt.Errorf("failed to read transliteration table: %v", err)
+		}
+		table, err := translit.ParseTable(name, bytes.NewReader(data))
+		if err != nil {
+			return "", fmt.Errorf("invalid transliteration table %s: %v", tablePath, err)
+		}
+		t = table
+	} else if registered, ok := translit.Lookup(name); ok {
+		t = registered
+	} else if name == "pinyin" {
+		return "", fmt.Errorf("pinyin needs a reading table: add --transliterate-table with lines like '中 zhong' (e.g. generated from Unihan kMandarin)")
+	} else {
+		return "", fmt.Errorf("unknown transliterator: %s. Available: %s, or any name with --transliterate-table", name, strings.Join(translit.Names(), ", "))
+	}
+
+	result, err := t.Transliterate(text)
+	if err != nil {
+		return "", fmt.Errorf("transliteration failed: %v", err)
+	}
+
+	if v, _ := cmd.Flags().GetBool("verbose"); v {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Transliterated (%s): %s\n", t.Name(), quotedPreview(cmd, result, 40))
+	}
+	if n := translit.Remaining(result); n > 0 {
+		if err := reportWarnings(cmd, enigma.Warning{
+			Code:    warnTransliterationIncomplete,
+			Message: fmt.Sprintf("%s left %d CJK character(s) unread; they stay in the text and enlarge the alphabet", t.Name(), n),
+		}); err != nil {
+			return "", err
+		}
+	}
+	return result, nil
+}
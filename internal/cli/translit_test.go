@@ -0,0 +1,61 @@
+// Package cli provides unit tests for CJK transliteration.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestEncryptTransliterate(t *testing.T) {
+	fsys := NewMemFS()
+	run := func(args ...string) (string, string, error) {
+		t.Helper()
+		var stdout, stderr bytes.Buffer
+		cmd := NewRootCommand(Options{FS: fsys})
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&stderr)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return strings.TrimSuffix(stdout.String(), "\n"), stderr.String(), err
+	}
+
+	cipher, _, err := run("encrypt", "--text", "こんにちは", "--transliterate", "romaji", "--auto-config", "ja.json")
+	if err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	plain, _, err := run("decrypt", "--text", cipher, "--config", "ja.json", "--no-heuristics")
+	if err != nil || plain != "konnichiha" {
+		t.Errorf("expected romaji round trip, got %q (%v)", plain, err)
+	}
+
+	if err := fsys.WriteFile("pinyin.txt", []byte("中 zhong\n国 guo\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cipher, _, err = run("encrypt", "--text", "中国", "--transliterate", "pinyin", "--transliterate-table", "pinyin.txt", "--auto-config", "zh.json")
+	if err != nil {
+		t.Fatalf("pinyin encrypt failed: %v", err)
+	}
+	plain, _, _ = run("decrypt", "--text", cipher, "--config", "zh.json", "--no-heuristics")
+	if plain != "zhong guo" {
+		t.Errorf("expected pinyin round trip, got %q", plain)
+	}
+
+	_, stderr, err := run("encrypt", "--text", "日本です", "--transliterate", "romaji", "--auto-config", "k.json", "--warnings-format", "json")
+	if err != nil || !strings.Contains(stderr, `"code":"transliteration_incomplete"`) {
+		t.Errorf("expected an incomplete transliteration warning, got %q (%v)", stderr, err)
+	}
+
+	for _, args := range [][]string{
+		{"--transliterate", "pinyin"},
+		{"--transliterate", "klingon"},
+		{"--transliterate-table", "pinyin.txt"},
+	} {
+		if _, _, err := run(append([]string{"encrypt", "--text", "中国", "--auto-config", "x.json"}, args...)...); err == nil {
+			t.Errorf("%v: expected an error", args)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+// Package cli provides terminal detection shared by the input and output helpers.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+// isTerminal reports whether w is an interactive terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && fileIsTerminal(f)
+}
+
+// isTerminalInput reports whether r is an interactive terminal rather than a
+// pipe, a file or the null device, so reading it would wait for the user.
+func isTerminalInput(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	if !ok {
+		return false
+	}
+	if _, err := f.Stat(); err != nil {
+		// Nothing to read from a handle that cannot even be inspected
+		return true
+	}
+	return fileIsTerminal(f)
+}
+
+// isMSYSPipeName reports whether name is the named pipe MSYS2, Git Bash or
+// Cygwin terminals (mintty) attach programs to, such as
+// \msys-1888ae32e00d56aa-pty0-from-master. Native Windows programs see these
+// terminals as pipes, not consoles.
+func isMSYSPipeName(name string) bool {
+	parts := strings.Split(strings.TrimPrefix(name, `\`), "-")
+	if len(parts) < 5 {
+		return false
+	}
+	return (parts[0] == "msys" || parts[0] == "cygwin") &&
+		strings.HasPrefix(parts[2], "pty") &&
+		(parts[3] == "from" || parts[3] == "to") &&
+		parts[4] == "master"
+}
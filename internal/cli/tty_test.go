@@ -0,0 +1,75 @@
+// Package cli provides unit tests for terminal detection and path input.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIsMSYSPipeName(t *testing.T) {
+	for name, want := range map[string]bool{
+		`\msys-1888ae32e00d56aa-pty0-from-master`:     true,
+		`\msys-1888ae32e00d56aa-pty12-to-master`:      true,
+		`\cygwin-e022582115c10879-pty4-from-master`:   true,
+		`msys-1888ae32e00d56aa-pty0-to-master`:        true,
+		`\msys-1888ae32e00d56aa-pty0-to-master-nat`:   true,
+		`\msys-1888ae32e00d56aa-pipe-0x1A2B`:          false,
+		`\mingw-1888ae32e00d56aa-pty0-from-master`:    false,
+		`\Device\NamedPipe\msys-1888ae32e00d56aa-pty`: false,
+		``: false,
+	} {
+		if got := isMSYSPipeName(name); got != want {
+			t.Errorf("isMSYSPipeName(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestIsTerminalInput(t *testing.T) {
+	if isTerminalInput(strings.NewReader("text")) {
+		t.Error("a reader is not a terminal")
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+	if isTerminalInput(r) {
+		t.Error("a pipe is not a terminal")
+	}
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "input.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if isTerminalInput(f) || isTerminal(f) {
+		t.Error("a regular file is not a terminal")
+	}
+
+	// A closed file cannot be read, so it is not waited on either
+	f.Close()
+	if !isTerminalInput(f) {
+		t.Error("an unreadable handle must not be read as piped input")
+	}
+}
+
+func TestTrimPathInput(t *testing.T) {
+	for in, want := range map[string]string{
+		"key.json\r\n": "key.json",
+		`  "C:\Users\Ada\My Keys\k.json"` + "\r\n": `C:\Users\Ada\My Keys\k.json`,
+		"'/home/ada/my keys/k.json'\n":             "/home/ada/my keys/k.json",
+		`"unbalanced.json`:                         `"unbalanced.json`,
+		`""`:                                       "",
+	} {
+		if got := trimPathInput(in); got != want {
+			t.Errorf("trimPathInput(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+//go:build !windows
+
+// Package cli provides terminal detection for Unix-like systems.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import "os"
+
+// fileIsTerminal reports whether f is a character device such as a TTY.
+func fileIsTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
+
+// enableTerminalStyle prepares terminal f for ANSI escape sequences. Unix
+// terminals always understand them.
+func enableTerminalStyle(f *os.File) bool {
+	return true
+}
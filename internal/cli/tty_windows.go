@@ -0,0 +1,76 @@
+//go:build windows
+
+// Package cli provides terminal detection for Windows consoles and MSYS
+// terminals.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"os"
+	"syscall"
+	"unicode/utf16"
+	"unsafe"
+)
+
+const (
+	// enableVirtualTerminalProcessing makes a console interpret ANSI escape
+	// sequences (Windows 10 and later).
+	enableVirtualTerminalProcessing = 0x0004
+	// fileNameInfo is the FILE_INFO_BY_HANDLE_CLASS of FILE_NAME_INFO.
+	fileNameInfo = 2
+)
+
+var (
+	kernel32                         = syscall.NewLazyDLL("kernel32.dll")
+	procSetConsoleMode               = kernel32.NewProc("SetConsoleMode")
+	procGetFileInformationByHandleEx = kernel32.NewProc("GetFileInformationByHandleEx")
+)
+
+// fileIsTerminal reports whether f is a console (cmd, PowerShell, Windows
+// Terminal) or an MSYS terminal. The null device is a character device but
+// not a console, so "< NUL" counts as empty piped input.
+func fileIsTerminal(f *os.File) bool {
+	h := syscall.Handle(f.Fd())
+	var mode uint32
+	if syscall.GetConsoleMode(h, &mode) == nil {
+		return true
+	}
+	if t, err := syscall.GetFileType(h); err != nil || t != syscall.FILE_TYPE_PIPE {
+		return false
+	}
+	return isMSYSPipeName(pipeName(h))
+}
+
+// pipeName returns the name of the pipe behind h, or "" if it has none.
+func pipeName(h syscall.Handle) string {
+	// FILE_NAME_INFO: a DWORD byte length followed by the UTF-16 name
+	buf := make([]uint16, 2+syscall.MAX_PATH)
+	r, _, _ := procGetFileInformationByHandleEx.Call(uintptr(h), fileNameInfo,
+		uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)*2))
+	if r == 0 {
+		return ""
+	}
+	n := int(*(*uint32)(unsafe.Pointer(&buf[0])) / 2)
+	if n > len(buf)-2 {
+		return ""
+	}
+	return string(utf16.Decode(buf[2 : 2+n]))
+}
+
+// enableTerminalStyle turns on ANSI escape sequences for console f and
+// reports whether the console supports them. Consoles older than Windows 10
+// do not, and print the sequences as text. MSYS terminals always do.
+func enableTerminalStyle(f *os.File) bool {
+	h := syscall.Handle(f.Fd())
+	var mode uint32
+	if syscall.GetConsoleMode(h, &mode) != nil {
+		return true
+	}
+	if mode&enableVirtualTerminalProcessing != 0 {
+		return true
+	}
+	r, _, _ := procSetConsoleMode.Call(uintptr(h), uintptr(mode|enableVirtualTerminalProcessing))
+	return r != 0
+}
@@ -0,0 +1,81 @@
+// Package cli provides simulated operator typing for demo output.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"fmt"
+	"io"
+	mrand "math/rand"
+	"time"
+
+	"github.com/coredds/enigoma/pkg/enigma"
+)
+
+// typingSimulator paces per-character output to mimic an operator at the keyboard.
+// It is driven by the machine's step callback, so the engine itself never sleeps.
+type typingSimulator struct {
+	out            io.Writer
+	charsPerSecond float64
+	jitter         float64 // Fraction of the base delay to randomly add or remove (0-1)
+	sleep          func(time.Duration)
+	rng            *mrand.Rand
+}
+
+// newTypingSimulator creates a simulator writing to out at the given rate.
+// A non-positive rate disables pacing entirely.
+func newTypingSimulator(out io.Writer, charsPerSecond, jitter float64) *typingSimulator {
+	if jitter < 0 {
+		jitter = 0
+	}
+	if jitter > 1 {
+		jitter = 1
+	}
+	return &typingSimulator{
+		out:            out,
+		charsPerSecond: charsPerSecond,
+		jitter:         jitter,
+		sleep:          time.Sleep,
+		rng:            mrand.New(mrand.NewSource(time.Now().UnixNano())), // #nosec G404 - cosmetic timing only
+	}
+}
+
+// enabled reports whether the simulator should pace output at all.
+func (t *typingSimulator) enabled() bool {
+	return t != nil && t.charsPerSecond > 0
+}
+
+// delay returns the pause before the next keystroke, including jitter.
+func (t *typingSimulator) delay() time.Duration {
+	if !t.enabled() {
+		return 0
+	}
+	base := float64(time.Second) / t.charsPerSecond
+	if t.jitter > 0 {
+		base += base * t.jitter * (2*t.rng.Float64() - 1)
+	}
+	return time.Duration(base)
+}
+
+// callback returns a step callback that echoes each output character and waits.
+func (t *typingSimulator) callback() enigma.StepCallback {
+	return func(info enigma.StepInfo) {
+		fmt.Fprintf(t.out, "%c", info.Output)
+		t.sleep(t.delay())
+	}
+}
+
+// typeOut runs fn with the simulator attached to machine, so its output appears
+// one keystroke at a time. The callback is detached afterwards.
+func (t *typingSimulator) typeOut(machine *enigma.Enigma, label string, fn func() (string, error)) (string, error) {
+	if !t.enabled() {
+		return fn()
+	}
+	fmt.Fprint(t.out, label)
+	machine.SetStepCallback(t.callback())
+	defer machine.SetStepCallback(nil)
+	result, err := fn()
+	fmt.Fprintln(t.out)
+	return result, err
+}
@@ -0,0 +1,75 @@
+// Package cli provides unit tests for the typing simulator.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/coredds/enigoma/pkg/enigma"
+)
+
+func TestTypingSimulatorDelay(t *testing.T) {
+	tests := []struct {
+		name   string
+		cps    float64
+		jitter float64
+		min    time.Duration
+		max    time.Duration
+	}{
+		{"disabled", 0, 0.5, 0, 0},
+		{"no jitter", 10, 0, 100 * time.Millisecond, 100 * time.Millisecond},
+		{"with jitter", 10, 0.5, 50 * time.Millisecond, 150 * time.Millisecond},
+		{"jitter clamped", 4, 5, 0, 500 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := newTypingSimulator(&bytes.Buffer{}, tt.cps, tt.jitter)
+			for i := 0; i < 50; i++ {
+				d := sim.delay()
+				if d < tt.min || d > tt.max {
+					t.Fatalf("delay %v outside [%v, %v]", d, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestTypingSimulatorTypeOut(t *testing.T) {
+	machine, err := enigma.NewEnigmaM3()
+	if err != nil {
+		t.Fatalf("Failed to create machine: %v", err)
+	}
+
+	var out bytes.Buffer
+	var slept []time.Duration
+	sim := newTypingSimulator(&out, 20, 0)
+	sim.sleep = func(d time.Duration) { slept = append(slept, d) }
+
+	result, err := sim.typeOut(machine, "> ", func() (string, error) {
+		return machine.Encrypt("ENIGMA")
+	})
+	if err != nil {
+		t.Fatalf("typeOut failed: %v", err)
+	}
+
+	if got, want := out.String(), "> "+result+"\n"; got != want {
+		t.Errorf("typed output = %q, want %q", got, want)
+	}
+	if len(slept) != len("ENIGMA") {
+		t.Errorf("expected %d pauses, got %d", len("ENIGMA"), len(slept))
+	}
+
+	// The callback must be detached afterwards
+	out.Reset()
+	if _, err := machine.Encrypt("A"); err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("callback still attached after typeOut: %q", out.String())
+	}
+}
@@ -19,12 +19,14 @@ func validateConfigFile(configPath string, cmd *cobra.Command) error {
 		return nil // No config file to validate
 	}
 
+	fsys, opts := fileSystem(cmd), configOptionsFor(cmd)
+
 	// Check if file exists
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+	if _, err := fsys.Stat(configPath); os.IsNotExist(err) {
 		// Try with .json extension
 		if !strings.HasSuffix(configPath, ".json") {
 			altPath := configPath + ".json"
-			if _, err := os.Stat(altPath); err == nil {
+			if _, err := fsys.Stat(altPath); err == nil {
 				configPath = altPath
 			} else {
 				return fmt.Errorf("configuration file not found: %s (also tried %s)", configPath, altPath)
@@ -34,20 +36,13 @@ func validateConfigFile(configPath string, cmd *cobra.Command) error {
 		}
 	}
 
-	// Try to load and validate the configuration
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		return fmt.Errorf("failed to read configuration file %s: %v", configPath, err)
-	}
-
 	// Attempt to create machine from config to validate
-	_, err = enigma.NewFromJSON(string(data))
-	if err != nil {
+	if _, err := loadConfig(fsys, opts, configPath); err != nil {
 		return fmt.Errorf("invalid configuration file %s: %v", configPath, err)
 	}
 
 	if verbose, _ := cmd.Flags().GetBool("verbose"); verbose {
-		fmt.Fprintf(cmd.ErrOrStderr(), "✅ Configuration file validated: %s\n", configPath)
+		fmt.Fprintf(uiErr(cmd), "✅ Configuration file validated: %s\n", configPath)
 	}
 
 	return nil
@@ -104,8 +99,10 @@ func prevalidateOperation(cmd *cobra.Command, text string) error {
 	if preset != "" && configFile == "" {
 		if needsPreprocessing(text) {
 			if verbose, _ := cmd.Flags().GetBool("verbose"); verbose {
-				fmt.Fprintf(cmd.ErrOrStderr(), "⚠️  Warning: Your text contains spaces/special characters.\n")
-				fmt.Fprintf(cmd.ErrOrStderr(), "   Consider using preprocessing flags or --auto-config instead.\n")
+				return reportWarnings(cmd, enigma.Warning{
+					Code:    warnInputNeedsPreprocessing,
+					Message: "Your text contains spaces/special characters. Consider using preprocessing flags or --auto-config instead.",
+				})
 			}
 		}
 	}
@@ -0,0 +1,55 @@
+// Package cli provides consistent rendering of library and CLI warnings.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/coredds/enigoma/pkg/enigma"
+	"github.com/spf13/cobra"
+)
+
+// warnInputNeedsPreprocessing flags preset input that likely needs cleaning up.
+const warnInputNeedsPreprocessing enigma.WarningCode = "input_needs_preprocessing"
+
+// jsonWarning is the machine-readable form of a warning, one object per line.
+type jsonWarning struct {
+	Type string `json:"type"`
+	enigma.Warning
+}
+
+// reportWarnings writes warnings to stderr in the format chosen with
+// --warnings-format: "text" (default), "json" (one object per line) or "none".
+func reportWarnings(cmd *cobra.Command, warnings ...enigma.Warning) error {
+	format, _ := cmd.Flags().GetString("warnings-format")
+	out := cmd.ErrOrStderr()
+
+	switch strings.ToLower(format) {
+	case "", "text":
+		ui := uiWriter(cmd, out)
+		for _, w := range warnings {
+			fmt.Fprintf(ui, "⚠️  %s %s\n", paint(colorYellow, "Warning:"), w.Message)
+		}
+	case "json":
+		for _, w := range warnings {
+			data, err := json.Marshal(jsonWarning{Type: "warning", Warning: w})
+			if err != nil {
+				return fmt.Errorf("failed to encode warning: %v", err)
+			}
+			fmt.Fprintln(out, string(data))
+		}
+	case "none":
+	default:
+		return fmt.Errorf("unknown --warnings-format: %s. Available: text, json, none", format)
+	}
+	return nil
+}
+
+// reportMachineWarnings renders the warnings a machine collected while it was built.
+func reportMachineWarnings(cmd *cobra.Command, machine *enigma.Enigma) error {
+	return reportWarnings(cmd, machine.Warnings()...)
+}
@@ -0,0 +1,60 @@
+// Package cli provides unit tests for warning rendering.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestWarningsFormat(t *testing.T) {
+	run := func(format string) (string, error) {
+		t.Helper()
+		var stderr bytes.Buffer
+		cmd := NewRootCommand(Options{FS: NewMemFS()})
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&stderr)
+		// Three distinct characters force a padding character into the alphabet
+		cmd.SetArgs([]string{"encrypt", "--text", "ABC", "--auto-config", "key.json", "--warnings-format", format})
+		err := cmd.Execute()
+		return stderr.String(), err
+	}
+
+	text, err := run("text")
+	if err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	if !strings.Contains(text, "Warning: added ' ' to the alphabet") {
+		t.Errorf("expected a text padding warning, got %q", text)
+	}
+
+	jsonOut, err := run("json")
+	if err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	var w struct {
+		Type    string `json:"type"`
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+	line, _, _ := strings.Cut(jsonOut, "\n")
+	if err := json.Unmarshal([]byte(line), &w); err != nil {
+		t.Fatalf("warning is not JSON: %q (%v)", jsonOut, err)
+	}
+	if w.Type != "warning" || w.Code != "alphabet_padded" || w.Message == "" {
+		t.Errorf("unexpected JSON warning: %+v", w)
+	}
+
+	none, err := run("none")
+	if err != nil || none != "" {
+		t.Errorf("--warnings-format none should be silent, got %q, %v", none, err)
+	}
+
+	if _, err := run("xml"); err == nil {
+		t.Error("unknown warnings format should fail")
+	}
+}
@@ -7,16 +7,20 @@ package cli
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
 )
 
-var wizardCmd = &cobra.Command{
-	Use:   "wizard",
-	Short: "Interactive wizard for beginners",
-	Long: `Interactive wizard to guide you through encrypting or decrypting text.
+// newWizardCommand creates the interactive wizard command.
+func newWizardCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wizard",
+		Short: "Interactive wizard for beginners",
+		Long: `Interactive wizard to guide you through encrypting or decrypting text.
 
 This wizard will ask you simple questions and generate the appropriate
 enigoma command for you. Perfect for beginners!
@@ -30,38 +34,38 @@ The wizard will:
 
 Example:
   enigoma wizard`,
-	RunE: runWizard,
-}
+		RunE: runWizard,
+	}
 
-func init() {
-	// Add wizard to root command in root.go
+	return cmd
 }
 
 func runWizard(cmd *cobra.Command, args []string) error {
-	fmt.Println("🔐 Welcome to the enigoma Interactive Wizard!")
-	fmt.Println("Let's help you encrypt or decrypt your text step by step.")
-	fmt.Println()
+	out := uiOut(cmd)
+	fmt.Fprintln(out, "🔐 Welcome to the enigoma Interactive Wizard!")
+	fmt.Fprintln(out, "Let's help you encrypt or decrypt your text step by step.")
+	fmt.Fprintln(out)
 
-	reader := bufio.NewReader(os.Stdin)
+	reader := bufio.NewReader(cmd.InOrStdin())
 
 	// Step 1: Choose operation
-	operation, err := askOperation(reader)
+	operation, err := askOperation(reader, out)
 	if err != nil {
 		return err
 	}
 
 	if operation == "encrypt" {
-		return runEncryptWizard(reader)
+		return runEncryptWizard(cmd, reader, out)
 	} else {
-		return runDecryptWizard(reader)
+		return runDecryptWizard(cmd, reader, out)
 	}
 }
 
-func askOperation(reader *bufio.Reader) (string, error) {
-	fmt.Println("📝 What would you like to do?")
-	fmt.Println("1) Encrypt text (turn readable text into secret code)")
-	fmt.Println("2) Decrypt text (turn secret code back into readable text)")
-	fmt.Print("\nEnter your choice (1 or 2): ")
+func askOperation(reader *bufio.Reader, out io.Writer) (string, error) {
+	fmt.Fprintln(out, "📝 What would you like to do?")
+	fmt.Fprintln(out, "1) Encrypt text (turn readable text into secret code)")
+	fmt.Fprintln(out, "2) Decrypt text (turn secret code back into readable text)")
+	fmt.Fprint(out, "\nEnter your choice (1 or 2): ")
 
 	choice, err := reader.ReadString('\n')
 	if err != nil {
@@ -75,29 +79,29 @@ func askOperation(reader *bufio.Reader) (string, error) {
 	case "2":
 		return "decrypt", nil
 	default:
-		fmt.Println("❌ Invalid choice. Please enter 1 or 2.")
-		return askOperation(reader) // Recursive retry
+		fmt.Fprintln(out, "❌ Invalid choice. Please enter 1 or 2.")
+		return askOperation(reader, out) // Recursive retry
 	}
 }
 
-func runEncryptWizard(reader *bufio.Reader) error {
-	fmt.Println("\n🔒 ENCRYPTION WIZARD")
-	fmt.Println("=====================")
+func runEncryptWizard(cmd *cobra.Command, reader *bufio.Reader, out io.Writer) error {
+	fmt.Fprintln(out, "\n🔒 ENCRYPTION WIZARD")
+	fmt.Fprintln(out, "=====================")
 
 	// Step 1: Get input text
-	inputText, inputFile, err := getWizardInputText(reader)
+	inputText, inputFile, err := getWizardInputText(fileSystem(cmd), reader, out)
 	if err != nil {
 		return err
 	}
 
 	// Step 2: Choose approach
-	approachChoice, err := getWizardApproach(reader)
+	approachChoice, err := getWizardApproach(reader, out)
 	if err != nil {
 		return err
 	}
 
 	// Step 3: Get configuration file name
-	configFile, err := getWizardConfigFile(reader)
+	configFile, err := getWizardConfigFile(reader, out)
 	if err != nil {
 		return err
 	}
@@ -118,18 +122,18 @@ func runEncryptWizard(reader *bufio.Reader) error {
 		cmdArgs = append(cmdArgs, "--auto-config", configFile)
 	case "2":
 		// Historical preset
-		preset := askPreset(reader)
+		preset := askPreset(reader, out)
 		cmdArgs = append(cmdArgs, "--preset", preset, "--save-config", configFile)
 
 		// Check if input has special characters
 		checkText := inputText
 		if inputText == "" {
 			// For file input, we'll trust the user or show a warning
-			fmt.Println("\n⚠️  Note: If your file contains spaces or special characters,")
-			fmt.Println("   the encryption might fail. Consider using auto-config instead.")
+			fmt.Fprintln(out, "\n⚠️  Note: If your file contains spaces or special characters,")
+			fmt.Fprintln(out, "   the encryption might fail. Consider using auto-config instead.")
 		} else if needsPreprocessing(checkText) {
-			fmt.Println("\n⚠️  Your text contains spaces or special characters.")
-			fmt.Println("   Adding preprocessing options to make it work with presets...")
+			fmt.Fprintln(out, "\n⚠️  Your text contains spaces or special characters.")
+			fmt.Fprintln(out, "   Adding preprocessing options to make it work with presets...")
 			if strings.Contains(checkText, " ") {
 				cmdArgs = append(cmdArgs, "--remove-spaces")
 			}
@@ -142,8 +146,8 @@ func runEncryptWizard(reader *bufio.Reader) error {
 		}
 	case "3":
 		// Custom settings
-		alphabet := askAlphabet(reader)
-		security, err := getWizardSecurityLevel(reader)
+		alphabet := askAlphabet(reader, out)
+		security, err := getWizardSecurityLevel(reader, out)
 		if err != nil {
 			return err
 		}
@@ -156,59 +160,58 @@ func runEncryptWizard(reader *bufio.Reader) error {
 	cmdArgs = append(cmdArgs, "--verbose")
 
 	// Execute command
-	fmt.Printf("\n🚀 Executing command: enigoma %s\n\n", strings.Join(cmdArgs, " "))
+	fmt.Fprintf(out, "\n🚀 Executing command: enigoma %s\n\n", strings.Join(cmdArgs, " "))
 
-	// Create and execute the encrypt command
-	encryptCmd.SetArgs(cmdArgs[1:]) // Remove 'encrypt' from args
-	err = encryptCmd.Execute()
-	if err != nil {
+	// Run the generated command in a fresh command tree sharing our IO
+	if err := runGeneratedCommand(cmd, cmdArgs); err != nil {
 		return fmt.Errorf("encryption failed: %v", err)
 	}
 
 	// Success message
-	fmt.Printf("\n✅ Success! Your text has been encrypted.\n")
-	fmt.Printf("📋 Configuration saved to: %s\n", configFile)
-	fmt.Printf("🔑 To decrypt later, use: enigoma decrypt --text \"ENCRYPTED_TEXT\" --config %s\n", configFile)
+	fmt.Fprintf(out, "\n✅ Success! Your text has been encrypted.\n")
+	fmt.Fprintf(out, "📋 Configuration saved to: %s\n", configFile)
+	fmt.Fprintf(out, "🔑 To decrypt later, use: enigoma decrypt --text \"ENCRYPTED_TEXT\" --config %s\n", configFile)
 
 	return nil
 }
 
-func runDecryptWizard(reader *bufio.Reader) error {
-	fmt.Println("\n🔓 DECRYPTION WIZARD")
-	fmt.Println("====================")
+func runDecryptWizard(cmd *cobra.Command, reader *bufio.Reader, out io.Writer) error {
+	fmt.Fprintln(out, "\n🔓 DECRYPTION WIZARD")
+	fmt.Fprintln(out, "====================")
 
 	// Step 1: Get encrypted text
-	fmt.Println("\n📄 How would you like to provide the encrypted text?")
-	inputText, inputFile, err := getWizardInputText(reader)
+	fmt.Fprintln(out, "\n📄 How would you like to provide the encrypted text?")
+	inputText, inputFile, err := getWizardInputText(fileSystem(cmd), reader, out)
 	if err != nil {
 		return err
 	}
 
 	// Step 2: Get configuration file
-	fmt.Print("\n🔑 Enter the path to your configuration file (.json): ")
+	fmt.Fprint(out, "\n🔑 Enter the path to your configuration file (.json): ")
 	configFile, err := reader.ReadString('\n')
 	if err != nil {
 		return fmt.Errorf("failed to read config file path: %v", err)
 	}
-	configFile = strings.TrimSpace(configFile)
+	configFile = trimPathInput(configFile)
 
 	// Validate config file exists
-	if _, err := os.Stat(configFile); os.IsNotExist(err) {
+	fsys := fileSystem(cmd)
+	if _, err := fsys.Stat(configFile); os.IsNotExist(err) {
 		// Try with .json extension
 		if !strings.HasSuffix(configFile, ".json") {
 			configFile += ".json"
 		}
-		if _, err := os.Stat(configFile); os.IsNotExist(err) {
+		if _, err := fsys.Stat(configFile); os.IsNotExist(err) {
 			return fmt.Errorf("configuration file does not exist: %s", configFile)
 		}
 	}
 
 	// Step 3: Check input format
-	fmt.Println("\n📋 What format is your encrypted text in?")
-	fmt.Println("1) Plain text (default)")
-	fmt.Println("2) Hexadecimal (like: 48656c6c6f)")
-	fmt.Println("3) Base64 (like: SGVsbG8=)")
-	fmt.Print("\nEnter your choice (1, 2, or 3): ")
+	fmt.Fprintln(out, "\n📋 What format is your encrypted text in?")
+	fmt.Fprintln(out, "1) Plain text (default)")
+	fmt.Fprintln(out, "2) Hexadecimal (like: 48656c6c6f)")
+	fmt.Fprintln(out, "3) Base64 (like: SGVsbG8=)")
+	fmt.Fprint(out, "\nEnter your choice (1, 2, or 3): ")
 
 	formatChoice, err := reader.ReadString('\n')
 	if err != nil {
@@ -244,65 +247,66 @@ func runDecryptWizard(reader *bufio.Reader) error {
 	cmdArgs = append(cmdArgs, "--verbose")
 
 	// Execute command
-	fmt.Printf("\n🚀 Executing command: enigoma %s\n\n", strings.Join(cmdArgs, " "))
+	fmt.Fprintf(out, "\n🚀 Executing command: enigoma %s\n\n", strings.Join(cmdArgs, " "))
 
-	// Create and execute the decrypt command
-	decryptCmd.SetArgs(cmdArgs[1:]) // Remove 'decrypt' from args
-	err = decryptCmd.Execute()
-	if err != nil {
+	// Run the generated command in a fresh command tree sharing our IO
+	if err := runGeneratedCommand(cmd, cmdArgs); err != nil {
 		return fmt.Errorf("decryption failed: %v", err)
 	}
 
-	fmt.Println("\n✅ Decryption completed!")
+	fmt.Fprintln(out, "\n✅ Decryption completed!")
 	return nil
 }
 
-func askPreset(reader *bufio.Reader) string {
-	fmt.Println("\n🎨 Choose a historical preset:")
-	fmt.Println("1) classic - Traditional 3-rotor Enigma (low security)")
-	fmt.Println("2) m3 - Historically accurate Enigma M3")
-	fmt.Println("3) m4 - Historically accurate Naval Enigma M4")
-	fmt.Println("4) high - High security (8 rotors, 15 plugboard pairs)")
-	fmt.Println("5) extreme - Maximum security (12 rotors, 20 plugboard pairs)")
-	fmt.Print("\nEnter your choice (1-5): ")
+// runGeneratedCommand executes args (e.g. "encrypt --text ...") in a new
+// command tree that shares cmd's input and output streams and filesystem.
+func runGeneratedCommand(cmd *cobra.Command, args []string) error {
+	root := NewRootCommand(Options{
+		In:  cmd.InOrStdin(),
+		Out: cmd.OutOrStdout(),
+		Err: cmd.ErrOrStderr(),
+		FS:  fileSystem(cmd),
+	})
+	root.SetArgs(args)
+	return root.Execute()
+}
+
+// askPreset offers every preset of the preset table and returns the chosen name.
+func askPreset(reader *bufio.Reader, out io.Writer) string {
+	presets := getAvailablePresets()
+	fmt.Fprintln(out, "\n🎨 Choose a preset:")
+	for i, preset := range presets {
+		fmt.Fprintf(out, "%d) %s - %s (%d rotors, %d plugboard pairs)\n", i+1, preset.Name, preset.Description, preset.RotorCount, preset.PlugboardPairs)
+	}
+	fmt.Fprintf(out, "\nEnter your choice (1-%d): ", len(presets))
 
 	choice, err := reader.ReadString('\n')
 	if err != nil {
-		fmt.Println("Error reading input, defaulting to classic")
+		fmt.Fprintln(out, "Error reading input, defaulting to classic")
 		return "classic"
 	}
 
-	choice = strings.TrimSpace(choice)
-	switch choice {
-	case "1":
-		return "classic"
-	case "2":
-		return "m3"
-	case "3":
-		return "m4"
-	case "4":
-		return "high"
-	case "5":
-		return "extreme"
-	default:
-		fmt.Println("Invalid choice, defaulting to classic")
+	n, err := strconv.Atoi(strings.TrimSpace(choice))
+	if err != nil || n < 1 || n > len(presets) {
+		fmt.Fprintln(out, "Invalid choice, defaulting to classic")
 		return "classic"
 	}
+	return presets[n-1].Name
 }
 
-func askAlphabet(reader *bufio.Reader) string {
-	fmt.Println("\n🔤 Choose an alphabet:")
-	fmt.Println("1) auto - Automatically detect from your text (recommended)")
-	fmt.Println("2) latin - A-Z only (classic)")
-	fmt.Println("3) ascii - All printable characters (spaces, symbols, etc.)")
-	fmt.Println("4) alphanumeric - Letters and numbers only")
-	fmt.Println("5) greek - Greek alphabet")
-	fmt.Println("6) cyrillic - Cyrillic alphabet")
-	fmt.Print("\nEnter your choice (1-6): ")
+func askAlphabet(reader *bufio.Reader, out io.Writer) string {
+	fmt.Fprintln(out, "\n🔤 Choose an alphabet:")
+	fmt.Fprintln(out, "1) auto - Automatically detect from your text (recommended)")
+	fmt.Fprintln(out, "2) latin - A-Z only (classic)")
+	fmt.Fprintln(out, "3) ascii - All printable characters (spaces, symbols, etc.)")
+	fmt.Fprintln(out, "4) alphanumeric - Letters and numbers only")
+	fmt.Fprintln(out, "5) greek - Greek alphabet")
+	fmt.Fprintln(out, "6) cyrillic - Cyrillic alphabet")
+	fmt.Fprint(out, "\nEnter your choice (1-6): ")
 
 	choice, err := reader.ReadString('\n')
 	if err != nil {
-		fmt.Println("Error reading input, defaulting to auto")
+		fmt.Fprintln(out, "Error reading input, defaulting to auto")
 		return "auto"
 	}
 
@@ -321,7 +325,7 @@ func askAlphabet(reader *bufio.Reader) string {
 	case "6":
 		return "cyrillic"
 	default:
-		fmt.Println("Invalid choice, defaulting to auto")
+		fmt.Fprintln(out, "Invalid choice, defaulting to auto")
 		return "auto"
 	}
 }
@@ -340,11 +344,11 @@ func hasSpecialChars(text string) bool {
 }
 
 // getWizardInputText handles input text collection for the wizard
-func getWizardInputText(reader *bufio.Reader) (inputText, inputFile string, err error) {
-	fmt.Println("\n📄 How would you like to provide the text to encrypt?")
-	fmt.Println("1) Type it directly")
-	fmt.Println("2) Read from a file")
-	fmt.Print("\nEnter your choice (1 or 2): ")
+func getWizardInputText(fsys FS, reader *bufio.Reader, out io.Writer) (inputText, inputFile string, err error) {
+	fmt.Fprintln(out, "\n📄 How would you like to provide the text to encrypt?")
+	fmt.Fprintln(out, "1) Type it directly")
+	fmt.Fprintln(out, "2) Read from a file")
+	fmt.Fprint(out, "\nEnter your choice (1 or 2): ")
 
 	inputChoice, err := reader.ReadString('\n')
 	if err != nil {
@@ -354,7 +358,7 @@ func getWizardInputText(reader *bufio.Reader) (inputText, inputFile string, err
 	inputChoice = strings.TrimSpace(inputChoice)
 	switch inputChoice {
 	case "1":
-		fmt.Print("\n📝 Enter the text to encrypt: ")
+		fmt.Fprint(out, "\n📝 Enter the text to encrypt: ")
 		inputText, err = reader.ReadString('\n')
 		if err != nil {
 			return "", "", fmt.Errorf("failed to read text: %v", err)
@@ -362,15 +366,15 @@ func getWizardInputText(reader *bufio.Reader) (inputText, inputFile string, err
 		inputText = strings.TrimSpace(inputText)
 		return inputText, "", nil
 	case "2":
-		fmt.Print("\n📁 Enter the file path: ")
+		fmt.Fprint(out, "\n📁 Enter the file path: ")
 		inputFile, err = reader.ReadString('\n')
 		if err != nil {
 			return "", "", fmt.Errorf("failed to read file path: %v", err)
 		}
-		inputFile = strings.TrimSpace(inputFile)
+		inputFile = trimPathInput(inputFile)
 
 		// Validate file exists
-		if _, err := os.Stat(inputFile); os.IsNotExist(err) {
+		if _, err := fsys.Stat(inputFile); os.IsNotExist(err) {
 			return "", "", fmt.Errorf("file does not exist: %s", inputFile)
 		}
 		return "", inputFile, nil
@@ -380,13 +384,13 @@ func getWizardInputText(reader *bufio.Reader) (inputText, inputFile string, err
 }
 
 // getWizardSecurityLevel handles security level selection for the wizard
-func getWizardSecurityLevel(reader *bufio.Reader) (string, error) {
-	fmt.Println("\n🛡️ Choose security level:")
-	fmt.Println("1) Low (3 rotors, 2 plugboard pairs)")
-	fmt.Println("2) Medium (5 rotors, 8 plugboard pairs)")
-	fmt.Println("3) High (8 rotors, 15 plugboard pairs)")
-	fmt.Println("4) Extreme (12 rotors, 20 plugboard pairs)")
-	fmt.Print("\nEnter your choice (1-4): ")
+func getWizardSecurityLevel(reader *bufio.Reader, out io.Writer) (string, error) {
+	fmt.Fprintln(out, "\n🛡️ Choose security level:")
+	fmt.Fprintln(out, "1) Low (3 rotors, 2 plugboard pairs)")
+	fmt.Fprintln(out, "2) Medium (5 rotors, 8 plugboard pairs)")
+	fmt.Fprintln(out, "3) High (8 rotors, 15 plugboard pairs)")
+	fmt.Fprintln(out, "4) Extreme (12 rotors, 20 plugboard pairs)")
+	fmt.Fprint(out, "\nEnter your choice (1-4): ")
 
 	secChoice, err := reader.ReadString('\n')
 	if err != nil {
@@ -409,12 +413,12 @@ func getWizardSecurityLevel(reader *bufio.Reader) (string, error) {
 }
 
 // getWizardApproach handles approach selection for the wizard
-func getWizardApproach(reader *bufio.Reader) (string, error) {
-	fmt.Println("\n⚙️  Which approach would you prefer?")
-	fmt.Println("1) 🎯 Auto-config (recommended) - automatically detect the best settings")
-	fmt.Println("2) 🎨 Historical preset - use classic Enigma machine settings")
-	fmt.Println("3) 🔧 Custom settings - choose alphabet and security level manually")
-	fmt.Print("\nEnter your choice (1, 2, or 3): ")
+func getWizardApproach(reader *bufio.Reader, out io.Writer) (string, error) {
+	fmt.Fprintln(out, "\n⚙️  Which approach would you prefer?")
+	fmt.Fprintln(out, "1) 🎯 Auto-config (recommended) - automatically detect the best settings")
+	fmt.Fprintln(out, "2) 🎨 Preset - a historical machine or a ready-made configuration")
+	fmt.Fprintln(out, "3) 🔧 Custom settings - choose alphabet and security level manually")
+	fmt.Fprint(out, "\nEnter your choice (1, 2, or 3): ")
 
 	approachChoice, err := reader.ReadString('\n')
 	if err != nil {
@@ -425,8 +429,8 @@ func getWizardApproach(reader *bufio.Reader) (string, error) {
 }
 
 // getWizardConfigFile handles config file name input for the wizard
-func getWizardConfigFile(reader *bufio.Reader) (string, error) {
-	fmt.Print("\n💾 Enter a name for your configuration file (without extension): ")
+func getWizardConfigFile(reader *bufio.Reader, out io.Writer) (string, error) {
+	fmt.Fprint(out, "\n💾 Enter a name for your configuration file (without extension): ")
 	configName, err := reader.ReadString('\n')
 	if err != nil {
 		return "", fmt.Errorf("failed to read config name: %v", err)
@@ -437,3 +441,14 @@ func getWizardConfigFile(reader *bufio.Reader) (string, error) {
 	}
 	return configName + ".json", nil
 }
+
+// trimPathInput cleans up a typed or pasted file path: surrounding spaces,
+// the CR of a Windows line ending, and the quotes that Windows Explorer's
+// "Copy as path" and drag-and-drop into a terminal put around paths.
+func trimPathInput(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '"' && s[len(s)-1] == '"' || s[0] == '\'' && s[len(s)-1] == '\'') {
+		s = strings.TrimSpace(s[1 : len(s)-1])
+	}
+	return s
+}
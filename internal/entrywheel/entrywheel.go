@@ -0,0 +1,97 @@
+// Package entrywheel provides the entry wheel (Eintrittswalze) component for
+// the Enigma machine. It is the fixed wheel that connects the keyboard and
+// plugboard to the rotors.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package entrywheel
+
+import (
+	"fmt"
+
+	"github.com/coredds/enigoma/internal/alphabet"
+)
+
+// EntryWheel represents the entry wheel of an Enigma machine. The military
+// machines wired it in alphabet order, which makes it the identity; the
+// commercial and Abwehr machines wired it in keyboard order.
+type EntryWheel struct {
+	alphabet *alphabet.Alphabet
+	entry    []int // Contact each character enters on
+	exit     []int // Inverse of entry
+}
+
+// New creates an entry wheel from a wiring string that lists, for each
+// contact in alphabet order, the character wired to it. For example
+// "QWERTZUIOASDFGHJKPYXCVBNML" wires key Q to contact A.
+func New(alph *alphabet.Alphabet, wiring string) (*EntryWheel, error) {
+	if alph == nil {
+		return nil, fmt.Errorf("alphabet cannot be nil")
+	}
+	runes := []rune(wiring)
+	if len(runes) != alph.Size() {
+		return nil, fmt.Errorf("entry wheel wiring length (%d) must match alphabet size (%d)", len(runes), alph.Size())
+	}
+
+	w := &EntryWheel{
+		alphabet: alph,
+		entry:    make([]int, len(runes)),
+		exit:     make([]int, len(runes)),
+	}
+	used := make([]bool, len(runes))
+	for contact, r := range runes {
+		idx, err := alph.RuneToIndex(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid entry wheel wiring: %v", err)
+		}
+		if used[idx] {
+			return nil, fmt.Errorf("invalid entry wheel wiring: %c is wired twice", r)
+		}
+		used[idx] = true
+		w.entry[idx] = contact
+		w.exit[contact] = idx
+	}
+	return w, nil
+}
+
+// Enter returns the contact a character index enters the rotors on.
+func (w *EntryWheel) Enter(idx int) int {
+	if idx < 0 || idx >= len(w.entry) {
+		return idx // Invalid input, return as-is
+	}
+	return w.entry[idx]
+}
+
+// Leave returns the character index for the contact the signal leaves on.
+func (w *EntryWheel) Leave(contact int) int {
+	if contact < 0 || contact >= len(w.exit) {
+		return contact // Invalid input, return as-is
+	}
+	return w.exit[contact]
+}
+
+// IsIdentity reports whether every character enters on its own contact.
+func (w *EntryWheel) IsIdentity() bool {
+	for idx, contact := range w.entry {
+		if idx != contact {
+			return false
+		}
+	}
+	return true
+}
+
+// Wiring returns the wiring in the form New takes.
+func (w *EntryWheel) Wiring() string {
+	runes := make([]rune, len(w.exit))
+	for contact, idx := range w.exit {
+		runes[contact], _ = w.alphabet.IndexToRune(idx)
+	}
+	return string(runes)
+}
+
+// Clone creates a copy of the entry wheel. The wiring never changes after
+// New, so the copy shares it.
+func (w *EntryWheel) Clone() *EntryWheel {
+	clone := *w
+	return &clone
+}
@@ -0,0 +1,64 @@
+package entrywheel
+
+import (
+	"testing"
+
+	"github.com/coredds/enigoma/internal/alphabet"
+)
+
+func createTestAlphabet() *alphabet.Alphabet {
+	alph, _ := alphabet.New([]rune{'A', 'B', 'C', 'D'})
+	return alph
+}
+
+func TestNew(t *testing.T) {
+	alph := createTestAlphabet()
+	tests := []struct {
+		name      string
+		alphabet  *alphabet.Alphabet
+		wiring    string
+		wantError bool
+	}{
+		{"keyboard order", alph, "CADB", false},
+		{"identity", alph, "ABCD", false},
+		{"nil alphabet", nil, "ABCD", true},
+		{"too short", alph, "ABC", true},
+		{"wired twice", alph, "AACD", true},
+		{"unknown character", alph, "ABCX", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.alphabet, tt.wiring)
+			if (err != nil) != tt.wantError {
+				t.Errorf("New() error = %v, wantError %v", err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestEnterLeave(t *testing.T) {
+	w, err := New(createTestAlphabet(), "CADB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// C is wired to contact 0, A to 1, D to 2 and B to 3
+	for idx, want := range []int{1, 3, 0, 2} {
+		if got := w.Enter(idx); got != want {
+			t.Errorf("Enter(%d) = %d, want %d", idx, got, want)
+		}
+		if got := w.Leave(want); got != idx {
+			t.Errorf("Leave(%d) = %d, want %d", want, got, idx)
+		}
+	}
+	if w.IsIdentity() {
+		t.Error("CADB is not the identity")
+	}
+	if got := w.Clone().Wiring(); got != "CADB" {
+		t.Errorf("Wiring() = %q, want CADB", got)
+	}
+
+	identity, _ := New(createTestAlphabet(), "ABCD")
+	if !identity.IsIdentity() {
+		t.Error("ABCD should be the identity")
+	}
+}
@@ -0,0 +1,257 @@
+// Package grapheme splits text into extended grapheme clusters, the
+// user-perceived characters of Unicode text: a letter with its combining
+// accents, a flag, or an emoji with its skin tone and zero-width joiners.
+//
+// It implements the boundary rules of Unicode Standard Annex #29 using the
+// standard library's character tables. Two properties are approximated:
+// Extended_Pictographic is taken from the blocks emoji live in, and Prepend
+// characters (a few Arabic and Indic signs) start a cluster of their own.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package grapheme
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// class is the Grapheme_Cluster_Break property of a rune.
+type class int
+
+const (
+	classOther class = iota
+	classCR
+	classLF
+	classControl
+	classExtend
+	classZWJ
+	classRegional
+	classSpacingMark
+	classL
+	classV
+	classT
+	classLV
+	classLVT
+)
+
+const (
+	zwj  = '\u200d'
+	zwnj = '\u200c'
+
+	hangulBase  = 0xac00
+	hangulLast  = 0xd7a3
+	hangulTails = 28
+)
+
+// classify returns the Grapheme_Cluster_Break property of r.
+func classify(r rune) class {
+	switch {
+	case r == '\r':
+		return classCR
+	case r == '\n':
+		return classLF
+	case r == zwj:
+		return classZWJ
+	case r == zwnj:
+		return classExtend
+	case r >= 0x1f1e6 && r <= 0x1f1ff:
+		return classRegional
+	case r >= 0x1f3fb && r <= 0x1f3ff: // Emoji skin tone modifiers
+		return classExtend
+	case r >= 0xe0020 && r <= 0xe007f: // Tag characters of subdivision flags
+		return classExtend
+	case r == 0xff9e || r == 0xff9f: // Halfwidth katakana voiced sound marks
+		return classExtend
+	case r >= 0x1100 && r <= 0x115f, r >= 0xa960 && r <= 0xa97c:
+		return classL
+	case r >= 0x1160 && r <= 0x11a7, r >= 0xd7b0 && r <= 0xd7c6:
+		return classV
+	case r >= 0x11a8 && r <= 0x11ff, r >= 0xd7cb && r <= 0xd7fb:
+		return classT
+	case r >= hangulBase && r <= hangulLast:
+		if (r-hangulBase)%hangulTails == 0 {
+			return classLV
+		}
+		return classLVT
+	case unicode.In(r, unicode.Mn, unicode.Me):
+		return classExtend
+	case unicode.Is(unicode.Mc, r), r == 0x0e33, r == 0x0eb3:
+		return classSpacingMark
+	case unicode.In(r, unicode.Cc, unicode.Zl, unicode.Zp, unicode.Cf):
+		return classControl
+	}
+	return classOther
+}
+
+// isPictographic approximates Extended_Pictographic: the emoji and symbol
+// blocks, less the regional indicators and skin tone modifiers.
+func isPictographic(r rune) bool {
+	switch {
+	case r == 0x00a9, r == 0x00ae, r == 0x203c, r == 0x2049, r == 0x2122, r == 0x2139,
+		r >= 0x2194 && r <= 0x21aa, r >= 0x231a && r <= 0x23ff, r == 0x24c2,
+		r >= 0x25aa && r <= 0x25fe, r >= 0x2600 && r <= 0x27bf,
+		r >= 0x2934 && r <= 0x2935, r >= 0x2b05 && r <= 0x2b55,
+		r == 0x3030, r == 0x303d, r == 0x3297, r == 0x3299:
+		return true
+	case r >= 0x1f000 && r <= 0x1faff:
+		return classify(r) == classOther
+	case r >= 0x1fc00 && r <= 0x1fffd:
+		return true
+	}
+	return false
+}
+
+// segmenter holds the context the boundary rules look back at.
+type segmenter struct {
+	prev      class
+	started   bool
+	regionals int  // Regional indicators in a row up to prev
+	pict      bool // The cluster so far is a pictograph followed by Extend*
+	pictZWJ   bool // ... and then a zero-width joiner, ending at prev
+}
+
+// breaks reports whether a cluster boundary falls before r, and records r.
+func (s *segmenter) breaks(r rune) bool {
+	c := classify(r)
+	boundary := !s.started || s.boundary(c, r)
+
+	// Update the look-back state for the next rune
+	switch {
+	case c == classRegional:
+		if boundary {
+			s.regionals = 0
+		}
+		s.regionals++
+	default:
+		s.regionals = 0
+	}
+	switch {
+	case isPictographic(r):
+		s.pict, s.pictZWJ = true, false
+	case c == classExtend && s.pict && !s.pictZWJ && !boundary:
+		// Still a pictograph followed by Extend*
+	case c == classZWJ && s.pict && !s.pictZWJ && !boundary:
+		s.pictZWJ = true
+	default:
+		s.pict, s.pictZWJ = false, false
+	}
+	s.prev = c
+	s.started = true
+	return boundary
+}
+
+// boundary applies the rules GB3 to GB999 between the previous rune and one
+// of class c.
+func (s *segmenter) boundary(c class, r rune) bool {
+	p := s.prev
+	switch {
+	case p == classCR && c == classLF: // GB3
+		return false
+	case p == classCR, p == classLF, p == classControl: // GB4
+		return true
+	case c == classCR, c == classLF, c == classControl: // GB5
+		return true
+	case p == classL && (c == classL || c == classV || c == classLV || c == classLVT): // GB6
+		return false
+	case (p == classLV || p == classV) && (c == classV || c == classT): // GB7
+		return false
+	case (p == classLVT || p == classT) && c == classT: // GB8
+		return false
+	case c == classExtend, c == classZWJ, c == classSpacingMark: // GB9, GB9a
+		return false
+	case s.pictZWJ && p == classZWJ && isPictographic(r): // GB11
+		return false
+	case p == classRegional && c == classRegional: // GB12, GB13
+		return s.regionals%2 == 0
+	}
+	return true // GB999
+}
+
+// Split returns the grapheme clusters of s in order. Invalid UTF-8 bytes
+// become clusters of their own.
+func Split(s string) []string {
+	var clusters []string
+	var seg segmenter
+	start := 0
+	for i, r := range s {
+		if seg.breaks(r) && i > start {
+			clusters = append(clusters, s[start:i])
+			start = i
+		}
+	}
+	if start < len(s) {
+		clusters = append(clusters, s[start:])
+	}
+	return clusters
+}
+
+// Count returns the number of grapheme clusters in s.
+func Count(s string) int {
+	n := 0
+	var seg segmenter
+	for _, r := range s {
+		if seg.breaks(r) {
+			n++
+		}
+	}
+	return n
+}
+
+// IsCluster reports whether s is exactly one grapheme cluster.
+func IsCluster(s string) bool {
+	return s != "" && utf8.ValidString(s) && Count(s) == 1
+}
+
+// Joins reports whether clusters a and b would merge, or regroup, when b
+// follows a directly, so that splitting a+b no longer gives back a and b.
+func Joins(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	seg := tail(a)
+	r, _ := utf8.DecodeRuneInString(b)
+	return !seg.boundary(classify(r), r)
+}
+
+// FirstJoin finds two clusters, a and b, such that b joins a when it follows
+// it (see Joins), trying pairs in order. A set of clusters without such a
+// pair can be written in any order and split back into the same clusters.
+func FirstJoin(clusters []string) (a, b string, found bool) {
+	// Whether b joins a depends only on the state at the end of a and on the
+	// first rune of b, so each distinct combination is tried once
+	var tails []segmenter
+	tailOf := make(map[segmenter]string)
+	var firsts []rune
+	firstOf := make(map[rune]string)
+	for _, c := range clusters {
+		if c == "" {
+			continue
+		}
+		if seg := tail(c); tailOf[seg] == "" {
+			tails = append(tails, seg)
+			tailOf[seg] = c
+		}
+		if r, _ := utf8.DecodeRuneInString(c); firstOf[r] == "" {
+			firsts = append(firsts, r)
+			firstOf[r] = c
+		}
+	}
+	for _, seg := range tails {
+		for _, r := range firsts {
+			if !seg.boundary(classify(r), r) {
+				return tailOf[seg], firstOf[r], true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// tail returns the state of the segmenter after s.
+func tail(s string) segmenter {
+	var seg segmenter
+	for _, r := range s {
+		seg.breaks(r)
+	}
+	return seg
+}
@@ -0,0 +1,96 @@
+package grapheme
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"ascii", "AB C", []string{"A", "B", " ", "C"}},
+		{"combining accent", "cafe\u0301!", []string{"c", "a", "f", "e\u0301", "!"}},
+		{"several marks", "a\u0301\u0323b", []string{"a\u0301\u0323", "b"}},
+		{"crlf", "A\r\nB\n\r", []string{"A", "\r\n", "B", "\n", "\r"}},
+		{"skin tone", "👍\U0001f3fd👍", []string{"👍\U0001f3fd", "👍"}},
+		{"zwj sequence", "👨\u200d👩\u200d👧x", []string{"👨\u200d👩\u200d👧", "x"}},
+		{"zwj without pictograph", "a\u200db", []string{"a\u200d", "b"}},
+		{"flags", "🇫🇷🇩🇪🇺", []string{"🇫🇷", "🇩🇪", "🇺"}},
+		{"subdivision flag", "🏴\U000E0067\U000E0062\U000E0065\U000E006E\U000E0067\U000E007F.", []string{"🏴\U000E0067\U000E0062\U000E0065\U000E006E\U000E0067\U000E007F", "."}},
+		{"keycap", "1\ufe0f\u20e3#", []string{"1\ufe0f\u20e3", "#"}},
+		{"hangul syllables", "한국", []string{"한", "국"}},
+		{"hangul jamo", "\u1112\u1161\u11ab\u1100", []string{"\u1112\u1161\u11ab", "\u1100"}},
+		{"spacing mark", "नमस\u094dत\u0947", []string{"न", "म", "स\u094d", "त\u0947"}},
+		{"control", "a\x00\u0301", []string{"a", "\x00", "\u0301"}},
+		{"empty", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Split(tt.text)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Split(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+			if n := Count(tt.text); n != len(tt.want) {
+				t.Errorf("Count(%q) = %d, want %d", tt.text, n, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestIsCluster(t *testing.T) {
+	for s, want := range map[string]bool{
+		"A":               true,
+		"e\u0301":         true,
+		"🇫🇷":              true,
+		"👨\u200d👩\u200d👧": true,
+		"":                false,
+		"AB":              false,
+		"🇫🇷🇩🇪":            false,
+		"\xff":            false,
+		"\r\n":            true,
+		"\u0301":          true,
+		"e\u0301x":        false,
+	} {
+		if got := IsCluster(s); got != want {
+			t.Errorf("IsCluster(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
+
+func TestJoins(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"A", "B", false},
+		{"e", "\u0301", true},
+		{"\r", "\n", true},
+		{"🇫🇷", "🇩🇪", false},
+		{"🇺", "🇸", true},
+		{"👍", "\U0001f3fd", true},
+		{"👨\u200d", "👩", true},
+		{"a\u200d", "👩", false},
+		{"\u1100", "\u1161", true},
+	}
+	for _, tt := range tests {
+		if got := Joins(tt.a, tt.b); got != tt.want {
+			t.Errorf("Joins(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestFirstJoin(t *testing.T) {
+	if a, b, found := FirstJoin([]string{"A", "é", "🇫🇷", "👍\U0001f3fd"}); found {
+		t.Errorf("no clusters should join, got %q and %q", a, b)
+	}
+	a, b, found := FirstJoin([]string{"A", "\u0301", "B"})
+	if !found || b != "\u0301" {
+		t.Errorf("the lone accent should join the letters, got %q and %q (%v)", a, b, found)
+	}
+	if _, _, found := FirstJoin([]string{"🇺", "X"}); !found {
+		t.Error("a lone regional indicator should join itself")
+	}
+}
@@ -0,0 +1,156 @@
+//go:build ignore
+
+// gen generates tables.go from the Unicode Character Database:
+//
+//	go run gen.go -ucd /path/to/ucd
+//
+// The directory must hold UnicodeData.txt and CompositionExclusions.txt.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	ucd := flag.String("ucd", ".", "directory holding the Unicode Character Database files")
+	out := flag.String("o", "tables.go", "output file")
+	flag.Parse()
+
+	decompositions := map[rune][]rune{}
+	classes := map[rune]uint64{}
+	readLines(filepath.Join(*ucd, "UnicodeData.txt"), func(fields []string) {
+		if len(fields) < 6 {
+			return
+		}
+		r := parseRune(fields[0])
+		if ccc, err := strconv.ParseUint(fields[3], 10, 8); err == nil && ccc != 0 {
+			classes[r] = ccc
+		}
+		// Compatibility decompositions start with a <tag> and are not canonical
+		if d := fields[5]; d != "" && !strings.HasPrefix(d, "<") {
+			for _, cp := range strings.Fields(d) {
+				decompositions[r] = append(decompositions[r], parseRune(cp))
+			}
+		}
+	})
+	var exclusions []rune
+	readLines(filepath.Join(*ucd, "CompositionExclusions.txt"), func(fields []string) {
+		exclusions = append(exclusions, parseRune(fields[0]))
+	})
+
+	excluded := map[rune]bool{}
+	for _, r := range exclusions {
+		excluded[r] = true
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "// Code generated by gen.go from the Unicode Character Database. DO NOT EDIT.")
+	fmt.Fprintln(&buf)
+	fmt.Fprintln(&buf, "package normalize")
+	fmt.Fprintln(&buf)
+	fmt.Fprintln(&buf, "// decompositions lists the canonical decomposition of each rune, one level")
+	fmt.Fprintln(&buf, "// deep, sorted by rune.")
+	fmt.Fprintln(&buf, "var decompositions = []decomposition{")
+	for _, r := range sortedKeys(decompositions) {
+		fmt.Fprintf(&buf, "{0x%04X, %+q},\n", r, string(decompositions[r]))
+	}
+	fmt.Fprintln(&buf, "}")
+	fmt.Fprintln(&buf)
+	fmt.Fprintln(&buf, "// combiningClasses lists the non-zero Canonical_Combining_Class of each")
+	fmt.Fprintln(&buf, "// rune, sorted by rune.")
+	fmt.Fprintln(&buf, "var combiningClasses = []combiningClass{")
+	for _, r := range sortedKeys(classes) {
+		fmt.Fprintf(&buf, "{0x%04X, %d},\n", r, classes[r])
+	}
+	fmt.Fprintln(&buf, "}")
+	fmt.Fprintln(&buf)
+
+	// Primary composites: pairs of starters, leaving out singletons,
+	// non-starter decompositions and the listed exclusions
+	var pairs []composition
+	for r, d := range decompositions {
+		if len(d) == 2 && !excluded[r] && classes[r] == 0 && classes[d[0]] == 0 {
+			pairs = append(pairs, composition{d[0], d[1], r})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].first != pairs[j].first {
+			return pairs[i].first < pairs[j].first
+		}
+		return pairs[i].second < pairs[j].second
+	})
+	fmt.Fprintln(&buf, "// compositions lists the primary composite of each pair of runes, sorted by")
+	fmt.Fprintln(&buf, "// pair.")
+	fmt.Fprintln(&buf, "var compositions = []composition{")
+	for _, c := range pairs {
+		fmt.Fprintf(&buf, "{0x%04X, 0x%04X, 0x%04X},\n", c.first, c.second, c.composite)
+	}
+	fmt.Fprintln(&buf, "}")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// readLines calls fn with the semicolon-separated fields of every data line
+// of a UCD file, comments stripped.
+func readLines(path string, fn func(fields []string)) {
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields := strings.Split(line, ";")
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		fn(fields)
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+type composition struct {
+	first, second, composite rune
+}
+
+func parseRune(s string) rune {
+	cp, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		log.Fatalf("invalid code point %q: %v", s, err)
+	}
+	return rune(cp)
+}
+
+func sortedKeys[V any](m map[rune]V) []rune {
+	keys := make([]rune, 0, len(m))
+	for r := range m {
+		keys = append(keys, r)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
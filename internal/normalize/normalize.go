@@ -0,0 +1,180 @@
+// Package normalize converts text to the canonical Unicode normalization
+// forms NFC and NFD, so that a character written precomposed ("é" as U+00E9)
+// and decomposed ("e" followed by U+0301) compare equal.
+//
+// It implements the algorithms of Unicode Standard Annex #15 over tables
+// generated from the Unicode Character Database by gen.go; Hangul syllables
+// are composed and decomposed arithmetically. Compatibility forms (NFKC and
+// NFKD) are not supported.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package normalize
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+//go:generate go run gen.go -ucd $UCD_DIR
+
+// decomposition is the canonical decomposition of a rune, one level deep.
+type decomposition struct {
+	r     rune
+	runes string
+}
+
+// combiningClass is the Canonical_Combining_Class of a rune.
+type combiningClass struct {
+	r     rune
+	class uint8
+}
+
+// composition is the primary composite of a pair of runes.
+type composition struct {
+	first, second, composite rune
+}
+
+const (
+	hangulBase   = 0xac00
+	hangulCount  = 11172
+	leadBase     = 0x1100
+	vowelBase    = 0x1161
+	tailBase     = 0x11a7
+	vowelCount   = 21
+	tailCount    = 28
+	leadCount    = 19
+	hangulVowels = vowelCount * tailCount
+)
+
+// NFD returns s in Normalization Form D: fully decomposed and in canonical
+// order.
+func NFD(s string) string {
+	if isASCII(s) {
+		return s
+	}
+	return string(decompose(s))
+}
+
+// NFC returns s in Normalization Form C: decomposed, then recomposed into
+// precomposed characters wherever Unicode defines one.
+func NFC(s string) string {
+	if isASCII(s) {
+		return s
+	}
+	return string(compose(decompose(s)))
+}
+
+// isASCII reports whether s only holds ASCII, which every form leaves as is.
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// decompose returns the runes of s fully decomposed and in canonical order.
+func decompose(s string) []rune {
+	out := make([]rune, 0, len(s))
+	for _, r := range s {
+		out = appendDecomposed(out, r)
+	}
+	canonicalOrder(out)
+	return out
+}
+
+// appendDecomposed appends the full canonical decomposition of r to out.
+func appendDecomposed(out []rune, r rune) []rune {
+	if r >= hangulBase && r < hangulBase+hangulCount {
+		index := r - hangulBase
+		out = append(out, leadBase+index/hangulVowels, vowelBase+(index%hangulVowels)/tailCount)
+		if tail := index % tailCount; tail != 0 {
+			out = append(out, tailBase+tail)
+		}
+		return out
+	}
+	i := sort.Search(len(decompositions), func(i int) bool { return decompositions[i].r >= r })
+	if i == len(decompositions) || decompositions[i].r != r {
+		return append(out, r)
+	}
+	for _, d := range decompositions[i].runes {
+		out = appendDecomposed(out, d)
+	}
+	return out
+}
+
+// canonicalOrder sorts each run of combining marks by combining class,
+// keeping marks of the same class in order.
+func canonicalOrder(runes []rune) {
+	for i := 1; i < len(runes); i++ {
+		c := classOf(runes[i])
+		if c == 0 {
+			continue
+		}
+		for j := i; j > 0; j-- {
+			prev := classOf(runes[j-1])
+			if prev == 0 || prev <= c {
+				break
+			}
+			runes[j-1], runes[j] = runes[j], runes[j-1]
+		}
+	}
+}
+
+// compose combines decomposed runes in canonical order into primary
+// composites. A mark combines with the last starter unless a mark of the
+// same or a higher class, or another starter, comes between them.
+func compose(runes []rune) []rune {
+	out := runes[:0]
+	starter := -1
+	lastClass := uint8(0)
+	for _, r := range runes {
+		c := classOf(r)
+		if starter >= 0 {
+			blocked := starter != len(out)-1 && (lastClass == 0 || lastClass >= c)
+			if !blocked {
+				if composite, ok := compositeOf(out[starter], r); ok {
+					out[starter] = composite
+					continue
+				}
+			}
+		}
+		if c == 0 {
+			starter = len(out)
+		}
+		out = append(out, r)
+		lastClass = c
+	}
+	return out
+}
+
+// compositeOf returns the primary composite of first followed by second.
+func compositeOf(first, second rune) (rune, bool) {
+	// Hangul: a leading consonant and a vowel, then a trailing consonant
+	if first >= leadBase && first < leadBase+leadCount && second >= vowelBase && second < vowelBase+vowelCount {
+		return hangulBase + ((first-leadBase)*vowelCount+second-vowelBase)*tailCount, true
+	}
+	if first >= hangulBase && first < hangulBase+hangulCount && (first-hangulBase)%tailCount == 0 &&
+		second > tailBase && second < tailBase+tailCount {
+		return first + second - tailBase, true
+	}
+	i := sort.Search(len(compositions), func(i int) bool {
+		c := compositions[i]
+		return c.first > first || c.first == first && c.second >= second
+	})
+	if i < len(compositions) && compositions[i].first == first && compositions[i].second == second {
+		return compositions[i].composite, true
+	}
+	return 0, false
+}
+
+// classOf returns the Canonical_Combining_Class of r; starters have 0.
+func classOf(r rune) uint8 {
+	i := sort.Search(len(combiningClasses), func(i int) bool { return combiningClasses[i].r >= r })
+	if i < len(combiningClasses) && combiningClasses[i].r == r {
+		return combiningClasses[i].class
+	}
+	return 0
+}
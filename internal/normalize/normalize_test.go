@@ -0,0 +1,56 @@
+package normalize
+
+import "testing"
+
+func TestForms(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		nfc, nfd string
+	}{
+		{"ascii", "Hello, World!", "Hello, World!", "Hello, World!"},
+		{"precomposed", "caf\u00e9", "caf\u00e9", "cafe\u0301"},
+		{"decomposed", "cafe\u0301", "caf\u00e9", "cafe\u0301"},
+		{"no precomposed form", "n\u0303g\u0303", "\u00f1g\u0303", "n\u0303g\u0303"},
+		{"marks out of order", "a\u0301\u0323", "\u1ea1\u0301", "a\u0323\u0301"},
+		{"two levels", "\u1e09", "\u1e09", "c\u0327\u0301"},
+		{"blocked mark", "a\u0301\u0301", "\u00e1\u0301", "a\u0301\u0301"},
+		{"singleton", "\u212b", "\u00c5", "A\u030a"},
+		{"composition exclusion", "\u0958", "\u0915\u093c", "\u0915\u093c"},
+		{"non-starter decomposition", "\u0344", "\u0308\u0301", "\u0308\u0301"},
+		{"hangul syllable", "\ud55c\uad6d", "\ud55c\uad6d", "\u1112\u1161\u11ab\u1100\u116e\u11a8"},
+		{"hangul jamo", "\u1112\u1161\u11ab", "\ud55c", "\u1112\u1161\u11ab"},
+		{"emoji", "\U0001f44d\U0001f3fd", "\U0001f44d\U0001f3fd", "\U0001f44d\U0001f3fd"},
+		{"leading mark", "\u0301e", "\u0301e", "\u0301e"},
+		{"empty", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NFC(tt.text); got != tt.nfc {
+				t.Errorf("NFC(%+q) = %+q, want %+q", tt.text, got, tt.nfc)
+			}
+			if got := NFD(tt.text); got != tt.nfd {
+				t.Errorf("NFD(%+q) = %+q, want %+q", tt.text, got, tt.nfd)
+			}
+		})
+	}
+}
+
+func TestTablesSorted(t *testing.T) {
+	for i := 1; i < len(decompositions); i++ {
+		if decompositions[i-1].r >= decompositions[i].r {
+			t.Fatalf("decompositions are not sorted at %U", decompositions[i].r)
+		}
+	}
+	for i := 1; i < len(combiningClasses); i++ {
+		if combiningClasses[i-1].r >= combiningClasses[i].r {
+			t.Fatalf("combining classes are not sorted at %U", combiningClasses[i].r)
+		}
+	}
+	for i := 1; i < len(compositions); i++ {
+		a, b := compositions[i-1], compositions[i]
+		if a.first > b.first || a.first == b.first && a.second >= b.second {
+			t.Fatalf("compositions are not sorted at %U %U", b.first, b.second)
+		}
+	}
+}
@@ -6,18 +6,17 @@
 package plugboard
 
 import (
-	"crypto/rand"
 	"fmt"
-	"math/big"
 
 	"github.com/coredds/enigoma/internal/alphabet"
+	"github.com/coredds/enigoma/internal/random"
 )
 
 // Plugboard represents the plugboard component of an Enigma machine.
 // It implements reciprocal character swapping.
 type Plugboard struct {
 	alphabet *alphabet.Alphabet
-	mapping  map[int]int
+	wiring   []int       // Output for each input index; the identity when unplugged
 	pairs    map[int]int // For tracking which characters are paired
 	size     int
 }
@@ -30,7 +29,7 @@ func New(alph *alphabet.Alphabet) (*Plugboard, error) {
 
 	return &Plugboard{
 		alphabet: alph,
-		mapping:  make(map[int]int),
+		wiring:   identity(alph.Size()),
 		pairs:    make(map[int]int),
 		size:     alph.Size(),
 	}, nil
@@ -61,8 +60,8 @@ func (p *Plugboard) AddPair(r1, r2 rune) error {
 	}
 
 	// Add the reciprocal mapping
-	p.mapping[idx1] = idx2
-	p.mapping[idx2] = idx1
+	p.wiring[idx1] = idx2
+	p.wiring[idx2] = idx1
 	p.pairs[idx1] = idx2
 	p.pairs[idx2] = idx1
 
@@ -83,8 +82,8 @@ func (p *Plugboard) RemovePair(r rune) error {
 	}
 
 	// Remove the reciprocal mapping
-	delete(p.mapping, idx)
-	delete(p.mapping, partner)
+	p.wiring[idx] = idx
+	p.wiring[partner] = partner
 	delete(p.pairs, idx)
 	delete(p.pairs, partner)
 
@@ -93,7 +92,7 @@ func (p *Plugboard) RemovePair(r rune) error {
 
 // Clear removes all plugboard connections.
 func (p *Plugboard) Clear() {
-	p.mapping = make(map[int]int)
+	p.wiring = identity(p.size)
 	p.pairs = make(map[int]int)
 }
 
@@ -103,11 +102,7 @@ func (p *Plugboard) Process(inputIdx int) int {
 	if inputIdx < 0 || inputIdx >= p.size {
 		return inputIdx // Invalid input, return as-is
 	}
-
-	if output, exists := p.mapping[inputIdx]; exists {
-		return output
-	}
-	return inputIdx
+	return p.wiring[inputIdx]
 }
 
 // ProcessRune applies the plugboard mapping to a rune.
@@ -124,6 +119,12 @@ func (p *Plugboard) ProcessRune(r rune) (rune, error) {
 // RandomPairs generates n random reciprocal pairs on the plugboard.
 // This clears any existing pairs first.
 func (p *Plugboard) RandomPairs(n int) error {
+	return p.RandomPairsFrom(n, random.Crypto)
+}
+
+// RandomPairsFrom generates n random reciprocal pairs drawing from the given source.
+// This clears any existing pairs first.
+func (p *Plugboard) RandomPairsFrom(n int, src random.Source) error {
 	if n < 0 {
 		return fmt.Errorf("number of pairs cannot be negative")
 	}
@@ -136,23 +137,41 @@ func (p *Plugboard) RandomPairs(n int) error {
 	// Clear existing pairs
 	p.Clear()
 
+	return p.AddRandomPairsFrom(n, src)
+}
+
+// AddRandomPairs adds n random reciprocal pairs, keeping the existing ones.
+func (p *Plugboard) AddRandomPairs(n int) error {
+	return p.AddRandomPairsFrom(n, random.Crypto)
+}
+
+// AddRandomPairsFrom adds n random reciprocal pairs between characters that
+// are not plugged yet, drawing from the given source. Existing pairs are kept.
+func (p *Plugboard) AddRandomPairsFrom(n int, src random.Source) error {
+	if n < 0 {
+		return fmt.Errorf("number of pairs cannot be negative")
+	}
 	if n == 0 {
 		return nil
 	}
 
 	// Create list of available indices
-	available := make([]int, p.size)
+	available := make([]int, 0, p.size)
 	for i := 0; i < p.size; i++ {
-		available[i] = i
+		if _, plugged := p.pairs[i]; !plugged {
+			available = append(available, i)
+		}
+	}
+	if n > len(available)/2 {
+		return fmt.Errorf("cannot add %d pairs: only %d characters are unplugged (max %d more pairs)", n, len(available), len(available)/2)
 	}
 
 	// Shuffle the available indices
-	for i := p.size - 1; i > 0; i-- {
-		jBig, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+	for i := len(available) - 1; i > 0; i-- {
+		j, err := src.Intn(i + 1)
 		if err != nil {
 			return fmt.Errorf("failed to generate random number: %v", err)
 		}
-		j := int(jBig.Int64())
 		available[i], available[j] = available[j], available[i]
 	}
 
@@ -161,8 +180,8 @@ func (p *Plugboard) RandomPairs(n int) error {
 		idx1 := available[i]
 		idx2 := available[i+1]
 
-		p.mapping[idx1] = idx2
-		p.mapping[idx2] = idx1
+		p.wiring[idx1] = idx2
+		p.wiring[idx2] = idx1
 		p.pairs[idx1] = idx2
 		p.pairs[idx2] = idx1
 	}
@@ -202,7 +221,7 @@ func (p *Plugboard) GetPairs() ([][2]rune, error) {
 func (p *Plugboard) GetPairsMap() (map[rune]rune, error) {
 	result := make(map[rune]rune)
 
-	for idx1, idx2 := range p.mapping {
+	for idx1, idx2 := range p.pairs {
 		r1, err := p.alphabet.IndexToRune(idx1)
 		if err != nil {
 			return nil, err
@@ -256,18 +275,23 @@ func (p *Plugboard) PairCount() int {
 func (p *Plugboard) Clone() (*Plugboard, error) {
 	clone := &Plugboard{
 		alphabet: p.alphabet,
-		mapping:  make(map[int]int),
+		wiring:   append([]int(nil), p.wiring...),
 		pairs:    make(map[int]int),
 		size:     p.size,
 	}
 
-	for k, v := range p.mapping {
-		clone.mapping[k] = v
-	}
-
 	for k, v := range p.pairs {
 		clone.pairs[k] = v
 	}
 
 	return clone, nil
 }
+
+// identity returns the wiring of an unplugged board of size characters.
+func identity(size int) []int {
+	wiring := make([]int, size)
+	for i := range wiring {
+		wiring[i] = i
+	}
+	return wiring
+}
@@ -316,6 +316,37 @@ func TestPlugboard_RandomPairs(t *testing.T) {
 	}
 }
 
+func TestPlugboard_AddRandomPairs(t *testing.T) {
+	pb, err := New(createTestAlphabet())
+	if err != nil {
+		t.Fatalf("Failed to create plugboard: %v", err)
+	}
+	if err := pb.AddPair('A', 'F'); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := pb.AddRandomPairs(3); err == nil {
+		t.Error("AddRandomPairs(3) expected error with only 4 characters unplugged")
+	}
+	if err := pb.AddRandomPairs(-1); err == nil {
+		t.Error("AddRandomPairs(-1) expected error")
+	}
+	if err := pb.AddRandomPairs(2); err != nil {
+		t.Fatalf("AddRandomPairs(2) unexpected error: %v", err)
+	}
+	if pb.PairCount() != 3 {
+		t.Errorf("expected 3 pairs, got %d", pb.PairCount())
+	}
+	if out, _ := pb.ProcessRune('A'); out != 'F' {
+		t.Errorf("the fixed pair A-F was changed: A -> %c", out)
+	}
+	for idx := 0; idx < 6; idx++ {
+		if pb.Process(pb.Process(idx)) != idx || pb.Process(idx) == idx {
+			t.Errorf("character %d is not in a reciprocal pair", idx)
+		}
+	}
+}
+
 func TestPlugboard_GetPairs(t *testing.T) {
 	pb, err := New(createTestAlphabet())
 	if err != nil {
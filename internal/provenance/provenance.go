@@ -0,0 +1,58 @@
+// Package provenance records where the wiring of a machine component came
+// from, so hand-assembled machines can be audited.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package provenance
+
+import "strings"
+
+// SourceHistorical prefixes the Source of wirings taken from a historical
+// machine, as in "historical:M3/I".
+const SourceHistorical = "historical:"
+
+// Provenance describes the origin of a rotor or reflector wiring. All fields
+// are optional and none of them affect encryption.
+type Provenance struct {
+	Source    string `json:"source,omitempty"`     // Where the wiring came from, e.g. "historical:M3/I"
+	Creator   string `json:"creator,omitempty"`    // Who designed or assembled the component
+	CreatedAt string `json:"created_at,omitempty"` // RFC 3339 timestamp
+}
+
+// Historical returns the provenance of a wiring from a historical machine.
+func Historical(machine, component string) *Provenance {
+	return &Provenance{Source: SourceHistorical + machine + "/" + component}
+}
+
+// IsZero reports whether p is nil or has no field set.
+func (p *Provenance) IsZero() bool {
+	return p == nil || *p == Provenance{}
+}
+
+// Copy returns a copy of p, or nil when p is zero, so specs omit empty provenance.
+func (p *Provenance) Copy() *Provenance {
+	if p.IsZero() {
+		return nil
+	}
+	c := *p
+	return &c
+}
+
+// String renders the set fields on one line, e.g.
+// "historical:M3/I, by Alice, 2025-01-02T15:04:05Z".
+func (p *Provenance) String() string {
+	if p.IsZero() {
+		return ""
+	}
+	var parts []string
+	if p.Source != "" {
+		parts = append(parts, p.Source)
+	}
+	if p.Creator != "" {
+		parts = append(parts, "by "+p.Creator)
+	}
+	if p.CreatedAt != "" {
+		parts = append(parts, p.CreatedAt)
+	}
+	return strings.Join(parts, ", ")
+}
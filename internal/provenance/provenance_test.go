@@ -0,0 +1,32 @@
+package provenance
+
+import "testing"
+
+func TestProvenance(t *testing.T) {
+	var nilProv *Provenance
+	if !nilProv.IsZero() || !(&Provenance{}).IsZero() {
+		t.Error("nil and empty provenance should be zero")
+	}
+	if nilProv.Copy() != nil || (&Provenance{}).Copy() != nil {
+		t.Error("copying zero provenance should give nil")
+	}
+
+	p := Historical("M3", "I")
+	if p.Source != "historical:M3/I" {
+		t.Errorf("Historical() source = %q", p.Source)
+	}
+
+	p.Creator = "Alice"
+	p.CreatedAt = "2025-01-02T15:04:05Z"
+	c := p.Copy()
+	c.Creator = "Bob"
+	if p.Creator != "Alice" {
+		t.Error("Copy() should not share storage")
+	}
+	if got, want := p.String(), "historical:M3/I, by Alice, 2025-01-02T15:04:05Z"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if got := (&Provenance{Creator: "Bob"}).String(); got != "by Bob" {
+		t.Errorf("String() = %q, want %q", got, "by Bob")
+	}
+}
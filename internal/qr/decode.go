@@ -0,0 +1,212 @@
+// Package qr provides decoding of QR code images.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package qr
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"math"
+	"math/bits"
+)
+
+// ErrNotFound reports an image in which no QR code could be located.
+var ErrNotFound = errors.New("no QR code found in the image")
+
+// Decode reads the byte-mode data of the QR code in img. The code must be
+// upright and unskewed, on a light background; any scale works.
+func Decode(img image.Image) ([]byte, error) {
+	c, err := sample(img)
+	if err != nil {
+		return nil, err
+	}
+	return c.read()
+}
+
+// sample locates the code in img and reads its modules.
+func sample(img image.Image) (*Code, error) {
+	b := img.Bounds()
+	luma := make([][]uint8, b.Dy())
+	lo, hi := uint8(255), uint8(0)
+	for y := 0; y < b.Dy(); y++ {
+		luma[y] = make([]uint8, b.Dx())
+		for x := 0; x < b.Dx(); x++ {
+			r, g, bl, _ := img.At(b.Min.X+x, b.Min.Y+y).RGBA()
+			v := uint8((299*r + 587*g + 114*bl) / 1000 >> 8)
+			luma[y][x] = v
+			lo, hi = min(lo, v), max(hi, v)
+		}
+	}
+	if hi-lo < 64 {
+		return nil, ErrNotFound
+	}
+	threshold := lo + (hi-lo)/2
+	dark := func(x, y int) bool { return luma[y][x] < threshold }
+
+	// Bounding box of the dark pixels, which the finder patterns span
+	minX, minY, maxX, maxY := b.Dx(), b.Dy(), -1, -1
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			if dark(x, y) {
+				minX, minY = min(minX, x), min(minY, y)
+				maxX, maxY = max(maxX, x), max(maxY, y)
+			}
+		}
+	}
+	if maxX < 0 {
+		return nil, ErrNotFound
+	}
+
+	// The top edge of the top-left finder pattern is seven modules wide
+	run := 0
+	for x := minX; x <= maxX && dark(x, minY); x++ {
+		run++
+	}
+	width, height := float64(maxX-minX+1), float64(maxY-minY+1)
+	size := int(math.Round(width / (float64(run) / 7)))
+	version := (size - 17) / 4
+	if run < 7 || (size-17)%4 != 0 || version < 1 || version > 40 {
+		return nil, ErrNotFound
+	}
+
+	c := &Code{Version: version, size: size}
+	c.modules = make([][]bool, size)
+	moduleW, moduleH := width/float64(size), height/float64(size)
+	for y := 0; y < size; y++ {
+		c.modules[y] = make([]bool, size)
+		py := minY + int((float64(y)+0.5)*moduleH)
+		for x := 0; x < size; x++ {
+			c.modules[y][x] = dark(minX+int((float64(x)+0.5)*moduleW), py)
+		}
+	}
+	return c, nil
+}
+
+// read decodes the format, unmasks the symbol and returns its data.
+func (c *Code) read() ([]byte, error) {
+	if err := c.readFormat(); err != nil {
+		return nil, err
+	}
+	c.applyMask(c.Mask)
+
+	raw := make([]byte, rawCodewords(c.Version))
+	i := 0
+	c.codewordOrder(func(x, y int) {
+		if i < len(raw)*8 && c.modules[y][x] {
+			raw[i/8] |= 0x80 >> (i % 8)
+		}
+		i++
+	})
+
+	data, err := deinterleave(raw, c.Version, c.Level)
+	if err != nil {
+		return nil, err
+	}
+	return parseSegments(data, c.Version)
+}
+
+// readFormat sets Level and Mask from whichever copy of the format bits is
+// closest to a valid code word.
+func (c *Code) readFormat() error {
+	first, second := c.formatPositions()
+	best, bestDistance := -1, 4
+	for _, positions := range [][15][2]int{first, second} {
+		read := 0
+		for i, pos := range positions {
+			if c.modules[pos[1]][pos[0]] {
+				read |= 1 << i
+			}
+		}
+		for level := L; level <= H; level++ {
+			for mask := 0; mask < 8; mask++ {
+				if d := bits.OnesCount(uint(read ^ formatInfo(level, mask))); d < bestDistance {
+					best, bestDistance = int(level)<<3|mask, d
+				}
+			}
+		}
+	}
+	if best < 0 {
+		return fmt.Errorf("unreadable QR format information")
+	}
+	c.Level, c.Mask = Level(best>>3), best&7
+	return nil
+}
+
+// deinterleave undoes interleave, corrects each block and returns the data
+// codewords.
+func deinterleave(raw []byte, version int, level Level) ([]byte, error) {
+	blocks, short, shortData := blockLayout(version, level)
+	ecc := eccPerBlock[level][version]
+
+	split := make([][]byte, blocks)
+	for j := range split {
+		n := shortData + ecc
+		if j >= short {
+			n++
+		}
+		split[j] = make([]byte, n)
+	}
+	k := 0
+	for i := 0; i < shortData+1+ecc; i++ {
+		for j := range split {
+			if i == shortData && j < short {
+				continue
+			}
+			at := i
+			if j < short && i > shortData {
+				at--
+			}
+			split[j][at] = raw[k]
+			k++
+		}
+	}
+
+	var data []byte
+	for j, block := range split {
+		if _, err := rsCorrect(block, ecc); err != nil {
+			return nil, fmt.Errorf("QR block %d: %v", j+1, err)
+		}
+		data = append(data, block[:len(block)-ecc]...)
+	}
+	return data, nil
+}
+
+// parseSegments concatenates the byte-mode segments of the data codewords.
+func parseSegments(data []byte, version int) ([]byte, error) {
+	pos := 0
+	next := func(n int) (int, bool) {
+		if pos+n > len(data)*8 {
+			return 0, false
+		}
+		v := 0
+		for i := 0; i < n; i++ {
+			v = v<<1 | int(data[pos/8]>>(7-pos%8)&1)
+			pos++
+		}
+		return v, true
+	}
+
+	var out []byte
+	for {
+		mode, ok := next(4)
+		if !ok || mode == 0 {
+			return out, nil
+		}
+		if mode != 0x4 {
+			return nil, fmt.Errorf("unsupported QR data mode %d (only byte mode is read)", mode)
+		}
+		count, ok := next(countBits(version))
+		if !ok {
+			return nil, fmt.Errorf("truncated QR data")
+		}
+		for i := 0; i < count; i++ {
+			v, ok := next(8)
+			if !ok {
+				return nil, fmt.Errorf("truncated QR data")
+			}
+			out = append(out, byte(v))
+		}
+	}
+}
@@ -0,0 +1,537 @@
+// Package qr encodes and decodes QR codes carrying binary data, enough to
+// pass an Enigma configuration between machines as an image. Only byte mode
+// is written and read. Decode reads upright, unskewed images such as those
+// written by Image; it is not a camera scanner.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package qr
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+)
+
+// Level is the error correction level of a code.
+type Level int
+
+const (
+	// L recovers about 7% of the codewords.
+	L Level = iota
+	// M recovers about 15% of the codewords.
+	M
+	// Q recovers about 25% of the codewords.
+	Q
+	// H recovers about 30% of the codewords.
+	H
+)
+
+// formatBits are the two bits identifying each level in the format info.
+var formatBits = [4]int{L: 1, M: 0, Q: 3, H: 2}
+
+// eccPerBlock and eccBlocks give, per level and version, the number of ECC
+// codewords in each block and the number of blocks (ISO/IEC 18004 table 9).
+var eccPerBlock = [4][41]int{
+	{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{-1, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+var eccBlocks = [4][41]int{
+	{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{-1, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{-1, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// Code is an encoded QR symbol.
+type Code struct {
+	Version int
+	Level   Level
+	Mask    int
+	size    int
+	modules [][]bool // [row][column], true is dark
+}
+
+// Size returns the width of the symbol in modules.
+func (c *Code) Size() int { return c.size }
+
+// Dark reports whether the module at column x and row y is dark.
+func (c *Code) Dark(x, y int) bool { return c.modules[y][x] }
+
+// Capacity returns the number of data bytes a code of the given version and
+// level holds in byte mode.
+func Capacity(version int, level Level) int {
+	bits := dataCodewords(version, level)*8 - 4 - countBits(version)
+	return bits / 8
+}
+
+// Encode encodes data as the smallest byte-mode code at level.
+func Encode(data []byte, level Level) (*Code, error) {
+	version := 0
+	for v := 1; v <= 40; v++ {
+		if len(data) <= Capacity(v, level) {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, fmt.Errorf("%d bytes do not fit in a QR code (at most %d)", len(data), Capacity(40, level))
+	}
+
+	codewords := interleave(dataBits(data, version, level), version, level)
+	c := newCode(version, level)
+	c.placeCodewords(codewords)
+
+	// Keep the mask with the lowest penalty
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		c.applyMask(mask)
+		c.drawFormat(mask)
+		if p := c.penalty(); bestPenalty < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		c.applyMask(mask)
+	}
+	c.applyMask(best)
+	c.drawFormat(best)
+	c.Mask = best
+	return c, nil
+}
+
+// Image renders the code with scale pixels per module and the four-module
+// quiet zone the standard requires.
+func (c *Code) Image(scale int) image.Image {
+	if scale < 1 {
+		scale = 1
+	}
+	const quiet = 4
+	width := (c.size + 2*quiet) * scale
+	img := image.NewGray(image.Rect(0, 0, width, width))
+	for i := range img.Pix {
+		img.Pix[i] = 0xFF
+	}
+	for y := 0; y < c.size; y++ {
+		for x := 0; x < c.size; x++ {
+			if !c.modules[y][x] {
+				continue
+			}
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					img.SetGray((x+quiet)*scale+dx, (y+quiet)*scale+dy, color.Gray{})
+				}
+			}
+		}
+	}
+	return img
+}
+
+// dataBits builds the data codewords: mode, length, data, terminator and
+// padding.
+func dataBits(data []byte, version int, level Level) []byte {
+	var w bitWriter
+	w.write(0x4, 4) // Byte mode
+	w.write(len(data), countBits(version))
+	for _, b := range data {
+		w.write(int(b), 8)
+	}
+	capacity := dataCodewords(version, level) * 8
+	if rest := capacity - w.n; rest > 0 {
+		w.write(0, min(4, rest))
+	}
+	if w.n%8 != 0 {
+		w.write(0, 8-w.n%8)
+	}
+	for pad := 0xEC; w.n < capacity; pad ^= 0xEC ^ 0x11 {
+		w.write(pad, 8)
+	}
+	return w.bytes
+}
+
+// countBits is the width of the byte-mode length field.
+func countBits(version int) int {
+	if version < 10 {
+		return 8
+	}
+	return 16
+}
+
+// rawCodewords returns the number of codewords a version holds, data and ECC.
+func rawCodewords(version int) int {
+	modules := (16*version+128)*version + 64
+	if version >= 2 {
+		align := version/7 + 2
+		modules -= (25*align-10)*align - 55
+		if version >= 7 {
+			modules -= 36
+		}
+	}
+	return modules / 8
+}
+
+// dataCodewords returns the number of data codewords at a version and level.
+func dataCodewords(version int, level Level) int {
+	return rawCodewords(version) - eccPerBlock[level][version]*eccBlocks[level][version]
+}
+
+// blockLayout returns the number of blocks, how many of them are short, and
+// the data length of a short block; long blocks hold one more.
+func blockLayout(version int, level Level) (blocks, short, shortData int) {
+	blocks = eccBlocks[level][version]
+	raw := rawCodewords(version)
+	short = blocks - raw%blocks
+	shortData = raw/blocks - eccPerBlock[level][version]
+	return blocks, short, shortData
+}
+
+// interleave splits data into blocks, appends each block's ECC and
+// interleaves the result column by column.
+func interleave(data []byte, version int, level Level) []byte {
+	blocks, short, shortData := blockLayout(version, level)
+	ecc := eccPerBlock[level][version]
+
+	var split [][]byte
+	for i, k := 0, 0; i < blocks; i++ {
+		n := shortData
+		if i >= short {
+			n++
+		}
+		block := append([]byte(nil), data[k:k+n]...)
+		k += n
+		split = append(split, append(block, rsEncode(block, ecc)...))
+	}
+
+	out := make([]byte, 0, rawCodewords(version))
+	for i := 0; i < shortData+1+ecc; i++ {
+		for j, block := range split {
+			// Short blocks have no codeword at the last data column
+			if i == shortData && j < short {
+				continue
+			}
+			k := i
+			if j < short && i > shortData {
+				k--
+			}
+			out = append(out, block[k])
+		}
+	}
+	return out
+}
+
+// newCode returns a blank code with its function patterns drawn.
+func newCode(version int, level Level) *Code {
+	size := 17 + 4*version
+	c := &Code{Version: version, Level: level, size: size}
+	c.modules = make([][]bool, size)
+	for i := range c.modules {
+		c.modules[i] = make([]bool, size)
+	}
+	c.drawFunctionPatterns(nil)
+	return c
+}
+
+// drawFunctionPatterns draws the finder, timing and alignment patterns, the
+// version info and a placeholder format area. With function set it marks
+// the modules those occupy instead.
+func (c *Code) drawFunctionPatterns(function [][]bool) {
+	set := func(x, y int, dark bool) {
+		if function != nil {
+			function[y][x] = true
+			return
+		}
+		c.modules[y][x] = dark
+	}
+
+	for i := 0; i < c.size; i++ {
+		set(6, i, i%2 == 0)
+		set(i, 6, i%2 == 0)
+	}
+
+	for _, center := range [][2]int{{3, 3}, {c.size - 4, 3}, {3, c.size - 4}} {
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := center[0]+dx, center[1]+dy
+				if x < 0 || y < 0 || x >= c.size || y >= c.size {
+					continue
+				}
+				dist := max(abs(dx), abs(dy))
+				set(x, y, dist != 2 && dist != 4)
+			}
+		}
+	}
+
+	positions := alignmentPositions(c.Version)
+	last := len(positions) - 1
+	for i, cy := range positions {
+		for j, cx := range positions {
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue // Overlaps a finder pattern
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					set(cx+dx, cy+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	// The format area is reserved here and filled by drawFormat
+	for i := 0; i < 9; i++ {
+		if i != 6 { // Timing pattern
+			set(8, i, false)
+			set(i, 8, false)
+		}
+	}
+	for i := 0; i < 8; i++ {
+		set(c.size-1-i, 8, false)
+		set(8, c.size-1-i, false)
+	}
+	set(8, c.size-8, true)
+
+	if c.Version >= 7 {
+		bits := versionBits(c.Version)
+		for i := 0; i < 18; i++ {
+			dark := bits>>i&1 != 0
+			a, b := c.size-11+i%3, i/3
+			set(a, b, dark)
+			set(b, a, dark)
+		}
+	}
+}
+
+// functionModules marks the modules that do not carry codewords.
+func (c *Code) functionModules() [][]bool {
+	function := make([][]bool, c.size)
+	for i := range function {
+		function[i] = make([]bool, c.size)
+	}
+	c.drawFunctionPatterns(function)
+	return function
+}
+
+// alignmentPositions returns the centre coordinates of alignment patterns.
+func alignmentPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	n := version/7 + 2
+	step := (version*8 + n*3 + 5) / (n*4 - 4) * 2
+	positions := make([]int, n)
+	positions[0] = 6
+	for i, pos := n-1, 17+4*version-7; i >= 1; i, pos = i-1, pos-step {
+		positions[i] = pos
+	}
+	return positions
+}
+
+// formatInfo returns the 15 format bits for a level and mask.
+func formatInfo(level Level, mask int) int {
+	data := formatBits[level]<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+// versionBits returns the 18 version bits of versions 7 and up.
+func versionBits(version int) int {
+	rem := version
+	for i := 0; i < 12; i++ {
+		rem = rem<<1 ^ (rem>>11)*0x1F25
+	}
+	return version<<12 | rem
+}
+
+// formatPositions lists the two copies of the format bits, bit 0 first.
+func (c *Code) formatPositions() (first, second [15][2]int) {
+	for i := 0; i <= 5; i++ {
+		first[i] = [2]int{8, i}
+	}
+	first[6] = [2]int{8, 7}
+	first[7] = [2]int{8, 8}
+	first[8] = [2]int{7, 8}
+	for i := 9; i < 15; i++ {
+		first[i] = [2]int{14 - i, 8}
+	}
+	for i := 0; i < 8; i++ {
+		second[i] = [2]int{c.size - 1 - i, 8}
+	}
+	for i := 8; i < 15; i++ {
+		second[i] = [2]int{8, c.size - 15 + i}
+	}
+	return first, second
+}
+
+// drawFormat writes both copies of the format bits.
+func (c *Code) drawFormat(mask int) {
+	bits := formatInfo(c.Level, mask)
+	first, second := c.formatPositions()
+	for i := 0; i < 15; i++ {
+		dark := bits>>i&1 != 0
+		c.modules[first[i][1]][first[i][0]] = dark
+		c.modules[second[i][1]][second[i][0]] = dark
+	}
+}
+
+// codewordOrder calls fn for each codeword module in placement order: two
+// columns at a time from the right, alternately upwards and downwards.
+func (c *Code) codewordOrder(fn func(x, y int)) {
+	function := c.functionModules()
+	for right := c.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // Skip the vertical timing pattern
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < c.size; vert++ {
+			y := vert
+			if upward {
+				y = c.size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				if x := right - j; !function[y][x] {
+					fn(x, y)
+				}
+			}
+		}
+	}
+}
+
+// placeCodewords writes codewords, most significant bit first. Remainder
+// modules stay light.
+func (c *Code) placeCodewords(codewords []byte) {
+	i := 0
+	c.codewordOrder(func(x, y int) {
+		if i < len(codewords)*8 {
+			c.modules[y][x] = codewords[i/8]>>(7-i%8)&1 != 0
+		}
+		i++
+	})
+}
+
+// applyMask inverts the codeword modules selected by mask. Applying the same
+// mask twice undoes it.
+func (c *Code) applyMask(mask int) {
+	c.codewordOrder(func(x, y int) {
+		if masked(mask, x, y) {
+			c.modules[y][x] = !c.modules[y][x]
+		}
+	})
+}
+
+// masked reports whether mask inverts the module at column x and row y.
+func masked(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	default:
+		return ((x+y)%2+x*y%3)%2 == 0
+	}
+}
+
+// penalty scores a masked symbol by the four rules of the standard; lower is
+// easier to scan.
+func (c *Code) penalty() int {
+	score := 0
+	line := func(get func(i int) bool) {
+		run := 1
+		for i := 1; i <= c.size; i++ {
+			if i < c.size && get(i) == get(i-1) {
+				run++
+				continue
+			}
+			if run >= 5 {
+				score += run - 2
+			}
+			run = 1
+		}
+		// Finder-like 1:1:3:1:1 patterns with four light modules on one side
+		pattern := []bool{true, false, true, true, true, false, true}
+		for i := 0; i+7 <= c.size; i++ {
+			match := true
+			for k, want := range pattern {
+				if get(i+k) != want {
+					match = false
+					break
+				}
+			}
+			if match && (lightRun(get, i-4, i, c.size) || lightRun(get, i+7, i+11, c.size)) {
+				score += 40
+			}
+		}
+	}
+	for y := 0; y < c.size; y++ {
+		line(func(i int) bool { return c.modules[y][i] })
+	}
+	for x := 0; x < c.size; x++ {
+		line(func(i int) bool { return c.modules[i][x] })
+	}
+
+	dark := 0
+	for y := 0; y < c.size; y++ {
+		for x := 0; x < c.size; x++ {
+			if c.modules[y][x] {
+				dark++
+			}
+			if x+1 < c.size && y+1 < c.size {
+				v := c.modules[y][x]
+				if c.modules[y][x+1] == v && c.modules[y+1][x] == v && c.modules[y+1][x+1] == v {
+					score += 3
+				}
+			}
+		}
+	}
+	total := c.size * c.size
+	score += abs(dark*20-total*10) / total * 10
+	return score
+}
+
+// lightRun reports whether modules from..to-1 are light; modules outside the
+// symbol count as light.
+func lightRun(get func(i int) bool, from, to, size int) bool {
+	for i := from; i < to; i++ {
+		if i >= 0 && i < size && get(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// bitWriter appends bits most significant first.
+type bitWriter struct {
+	bytes []byte
+	n     int
+}
+
+func (w *bitWriter) write(v, bits int) {
+	for i := bits - 1; i >= 0; i-- {
+		if w.n%8 == 0 {
+			w.bytes = append(w.bytes, 0)
+		}
+		if v>>i&1 != 0 {
+			w.bytes[w.n/8] |= 0x80 >> (w.n % 8)
+		}
+		w.n++
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
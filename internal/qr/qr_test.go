@@ -0,0 +1,143 @@
+// Package qr provides unit tests for QR code encoding and decoding.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package qr
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"math/rand"
+	"testing"
+)
+
+func TestReedSolomonReferenceVector(t *testing.T) {
+	// "HELLO WORLD" as version 1-M, from the worked example of ISO/IEC 18004
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	if got := rsEncode(data, 10); !bytes.Equal(got, want) {
+		t.Errorf("rsEncode = %v, want %v", got, want)
+	}
+}
+
+func TestFormatAndVersionBits(t *testing.T) {
+	if got := formatInfo(M, 0); got != 0x5412 {
+		t.Errorf("formatInfo(M, 0) = %#x, want 0x5412", got)
+	}
+	if got := formatInfo(L, 0); got != 0x77C4 {
+		t.Errorf("formatInfo(L, 0) = %#x, want 0x77c4", got)
+	}
+	if got := versionBits(7); got != 0x07C94 {
+		t.Errorf("versionBits(7) = %#x, want 0x7c94", got)
+	}
+}
+
+func TestCapacity(t *testing.T) {
+	tests := []struct {
+		version int
+		level   Level
+		want    int
+	}{
+		{1, L, 17}, {1, M, 14}, {1, H, 7}, {10, M, 213}, {40, L, 2953}, {40, M, 2331},
+	}
+	for _, tt := range tests {
+		if got := Capacity(tt.version, tt.level); got != tt.want {
+			t.Errorf("Capacity(%d, %d) = %d, want %d", tt.version, tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, level := range []Level{L, M, Q, H} {
+		for _, n := range []int{0, 1, 14, 100, 300, 1000} {
+			data := make([]byte, n)
+			rng.Read(data)
+			code, err := Encode(data, level)
+			if err != nil {
+				t.Fatalf("Encode(%d bytes, level %d) failed: %v", n, level, err)
+			}
+			got, err := Decode(code.Image(3))
+			if err != nil {
+				t.Fatalf("Decode(version %d, level %d) failed: %v", code.Version, level, err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Errorf("version %d level %d: round trip changed the data", code.Version, level)
+			}
+		}
+	}
+}
+
+func TestDecodePNGAndOffset(t *testing.T) {
+	code, err := Encode([]byte("enigoma"), M)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, code.Image(7)); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Place the code off-centre on a larger, grey-ish canvas
+	canvas := image.NewRGBA(image.Rect(0, 0, 400, 300))
+	for i := range canvas.Pix {
+		canvas.Pix[i] = 0xF0
+	}
+	bounds := img.Bounds()
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			canvas.Set(x+31, y+17, img.At(x, y))
+		}
+	}
+	got, err := Decode(canvas)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if string(got) != "enigoma" {
+		t.Errorf("Decode = %q", got)
+	}
+}
+
+func TestDecodeCorrectsErrors(t *testing.T) {
+	data := bytes.Repeat([]byte("ABCDEFGH"), 20)
+	code, err := Encode(data, M)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Flip a few codeword modules; M corrects far more
+	flipped := 0
+	code.codewordOrder(func(x, y int) {
+		if flipped < 6 && (x+y)%17 == 0 {
+			code.modules[y][x] = !code.modules[y][x]
+			flipped++
+		}
+	})
+	got, err := Decode(code.Image(2))
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Error("errors were not corrected")
+	}
+}
+
+func TestEncodeTooLarge(t *testing.T) {
+	if _, err := Encode(make([]byte, Capacity(40, M)+1), M); err == nil {
+		t.Error("data over the version 40 capacity should fail")
+	}
+}
+
+func TestDecodeBlankImage(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 50, 50))
+	for i := range img.Pix {
+		img.Pix[i] = 0xFF
+	}
+	if _, err := Decode(img); err != ErrNotFound {
+		t.Errorf("Decode(blank) = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,204 @@
+// Package qr provides Reed-Solomon error correction over GF(256) with the
+// QR code polynomial x^8 + x^4 + x^3 + x^2 + 1.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package qr
+
+import "errors"
+
+// errUncorrectable reports a block with more errors than its ECC can fix.
+var errUncorrectable = errors.New("too many errors to correct")
+
+// gfExp and gfLog are the antilog and log tables of GF(256). gfExp is doubled
+// so products of two logs need no reduction.
+var (
+	gfExp [510]byte
+	gfLog [256]int
+)
+
+func init() {
+	x := 1
+	for i := 0; i < 255; i++ {
+		gfExp[i] = byte(x)
+		gfExp[i+255] = byte(x)
+		gfLog[x] = i
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11D
+		}
+	}
+}
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[gfLog[a]+gfLog[b]]
+}
+
+func gfDiv(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return gfExp[gfLog[a]+255-gfLog[b]]
+}
+
+// gfPow returns alpha^n.
+func gfPow(n int) byte {
+	n %= 255
+	if n < 0 {
+		n += 255
+	}
+	return gfExp[n]
+}
+
+// rsGenerator returns the generator polynomial of degree n, highest
+// coefficient first, whose roots are alpha^0 .. alpha^(n-1).
+func rsGenerator(n int) []byte {
+	g := []byte{1}
+	for i := 0; i < n; i++ {
+		next := make([]byte, len(g)+1)
+		for j, c := range g {
+			next[j] ^= c
+			next[j+1] ^= gfMul(c, gfPow(i))
+		}
+		g = next
+	}
+	return g
+}
+
+// rsEncode returns the n error correction codewords for data.
+func rsEncode(data []byte, n int) []byte {
+	gen := rsGenerator(n)
+	rem := make([]byte, n)
+	for _, b := range data {
+		factor := b ^ rem[0]
+		copy(rem, rem[1:])
+		rem[n-1] = 0
+		for i := range rem {
+			rem[i] ^= gfMul(gen[i+1], factor)
+		}
+	}
+	return rem
+}
+
+// rsCorrect fixes block, data codewords followed by n ECC codewords, in place
+// and returns the number of corrected codewords.
+func rsCorrect(block []byte, n int) (int, error) {
+	// Syndromes: the received polynomial at each root of the generator
+	syndromes := make([]byte, n)
+	clean := true
+	for i := range syndromes {
+		var s byte
+		for _, c := range block {
+			s = gfMul(s, gfPow(i)) ^ c
+		}
+		syndromes[i] = s
+		if s != 0 {
+			clean = false
+		}
+	}
+	if clean {
+		return 0, nil
+	}
+
+	// Berlekamp-Massey finds the error locator, lowest coefficient first
+	locator, prev := []byte{1}, []byte{1}
+	length, shift, lastDelta := 0, 1, byte(1)
+	for i := 0; i < n; i++ {
+		delta := syndromes[i]
+		for j := 1; j <= length && j < len(locator); j++ {
+			delta ^= gfMul(locator[j], syndromes[i-j])
+		}
+		if delta == 0 {
+			shift++
+			continue
+		}
+		correction := append(make([]byte, shift), scalePoly(prev, gfDiv(delta, lastDelta))...)
+		if 2*length <= i {
+			prev, locator = locator, addPoly(locator, correction)
+			length, shift, lastDelta = i+1-length, 1, delta
+			continue
+		}
+		locator = addPoly(locator, correction)
+		shift++
+	}
+	for len(locator) > 1 && locator[len(locator)-1] == 0 {
+		locator = locator[:len(locator)-1]
+	}
+	errs := len(locator) - 1
+	if errs*2 > n {
+		return 0, errUncorrectable
+	}
+
+	// Chien search: position p (from the end) is wrong when the locator
+	// vanishes at alpha^-p
+	var positions []int
+	for p := 0; p < len(block); p++ {
+		if evalPoly(locator, gfPow(-p)) == 0 {
+			positions = append(positions, p)
+		}
+	}
+	if len(positions) != errs {
+		return 0, errUncorrectable
+	}
+
+	// Forney: error magnitudes from the evaluator polynomial
+	evaluator := make([]byte, errs)
+	for i := range evaluator {
+		for j := 0; j <= i && j < len(locator); j++ {
+			evaluator[i] ^= gfMul(locator[j], syndromes[i-j])
+		}
+	}
+	for _, p := range positions {
+		xInv := gfPow(-p)
+		var num, den byte
+		num = evalPoly(evaluator, xInv)
+		for j := 1; j < len(locator); j += 2 {
+			den ^= gfMul(locator[j], gfPowOf(xInv, j-1))
+		}
+		if den == 0 {
+			return 0, errUncorrectable
+		}
+		block[len(block)-1-p] ^= gfMul(gfPow(p), gfDiv(num, den))
+	}
+	return errs, nil
+}
+
+// evalPoly evaluates p, lowest coefficient first, at x.
+func evalPoly(p []byte, x byte) byte {
+	var y byte
+	for i := len(p) - 1; i >= 0; i-- {
+		y = gfMul(y, x) ^ p[i]
+	}
+	return y
+}
+
+// gfPowOf returns x^n.
+func gfPowOf(x byte, n int) byte {
+	y := byte(1)
+	for i := 0; i < n; i++ {
+		y = gfMul(y, x)
+	}
+	return y
+}
+
+func scalePoly(p []byte, c byte) []byte {
+	out := make([]byte, len(p))
+	for i, v := range p {
+		out[i] = gfMul(v, c)
+	}
+	return out
+}
+
+func addPoly(a, b []byte) []byte {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	out := append([]byte(nil), a...)
+	for i, v := range b {
+		out[i] ^= v
+	}
+	return out
+}
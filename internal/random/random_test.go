@@ -0,0 +1,85 @@
+package random
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDeterministicIsReproducible(t *testing.T) {
+	a := NewDeterministic([]byte("seed"))
+	b := NewDeterministic([]byte("seed"))
+	c := NewDeterministic([]byte("other seed"))
+
+	same := true
+	for i := 0; i < 200; i++ {
+		n := i%50 + 1
+		va, err := a.Intn(n)
+		if err != nil {
+			t.Fatalf("Intn failed: %v", err)
+		}
+		vb, _ := b.Intn(n)
+		vc, _ := c.Intn(n)
+		if va != vb {
+			t.Fatalf("draw %d differs for identical seeds: %d vs %d", i, va, vb)
+		}
+		if va < 0 || va >= n {
+			t.Fatalf("draw %d out of range [0,%d): %d", i, n, va)
+		}
+		if va != vc {
+			same = false
+		}
+	}
+	if same {
+		t.Error("different seeds produced identical streams")
+	}
+}
+
+func TestDeterministicCoversRange(t *testing.T) {
+	d := NewDeterministic([]byte("coverage"))
+	seen := make(map[int]bool)
+	for i := 0; i < 1000; i++ {
+		v, _ := d.Intn(26)
+		seen[v] = true
+	}
+	if len(seen) != 26 {
+		t.Errorf("expected all 26 values to appear, got %d", len(seen))
+	}
+}
+
+func TestInvalidRange(t *testing.T) {
+	if _, err := NewDeterministic(nil).Intn(0); err == nil {
+		t.Error("Intn(0) should fail for the deterministic source")
+	}
+	if _, err := Crypto.Intn(-1); err == nil {
+		t.Error("Intn(-1) should fail for the crypto source")
+	}
+	if v, err := Crypto.Intn(1); err != nil || v != 0 {
+		t.Errorf("Intn(1) = %d, %v", v, err)
+	}
+}
+
+func TestFromReader(t *testing.T) {
+	stream := bytes.Repeat([]byte{0x00, 0x01, 0x02, 0x03}, 16)
+	a := FromReader(bytes.NewReader(stream))
+	b := FromReader(bytes.NewReader(stream))
+	for i := 0; i < 8; i++ {
+		va, err := a.Intn(4)
+		if err != nil {
+			t.Fatalf("Intn failed: %v", err)
+		}
+		if vb, _ := b.Intn(4); va != vb {
+			t.Fatalf("draw %d differs for identical readers: %d vs %d", i, va, vb)
+		}
+		if va < 0 || va >= 4 {
+			t.Fatalf("draw %d out of range [0,4): %d", i, va)
+		}
+	}
+
+	// An exhausted reader is an error, not a silent zero
+	if _, err := FromReader(bytes.NewReader(nil)).Intn(10); err == nil {
+		t.Error("Intn should fail when the reader runs dry")
+	}
+	if _, err := FromReader(bytes.NewReader(stream)).Intn(0); err == nil {
+		t.Error("Intn(0) should fail for a reader source")
+	}
+}
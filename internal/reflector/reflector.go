@@ -6,11 +6,11 @@
 package reflector
 
 import (
-	"crypto/rand"
 	"fmt"
-	"math/big"
 
 	"github.com/coredds/enigoma/internal/alphabet"
+	"github.com/coredds/enigoma/internal/provenance"
+	"github.com/coredds/enigoma/internal/random"
 )
 
 // Reflector represents the reflector component of an Enigma machine.
@@ -22,16 +22,25 @@ type Reflector interface {
 
 // BasicReflector implements the Reflector interface with reciprocal mapping.
 type BasicReflector struct {
-	id       string
-	alphabet *alphabet.Alphabet
-	mapping  []int
-	size     int
+	id         string
+	alphabet   *alphabet.Alphabet
+	mapping    []int
+	size       int
+	provenance provenance.Provenance // Carried through specs; does not affect encryption
 }
 
 // NewReflector creates a new reflector with the specified mapping.
 // The mapping string should represent reciprocal pairs where each character
 // maps to another character bidirectionally.
 func NewReflector(id string, alph *alphabet.Alphabet, mapping string) (Reflector, error) {
+	return NewReflectorWithFixedPoints(id, alph, mapping, 0)
+}
+
+// NewReflectorWithFixedPoints creates a reflector whose mapping leaves exactly
+// fixedPoints characters in place; the others form reciprocal pairs as in
+// NewReflector. A character left in place encrypts to itself, which the
+// historical reflectors never allowed.
+func NewReflectorWithFixedPoints(id string, alph *alphabet.Alphabet, mapping string, fixedPoints int) (Reflector, error) {
 	if alph == nil {
 		return nil, fmt.Errorf("alphabet cannot be nil")
 	}
@@ -46,6 +55,7 @@ func NewReflector(id string, alph *alphabet.Alphabet, mapping string) (Reflector
 	// Convert mapping string to indices and validate reciprocity
 	reflectMap := make([]int, size)
 	used := make([]bool, size)
+	selfMapped := 0
 
 	for i, r := range mappingRunes {
 		outputIdx, err := alph.RuneToIndex(r)
@@ -55,8 +65,11 @@ func NewReflector(id string, alph *alphabet.Alphabet, mapping string) (Reflector
 
 		// Check for self-mapping (not allowed in Enigma reflectors)
 		if i == outputIdx {
-			inputRune, _ := alph.IndexToRune(i)
-			return nil, fmt.Errorf("character %c cannot map to itself in a reflector", inputRune)
+			if fixedPoints == 0 {
+				inputRune, _ := alph.IndexToRune(i)
+				return nil, fmt.Errorf("character %c cannot map to itself in a reflector", inputRune)
+			}
+			selfMapped++
 		}
 
 		if used[outputIdx] {
@@ -67,6 +80,9 @@ func NewReflector(id string, alph *alphabet.Alphabet, mapping string) (Reflector
 		reflectMap[i] = outputIdx
 		used[outputIdx] = true
 	}
+	if selfMapped != fixedPoints {
+		return nil, fmt.Errorf("mapping leaves %d character(s) in place, want %d fixed points", selfMapped, fixedPoints)
+	}
 
 	// Validate reciprocal mapping: if A->B then B->A
 	for i := 0; i < size; i++ {
@@ -88,8 +104,10 @@ func NewReflector(id string, alph *alphabet.Alphabet, mapping string) (Reflector
 	}, nil
 }
 
-// RandomReflector generates a cryptographically random reflector with reciprocal mapping.
-func RandomReflector(id string, alph *alphabet.Alphabet) (Reflector, error) {
+// NewReflectorFromPairs creates a reflector from explicit character pairs.
+// Each pair may be given in one direction (A->Y) or both (A->Y and Y->A), but
+// every character of the alphabet must end up in exactly one pair.
+func NewReflectorFromPairs(id string, alph *alphabet.Alphabet, pairs map[rune]rune) (Reflector, error) {
 	if alph == nil {
 		return nil, fmt.Errorf("alphabet cannot be nil")
 	}
@@ -99,6 +117,88 @@ func RandomReflector(id string, alph *alphabet.Alphabet) (Reflector, error) {
 		return nil, fmt.Errorf("alphabet size must be even for reflector (%d is odd)", size)
 	}
 
+	partner := make([]int, size)
+	for i := range partner {
+		partner[i] = -1
+	}
+	link := func(a, b int) error {
+		if partner[a] != -1 && partner[a] != b {
+			aRune, _ := alph.IndexToRune(a)
+			otherRune, _ := alph.IndexToRune(partner[a])
+			return fmt.Errorf("character %c is paired more than once (with %c already)", aRune, otherRune)
+		}
+		partner[a] = b
+		return nil
+	}
+
+	for from, to := range pairs {
+		fromIdx, err := alph.RuneToIndex(from)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reflector pair %c%c: %v", from, to, err)
+		}
+		toIdx, err := alph.RuneToIndex(to)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reflector pair %c%c: %v", from, to, err)
+		}
+		if fromIdx == toIdx {
+			return nil, fmt.Errorf("character %c cannot map to itself in a reflector", from)
+		}
+		if err := link(fromIdx, toIdx); err != nil {
+			return nil, err
+		}
+		if err := link(toIdx, fromIdx); err != nil {
+			return nil, err
+		}
+	}
+
+	// Every character must be wired
+	var missing []rune
+	for i, p := range partner {
+		if p == -1 {
+			r, _ := alph.IndexToRune(i)
+			missing = append(missing, r)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("reflector pairs are incomplete: %d character(s) unpaired: %q", len(missing), string(missing))
+	}
+
+	mapping := make([]rune, size)
+	for i, p := range partner {
+		mapping[i], _ = alph.IndexToRune(p)
+	}
+	return NewReflector(id, alph, string(mapping))
+}
+
+// RandomReflector generates a cryptographically random reflector with reciprocal mapping.
+func RandomReflector(id string, alph *alphabet.Alphabet) (Reflector, error) {
+	return RandomReflectorFrom(id, alph, random.Crypto)
+}
+
+// RandomReflectorFrom generates a random reflector drawing from the given source.
+func RandomReflectorFrom(id string, alph *alphabet.Alphabet, src random.Source) (Reflector, error) {
+	return RandomReflectorWithFixedPointsFrom(id, alph, 0, src)
+}
+
+// RandomReflectorWithFixedPointsFrom generates a random reflector that leaves
+// fixedPoints randomly chosen characters in place and pairs the rest. With no
+// fixed points it draws exactly what RandomReflectorFrom draws.
+func RandomReflectorWithFixedPointsFrom(id string, alph *alphabet.Alphabet, fixedPoints int, src random.Source) (Reflector, error) {
+	if alph == nil {
+		return nil, fmt.Errorf("alphabet cannot be nil")
+	}
+
+	size := alph.Size()
+	if fixedPoints < 0 || fixedPoints > size {
+		return nil, fmt.Errorf("fixed points must be between 0 and the alphabet size (%d), got %d", size, fixedPoints)
+	}
+	if (size-fixedPoints)%2 != 0 {
+		if fixedPoints == 0 {
+			return nil, fmt.Errorf("alphabet size must be even for reflector (%d is odd)", size)
+		}
+		return nil, fmt.Errorf("%d characters remain after %d fixed points, which cannot be paired (the count must be even)", size-fixedPoints, fixedPoints)
+	}
+
 	runes := alph.Runes()
 	mapping := make([]rune, size)
 
@@ -110,16 +210,18 @@ func RandomReflector(id string, alph *alphabet.Alphabet) (Reflector, error) {
 
 	// Shuffle the available indices
 	for i := size - 1; i > 0; i-- {
-		jBig, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		j, err := src.Intn(i + 1)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate random number: %v", err)
 		}
-		j := int(jBig.Int64())
 		available[i], available[j] = available[j], available[i]
 	}
 
-	// Create pairs from the shuffled list
-	for i := 0; i < size; i += 2 {
+	// Create pairs from the shuffled list, after the fixed points
+	for _, idx := range available[size-fixedPoints:] {
+		mapping[idx] = runes[idx]
+	}
+	for i := 0; i < size-fixedPoints; i += 2 {
 		idx1 := available[i]
 		idx2 := available[i+1]
 
@@ -127,7 +229,7 @@ func RandomReflector(id string, alph *alphabet.Alphabet) (Reflector, error) {
 		mapping[idx2] = runes[idx1]
 	}
 
-	return NewReflector(id, alph, string(mapping))
+	return NewReflectorWithFixedPoints(id, alph, string(mapping), fixedPoints)
 }
 
 // ID returns the identifier of the reflector.
@@ -149,10 +251,11 @@ func (r *BasicReflector) Clone() Reflector {
 	copy(mapping, r.mapping)
 
 	return &BasicReflector{
-		id:       r.id,
-		alphabet: r.alphabet,
-		mapping:  mapping,
-		size:     r.size,
+		id:         r.id,
+		alphabet:   r.alphabet,
+		mapping:    mapping,
+		size:       r.size,
+		provenance: r.provenance,
 	}
 }
 
@@ -160,17 +263,89 @@ func (r *BasicReflector) Clone() Reflector {
 type ReflectorSpec struct {
 	ID      string `json:"id"`
 	Mapping string `json:"mapping"`
+
+	Rewirable   bool                   `json:"rewirable,omitempty"`    // Wiring can be changed at runtime (UKW-D style)
+	Rotating    bool                   `json:"rotating,omitempty"`     // Turns with the rotors (Enigma G style)
+	Settable    bool                   `json:"settable,omitempty"`     // Can be set to a position but does not turn (Enigma K style)
+	Position    int                    `json:"position,omitempty"`     // Position of a rotating or settable reflector
+	FixedPoints int                    `json:"fixed_points,omitempty"` // Characters the mapping leaves in place
+	Provenance  *provenance.Provenance `json:"provenance,omitempty"`   // Optional origin of the wiring
 }
 
 // CreateFromSpec creates a reflector from a specification.
 func CreateFromSpec(spec ReflectorSpec, alph *alphabet.Alphabet) (Reflector, error) {
-	return NewReflector(spec.ID, alph, spec.Mapping)
+	if spec.Rewirable && (spec.Rotating || spec.Settable) {
+		return nil, fmt.Errorf("a reflector cannot be both rewirable and rotating or settable")
+	}
+	if spec.Rotating && spec.Settable {
+		return nil, fmt.Errorf("a reflector is either rotating or settable, not both")
+	}
+	if !spec.Rotating && !spec.Settable && spec.Position != 0 {
+		return nil, fmt.Errorf("only a rotating or settable reflector has a position")
+	}
+	if spec.FixedPoints != 0 && (spec.Rewirable || spec.Rotating || spec.Settable) {
+		return nil, fmt.Errorf("only a fixed reflector can have fixed points")
+	}
+	if spec.Rotating || spec.Settable {
+		rr, err := NewRotatingReflector(spec.ID, alph, spec.Mapping)
+		if err != nil {
+			return nil, err
+		}
+		rr.stationary = spec.Settable
+		if spec.Position < 0 || spec.Position >= rr.size {
+			return nil, fmt.Errorf("reflector position %d is outside 0-%d", spec.Position, rr.size-1)
+		}
+		rr.position = spec.Position
+		if !spec.Provenance.IsZero() {
+			rr.provenance = *spec.Provenance
+		}
+		return rr, nil
+	}
+	if spec.Rewirable {
+		rr, err := newRewirableFromMapping(spec.ID, alph, spec.Mapping)
+		if err != nil {
+			return nil, err
+		}
+		if !spec.Provenance.IsZero() {
+			rr.provenance = *spec.Provenance
+		}
+		return rr, nil
+	}
+
+	reflector, err := NewReflectorWithFixedPoints(spec.ID, alph, spec.Mapping, spec.FixedPoints)
+	if err != nil {
+		return nil, err
+	}
+	if br, ok := reflector.(*BasicReflector); ok && !spec.Provenance.IsZero() {
+		br.provenance = *spec.Provenance
+	}
+	return reflector, nil
 }
 
 // ToSpec converts a reflector to a specification for serialization.
 func ToSpec(reflector Reflector, alph *alphabet.Alphabet) (ReflectorSpec, error) {
+	if rr, ok := reflector.(*RewirableReflector); ok {
+		spec, err := ToSpec(&rr.BasicReflector, alph)
+		if err != nil {
+			return ReflectorSpec{}, err
+		}
+		spec.Rewirable = true
+		return spec, nil
+	}
+	if rr, ok := reflector.(*RotatingReflector); ok {
+		spec, err := ToSpec(&rr.BasicReflector, alph)
+		if err != nil {
+			return ReflectorSpec{}, err
+		}
+		spec.Rotating = !rr.stationary
+		spec.Settable = rr.stationary
+		spec.Position = rr.position
+		return spec, nil
+	}
+
 	if br, ok := reflector.(*BasicReflector); ok {
 		mapping := make([]rune, br.size)
+		fixedPoints := 0
 		for i := 0; i < br.size; i++ {
 			outputIdx := br.mapping[i]
 			r, err := alph.IndexToRune(outputIdx)
@@ -178,11 +353,16 @@ func ToSpec(reflector Reflector, alph *alphabet.Alphabet) (ReflectorSpec, error)
 				return ReflectorSpec{}, err
 			}
 			mapping[i] = r
+			if outputIdx == i {
+				fixedPoints++
+			}
 		}
 
 		return ReflectorSpec{
-			ID:      br.id,
-			Mapping: string(mapping),
+			ID:          br.id,
+			Mapping:     string(mapping),
+			FixedPoints: fixedPoints,
+			Provenance:  br.provenance.Copy(),
 		}, nil
 	}
 
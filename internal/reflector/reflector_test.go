@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/coredds/enigoma/internal/alphabet"
+	"github.com/coredds/enigoma/internal/provenance"
+	"github.com/coredds/enigoma/internal/random"
 )
 
 func createTestAlphabet() *alphabet.Alphabet {
@@ -392,3 +394,189 @@ func TestReflectorRoundtrip(t *testing.T) {
 		}
 	}
 }
+
+func TestNewReflectorFromPairs(t *testing.T) {
+	alph := createTestAlphabet()
+
+	tests := []struct {
+		name      string
+		alphabet  *alphabet.Alphabet
+		pairs     map[rune]rune
+		wantError bool
+	}{
+		{
+			name:     "one direction per pair",
+			alphabet: alph,
+			pairs:    map[rune]rune{'A': 'C', 'B': 'D'},
+		},
+		{
+			name:     "both directions agree",
+			alphabet: alph,
+			pairs:    map[rune]rune{'A': 'C', 'C': 'A', 'B': 'D', 'D': 'B'},
+		},
+		{
+			name:      "incomplete",
+			alphabet:  alph,
+			pairs:     map[rune]rune{'A': 'C'},
+			wantError: true,
+		},
+		{
+			name:      "not reciprocal",
+			alphabet:  alph,
+			pairs:     map[rune]rune{'A': 'C', 'C': 'B', 'D': 'B'},
+			wantError: true,
+		},
+		{
+			name:      "character paired twice",
+			alphabet:  alph,
+			pairs:     map[rune]rune{'A': 'C', 'B': 'C'},
+			wantError: true,
+		},
+		{
+			name:      "self pair",
+			alphabet:  alph,
+			pairs:     map[rune]rune{'A': 'A', 'B': 'C'},
+			wantError: true,
+		},
+		{
+			name:      "unknown character",
+			alphabet:  alph,
+			pairs:     map[rune]rune{'A': 'X', 'B': 'C'},
+			wantError: true,
+		},
+		{
+			name:      "odd alphabet",
+			alphabet:  createTestAlphabetOdd(),
+			pairs:     map[rune]rune{'A': 'B'},
+			wantError: true,
+		},
+		{
+			name:      "nil alphabet",
+			alphabet:  nil,
+			pairs:     map[rune]rune{'A': 'B'},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refl, err := NewReflectorFromPairs("CUSTOM", tt.alphabet, tt.pairs)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("NewReflectorFromPairs() error = %v, wantError %v", err, tt.wantError)
+			}
+			if err != nil {
+				return
+			}
+			// A<->C, B<->D
+			want := []int{2, 3, 0, 1}
+			for i, w := range want {
+				if got := refl.Reflect(i); got != w {
+					t.Errorf("Reflect(%d) = %d, want %d", i, got, w)
+				}
+			}
+		})
+	}
+}
+
+func TestSpecProvenance(t *testing.T) {
+	alph := createTestAlphabet()
+	src := &provenance.Provenance{Source: "library:custom", Creator: "Alice", CreatedAt: "2025-01-02T15:04:05Z"}
+
+	reflector, err := CreateFromSpec(ReflectorSpec{
+		ID:         "custom",
+		Mapping:    "BADC",
+		Provenance: src,
+	}, alph)
+	if err != nil {
+		t.Fatalf("CreateFromSpec() error: %v", err)
+	}
+	src.Creator = "Mallory" // The reflector keeps its own copy
+
+	for _, r := range []Reflector{reflector, reflector.Clone()} {
+		spec, err := ToSpec(r, alph)
+		if err != nil {
+			t.Fatalf("ToSpec() error: %v", err)
+		}
+		if spec.Provenance == nil || *spec.Provenance != (provenance.Provenance{Source: "library:custom", Creator: "Alice", CreatedAt: "2025-01-02T15:04:05Z"}) {
+			t.Errorf("Spec provenance = %+v", spec.Provenance)
+		}
+	}
+
+	plain, err := NewReflector("plain", alph, "BADC")
+	if err != nil {
+		t.Fatalf("Failed to create reflector: %v", err)
+	}
+	if spec, _ := ToSpec(plain, alph); spec.Provenance != nil {
+		t.Errorf("Spec provenance = %+v, want nil", spec.Provenance)
+	}
+}
+
+func TestReflectorFixedPoints(t *testing.T) {
+	alph, _ := alphabet.New([]rune("ABCDE"))
+
+	refl, err := RandomReflectorWithFixedPointsFrom("UKW", alph, 1, random.NewDeterministic([]byte("fixed")))
+	if err != nil {
+		t.Fatalf("RandomReflectorWithFixedPointsFrom failed: %v", err)
+	}
+	fixed := 0
+	for i := 0; i < alph.Size(); i++ {
+		out := refl.Reflect(i)
+		if out == i {
+			fixed++
+		}
+		if refl.Reflect(out) != i {
+			t.Errorf("reflector is not reciprocal at %d", i)
+		}
+	}
+	if fixed != 1 {
+		t.Errorf("reflector leaves %d characters in place, want 1", fixed)
+	}
+
+	spec, err := ToSpec(refl, alph)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spec.FixedPoints != 1 {
+		t.Errorf("spec.FixedPoints = %d, want 1", spec.FixedPoints)
+	}
+	if _, err := CreateFromSpec(spec, alph); err != nil {
+		t.Errorf("CreateFromSpec should accept its own spec: %v", err)
+	}
+
+	// Without the count, a self-mapping is still rejected
+	spec.FixedPoints = 0
+	if _, err := CreateFromSpec(spec, alph); err == nil {
+		t.Error("a spec without fixed points should reject a self-mapping")
+	}
+	spec.FixedPoints = 3
+	if _, err := CreateFromSpec(spec, alph); err == nil {
+		t.Error("a spec should leave exactly its fixed points in place")
+	}
+	spec.FixedPoints, spec.Rotating = 1, true
+	if _, err := CreateFromSpec(spec, alph); err == nil {
+		t.Error("a rotating reflector cannot have fixed points")
+	}
+
+	for _, n := range []int{-1, 0, 2, 6} {
+		if _, err := RandomReflectorWithFixedPointsFrom("UKW", alph, n, random.Crypto); err == nil {
+			t.Errorf("%d fixed points should not fit an alphabet of 5", n)
+		}
+	}
+}
+
+func TestRandomReflectorWithoutFixedPointsUnchanged(t *testing.T) {
+	alph := createTestAlphabet()
+	a, err := RandomReflectorFrom("UKW", alph, random.NewDeterministic([]byte("seed")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := RandomReflectorWithFixedPointsFrom("UKW", alph, 0, random.NewDeterministic([]byte("seed")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < alph.Size(); i++ {
+		if a.Reflect(i) != b.Reflect(i) {
+			t.Fatalf("zero fixed points should draw the same reflector as RandomReflectorFrom")
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// Package reflector provides a rewirable reflector modelled on the UKW-D.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package reflector
+
+import (
+	"fmt"
+
+	"github.com/coredds/enigoma/internal/alphabet"
+)
+
+// RewirableReflector is a reflector whose wiring can be changed after it is
+// built, like the field-rewirable UKW-D the Luftwaffe introduced in 1944.
+// Operators replug its pairs from a key sheet instead of swapping the whole
+// machine.
+type RewirableReflector struct {
+	BasicReflector
+}
+
+// NewRewirableReflector creates a rewirable reflector wired from explicit
+// character pairs, as accepted by NewReflectorFromPairs.
+func NewRewirableReflector(id string, alph *alphabet.Alphabet, pairs map[rune]rune) (*RewirableReflector, error) {
+	if alph == nil {
+		return nil, fmt.Errorf("alphabet cannot be nil")
+	}
+	r := &RewirableReflector{BasicReflector: BasicReflector{id: id, alphabet: alph}}
+	if err := r.Rewire(pairs); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// newRewirableFromMapping creates a rewirable reflector from a mapping string,
+// as stored in a ReflectorSpec.
+func newRewirableFromMapping(id string, alph *alphabet.Alphabet, mapping string) (*RewirableReflector, error) {
+	refl, err := NewReflector(id, alph, mapping)
+	if err != nil {
+		return nil, err
+	}
+	return &RewirableReflector{BasicReflector: *refl.(*BasicReflector)}, nil
+}
+
+// Rewire replaces the reflector's wiring with pairs. The pairs must cover the
+// whole alphabet; on error the current wiring is kept.
+func (r *RewirableReflector) Rewire(pairs map[rune]rune) error {
+	refl, err := NewReflectorFromPairs(r.id, r.alphabet, pairs)
+	if err != nil {
+		return err
+	}
+	wired := refl.(*BasicReflector)
+	r.mapping = wired.mapping
+	r.size = wired.size
+	return nil
+}
+
+// Pairs returns the current wiring, listing each pair once from the
+// character that comes first in the alphabet.
+func (r *RewirableReflector) Pairs() map[rune]rune {
+	pairs := make(map[rune]rune, r.size/2)
+	for i, j := range r.mapping {
+		if i < j {
+			from, _ := r.alphabet.IndexToRune(i)
+			to, _ := r.alphabet.IndexToRune(j)
+			pairs[from] = to
+		}
+	}
+	return pairs
+}
+
+// Clone creates a deep copy of the reflector.
+func (r *RewirableReflector) Clone() Reflector {
+	return &RewirableReflector{BasicReflector: *r.BasicReflector.Clone().(*BasicReflector)}
+}
@@ -0,0 +1,78 @@
+package reflector
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRewirableReflector(t *testing.T) {
+	alph := createTestAlphabet()
+
+	r, err := NewRewirableReflector("UKW-D", alph, map[rune]rune{'A': 'B', 'C': 'D'})
+	if err != nil {
+		t.Fatalf("NewRewirableReflector() error: %v", err)
+	}
+	if got := r.Reflect(0); got != 1 {
+		t.Errorf("Reflect(A) = %d, want 1", got)
+	}
+
+	if err := r.Rewire(map[rune]rune{'A': 'C', 'B': 'D'}); err != nil {
+		t.Fatalf("Rewire() error: %v", err)
+	}
+	if got := r.Reflect(0); got != 2 {
+		t.Errorf("after rewiring, Reflect(A) = %d, want 2", got)
+	}
+	if want := map[rune]rune{'A': 'C', 'B': 'D'}; !reflect.DeepEqual(r.Pairs(), want) {
+		t.Errorf("Pairs() = %q, want %q", r.Pairs(), want)
+	}
+
+	// A failed rewire keeps the current wiring
+	if err := r.Rewire(map[rune]rune{'A': 'B'}); err == nil {
+		t.Error("Rewire() with incomplete pairs should fail")
+	}
+	if got := r.Reflect(0); got != 2 {
+		t.Errorf("failed rewire changed the wiring: Reflect(A) = %d", got)
+	}
+
+	// Clones are rewired independently
+	clone := r.Clone().(*RewirableReflector)
+	if err := clone.Rewire(map[rune]rune{'A': 'D', 'B': 'C'}); err != nil {
+		t.Fatal(err)
+	}
+	if r.Reflect(0) != 2 || clone.Reflect(0) != 3 {
+		t.Error("rewiring a clone affected the original")
+	}
+}
+
+func TestRewirableSpecRoundTrip(t *testing.T) {
+	alph := createTestAlphabet()
+	r, err := NewRewirableReflector("UKW-D", alph, map[rune]rune{'A': 'D', 'B': 'C'})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spec, err := ToSpec(r, alph)
+	if err != nil {
+		t.Fatalf("ToSpec() error: %v", err)
+	}
+	if !spec.Rewirable || spec.Mapping != "DCBA" {
+		t.Errorf("spec = %+v, want rewirable with mapping DCBA", spec)
+	}
+
+	restored, err := CreateFromSpec(spec, alph)
+	if err != nil {
+		t.Fatalf("CreateFromSpec() error: %v", err)
+	}
+	if _, ok := restored.(*RewirableReflector); !ok {
+		t.Fatalf("CreateFromSpec() returned %T, want *RewirableReflector", restored)
+	}
+
+	spec.Rewirable = false
+	plain, err := CreateFromSpec(spec, alph)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := plain.(*RewirableReflector); ok {
+		t.Error("a spec without rewirable should build a fixed reflector")
+	}
+}
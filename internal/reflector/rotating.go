@@ -0,0 +1,81 @@
+// Package reflector provides rotating and settable reflectors modelled on the
+// Enigma G and the Enigma K.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package reflector
+
+import (
+	"github.com/coredds/enigoma/internal/alphabet"
+)
+
+// RotatingReflector is a reflector that turns like a rotor, as on the gear
+// driven Enigma G: it can be set to any position and is advanced by the
+// leftmost rotor's turnovers. Its wiring stays reciprocal in every position.
+// A settable reflector, as on the Enigma K, is set the same way but never
+// turns.
+type RotatingReflector struct {
+	BasicReflector
+	position   int
+	stationary bool
+}
+
+// NewRotatingReflector creates a rotating reflector from a mapping string, as
+// accepted by NewReflector, set to position 0.
+func NewRotatingReflector(id string, alph *alphabet.Alphabet, mapping string) (*RotatingReflector, error) {
+	refl, err := NewReflector(id, alph, mapping)
+	if err != nil {
+		return nil, err
+	}
+	return &RotatingReflector{BasicReflector: *refl.(*BasicReflector)}, nil
+}
+
+// NewSettableReflector creates a reflector that can be set to any position
+// but does not turn with the rotors, set to position 0.
+func NewSettableReflector(id string, alph *alphabet.Alphabet, mapping string) (*RotatingReflector, error) {
+	rr, err := NewRotatingReflector(id, alph, mapping)
+	if err != nil {
+		return nil, err
+	}
+	rr.stationary = true
+	return rr, nil
+}
+
+// Turns reports whether the reflector is advanced by the rotors; it is false
+// for a settable reflector.
+func (r *RotatingReflector) Turns() bool {
+	return !r.stationary
+}
+
+// Reflect performs the reflection at the current position.
+func (r *RotatingReflector) Reflect(inputIdx int) int {
+	if inputIdx < 0 || inputIdx >= r.size {
+		return inputIdx // Invalid input, return as-is
+	}
+	shifted := r.mapping[(inputIdx+r.position)%r.size]
+	return (shifted - r.position + r.size) % r.size
+}
+
+// Position returns the current position.
+func (r *RotatingReflector) Position() int {
+	return r.position
+}
+
+// SetPosition sets the position, wrapping around the alphabet.
+func (r *RotatingReflector) SetPosition(position int) {
+	r.position = ((position % r.size) + r.size) % r.size
+}
+
+// Step advances the reflector by one position.
+func (r *RotatingReflector) Step() {
+	r.position = (r.position + 1) % r.size
+}
+
+// Clone creates a deep copy of the reflector.
+func (r *RotatingReflector) Clone() Reflector {
+	return &RotatingReflector{
+		BasicReflector: *r.BasicReflector.Clone().(*BasicReflector),
+		position:       r.position,
+		stationary:     r.stationary,
+	}
+}
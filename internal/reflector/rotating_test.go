@@ -0,0 +1,97 @@
+package reflector
+
+import "testing"
+
+func TestRotatingReflector(t *testing.T) {
+	alph := createTestAlphabet()
+	r, err := NewRotatingReflector("UKW", alph, "BADC")
+	if err != nil {
+		t.Fatalf("NewRotatingReflector() error: %v", err)
+	}
+
+	for pos := 0; pos < alph.Size(); pos++ {
+		r.SetPosition(pos)
+		for i := 0; i < alph.Size(); i++ {
+			out := r.Reflect(i)
+			if out == i || r.Reflect(out) != i {
+				t.Errorf("position %d: Reflect(%d) = %d is not a reciprocal pairing", pos, i, out)
+			}
+		}
+	}
+
+	r.SetPosition(0)
+	if got := r.Reflect(1); got != 0 {
+		t.Errorf("Reflect(B) at position 0 = %d, want 0", got)
+	}
+	r.Step()
+	if r.Position() != 1 {
+		t.Errorf("Position() after Step = %d, want 1", r.Position())
+	}
+	// Turned by one, B meets the wiring at C, which leads to D and leaves as C
+	if got := r.Reflect(1); got != 2 {
+		t.Errorf("Reflect(B) at position 1 = %d, want 2", got)
+	}
+	r.SetPosition(-1)
+	if r.Position() != 3 {
+		t.Errorf("SetPosition(-1) = %d, want 3", r.Position())
+	}
+
+	clone := r.Clone().(*RotatingReflector)
+	clone.Step()
+	if r.Position() != 3 || clone.Position() != 0 {
+		t.Error("stepping a clone moved the original")
+	}
+}
+
+func TestRotatingSpecRoundTrip(t *testing.T) {
+	alph := createTestAlphabet()
+	refl, err := CreateFromSpec(ReflectorSpec{ID: "UKW", Mapping: "BADC", Rotating: true, Position: 2}, alph)
+	if err != nil {
+		t.Fatalf("CreateFromSpec() error: %v", err)
+	}
+	r, ok := refl.(*RotatingReflector)
+	if !ok || r.Position() != 2 {
+		t.Fatalf("CreateFromSpec() = %T at %d, want a rotating reflector at 2", refl, r.Position())
+	}
+
+	spec, err := ToSpec(r, alph)
+	if err != nil {
+		t.Fatalf("ToSpec() error: %v", err)
+	}
+	if !spec.Rotating || spec.Position != 2 || spec.Mapping != "BADC" {
+		t.Errorf("spec = %+v, want rotating at 2 with mapping BADC", spec)
+	}
+
+	for name, bad := range map[string]ReflectorSpec{
+		"rewirable and rotating": {Mapping: "BADC", Rotating: true, Rewirable: true},
+		"position out of range":  {Mapping: "BADC", Rotating: true, Position: 4},
+		"position when fixed":    {Mapping: "BADC", Position: 1},
+	} {
+		if _, err := CreateFromSpec(bad, alph); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestSettableReflectorSpec(t *testing.T) {
+	alph := createTestAlphabet()
+	refl, err := CreateFromSpec(ReflectorSpec{ID: "UKW", Mapping: "BADC", Settable: true, Position: 3}, alph)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr, ok := refl.(*RotatingReflector)
+	if !ok || rr.Turns() || rr.Position() != 3 {
+		t.Fatalf("expected a stationary reflector at 3, got %T %+v", refl, refl)
+	}
+	spec, err := ToSpec(rr.Clone(), alph)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !spec.Settable || spec.Rotating || spec.Position != 3 {
+		t.Errorf("ToSpec = %+v", spec)
+	}
+
+	if _, err := CreateFromSpec(ReflectorSpec{ID: "UKW", Mapping: "BADC", Settable: true, Rotating: true}, alph); err == nil {
+		t.Error("expected an error for a reflector both settable and rotating")
+	}
+}
@@ -6,11 +6,11 @@
 package rotor
 
 import (
-	"crypto/rand"
 	"fmt"
-	"math/big"
 
 	"github.com/coredds/enigoma/internal/alphabet"
+	"github.com/coredds/enigoma/internal/provenance"
+	"github.com/coredds/enigoma/internal/random"
 )
 
 // Rotor represents a single rotor with its internal wiring and notch positions.
@@ -31,12 +31,15 @@ type Rotor interface {
 type BasicRotor struct {
 	id          string
 	alphabet    *alphabet.Alphabet
-	forwardMap  []int
-	backwardMap []int
+	forwardMap  []int // Wiring twice over, so offset inputs need no wrap
+	backwardMap []int // Inverse wiring, twice over like forwardMap
 	notches     []int
+	notchAt     []bool // Whether each position is a notch; never modified, so clones share it
 	position    int
 	ringSetting int
+	offset      int // position - ringSetting within [0, size), kept up to date for substitute
 	size        int
+	provenance  provenance.Provenance // Carried through specs; does not affect encryption
 }
 
 // NewRotor creates a new rotor with the specified parameters.
@@ -54,9 +57,9 @@ func NewRotor(id string, alph *alphabet.Alphabet, forwardMapping string, notches
 			len(forwardMappingRunes), size)
 	}
 
-	// Convert forward mapping string to indices
-	forwardMap := make([]int, size)
-	backwardMap := make([]int, size)
+	// Convert forward mapping string to indices, repeated in the second half
+	forwardMap := make([]int, 2*size)
+	backwardMap := make([]int, 2*size)
 	used := make([]bool, size)
 
 	for i, r := range forwardMappingRunes {
@@ -69,19 +72,21 @@ func NewRotor(id string, alph *alphabet.Alphabet, forwardMapping string, notches
 			return nil, fmt.Errorf("duplicate output character in forward mapping: %c", r)
 		}
 
-		forwardMap[i] = outputIdx
-		backwardMap[outputIdx] = i
+		forwardMap[i], forwardMap[size+i] = outputIdx, outputIdx
+		backwardMap[outputIdx], backwardMap[size+outputIdx] = i, i
 		used[outputIdx] = true
 	}
 
 	// Convert notch runes to indices
 	notchIndices := make([]int, len(notches))
+	notchAt := make([]bool, size)
 	for i, r := range notches {
 		idx, err := alph.RuneToIndex(r)
 		if err != nil {
 			return nil, fmt.Errorf("invalid notch character: %v", err)
 		}
 		notchIndices[i] = idx
+		notchAt[idx] = true
 	}
 
 	return &BasicRotor{
@@ -90,6 +95,7 @@ func NewRotor(id string, alph *alphabet.Alphabet, forwardMapping string, notches
 		forwardMap:  forwardMap,
 		backwardMap: backwardMap,
 		notches:     notchIndices,
+		notchAt:     notchAt,
 		position:    0,
 		ringSetting: 0,
 		size:        size,
@@ -98,6 +104,11 @@ func NewRotor(id string, alph *alphabet.Alphabet, forwardMapping string, notches
 
 // RandomRotor generates a cryptographically random rotor with random notch positions.
 func RandomRotor(id string, alph *alphabet.Alphabet) (Rotor, error) {
+	return RandomRotorFrom(id, alph, random.Crypto)
+}
+
+// RandomRotorFrom generates a random rotor drawing from the given source.
+func RandomRotorFrom(id string, alph *alphabet.Alphabet, src random.Source) (Rotor, error) {
 	if alph == nil {
 		return nil, fmt.Errorf("alphabet cannot be nil")
 	}
@@ -107,20 +118,23 @@ func RandomRotor(id string, alph *alphabet.Alphabet) (Rotor, error) {
 
 	// Generate random permutation using Fisher-Yates shuffle
 	for i := size - 1; i > 0; i-- {
-		jBig, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		j, err := src.Intn(i + 1)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate random number: %v", err)
 		}
-		j := int(jBig.Int64())
 		runes[i], runes[j] = runes[j], runes[i]
 	}
 
 	// Generate 1-3 random notch positions
-	numNotchesBig, err := rand.Int(rand.Reader, big.NewInt(3))
+	numNotches, err := src.Intn(3)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate random notch count: %v", err)
 	}
-	numNotches := int(numNotchesBig.Int64()) + 1
+	numNotches++
+	if numNotches > size {
+		// A two-character rotor has no room for a third distinct notch
+		numNotches = size
+	}
 
 	notches := make([]rune, numNotches)
 	notchPositions := make(map[int]bool)
@@ -128,11 +142,10 @@ func RandomRotor(id string, alph *alphabet.Alphabet) (Rotor, error) {
 	for i := 0; i < numNotches; i++ {
 		var pos int
 		for {
-			posBig, err := rand.Int(rand.Reader, big.NewInt(int64(size)))
+			pos, err = src.Intn(size)
 			if err != nil {
 				return nil, fmt.Errorf("failed to generate random notch position: %v", err)
 			}
-			pos = int(posBig.Int64())
 			if !notchPositions[pos] {
 				break
 			}
@@ -154,15 +167,7 @@ func (r *BasicRotor) Forward(inputIdx int) int {
 	if inputIdx < 0 || inputIdx >= r.size {
 		return inputIdx // Invalid input, return as-is
 	}
-
-	// Apply position offset
-	adjustedInput := (inputIdx + r.position - r.ringSetting + r.size) % r.size
-
-	// Apply rotor wiring
-	output := r.forwardMap[adjustedInput]
-
-	// Apply position offset to output
-	return (output - r.position + r.ringSetting + r.size) % r.size
+	return r.substitute(r.forwardMap, inputIdx)
 }
 
 // Backward performs the backward substitution through the rotor.
@@ -170,40 +175,72 @@ func (r *BasicRotor) Backward(inputIdx int) int {
 	if inputIdx < 0 || inputIdx >= r.size {
 		return inputIdx // Invalid input, return as-is
 	}
+	return r.substitute(r.backwardMap, inputIdx)
+}
 
-	// Apply position offset
-	adjustedInput := (inputIdx + r.position - r.ringSetting + r.size) % r.size
+// ForwardUnchecked is Forward for an index known to be within the alphabet,
+// without the range check. An index outside it panics or returns garbage.
+func (r *BasicRotor) ForwardUnchecked(inputIdx int) int {
+	return r.substitute(r.forwardMap, inputIdx)
+}
 
-	// Apply rotor wiring
-	output := r.backwardMap[adjustedInput]
+// BackwardUnchecked is Backward for an index known to be within the
+// alphabet, without the range check.
+func (r *BasicRotor) BackwardUnchecked(inputIdx int) int {
+	return r.substitute(r.backwardMap, inputIdx)
+}
 
-	// Apply position offset to output
-	return (output - r.position + r.ringSetting + r.size) % r.size
+// substitute passes a valid index through wiring at the current position
+// and ring setting. The offset is within [0, size), so the offset input
+// stays below 2*size, where the doubled wiring needs no wrap, and the output
+// wraps by adding size at most once. That wrap uses the sign bit as a mask
+// instead of a branch: on text it goes either way at random, and mispredicted
+// branches would cost more than the arithmetic.
+func (r *BasicRotor) substitute(wiring []int, inputIdx int) int {
+	// Apply position offset and rotor wiring, then the offset in reverse
+	output := wiring[inputIdx+r.offset] - r.offset
+	return output + r.size&(output>>63)
 }
 
 // IsAtNotch returns true if the rotor is at a notch position.
 func (r *BasicRotor) IsAtNotch() bool {
-	for _, notch := range r.notches {
-		if r.position == notch {
-			return true
-		}
-	}
-	return false
+	return r.notchAt[r.position]
 }
 
 // Step advances the rotor position by one.
 func (r *BasicRotor) Step() {
-	r.position = (r.position + 1) % r.size
+	r.position++
+	if r.position == r.size {
+		r.position = 0
+	}
+	r.offset++
+	if r.offset == r.size {
+		r.offset = 0
+	}
 }
 
 // SetPosition sets the rotor position.
 func (r *BasicRotor) SetPosition(pos int) {
 	r.position = ((pos % r.size) + r.size) % r.size
+	r.updateOffset()
 }
 
 // SetRingSetting sets the ring setting of the rotor.
 func (r *BasicRotor) SetRingSetting(ring int) {
 	r.ringSetting = ((ring % r.size) + r.size) % r.size
+	r.updateOffset()
+}
+
+// updateOffset recomputes offset after the position or ring setting changed.
+func (r *BasicRotor) updateOffset() {
+	r.offset = (r.position - r.ringSetting + r.size) % r.size
+}
+
+// Offset returns the position less the ring setting, within [0, size): the
+// rotation the wiring is applied at. Two rotors with the same wiring and
+// offset substitute alike whatever their positions and ring settings.
+func (r *BasicRotor) Offset() int {
+	return r.offset
 }
 
 // GetPosition returns the current rotor position.
@@ -233,9 +270,12 @@ func (r *BasicRotor) Clone() Rotor {
 		forwardMap:  forwardMap,
 		backwardMap: backwardMap,
 		notches:     notches,
+		notchAt:     r.notchAt,
 		position:    r.position,
 		ringSetting: r.ringSetting,
+		offset:      r.offset,
 		size:        r.size,
+		provenance:  r.provenance,
 	}
 }
 
@@ -246,6 +286,8 @@ type RotorSpec struct {
 	Notches        []rune `json:"notches"`
 	Position       int    `json:"position"`
 	RingSetting    int    `json:"ring_setting"`
+
+	Provenance *provenance.Provenance `json:"provenance,omitempty"` // Optional origin of the wiring
 }
 
 // CreateFromSpec creates a rotor from a specification.
@@ -257,6 +299,9 @@ func CreateFromSpec(spec RotorSpec, alph *alphabet.Alphabet) (Rotor, error) {
 
 	rotor.SetPosition(spec.Position)
 	rotor.SetRingSetting(spec.RingSetting)
+	if br, ok := rotor.(*BasicRotor); ok && !spec.Provenance.IsZero() {
+		br.provenance = *spec.Provenance
+	}
 
 	return rotor, nil
 }
@@ -291,6 +336,7 @@ func ToSpec(rotor Rotor, alph *alphabet.Alphabet) (RotorSpec, error) {
 			Notches:        notches,
 			Position:       br.position,
 			RingSetting:    br.ringSetting,
+			Provenance:     br.provenance.Copy(),
 		}, nil
 	}
 
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/coredds/enigoma/internal/alphabet"
+	"github.com/coredds/enigoma/internal/provenance"
 )
 
 func createTestAlphabet() *alphabet.Alphabet {
@@ -118,6 +119,16 @@ func TestRandomRotor(t *testing.T) {
 	}
 }
 
+func TestRandomRotorTwoCharacters(t *testing.T) {
+	alph, _ := alphabet.New([]rune{'A', 'B'})
+	// Draw enough rotors that some ask for three notches
+	for i := 0; i < 50; i++ {
+		if _, err := RandomRotor("tiny", alph); err != nil {
+			t.Fatalf("RandomRotor() error: %v", err)
+		}
+	}
+}
+
 func TestBasicRotor_Forward(t *testing.T) {
 	alph := createTestAlphabet()
 	// Mapping: A->E, B->A, C->B, D->D, E->C
@@ -195,6 +206,28 @@ func TestBasicRotor_ForwardBackwardRoundtrip(t *testing.T) {
 	}
 }
 
+func TestBasicRotor_Unchecked(t *testing.T) {
+	alph := createTestAlphabet()
+	r, err := NewRotor("test", alph, "EABDC", []rune{'B'})
+	if err != nil {
+		t.Fatalf("Failed to create rotor: %v", err)
+	}
+	basic := r.(*BasicRotor)
+	basic.SetRingSetting(3)
+
+	for pos := 0; pos < alph.Size(); pos++ {
+		basic.SetPosition(pos)
+		for i := 0; i < alph.Size(); i++ {
+			if got, want := basic.ForwardUnchecked(i), basic.Forward(i); got != want {
+				t.Errorf("position %d: ForwardUnchecked(%d) = %d, Forward gives %d", pos, i, got, want)
+			}
+			if got, want := basic.BackwardUnchecked(i), basic.Backward(i); got != want {
+				t.Errorf("position %d: BackwardUnchecked(%d) = %d, Backward gives %d", pos, i, got, want)
+			}
+		}
+	}
+}
+
 func TestBasicRotor_IsAtNotch(t *testing.T) {
 	alph := createTestAlphabet()
 	rotor, err := NewRotor("test", alph, "EABDC", []rune{'B', 'D'})
@@ -440,3 +473,37 @@ func TestToSpec(t *testing.T) {
 		t.Errorf("Spec notches = %v, want [B]", spec.Notches)
 	}
 }
+
+func TestSpecProvenance(t *testing.T) {
+	alph := createTestAlphabet()
+	src := &provenance.Provenance{Source: "library:custom", Creator: "Alice", CreatedAt: "2025-01-02T15:04:05Z"}
+
+	rotor, err := CreateFromSpec(RotorSpec{
+		ID:             "custom",
+		ForwardMapping: "EABDC",
+		Notches:        []rune{'B'},
+		Provenance:     src,
+	}, alph)
+	if err != nil {
+		t.Fatalf("CreateFromSpec() error: %v", err)
+	}
+	src.Creator = "Mallory" // The rotor keeps its own copy
+
+	for _, r := range []Rotor{rotor, rotor.Clone()} {
+		spec, err := ToSpec(r, alph)
+		if err != nil {
+			t.Fatalf("ToSpec() error: %v", err)
+		}
+		if spec.Provenance == nil || *spec.Provenance != (provenance.Provenance{Source: "library:custom", Creator: "Alice", CreatedAt: "2025-01-02T15:04:05Z"}) {
+			t.Errorf("Spec provenance = %+v", spec.Provenance)
+		}
+	}
+
+	plain, err := NewRotor("plain", alph, "EABDC", nil)
+	if err != nil {
+		t.Fatalf("Failed to create rotor: %v", err)
+	}
+	if spec, _ := ToSpec(plain, alph); spec.Provenance != nil {
+		t.Errorf("Spec provenance = %+v, want nil", spec.Provenance)
+	}
+}
@@ -0,0 +1,175 @@
+// Package textconfig converts configuration documents between JSON, YAML and
+// TOML. Documents pass through an ordered tree, so keys keep the order of the
+// JSON they came from and numbers keep their exact spelling. Only the subset
+// of YAML and TOML needed for configuration files is supported: no anchors,
+// tags, multi-document streams or TOML dates.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package textconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+// Kind is the type of a Node.
+type Kind int
+
+const (
+	Null Kind = iota
+	Bool
+	Number
+	String
+	Array
+	Object
+)
+
+// Node is one value of a document.
+type Node struct {
+	Kind   Kind
+	Scalar string   // Text of a Bool ("true"/"false"), Number or String
+	Keys   []string // Keys of an Object, in document order
+	Items  []*Node  // Values of an Object (parallel to Keys) or elements of an Array
+}
+
+// Get returns the value of key in an Object, or nil.
+func (n *Node) Get(key string) *Node {
+	for i, k := range n.Keys {
+		if k == key {
+			return n.Items[i]
+		}
+	}
+	return nil
+}
+
+// set adds key to an Object, replacing an existing value.
+func (n *Node) set(key string, value *Node) {
+	for i, k := range n.Keys {
+		if k == key {
+			n.Items[i] = value
+			return
+		}
+	}
+	n.Keys = append(n.Keys, key)
+	n.Items = append(n.Items, value)
+}
+
+// FromJSON parses a JSON document.
+func FromJSON(data []byte) (*Node, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	n, err := decodeJSONValue(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after the JSON document")
+	}
+	return n, nil
+}
+
+func decodeJSONValue(dec *json.Decoder) (*Node, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case nil:
+		return &Node{Kind: Null}, nil
+	case bool:
+		return &Node{Kind: Bool, Scalar: strconv.FormatBool(t)}, nil
+	case json.Number:
+		return &Node{Kind: Number, Scalar: t.String()}, nil
+	case string:
+		return &Node{Kind: String, Scalar: t}, nil
+	case json.Delim:
+		if t == '[' {
+			n := &Node{Kind: Array}
+			for dec.More() {
+				item, err := decodeJSONValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				n.Items = append(n.Items, item)
+			}
+			_, err := dec.Token()
+			return n, err
+		}
+		n := &Node{Kind: Object}
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeJSONValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			n.set(keyTok.(string), value)
+		}
+		_, err := dec.Token()
+		return n, err
+	}
+	return nil, fmt.Errorf("unexpected JSON token %v", tok)
+}
+
+// JSON encodes n as indented JSON.
+func (n *Node) JSON() []byte {
+	var buf bytes.Buffer
+	n.writeJSON(&buf, "")
+	return buf.Bytes()
+}
+
+func (n *Node) writeJSON(buf *bytes.Buffer, indent string) {
+	switch n.Kind {
+	case Null:
+		buf.WriteString("null")
+	case Bool, Number:
+		buf.WriteString(n.Scalar)
+	case String:
+		buf.WriteString(quoteString(n.Scalar))
+	case Array, Object:
+		open, close := "[", "]"
+		if n.Kind == Object {
+			open, close = "{", "}"
+		}
+		buf.WriteString(open)
+		for i, item := range n.Items {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteString("\n" + indent + "  ")
+			if n.Kind == Object {
+				buf.WriteString(quoteString(n.Keys[i]) + ": ")
+			}
+			item.writeJSON(buf, indent+"  ")
+		}
+		if len(n.Items) > 0 {
+			buf.WriteString("\n" + indent)
+		}
+		buf.WriteString(close)
+	}
+}
+
+// errTooDeep reports a document nested deeper than the parser allows.
+func errTooDeep(max int) error {
+	return fmt.Errorf("document nests deeper than %d levels", max)
+}
+
+// jsonNumber matches a number in JSON syntax.
+var jsonNumber = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// quoteString returns s as a double-quoted string with JSON escapes, which
+// YAML and TOML basic strings accept as well. Non-ASCII text is kept as is.
+func quoteString(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s) // Strings always encode
+	return string(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+}
@@ -0,0 +1,199 @@
+package textconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sampleJSON = `{
+  "schema_version": 1,
+  "alphabet": "ABC \"quoted\" é\n#tab\t",
+  "rotor_specs": [
+    {"id": "I", "notches": [81, 82], "position": 9, "provenance": {"source": "historical:M3/I"}},
+    {"id": "true", "notches": [], "position": -1.5e3}
+  ],
+  "reflector_spec": {"id": "B", "mapping": "YRUH: #x"},
+  "plugboard_pairs": {"A": "B", "é": "#"},
+  "empty": {},
+  "flag": false,
+  "nested": [[1, 2], [3]],
+  "metadata": {"created_at": "2025-01-02T03:04:05Z", "tags": ["a b", "yes", ""]}
+}`
+
+// sameJSON compares two JSON documents by value.
+func sameJSON(t *testing.T, a, b []byte) bool {
+	t.Helper()
+	var va, vb any
+	if err := json.Unmarshal(a, &va); err != nil {
+		t.Fatalf("invalid JSON %s: %v", a, err)
+	}
+	if err := json.Unmarshal(b, &vb); err != nil {
+		t.Fatalf("invalid JSON %s: %v", b, err)
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+func TestJSONRoundTripKeepsOrder(t *testing.T) {
+	n, err := FromJSON([]byte(sampleJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sameJSON(t, n.JSON(), []byte(sampleJSON)) {
+		t.Errorf("JSON round trip changed the document:\n%s", n.JSON())
+	}
+	if n.Keys[0] != "schema_version" || n.Keys[len(n.Keys)-1] != "metadata" {
+		t.Errorf("keys out of order: %v", n.Keys)
+	}
+}
+
+func TestYAMLRoundTrip(t *testing.T) {
+	n, err := FromJSON([]byte(sampleJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := n.YAML()
+	back, err := FromYAML(doc, 32)
+	if err != nil {
+		t.Fatalf("FromYAML failed: %v\n%s", err, doc)
+	}
+	if !sameJSON(t, back.JSON(), []byte(sampleJSON)) {
+		t.Errorf("YAML round trip changed the document:\n%s\n%s", doc, back.JSON())
+	}
+	for _, want := range []string{"schema_version: 1\n", "  - id: I\n", "    notches: [81, 82]\n", `  "é": "#"`} {
+		if !bytes.Contains(doc, []byte(want)) {
+			t.Errorf("missing %q in:\n%s", want, doc)
+		}
+	}
+}
+
+func TestTOMLRoundTrip(t *testing.T) {
+	n, err := FromJSON([]byte(sampleJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := n.TOML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	back, err := FromTOML(doc, 32)
+	if err != nil {
+		t.Fatalf("FromTOML failed: %v\n%s", err, doc)
+	}
+	if !sameJSON(t, back.JSON(), []byte(sampleJSON)) {
+		t.Errorf("TOML round trip changed the document:\n%s\n%s", doc, back.JSON())
+	}
+	for _, want := range []string{"schema_version = 1\n", "[[rotor_specs]]\n", "[rotor_specs.provenance]\n", "empty = {}\n"} {
+		if !bytes.Contains(doc, []byte(want)) {
+			t.Errorf("missing %q in:\n%s", want, doc)
+		}
+	}
+}
+
+func TestFromYAMLHandWritten(t *testing.T) {
+	doc := `---
+# A key written by hand
+alphabet: ABCD   # trailing comment
+positions:
+- 1
+- 2
+spec:
+  id: 'it''s'
+  list: [a, "b, c", {k: v}]
+  escaped: "\x41\u00e9"
+  empty:
+text: hello, world [1]
+`
+	n, err := FromYAML([]byte(doc), 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"alphabet":"ABCD","positions":[1,2],"spec":{"id":"it's","list":["a","b, c",{"k":"v"}],"escaped":"Aé","empty":null},"text":"hello, world [1]"}`
+	if !sameJSON(t, n.JSON(), []byte(want)) {
+		t.Errorf("got %s", n.JSON())
+	}
+}
+
+func TestFromTOMLHandWritten(t *testing.T) {
+	doc := `# A key written by hand
+alphabet = 'ABCD'
+positions = [
+  1, 2,  # trailing comma allowed
+]
+big = 1_000
+hex = 0x1F
+spec.id = "I"
+
+[plugboard_pairs]
+"A" = "B"
+
+[[rotor_specs]]
+id = """
+multi"""
+`
+	n, err := FromTOML([]byte(doc), 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"alphabet":"ABCD","positions":[1,2],"big":1000,"hex":31,"spec":{"id":"I"},"plugboard_pairs":{"A":"B"},"rotor_specs":[{"id":"multi"}]}`
+	if !sameJSON(t, n.JSON(), []byte(want)) {
+		t.Errorf("got %s", n.JSON())
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	yamlDocs := []string{
+		"a: 1\n  b: 2",
+		"a: [1, 2",
+		"a: |\n  text",
+		"a: 1\na: 2",
+		"a:\n\t- 1",
+		"a: 1\n---\nb: 2",
+	}
+	for _, doc := range yamlDocs {
+		if _, err := FromYAML([]byte(doc), 32); err == nil {
+			t.Errorf("FromYAML(%q) should fail", doc)
+		}
+	}
+	tomlDocs := []string{
+		"a = 1\na = 2",
+		"a = 2025-01-02",
+		"a = [1, 2",
+		"a = \"unterminated",
+		"a = 1 b = 2",
+		"a = 1\n[a]",
+	}
+	for _, doc := range tomlDocs {
+		if _, err := FromTOML([]byte(doc), 32); err == nil {
+			t.Errorf("FromTOML(%q) should fail", doc)
+		}
+	}
+}
+
+func TestDepthLimit(t *testing.T) {
+	deep := strings.Repeat("[", 40) + strings.Repeat("]", 40)
+	if _, err := FromYAML([]byte("a: "+deep), 32); err == nil {
+		t.Error("deep YAML should be rejected")
+	}
+	if _, err := FromTOML([]byte("a = "+deep), 32); err == nil {
+		t.Error("deep TOML should be rejected")
+	}
+	if _, err := FromYAML([]byte("a: "+deep), -1); err != nil {
+		t.Errorf("a negative limit should disable the check: %v", err)
+	}
+}
+
+func TestTOMLRejectsNullInArray(t *testing.T) {
+	n, err := FromJSON([]byte(`{"a": [1, null]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := n.TOML(); err == nil {
+		t.Error("null array elements cannot be written as TOML")
+	}
+	if _, err := (&Node{Kind: Array}).TOML(); err == nil {
+		t.Error("a TOML document must be a table")
+	}
+}
@@ -0,0 +1,554 @@
+// Package textconfig provides the TOML encoder and decoder.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package textconfig
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// tomlBareKey matches keys that need no quotes.
+var tomlBareKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// TOML encodes n, which must be an Object, as a TOML document. Nested objects
+// become tables and arrays of objects become arrays of tables. TOML has no
+// null: null members are left out and null array elements are an error.
+func (n *Node) TOML() ([]byte, error) {
+	if n.Kind != Object {
+		return nil, fmt.Errorf("a TOML document must be a table")
+	}
+	var buf bytes.Buffer
+	if err := writeTOMLTable(&buf, n, nil); err != nil {
+		return nil, err
+	}
+	return bytes.TrimLeft(buf.Bytes(), "\n"), nil
+}
+
+// isTableArray reports whether an array is written as an array of tables.
+func isTableArray(n *Node) bool {
+	if n.Kind != Array || len(n.Items) == 0 {
+		return false
+	}
+	for _, item := range n.Items {
+		if item.Kind != Object {
+			return false
+		}
+	}
+	return true
+}
+
+// writeTOMLTable writes the members of a table whose header, if any, has
+// already been written. Plain values come first, as TOML requires.
+func writeTOMLTable(buf *bytes.Buffer, n *Node, path []string) error {
+	for i, key := range n.Keys {
+		item := n.Items[i]
+		if item.Kind == Null || (item.Kind == Object && len(item.Items) > 0) || isTableArray(item) {
+			continue
+		}
+		value, err := tomlInline(item)
+		if err != nil {
+			return fmt.Errorf("%s: %v", strings.Join(append(path, key), "."), err)
+		}
+		buf.WriteString(tomlKey(key) + " = " + value + "\n")
+	}
+	for i, key := range n.Keys {
+		item := n.Items[i]
+		sub := append(append([]string(nil), path...), key)
+		switch {
+		case item.Kind == Object && len(item.Items) > 0:
+			buf.WriteString("\n[" + tomlPath(sub) + "]\n")
+			if err := writeTOMLTable(buf, item, sub); err != nil {
+				return err
+			}
+		case isTableArray(item):
+			for _, element := range item.Items {
+				buf.WriteString("\n[[" + tomlPath(sub) + "]]\n")
+				if err := writeTOMLTable(buf, element, sub); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// tomlInline writes a value on one line, with inline arrays and tables.
+func tomlInline(n *Node) (string, error) {
+	switch n.Kind {
+	case Null:
+		return "", fmt.Errorf("TOML cannot represent null")
+	case Bool, Number:
+		return n.Scalar, nil
+	case String:
+		return quoteString(n.Scalar), nil
+	case Array:
+		items := make([]string, len(n.Items))
+		for i, item := range n.Items {
+			value, err := tomlInline(item)
+			if err != nil {
+				return "", err
+			}
+			items[i] = value
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	default:
+		var items []string
+		for i, item := range n.Items {
+			if item.Kind == Null {
+				continue
+			}
+			value, err := tomlInline(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, tomlKey(n.Keys[i])+" = "+value)
+		}
+		if len(items) == 0 {
+			return "{}", nil
+		}
+		return "{ " + strings.Join(items, ", ") + " }", nil
+	}
+}
+
+func tomlKey(key string) string {
+	if tomlBareKey.MatchString(key) {
+		return key
+	}
+	return quoteString(key)
+}
+
+func tomlPath(path []string) string {
+	keys := make([]string, len(path))
+	for i, key := range path {
+		keys[i] = tomlKey(key)
+	}
+	return strings.Join(keys, ".")
+}
+
+// tomlParser reads a TOML document.
+type tomlParser struct {
+	src      string
+	pos      int
+	line     int
+	maxDepth int
+}
+
+// FromTOML parses a TOML document into an Object. Documents nesting deeper
+// than maxDepth are rejected; a negative maxDepth disables the check. Dates and
+// times are not supported.
+func FromTOML(data []byte, maxDepth int) (*Node, error) {
+	if !utf8.Valid(data) {
+		return nil, fmt.Errorf("TOML is not valid UTF-8")
+	}
+	p := &tomlParser{src: string(data), line: 1, maxDepth: maxDepth}
+	root := &Node{Kind: Object}
+	if err := p.parse(root); err != nil {
+		return nil, fmt.Errorf("line %d: %v", p.line, err)
+	}
+	return root, nil
+}
+
+func (p *tomlParser) parse(root *Node) error {
+	current := root
+	for {
+		p.skipBlank()
+		if p.pos >= len(p.src) {
+			return nil
+		}
+
+		if p.src[p.pos] == '[' {
+			array := strings.HasPrefix(p.src[p.pos:], "[[")
+			if array {
+				p.pos += 2
+			} else {
+				p.pos++
+			}
+			path, err := p.key()
+			if err != nil {
+				return err
+			}
+			closing := "]"
+			if array {
+				closing = "]]"
+			}
+			p.skipSpace()
+			if !strings.HasPrefix(p.src[p.pos:], closing) {
+				return fmt.Errorf("expected %q after table name", closing)
+			}
+			p.pos += len(closing)
+			if err := p.endOfLine(); err != nil {
+				return err
+			}
+			if p.maxDepth >= 0 && len(path) >= p.maxDepth {
+				return errTooDeep(p.maxDepth)
+			}
+			if current, err = tomlTable(root, path, array); err != nil {
+				return err
+			}
+			continue
+		}
+
+		path, err := p.key()
+		if err != nil {
+			return err
+		}
+		p.skipSpace()
+		if p.pos >= len(p.src) || p.src[p.pos] != '=' {
+			return fmt.Errorf("expected '=' after key %q", strings.Join(path, "."))
+		}
+		p.pos++
+		value, err := p.value(len(path))
+		if err != nil {
+			return err
+		}
+		if err := p.endOfLine(); err != nil {
+			return err
+		}
+		table, err := tomlDescend(current, path[:len(path)-1])
+		if err != nil {
+			return err
+		}
+		last := path[len(path)-1]
+		if table.Get(last) != nil {
+			return fmt.Errorf("duplicate key %q", strings.Join(path, "."))
+		}
+		table.set(last, value)
+	}
+}
+
+// tomlTable finds or creates the table named by a [header] or [[header]].
+func tomlTable(root *Node, path []string, array bool) (*Node, error) {
+	parent, err := tomlDescend(root, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := path[len(path)-1]
+	existing := parent.Get(last)
+	if !array {
+		if existing == nil {
+			table := &Node{Kind: Object}
+			parent.set(last, table)
+			return table, nil
+		}
+		if existing.Kind != Object {
+			return nil, fmt.Errorf("%q is not a table", strings.Join(path, "."))
+		}
+		return existing, nil
+	}
+	if existing == nil {
+		existing = &Node{Kind: Array}
+		parent.set(last, existing)
+	} else if !isTableArray(existing) {
+		return nil, fmt.Errorf("%q is not an array of tables", strings.Join(path, "."))
+	}
+	table := &Node{Kind: Object}
+	existing.Items = append(existing.Items, table)
+	return table, nil
+}
+
+// tomlDescend follows path from table, creating missing tables. Arrays of
+// tables resolve to their last element.
+func tomlDescend(table *Node, path []string) (*Node, error) {
+	for _, key := range path {
+		next := table.Get(key)
+		switch {
+		case next == nil:
+			next = &Node{Kind: Object}
+			table.set(key, next)
+		case isTableArray(next):
+			next = next.Items[len(next.Items)-1]
+		case next.Kind != Object:
+			return nil, fmt.Errorf("%q is not a table", key)
+		}
+		table = next
+	}
+	return table, nil
+}
+
+func (p *tomlParser) skipSpace() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// skipBlank skips whitespace, newlines and comments.
+func (p *tomlParser) skipBlank() {
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case ' ', '\t', '\r':
+			p.pos++
+		case '\n':
+			p.pos++
+			p.line++
+		case '#':
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// endOfLine accepts trailing whitespace and a comment before the newline.
+func (p *tomlParser) endOfLine() error {
+	p.skipSpace()
+	if p.pos < len(p.src) && p.src[p.pos] == '#' {
+		for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+			p.pos++
+		}
+	}
+	if p.pos < len(p.src) && p.src[p.pos] == '\r' {
+		p.pos++
+	}
+	if p.pos < len(p.src) && p.src[p.pos] != '\n' {
+		return fmt.Errorf("unexpected %q at end of line", p.src[p.pos])
+	}
+	return nil
+}
+
+// key reads a bare, quoted or dotted key.
+func (p *tomlParser) key() ([]string, error) {
+	var path []string
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.src) {
+			return nil, fmt.Errorf("expected a key")
+		}
+		switch c := p.src[p.pos]; {
+		case c == '"' || c == '\'':
+			s, err := p.str()
+			if err != nil {
+				return nil, err
+			}
+			path = append(path, s)
+		default:
+			start := p.pos
+			for p.pos < len(p.src) && tomlBareKey.MatchString(p.src[p.pos:p.pos+1]) {
+				p.pos++
+			}
+			if start == p.pos {
+				return nil, fmt.Errorf("expected a key, found %q", c)
+			}
+			path = append(path, p.src[start:p.pos])
+		}
+		p.skipSpace()
+		if p.pos >= len(p.src) || p.src[p.pos] != '.' {
+			return path, nil
+		}
+		p.pos++
+	}
+}
+
+func (p *tomlParser) value(depth int) (*Node, error) {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return nil, fmt.Errorf("expected a value")
+	}
+	switch c := p.src[p.pos]; {
+	case c == '"' || c == '\'':
+		s, err := p.str()
+		if err != nil {
+			return nil, err
+		}
+		return &Node{Kind: String, Scalar: s}, nil
+	case c == '[' || c == '{':
+		if p.maxDepth >= 0 && depth >= p.maxDepth {
+			return nil, errTooDeep(p.maxDepth)
+		}
+		if c == '[' {
+			return p.array(depth)
+		}
+		return p.inlineTable(depth)
+	}
+
+	start := p.pos
+	for p.pos < len(p.src) && !strings.ContainsRune(" \t\r\n,]}#", rune(p.src[p.pos])) {
+		p.pos++
+	}
+	word := p.src[start:p.pos]
+	switch word {
+	case "true", "false":
+		return &Node{Kind: Bool, Scalar: word}, nil
+	}
+	number, err := tomlNumber(word)
+	if err != nil {
+		return nil, err
+	}
+	return &Node{Kind: Number, Scalar: number}, nil
+}
+
+// tomlNumber converts a TOML integer or float to JSON number syntax.
+func tomlNumber(word string) (string, error) {
+	if word == "" {
+		return "", fmt.Errorf("expected a value")
+	}
+	if len(word) >= 10 && word[4] == '-' {
+		return "", fmt.Errorf("dates are not supported: %s", word)
+	}
+	clean := strings.TrimPrefix(strings.ReplaceAll(word, "_", ""), "+")
+	for prefix, base := range map[string]int{"0x": 16, "0o": 8, "0b": 2} {
+		if strings.HasPrefix(clean, prefix) {
+			v, err := strconv.ParseUint(clean[2:], base, 64)
+			if err != nil {
+				return "", fmt.Errorf("invalid number %q", word)
+			}
+			return strconv.FormatUint(v, 10), nil
+		}
+	}
+	if !jsonNumber.MatchString(clean) {
+		return "", fmt.Errorf("invalid value %q", word)
+	}
+	return clean, nil
+}
+
+func (p *tomlParser) array(depth int) (*Node, error) {
+	p.pos++ // [
+	n := &Node{Kind: Array}
+	for {
+		p.skipBlank()
+		if p.pos >= len(p.src) {
+			return nil, fmt.Errorf("unterminated array")
+		}
+		if p.src[p.pos] == ']' {
+			p.pos++
+			return n, nil
+		}
+		item, err := p.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		n.Items = append(n.Items, item)
+		p.skipBlank()
+		if p.pos < len(p.src) && p.src[p.pos] == ',' {
+			p.pos++
+		} else if p.pos >= len(p.src) || p.src[p.pos] != ']' {
+			return nil, fmt.Errorf("expected ',' or ']' in array")
+		}
+	}
+}
+
+func (p *tomlParser) inlineTable(depth int) (*Node, error) {
+	p.pos++ // {
+	n := &Node{Kind: Object}
+	p.skipSpace()
+	if p.pos < len(p.src) && p.src[p.pos] == '}' {
+		p.pos++
+		return n, nil
+	}
+	for {
+		path, err := p.key()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if p.pos >= len(p.src) || p.src[p.pos] != '=' {
+			return nil, fmt.Errorf("expected '=' in inline table")
+		}
+		p.pos++
+		value, err := p.value(depth + len(path))
+		if err != nil {
+			return nil, err
+		}
+		table, err := tomlDescend(n, path[:len(path)-1])
+		if err != nil {
+			return nil, err
+		}
+		table.set(path[len(path)-1], value)
+
+		p.skipSpace()
+		if p.pos < len(p.src) && p.src[p.pos] == '}' {
+			p.pos++
+			return n, nil
+		}
+		if p.pos >= len(p.src) || p.src[p.pos] != ',' {
+			return nil, fmt.Errorf("expected ',' or '}' in inline table")
+		}
+		p.pos++
+	}
+}
+
+// str reads a basic or literal string, single-line or multi-line.
+func (p *tomlParser) str() (string, error) {
+	q := p.src[p.pos]
+	multi := strings.HasPrefix(p.src[p.pos:], strings.Repeat(string(q), 3))
+	delim := string(q)
+	if multi {
+		delim = strings.Repeat(delim, 3)
+		p.pos += 3
+		// A newline right after the opening delimiter is trimmed
+		if strings.HasPrefix(p.src[p.pos:], "\r\n") {
+			p.pos += 2
+			p.line++
+		} else if strings.HasPrefix(p.src[p.pos:], "\n") {
+			p.pos++
+			p.line++
+		}
+	} else {
+		p.pos++
+	}
+
+	var b strings.Builder
+	for p.pos < len(p.src) {
+		if strings.HasPrefix(p.src[p.pos:], delim) {
+			p.pos += len(delim)
+			return b.String(), nil
+		}
+		c := p.src[p.pos]
+		switch {
+		case c == '\n' && !multi:
+			return "", fmt.Errorf("newline in string")
+		case c == '\\' && q == '"':
+			if multi && strings.TrimLeft(strings.SplitN(p.src[p.pos+1:], "\n", 2)[0], " \t\r") == "" && strings.Contains(p.src[p.pos+1:], "\n") {
+				// Line-ending backslash: skip the newline and leading whitespace
+				p.pos++
+				for p.pos < len(p.src) && strings.IndexByte(" \t\r\n", p.src[p.pos]) >= 0 {
+					if p.src[p.pos] == '\n' {
+						p.line++
+					}
+					p.pos++
+				}
+				continue
+			}
+			r, n, err := tomlEscape(p.src[p.pos:])
+			if err != nil {
+				return "", err
+			}
+			b.WriteRune(r)
+			p.pos += n
+		default:
+			if c == '\n' {
+				p.line++
+			}
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	return "", fmt.Errorf("unterminated string")
+}
+
+// tomlEscape decodes the escape sequence at the start of s.
+func tomlEscape(s string) (rune, int, error) {
+	if len(s) < 2 {
+		return 0, 0, fmt.Errorf("unterminated escape")
+	}
+	simple := map[byte]rune{'b': '\b', 't': '\t', 'n': '\n', 'f': '\f', 'r': '\r', 'e': 0x1b, '"': '"', '\\': '\\', '/': '/'}
+	if r, ok := simple[s[1]]; ok {
+		return r, 2, nil
+	}
+	digits := map[byte]int{'x': 2, 'u': 4, 'U': 8}[s[1]]
+	if digits == 0 || len(s) < 2+digits {
+		return 0, 0, fmt.Errorf("invalid escape %q", s[:2])
+	}
+	v, err := strconv.ParseUint(s[2:2+digits], 16, 32)
+	if err != nil || !utf8.ValidRune(rune(v)) {
+		return 0, 0, fmt.Errorf("invalid escape %q", s[:2+digits])
+	}
+	return rune(v), 2 + digits, nil
+}
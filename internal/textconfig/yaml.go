@@ -0,0 +1,522 @@
+// Package textconfig provides the YAML encoder and decoder.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package textconfig
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// yamlPlain matches strings that can be written without quotes.
+var yamlPlain = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// yamlReserved lists plain words that YAML 1.1 readers take for booleans or null.
+var yamlReserved = map[string]bool{
+	"true": true, "false": true, "null": true, "yes": true, "no": true,
+	"on": true, "off": true, "y": true, "n": true,
+}
+
+// YAML encodes n as a YAML document. Objects and arrays holding collections
+// use block style; arrays of scalars stay on one line.
+func (n *Node) YAML() []byte {
+	var buf bytes.Buffer
+	switch {
+	case n.Kind == Object && len(n.Items) > 0:
+		writeYAMLObject(&buf, n, "")
+	case n.Kind == Array && len(n.Items) > 0 && !isFlat(n):
+		writeYAMLArray(&buf, n, "")
+	default:
+		buf.WriteString(yamlInline(n) + "\n")
+	}
+	return buf.Bytes()
+}
+
+func writeYAMLObject(buf *bytes.Buffer, n *Node, indent string) {
+	for i, key := range n.Keys {
+		buf.WriteString(indent + yamlString(key) + ":")
+		writeYAMLValue(buf, n.Items[i], indent)
+	}
+}
+
+func writeYAMLArray(buf *bytes.Buffer, n *Node, indent string) {
+	for _, item := range n.Items {
+		buf.WriteString(indent + "-")
+		switch {
+		case item.Kind == Object && len(item.Items) > 0:
+			// The first key shares the dash's line
+			var nested bytes.Buffer
+			writeYAMLObject(&nested, item, indent+"  ")
+			buf.WriteString(" " + strings.TrimPrefix(nested.String(), indent+"  "))
+		default:
+			writeYAMLValue(buf, item, indent)
+		}
+	}
+}
+
+// writeYAMLValue writes the value after a key or dash, including the newline.
+func writeYAMLValue(buf *bytes.Buffer, n *Node, indent string) {
+	switch {
+	case n.Kind == Object && len(n.Items) > 0:
+		buf.WriteString("\n")
+		writeYAMLObject(buf, n, indent+"  ")
+	case n.Kind == Array && len(n.Items) > 0 && !isFlat(n):
+		buf.WriteString("\n")
+		writeYAMLArray(buf, n, indent+"  ")
+	default:
+		buf.WriteString(" " + yamlInline(n) + "\n")
+	}
+}
+
+// isFlat reports whether every element of an array is a scalar.
+func isFlat(n *Node) bool {
+	for _, item := range n.Items {
+		if item.Kind == Array || item.Kind == Object {
+			return false
+		}
+	}
+	return true
+}
+
+// yamlInline writes n in flow style.
+func yamlInline(n *Node) string {
+	switch n.Kind {
+	case Null:
+		return "null"
+	case Bool, Number:
+		return n.Scalar
+	case String:
+		return yamlString(n.Scalar)
+	case Array:
+		items := make([]string, len(n.Items))
+		for i, item := range n.Items {
+			items[i] = yamlInline(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	default:
+		items := make([]string, len(n.Items))
+		for i, item := range n.Items {
+			items[i] = yamlString(n.Keys[i]) + ": " + yamlInline(item)
+		}
+		return "{" + strings.Join(items, ", ") + "}"
+	}
+}
+
+// yamlString writes s plain when it cannot be mistaken for anything else,
+// and double-quoted otherwise.
+func yamlString(s string) string {
+	if yamlPlain.MatchString(s) && !yamlReserved[strings.ToLower(s)] {
+		return s
+	}
+	return quoteString(s)
+}
+
+// yamlLine is a line of a YAML document with its indentation removed.
+type yamlLine struct {
+	num    int // 1-based line number
+	indent int
+	text   string
+}
+
+// yamlParser parses the block structure of a YAML document.
+type yamlParser struct {
+	lines    []yamlLine
+	pos      int
+	maxDepth int
+}
+
+// FromYAML parses a YAML document. Documents nesting deeper than maxDepth are
+// rejected; a negative maxDepth disables the check.
+func FromYAML(data []byte, maxDepth int) (*Node, error) {
+	if !utf8.Valid(data) {
+		return nil, fmt.Errorf("YAML is not valid UTF-8")
+	}
+	p := &yamlParser{maxDepth: maxDepth}
+	for i, raw := range strings.Split(string(data), "\n") {
+		raw = strings.TrimSuffix(raw, "\r")
+		text := strings.TrimLeft(raw, " ")
+		if strings.HasPrefix(text, "\t") {
+			return nil, fmt.Errorf("line %d: tabs cannot indent YAML", i+1)
+		}
+		text = strings.TrimRight(stripYAMLComment(text), " \t")
+		if text == "" || (len(p.lines) == 0 && text == "---") {
+			continue
+		}
+		if text == "---" || text == "..." {
+			return nil, fmt.Errorf("line %d: only one YAML document is supported", i+1)
+		}
+		p.lines = append(p.lines, yamlLine{num: i + 1, indent: len(raw) - len(strings.TrimLeft(raw, " ")), text: text})
+	}
+	if len(p.lines) == 0 {
+		return &Node{Kind: Null}, nil
+	}
+
+	n, err := p.parseBlock(p.lines[0].indent, 0)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, fmt.Errorf("line %d: unexpected indentation", p.lines[p.pos].num)
+	}
+	return n, nil
+}
+
+// stripYAMLComment removes a comment: a # at the start of the line or after
+// whitespace, outside quoted strings.
+func stripYAMLComment(s string) string {
+	quote := byte(0)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++ // Skip the escaped character
+		case quote == '\'' && c == '\'' && i+1 < len(s) && s[i+1] == '\'':
+			i++ // Escaped quote
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			// Quotes only start a string at the beginning of a scalar
+			if i == 0 || strings.IndexByte(" [{,", s[i-1]) >= 0 {
+				quote = c
+			}
+		case c == '#' && (i == 0 || s[i-1] == ' ' || s[i-1] == '\t'):
+			return s[:i]
+		}
+	}
+	return s
+}
+
+// parseBlock parses the block collection or scalar starting at the current line,
+// whose items are indented by indent.
+func (p *yamlParser) parseBlock(indent, depth int) (*Node, error) {
+	line := p.lines[p.pos]
+	if line.text == "-" || strings.HasPrefix(line.text, "- ") {
+		return p.parseSequence(indent, depth)
+	}
+	if _, _, ok, err := splitYAMLKey(line.text); err != nil {
+		return nil, fmt.Errorf("line %d: %v", line.num, err)
+	} else if ok {
+		return p.parseMapping(indent, depth)
+	}
+	p.pos++
+	n, err := parseYAMLFlow(line.text, depth, p.maxDepth)
+	if err != nil {
+		return nil, fmt.Errorf("line %d: %v", line.num, err)
+	}
+	return n, nil
+}
+
+func (p *yamlParser) parseSequence(indent, depth int) (*Node, error) {
+	if p.maxDepth >= 0 && depth >= p.maxDepth {
+		return nil, errTooDeep(p.maxDepth)
+	}
+	n := &Node{Kind: Array}
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", line.num)
+		}
+		if line.text != "-" && !strings.HasPrefix(line.text, "- ") {
+			break
+		}
+
+		rest := strings.TrimLeft(strings.TrimPrefix(line.text, "-"), " ")
+		if rest == "" {
+			p.pos++
+			item, err := p.parseNested(indent, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			n.Items = append(n.Items, item)
+			continue
+		}
+		// Parse the rest of the line as if it started a block of its own
+		p.lines[p.pos] = yamlLine{num: line.num, indent: indent + len(line.text) - len(rest), text: rest}
+		item, err := p.parseBlock(p.lines[p.pos].indent, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		n.Items = append(n.Items, item)
+	}
+	return n, nil
+}
+
+func (p *yamlParser) parseMapping(indent, depth int) (*Node, error) {
+	if p.maxDepth >= 0 && depth >= p.maxDepth {
+		return nil, errTooDeep(p.maxDepth)
+	}
+	n := &Node{Kind: Object}
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", line.num)
+		}
+		key, value, ok, err := splitYAMLKey(line.text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line.num, err)
+		}
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected a key", line.num)
+		}
+		if n.Get(key) != nil {
+			return nil, fmt.Errorf("line %d: duplicate key %q", line.num, key)
+		}
+		p.pos++
+
+		var item *Node
+		if value == "" {
+			// A sequence may sit at the same indentation as its key
+			if p.pos < len(p.lines) && p.lines[p.pos].indent == indent && (p.lines[p.pos].text == "-" || strings.HasPrefix(p.lines[p.pos].text, "- ")) {
+				item, err = p.parseSequence(indent, depth+1)
+			} else {
+				item, err = p.parseNested(indent, depth+1)
+			}
+		} else {
+			if strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">") {
+				return nil, fmt.Errorf("line %d: block scalars are not supported; use a quoted string", line.num)
+			}
+			item, err = parseYAMLFlow(value, depth+1, p.maxDepth)
+			if err != nil {
+				err = fmt.Errorf("line %d: %v", line.num, err)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		n.set(key, item)
+	}
+	return n, nil
+}
+
+// parseNested parses the block indented deeper than indent that follows a
+// consumed key or dash, or returns null when there is none.
+func (p *yamlParser) parseNested(indent, depth int) (*Node, error) {
+	if p.pos >= len(p.lines) || p.lines[p.pos].indent <= indent {
+		return &Node{Kind: Null}, nil
+	}
+	return p.parseBlock(p.lines[p.pos].indent, depth)
+}
+
+// splitYAMLKey splits "key: value" (or "key:"). ok is false when the line is
+// not a mapping entry.
+func splitYAMLKey(text string) (key, value string, ok bool, err error) {
+	if text[0] == '"' || text[0] == '\'' {
+		s := &flowScanner{src: text}
+		quoted, err := s.quoted()
+		if err != nil {
+			return "", "", false, err
+		}
+		rest := strings.TrimLeft(text[s.pos:], " ")
+		if !strings.HasPrefix(rest, ":") {
+			return "", "", false, nil
+		}
+		if after := rest[1:]; after != "" && after[0] != ' ' {
+			return "", "", false, nil
+		}
+		return quoted, strings.TrimSpace(rest[1:]), true, nil
+	}
+	if text[0] == '[' || text[0] == '{' {
+		return "", "", false, nil
+	}
+	for i := 0; i < len(text); i++ {
+		if text[i] == ':' && (i+1 == len(text) || text[i+1] == ' ') {
+			return strings.TrimRight(text[:i], " "), strings.TrimSpace(text[i+1:]), true, nil
+		}
+	}
+	return "", "", false, nil
+}
+
+// parseYAMLFlow parses the value on a single line: a flow collection, a quoted
+// string or a plain scalar, which may contain commas and brackets.
+func parseYAMLFlow(text string, depth, maxDepth int) (*Node, error) {
+	if !strings.ContainsRune("[{\"'", rune(text[0])) {
+		return yamlScalar(text), nil
+	}
+	s := &flowScanner{src: text, maxDepth: maxDepth}
+	n, err := s.value(depth)
+	if err != nil {
+		return nil, err
+	}
+	s.skipSpace()
+	if s.pos < len(s.src) {
+		return nil, fmt.Errorf("unexpected %q after value", s.src[s.pos:])
+	}
+	return n, nil
+}
+
+// flowScanner reads YAML flow values: [a, b], {k: v}, quoted and plain scalars.
+type flowScanner struct {
+	src      string
+	pos      int
+	maxDepth int
+}
+
+func (s *flowScanner) skipSpace() {
+	for s.pos < len(s.src) && (s.src[s.pos] == ' ' || s.src[s.pos] == '\t') {
+		s.pos++
+	}
+}
+
+func (s *flowScanner) value(depth int) (*Node, error) {
+	s.skipSpace()
+	if s.pos >= len(s.src) {
+		return &Node{Kind: Null}, nil
+	}
+	switch s.src[s.pos] {
+	case '[', '{':
+		if s.maxDepth >= 0 && depth >= s.maxDepth {
+			return nil, errTooDeep(s.maxDepth)
+		}
+		return s.collection(depth)
+	case '"', '\'':
+		str, err := s.quoted()
+		if err != nil {
+			return nil, err
+		}
+		return &Node{Kind: String, Scalar: str}, nil
+	}
+	start := s.pos
+	for s.pos < len(s.src) && !strings.ContainsRune(",]}", rune(s.src[s.pos])) {
+		if s.src[s.pos] == ':' && (s.pos+1 == len(s.src) || s.src[s.pos+1] == ' ') {
+			break
+		}
+		s.pos++
+	}
+	return yamlScalar(strings.TrimSpace(s.src[start:s.pos])), nil
+}
+
+func (s *flowScanner) collection(depth int) (*Node, error) {
+	open := s.src[s.pos]
+	close := byte(']')
+	n := &Node{Kind: Array}
+	if open == '{' {
+		close, n.Kind = '}', Object
+	}
+	s.pos++
+	for {
+		s.skipSpace()
+		if s.pos >= len(s.src) {
+			return nil, fmt.Errorf("unterminated %c (flow collections must fit on one line)", open)
+		}
+		if s.src[s.pos] == close {
+			s.pos++
+			return n, nil
+		}
+
+		if n.Kind == Array {
+			item, err := s.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			n.Items = append(n.Items, item)
+		} else {
+			key, err := s.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			if key.Kind == Array || key.Kind == Object {
+				return nil, fmt.Errorf("keys must be scalars")
+			}
+			s.skipSpace()
+			if s.pos >= len(s.src) || s.src[s.pos] != ':' {
+				return nil, fmt.Errorf("expected ':' after key %q", key.Scalar)
+			}
+			s.pos++
+			item, err := s.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			n.set(key.Scalar, item)
+		}
+
+		s.skipSpace()
+		if s.pos < len(s.src) && s.src[s.pos] == ',' {
+			s.pos++
+		} else if s.pos >= len(s.src) || s.src[s.pos] != close {
+			return nil, fmt.Errorf("expected ',' or '%c'", close)
+		}
+	}
+}
+
+// quoted reads a single- or double-quoted string.
+func (s *flowScanner) quoted() (string, error) {
+	q := s.src[s.pos]
+	s.pos++
+	var b strings.Builder
+	for s.pos < len(s.src) {
+		c := s.src[s.pos]
+		switch {
+		case c == q && q == '\'' && s.pos+1 < len(s.src) && s.src[s.pos+1] == '\'':
+			b.WriteByte('\'')
+			s.pos += 2
+		case c == q:
+			s.pos++
+			return b.String(), nil
+		case c == '\\' && q == '"':
+			r, n, err := yamlEscape(s.src[s.pos:])
+			if err != nil {
+				return "", err
+			}
+			b.WriteRune(r)
+			s.pos += n
+		default:
+			b.WriteByte(c)
+			s.pos++
+		}
+	}
+	return "", fmt.Errorf("unterminated string")
+}
+
+// yamlEscape decodes the escape sequence at the start of s, returning the
+// character and the length of the sequence.
+func yamlEscape(s string) (rune, int, error) {
+	if len(s) < 2 {
+		return 0, 0, fmt.Errorf("unterminated escape")
+	}
+	simple := map[byte]rune{
+		'0': 0, 'a': '\a', 'b': '\b', 't': '\t', '\t': '\t', 'n': '\n', 'v': '\v',
+		'f': '\f', 'r': '\r', 'e': 0x1b, ' ': ' ', '"': '"', '/': '/', '\\': '\\',
+		'N': 0x85, '_': 0xa0, 'L': 0x2028, 'P': 0x2029,
+	}
+	if r, ok := simple[s[1]]; ok {
+		return r, 2, nil
+	}
+	digits := map[byte]int{'x': 2, 'u': 4, 'U': 8}[s[1]]
+	if digits == 0 || len(s) < 2+digits {
+		return 0, 0, fmt.Errorf("invalid escape %q", s[:2])
+	}
+	v, err := strconv.ParseUint(s[2:2+digits], 16, 32)
+	if err != nil || !utf8.ValidRune(rune(v)) {
+		return 0, 0, fmt.Errorf("invalid escape %q", s[:2+digits])
+	}
+	return rune(v), 2 + digits, nil
+}
+
+// yamlScalar types a plain scalar: null, a boolean, a number or a string.
+func yamlScalar(text string) *Node {
+	switch text {
+	case "", "~", "null", "Null", "NULL":
+		return &Node{Kind: Null}
+	case "true", "True", "TRUE":
+		return &Node{Kind: Bool, Scalar: "true"}
+	case "false", "False", "FALSE":
+		return &Node{Kind: Bool, Scalar: "false"}
+	}
+	if jsonNumber.MatchString(text) {
+		return &Node{Kind: Number, Scalar: text}
+	}
+	return &Node{Kind: String, Scalar: text}
+}
@@ -0,0 +1,139 @@
+// Package uhr provides the Enigma Uhr, a plugboard attachment whose rotating
+// disc replaces ten plugboard cables with non-reciprocal wiring.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package uhr
+
+import "fmt"
+
+const (
+	// Positions is the number of settings of the Uhr's knob.
+	Positions = 40
+	// Pairs is the number of plug pairs the Uhr connects.
+	Pairs = 10
+)
+
+// discWiring connects the 40 contacts on the two faces of the disc. Every
+// contact is wired to one two places further round modulo 4, so the
+// keyboard pins of a-plugs always reach the entry-wheel pins of b-plugs and
+// vice versa, whatever the position.
+var discWiring = [Positions]int{
+	6, 31, 4, 29, 18, 39, 16, 25, 30, 23, 28, 1, 38, 11, 36, 37, 26, 27, 24, 21,
+	14, 3, 12, 17, 2, 7, 0, 33, 10, 35, 8, 5, 22, 19, 20, 13, 34, 15, 32, 9,
+}
+
+// Uhr represents the Uhr attachment. Each of its ten plug pairs has an
+// a-plug and a b-plug, and each plug has a keyboard pin and an entry-wheel
+// pin. Plug a_i has its keyboard pin on contact 4i and its entry-wheel pin on
+// contact 4i+3; the pins of b_i sit where the disc at position 0 connects
+// them to a_i, so position 0 works like ten ordinary cables.
+type Uhr struct {
+	size     int
+	a, b     [Pairs]int // Character index plugged into each a- and b-plug
+	position int
+	forward  []int // Keyboard side to entry-wheel side
+	backward []int // Inverse of forward
+}
+
+// New creates an Uhr over an alphabet of the given size. pairs lists the
+// character indices of the ten plug pairs, a-plug first.
+func New(size int, pairs [][2]int, position int) (*Uhr, error) {
+	if len(pairs) != Pairs {
+		return nil, fmt.Errorf("the Uhr needs exactly %d plug pairs, got %d", Pairs, len(pairs))
+	}
+	u := &Uhr{size: size}
+	used := make(map[int]bool)
+	for i, pair := range pairs {
+		for _, idx := range pair {
+			if idx < 0 || idx >= size {
+				return nil, fmt.Errorf("plug pair %d: character index %d is outside the alphabet", i+1, idx)
+			}
+			if used[idx] {
+				return nil, fmt.Errorf("plug pair %d: character index %d is plugged twice", i+1, idx)
+			}
+			used[idx] = true
+		}
+		u.a[i], u.b[i] = pair[0], pair[1]
+	}
+	if err := u.SetPosition(position); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// Forward maps a character on its way from the keyboard to the rotors.
+// Characters that are not plugged pass unchanged.
+func (u *Uhr) Forward(idx int) int {
+	if idx < 0 || idx >= len(u.forward) {
+		return idx // Invalid input, return as-is
+	}
+	return u.forward[idx]
+}
+
+// Backward maps a character on its way from the rotors to the lamps. It is
+// the inverse of Forward.
+func (u *Uhr) Backward(idx int) int {
+	if idx < 0 || idx >= len(u.backward) {
+		return idx // Invalid input, return as-is
+	}
+	return u.backward[idx]
+}
+
+// Position returns the position of the knob.
+func (u *Uhr) Position() int {
+	return u.position
+}
+
+// SetPosition turns the knob to a position from 0 to 39.
+func (u *Uhr) SetPosition(position int) error {
+	if position < 0 || position >= Positions {
+		return fmt.Errorf("Uhr position %d is outside 0-%d", position, Positions-1)
+	}
+	u.position = position
+
+	// Which character's entry-wheel pin sits on each contact
+	var etw [Positions]int
+	for i := 0; i < Pairs; i++ {
+		etw[4*i+3] = u.a[i]
+		etw[discWiring[4*i]] = u.b[i]
+	}
+
+	u.forward = make([]int, u.size)
+	u.backward = make([]int, u.size)
+	for idx := range u.forward {
+		u.forward[idx] = idx
+		u.backward[idx] = idx
+	}
+	for i := 0; i < Pairs; i++ {
+		u.connect(u.a[i], 4*i, etw)
+		u.connect(u.b[i], inverseWiring(4*i+3), etw)
+	}
+	return nil
+}
+
+// connect wires the character whose keyboard pin sits on contact through
+// the disc at the current position.
+func (u *Uhr) connect(idx, contact int, etw [Positions]int) {
+	out := (discWiring[(contact+u.position)%Positions] - u.position + Positions) % Positions
+	u.forward[idx] = etw[out]
+	u.backward[etw[out]] = idx
+}
+
+// inverseWiring returns the contact the disc wires to contact at position 0.
+func inverseWiring(contact int) int {
+	for c, to := range discWiring {
+		if to == contact {
+			return c
+		}
+	}
+	return -1 // Unreachable: discWiring is a permutation
+}
+
+// Clone creates a copy of the Uhr.
+func (u *Uhr) Clone() *Uhr {
+	clone := *u
+	clone.forward = append([]int(nil), u.forward...)
+	clone.backward = append([]int(nil), u.backward...)
+	return &clone
+}
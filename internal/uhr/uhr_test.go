@@ -0,0 +1,96 @@
+package uhr
+
+import "testing"
+
+// testPairs plugs A-B, C-D, ..., S-T of a 26-character alphabet.
+func testPairs() [][2]int {
+	pairs := make([][2]int, Pairs)
+	for i := range pairs {
+		pairs[i] = [2]int{2 * i, 2*i + 1}
+	}
+	return pairs
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name      string
+		pairs     [][2]int
+		position  int
+		wantError bool
+	}{
+		{"valid", testPairs(), 7, false},
+		{"too few pairs", testPairs()[:9], 0, true},
+		{"plugged twice", append(testPairs()[:9], [2]int{0, 25}), 0, true},
+		{"outside alphabet", append(testPairs()[:9], [2]int{24, 26}), 0, true},
+		{"position too high", testPairs(), 40, true},
+		{"negative position", testPairs(), -1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(26, tt.pairs, tt.position)
+			if (err != nil) != tt.wantError {
+				t.Errorf("New() error = %v, wantError %v", err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestPositionZeroIsPlainCables(t *testing.T) {
+	u, err := New(26, testPairs(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pair := range testPairs() {
+		if u.Forward(pair[0]) != pair[1] || u.Forward(pair[1]) != pair[0] {
+			t.Errorf("pair %v is not swapped: %d, %d", pair, u.Forward(pair[0]), u.Forward(pair[1]))
+		}
+	}
+	for idx := 20; idx < 26; idx++ {
+		if u.Forward(idx) != idx {
+			t.Errorf("unplugged %d maps to %d", idx, u.Forward(idx))
+		}
+	}
+}
+
+func TestPositionsArePermutations(t *testing.T) {
+	u, err := New(26, testPairs(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonReciprocal := 0
+	for pos := 0; pos < Positions; pos++ {
+		if err := u.SetPosition(pos); err != nil {
+			t.Fatal(err)
+		}
+		reciprocal := true
+		seen := make(map[int]bool)
+		for idx := 0; idx < 26; idx++ {
+			out := u.Forward(idx)
+			if seen[out] {
+				t.Fatalf("position %d: %d is reached twice", pos, out)
+			}
+			seen[out] = true
+			if u.Backward(out) != idx {
+				t.Errorf("position %d: Backward(Forward(%d)) = %d", pos, idx, u.Backward(out))
+			}
+			if u.Forward(out) != idx {
+				reciprocal = false
+			}
+		}
+		if !reciprocal {
+			nonReciprocal++
+		}
+	}
+	if nonReciprocal < Positions/2 {
+		t.Errorf("only %d of %d positions are non-reciprocal", nonReciprocal, Positions)
+	}
+
+	clone := u.Clone()
+	want := clone.Forward(0)
+	if err := u.SetPosition(0); err != nil {
+		t.Fatal(err)
+	}
+	if clone.Position() != Positions-1 || clone.Forward(0) != want {
+		t.Errorf("the clone changed with the original")
+	}
+}
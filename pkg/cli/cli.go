@@ -0,0 +1,38 @@
+// Package cli exposes the enigoma command tree for embedding in other Go
+// programs, for example as a subcommand of a larger tool:
+//
+//	root.AddCommand(cli.NewRootCommand(cli.Options{Out: os.Stdout}))
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package cli
+
+import (
+	internal "github.com/coredds/enigoma/internal/cli"
+	"github.com/spf13/cobra"
+)
+
+// Options configures the streams and filesystem of an embedded command tree.
+// Nil fields fall back to the process's standard streams and the OS filesystem.
+type Options = internal.Options
+
+// FS is the filesystem the commands read configurations and inputs from and
+// write outputs to.
+type FS = internal.FS
+
+// OSFS is the FS backed by the host operating system (the default).
+type OSFS = internal.OSFS
+
+// MemFS is an in-memory FS, useful for tests and virtual inputs.
+type MemFS = internal.MemFS
+
+// NewMemFS returns an empty in-memory filesystem.
+func NewMemFS() *MemFS {
+	return internal.NewMemFS()
+}
+
+// NewRootCommand returns a fresh, fully wired enigoma command tree. Trees
+// created by separate calls share no flag or command state.
+func NewRootCommand(opts Options) *cobra.Command {
+	return internal.NewRootCommand(opts)
+}
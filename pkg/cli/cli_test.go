@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestNewRootCommandEmbedded(t *testing.T) {
+	var out bytes.Buffer
+	host := &cobra.Command{Use: "host"}
+	host.AddCommand(NewRootCommand(Options{Out: &out}))
+	host.SetArgs([]string{"enigoma", "preset", "--list"})
+
+	if err := host.Execute(); err != nil {
+		t.Fatalf("embedded command failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "Available Enigma Machine Presets") {
+		t.Errorf("expected preset list on injected output, got: %s", out.String())
+	}
+}
+
+func TestNewRootCommandWithMemFS(t *testing.T) {
+	fsys := NewMemFS()
+	if err := fsys.WriteFile("plain.txt", []byte("HELLOWORLD"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	host := &cobra.Command{Use: "host"}
+	host.AddCommand(NewRootCommand(Options{Out: &bytes.Buffer{}, FS: fsys}))
+	host.SetArgs([]string{"enigoma", "encrypt", "--file", "plain.txt", "--preset", "classic", "--save-config", "key.json", "--output", "cipher.txt"})
+	if err := host.Execute(); err != nil {
+		t.Fatalf("embedded encrypt failed: %v", err)
+	}
+
+	for _, name := range []string{"key.json", "cipher.txt"} {
+		if _, err := fsys.Stat(name); err != nil {
+			t.Errorf("%s should be written to the injected FS: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// Package enigma provides byte-mode encryption of binary data.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import "fmt"
+
+// ByteAlphabet returns the alphabet of byte mode: the 256 runes U+0000 to
+// U+00FF, where each rune stands for the byte of the same value.
+func ByteAlphabet() []rune {
+	runes := make([]rune, 256)
+	for i := range runes {
+		runes[i] = rune(i)
+	}
+	return runes
+}
+
+// NewByteEnigma creates a machine with random settings at the given security
+// level over ByteAlphabet, so EncryptBytes accepts any data: images,
+// archives, or text in any encoding. Its settings save and load like those of
+// any other machine.
+func NewByteEnigma(level SecurityLevel) (*Enigma, error) {
+	return New(WithAlphabet(ByteAlphabet()), WithRandomSettings(level))
+}
+
+// IsByteMode reports whether every character of the alphabet is a byte value,
+// U+0000 to U+00FF, so the machine can encipher bytes. Machines created by
+// NewByteEnigma accept every byte; smaller alphabets, such as A-Z, accept
+// only the bytes they contain.
+func (e *Enigma) IsByteMode() bool {
+	for i := 0; i < e.alphabet.Size(); i++ {
+		if r, _ := e.alphabet.IndexToRune(i); r > 0xff {
+			return false
+		}
+	}
+	return true
+}
+
+// EncryptBytes encrypts data one byte at a time, each byte being the
+// character of the same value. Unlike Encrypt, it applies no normalization,
+// input policies or case folding: every byte must be in the alphabet.
+func (e *Enigma) EncryptBytes(data []byte) ([]byte, error) {
+	return e.processBytes(data, true)
+}
+
+// DecryptBytes decrypts data produced by EncryptBytes.
+func (e *Enigma) DecryptBytes(data []byte) ([]byte, error) {
+	return e.processBytes(data, false)
+}
+
+// processBytes runs data through the machine, one byte per character.
+func (e *Enigma) processBytes(data []byte, encrypt bool) ([]byte, error) {
+	if !e.IsByteMode() {
+		return nil, fmt.Errorf("the alphabet has characters above U+00FF, which cannot be written as bytes; use NewByteEnigma for binary data")
+	}
+	indices := make([]int, len(data))
+	for i, b := range data {
+		idx, err := e.alphabet.RuneToIndex(rune(b))
+		if err != nil {
+			return nil, fmt.Errorf("byte 0x%02x at offset %d is not in the alphabet", b, i)
+		}
+		indices[i] = idx
+	}
+
+	output := make([]byte, len(indices))
+	for i, idx := range e.processIndices(indices, encrypt, nil) {
+		r, _ := e.alphabet.IndexToRune(idx)
+		output[i] = byte(r)
+	}
+	return output, nil
+}
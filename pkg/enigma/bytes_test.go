@@ -0,0 +1,78 @@
+package enigma
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestByteEnigmaRoundTrip(t *testing.T) {
+	machine, err := NewByteEnigma(Medium)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !machine.IsByteMode() || machine.GetAlphabetSize() != 256 {
+		t.Fatalf("byte mode = %v with %d characters, want 256", machine.IsByteMode(), machine.GetAlphabetSize())
+	}
+	clone, err := machine.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every byte value, including NUL and invalid UTF-8
+	data := make([]byte, 0, 1024)
+	for i := 0; i < 1024; i++ {
+		data = append(data, byte(i*7))
+	}
+	ciphertext, err := machine.EncryptBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ciphertext) != len(data) || bytes.Equal(ciphertext, data) {
+		t.Fatal("the ciphertext should have the length of the data and differ from it")
+	}
+	plaintext, err := clone.DecryptBytes(ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(plaintext, data) {
+		t.Error("DecryptBytes should restore the data")
+	}
+}
+
+func TestEncryptBytesMatchesText(t *testing.T) {
+	// On a Latin alphabet, bytes encrypt as the letters they encode
+	machine, err := New(WithAlphabet([]rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")), WithRandomSettings(Low))
+	if err != nil {
+		t.Fatal(err)
+	}
+	clone, err := machine.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := machine.EncryptBytes([]byte("HELLOWORLD"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := clone.Encrypt("HELLOWORLD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("EncryptBytes = %q, want %q", got, want)
+	}
+	if _, err := machine.EncryptBytes([]byte{'A', 0x00}); err == nil || !strings.Contains(err.Error(), "0x00 at offset 1") {
+		t.Errorf("a byte outside the alphabet should be rejected, got %v", err)
+	}
+
+	greek, err := New(WithAlphabet([]rune("\u0391\u0392")), WithRandomSettings(Low))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if greek.IsByteMode() {
+		t.Error("an alphabet above U+00FF is not byte mode")
+	}
+	if _, err := greek.EncryptBytes([]byte("AB")); err == nil {
+		t.Error("EncryptBytes should fail on an alphabet above U+00FF")
+	}
+}
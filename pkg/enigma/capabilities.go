@@ -0,0 +1,138 @@
+// Package enigma provides introspection of the features a machine uses.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import (
+	"fmt"
+
+	"github.com/coredds/enigoma/internal/reflector"
+)
+
+// ReflectorType describes how the reflector of a machine behaves.
+type ReflectorType int
+
+const (
+	// ReflectorFixed is the ordinary reflector of the military machines.
+	ReflectorFixed ReflectorType = iota
+	// ReflectorRewirable can be rewired at runtime, like the UKW-D.
+	ReflectorRewirable
+	// ReflectorSettable can be set to a position but does not turn, as on
+	// the Enigma K.
+	ReflectorSettable
+	// ReflectorRotating turns with the rotors, as on the Enigma G.
+	ReflectorRotating
+	// ReflectorNone is the experimental reflector-less mode.
+	ReflectorNone
+)
+
+// String returns the name of the reflector type.
+func (t ReflectorType) String() string {
+	switch t {
+	case ReflectorFixed:
+		return "fixed"
+	case ReflectorRewirable:
+		return "rewirable"
+	case ReflectorSettable:
+		return "settable"
+	case ReflectorRotating:
+		return "rotating"
+	case ReflectorNone:
+		return "none"
+	default:
+		return fmt.Sprintf("ReflectorType(%d)", int(t))
+	}
+}
+
+// Capabilities lists the features of a machine's configuration, so tools can
+// adapt to it without reading its settings.
+type Capabilities struct {
+	SchemaVersion int               // Settings schema the machine saves with
+	SteppingMode  SteppingMode      // Mechanism that advances the rotors
+	Reflector     ReflectorType     // How the reflector behaves
+	FixedPoints   int               // Characters the reflector leaves in place (see WithReflectorFixedPoints)
+	EntryWheel    bool              // The entry wheel is not the identity
+	Uhr           bool              // The plugboard pairs go through the Uhr
+	NoPlugboard   bool              // The plugboard stage is skipped (see WithoutPlugboard)
+	UnknownChars  UnknownCharPolicy // What happens to characters outside the alphabet
+	StaticRotors  []int             // Rotors, by index from the left, that never step
+	RotorCount    int
+	AlphabetSize  int
+}
+
+// HasStaticRotors reports whether some rotors never step, such as every
+// rotor with SteppingNone.
+func (c Capabilities) HasStaticRotors() bool {
+	return len(c.StaticRotors) > 0
+}
+
+// Capabilities reports the features of the loaded configuration.
+func (e *Enigma) Capabilities() Capabilities {
+	return Capabilities{
+		SchemaVersion: CurrentSchemaVersion,
+		SteppingMode:  e.stepping,
+		Reflector:     e.reflectorType(),
+		FixedPoints:   e.ReflectorFixedPoints(),
+		EntryWheel:    e.entryWheel != nil,
+		Uhr:           e.uhr != nil,
+		NoPlugboard:   e.plugboardDisabled,
+		UnknownChars:  e.unknownChars,
+		StaticRotors:  e.staticRotors(),
+		RotorCount:    len(e.rotors),
+		AlphabetSize:  e.alphabet.Size(),
+	}
+}
+
+// reflectorType classifies the reflector.
+func (e *Enigma) reflectorType() ReflectorType {
+	switch r := e.reflector.(type) {
+	case nil:
+		return ReflectorNone
+	case *reflector.RewirableReflector:
+		return ReflectorRewirable
+	case *reflector.RotatingReflector:
+		if r.Turns() {
+			return ReflectorRotating
+		}
+		return ReflectorSettable
+	default:
+		return ReflectorFixed
+	}
+}
+
+// staticRotors returns the rotors that cannot step from any position. A rotor
+// steps only when the rotor to its right steps onto a notch, so it is static
+// when that rotor has no notches or is static itself. With lever stepping the
+// middle rotor also steps on its own notch.
+func (e *Enigma) staticRotors() []int {
+	n := len(e.rotors)
+	static := make([]bool, n)
+	for i := n - 1; i >= 0; i-- {
+		switch {
+		case e.stepping == SteppingNone:
+			static[i] = true
+		case i == n-1:
+			// The rightmost rotor steps on every character
+		case e.stepping == SteppingLever && i == n-2 && len(e.rotorNotches(i)) > 0:
+			// Double-stepping moves the middle rotor when it sits on its own notch
+		default:
+			static[i] = static[i+1] || len(e.rotorNotches(i+1)) == 0
+		}
+	}
+	var result []int
+	for i, isStatic := range static {
+		if isStatic {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+// rotorNotches returns the notches a rotor was configured with.
+func (e *Enigma) rotorNotches(i int) []rune {
+	if i >= len(e.initialSettings.RotorSpecs) {
+		return nil
+	}
+	return e.initialSettings.RotorSpecs[i].Notches
+}
@@ -0,0 +1,93 @@
+package enigma
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCapabilities(t *testing.T) {
+	tests := []struct {
+		name       string
+		build      func() (*Enigma, error)
+		stepping   SteppingMode
+		reflector  ReflectorType
+		entryWheel bool
+	}{
+		{"m3", NewEnigmaM3, SteppingLever, ReflectorFixed, false},
+		{"g", NewEnigmaG, SteppingGear, ReflectorRotating, true},
+		{"k", NewEnigmaK, SteppingLever, ReflectorSettable, true},
+		{"reflectorless", func() (*Enigma, error) {
+			return New(WithAlphabet([]rune("ABCD")), WithRandomComponents(2, 0), WithoutReflector())
+		}, SteppingLever, ReflectorNone, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			machine, err := tt.build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			caps := machine.Capabilities()
+			if caps.SchemaVersion != CurrentSchemaVersion || caps.SteppingMode != tt.stepping ||
+				caps.Reflector != tt.reflector || caps.EntryWheel != tt.entryWheel {
+				t.Errorf("Capabilities() = %+v", caps)
+			}
+			if caps.RotorCount != machine.GetRotorCount() || caps.AlphabetSize != machine.GetAlphabetSize() {
+				t.Errorf("Capabilities() = %+v, want %d rotors over %d characters", caps, machine.GetRotorCount(), machine.GetAlphabetSize())
+			}
+		})
+	}
+}
+
+func TestCapabilitiesStaticRotors(t *testing.T) {
+	machine, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if caps := machine.Capabilities(); caps.HasStaticRotors() {
+		t.Errorf("M3 has no static rotors, got %v", caps.StaticRotors)
+	}
+
+	// Without notches on the fast rotor, the middle rotor only moves by
+	// double-stepping, which the gear drive does not do
+	settings, err := machine.GetSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings.RotorSpecs[2].Notches = nil
+	if err := machine.LoadSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+	if got := machine.Capabilities().StaticRotors; got != nil {
+		t.Errorf("lever StaticRotors = %v, want none", got)
+	}
+	settings.SteppingMode = SteppingGear
+	if err := machine.LoadSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+	if got := machine.Capabilities().StaticRotors; !reflect.DeepEqual(got, []int{0, 1}) {
+		t.Errorf("gear StaticRotors = %v, want [0 1]", got)
+	}
+
+	settings.SteppingMode = SteppingNone
+	if err := machine.LoadSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+	if got := machine.Capabilities().StaticRotors; !reflect.DeepEqual(got, []int{0, 1, 2}) {
+		t.Errorf("StaticRotors with SteppingNone = %v, want [0 1 2]", got)
+	}
+}
+
+func TestReflectorTypeString(t *testing.T) {
+	for typ, want := range map[ReflectorType]string{
+		ReflectorFixed:     "fixed",
+		ReflectorRewirable: "rewirable",
+		ReflectorSettable:  "settable",
+		ReflectorRotating:  "rotating",
+		ReflectorNone:      "none",
+		ReflectorType(42):  "ReflectorType(42)",
+	} {
+		if got := typ.String(); got != want {
+			t.Errorf("%d.String() = %q, want %q", int(typ), got, want)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+// Package enigma provides case folding onto uppercase alphabets.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WithCaseFolding lets an uppercase alphabet encipher lowercase text. A
+// lowercase letter outside the alphabet whose uppercase form is in it is
+// enciphered as the uppercase letter, and the character it becomes is
+// written in lowercase: the ciphertext carries the case mask of the input,
+// and decryption folds and restores it the same way.
+//
+// The case of a letter enciphered to a character without one (a digit, a
+// space, punctuation) is lost, and the letter decrypts in uppercase; a
+// warning tells when the alphabet has such characters. As with
+// UnknownCharPass, the ciphertext shows which letters were lowercase. The
+// setting is saved with the settings (schema version 2) but is not part of
+// the fingerprint.
+func WithCaseFolding() Option {
+	return func(e *Enigma) error {
+		e.caseFolding = true
+		return nil
+	}
+}
+
+// IsCaseFolding reports whether the machine folds lowercase input onto its
+// alphabet; see WithCaseFolding.
+func (e *Enigma) IsCaseFolding() bool {
+	return e.caseFolding
+}
+
+// SetCaseFolding turns case folding on or off; see WithCaseFolding. It fails,
+// leaving the machine unchanged, when the alphabet has no character that can
+// be written in lowercase.
+func (e *Enigma) SetCaseFolding(enabled bool) error {
+	previous := e.caseFolding
+	e.caseFolding = enabled
+	if err := e.checkCaseFolding(); err != nil {
+		e.caseFolding = previous
+		return err
+	}
+	return nil
+}
+
+// checkCaseFolding rejects case folding for an alphabet with no character
+// that can be written in lowercase, and warns when only some can.
+func (e *Enigma) checkCaseFolding() error {
+	if !e.caseFolding {
+		return nil
+	}
+	caseless := 0
+	for _, s := range e.alphabet.Symbols() {
+		if e.lowerSymbol(s) == s {
+			caseless++
+		}
+	}
+	switch {
+	case caseless == e.alphabet.Size():
+		return fmt.Errorf("case folding needs uppercase letters whose lowercase is not in the alphabet")
+	case caseless > 0:
+		e.warn(WarnCaseLost, "%d alphabet character(s) have no lowercase form; letters enciphered to them lose their case", caseless)
+	}
+	return nil
+}
+
+// foldCase replaces the lowercase letters of text that are only in the
+// alphabet in uppercase. It returns one entry per character of the alphabet
+// in the result, true where it was folded, or nil if none was.
+func (e *Enigma) foldCase(text string) (string, []bool) {
+	var folded strings.Builder
+	lower := make([]bool, 0, len(text))
+	changed := false
+	for _, s := range e.alphabet.Segment(text) {
+		if e.alphabet.ContainsSymbol(s) {
+			folded.WriteString(s)
+			lower = append(lower, false)
+			continue
+		}
+		upper := strings.ToUpper(s)
+		if upper == s || strings.ToLower(upper) != s || !e.alphabet.ContainsSymbol(upper) {
+			folded.WriteString(s)
+			continue
+		}
+		folded.WriteString(upper)
+		lower = append(lower, true)
+		changed = true
+	}
+	if !changed {
+		return text, nil
+	}
+	return folded.String(), lower
+}
+
+// lowerSymbol returns the lowercase form of an alphabet symbol, or the symbol
+// itself when it has none that folds back to it.
+func (e *Enigma) lowerSymbol(s string) string {
+	lowered := strings.ToLower(s)
+	if lowered == s || e.alphabet.ContainsSymbol(lowered) || strings.ToUpper(lowered) != s {
+		return s
+	}
+	return lowered
+}
@@ -0,0 +1,34 @@
+//go:build !tinygo
+
+package enigma
+
+import "testing"
+
+// TestCaseFoldingSettingsVersion checks what case folding means for the
+// schema version and the fingerprint; TestOptionSettingsRoundTrip covers the
+// round trips.
+func TestCaseFoldingSettingsVersion(t *testing.T) {
+	machine, err := New(WithAlphabet([]rune("ABCDEFGH")), WithRandomSettings(Low), WithCaseFolding())
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings, err := machine.GetSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings.SchemaVersion = 1
+	if _, err := settings.MarshalJSON(); err == nil {
+		t.Error("schema version 1 cannot record case folding")
+	}
+	settings.SchemaVersion = 2
+	settings.CaseFolding = false
+	plain, err := NewFromSettings(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, _ := machine.Fingerprint()
+	b, _ := plain.Fingerprint()
+	if a != b {
+		t.Error("case folding should not change the fingerprint")
+	}
+}
@@ -0,0 +1,118 @@
+package enigma
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestCaseFoldingRoundTrip(t *testing.T) {
+	machine, err := New(WithAlphabet([]rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")), WithRandomSettings(Medium),
+		WithCaseFolding(), WithUnknownCharPolicy(UnknownCharPass))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !machine.IsCaseFolding() {
+		t.Fatal("case folding should be on")
+	}
+	clone, err := machine.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const text = "Hello, World! ENIGMA enigma"
+	ciphertext, err := machine.Encrypt(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The ciphertext carries the case mask of the input
+	for i, r := range []rune(ciphertext) {
+		if want := []rune(text)[i]; unicode.IsLower(want) != unicode.IsLower(r) {
+			t.Errorf("character %d: %q should have the case of %q", i, r, want)
+		}
+	}
+	plaintext, err := clone.Decrypt(ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plaintext != text {
+		t.Errorf("Decrypt = %q, want %q", plaintext, text)
+	}
+}
+
+func TestCaseFoldingMatchesUppercase(t *testing.T) {
+	machine, err := New(WithAlphabet([]rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")), WithRandomSettings(Low), WithCaseFolding())
+	if err != nil {
+		t.Fatal(err)
+	}
+	upper, err := machine.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	folded, err := machine.Encrypt("attackAtDawn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := upper.Encrypt("ATTACKATDAWN")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.ToUpper(folded) != plain {
+		t.Errorf("folded ciphertext %q should match %q but for case", folded, plain)
+	}
+}
+
+func TestCaseFoldingAlphabets(t *testing.T) {
+	// Letters enciphered to a digit or space lose their case
+	var warnings []Warning
+	machine, err := New(WithWarningHandler(func(w Warning) { warnings = append(warnings, w) }),
+		WithAlphabet([]rune("ABCDEF0123")), WithRandomSettings(Low), WithCaseFolding())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 1 || warnings[0].Code != WarnCaseLost || !strings.Contains(warnings[0].Message, "4 alphabet") {
+		t.Errorf("warnings = %v, want one %s for the 4 digits", warnings, WarnCaseLost)
+	}
+
+	// An alphabet holding both cases has nothing to fold
+	if _, err := New(WithAlphabet([]rune("ABab")), WithRandomSettings(Low), WithCaseFolding()); err == nil {
+		t.Error("case folding should be rejected when no character can be written in lowercase")
+	}
+
+	if err := machine.SetCaseFolding(false); err != nil || machine.IsCaseFolding() {
+		t.Errorf("SetCaseFolding(false) = %v, folding %v", err, machine.IsCaseFolding())
+	}
+	if _, err := machine.Encrypt("abc"); err == nil {
+		t.Error("lowercase input should be rejected without case folding")
+	}
+	digits, err := New(WithAlphabet([]rune("0123")), WithRandomSettings(Low))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := digits.SetCaseFolding(true); err == nil || digits.IsCaseFolding() {
+		t.Errorf("SetCaseFolding(true) on digits = %v, folding %v", err, digits.IsCaseFolding())
+	}
+}
+
+func TestNewFromTextFoldedCase(t *testing.T) {
+	// Eight letters: no padding, so every character has a lowercase form
+	const text = "EnigmaMachine"
+	machine, err := NewFromText(text, Low, WithFoldedCase())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alphabet := string(machine.alphabet.Runes()); strings.ToUpper(alphabet) != alphabet {
+		t.Errorf("the detected alphabet should be uppercase, got %q", alphabet)
+	}
+	clone, err := machine.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ciphertext, err := machine.Encrypt(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plaintext, err := clone.Decrypt(ciphertext); err != nil || plaintext != text {
+		t.Errorf("Decrypt = %q, %v; want %q", plaintext, err, text)
+	}
+}
@@ -0,0 +1,145 @@
+//go:build !tinygo
+
+// Package enigma provides the .enig container format for ciphertext.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ContainerFormat identifies an enigoma container in its "format" field.
+const ContainerFormat = "enigoma-container"
+
+// ContainerVersion is the current container format version.
+const ContainerVersion = 1
+
+// ContainerExtension is the conventional file extension for containers.
+const ContainerExtension = ".enig"
+
+// plaintextSaltSize is the number of random salt bytes used for plaintext checks.
+const plaintextSaltSize = 16
+
+// Container wraps ciphertext with optional information that helps the
+// recipient decrypt it. It is serialized as JSON.
+type Container struct {
+	Format         string          `json:"format"`
+	Version        int             `json:"version"`
+	Ciphertext     string          `json:"ciphertext"`
+	KeyFingerprint string          `json:"key_fingerprint,omitempty"` // Enigma.Fingerprint of the key, before encryption
+	PlaintextCheck *PlaintextCheck `json:"plaintext_check,omitempty"`
+}
+
+// PlaintextCheck is a salted hash of the plaintext. It lets decryption detect
+// a wrong key or wrong rotor positions without storing the plaintext itself.
+//
+// The check is opt-in because it is also an oracle: anyone holding the
+// container can test guesses of the plaintext offline. The salt defeats
+// precomputed tables, but short or predictable messages (dates, "YES", "NO")
+// can still be recovered by a dictionary attack. Only enable it when the
+// plaintext has enough entropy or confirmation matters more than secrecy.
+type PlaintextCheck struct {
+	Algorithm string `json:"algorithm"`
+	Salt      string `json:"salt"`   // Hex-encoded random salt
+	Digest    string `json:"digest"` // Hex-encoded SHA-256(salt || plaintext)
+}
+
+// NewContainer wraps ciphertext in a container without a plaintext check.
+func NewContainer(ciphertext string) *Container {
+	return &Container{
+		Format:     ContainerFormat,
+		Version:    ContainerVersion,
+		Ciphertext: ciphertext,
+	}
+}
+
+// AddPlaintextCheck stores a freshly salted hash of plaintext in the container.
+func (c *Container) AddPlaintextCheck(plaintext string) error {
+	salt := make([]byte, plaintextSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %v", err)
+	}
+	c.PlaintextCheck = &PlaintextCheck{
+		Algorithm: "sha256",
+		Salt:      hex.EncodeToString(salt),
+		Digest:    hex.EncodeToString(plaintextDigest(salt, plaintext)),
+	}
+	return nil
+}
+
+// HasPlaintextCheck reports whether the container carries a plaintext check.
+func (c *Container) HasPlaintextCheck() bool {
+	return c.PlaintextCheck != nil
+}
+
+// VerifyPlaintext reports whether plaintext matches the stored check.
+// Containers without a check always verify.
+func (c *Container) VerifyPlaintext(plaintext string) (bool, error) {
+	check := c.PlaintextCheck
+	if check == nil {
+		return true, nil
+	}
+	if check.Algorithm != "sha256" {
+		return false, fmt.Errorf("unsupported plaintext check algorithm: %s", check.Algorithm)
+	}
+	salt, err := hex.DecodeString(check.Salt)
+	if err != nil {
+		return false, fmt.Errorf("invalid plaintext check salt: %v", err)
+	}
+	expected, err := hex.DecodeString(check.Digest)
+	if err != nil {
+		return false, fmt.Errorf("invalid plaintext check digest: %v", err)
+	}
+	return subtle.ConstantTimeCompare(plaintextDigest(salt, plaintext), expected) == 1, nil
+}
+
+// Marshal serializes the container as indented JSON.
+func (c *Container) Marshal() (string, error) {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal container: %v", err)
+	}
+	return string(data), nil
+}
+
+// ParseContainer parses a serialized container.
+func ParseContainer(data string) (*Container, error) {
+	var c Container
+	if err := json.Unmarshal([]byte(data), &c); err != nil {
+		return nil, fmt.Errorf("failed to parse container: %v", err)
+	}
+	if c.Format != ContainerFormat {
+		return nil, fmt.Errorf("not an enigoma container (format %q)", c.Format)
+	}
+	if c.Version < 1 || c.Version > ContainerVersion {
+		return nil, fmt.Errorf("unsupported container version: %d", c.Version)
+	}
+	return &c, nil
+}
+
+// IsContainer reports whether data looks like a serialized container.
+func IsContainer(data string) bool {
+	trimmed := strings.TrimSpace(data)
+	if !strings.HasPrefix(trimmed, "{") {
+		return false
+	}
+	var probe struct {
+		Format string `json:"format"`
+	}
+	return json.Unmarshal([]byte(trimmed), &probe) == nil && probe.Format == ContainerFormat
+}
+
+func plaintextDigest(salt []byte, plaintext string) []byte {
+	h := sha256.New()
+	h.Write(salt)
+	h.Write([]byte(plaintext))
+	return h.Sum(nil)
+}
@@ -0,0 +1,96 @@
+//go:build !tinygo
+
+package enigma
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestContainerRoundTrip(t *testing.T) {
+	c := NewContainer("QMJIDO MZWZJFJR")
+	if err := c.AddPlaintextCheck("HELLO WORLD"); err != nil {
+		t.Fatalf("AddPlaintextCheck failed: %v", err)
+	}
+
+	data, err := c.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if strings.Contains(data, "HELLO WORLD") {
+		t.Fatal("container must not contain the plaintext")
+	}
+	if !IsContainer(data) {
+		t.Fatal("IsContainer should recognize a marshaled container")
+	}
+
+	parsed, err := ParseContainer(data)
+	if err != nil {
+		t.Fatalf("ParseContainer failed: %v", err)
+	}
+	if parsed.Ciphertext != "QMJIDO MZWZJFJR" {
+		t.Errorf("ciphertext mismatch: %q", parsed.Ciphertext)
+	}
+
+	tests := []struct {
+		plaintext string
+		expected  bool
+	}{
+		{"HELLO WORLD", true},
+		{"HELLO WORLE", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		ok, err := parsed.VerifyPlaintext(tt.plaintext)
+		if err != nil {
+			t.Fatalf("VerifyPlaintext(%q) error: %v", tt.plaintext, err)
+		}
+		if ok != tt.expected {
+			t.Errorf("VerifyPlaintext(%q) = %v, want %v", tt.plaintext, ok, tt.expected)
+		}
+	}
+}
+
+func TestContainerSaltIsRandom(t *testing.T) {
+	a, b := NewContainer("X"), NewContainer("X")
+	if err := a.AddPlaintextCheck("SAME"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.AddPlaintextCheck("SAME"); err != nil {
+		t.Fatal(err)
+	}
+	if a.PlaintextCheck.Digest == b.PlaintextCheck.Digest {
+		t.Error("identical plaintexts should produce different digests")
+	}
+}
+
+func TestContainerWithoutCheck(t *testing.T) {
+	c := NewContainer("ABC")
+	if c.HasPlaintextCheck() {
+		t.Error("new container should not have a plaintext check")
+	}
+	if ok, err := c.VerifyPlaintext("anything"); err != nil || !ok {
+		t.Errorf("container without check should always verify, got %v, %v", ok, err)
+	}
+}
+
+func TestParseContainerErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"not json", "HELLO"},
+		{"settings file", `{"schema_version": 1}`},
+		{"future version", `{"format": "enigoma-container", "version": 99, "ciphertext": "A"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseContainer(tt.data); err == nil {
+				t.Errorf("expected error for %s", tt.name)
+			}
+		})
+	}
+	if IsContainer(`{"schema_version": 1}`) {
+		t.Error("settings JSON should not be detected as a container")
+	}
+}
@@ -6,51 +6,268 @@ package enigma
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/coredds/enigoma/internal/alphabet"
 )
 
-// QuickEncrypt encrypts text with auto-detected alphabet and specified security level.
-// Returns the encrypted text, the machine configuration as JSON, and any error.
-// This is perfect for one-off encryption where you want maximum convenience.
-func QuickEncrypt(text string, security SecurityLevel) (encrypted string, config string, err error) {
-	machine, err := NewFromText(text, security)
+// AlphabetOrdering selects how auto-detection orders the characters it finds.
+// The order decides each character's index, so tools exchanging keys with
+// enigoma must agree on it.
+type AlphabetOrdering int
+
+const (
+	// Codepoint sorts characters by Unicode codepoint (the default).
+	Codepoint AlphabetOrdering = iota
+	// Frequency puts the most frequent characters first, breaking ties by codepoint.
+	Frequency
+	// AsEncountered keeps the order in which characters first appear.
+	AsEncountered
+)
+
+// String returns the ordering's name, as recorded in metadata.
+func (o AlphabetOrdering) String() string {
+	return o.internal().String()
+}
+
+// ParseAlphabetOrdering converts an ordering name (codepoint, frequency or
+// encountered) to an AlphabetOrdering.
+func ParseAlphabetOrdering(name string) (AlphabetOrdering, error) {
+	o, err := alphabet.ParseOrdering(name)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to create machine: %v", err)
+		return Codepoint, err
+	}
+	switch o {
+	case alphabet.OrderFrequency:
+		return Frequency, nil
+	case alphabet.OrderAsEncountered:
+		return AsEncountered, nil
+	default:
+		return Codepoint, nil
 	}
+}
 
-	// Save configuration BEFORE encryption to preserve initial state
-	config, err = machine.SaveSettingsToJSON()
-	if err != nil {
-		return "", "", fmt.Errorf("failed to save configuration: %v", err)
+// internal converts the ordering to its auto-detection counterpart.
+func (o AlphabetOrdering) internal() alphabet.Ordering {
+	switch o {
+	case Frequency:
+		return alphabet.OrderFrequency
+	case AsEncountered:
+		return alphabet.OrderAsEncountered
+	default:
+		return alphabet.OrderCodepoint
 	}
+}
+
+// PaddingStrategy selects how auto-detection makes an odd-sized alphabet even,
+// as the reflector must pair every character.
+type PaddingStrategy int
+
+const (
+	// PadNextCodepoint adds the first visible character from the space
+	// upwards that the text does not use (the default).
+	PadNextCodepoint PaddingStrategy = iota
+	// PadFromList adds the first unused character from WithPaddingCandidates.
+	PadFromList
+	// PadDropLeastFrequent leaves out the least frequent character. The
+	// machine drops it from its input, with a warning.
+	PadDropLeastFrequent
+	// PadMerge leaves out a character that has a case or whitespace variant in
+	// the alphabet. The machine writes the variant in its place, with a warning.
+	PadMerge
+)
 
-	encrypted, err = machine.Encrypt(text)
+// String returns the strategy's name, as accepted by ParsePaddingStrategy.
+func (p PaddingStrategy) String() string {
+	return p.internal().String()
+}
+
+// ParsePaddingStrategy converts a strategy name (codepoint, list, drop or
+// merge) to a PaddingStrategy.
+func ParsePaddingStrategy(name string) (PaddingStrategy, error) {
+	p, err := alphabet.ParsePaddingStrategy(name)
 	if err != nil {
-		return "", "", fmt.Errorf("encryption failed: %v", err)
+		return PadNextCodepoint, err
+	}
+	switch p {
+	case alphabet.PadFromList:
+		return PadFromList, nil
+	case alphabet.PadDropLeastFrequent:
+		return PadDropLeastFrequent, nil
+	case alphabet.PadMerge:
+		return PadMerge, nil
+	default:
+		return PadNextCodepoint, nil
+	}
+}
+
+// internal converts the strategy to its auto-detection counterpart.
+func (p PaddingStrategy) internal() alphabet.PaddingStrategy {
+	switch p {
+	case PadFromList:
+		return alphabet.PadFromList
+	case PadDropLeastFrequent:
+		return alphabet.PadDropLeastFrequent
+	case PadMerge:
+		return alphabet.PadMerge
+	default:
+		return alphabet.PadNextCodepoint
+	}
+}
+
+// detectConfig holds the options of an auto-detecting constructor.
+type detectConfig struct {
+	ordering      AlphabetOrdering
+	padding       PaddingStrategy
+	padCandidates []rune
+	graphemes     bool
+	normalization Normalization
+	foldCase      bool
+}
+
+// options converts the configuration to auto-detection options.
+func (c *detectConfig) options() []alphabet.AutoDetectOption {
+	opts := []alphabet.AutoDetectOption{
+		alphabet.WithOrdering(c.ordering.internal()),
+		alphabet.WithPaddingStrategy(c.padding.internal()),
+	}
+	if c.padding == PadFromList {
+		opts = append(opts, alphabet.WithPaddingCandidates(c.padCandidates))
+	}
+	if c.graphemes {
+		opts = append(opts, alphabet.WithGraphemeClusters())
+	}
+	if normalizer := c.normalization.normalizer(); normalizer != nil {
+		opts = append(opts, alphabet.WithNormalizer(normalizer))
+	}
+	return opts
+}
+
+// DetectOption configures alphabet auto-detection in NewFromText.
+type DetectOption func(*detectConfig)
+
+// WithAlphabetOrdering sets how the detected characters are ordered. The
+// ordering is recorded in the machine's metadata.
+func WithAlphabetOrdering(ordering AlphabetOrdering) DetectOption {
+	return func(c *detectConfig) {
+		c.ordering = ordering
+	}
+}
+
+// WithPaddingStrategy sets how an odd-sized alphabet is made even.
+func WithPaddingStrategy(strategy PaddingStrategy) DetectOption {
+	return func(c *detectConfig) {
+		c.padding = strategy
+	}
+}
+
+// WithPaddingCandidates selects PadFromList with the given candidates, tried
+// in order.
+func WithPaddingCandidates(candidates []rune) DetectOption {
+	return func(c *detectConfig) {
+		c.padding = PadFromList
+		c.padCandidates = append([]rune(nil), candidates...)
+	}
+}
+
+// WithGraphemeClusters detects an alphabet of grapheme clusters, as
+// WithGraphemeAlphabet sets, so that accented letters, flags and emoji
+// sequences are enciphered whole. Only PadNextCodepoint and PadFromList
+// apply.
+func WithGraphemeClusters() DetectOption {
+	return func(c *detectConfig) {
+		c.graphemes = true
+	}
+}
+
+// WithTextNormalization brings the sample text to a normalization form
+// before the alphabet is detected, and the machine built from it applies the
+// same form to every text it enciphers; see WithNormalization.
+func WithTextNormalization(n Normalization) DetectOption {
+	return func(c *detectConfig) {
+		c.normalization = n
+	}
+}
+
+// WithFoldedCase detects the alphabet from the text in uppercase, and the
+// machine built from it folds lowercase input onto it; see WithCaseFolding.
+func WithFoldedCase() DetectOption {
+	return func(c *detectConfig) {
+		c.foldCase = true
+	}
+}
+
+// sample returns the text auto-detection looks at.
+func (c *detectConfig) sample(text string) string {
+	if c.foldCase {
+		return strings.ToUpper(text)
 	}
+	return text
+}
+
+// withAlphabet sets an alphabet that is already built.
+func withAlphabet(alph *alphabet.Alphabet) Option {
+	return func(e *Enigma) error {
+		return e.setAlphabet(alph)
+	}
+}
 
-	return encrypted, config, nil
+// WithDetectedAlphabet sets the alphabet auto-detected from text, as
+// NewFromText does, for callers assembling their own options. Adjustments
+// made during detection become warnings. The text is normalized to the form
+// set by an earlier WithNormalization unless WithTextNormalization is given.
+func WithDetectedAlphabet(text string, opts ...DetectOption) Option {
+	return func(e *Enigma) error {
+		config := &detectConfig{normalization: e.normalization}
+		for _, opt := range opts {
+			opt(config)
+		}
+		if !config.normalization.valid() {
+			return fmt.Errorf("invalid normalization: %d", config.normalization)
+		}
+		detected, report, err := alphabet.AutoDetectFromTextReport(config.sample(text), config.options()...)
+		if err != nil {
+			return fmt.Errorf("failed to auto-detect alphabet: %v", err)
+		}
+		e.normalization = config.normalization
+		e.caseFolding = e.caseFolding || config.foldCase
+		if err := e.setAlphabet(detected); err != nil {
+			return err
+		}
+		return withAutoDetectWarnings(report)(e)
+	}
 }
 
 // NewFromText creates an Enigma machine by auto-detecting the alphabet from the input text.
 // This is the easiest way to create a machine - just provide your text and desired security level.
-func NewFromText(text string, security SecurityLevel) (*Enigma, error) {
+func NewFromText(text string, security SecurityLevel, opts ...DetectOption) (*Enigma, error) {
 	if text == "" {
 		return nil, fmt.Errorf("text cannot be empty for auto-detection. Provide sample text or use enigma.NewEnigmaClassic() for default setup")
 	}
 
+	config := &detectConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
 	// Auto-detect alphabet from text
-	detectedAlphabet, err := alphabet.AutoDetectFromText(text)
+	detectedAlphabet, report, err := alphabet.AutoDetectFromTextReport(config.sample(text), config.options()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to auto-detect alphabet from text %q: %v. Try using enigma.NewEnigmaSimple(enigoma.AlphabetLatinUpper) for manual setup", text, err)
 	}
 
 	// Create machine with detected alphabet and specified security
-	machine, err := New(
-		WithAlphabet(detectedAlphabet.Runes()),
+	machineOpts := []Option{
+		WithNormalization(config.normalization),
+		withAlphabet(detectedAlphabet),
+		withAutoDetectWarnings(report),
 		WithRandomSettings(security),
-	)
+		WithMetadata(&Metadata{AlphabetOrdering: config.ordering.String()}),
+	}
+	if config.foldCase {
+		machineOpts = append(machineOpts, WithCaseFolding())
+	}
+	machine, err := New(machineOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create machine: %v", err)
 	}
@@ -58,31 +275,47 @@ func NewFromText(text string, security SecurityLevel) (*Enigma, error) {
 	return machine, nil
 }
 
-// EncryptText is the simplest possible encryption function.
-// Auto-detects alphabet, uses medium security, and returns encrypted text with config.
-// Perfect for quick experiments and demos.
-func EncryptText(text string) (encrypted string, config string, err error) {
-	return QuickEncrypt(text, Medium)
-}
-
-// DecryptWithConfig decrypts text using a JSON configuration string.
-// Companion function to QuickEncrypt and EncryptText.
-func DecryptWithConfig(encryptedText string, configJSON string) (decrypted string, err error) {
-	machine, err := NewFromJSON(configJSON)
-	if err != nil {
-		return "", fmt.Errorf("failed to load configuration: %v. Make sure you're using the same config that was used for encryption", err)
-	}
-
-	decrypted, err = machine.Decrypt(encryptedText)
-	if err != nil {
-		return "", fmt.Errorf("decryption failed: %v. Make sure you're using the correct configuration and encrypted text", err)
+// withAutoDetectWarnings turns the adjustments made during alphabet detection
+// into warnings, ahead of any raised while building the components.
+func withAutoDetectWarnings(report alphabet.AutoDetectReport) Option {
+	return func(e *Enigma) error {
+		if report.Normalized {
+			e.warn(WarnInputNormalized, "line endings or surrounding whitespace were normalized before detecting the alphabet")
+		}
+		if report.SkippedControl > 0 {
+			e.warn(WarnControlSkipped, "%d control character(s) were left out of the alphabet", report.SkippedControl)
+		}
+		if report.Truncated {
+			e.warn(WarnAlphabetTruncated, "the alphabet reached its size limit of %d characters", e.alphabet.Size())
+		}
+		if report.Padding != 0 {
+			e.warn(WarnAlphabetPadded, "added %q to the alphabet so the reflector can pair every character", report.Padding)
+		}
+		removed, into := report.Removed, report.MergedInto
+		switch {
+		case removed != 0 && into != 0:
+			e.warn(WarnAlphabetMerged, "left %q out of the alphabet so the reflector can pair every character; it is written as %q", removed, into)
+			e.inputPolicies = append(e.inputPolicies, func(r rune) (rune, error) {
+				if r == removed {
+					return into, nil
+				}
+				return r, nil
+			})
+		case removed != 0:
+			e.warn(WarnAlphabetDropped, "left %q out of the alphabet so the reflector can pair every character; it is dropped from the input", removed)
+			e.inputPolicies = append(e.inputPolicies, func(r rune) (rune, error) {
+				if r == removed {
+					return -1, nil
+				}
+				return r, nil
+			})
+		}
+		return nil
 	}
-
-	return decrypted, nil
 }
 
 // NewWithAutoDetection creates an Enigma machine with auto-detected alphabet and medium security.
 // This is a convenience function for the most common use case.
-func NewWithAutoDetection(text string) (*Enigma, error) {
-	return NewFromText(text, Medium)
+func NewWithAutoDetection(text string, opts ...DetectOption) (*Enigma, error) {
+	return NewFromText(text, Medium, opts...)
 }
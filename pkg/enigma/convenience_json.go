@@ -0,0 +1,54 @@
+//go:build !tinygo
+
+// Package enigma provides convenience functions that exchange configurations as JSON.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import (
+	"fmt"
+	"strings"
+)
+
+// QuickEncrypt encrypts text with auto-detected alphabet and specified security level.
+// Returns the encrypted text, the machine configuration as JSON, and any error.
+// This is perfect for one-off encryption where you want maximum convenience.
+func QuickEncrypt(text string, security SecurityLevel) (encrypted string, config string, err error) {
+	machine, err := NewFromText(text, security)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create machine: %v", err)
+	}
+
+	// Save configuration BEFORE encryption to preserve initial state
+	config, err = machine.SaveSettingsToJSON()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to save configuration: %v", err)
+	}
+
+	encrypted, err = machine.Encrypt(text)
+	if err != nil {
+		return "", "", fmt.Errorf("encryption failed: %v", err)
+	}
+
+	return encrypted, config, nil
+}
+
+// EncryptText is the simplest possible encryption function.
+// Auto-detects alphabet, uses medium security, and returns encrypted text with config.
+// Perfect for quick experiments and demos.
+func EncryptText(text string) (encrypted string, config string, err error) {
+	return QuickEncrypt(text, Medium)
+}
+
+// DecryptWithConfig decrypts text using a JSON configuration string.
+// Companion function to QuickEncrypt and EncryptText. Errors name the
+// character where decryption stopped, the key's fingerprint and the rotor
+// positions; see DecryptStreamWithConfig for input that is read from a stream.
+func DecryptWithConfig(encryptedText string, configJSON string) (decrypted string, err error) {
+	var out strings.Builder
+	if err := DecryptStreamWithConfig(configJSON, strings.NewReader(encryptedText), &out); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,155 @@
+//go:build !tinygo
+
+// Package enigma provides a streaming counterpart to DecryptWithConfig.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// streamChunkSize is the number of characters decrypted per write.
+const streamChunkSize = 4096
+
+// DecryptStreamWithConfig decrypts everything read from r using a JSON
+// configuration and writes the plaintext to w as it goes, so large inputs
+// never have to fit in memory. It is the streaming counterpart of
+// DecryptWithConfig: the output is the same as decrypting the whole input
+// at once.
+//
+// When a character cannot be decrypted, the error names its offset, the
+// key's fingerprint and the rotor positions reached there; plaintext before
+// that character has already been written to w.
+func DecryptStreamWithConfig(configJSON string, r io.Reader, w io.Writer) error {
+	machine, err := NewFromJSON(configJSON)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %v. Make sure you're using the same config that was used for encryption", err)
+	}
+	fingerprint, err := machine.Fingerprint()
+	if err != nil {
+		return fmt.Errorf("failed to fingerprint configuration: %v", err)
+	}
+
+	br := bufio.NewReader(r)
+	if machine.IsGraphemeAlphabet() {
+		return decryptSymbolStream(machine, fingerprint, br, w)
+	}
+	chunk := make([]rune, 0, streamChunkSize)
+	offset := 0
+	flush := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+		plaintext, err := machine.Decrypt(string(chunk))
+		if err != nil {
+			return decryptFailure(machine, fingerprint, offset, err)
+		}
+		if _, err := io.WriteString(w, plaintext); err != nil {
+			return fmt.Errorf("failed to write plaintext: %v", err)
+		}
+		offset += len(chunk)
+		chunk = chunk[:0]
+		return nil
+	}
+
+	for {
+		c, _, err := br.ReadRune()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read ciphertext at character %d: %v", offset+len(chunk), err)
+		}
+		if !machine.alphabet.Contains(c) {
+			// Decrypt up to the bad character so the positions point at it
+			if err := flush(); err != nil {
+				return err
+			}
+			return decryptFailure(machine, fingerprint, offset, fmt.Errorf("character %q is not in the key's alphabet", c))
+		}
+		chunk = append(chunk, c)
+		if len(chunk) == streamChunkSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return flush()
+}
+
+// decryptSymbolStream is DecryptStreamWithConfig for alphabets of grapheme
+// clusters, counting offsets in clusters. The last cluster read may continue
+// in the next chunk, so it waits until more text arrives.
+func decryptSymbolStream(machine *Enigma, fingerprint string, br *bufio.Reader, w io.Writer) error {
+	var pending []rune
+	offset := 0
+	limit := streamChunkSize
+	decrypt := func(final bool) error {
+		clusters := machine.alphabet.Segment(string(pending))
+		if !final && len(clusters) > 0 {
+			clusters = clusters[:len(clusters)-1]
+		}
+		var bad error
+		for i, c := range clusters {
+			if !machine.alphabet.ContainsSymbol(c) {
+				bad = fmt.Errorf("symbol %q is not in the key's alphabet", c)
+				clusters = clusters[:i]
+				break
+			}
+		}
+		consumed := 0
+		if len(clusters) > 0 {
+			plaintext, err := machine.Decrypt(strings.Join(clusters, ""))
+			if err != nil {
+				return decryptFailure(machine, fingerprint, offset, err)
+			}
+			if _, err := io.WriteString(w, plaintext); err != nil {
+				return fmt.Errorf("failed to write plaintext: %v", err)
+			}
+			offset += len(clusters)
+			for _, c := range clusters {
+				consumed += utf8.RuneCountInString(c)
+			}
+		}
+		if bad != nil {
+			return decryptFailure(machine, fingerprint, offset, bad)
+		}
+		pending = append(pending[:0], pending[consumed:]...)
+		limit = len(pending) + streamChunkSize
+		return nil
+	}
+
+	for {
+		c, _, err := br.ReadRune()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read ciphertext after symbol %d: %v", offset, err)
+		}
+		pending = append(pending, c)
+		if len(pending) >= limit {
+			if err := decrypt(false); err != nil {
+				return err
+			}
+		}
+	}
+	return decrypt(true)
+}
+
+// decryptFailure adds the context needed to tell a wrong key from damaged
+// ciphertext: where decryption stopped, which key was used and the rotor
+// positions at that point.
+func decryptFailure(machine *Enigma, fingerprint string, offset int, cause error) error {
+	if len(fingerprint) > 12 {
+		fingerprint = fingerprint[:12]
+	}
+	return fmt.Errorf("decryption failed at character %d (key %s, rotor positions %v): %v. Make sure you're using the correct configuration and encrypted text",
+		offset, fingerprint, machine.GetCurrentRotorPositions(), cause)
+}
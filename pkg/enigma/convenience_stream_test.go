@@ -0,0 +1,81 @@
+//go:build !tinygo
+
+package enigma
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDecryptStreamWithConfig(t *testing.T) {
+	// Longer than one chunk, so rotor state must carry across reads
+	plaintext := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 200)
+	encrypted, config, err := EncryptText(plaintext)
+	if err != nil {
+		t.Fatalf("EncryptText failed: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := DecryptStreamWithConfig(config, strings.NewReader(encrypted), &out); err != nil {
+		t.Fatalf("DecryptStreamWithConfig failed: %v", err)
+	}
+	if out.String() != plaintext {
+		t.Error("streamed decryption differs from the plaintext")
+	}
+
+	decrypted, err := DecryptWithConfig(encrypted, config)
+	if err != nil || decrypted != plaintext {
+		t.Errorf("DecryptWithConfig = %v, want the plaintext", err)
+	}
+}
+
+func TestDecryptWithConfigErrorContext(t *testing.T) {
+	machine, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatalf("NewEnigmaM3 failed: %v", err)
+	}
+	config, err := machine.SaveSettingsToJSON()
+	if err != nil {
+		t.Fatalf("SaveSettingsToJSON failed: %v", err)
+	}
+	fingerprint, _ := machine.Fingerprint()
+
+	var out bytes.Buffer
+	err = DecryptStreamWithConfig(config, strings.NewReader("ABCDE?FGH"), &out)
+	if err == nil {
+		t.Fatal("expected an error for a character outside the alphabet")
+	}
+	for _, want := range []string{"at character 5", "key " + fingerprint[:12], "rotor positions [0 0 5]", `'?'`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should mention %q", err, want)
+		}
+	}
+	if out.Len() != 5 {
+		t.Errorf("the 5 characters before the error should be written, got %q", out.String())
+	}
+
+	if _, err := DecryptWithConfig("HELLO world", config); err == nil || !strings.Contains(err.Error(), "at character 5") {
+		t.Errorf("DecryptWithConfig error should carry the offset, got %v", err)
+	}
+	if _, err := DecryptWithConfig("HELLO", "{not json"); err == nil || !strings.Contains(err.Error(), "failed to load configuration") {
+		t.Errorf("expected a configuration error, got %v", err)
+	}
+}
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestDecryptStreamWithConfigWriteError(t *testing.T) {
+	encrypted, config, err := EncryptText("Hello")
+	if err != nil {
+		t.Fatalf("EncryptText failed: %v", err)
+	}
+	err = DecryptStreamWithConfig(config, strings.NewReader(encrypted), failingWriter{})
+	if err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Errorf("expected the write error, got %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package enigma
+
+import (
+	"go/build"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const modulePath = "github.com/coredds/enigoma"
+
+// tinygoUnfriendly lists standard packages the TinyGo subset must not import
+// directly: they rely on full reflection or an operating system.
+var tinygoUnfriendly = []string{"encoding/json", "encoding/gob", "os", "os/exec", "net", "net/http", "plugin"}
+
+// moduleImports walks the module-internal import graph of pkg under ctx and
+// returns every import path outside the module, mapped to the package using it.
+func moduleImports(t *testing.T, ctx build.Context, pkg string) map[string]string {
+	t.Helper()
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	external := make(map[string]string)
+	seen := make(map[string]bool)
+	var walk func(path string)
+	walk = func(path string) {
+		if seen[path] {
+			return
+		}
+		seen[path] = true
+
+		dir := filepath.Join(root, strings.TrimPrefix(strings.TrimPrefix(path, modulePath), "/"))
+		p, err := ctx.ImportDir(dir, 0)
+		if err != nil {
+			t.Fatalf("import %s: %v", path, err)
+		}
+		for _, imp := range p.Imports {
+			if strings.HasPrefix(imp, modulePath) {
+				walk(imp)
+				continue
+			}
+			external[imp] = path
+		}
+	}
+	walk(pkg)
+	return external
+}
+
+// isStdlib reports whether an import path belongs to the standard library,
+// whose paths never contain a dot in the first element.
+func isStdlib(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(first, ".")
+}
+
+func TestCoreHasOnlyStdlibDependencies(t *testing.T) {
+	for _, pkg := range []string{modulePath, modulePath + "/pkg/enigma"} {
+		for imp, user := range moduleImports(t, build.Default, pkg) {
+			if !isStdlib(imp) {
+				t.Errorf("%s imports third-party package %s; the core must stay stdlib-only", user, imp)
+			}
+		}
+	}
+}
+
+func TestTinyGoSubsetImports(t *testing.T) {
+	ctx := build.Default
+	ctx.BuildTags = append(ctx.BuildTags, "tinygo")
+
+	imports := moduleImports(t, ctx, modulePath+"/pkg/enigma")
+	for _, banned := range tinygoUnfriendly {
+		if user, ok := imports[banned]; ok {
+			t.Errorf("%s imports %s, which is excluded from the tinygo build", user, banned)
+		}
+	}
+}
+
+func TestTinyGoSubsetCompiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping compile check in short mode")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not available")
+	}
+
+	// TinyGo sets the tinygo build tag; emulate it with the regular compiler.
+	cmd := exec.Command(goTool, "vet", "-tags", "tinygo", ".")
+	cmd.Env = append(os.Environ(), "CGO_ENABLED=0")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("tinygo subset does not build: %v\n%s", err, out)
+	}
+}
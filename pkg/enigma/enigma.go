@@ -6,22 +6,52 @@ package enigma
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/coredds/enigoma/internal/alphabet"
+	"github.com/coredds/enigoma/internal/entrywheel"
 	"github.com/coredds/enigoma/internal/plugboard"
+	"github.com/coredds/enigoma/internal/random"
 	"github.com/coredds/enigoma/internal/reflector"
 	"github.com/coredds/enigoma/internal/rotor"
+	"github.com/coredds/enigoma/internal/uhr"
 )
 
 // Enigma represents a configurable Enigma machine.
 type Enigma struct {
-	alphabet        *alphabet.Alphabet
-	rotors          []rotor.Rotor
-	reflector       reflector.Reflector
-	plugboard       *plugboard.Plugboard
-	initialSettings EnigmaSettings // Store initial settings for reset
+	alphabet          *alphabet.Alphabet
+	rotors            []rotor.Rotor
+	reflector         reflector.Reflector
+	plugboard         *plugboard.Plugboard
+	initialSettings   EnigmaSettings         // Store initial settings for reset
+	onStep            StepCallback           // Optional per-character observer
+	onRotorEvent      RotorEventCallback     // Optional stepping/turnover observer
+	stepCounts        []int                  // Number of times each rotor has stepped
+	metadata          *Metadata              // Descriptive information carried with the settings
+	reflectorless     bool                   // Experimental straight-through mode (see WithoutReflector)
+	stepping          SteppingMode           // Mechanism that advances the rotors
+	entryWheel        *entrywheel.EntryWheel // Entry wheel; nil is the identity
+	onWarning         WarningHandler         // Optional receiver for non-fatal conditions
+	warnings          []Warning              // Warnings raised so far
+	inputPolicies     []InputPolicy          // Preprocessing applied before alphabet validation
+	uhr               *uhr.Uhr               // Optional Uhr in place of the plugboard cables
+	plugboardDisabled bool                   // No plugboard stage at all (see WithoutPlugboard)
+	unknownChars      UnknownCharPolicy      // What happens to characters outside the alphabet
+	migratedFrom      int                    // Schema version the settings were upgraded from; 0 if none
+	normalization     Normalization          // Unicode form text and the alphabet are brought to (see WithNormalization)
+	caseFolding       bool                   // Lowercase input is folded onto an uppercase alphabet (see WithCaseFolding)
+	fixedPoints       int                    // Characters the generated reflector leaves in place (see WithReflectorFixedPoints)
+	randomSource      random.Source          // Where random components come from; nil is crypto/rand (see WithRandomSource)
+	indexBuf          []int                  // Character indices, reused from call to call by processText
+	textBuf           []byte                 // Output text, reused from call to call by processText
+	core              signalCore             // Cached inner signal path of processFast
 }
 
+// maxRetainedBuffer is the capacity up to which processText keeps its
+// buffers for the next call. Buffers grown by larger texts are let go rather
+// than pinning their memory to the machine.
+const maxRetainedBuffer = 1 << 16
+
 // New creates a new Enigma machine with the given options.
 func New(opts ...Option) (*Enigma, error) {
 	e := &Enigma{}
@@ -40,7 +70,9 @@ func New(opts ...Option) (*Enigma, error) {
 	if len(e.rotors) == 0 {
 		return nil, fmt.Errorf("at least one rotor must be configured")
 	}
-	if e.reflector == nil {
+	if e.reflectorless {
+		e.reflector = nil
+	} else if e.reflector == nil {
 		return nil, fmt.Errorf("reflector must be set")
 	}
 	if e.plugboard == nil {
@@ -51,6 +83,16 @@ func New(opts ...Option) (*Enigma, error) {
 		}
 		e.plugboard = pb
 	}
+	if err := e.checkPlugboardDisabled(); err != nil {
+		return nil, err
+	}
+	if err := e.checkReflectorFixedPoints(); err != nil {
+		return nil, err
+	}
+	e.checkComponents()
+	if err := e.checkCaseFolding(); err != nil {
+		return nil, err
+	}
 
 	// Store initial settings for reset functionality
 	settings, err := e.GetSettings()
@@ -64,90 +106,243 @@ func New(opts ...Option) (*Enigma, error) {
 
 // Encrypt encrypts the given plaintext using the current machine state.
 func (e *Enigma) Encrypt(plaintext string) (string, error) {
-	return e.processText(plaintext)
+	return e.processText(plaintext, true, nil)
 }
 
 // Decrypt decrypts the given ciphertext using the current machine state.
-// Due to the reciprocal nature of Enigma, this is identical to Encrypt.
+// Due to the reciprocal nature of Enigma, this is identical to Encrypt,
+// except in reflector-less mode where the signal runs the other way.
 func (e *Enigma) Decrypt(ciphertext string) (string, error) {
-	return e.processText(ciphertext)
+	return e.processText(ciphertext, false, nil)
 }
 
-// processText performs the core Enigma encryption/decryption logic.
-func (e *Enigma) processText(text string) (string, error) {
+// processText performs the core Enigma encryption/decryption logic. When
+// traces is not nil, the signal path of every character is appended to it.
+func (e *Enigma) processText(text string, encrypt bool, traces *[]TraceStep) (string, error) {
+	return e.transformText(text, func(indices []int) error {
+		e.processIndices(indices, encrypt, traces)
+		return nil
+	})
+}
+
+// transformText takes text through the steps around the machine itself:
+// normalization, input policies, case folding, characters outside the
+// alphabet and the conversion to and from alphabet indices. process runs
+// the indices through the machine, overwriting them with its output.
+func (e *Enigma) transformText(text string, process func(indices []int) error) (string, error) {
 	if text == "" {
 		return "", nil
 	}
 
-	// Validate input text
-	if invalidRune, err := e.alphabet.ValidateString(text); err != nil {
-		return "", fmt.Errorf("invalid character %c in input text: %v", invalidRune, err)
+	// Bring the text to the alphabet's normalization form
+	if e.normalization != NormalizationNone {
+		text = e.normalization.apply(text)
+	}
+
+	// Let input policies transform or reject characters first
+	if len(e.inputPolicies) > 0 {
+		var err error
+		if text, err = e.applyInputPolicies(text); err != nil {
+			return "", err
+		}
+		if text == "" {
+			return "", nil
+		}
+	}
+
+	// Fold lowercase letters onto the alphabet, remembering where
+	var lower []bool
+	if e.caseFolding {
+		text, lower = e.foldCase(text)
+	}
+
+	// Characters outside the alphabet pass through or drop out by policy
+	var layout []string
+	if e.unknownChars != UnknownCharError {
+		text, layout = e.splitUnknown(text)
+	}
+	if text == "" {
+		return mergeUnknown(nil, layout), nil
 	}
 
-	// Convert text to indices
-	indices, err := e.alphabet.StringToIndices(text)
+	// Validate the text and convert it to indices in one pass
+	indices, invalidRune, err := e.alphabet.AppendIndices(e.indexBuf[:0], text)
 	if err != nil {
-		return "", fmt.Errorf("failed to convert text to indices: %v", err)
+		if e.alphabet.IsGrapheme() {
+			return "", fmt.Errorf("invalid symbol in input text: %v", err)
+		}
+		return "", fmt.Errorf("invalid character %c in input text: %v", invalidRune, err)
+	}
+	if cap(indices) <= maxRetainedBuffer {
+		e.indexBuf = indices
 	}
 
 	// Process each character
-	outputIndices := make([]int, len(indices))
-	for i, inputIdx := range indices {
-		outputIndices[i] = e.processCharacter(inputIdx)
+	if err := process(indices); err != nil {
+		return "", err
+	}
+	outputIndices := indices
+
+	if layout != nil || lower != nil {
+		symbols := make([]string, len(outputIndices))
+		for i, idx := range outputIndices {
+			symbols[i], _ = e.alphabet.IndexToSymbol(idx)
+			if lower != nil && lower[i] {
+				symbols[i] = e.lowerSymbol(symbols[i])
+			}
+		}
+		if layout == nil {
+			return strings.Join(symbols, ""), nil
+		}
+		return mergeUnknown(symbols, layout), nil
 	}
 
 	// Convert back to string
-	result, err := e.alphabet.IndicesToString(outputIndices)
+	result, err := e.alphabet.AppendText(e.textBuf[:0], outputIndices)
 	if err != nil {
 		return "", fmt.Errorf("failed to convert indices to string: %v", err)
 	}
+	if cap(result) <= maxRetainedBuffer {
+		e.textBuf = result
+	}
+	return string(result), nil
+}
 
-	return result, nil
+// processIndices runs each alphabet index through the machine in turn,
+// recording traces and notifying observers. The output indices overwrite
+// the input ones, and the slice is returned.
+func (e *Enigma) processIndices(indices []int, encrypt bool, traces *[]TraceStep) []int {
+	if traces == nil && e.processFast(indices, encrypt) {
+		return indices
+	}
+	outputIndices := indices
+	for i, inputIdx := range indices {
+		if traces == nil {
+			outputIndices[i] = e.processCharacter(i, inputIdx, encrypt, nil)
+		} else {
+			step := &TraceStep{Index: i}
+			outputIndices[i] = e.processCharacter(i, inputIdx, encrypt, step)
+			step.Input, _ = e.alphabet.IndexToRune(inputIdx)
+			step.Output, _ = e.alphabet.IndexToRune(outputIndices[i])
+			step.Positions = e.GetCurrentRotorPositions()
+			*traces = append(*traces, *step)
+		}
+		if e.onStep != nil {
+			e.notifyStep(i, inputIdx, outputIndices[i])
+		}
+	}
+	return outputIndices
 }
 
 // processCharacter processes a single character through the Enigma machine.
-func (e *Enigma) processCharacter(inputIdx int) int {
+// charIndex is the character's position within the current call and is only
+// used to label rotor events. Each stage of the signal path is recorded in
+// trace, which may be nil.
+func (e *Enigma) processCharacter(charIndex, inputIdx int, encrypt bool, trace *TraceStep) int {
 	// Step rotors before processing character (true Enigma behavior)
-	e.stepRotors()
+	e.stepRotors(charIndex)
 
-	// 1. Plugboard forward
-	current := e.plugboard.Process(inputIdx)
+	// 1. Plugboard forward, then the entry wheel
+	current := e.enter(e.plugIn(inputIdx, trace), trace)
+
+	if e.reflectorless {
+		return e.plugOut(e.leave(e.passStraight(current, encrypt, trace), trace), trace)
+	}
 
 	// 2. Rotors forward (right to left)
 	for i := len(e.rotors) - 1; i >= 0; i-- {
+		in := current
 		current = e.rotors[i].Forward(current)
+		trace.record(TraceRotorForward, i, in, current)
 	}
 
 	// 3. Reflector
+	in := current
 	current = e.reflector.Reflect(current)
+	trace.record(TraceReflector, -1, in, current)
 
 	// 4. Rotors backward (left to right)
 	for i := 0; i < len(e.rotors); i++ {
+		in := current
 		current = e.rotors[i].Backward(current)
+		trace.record(TraceRotorBackward, i, in, current)
 	}
 
-	// 5. Plugboard backward
-	current = e.plugboard.Process(current)
+	// 5. Entry wheel and plugboard backward
+	return e.plugOut(e.leave(current, trace), trace)
+}
+
+// enter passes the signal from the plugboard into the entry wheel.
+func (e *Enigma) enter(current int, trace *TraceStep) int {
+	if e.entryWheel == nil {
+		return current
+	}
+	out := e.entryWheel.Enter(current)
+	trace.record(TraceEntryWheel, -1, current, out)
+	return out
+}
 
+// leave passes the signal back out through the entry wheel.
+func (e *Enigma) leave(current int, trace *TraceStep) int {
+	if e.entryWheel == nil {
+		return current
+	}
+	out := e.entryWheel.Leave(current)
+	trace.record(TraceEntryWheelReturn, -1, current, out)
+	return out
+}
+
+// passStraight sends the signal through the rotors once, without a reflector.
+// Encryption runs right to left through the forward wiring; decryption undoes
+// it by running left to right through the backward wiring.
+func (e *Enigma) passStraight(current int, encrypt bool, trace *TraceStep) int {
+	if encrypt {
+		for i := len(e.rotors) - 1; i >= 0; i-- {
+			in := current
+			current = e.rotors[i].Forward(current)
+			trace.record(TraceRotorForward, i, in, current)
+		}
+		return current
+	}
+	for i := 0; i < len(e.rotors); i++ {
+		in := current
+		current = e.rotors[i].Backward(current)
+		trace.record(TraceRotorBackward, i, in, current)
+	}
 	return current
 }
 
-// stepRotors implements the Enigma rotor stepping mechanism including double-stepping.
-func (e *Enigma) stepRotors() {
-	if len(e.rotors) == 0 {
+// notifyStep reports a processed character to the registered step callback.
+func (e *Enigma) notifyStep(i, inputIdx, outputIdx int) {
+	in, _ := e.alphabet.IndexToRune(inputIdx)
+	out, _ := e.alphabet.IndexToRune(outputIdx)
+	e.onStep(StepInfo{
+		Index:     i,
+		Input:     in,
+		Output:    out,
+		Positions: e.GetCurrentRotorPositions(),
+	})
+}
+
+// stepRotors implements the Enigma rotor stepping mechanism, including
+// double-stepping unless the machine is gear-driven. A rotating reflector
+// steps when the leftmost rotor steps onto a notch.
+func (e *Enigma) stepRotors(charIndex int) {
+	if len(e.rotors) == 0 || e.stepping == SteppingNone {
 		return
 	}
 
 	// Check for double-stepping (middle rotor steps twice)
 	// This happens when the middle rotor is at its notch position
 	doubleStep := false
-	if len(e.rotors) >= 2 {
+	if len(e.rotors) >= 2 && e.stepping == SteppingLever {
 		middleRotor := e.rotors[len(e.rotors)-2]
 		doubleStep = middleRotor.IsAtNotch()
 	}
 
 	// Always step the rightmost (fastest) rotor
-	e.rotors[len(e.rotors)-1].Step()
+	e.stepRotor(charIndex, len(e.rotors)-1)
+	leftStepped := len(e.rotors) == 1
 
 	// Step other rotors based on notch positions
 	for i := len(e.rotors) - 2; i >= 0; i-- {
@@ -155,18 +350,37 @@ func (e *Enigma) stepRotors() {
 
 		// Step if the next rotor is at a notch
 		if nextRotor.IsAtNotch() {
-			e.rotors[i].Step()
+			e.emitRotorEvent(RotorTurnover, charIndex, i+1)
+			e.stepRotor(charIndex, i)
 		} else if i == len(e.rotors)-2 && doubleStep {
 			// Double-stepping: middle rotor steps again
-			e.rotors[i].Step()
+			e.stepRotor(charIndex, i)
+			e.emitRotorEvent(RotorDoubleStep, charIndex, i)
 		} else {
 			// No more stepping needed
 			break
 		}
+		leftStepped = i == 0
+	}
+
+	if rr, ok := e.rotatingReflector(); ok && rr.Turns() && leftStepped && e.rotors[0].IsAtNotch() {
+		e.emitRotorEvent(RotorTurnover, charIndex, 0)
+		rr.Step()
 	}
 }
 
+// stepRotor advances a single rotor and records the movement.
+func (e *Enigma) stepRotor(charIndex, i int) {
+	if len(e.stepCounts) != len(e.rotors) {
+		e.stepCounts = make([]int, len(e.rotors))
+	}
+	e.rotors[i].Step()
+	e.stepCounts[i]++
+	e.emitRotorEvent(RotorStepped, charIndex, i)
+}
+
 // Reset resets the rotor positions to their initial configuration.
+// Rotor step counters are cleared as well.
 func (e *Enigma) Reset() error {
 	// Reset rotor positions to initial values
 	for i, rotorSpec := range e.initialSettings.RotorSpecs {
@@ -174,9 +388,26 @@ func (e *Enigma) Reset() error {
 			e.rotors[i].SetPosition(rotorSpec.Position)
 		}
 	}
+	if rr, ok := e.rotatingReflector(); ok {
+		rr.SetPosition(e.initialSettings.ReflectorSpec.Position)
+	}
+	e.ResetStepCounts()
 	return nil
 }
 
+// GetRotorStepCounts returns how many times each rotor has stepped since the
+// machine was created or last reset, ordered like GetCurrentRotorPositions.
+func (e *Enigma) GetRotorStepCounts() []int {
+	counts := make([]int, len(e.rotors))
+	copy(counts, e.stepCounts)
+	return counts
+}
+
+// ResetStepCounts clears the rotor step counters without moving any rotor.
+func (e *Enigma) ResetStepCounts() {
+	e.stepCounts = nil
+}
+
 // GetCurrentRotorPositions returns the current positions of all rotors.
 func (e *Enigma) GetCurrentRotorPositions() []int {
 	positions := make([]int, len(e.rotors))
@@ -199,6 +430,116 @@ func (e *Enigma) SetRotorPositions(positions []int) error {
 	return nil
 }
 
+// GetRotorPositionsAsRunes returns the current positions of all rotors as
+// the alphabet characters shown in the rotor windows, e.g. "ADU" on a Latin
+// machine, ordered like GetCurrentRotorPositions.
+func (e *Enigma) GetRotorPositionsAsRunes() []rune {
+	positions := make([]rune, len(e.rotors))
+	for i, r := range e.rotors {
+		positions[i], _ = e.alphabet.IndexToRune(r.GetPosition())
+	}
+	return positions
+}
+
+// SetRotorPositionsFromRunes sets the positions of all rotors from the
+// characters shown in the rotor windows, one alphabet character per rotor.
+func (e *Enigma) SetRotorPositionsFromRunes(positions []rune) error {
+	indices, err := e.runePositions(positions)
+	if err != nil {
+		return err
+	}
+	return e.SetRotorPositions(indices)
+}
+
+// runePositions converts rotor window characters to zero-based positions.
+func (e *Enigma) runePositions(positions []rune) ([]int, error) {
+	if len(positions) != len(e.rotors) {
+		return nil, fmt.Errorf("position count (%d) must match rotor count (%d)",
+			len(positions), len(e.rotors))
+	}
+	indices := make([]int, len(positions))
+	for i, r := range positions {
+		idx, err := e.alphabet.RuneToIndex(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid position of rotor %d: %v", i+1, err)
+		}
+		indices[i] = idx
+	}
+	return indices, nil
+}
+
+// GetRingSettings returns the zero-based ring settings of all rotors,
+// ordered like GetCurrentRotorPositions.
+func (e *Enigma) GetRingSettings() []int {
+	rings := make([]int, len(e.rotors))
+	for i, r := range e.rotors {
+		rings[i] = r.GetRingSetting()
+	}
+	return rings
+}
+
+// SetRingSettings sets the ring settings (Ringstellung) of all rotors, each a
+// zero-based offset: 0 is the historical ring setting 01 or A. Ring settings
+// are part of the key, so they are saved with the settings.
+func (e *Enigma) SetRingSettings(rings []int) error {
+	if len(rings) != len(e.rotors) {
+		return fmt.Errorf("ring setting count (%d) must match rotor count (%d)",
+			len(rings), len(e.rotors))
+	}
+	for i, ring := range rings {
+		if ring < 0 || ring >= e.alphabet.Size() {
+			return fmt.Errorf("ring setting %d of rotor %d is outside 0-%d", ring, i+1, e.alphabet.Size()-1)
+		}
+	}
+
+	for i, ring := range rings {
+		e.rotors[i].SetRingSetting(ring)
+	}
+	return nil
+}
+
+// IsPlugboardDisabled reports whether the machine has no plugboard stage, as
+// set by WithoutPlugboard.
+func (e *Enigma) IsPlugboardDisabled() bool {
+	return e.plugboardDisabled
+}
+
+// checkPlugboardDisabled rejects plugboard pairs and the Uhr on a machine
+// without a plugboard.
+func (e *Enigma) checkPlugboardDisabled() error {
+	if !e.plugboardDisabled {
+		return nil
+	}
+	if n := e.plugboard.PairCount(); n > 0 {
+		return fmt.Errorf("the plugboard is disabled but %d plugboard pairs are set", n)
+	}
+	if e.uhr != nil {
+		return fmt.Errorf("the plugboard is disabled, so the Uhr cannot be attached")
+	}
+	return nil
+}
+
+// IsReflectorless reports whether the machine runs in the experimental
+// reflector-less mode enabled by WithoutReflector.
+func (e *Enigma) IsReflectorless() bool {
+	return e.reflectorless
+}
+
+// RewireReflector changes the wiring of a rewirable reflector (see
+// WithRewirableReflector) without rebuilding the machine. Rotor positions are
+// kept. The new wiring is part of the settings, so the machine's fingerprint
+// changes with it.
+func (e *Enigma) RewireReflector(pairs map[rune]rune) error {
+	rr, ok := e.reflector.(*reflector.RewirableReflector)
+	if !ok {
+		return fmt.Errorf("the reflector is not rewirable. Build the machine with WithRewirableReflector or a spec marked rewirable")
+	}
+	if err := rr.Rewire(pairs); err != nil {
+		return fmt.Errorf("invalid reflector pairs: %v", err)
+	}
+	return nil
+}
+
 // GetRotorCount returns the number of rotors in the machine.
 func (e *Enigma) GetRotorCount() int {
 	return len(e.rotors)
@@ -214,11 +555,67 @@ func (e *Enigma) GetPlugboardPairCount() int {
 	return e.plugboard.PairCount()
 }
 
+// AddPlugboardPair plugs a cable between r1 and r2 without rebuilding the
+// machine. Both characters must be in the alphabet and not yet plugged.
+// Rotor positions are kept; the pairs are part of the settings, so the
+// fingerprint changes.
+func (e *Enigma) AddPlugboardPair(r1, r2 rune) error {
+	if err := e.checkPlugboardMutable(); err != nil {
+		return err
+	}
+	return e.plugboard.AddPair(r1, r2)
+}
+
+// RemovePlugboardPair unplugs the cable connected to r.
+func (e *Enigma) RemovePlugboardPair(r rune) error {
+	if err := e.checkPlugboardMutable(); err != nil {
+		return err
+	}
+	return e.plugboard.RemovePair(r)
+}
+
+// ClearPlugboard unplugs every cable.
+func (e *Enigma) ClearPlugboard() error {
+	if err := e.checkPlugboardMutable(); err != nil {
+		return err
+	}
+	e.plugboard.Clear()
+	return nil
+}
+
+// checkPlugboardMutable rejects cable changes on a machine without a
+// plugboard, and while the Uhr holds the cables: it needs all ten pairs.
+func (e *Enigma) checkPlugboardMutable() error {
+	if e.plugboardDisabled {
+		return fmt.Errorf("the machine has no plugboard (see WithoutPlugboard)")
+	}
+	if e.uhr != nil {
+		return fmt.Errorf("the plugboard pairs go through the Uhr, which needs exactly ten; rebuild the machine to change them")
+	}
+	return nil
+}
+
 // Clone creates a deep copy of the Enigma machine.
 func (e *Enigma) Clone() (*Enigma, error) {
 	clone := &Enigma{
-		alphabet:        e.alphabet, // Alphabet is immutable, safe to share
-		initialSettings: e.initialSettings,
+		alphabet:          e.alphabet, // Alphabet is immutable, safe to share
+		initialSettings:   e.initialSettings,
+		onStep:            e.onStep,
+		onRotorEvent:      e.onRotorEvent,
+		stepCounts:        e.GetRotorStepCounts(),
+		metadata:          e.GetMetadata(),
+		reflectorless:     e.reflectorless,
+		plugboardDisabled: e.plugboardDisabled,
+		stepping:          e.stepping,
+		entryWheel:        e.entryWheel, // Entry wheel wiring is never modified, safe to share
+		onWarning:         e.onWarning,
+		warnings:          e.Warnings(),
+		inputPolicies:     append([]InputPolicy(nil), e.inputPolicies...),
+		unknownChars:      e.unknownChars,
+		migratedFrom:      e.migratedFrom,
+		normalization:     e.normalization,
+		caseFolding:       e.caseFolding,
+		randomSource:      e.randomSource,
 	}
 
 	// Clone rotors
@@ -228,7 +625,9 @@ func (e *Enigma) Clone() (*Enigma, error) {
 	}
 
 	// Clone reflector
-	clone.reflector = e.reflector.Clone()
+	if e.reflector != nil {
+		clone.reflector = e.reflector.Clone()
+	}
 
 	// Clone plugboard
 	pb, err := e.plugboard.Clone()
@@ -236,6 +635,9 @@ func (e *Enigma) Clone() (*Enigma, error) {
 		return nil, fmt.Errorf("failed to clone plugboard: %v", err)
 	}
 	clone.plugboard = pb
+	if e.uhr != nil {
+		clone.uhr = e.uhr.Clone()
+	}
 
 	return clone, nil
 }
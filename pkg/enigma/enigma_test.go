@@ -540,3 +540,217 @@ func equalSlices(a, b []int) bool {
 	}
 	return true
 }
+
+func TestEnigma_RotorStepCounts(t *testing.T) {
+	machine, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatalf("Failed to create M3: %v", err)
+	}
+
+	if counts := machine.GetRotorStepCounts(); !equalSlices(counts, []int{0, 0, 0}) {
+		t.Fatalf("Fresh machine should have zero step counts, got %v", counts)
+	}
+
+	if _, err := machine.Encrypt(strings.Repeat("A", 26)); err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+
+	counts := machine.GetRotorStepCounts()
+	if counts[2] != 26 {
+		t.Errorf("Fast rotor should step once per character, got %d", counts[2])
+	}
+	if counts[1] != 1 {
+		t.Errorf("Middle rotor should step once per fast-rotor revolution, got %d", counts[1])
+	}
+	if counts[0] != 0 {
+		t.Errorf("Slow rotor should not step, got %d", counts[0])
+	}
+
+	// Returned slice is a copy
+	counts[2] = 999
+	if machine.GetRotorStepCounts()[2] != 26 {
+		t.Errorf("GetRotorStepCounts should return a copy")
+	}
+
+	if err := machine.Reset(); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	if counts := machine.GetRotorStepCounts(); !equalSlices(counts, []int{0, 0, 0}) {
+		t.Errorf("Reset should clear step counts, got %v", counts)
+	}
+}
+
+func TestEnigma_WithoutReflector(t *testing.T) {
+	latin := []rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")
+	machine, err := New(WithAlphabet(latin), WithoutReflector(), WithRandomSettings(High))
+	if err != nil {
+		t.Fatalf("Failed to create reflector-less machine: %v", err)
+	}
+	if !machine.IsReflectorless() {
+		t.Fatal("IsReflectorless should report the experimental mode")
+	}
+
+	// Long enough that some letter almost surely maps to itself
+	message := strings.Repeat("THEQUICKBROWNFOXJUMPSOVERTHELAZYDOG", 20)
+	encrypted, err := machine.Encrypt(message)
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	selfMapped := false
+	for i := range message {
+		if message[i] == encrypted[i] {
+			selfMapped = true
+			break
+		}
+	}
+	if !selfMapped {
+		t.Error("Expected at least one letter to encrypt to itself without a reflector")
+	}
+
+	if err := machine.Reset(); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	decrypted, err := machine.Decrypt(encrypted)
+	if err != nil {
+		t.Fatalf("Decrypt failed: %v", err)
+	}
+	if decrypted != message {
+		t.Errorf("Round trip failed: got %q", decrypted)
+	}
+
+	// The machine is not reciprocal: encrypting twice does not decrypt
+	if err := machine.Reset(); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	again, _ := machine.Encrypt(encrypted)
+	if again == message {
+		t.Error("Encrypt should not undo Encrypt without a reflector")
+	}
+
+	// Clones keep the mode
+	clone, err := machine.Clone()
+	if err != nil {
+		t.Fatalf("Clone failed: %v", err)
+	}
+	if !clone.IsReflectorless() {
+		t.Error("Clone should stay reflector-less")
+	}
+}
+
+func TestEnigma_WithoutReflectorOddAlphabet(t *testing.T) {
+	// Reflectors need an even alphabet; the straight-through mode does not
+	machine, err := New(WithAlphabet([]rune("ABCDE")), WithoutReflector(), WithRandomSettings(Low))
+	if err != nil {
+		t.Fatalf("Odd alphabet should be allowed without a reflector: %v", err)
+	}
+	settings, err := machine.GetSettings()
+	if err != nil {
+		t.Fatalf("GetSettings failed: %v", err)
+	}
+	if !settings.Reflectorless {
+		t.Fatal("Settings should record the reflector-less mode")
+	}
+
+	encrypted, err := machine.Encrypt("ABCDEEDCBA")
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if err := machine.Reset(); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	decrypted, err := machine.Decrypt(encrypted)
+	if err != nil || decrypted != "ABCDEEDCBA" {
+		t.Errorf("Round trip failed: %q, %v", decrypted, err)
+	}
+
+	restored, err := NewFromSettings(settings)
+	if err != nil {
+		t.Fatalf("NewFromSettings failed: %v", err)
+	}
+	if got, _ := restored.Decrypt(encrypted); got != "ABCDEEDCBA" {
+		t.Errorf("Restored machine decrypted to %q", got)
+	}
+}
+
+func TestEnigma_PlugboardMutation(t *testing.T) {
+	machine, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := machine.SetRotorPositionsFromRunes([]rune("ADU")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := machine.AddPlugboardPair('A', 'Z'); err != nil {
+		t.Fatalf("AddPlugboardPair failed: %v", err)
+	}
+	if err := machine.AddPlugboardPair('B', 'Y'); err != nil {
+		t.Fatalf("AddPlugboardPair failed: %v", err)
+	}
+	if got := machine.GetPlugboardPairCount(); got != 2 {
+		t.Errorf("GetPlugboardPairCount() = %d, want 2", got)
+	}
+	if got := string(machine.GetRotorPositionsAsRunes()); got != "ADU" {
+		t.Errorf("rotor positions = %s, want ADU", got)
+	}
+
+	// Same wiring as a machine loaded with the pairs
+	settings, _ := machine.GetSettings()
+	if settings.PlugboardPairs['Z'] != 'A' || settings.PlugboardPairs['B'] != 'Y' {
+		t.Errorf("the settings should list both pairs, got %v", settings.PlugboardPairs)
+	}
+	loaded, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := loaded.LoadSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+	want, _ := loaded.Encrypt("PLUGBOARDMUTATION")
+	got, err := machine.Encrypt("PLUGBOARDMUTATION")
+	if err != nil || got != want {
+		t.Errorf("Encrypt = %q, %v; want %q", got, err, want)
+	}
+
+	if err := machine.RemovePlugboardPair('Y'); err != nil {
+		t.Fatalf("RemovePlugboardPair failed: %v", err)
+	}
+	if got := machine.GetPlugboardPairCount(); got != 1 {
+		t.Errorf("after removing B-Y, GetPlugboardPairCount() = %d, want 1", got)
+	}
+	if err := machine.ClearPlugboard(); err != nil {
+		t.Fatalf("ClearPlugboard failed: %v", err)
+	}
+	if got := machine.GetPlugboardPairCount(); got != 0 {
+		t.Errorf("after ClearPlugboard, GetPlugboardPairCount() = %d, want 0", got)
+	}
+}
+
+func TestEnigma_PlugboardMutationErrors(t *testing.T) {
+	machine, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := machine.AddPlugboardPair('A', 'B'); err != nil {
+		t.Fatal(err)
+	}
+	for name, err := range map[string]error{
+		"already plugged": machine.AddPlugboardPair('B', 'C'),
+		"itself":          machine.AddPlugboardPair('C', 'C'),
+		"not in alphabet": machine.AddPlugboardPair('C', 'é'),
+		"not plugged":     machine.RemovePlugboardPair('Q'),
+	} {
+		if err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	bare := newNoPlugboardMachine(t)
+	if err := bare.AddPlugboardPair('A', 'B'); err == nil {
+		t.Error("a machine without a plugboard should reject pairs")
+	}
+	withUhr := newUhrM3(t, 5)
+	if err := withUhr.ClearPlugboard(); err == nil {
+		t.Error("the Uhr's pairs should not be cleared")
+	}
+}
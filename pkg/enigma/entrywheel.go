@@ -0,0 +1,104 @@
+// Package enigma provides the entry wheel and the position of rotating and
+// settable reflectors.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import (
+	"fmt"
+
+	"github.com/coredds/enigoma/internal/alphabet"
+	"github.com/coredds/enigoma/internal/entrywheel"
+	"github.com/coredds/enigoma/internal/reflector"
+)
+
+// WithEntryWheel sets the wiring of the entry wheel (Eintrittswalze), the
+// fixed wheel between the keyboard and the rotors. wiring lists, for each
+// contact in alphabet order, the character wired to it: the commercial and
+// Abwehr machines used "QWERTZUIOASDFGHJKPYXCVBNML", the keyboard order.
+// Without this option the entry wheel is the identity, as on the military
+// machines. Apply it after WithAlphabet.
+func WithEntryWheel(wiring string) Option {
+	return func(e *Enigma) error {
+		if e.alphabet == nil {
+			return fmt.Errorf("alphabet must be set before configuring the entry wheel")
+		}
+		ew, err := newEntryWheel(wiring, e.alphabet)
+		if err != nil {
+			return err
+		}
+		e.entryWheel = ew
+		return nil
+	}
+}
+
+// newEntryWheel builds an entry wheel from its wiring. An empty or identity
+// wiring returns nil, so it is neither saved nor fingerprinted.
+func newEntryWheel(wiring string, alph *alphabet.Alphabet) (*entrywheel.EntryWheel, error) {
+	if wiring == "" {
+		return nil, nil
+	}
+	ew, err := entrywheel.New(alph, wiring)
+	if err != nil {
+		return nil, err
+	}
+	if ew.IsIdentity() {
+		return nil, nil
+	}
+	return ew, nil
+}
+
+// GetEntryWheel returns the entry wheel wiring in the form WithEntryWheel
+// takes, or "" when the entry wheel is the identity.
+func (e *Enigma) GetEntryWheel() string {
+	if e.entryWheel == nil {
+		return ""
+	}
+	return e.entryWheel.Wiring()
+}
+
+// rotatingReflector returns the reflector if it can be set to a position,
+// whether or not it turns with the rotors.
+func (e *Enigma) rotatingReflector() (*reflector.RotatingReflector, bool) {
+	rr, ok := e.reflector.(*reflector.RotatingReflector)
+	return rr, ok
+}
+
+// HasRotatingReflector reports whether the reflector turns with the rotors,
+// as on the Enigma G.
+func (e *Enigma) HasRotatingReflector() bool {
+	rr, ok := e.rotatingReflector()
+	return ok && rr.Turns()
+}
+
+// HasSettableReflector reports whether the reflector can be set to a
+// position: a rotating reflector, or a stationary one as on the Enigma K.
+func (e *Enigma) HasSettableReflector() bool {
+	_, ok := e.rotatingReflector()
+	return ok
+}
+
+// GetReflectorPosition returns the position of a rotating or settable
+// reflector.
+func (e *Enigma) GetReflectorPosition() (int, error) {
+	rr, ok := e.rotatingReflector()
+	if !ok {
+		return 0, fmt.Errorf("the reflector cannot be set")
+	}
+	return rr.Position(), nil
+}
+
+// SetReflectorPosition sets the position of a rotating or settable
+// reflector. Like the rotor positions, it is part of the message setting.
+func (e *Enigma) SetReflectorPosition(position int) error {
+	rr, ok := e.rotatingReflector()
+	if !ok {
+		return fmt.Errorf("the reflector cannot be set")
+	}
+	if position < 0 || position >= e.alphabet.Size() {
+		return fmt.Errorf("reflector position %d is outside 0-%d", position, e.alphabet.Size()-1)
+	}
+	rr.SetPosition(position)
+	return nil
+}
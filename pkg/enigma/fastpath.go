@@ -0,0 +1,157 @@
+// Package enigma provides the fast signal path used for plain encryption.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import (
+	"github.com/coredds/enigoma/internal/reflector"
+	"github.com/coredds/enigoma/internal/rotor"
+)
+
+// maxFastRotors is the most rotors processFast handles; it keeps the rotors
+// in an array on the stack rather than allocating a slice per call.
+const maxFastRotors = 16
+
+// processFast is processIndices for the common machine: basic rotors, a
+// fixed basic reflector or none, plugboard cables rather than an Uhr, and no
+// traces or observers. Calling the components through their concrete types
+// lets the compiler inline every stage of the signal path, which is most of
+// the cost of encryption. It reports false, having done nothing, when the
+// machine is not one it covers.
+func (e *Enigma) processFast(indices []int, encrypt bool) bool {
+	if e.onStep != nil || e.onRotorEvent != nil || e.uhr != nil || e.plugboardDisabled || len(e.rotors) > maxFastRotors {
+		return false
+	}
+	var refl *reflector.BasicReflector
+	if !e.reflectorless {
+		basic, ok := e.reflector.(*reflector.BasicReflector)
+		if !ok {
+			return false
+		}
+		refl = basic
+	}
+	var stack [maxFastRotors]*rotor.BasicRotor
+	rotors := stack[:len(e.rotors)]
+	for i, r := range e.rotors {
+		basic, ok := r.(*rotor.BasicRotor)
+		if !ok {
+			return false
+		}
+		rotors[i] = basic
+	}
+	if len(e.stepCounts) != len(e.rotors) {
+		e.stepCounts = make([]int, len(e.rotors))
+	}
+
+	// With a reflector, the rotors left of the two rightmost and the
+	// reflector form a core that only changes at their turnovers
+	last := len(rotors) - 1
+	coreLen := max(len(rotors)-2, 0)
+	if refl != nil && !e.core.matches(rotors[:coreLen], refl) {
+		e.core.build(rotors[:coreLen], refl, e.alphabet.Size())
+	}
+
+	plugboard, stepping := e.plugboard, e.stepping != SteppingNone
+	for i, current := range indices {
+		if stepping {
+			if first := e.stepBasic(rotors); refl != nil && first < coreLen {
+				e.core.build(rotors[:coreLen], refl, e.alphabet.Size())
+			}
+		}
+		// Indices come from the alphabet and every stage maps the alphabet
+		// onto itself, so the rotors can skip their range checks
+		current = e.enter(plugboard.Process(current), nil)
+
+		switch {
+		case refl == nil && !encrypt:
+			for _, r := range rotors {
+				current = r.BackwardUnchecked(current)
+			}
+		case refl == nil:
+			for j := last; j >= 0; j-- {
+				current = rotors[j].ForwardUnchecked(current)
+			}
+		default:
+			for j := last; j >= coreLen; j-- {
+				current = rotors[j].ForwardUnchecked(current)
+			}
+			current = e.core.path[current]
+			for j := coreLen; j <= last; j++ {
+				current = rotors[j].BackwardUnchecked(current)
+			}
+		}
+
+		indices[i] = plugboard.Process(e.leave(current, nil))
+	}
+	return true
+}
+
+// stepBasic advances basic rotors exactly as stepRotors does, for machines
+// processFast covers. It returns the index of the leftmost rotor that
+// stepped.
+func (e *Enigma) stepBasic(rotors []*rotor.BasicRotor) int {
+	last := len(rotors) - 1
+	doubleStep := last >= 1 && e.stepping == SteppingLever && rotors[last-1].IsAtNotch()
+
+	rotors[last].Step()
+	e.stepCounts[last]++
+	first := last
+	for i := last - 1; i >= 0; i-- {
+		if !rotors[i+1].IsAtNotch() && !(i == last-1 && doubleStep) {
+			break
+		}
+		rotors[i].Step()
+		e.stepCounts[i]++
+		first = i
+	}
+	return first
+}
+
+// signalCore is the signal path from the left side of the two rightmost
+// rotors through the rotors beyond them, the reflector and back. Those
+// rotors step once per full turn of the middle rotor at most, so processFast
+// looks the path up instead of walking it for every character.
+type signalCore struct {
+	rotors  []*rotor.BasicRotor // Rotors the path was built through, left to right
+	offsets []int               // Their offsets when it was built
+	refl    *reflector.BasicReflector
+	path    []int // Where a signal entering at each contact comes back out
+}
+
+// matches reports whether the path is still that of rotors and refl.
+func (c *signalCore) matches(rotors []*rotor.BasicRotor, refl *reflector.BasicReflector) bool {
+	if c.path == nil || c.refl != refl || len(c.rotors) != len(rotors) {
+		return false
+	}
+	for i, r := range rotors {
+		if c.rotors[i] != r || c.offsets[i] != r.Offset() {
+			return false
+		}
+	}
+	return true
+}
+
+// build computes the path through rotors and refl at their current offsets.
+func (c *signalCore) build(rotors []*rotor.BasicRotor, refl *reflector.BasicReflector, size int) {
+	c.rotors = append(c.rotors[:0], rotors...)
+	c.offsets = c.offsets[:0]
+	for _, r := range rotors {
+		c.offsets = append(c.offsets, r.Offset())
+	}
+	c.refl = refl
+	if len(c.path) != size {
+		c.path = make([]int, size)
+	}
+	for in := range c.path {
+		current := in
+		for j := len(rotors) - 1; j >= 0; j-- {
+			current = rotors[j].Forward(current)
+		}
+		current = refl.Reflect(current)
+		for _, r := range rotors {
+			current = r.Backward(current)
+		}
+		c.path[in] = current
+	}
+}
@@ -0,0 +1,105 @@
+package enigma
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestProcessFastMatchesGeneralPath checks that the fast path produces the
+// same text, rotor positions and step counts as the general one, which
+// processText takes whenever it records traces.
+func TestProcessFastMatchesGeneralPath(t *testing.T) {
+	ascii := make([]rune, 0, 95)
+	for r := rune(' '); r <= '~'; r++ {
+		ascii = append(ascii, r)
+	}
+	machines := map[string]func() (*Enigma, error){
+		"classic": NewEnigmaClassic,
+		"m4":      NewEnigmaM4,
+		"g":       NewEnigmaG,
+		"extreme": func() (*Enigma, error) {
+			return New(WithAlphabet([]rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")), WithRandomSettings(Extreme))
+		},
+		"reflectorless": func() (*Enigma, error) {
+			return New(WithAlphabet(ascii), WithoutReflector(), WithRandomSettings(High))
+		},
+		"rings": func() (*Enigma, error) {
+			return New(WithAlphabet(ascii[1:]), WithRandomSettings(Medium), WithRingSettings([]int{3, 50, 93, 7, 11}))
+		},
+	}
+
+	for name, build := range machines {
+		t.Run(name, func(t *testing.T) {
+			fast, err := build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			general, err := fast.Clone()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			alph := fast.alphabet.Runes()
+			text := make([]rune, 5000)
+			for i := range text {
+				text[i] = alph[(i*7+i/13)%len(alph)]
+			}
+
+			for _, encrypt := range []bool{true, false} {
+				if !encrypt {
+					// Moving the rotors between calls must not reuse the
+					// signal path cached for their old positions
+					if err := fast.Reset(); err != nil {
+						t.Fatal(err)
+					}
+					if err := general.Reset(); err != nil {
+						t.Fatal(err)
+					}
+				}
+				want, err := general.processText(string(text), encrypt, &[]TraceStep{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				got, err := fast.processText(string(text), encrypt, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got != want {
+					t.Fatalf("encrypt=%v: fast path output differs from the general path", encrypt)
+				}
+			}
+			if got, want := fast.GetCurrentRotorPositions(), general.GetCurrentRotorPositions(); !reflect.DeepEqual(got, want) {
+				t.Errorf("positions = %v, want %v", got, want)
+			}
+			if got, want := fast.GetRotorStepCounts(), general.GetRotorStepCounts(); !reflect.DeepEqual(got, want) {
+				t.Errorf("step counts = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestProcessTextReusesBuffers(t *testing.T) {
+	machine, err := NewEnigmaClassic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := machine.Encrypt("HELLOWORLD"); err != nil {
+		t.Fatal(err)
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := machine.Encrypt("HELLOWORLD"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs > 1 {
+		t.Errorf("Encrypt made %.0f allocations, want only the result string", allocs)
+	}
+
+	if _, err := machine.Encrypt(strings.Repeat("A", maxRetainedBuffer+1)); err != nil {
+		t.Fatal(err)
+	}
+	if cap(machine.indexBuf) > maxRetainedBuffer || cap(machine.textBuf) > maxRetainedBuffer {
+		t.Error("buffers grown past maxRetainedBuffer should not be kept")
+	}
+}
@@ -0,0 +1,54 @@
+//go:build !tinygo
+
+// Package enigma provides configuration fingerprints for identifying keys.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// fingerprintSchemaVersion is the settings encoding fingerprints are taken over.
+const fingerprintSchemaVersion = 1
+
+// Fingerprint returns a hex-encoded SHA-256 digest identifying the machine's
+// configuration: alphabet, rotor wiring, ring settings and current positions,
+// reflector and plugboard. Metadata, component provenance and whether the
+// reflector is rewirable are ignored, so annotating a key does not change its
+// fingerprint. So are the unknown character policy, the normalization form
+// (the alphabet is saved normalized), case folding and the schema version:
+// the digest is taken over the schema version 1 encoding, which stays stable
+// as the schema grows.
+//
+// Because rotor positions are included, compute the fingerprint before
+// processing text (or after Reset) to identify a key file.
+func (e *Enigma) Fingerprint() (string, error) {
+	settings, err := e.GetSettings()
+	if err != nil {
+		return "", fmt.Errorf("failed to get settings: %v", err)
+	}
+	settings.Metadata = nil
+	for i := range settings.RotorSpecs {
+		settings.RotorSpecs[i].Provenance = nil
+	}
+	settings.ReflectorSpec.Provenance = nil
+	settings.ReflectorSpec.Rewirable = false
+	settings.UnknownCharPolicy = UnknownCharError
+	settings.Normalization = NormalizationNone
+	settings.CaseFolding = false
+	settings.SchemaVersion = fingerprintSchemaVersion
+
+	// Map keys are sorted by encoding/json, so the encoding is canonical.
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal settings: %v", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
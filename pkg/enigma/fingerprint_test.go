@@ -0,0 +1,108 @@
+//go:build !tinygo
+
+package enigma
+
+import "testing"
+
+func TestFingerprint(t *testing.T) {
+	machine, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatalf("NewEnigmaM3 failed: %v", err)
+	}
+
+	fp, err := machine.Fingerprint()
+	if err != nil {
+		t.Fatalf("Fingerprint failed: %v", err)
+	}
+	if len(fp) != 64 {
+		t.Errorf("expected 64 hex characters, got %d", len(fp))
+	}
+
+	// Stable across serialization round trips
+	data, err := machine.SaveSettingsToJSON()
+	if err != nil {
+		t.Fatalf("SaveSettingsToJSON failed: %v", err)
+	}
+	restored, err := NewFromJSON(data)
+	if err != nil {
+		t.Fatalf("NewFromJSON failed: %v", err)
+	}
+	if got, _ := restored.Fingerprint(); got != fp {
+		t.Errorf("fingerprint changed after round trip: %s != %s", got, fp)
+	}
+
+	// Metadata does not affect the fingerprint
+	restored.SetMetadata(&Metadata{Description: "annotated"})
+	if got, _ := restored.Fingerprint(); got != fp {
+		t.Error("metadata should not change the fingerprint")
+	}
+
+	// Neither does component provenance
+	settings, _ := machine.GetSettings()
+	for i := range settings.RotorSpecs {
+		settings.RotorSpecs[i].Provenance = nil
+	}
+	settings.ReflectorSpec.Provenance = nil
+	bare, err := NewFromSettings(settings)
+	if err != nil {
+		t.Fatalf("NewFromSettings failed: %v", err)
+	}
+	if got, _ := bare.Fingerprint(); got != fp {
+		t.Error("provenance should not change the fingerprint")
+	}
+
+	// Rotor movement does, and Reset restores it
+	if _, err := restored.Encrypt("HELLO"); err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if got, _ := restored.Fingerprint(); got == fp {
+		t.Error("fingerprint should reflect rotor positions")
+	}
+	if err := restored.Reset(); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	if got, _ := restored.Fingerprint(); got != fp {
+		t.Error("fingerprint should match again after Reset")
+	}
+
+	// A different key has a different fingerprint
+	other, err := NewEnigmaClassic()
+	if err != nil {
+		t.Fatalf("NewEnigmaClassic failed: %v", err)
+	}
+	if got, _ := other.Fingerprint(); got == fp {
+		t.Error("different configurations should have different fingerprints")
+	}
+}
+
+func TestFingerprintRewirableReflector(t *testing.T) {
+	machine, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatalf("NewEnigmaM3 failed: %v", err)
+	}
+	fp, _ := machine.Fingerprint()
+
+	// Marking the same wiring rewirable does not change the key
+	settings, _ := machine.GetSettings()
+	settings.ReflectorSpec.Rewirable = true
+	rewirable, err := NewFromSettings(settings)
+	if err != nil {
+		t.Fatalf("NewFromSettings failed: %v", err)
+	}
+	if got, _ := rewirable.Fingerprint(); got != fp {
+		t.Error("the rewirable flag should not change the fingerprint")
+	}
+
+	// Rewiring does
+	pairs := make(map[rune]rune)
+	letters := []rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")
+	for i := 0; i < len(letters); i += 2 {
+		pairs[letters[i]] = letters[i+1]
+	}
+	if err := rewirable.RewireReflector(pairs); err != nil {
+		t.Fatalf("RewireReflector failed: %v", err)
+	}
+	if got, _ := rewirable.Fingerprint(); got == fp {
+		t.Error("rewiring should change the fingerprint")
+	}
+}
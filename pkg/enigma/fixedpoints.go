@@ -0,0 +1,57 @@
+// Package enigma provides reflectors that leave some characters in place.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import "fmt"
+
+// WithReflectorFixedPoints makes the generated reflector leave n randomly
+// chosen characters in place and pair the rest, so with n > 0 a character can
+// encrypt to itself. This removes the classic Enigma weakness that cribs are
+// placed with, while the machine stays reciprocal: Encrypt still decrypts.
+// The alphabet size minus n must be even, so an odd n makes odd-sized
+// alphabets usable with a reflector. Apply it before WithRandomSettings;
+// saved settings record the fixed points and load without it.
+func WithReflectorFixedPoints(n int) Option {
+	return func(e *Enigma) error {
+		if n < 0 {
+			return fmt.Errorf("reflector fixed points cannot be negative, got %d", n)
+		}
+		if e.reflectorless {
+			return fmt.Errorf("reflector fixed points cannot be used with WithoutReflector")
+		}
+		e.fixedPoints = n
+		return nil
+	}
+}
+
+// ReflectorFixedPoints returns the number of characters the reflector leaves
+// in place: 0 for historical reflectors and reflector-less machines.
+func (e *Enigma) ReflectorFixedPoints() int {
+	if e.reflector == nil {
+		return 0
+	}
+	n := 0
+	for i := 0; i < e.alphabet.Size(); i++ {
+		if e.reflector.Reflect(i) == i {
+			n++
+		}
+	}
+	return n
+}
+
+// checkReflectorFixedPoints rejects a WithReflectorFixedPoints that the
+// reflector does not honor, such as one applied after WithRandomSettings.
+func (e *Enigma) checkReflectorFixedPoints() error {
+	if e.fixedPoints == 0 {
+		return nil
+	}
+	if e.reflectorless {
+		return fmt.Errorf("reflector fixed points cannot be used with WithoutReflector")
+	}
+	if n := e.ReflectorFixedPoints(); n != e.fixedPoints {
+		return fmt.Errorf("the reflector leaves %d characters in place, not the %d of WithReflectorFixedPoints; apply it before WithRandomSettings", n, e.fixedPoints)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+//go:build !tinygo
+
+package enigma
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReflectorFixedPointsSettingsTampered(t *testing.T) {
+	machine, err := New(WithAlphabet([]rune("ABCDEFGHI")), WithReflectorFixedPoints(3), WithRandomSettings(Low))
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsonData, err := machine.SaveSettingsToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The mapping alone cannot pass for a historical reflector
+	tampered := strings.Replace(jsonData, `"fixed_points": 3`, `"fixed_points": 0`, 1)
+	if _, err := NewFromJSON(tampered); err == nil {
+		t.Error("a mapping with fixed points should not load without them")
+	}
+}
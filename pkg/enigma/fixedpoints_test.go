@@ -0,0 +1,58 @@
+package enigma
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReflectorFixedPoints(t *testing.T) {
+	machine, err := New(WithAlphabet([]rune("ABCDE")), WithReflectorFixedPoints(1), WithRandomSettings(Low))
+	if err != nil {
+		t.Fatalf("an odd alphabet with one fixed point should build: %v", err)
+	}
+	if n := machine.ReflectorFixedPoints(); n != 1 {
+		t.Errorf("ReflectorFixedPoints() = %d, want 1", n)
+	}
+	if caps := machine.Capabilities(); caps.FixedPoints != 1 || caps.Reflector != ReflectorFixed {
+		t.Errorf("Capabilities() = %+v, want a fixed reflector with 1 fixed point", caps)
+	}
+
+	ciphertext, err := machine.Encrypt("ABCDEEDCBA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := machine.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if plaintext, err := machine.Encrypt(ciphertext); err != nil || plaintext != "ABCDEEDCBA" {
+		t.Errorf("the machine should stay reciprocal: Encrypt(%q) = %q, %v", ciphertext, plaintext, err)
+	}
+
+	plain, err := New(WithAlphabet([]rune("ABCD")), WithRandomSettings(Low))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := plain.ReflectorFixedPoints(); n != 0 {
+		t.Errorf("a generated reflector has no fixed points by default, got %d", n)
+	}
+}
+
+func TestReflectorFixedPointsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr string
+	}{
+		{"negative", []Option{WithAlphabet([]rune("ABCD")), WithReflectorFixedPoints(-1), WithRandomSettings(Low)}, "cannot be negative"},
+		{"unpaired remainder", []Option{WithAlphabet([]rune("ABCD")), WithReflectorFixedPoints(1), WithRandomSettings(Low)}, "cannot be paired"},
+		{"no reflector", []Option{WithAlphabet([]rune("ABCD")), WithoutReflector(), WithReflectorFixedPoints(2), WithRandomSettings(Low)}, "WithoutReflector"},
+		{"after random settings", []Option{WithAlphabet([]rune("ABCD")), WithRandomSettings(Low), WithReflectorFixedPoints(2)}, "apply it before WithRandomSettings"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.opts...); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("New() error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
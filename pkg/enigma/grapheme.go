@@ -0,0 +1,144 @@
+// Package enigma provides grapheme-cluster alphabets, whose symbols can span
+// several runes.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import (
+	"fmt"
+
+	"github.com/coredds/enigoma/internal/alphabet"
+)
+
+// WithGraphemeAlphabet sets an alphabet of grapheme clusters: each symbol is
+// one user-perceived character, such as "é" written with a combining accent,
+// a flag or an emoji with a skin tone, and text is split into clusters
+// rather than runes, so such characters are enciphered whole.
+//
+// The components still see one rune per symbol. A symbol of a single rune is
+// that rune; each symbol of several runes stands in as a private use rune
+// from U+F0000 upwards, in order. Options and methods taking runes (rotor
+// wirings, plugboard pairs, window positions) expect those runes; SymbolRune
+// converts, and the WithSymbol... options take symbols directly.
+func WithGraphemeAlphabet(symbols []string) Option {
+	return func(e *Enigma) error {
+		alph, err := alphabet.NewFromSymbols(symbols)
+		if err != nil {
+			return fmt.Errorf("failed to create alphabet: %v", err)
+		}
+		return e.setAlphabet(alph)
+	}
+}
+
+// IsGraphemeAlphabet reports whether the machine's alphabet is made of
+// grapheme clusters; see WithGraphemeAlphabet.
+func (e *Enigma) IsGraphemeAlphabet() bool {
+	return e.alphabet != nil && e.alphabet.IsGrapheme()
+}
+
+// GetSymbols returns the symbols of the alphabet in order. For an alphabet of
+// runes each symbol is one rune.
+func (e *Enigma) GetSymbols() []string {
+	if e.alphabet == nil {
+		return nil
+	}
+	return e.alphabet.Symbols()
+}
+
+// SymbolRune returns the rune that stands for symbol in the components.
+func (e *Enigma) SymbolRune(symbol string) (rune, error) {
+	if e.alphabet == nil {
+		return 0, fmt.Errorf("alphabet is not initialized")
+	}
+	return e.alphabet.SymbolToRune(symbol)
+}
+
+// RuneSymbol returns the symbol r stands for in the components, for example
+// to show the runes of a trace or rotor event.
+func (e *Enigma) RuneSymbol(r rune) (string, error) {
+	if e.alphabet == nil {
+		return "", fmt.Errorf("alphabet is not initialized")
+	}
+	return e.alphabet.RuneToSymbol(r)
+}
+
+// symbolPairs converts pairs of symbols to the runes standing for them.
+func (e *Enigma) symbolPairs(pairs map[string]string) (map[rune]rune, error) {
+	if e.alphabet == nil {
+		return nil, fmt.Errorf("alphabet must be set before pairing symbols")
+	}
+	runes := make(map[rune]rune, len(pairs))
+	for a, b := range pairs {
+		ra, err := e.alphabet.SymbolToRune(e.normalization.apply(a))
+		if err != nil {
+			return nil, err
+		}
+		rb, err := e.alphabet.SymbolToRune(e.normalization.apply(b))
+		if err != nil {
+			return nil, err
+		}
+		runes[ra] = rb
+	}
+	return runes, nil
+}
+
+// WithSymbolPlugboardPairs works like WithPlugboardConfiguration with pairs
+// of symbols.
+func WithSymbolPlugboardPairs(pairs map[string]string) Option {
+	return func(e *Enigma) error {
+		runes, err := e.symbolPairs(pairs)
+		if err != nil {
+			return fmt.Errorf("failed to set plugboard pairs: %v", err)
+		}
+		return WithPlugboardConfiguration(runes)(e)
+	}
+}
+
+// WithSymbolReflectorPairs works like WithReflectorPairs with pairs of
+// symbols.
+func WithSymbolReflectorPairs(pairs map[string]string) Option {
+	return func(e *Enigma) error {
+		runes, err := e.symbolPairs(pairs)
+		if err != nil {
+			return fmt.Errorf("failed to wire reflector: %v", err)
+		}
+		return WithReflectorPairs(runes)(e)
+	}
+}
+
+// WithRotorPositionSymbols sets the initial rotor positions from the symbols
+// shown in the rotor windows; see SetRotorPositionsFromSymbols.
+func WithRotorPositionSymbols(positions []string) Option {
+	return func(e *Enigma) error {
+		return e.SetRotorPositionsFromSymbols(positions)
+	}
+}
+
+// GetRotorPositionsAsSymbols works like GetRotorPositionsAsRunes but returns
+// the symbols shown in the rotor windows.
+func (e *Enigma) GetRotorPositionsAsSymbols() []string {
+	positions := make([]string, len(e.rotors))
+	for i, r := range e.rotors {
+		positions[i], _ = e.alphabet.IndexToSymbol(r.GetPosition())
+	}
+	return positions
+}
+
+// SetRotorPositionsFromSymbols sets the positions of all rotors from the
+// symbols shown in the rotor windows, one symbol per rotor.
+func (e *Enigma) SetRotorPositionsFromSymbols(positions []string) error {
+	if len(positions) != len(e.rotors) {
+		return fmt.Errorf("position count (%d) must match rotor count (%d)",
+			len(positions), len(e.rotors))
+	}
+	indices := make([]int, len(positions))
+	for i, s := range positions {
+		idx, err := e.alphabet.SymbolToIndex(e.normalization.apply(s))
+		if err != nil {
+			return fmt.Errorf("invalid position of rotor %d: %v", i+1, err)
+		}
+		indices[i] = idx
+	}
+	return e.SetRotorPositions(indices)
+}
@@ -0,0 +1,92 @@
+//go:build !tinygo
+
+package enigma
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestGraphemeSettingsMismatch(t *testing.T) {
+	machine := newGraphemeMachine(t)
+	settings, err := machine.GetSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings.Symbols[0], settings.Symbols[1] = settings.Symbols[1], settings.Symbols[0]
+	if _, err := NewFromSettings(settings); err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("symbols that do not match the alphabet should be rejected, got %v", err)
+	}
+}
+
+func TestGraphemeFingerprint(t *testing.T) {
+	// Both alphabets stand in for their second symbol with U+F0000
+	a, err := New(WithGraphemeAlphabet([]string{"A", "e\u0301"}), WithoutPlugboard(), WithRandomSettings(Low))
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings, err := a.GetSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings.Symbols[1] = "n\u0303"
+	b, err := NewFromSettings(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fa, err := a.Fingerprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fb, err := b.Fingerprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fa == fb {
+		t.Error("machines with different symbols should have different fingerprints")
+	}
+}
+
+func TestNewFromTextGraphemeClusters(t *testing.T) {
+	const text = "Cafe\u0301 👍\U0001f3fd 🇫🇷"
+	machine, err := NewFromText(text, Low, WithGraphemeClusters())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !machine.IsGraphemeAlphabet() {
+		t.Fatal("the detected alphabet should be of grapheme clusters")
+	}
+	config, err := machine.SaveSettingsToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ciphertext, err := machine.Encrypt(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Decrypt in chunks that split clusters between reads
+	var out bytes.Buffer
+	if err := DecryptStreamWithConfig(config, &oneByteReader{data: []byte(ciphertext)}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != text {
+		t.Errorf("streamed plaintext = %q, want %q", out.String(), text)
+	}
+}
+
+// oneByteReader returns its data one byte per Read.
+type oneByteReader struct {
+	data []byte
+}
+
+func (r *oneByteReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, io.EOF
+	}
+	p[0] = r.data[0]
+	r.data = r.data[1:]
+	return 1, nil
+}
@@ -0,0 +1,139 @@
+package enigma
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// graphemeSymbols is an alphabet of letters, accented letters written with
+// combining marks, flags and emoji with skin tones.
+var graphemeSymbols = []string{
+	"A", "B", "C", "D", "E", "F", " ",
+	"e\u0301", "n\u0303", "🇫🇷", "🇩🇪", "👍\U0001f3fd", "👨\u200d👩\u200d👧", "❤\ufe0f",
+}
+
+func newGraphemeMachine(t *testing.T, opts ...Option) *Enigma {
+	t.Helper()
+	machine, err := New(append([]Option{WithGraphemeAlphabet(graphemeSymbols), WithRandomSettings(Medium)}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return machine
+}
+
+func TestGraphemeAlphabetRoundTrip(t *testing.T) {
+	machine := newGraphemeMachine(t)
+	if !machine.IsGraphemeAlphabet() {
+		t.Fatal("the machine should have a grapheme alphabet")
+	}
+	if got := machine.GetSymbols(); !slices.Equal(got, graphemeSymbols) {
+		t.Errorf("symbols = %q, want %q", got, graphemeSymbols)
+	}
+
+	const text = "CAFe\u0301 🇫🇷 👍\U0001f3fd👨\u200d👩\u200d👧 ❤\ufe0f"
+	clone, err := machine.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ciphertext, err := machine.Encrypt(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(machine.alphabet.Segment(ciphertext)), len(machine.alphabet.Segment(text)); got != want {
+		t.Errorf("ciphertext has %d symbols, want %d", got, want)
+	}
+	plaintext, err := clone.Decrypt(ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plaintext != text {
+		t.Errorf("Decrypt = %q, want %q", plaintext, text)
+	}
+
+	// A bare "e" or thumb is not a symbol of the alphabet
+	for _, bad := range []string{"CAFe", "👍"} {
+		if _, err := machine.Encrypt(bad); err == nil || !strings.Contains(err.Error(), "invalid symbol") {
+			t.Errorf("Encrypt(%q) error = %v, want an invalid symbol", bad, err)
+		}
+	}
+}
+
+func TestGraphemeUnknownCharPolicy(t *testing.T) {
+	machine := newGraphemeMachine(t, WithUnknownCharPolicy(UnknownCharPass))
+	clone, err := machine.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	const text = "A🇺🇸B e\u0302!"
+	ciphertext, err := machine.Encrypt(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	symbols := machine.alphabet.Segment(ciphertext)
+	if len(symbols) != 6 || symbols[1] != "🇺🇸" || symbols[4] != "e\u0302" || symbols[5] != "!" {
+		t.Errorf("unknown clusters should pass through whole, got %q", symbols)
+	}
+	plaintext, err := clone.Decrypt(ciphertext)
+	if err != nil || plaintext != text {
+		t.Errorf("Decrypt = %q, %v; want %q", plaintext, err, text)
+	}
+}
+
+func TestGraphemeSymbolOptions(t *testing.T) {
+	machine, err := New(WithGraphemeAlphabet(graphemeSymbols), WithRandomSettings(Low),
+		WithSymbolPlugboardPairs(map[string]string{"🇫🇷": "🇩🇪", "🇩🇪": "🇫🇷", "e\u0301": "A", "A": "e\u0301"}),
+		WithRotorPositionSymbols([]string{"👍\U0001f3fd", "A", "❤\ufe0f"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := machine.GetRotorPositionsAsSymbols(), []string{"👍\U0001f3fd", "A", "❤\ufe0f"}; !slices.Equal(got, want) {
+		t.Errorf("positions = %q, want %q", got, want)
+	}
+
+	pairs, err := machine.plugboard.GetPairsMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fr, _ := machine.SymbolRune("🇫🇷")
+	de, _ := machine.SymbolRune("🇩🇪")
+	if pairs[fr] != de {
+		t.Errorf("the flags should be plugged together, got %v", pairs)
+	}
+	if symbol, err := machine.RuneSymbol(fr); err != nil || symbol != "🇫🇷" {
+		t.Errorf("RuneSymbol(%U) = %q, %v", fr, symbol, err)
+	}
+
+	if _, err := New(WithGraphemeAlphabet(graphemeSymbols), WithRandomSettings(Low),
+		WithSymbolPlugboardPairs(map[string]string{"🇺🇸": "A"})); err == nil {
+		t.Error("pairing a symbol outside the alphabet should fail")
+	}
+	if _, err := New(WithGraphemeAlphabet([]string{"e", "\u0301"})); err == nil {
+		t.Error("symbols that merge when written together should be rejected")
+	}
+}
+
+func TestGraphemeMessage(t *testing.T) {
+	symbols := []string{"A", "B", "C", "D", "🇫🇷", "🇩🇪", "👍\U0001f3fd", "e\u0301"}
+	machine, err := New(WithGraphemeAlphabet(symbols), WithRandomSettings(Low))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const text = "ABe\u0301🇫🇷👍\U0001f3fdDCBA🇩🇪"
+	msg, err := machine.EncryptMessageWithKeys(text, "🇫🇷AB", "e\u0301👍\U0001f3fdC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msg.Groups) != 2 || len(machine.alphabet.Segment(msg.Groups[0])) != MessageGroupSize {
+		t.Errorf("groups should hold %d symbols each, got %q", MessageGroupSize, msg.Groups)
+	}
+	parsed, err := ParseMessage(msg.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext, err := machine.DecryptMessage(parsed)
+	if err != nil || plaintext != text {
+		t.Errorf("DecryptMessage = %q, %v; want %q", plaintext, err, text)
+	}
+}
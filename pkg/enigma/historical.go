@@ -5,6 +5,7 @@
 package enigma
 
 import (
+	"github.com/coredds/enigoma/internal/provenance"
 	"github.com/coredds/enigoma/internal/reflector"
 	"github.com/coredds/enigoma/internal/rotor"
 )
@@ -24,8 +25,53 @@ const (
 	// M4 Naval Enigma additional thin rotors (used with thin reflectors)
 	RotorBeta  = "LEYJVCNIXWPBQMDRTAKZGFUHOS"
 	RotorGamma = "FSOKANUERHMBTIYCWLQPZXVGJD"
+
+	// Abwehr Enigma G-312 rotors
+	RotorGI   = "DMTWSILRUYQNKFEJCAZBPGXOHV"
+	RotorGII  = "HQZGPJTMOBLNCIFDYAWVEUSRKX"
+	RotorGIII = "UQNTLSZFMREHDPXKIBVYGJCWOA"
+
+	// Commercial Enigma K rotors (shared with the Enigma D)
+	RotorKI   = "LPGSZMHAEOQKVXRFYBUTNICJDW"
+	RotorKII  = "SLVGBTFXJQOHEWIRZYAMKPCNDU"
+	RotorKIII = "CJGDPSHKTURAWZXFMYNQOBVLIE"
+
+	// Swiss Army Enigma K rotors, rewired from the commercial ones in 1939
+	RotorSwissKI   = "PEZUOHXSCVFMTBGLRINQJWAYDK"
+	RotorSwissKII  = "ZOUESYDKFWPCIQXHMVBLGNJRAT"
+	RotorSwissKIII = "EHRVXGAOBQUSIMZFLYNWKTPDJC"
+
+	// German Railway (Rocket) Enigma rotors
+	RotorRailwayI   = "JGDQOXUSCAMIFRVTPNEWKBLZYH"
+	RotorRailwayII  = "NTZPSFBOKMWRCJDIVLAEYUXHGQ"
+	RotorRailwayIII = "JVIUBHTCDYAKEQZPOSGXNRMWFL"
+
+	// Norway Enigma rotors, rewired Enigma I rotors used in Norway after 1945
+	RotorNorwayI   = "WTOKASUYVRBXJHQCPZEFMDINLG"
+	RotorNorwayII  = "GJLPUBSWEMCTQVHXAOFZDRKYNI"
+	RotorNorwayIII = "JWFMHNBPUSDYTIXVZGRQLAOEKC"
+	RotorNorwayIV  = "ESOVPZJAYQUIRHXLNFTGKDCMWB"
+	RotorNorwayV   = "HEJXQOTZBVFDASCILWPGYNMURK"
+
+	// Enigma T (Tirpitz) rotors, built for the Japanese navy
+	RotorTirpitzI    = "KPTYUELOCVGRFQDANJMBSWHZXI"
+	RotorTirpitzII   = "UPHZLWEQMTDJXCAKSOIGVBYFNR"
+	RotorTirpitzIII  = "QUDLYRFEKONVZAXWHMGPJBSICT"
+	RotorTirpitzIV   = "CIWTBKXNRESPFLYDAGVHQUOJZM"
+	RotorTirpitzV    = "UAXGISNJBVERDYLFZWTPCKOHMQ"
+	RotorTirpitzVI   = "XFUZGALVHCNYSEWQTDMRBKPIOJ"
+	RotorTirpitzVII  = "BJVFTXPLNAYOZIKWGDQERUCHSM"
+	RotorTirpitzVIII = "YMTPNZHWKODAJXELUQVGCBISFR"
 )
 
+// EntryWheelQWERTZ is the keyboard-order entry wheel of the commercial and
+// Abwehr machines: key Q is wired to contact A, W to B, and so on.
+const EntryWheelQWERTZ = "QWERTZUIOASDFGHJKPYXCVBNML"
+
+// EntryWheelTirpitz is the entry wheel of the Enigma T, which follows neither
+// the alphabet nor the keyboard.
+const EntryWheelTirpitz = "KZROUQHYAIGBLWVSTDXFPNMCJE"
+
 // Historical reflector wirings
 const (
 	// Standard reflectors
@@ -36,6 +82,19 @@ const (
 	// M4 Naval Enigma thin reflectors (used with thin rotors)
 	ReflectorBThin = "ENKQAUYWJICOPBLMDXZVFTHRGS"
 	ReflectorCThin = "RDOBJNTKVEHMLFCWZAXGYIPSUQ"
+
+	// Abwehr Enigma G-312 rotating reflector
+	ReflectorG = "RULQMZJSYGOCETKWDAHNBXPVIF"
+
+	// Settable reflectors of the Enigma K (also the Swiss K) and the Railway Enigma
+	ReflectorK       = "IMETCGFRAYSQBZXWLHKDVUPOJN"
+	ReflectorRailway = "QYHOGNECVPUZTFDJAXWMKISRBL"
+
+	// Norway Enigma reflector
+	ReflectorNorway = "MOWJYPUXNDSRAIBFVLKZGQCHET"
+
+	// Enigma T (Tirpitz) settable reflector
+	ReflectorTirpitz = "GEKPBTAUMOCNILJDXZYFHWVQSR"
 )
 
 // Historical notch positions (when stepping occurs)
@@ -48,6 +107,32 @@ var (
 	NotchVI   = []rune{'Z', 'M'} // Notches at positions Z and M
 	NotchVII  = []rune{'Z', 'M'} // Notches at positions Z and M
 	NotchVIII = []rune{'Z', 'M'} // Notches at positions Z and M
+
+	// Enigma G-312 rotors have many notches, giving irregular stepping
+	NotchGI   = []rune("SUVWZABCEFGIKLOPQ")
+	NotchGII  = []rune("STVYZACDFGHKMNQ")
+	NotchGIII = []rune("UWXAEFHKMNR")
+
+	// Enigma K and Swiss K rotors share their notch positions
+	NotchKI   = []rune{'Y'}
+	NotchKII  = []rune{'E'}
+	NotchKIII = []rune{'N'}
+
+	// Railway Enigma rotors
+	NotchRailwayI   = []rune{'N'}
+	NotchRailwayII  = []rune{'E'}
+	NotchRailwayIII = []rune{'Y'}
+
+	// Enigma T rotors have five notches each; the Norway rotors keep the
+	// notches of the Enigma I rotors they were made from (NotchI to NotchV)
+	NotchTirpitzI    = []rune("WZEKQ")
+	NotchTirpitzII   = []rune("WZFLR")
+	NotchTirpitzIII  = []rune("WZEKQ")
+	NotchTirpitzIV   = []rune("WZFLR")
+	NotchTirpitzV    = []rune("YCFKR")
+	NotchTirpitzVI   = []rune("XEIMQ")
+	NotchTirpitzVII  = []rune("YCFKR")
+	NotchTirpitzVIII = []rune("XEIMQ")
 )
 
 // NewEnigmaM3 creates a historically accurate Enigma M3 machine.
@@ -68,6 +153,7 @@ func NewEnigmaM3() (*Enigma, error) {
 			Notches:        NotchI,
 			Position:       0,
 			RingSetting:    0,
+			Provenance:     provenance.Historical("M3", "I"),
 		},
 		{
 			ID:             "II",
@@ -75,6 +161,7 @@ func NewEnigmaM3() (*Enigma, error) {
 			Notches:        NotchII,
 			Position:       0,
 			RingSetting:    0,
+			Provenance:     provenance.Historical("M3", "II"),
 		},
 		{
 			ID:             "III",
@@ -82,13 +169,15 @@ func NewEnigmaM3() (*Enigma, error) {
 			Notches:        NotchIII,
 			Position:       0,
 			RingSetting:    0,
+			Provenance:     provenance.Historical("M3", "III"),
 		},
 	}
 
 	// Define the reflector specification
 	reflectorSpec := reflector.ReflectorSpec{
-		ID:      "B",
-		Mapping: ReflectorB,
+		ID:         "B",
+		Mapping:    ReflectorB,
+		Provenance: provenance.Historical("M3", "B"),
 	}
 
 	// Create the machine
@@ -118,6 +207,7 @@ func NewEnigmaM4() (*Enigma, error) {
 			Notches:        []rune{}, // The thin rotor doesn't step
 			Position:       0,
 			RingSetting:    0,
+			Provenance:     provenance.Historical("M4", "Beta"),
 		},
 		{
 			ID:             "I",
@@ -125,6 +215,7 @@ func NewEnigmaM4() (*Enigma, error) {
 			Notches:        NotchI,
 			Position:       0,
 			RingSetting:    0,
+			Provenance:     provenance.Historical("M4", "I"),
 		},
 		{
 			ID:             "II",
@@ -132,6 +223,7 @@ func NewEnigmaM4() (*Enigma, error) {
 			Notches:        NotchII,
 			Position:       0,
 			RingSetting:    0,
+			Provenance:     provenance.Historical("M4", "II"),
 		},
 		{
 			ID:             "III",
@@ -139,13 +231,15 @@ func NewEnigmaM4() (*Enigma, error) {
 			Notches:        NotchIII,
 			Position:       0,
 			RingSetting:    0,
+			Provenance:     provenance.Historical("M4", "III"),
 		},
 	}
 
 	// Define the reflector specification
 	reflectorSpec := reflector.ReflectorSpec{
-		ID:      "B-Thin",
-		Mapping: ReflectorBThin,
+		ID:         "B-Thin",
+		Mapping:    ReflectorBThin,
+		Provenance: provenance.Historical("M4", "B-Thin"),
 	}
 
 	// Create the machine
@@ -156,5 +250,167 @@ func NewEnigmaM4() (*Enigma, error) {
 	)
 }
 
+// NewEnigmaG creates an Abwehr Enigma G-312. It differs from the military
+// machines in four ways: the rotors are gear-driven (SteppingGear) and have
+// many notches, the reflector turns with the rotors and can be set like
+// them, the entry wheel follows the keyboard (EntryWheelQWERTZ), and there
+// is no plugboard. The rotors and the reflector start at A.
+func NewEnigmaG() (*Enigma, error) {
+	alphabet := []rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")
+
+	rotorSpecs := []rotor.RotorSpec{
+		{
+			ID:             "I",
+			ForwardMapping: RotorGI,
+			Notches:        NotchGI,
+			Provenance:     provenance.Historical("G-312", "I"),
+		},
+		{
+			ID:             "II",
+			ForwardMapping: RotorGII,
+			Notches:        NotchGII,
+			Provenance:     provenance.Historical("G-312", "II"),
+		},
+		{
+			ID:             "III",
+			ForwardMapping: RotorGIII,
+			Notches:        NotchGIII,
+			Provenance:     provenance.Historical("G-312", "III"),
+		},
+	}
+
+	reflectorSpec := reflector.ReflectorSpec{
+		ID:         "UKW",
+		Mapping:    ReflectorG,
+		Rotating:   true,
+		Provenance: provenance.Historical("G-312", "UKW"),
+	}
+
+	return New(
+		WithAlphabet(alphabet),
+		WithEntryWheel(EntryWheelQWERTZ),
+		WithRotorConfiguration(rotorSpecs),
+		WithReflectorConfiguration(reflectorSpec),
+		WithSteppingMode(SteppingGear),
+	)
+}
+
+// NewEnigmaK creates a commercial Enigma K (1927). Like the military machines
+// it has lever stepping with the double-step, but its entry wheel follows the
+// keyboard (EntryWheelQWERTZ), it has no plugboard, and its reflector can be
+// set to any position with SetReflectorPosition, though it never turns. The
+// rotors and the reflector start at A.
+func NewEnigmaK() (*Enigma, error) {
+	return newSettableReflectorEnigma("K", EntryWheelQWERTZ,
+		[3]string{RotorKI, RotorKII, RotorKIII},
+		[3][]rune{NotchKI, NotchKII, NotchKIII},
+		ReflectorK)
+}
+
+// NewEnigmaSwissK creates the Enigma K of the Swiss Army, whose rotors were
+// rewired in 1939 after the commercial wirings were found to be known.
+// Otherwise it is the same machine as NewEnigmaK.
+func NewEnigmaSwissK() (*Enigma, error) {
+	return newSettableReflectorEnigma("Swiss-K", EntryWheelQWERTZ,
+		[3]string{RotorSwissKI, RotorSwissKII, RotorSwissKIII},
+		[3][]rune{NotchKI, NotchKII, NotchKIII},
+		ReflectorK)
+}
+
+// NewEnigmaRailway creates the Enigma of the German Railway (Reichsbahn),
+// known at Bletchley Park as "Rocket": an Enigma K with its own rotor and
+// reflector wirings.
+func NewEnigmaRailway() (*Enigma, error) {
+	return newSettableReflectorEnigma("Railway", EntryWheelQWERTZ,
+		[3]string{RotorRailwayI, RotorRailwayII, RotorRailwayIII},
+		[3][]rune{NotchRailwayI, NotchRailwayII, NotchRailwayIII},
+		ReflectorRailway)
+}
+
+// NewEnigmaNorway creates the Norway Enigma ("Norenigma"), an Enigma I whose
+// rotors and reflector were rewired for the Norwegian police after the war.
+// It has rotors I, II and III, the Norway reflector and an empty plugboard,
+// like NewEnigmaM3.
+func NewEnigmaNorway() (*Enigma, error) {
+	alphabet := []rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")
+
+	rotorSpecs := []rotor.RotorSpec{
+		{
+			ID:             "I",
+			ForwardMapping: RotorNorwayI,
+			Notches:        NotchI,
+			Provenance:     provenance.Historical("Norway", "I"),
+		},
+		{
+			ID:             "II",
+			ForwardMapping: RotorNorwayII,
+			Notches:        NotchII,
+			Provenance:     provenance.Historical("Norway", "II"),
+		},
+		{
+			ID:             "III",
+			ForwardMapping: RotorNorwayIII,
+			Notches:        NotchIII,
+			Provenance:     provenance.Historical("Norway", "III"),
+		},
+	}
+
+	reflectorSpec := reflector.ReflectorSpec{
+		ID:         "UKW",
+		Mapping:    ReflectorNorway,
+		Provenance: provenance.Historical("Norway", "UKW"),
+	}
+
+	return New(
+		WithAlphabet(alphabet),
+		WithRotorConfiguration(rotorSpecs),
+		WithReflectorConfiguration(reflectorSpec),
+	)
+}
+
+// NewEnigmaTirpitz creates the Enigma T ("Tirpitz"), an Enigma K built for
+// the Japanese navy. It has eight rotors with five notches each, of which
+// rotors I, II and III are fitted, its own entry wheel (EntryWheelTirpitz)
+// and a settable reflector. Swap in the other rotors with
+// WithRotorConfiguration and the RotorTirpitz constants.
+func NewEnigmaTirpitz() (*Enigma, error) {
+	return newSettableReflectorEnigma("Tirpitz", EntryWheelTirpitz,
+		[3]string{RotorTirpitzI, RotorTirpitzII, RotorTirpitzIII},
+		[3][]rune{NotchTirpitzI, NotchTirpitzII, NotchTirpitzIII},
+		ReflectorTirpitz)
+}
+
+// newSettableReflectorEnigma builds one of the Enigma K family: three rotors
+// with lever stepping, the given entry wheel, a settable reflector and no
+// plugboard.
+func newSettableReflectorEnigma(machine, entryWheel string, wirings [3]string, notches [3][]rune, reflectorWiring string) (*Enigma, error) {
+	alphabet := []rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")
+
+	ids := [3]string{"I", "II", "III"}
+	rotorSpecs := make([]rotor.RotorSpec, len(ids))
+	for i, id := range ids {
+		rotorSpecs[i] = rotor.RotorSpec{
+			ID:             id,
+			ForwardMapping: wirings[i],
+			Notches:        notches[i],
+			Provenance:     provenance.Historical(machine, id),
+		}
+	}
+
+	reflectorSpec := reflector.ReflectorSpec{
+		ID:         "UKW",
+		Mapping:    reflectorWiring,
+		Settable:   true,
+		Provenance: provenance.Historical(machine, "UKW"),
+	}
+
+	return New(
+		WithAlphabet(alphabet),
+		WithEntryWheel(entryWheel),
+		WithRotorConfiguration(rotorSpecs),
+		WithReflectorConfiguration(reflectorSpec),
+	)
+}
+
 // Note: ReflectorSpec.Mapping expects a string, not a map.
 // The reflector implementation handles converting the string to the appropriate mapping.
@@ -1,6 +1,8 @@
 package enigma
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -97,6 +99,14 @@ func TestHistoricalRotorWirings(t *testing.T) {
 		RotorI, RotorII, RotorIII, RotorIV, RotorV, RotorVI, RotorVII, RotorVIII,
 		RotorBeta, RotorGamma,
 		ReflectorA, ReflectorB, ReflectorC, ReflectorBThin, ReflectorCThin,
+		RotorGI, RotorGII, RotorGIII, ReflectorG, EntryWheelQWERTZ,
+		RotorKI, RotorKII, RotorKIII, ReflectorK,
+		RotorSwissKI, RotorSwissKII, RotorSwissKIII,
+		RotorRailwayI, RotorRailwayII, RotorRailwayIII, ReflectorRailway,
+		RotorNorwayI, RotorNorwayII, RotorNorwayIII, RotorNorwayIV, RotorNorwayV, ReflectorNorway,
+		RotorTirpitzI, RotorTirpitzII, RotorTirpitzIII, RotorTirpitzIV,
+		RotorTirpitzV, RotorTirpitzVI, RotorTirpitzVII, RotorTirpitzVIII,
+		ReflectorTirpitz, EntryWheelTirpitz,
 	}
 
 	for i, wiring := range wirings {
@@ -114,3 +124,326 @@ func TestHistoricalRotorWirings(t *testing.T) {
 		}
 	}
 }
+
+// TestHistoricalProvenance checks that historical machines record where their
+// wirings came from, and that reloading the settings keeps it.
+func TestHistoricalProvenance(t *testing.T) {
+	machine, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatalf("Failed to create M3: %v", err)
+	}
+	original, err := machine.GetSettings()
+	if err != nil {
+		t.Fatalf("GetSettings failed: %v", err)
+	}
+	restored, err := NewFromSettings(original)
+	if err != nil {
+		t.Fatalf("NewFromSettings failed: %v", err)
+	}
+	settings, err := restored.GetSettings()
+	if err != nil {
+		t.Fatalf("GetSettings failed: %v", err)
+	}
+
+	for i, want := range []string{"historical:M3/I", "historical:M3/II", "historical:M3/III"} {
+		if p := settings.RotorSpecs[i].Provenance; p == nil || p.Source != want {
+			t.Errorf("rotor %d provenance = %+v, want source %s", i, p, want)
+		}
+	}
+	if p := settings.ReflectorSpec.Provenance; p == nil || p.Source != "historical:M3/B" {
+		t.Errorf("reflector provenance = %+v, want source historical:M3/B", p)
+	}
+}
+
+// TestHistoricalM3RingSettings checks the well-known vector for rotors I II III
+// with rings 02 02 02 (BBB) at AAA: AAAAA enciphers to EWTYX.
+func TestHistoricalM3RingSettings(t *testing.T) {
+	machine, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatalf("Failed to create M3: %v", err)
+	}
+	if err := machine.SetRingSettings([]int{1, 1, 1}); err != nil {
+		t.Fatalf("SetRingSettings failed: %v", err)
+	}
+	if got, _ := machine.Encrypt("AAAAA"); got != "EWTYX" {
+		t.Errorf("Encrypt(AAAAA) = %s, want EWTYX", got)
+	}
+	if got := machine.GetRingSettings(); !reflect.DeepEqual(got, []int{1, 1, 1}) {
+		t.Errorf("GetRingSettings() = %v, want [1 1 1]", got)
+	}
+
+	// Ring settings are saved with the key
+	settings, _ := machine.GetSettings()
+	restored, err := NewFromSettings(settings)
+	if err != nil {
+		t.Fatalf("NewFromSettings failed: %v", err)
+	}
+	if got := restored.GetRingSettings(); !reflect.DeepEqual(got, []int{1, 1, 1}) {
+		t.Errorf("restored ring settings = %v, want [1 1 1]", got)
+	}
+}
+
+func TestHistoricalG(t *testing.T) {
+	machine, err := NewEnigmaG()
+	if err != nil {
+		t.Fatalf("NewEnigmaG failed: %v", err)
+	}
+	if machine.GetSteppingMode() != SteppingGear || !machine.HasRotatingReflector() || machine.GetPlugboardPairCount() != 0 {
+		t.Errorf("G-312 should be gear-driven with a rotating reflector and no plugboard")
+	}
+
+	plaintext := strings.Repeat("ABWEHRNACHRICHT", 20)
+	ciphertext, err := machine.Encrypt(plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range ciphertext {
+		if ciphertext[i] == plaintext[i] {
+			t.Fatalf("character %d encrypted to itself", i)
+		}
+	}
+	if pos, _ := machine.GetReflectorPosition(); pos == 0 {
+		t.Error("the reflector never stepped")
+	}
+
+	if err := machine.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if pos, _ := machine.GetReflectorPosition(); pos != 0 {
+		t.Errorf("Reset left the reflector at %d", pos)
+	}
+	if decrypted, _ := machine.Decrypt(ciphertext); decrypted != plaintext {
+		t.Errorf("Decrypt did not recover the plaintext")
+	}
+
+	// The reflector position is part of the message setting
+	if err := machine.SetReflectorPosition(5); err != nil {
+		t.Fatal(err)
+	}
+	machine.SetRotorPositions([]int{0, 0, 0})
+	if other, _ := machine.Encrypt(plaintext); other == ciphertext {
+		t.Error("the reflector position did not change the ciphertext")
+	}
+	if err := machine.SetReflectorPosition(26); err == nil {
+		t.Error("expected an error for a reflector position outside the alphabet")
+	}
+
+	m3, _ := NewEnigmaM3()
+	if err := m3.SetReflectorPosition(1); err == nil {
+		t.Error("expected an error setting the position of a fixed reflector")
+	}
+}
+
+func TestHistoricalGSettingsRoundTrip(t *testing.T) {
+	machine, err := NewEnigmaG()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := machine.SetReflectorPosition(7); err != nil {
+		t.Fatal(err)
+	}
+	settings, err := machine.GetSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.EntryWheel != EntryWheelQWERTZ || !settings.ReflectorSpec.Rotating || settings.ReflectorSpec.Position != 7 {
+		t.Errorf("settings lost the G-312 components: entry %q, reflector %+v", settings.EntryWheel, settings.ReflectorSpec)
+	}
+
+	data, err := settings.MarshalProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded EnigmaSettings
+	if err := decoded.UnmarshalProto(data); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := NewFromSettings(&decoded)
+	if err != nil {
+		t.Fatalf("NewFromSettings failed: %v", err)
+	}
+	want, _ := machine.Encrypt("GEHEIMEKOMMANDOSACHE")
+	if got, _ := restored.Encrypt("GEHEIMEKOMMANDOSACHE"); got != want {
+		t.Errorf("restored machine encrypts to %s, want %s", got, want)
+	}
+}
+
+func TestHistoricalKFamily(t *testing.T) {
+	constructors := map[string]func() (*Enigma, error){
+		"K":       NewEnigmaK,
+		"Swiss-K": NewEnigmaSwissK,
+		"Railway": NewEnigmaRailway,
+	}
+	ciphertexts := make(map[string]bool)
+	for name, newMachine := range constructors {
+		machine, err := newMachine()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if machine.GetSteppingMode() != SteppingLever || machine.GetPlugboardPairCount() != 0 {
+			t.Errorf("%s should have lever stepping and no plugboard", name)
+		}
+		if machine.HasRotatingReflector() || !machine.HasSettableReflector() {
+			t.Errorf("%s reflector should be settable but stationary", name)
+		}
+		if settings, _ := machine.GetSettings(); settings.EntryWheel != EntryWheelQWERTZ {
+			t.Errorf("%s entry wheel = %q", name, settings.EntryWheel)
+		}
+
+		if err := machine.SetReflectorPosition(3); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		plaintext := strings.Repeat("FAHRPLAN", 100)
+		ciphertext, err := machine.Encrypt(plaintext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ciphertexts[ciphertext] = true
+		if pos, _ := machine.GetReflectorPosition(); pos != 3 {
+			t.Errorf("%s reflector moved to %d", name, pos)
+		}
+
+		// Reset returns to the saved settings, where the reflector is at A
+		machine.Reset()
+		if other, _ := machine.Encrypt(plaintext); other == ciphertext {
+			t.Errorf("%s: the reflector position did not change the ciphertext", name)
+		}
+		machine.Reset()
+		machine.SetReflectorPosition(3)
+		if decrypted, _ := machine.Decrypt(ciphertext); decrypted != plaintext {
+			t.Errorf("%s: Decrypt did not recover the plaintext", name)
+		}
+	}
+	if len(ciphertexts) != len(constructors) {
+		t.Error("the K family machines should have different wirings")
+	}
+}
+
+func TestSettableReflectorSettingsRoundTrip(t *testing.T) {
+	machine, err := NewEnigmaSwissK()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := machine.SetReflectorPosition(11); err != nil {
+		t.Fatal(err)
+	}
+	settings, err := machine.GetSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !settings.ReflectorSpec.Settable || settings.ReflectorSpec.Rotating || settings.ReflectorSpec.Position != 11 {
+		t.Errorf("settings lost the settable reflector: %+v", settings.ReflectorSpec)
+	}
+
+	data, err := settings.MarshalProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded EnigmaSettings
+	if err := decoded.UnmarshalProto(data); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := NewFromSettings(&decoded)
+	if err != nil {
+		t.Fatalf("NewFromSettings failed: %v", err)
+	}
+	if restored.HasRotatingReflector() || !restored.HasSettableReflector() {
+		t.Error("restored reflector should be settable but stationary")
+	}
+	want, _ := machine.Encrypt("SCHWEIZERARMEE")
+	if got, _ := restored.Encrypt("SCHWEIZERARMEE"); got != want {
+		t.Errorf("restored machine encrypts to %s, want %s", got, want)
+	}
+}
+
+func TestHistoricalNorway(t *testing.T) {
+	machine, err := NewEnigmaNorway()
+	if err != nil {
+		t.Fatalf("NewEnigmaNorway failed: %v", err)
+	}
+	if settings, _ := machine.GetSettings(); settings.EntryWheel != "" || settings.ReflectorSpec.Settable {
+		t.Errorf("Norway should have the plain entry wheel and a fixed reflector")
+	}
+
+	// Same stepping as the M3, different wirings
+	m3, _ := NewEnigmaM3()
+	plaintext := strings.Repeat("NORENIGMA", 50)
+	ciphertext, err := machine.Encrypt(plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m3Text, _ := m3.Encrypt(plaintext); m3Text == ciphertext {
+		t.Error("Norway should not encrypt like the M3")
+	}
+	if got, want := machine.GetCurrentRotorPositions(), m3.GetCurrentRotorPositions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Norway rotors at %v, M3 at %v; stepping should match", got, want)
+	}
+	machine.Reset()
+	if decrypted, _ := machine.Decrypt(ciphertext); decrypted != plaintext {
+		t.Error("Decrypt did not recover the plaintext")
+	}
+}
+
+func TestHistoricalTirpitz(t *testing.T) {
+	machine, err := NewEnigmaTirpitz()
+	if err != nil {
+		t.Fatalf("NewEnigmaTirpitz failed: %v", err)
+	}
+	settings, _ := machine.GetSettings()
+	if settings.EntryWheel != EntryWheelTirpitz || !settings.ReflectorSpec.Settable || machine.GetPlugboardPairCount() != 0 {
+		t.Errorf("Tirpitz components wrong: entry %q, reflector %+v", settings.EntryWheel, settings.ReflectorSpec)
+	}
+
+	// Five notches per rotor make the middle rotor turn far more often
+	plaintext := strings.Repeat("KAMIKAZE", 26)
+	ciphertext, err := machine.Encrypt(plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counts := machine.GetRotorStepCounts(); counts[1] < 10 {
+		t.Errorf("middle rotor stepped %d times in %d characters", counts[1], len(plaintext))
+	}
+	machine.Reset()
+	if decrypted, _ := machine.Decrypt(ciphertext); decrypted != plaintext {
+		t.Error("Decrypt did not recover the plaintext")
+	}
+
+	// All eight rotors can be fitted
+	wirings := []string{RotorTirpitzIV, RotorTirpitzV, RotorTirpitzVI, RotorTirpitzVII, RotorTirpitzVIII}
+	notches := [][]rune{NotchTirpitzIV, NotchTirpitzV, NotchTirpitzVI, NotchTirpitzVII, NotchTirpitzVIII}
+	for i := range wirings {
+		specs := settings.RotorSpecs
+		specs[0].ForwardMapping, specs[0].Notches = wirings[i], notches[i]
+		if _, err := New(
+			WithAlphabet([]rune(settings.Alphabet)),
+			WithEntryWheel(EntryWheelTirpitz),
+			WithRotorConfiguration(specs),
+			WithReflectorConfiguration(settings.ReflectorSpec),
+		); err != nil {
+			t.Errorf("rotor %d: %v", i+4, err)
+		}
+	}
+}
+
+func TestWithEntryWheel(t *testing.T) {
+	base := []Option{WithAlphabet([]rune("ABCD")), WithRandomSettings(Low)}
+	machine, err := New(append(base, WithEntryWheel("DCBA"))...)
+	if err != nil {
+		t.Fatalf("WithEntryWheel failed: %v", err)
+	}
+	if got := machine.GetEntryWheel(); got != "DCBA" {
+		t.Errorf("GetEntryWheel() = %q, want DCBA", got)
+	}
+	// An identity wiring is the default and is not recorded
+	if machine, _ := New(append(base, WithEntryWheel("ABCD"))...); machine.GetEntryWheel() != "" {
+		t.Errorf("identity entry wheel recorded as %q", machine.GetEntryWheel())
+	}
+	for _, bad := range []string{"ABC", "AABC", "ABCZ"} {
+		if _, err := New(append(base, WithEntryWheel(bad))...); err == nil {
+			t.Errorf("WithEntryWheel(%q): expected an error", bad)
+		}
+	}
+	if err := WithEntryWheel("ABCD")(&Enigma{}); err == nil {
+		t.Error("expected an error without an alphabet")
+	}
+}
@@ -0,0 +1,105 @@
+// Package enigma provides per-character callbacks for observing the machine.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+// StepInfo describes a single character that has just been processed.
+type StepInfo struct {
+	Index     int   // Zero-based character index within the current call
+	Input     rune  // Character fed into the machine (for grapheme alphabets, the rune standing for the symbol; see RuneSymbol)
+	Output    rune  // Character produced by the machine
+	Positions []int // Rotor positions after stepping for this character
+}
+
+// StepCallback is invoked once per processed character.
+// Callbacks run synchronously on the caller's goroutine, so any pacing
+// (for example simulated typing delays) belongs in the callback, never in
+// the engine itself.
+type StepCallback func(info StepInfo)
+
+// WithStepCallback registers a callback invoked after every processed character.
+func WithStepCallback(cb StepCallback) Option {
+	return func(e *Enigma) error {
+		e.onStep = cb
+		return nil
+	}
+}
+
+// SetStepCallback replaces the step callback. Passing nil disables it.
+func (e *Enigma) SetStepCallback(cb StepCallback) {
+	e.onStep = cb
+}
+
+// RotorEventKind identifies a mechanical event during rotor stepping.
+type RotorEventKind int
+
+const (
+	// RotorStepped is emitted every time a rotor advances one position.
+	RotorStepped RotorEventKind = iota
+	// RotorDoubleStep is emitted when the middle rotor steps a second time
+	// because it sat on its own notch (the historical double-step anomaly).
+	RotorDoubleStep
+	// RotorTurnover is emitted when a rotor's notch carries its left neighbour.
+	// The event names the rotor whose notch was passed, not the one that moves.
+	RotorTurnover
+)
+
+// String returns a short lowercase name for the event kind.
+func (k RotorEventKind) String() string {
+	switch k {
+	case RotorStepped:
+		return "stepped"
+	case RotorDoubleStep:
+		return "double-step"
+	case RotorTurnover:
+		return "turnover"
+	default:
+		return "unknown"
+	}
+}
+
+// RotorEvent describes a single stepping event for one rotor.
+type RotorEvent struct {
+	Kind     RotorEventKind
+	Index    int    // Zero-based character index within the current call
+	Rotor    int    // Rotor slot, 0 is the leftmost (slowest) rotor
+	RotorID  string // Identifier of the rotor in that slot
+	Position int    // Rotor position after the event
+	Window   rune   // Alphabet character shown in the rotor window at Position
+}
+
+// RotorEventCallback is invoked for every rotor event, in mechanical order.
+// All events for a character fire before that character's StepCallback, and
+// like step callbacks they run synchronously on the caller's goroutine.
+type RotorEventCallback func(event RotorEvent)
+
+// WithRotorEventCallback registers a callback for rotor stepping events.
+func WithRotorEventCallback(cb RotorEventCallback) Option {
+	return func(e *Enigma) error {
+		e.onRotorEvent = cb
+		return nil
+	}
+}
+
+// SetRotorEventCallback replaces the rotor event callback. Passing nil disables it.
+func (e *Enigma) SetRotorEventCallback(cb RotorEventCallback) {
+	e.onRotorEvent = cb
+}
+
+// emitRotorEvent reports an event for the rotor in slot i, if anyone listens.
+func (e *Enigma) emitRotorEvent(kind RotorEventKind, charIndex, i int) {
+	if e.onRotorEvent == nil {
+		return
+	}
+	r := e.rotors[i]
+	window, _ := e.alphabet.IndexToRune(r.GetPosition())
+	e.onRotorEvent(RotorEvent{
+		Kind:     kind,
+		Index:    charIndex,
+		Rotor:    i,
+		RotorID:  r.ID(),
+		Position: r.GetPosition(),
+		Window:   window,
+	})
+}
@@ -0,0 +1,143 @@
+package enigma
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestStepCallback(t *testing.T) {
+	machine, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatalf("Failed to create M3: %v", err)
+	}
+
+	var steps []StepInfo
+	machine.SetStepCallback(func(info StepInfo) {
+		steps = append(steps, info)
+	})
+
+	ciphertext, err := machine.Encrypt("HELLO")
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+
+	if len(steps) != 5 {
+		t.Fatalf("Expected 5 callbacks, got %d", len(steps))
+	}
+	for i, step := range steps {
+		if step.Index != i {
+			t.Errorf("Step %d has index %d", i, step.Index)
+		}
+		if step.Input != rune("HELLO"[i]) {
+			t.Errorf("Step %d input = %c, want %c", i, step.Input, "HELLO"[i])
+		}
+		if step.Output != rune(ciphertext[i]) {
+			t.Errorf("Step %d output = %c, want %c", i, step.Output, ciphertext[i])
+		}
+		if step.Positions[2] != i+1 {
+			t.Errorf("Step %d fast rotor position = %d, want %d", i, step.Positions[2], i+1)
+		}
+	}
+
+	// Disabling the callback stops notifications
+	machine.SetStepCallback(nil)
+	if _, err := machine.Encrypt("A"); err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if len(steps) != 5 {
+		t.Errorf("Callback invoked after being cleared")
+	}
+}
+
+func TestWithStepCallbackOption(t *testing.T) {
+	count := 0
+	machine, err := New(
+		WithAlphabet([]rune("ABCDEF")),
+		WithRandomSettings(Low),
+		WithStepCallback(func(StepInfo) { count++ }),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create machine: %v", err)
+	}
+
+	clone, err := machine.Clone()
+	if err != nil {
+		t.Fatalf("Clone failed: %v", err)
+	}
+
+	if _, err := clone.Encrypt("ABC"); err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected clone to keep callback (3 calls), got %d", count)
+	}
+}
+
+func TestRotorEventCallback(t *testing.T) {
+	machine, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatalf("Failed to create M3: %v", err)
+	}
+	// ADU: the next key turns the fast rotor onto V, carrying II onto its E notch
+	if err := machine.SetRotorPositions([]int{0, 3, 20}); err != nil {
+		t.Fatalf("SetRotorPositions failed: %v", err)
+	}
+
+	var events []string
+	var steps int
+	machine.SetStepCallback(func(StepInfo) { steps++ })
+	machine.SetRotorEventCallback(func(ev RotorEvent) {
+		if steps != ev.Index {
+			t.Errorf("rotor event for char %d fired after its step callback", ev.Index)
+		}
+		events = append(events, fmt.Sprintf("%d:%s:%s:%c", ev.Index, ev.RotorID, ev.Kind, ev.Window))
+	})
+
+	if _, err := machine.Encrypt("AB"); err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+
+	want := []string{
+		"0:III:stepped:V",
+		"0:III:turnover:V",
+		"0:II:stepped:E",
+		"0:II:turnover:E",
+		"0:I:stepped:B",
+		"1:III:stepped:W",
+		"1:II:stepped:F",
+		"1:II:double-step:F",
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events =\n%v\nwant\n%v", events, want)
+	}
+
+	// Clones keep the callback; clearing it stops notifications
+	clone, err := machine.Clone()
+	if err != nil {
+		t.Fatalf("Clone failed: %v", err)
+	}
+	events = nil
+	steps = 0
+	if _, err := clone.Encrypt("C"); err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if len(events) != 1 || events[0] != "0:III:stepped:X" {
+		t.Errorf("clone events = %v", events)
+	}
+
+	machine.SetRotorEventCallback(nil)
+	events = nil
+	if _, err := machine.Encrypt("C"); err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("callback invoked after being cleared: %v", events)
+	}
+}
+
+func TestRotorEventKindString(t *testing.T) {
+	if RotorTurnover.String() != "turnover" || RotorEventKind(99).String() != "unknown" {
+		t.Errorf("unexpected kind names: %s, %s", RotorTurnover, RotorEventKind(99))
+	}
+}
@@ -0,0 +1,92 @@
+// Package enigma provides exact keyspace statistics for machine configurations.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import (
+	"math"
+	"math/big"
+)
+
+// KeyspaceReport counts the configurations a machine's key is drawn from,
+// for a machine of the same shape with freely wired components. Counts are
+// exact, so they do not overflow for large alphabets or many rotors. Ring
+// settings are not counted, since with free wirings they only relabel a
+// wiring, nor are the positions of the Uhr or of a settable reflector. The
+// total bounds brute-force effort; it is not a measure of cryptographic
+// strength.
+type KeyspaceReport struct {
+	AlphabetSize          int
+	RotorCount            int
+	PlugboardPairs        int
+	ReflectorFixedPoints  int
+	RotorWirings          *big.Int // Ways to wire the rotors, each a permutation of the alphabet: (n!)^rotors
+	RotorPositions        *big.Int // Starting positions: n^rotors
+	ReflectorWirings      *big.Int // Reciprocal reflector wirings with its fixed points; 1 without a reflector
+	PlugboardCombinations *big.Int // Ways to choose the plugboard pairs; 1 without pairs
+	Total                 *big.Int // Product of the above
+	TotalBits             float64  // log2(Total), the key entropy of a randomly generated key
+}
+
+// KeyspaceReport computes the keyspace statistics of the machine's shape.
+func (e *Enigma) KeyspaceReport() KeyspaceReport {
+	n := e.alphabet.Size()
+	r := KeyspaceReport{
+		AlphabetSize:          n,
+		RotorCount:            len(e.rotors),
+		PlugboardPairs:        e.plugboard.PairCount(),
+		ReflectorFixedPoints:  e.ReflectorFixedPoints(),
+		RotorPositions:        new(big.Int).Exp(big.NewInt(int64(n)), big.NewInt(int64(len(e.rotors))), nil),
+		ReflectorWirings:      big.NewInt(1),
+		PlugboardCombinations: big.NewInt(1),
+	}
+	r.RotorWirings = new(big.Int).Exp(factorial(n), big.NewInt(int64(len(e.rotors))), nil)
+	if e.reflector != nil {
+		r.ReflectorWirings = involutions(n, r.ReflectorFixedPoints)
+	}
+	if p := r.PlugboardPairs; p > 0 {
+		// n! / ((n-2p)! p! 2^p)
+		c := new(big.Int).MulRange(int64(n-2*p+1), int64(n))
+		c.Quo(c, factorial(p))
+		r.PlugboardCombinations = c.Rsh(c, uint(p))
+	}
+
+	r.Total = new(big.Int).Mul(r.RotorWirings, r.RotorPositions)
+	r.Total.Mul(r.Total, r.ReflectorWirings)
+	r.Total.Mul(r.Total, r.PlugboardCombinations)
+	r.TotalBits = KeyspaceBits(r.Total)
+	return r
+}
+
+// involutions returns the number of reciprocal wirings of n characters that
+// leave exactly fixed of them in place: C(n, fixed) * (n-fixed-1)!!.
+func involutions(n, fixed int) *big.Int {
+	count := new(big.Int).Binomial(int64(n), int64(fixed))
+	for k := n - fixed - 1; k > 1; k -= 2 {
+		count.Mul(count, big.NewInt(int64(k)))
+	}
+	return count
+}
+
+// factorial returns n!.
+func factorial(n int) *big.Int {
+	if n < 2 {
+		return big.NewInt(1)
+	}
+	return new(big.Int).MulRange(2, int64(n))
+}
+
+// KeyspaceBits returns log2(count), the number of bits needed to index count
+// keys, to float64 precision. It is 0 for counts below 1.
+func KeyspaceBits(x *big.Int) float64 {
+	if x.Sign() <= 0 {
+		return 0
+	}
+	shift := x.BitLen() - 53
+	if shift <= 0 {
+		return math.Log2(float64(x.Int64()))
+	}
+	top := new(big.Int).Rsh(x, uint(shift))
+	return math.Log2(float64(top.Int64())) + float64(shift)
+}
@@ -0,0 +1,88 @@
+package enigma
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestKeyspaceReport(t *testing.T) {
+	machine, err := New(WithAlphabet([]rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")), WithRandomComponents(3, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := machine.KeyspaceReport()
+
+	checks := []struct {
+		name string
+		got  *big.Int
+		want string
+	}{
+		{"rotor positions", r.RotorPositions, "17576"},
+		{"reflector wirings", r.ReflectorWirings, "7905853580625"},
+		{"plugboard combinations", r.PlugboardCombinations, "150738274937250"},
+	}
+	for _, c := range checks {
+		if c.got.String() != c.want {
+			t.Errorf("%s = %s, want %s", c.name, c.got, c.want)
+		}
+	}
+	f26 := new(big.Int).MulRange(1, 26)
+	if want := new(big.Int).Exp(f26, big.NewInt(3), nil); r.RotorWirings.Cmp(want) != 0 {
+		t.Errorf("rotor wirings = %s, want (26!)^3", r.RotorWirings)
+	}
+
+	lg, _ := math.Lgamma(27)
+	wantBits := 3*lg/math.Ln2 + 3*math.Log2(26) + math.Log2(7905853580625) + math.Log2(150738274937250)
+	if math.Abs(r.TotalBits-wantBits) > 1e-6 {
+		t.Errorf("TotalBits = %f, want %f", r.TotalBits, wantBits)
+	}
+}
+
+func TestKeyspaceReportShapes(t *testing.T) {
+	odd, err := New(WithAlphabet([]rune("ABCDE")), WithReflectorFixedPoints(1), WithoutPlugboard(), WithRandomSettings(Low))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := odd.KeyspaceReport(); r.ReflectorWirings.Int64() != 15 || r.PlugboardCombinations.Int64() != 1 {
+		t.Errorf("5 characters with 1 fixed point: reflector %s, plugboard %s; want 15 and 1", r.ReflectorWirings, r.PlugboardCombinations)
+	}
+
+	straight, err := New(WithAlphabet([]rune("ABCDEFG")), WithoutReflector(), WithRandomSettings(Low))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := straight.KeyspaceReport(); r.ReflectorWirings.Int64() != 1 {
+		t.Errorf("a reflector-less machine has %s reflector wirings, want 1", r.ReflectorWirings)
+	}
+
+	// Twelve rotors over 95 characters: far beyond int64 and float64
+	extreme, err := New(WithAlphabet([]rune(" !\"#$%&'()*+,-./0123456789:;<=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_`abcdefghijklmnopqrstuvwxyz{|}~")), WithoutReflector(), WithRandomSettings(Extreme))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := extreme.KeyspaceReport()
+	if r.RotorCount != 12 || r.Total.BitLen() < 5000 {
+		t.Errorf("extreme keyspace has %d rotors and %d bits", r.RotorCount, r.Total.BitLen())
+	}
+	if math.IsInf(r.TotalBits, 0) || math.Abs(r.TotalBits-float64(r.Total.BitLen())) > 1 {
+		t.Errorf("TotalBits = %f for a %d-bit total", r.TotalBits, r.Total.BitLen())
+	}
+}
+
+func TestKeyspaceBits(t *testing.T) {
+	tests := []struct {
+		count *big.Int
+		want  float64
+	}{
+		{big.NewInt(0), 0},
+		{big.NewInt(1), 0},
+		{big.NewInt(1024), 10},
+		{new(big.Int).Lsh(big.NewInt(3), 100000), 100000 + math.Log2(3)},
+	}
+	for _, tt := range tests {
+		if got := KeyspaceBits(tt.count); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("KeyspaceBits(%d bits) = %f, want %f", tt.count.BitLen(), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// Package enigma provides size limits for configurations read from untrusted sources.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import "fmt"
+
+// Limits bound the configurations a loader accepts, so a hostile or corrupt
+// file cannot exhaust memory or CPU. A zero field takes its value from
+// DefaultLimits; a negative field disables that limit.
+type Limits struct {
+	MaxConfigBytes  int // Size of the serialized configuration in bytes
+	MaxAlphabetSize int // Characters in the alphabet
+	MaxRotors       int // Number of rotors
+	MaxDepth        int // Nesting depth of JSON objects and arrays
+}
+
+// DefaultLimits are the limits NewFromJSON and LoadSettingsFromJSON apply.
+// They leave ample room for any key enigoma generates: the extreme security
+// level uses 12 rotors and auto-detected alphabets stop at 1000 characters.
+var DefaultLimits = Limits{
+	MaxConfigBytes:  4 << 20,
+	MaxAlphabetSize: 16384,
+	MaxRotors:       64,
+	MaxDepth:        32,
+}
+
+// LimitError reports a configuration that exceeds one of its Limits. Match it
+// with errors.As.
+type LimitError struct {
+	Limit string // The exceeded limit, e.g. "config size"
+	Value int    // The configuration's value, or the point where reading stopped
+	Max   int    // The limit in force
+}
+
+// Error implements error.
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s of %d exceeds the limit of %d", e.Limit, e.Value, e.Max)
+}
+
+// withDefaults fills zero fields from DefaultLimits.
+func (l Limits) withDefaults() Limits {
+	if l.MaxConfigBytes == 0 {
+		l.MaxConfigBytes = DefaultLimits.MaxConfigBytes
+	}
+	if l.MaxAlphabetSize == 0 {
+		l.MaxAlphabetSize = DefaultLimits.MaxAlphabetSize
+	}
+	if l.MaxRotors == 0 {
+		l.MaxRotors = DefaultLimits.MaxRotors
+	}
+	if l.MaxDepth == 0 {
+		l.MaxDepth = DefaultLimits.MaxDepth
+	}
+	return l
+}
+
+// exceeds returns a LimitError when a positive max is exceeded.
+func exceeds(limit string, value, max int) error {
+	if max > 0 && value > max {
+		return &LimitError{Limit: limit, Value: value, Max: max}
+	}
+	return nil
+}
+
+// checkSettings checks decoded settings before any component is built.
+func (l Limits) checkSettings(s *EnigmaSettings) error {
+	if err := exceeds("alphabet size", len(s.Alphabet), l.MaxAlphabetSize); err != nil {
+		return err
+	}
+	if err := exceeds("symbol count", len(s.Symbols), l.MaxAlphabetSize); err != nil {
+		return err
+	}
+	if err := exceeds("rotor count", len(s.RotorSpecs), l.MaxRotors); err != nil {
+		return err
+	}
+	// A valid plugboard never lists more entries than there are characters
+	return exceeds("plugboard entry count", len(s.PlugboardPairs), l.MaxAlphabetSize)
+}
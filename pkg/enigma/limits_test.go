@@ -0,0 +1,88 @@
+//go:build !tinygo
+
+package enigma
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewFromJSONWithLimits(t *testing.T) {
+	machine, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsonData, err := machine.SaveSettingsToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewFromJSONWithLimits(jsonData, Limits{}); err != nil {
+		t.Fatalf("zero Limits should apply the defaults: %v", err)
+	}
+
+	tests := map[string]struct {
+		data   string
+		limits Limits
+		limit  string
+	}{
+		"size":     {jsonData, Limits{MaxConfigBytes: 100}, "config size"},
+		"alphabet": {jsonData, Limits{MaxAlphabetSize: 25}, "alphabet size"},
+		"rotors":   {jsonData, Limits{MaxRotors: 2}, "rotor count"},
+		"depth":    {strings.Repeat("[", 40) + strings.Repeat("]", 40), Limits{}, "nesting depth"},
+	}
+	for name, tt := range tests {
+		_, err := NewFromJSONWithLimits(tt.data, tt.limits)
+		var limitErr *LimitError
+		if !errors.As(err, &limitErr) {
+			t.Errorf("%s: error = %v, want a *LimitError", name, err)
+			continue
+		}
+		if limitErr.Limit != tt.limit {
+			t.Errorf("%s: Limit = %q, want %q", name, limitErr.Limit, tt.limit)
+		}
+	}
+
+	if _, err := NewFromJSONWithLimits(jsonData, Limits{MaxConfigBytes: -1, MaxRotors: -1}); err != nil {
+		t.Errorf("negative limits should be disabled: %v", err)
+	}
+}
+
+func TestCheckJSONDepthIgnoresStrings(t *testing.T) {
+	data := []byte(`{"description": "` + strings.Repeat("[{", 100) + `\"]"}`)
+	if err := checkJSONDepth(data, 2); err != nil {
+		t.Errorf("brackets inside strings counted towards the depth: %v", err)
+	}
+}
+
+func TestNewFromJSONReader(t *testing.T) {
+	machine, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsonData, _ := machine.SaveSettingsToJSON()
+
+	if _, err := NewFromJSONReader(context.Background(), strings.NewReader(jsonData), Limits{}); err != nil {
+		t.Fatalf("NewFromJSONReader failed: %v", err)
+	}
+
+	// The reader stops after the limit instead of consuming the whole stream
+	endless := io.MultiReader(strings.NewReader(jsonData), strings.NewReader(strings.Repeat(" ", 1<<20)))
+	_, err = NewFromJSONReader(context.Background(), endless, Limits{MaxConfigBytes: 4096})
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) || limitErr.Value != 4097 {
+		t.Errorf("error = %v, want a config size *LimitError stopping at 4097 bytes", err)
+	}
+
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := NewFromJSONReader(ctx, pr, Limits{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want context.DeadlineExceeded from a stalled reader", err)
+	}
+}
@@ -0,0 +1,190 @@
+// Package enigma provides the historical message procedure with indicator groups.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// MessageGroupSize is the length of the cipher groups a Message is written in.
+const MessageGroupSize = 5
+
+// Message is a message enciphered with the operator procedure used from 1940
+// onwards. The operator chose a Grundstellung (start position) and sent it in
+// the clear, enciphered a freely chosen message key at that position to get
+// the indicator, then enciphered the text with the rotors at the message key.
+// Only the Grundstellung and indicator travel with the text; the message key
+// itself never does.
+type Message struct {
+	Grundstellung string   // Start position for the indicator, sent in the clear
+	Indicator     string   // The message key enciphered at the Grundstellung
+	Groups        []string // Ciphertext in groups of MessageGroupSize characters
+}
+
+// String renders the message the way it was transmitted: the Grundstellung
+// and indicator, then the cipher groups.
+//
+//	WZA UHL
+//	QBLTW LDAHH YEOEF PTWYB LENDP MKOXL DFAMU DWIJD XRJZY
+func (m *Message) String() string {
+	return m.Grundstellung + " " + m.Indicator + "\n" + strings.Join(m.Groups, " ")
+}
+
+// Ciphertext returns the cipher groups joined without separators.
+func (m *Message) Ciphertext() string {
+	return strings.Join(m.Groups, "")
+}
+
+// ParseMessage reads a message in the format written by Message.String. Any
+// whitespace separates the fields: the first is the Grundstellung, the second
+// the indicator and the rest are cipher groups.
+func ParseMessage(s string) (*Message, error) {
+	fields := strings.Fields(s)
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("message must start with the Grundstellung and indicator")
+	}
+	return &Message{Grundstellung: fields[0], Indicator: fields[1], Groups: fields[2:]}, nil
+}
+
+// EncryptMessage enciphers plaintext with a random Grundstellung and message
+// key, one character per rotor from the machine's alphabet. The rotor
+// positions are restored afterwards, so the machine's own positions play no
+// part in the message.
+func (e *Enigma) EncryptMessage(plaintext string) (*Message, error) {
+	grundstellung, err := e.randomRotorSetting()
+	if err != nil {
+		return nil, fmt.Errorf("failed to choose Grundstellung: %v", err)
+	}
+	messageKey, err := e.randomRotorSetting()
+	if err != nil {
+		return nil, fmt.Errorf("failed to choose message key: %v", err)
+	}
+	return e.EncryptMessageWithKeys(plaintext, grundstellung, messageKey)
+}
+
+// EncryptMessageWithKeys enciphers plaintext with the given Grundstellung and
+// message key, each one character per rotor (e.g. "WZA" and "SXT" on a
+// three-rotor machine). The rotor positions are restored afterwards.
+func (e *Enigma) EncryptMessageWithKeys(plaintext, grundstellung, messageKey string) (*Message, error) {
+	if err := e.checkGroupable(); err != nil {
+		return nil, err
+	}
+	defer e.restorePositions(e.GetCurrentRotorPositions())
+
+	if err := e.setRotorSetting(grundstellung, "Grundstellung"); err != nil {
+		return nil, err
+	}
+	indicator, err := e.Encrypt(messageKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encipher message key: %v", err)
+	}
+	if err := e.setRotorSetting(messageKey, "message key"); err != nil {
+		return nil, err
+	}
+	ciphertext, err := e.Encrypt(plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Message{
+		Grundstellung: grundstellung,
+		Indicator:     indicator,
+		Groups:        splitGroups(e.alphabet.Segment(ciphertext), MessageGroupSize),
+	}, nil
+}
+
+// DecryptMessage recovers the message key by deciphering the indicator at the
+// Grundstellung, then deciphers the groups from the message key. The rotor
+// positions are restored afterwards.
+func (e *Enigma) DecryptMessage(msg *Message) (string, error) {
+	if msg == nil {
+		return "", fmt.Errorf("message cannot be nil")
+	}
+	if err := e.checkGroupable(); err != nil {
+		return "", err
+	}
+	defer e.restorePositions(e.GetCurrentRotorPositions())
+
+	if err := e.setRotorSetting(msg.Grundstellung, "Grundstellung"); err != nil {
+		return "", err
+	}
+	if len(e.alphabet.Segment(msg.Indicator)) != len(e.rotors) {
+		return "", fmt.Errorf("indicator %q must have one character per rotor (%d)", msg.Indicator, len(e.rotors))
+	}
+	messageKey, err := e.Decrypt(msg.Indicator)
+	if err != nil {
+		return "", fmt.Errorf("failed to decipher indicator: %v", err)
+	}
+	if err := e.setRotorSetting(messageKey, "message key"); err != nil {
+		return "", err
+	}
+	return e.Decrypt(msg.Ciphertext())
+}
+
+// checkGroupable rejects alphabets containing whitespace, which would be
+// confused with the separators between groups.
+func (e *Enigma) checkGroupable() error {
+	for _, s := range e.alphabet.Symbols() {
+		if strings.IndexFunc(s, unicode.IsSpace) >= 0 {
+			return fmt.Errorf("the message procedure separates groups with spaces, so the alphabet cannot contain whitespace (%q)", s)
+		}
+	}
+	return nil
+}
+
+// setRotorSetting sets the rotors to a setting written as one alphabet
+// character (symbol, in grapheme mode) per rotor. what names the setting in
+// errors.
+func (e *Enigma) setRotorSetting(setting, what string) error {
+	symbols := e.alphabet.Segment(e.normalization.apply(setting))
+	if len(symbols) != len(e.rotors) {
+		return fmt.Errorf("%s %q must have one character per rotor (%d)", what, setting, len(e.rotors))
+	}
+	positions := make([]int, len(symbols))
+	for i, symbol := range symbols {
+		idx, err := e.alphabet.SymbolToIndex(symbol)
+		if err != nil {
+			return fmt.Errorf("%s %q: %v", what, setting, err)
+		}
+		positions[i] = idx
+	}
+	return e.SetRotorPositions(positions)
+}
+
+// randomRotorSetting returns one random alphabet character per rotor.
+func (e *Enigma) randomRotorSetting() (string, error) {
+	setting := make([]int, len(e.rotors))
+	for i := range setting {
+		idx, err := e.randomness().Intn(e.alphabet.Size())
+		if err != nil {
+			return "", err
+		}
+		setting[i] = idx
+	}
+	return e.alphabet.IndicesToString(setting)
+}
+
+// restorePositions puts the rotors back where they were before a message.
+func (e *Enigma) restorePositions(positions []int) {
+	for i, pos := range positions {
+		e.rotors[i].SetPosition(pos)
+	}
+}
+
+// splitGroups joins the characters (or symbols) of a text into groups of
+// size; the last may be shorter.
+func splitGroups(chars []string, size int) []string {
+	groups := make([]string, 0, (len(chars)+size-1)/size)
+	for start := 0; start < len(chars); start += size {
+		end := start + size
+		if end > len(chars) {
+			end = len(chars)
+		}
+		groups = append(groups, strings.Join(chars[start:end], ""))
+	}
+	return groups
+}
@@ -0,0 +1,94 @@
+package enigma
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMessageRoundTrip(t *testing.T) {
+	sender, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatal(err)
+	}
+	const plaintext = "ANGRIFFIMMORGENGRAUENXPUNKTX"
+	msg, err := sender.EncryptMessage(plaintext)
+	if err != nil {
+		t.Fatalf("EncryptMessage failed: %v", err)
+	}
+	if len(msg.Grundstellung) != 3 || len(msg.Indicator) != 3 {
+		t.Errorf("header = %q %q, want three letters each", msg.Grundstellung, msg.Indicator)
+	}
+	for i, group := range msg.Groups {
+		if i < len(msg.Groups)-1 && len(group) != MessageGroupSize {
+			t.Errorf("group %d = %q, want %d letters", i, group, MessageGroupSize)
+		}
+	}
+
+	parsed, err := ParseMessage(msg.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed, msg) {
+		t.Errorf("ParseMessage(String()) = %+v, want %+v", parsed, msg)
+	}
+
+	receiver, _ := NewEnigmaM3()
+	got, err := receiver.DecryptMessage(parsed)
+	if err != nil || got != plaintext {
+		t.Errorf("DecryptMessage = %q, %v; want %q", got, err, plaintext)
+	}
+}
+
+func TestEncryptMessageWithKeys(t *testing.T) {
+	machine, _ := NewEnigmaM3()
+	machine.SetRotorPositions([]int{7, 8, 9})
+	msg, err := machine.EncryptMessageWithKeys("HELLOWORLD", "WZA", "SXT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := machine.GetCurrentRotorPositions(); !reflect.DeepEqual(got, []int{7, 8, 9}) {
+		t.Errorf("positions after EncryptMessage = %v, want [7 8 9]", got)
+	}
+
+	// The indicator is the message key enciphered at the Grundstellung, and
+	// the text is enciphered from the message key.
+	manual, _ := NewEnigmaM3()
+	manual.SetRotorPositions([]int{22, 25, 0})
+	indicator, _ := manual.Encrypt("SXT")
+	manual.SetRotorPositions([]int{18, 23, 19})
+	ciphertext, _ := manual.Encrypt("HELLOWORLD")
+	if msg.Grundstellung != "WZA" || msg.Indicator != indicator {
+		t.Errorf("header = %s %s, want WZA %s", msg.Grundstellung, msg.Indicator, indicator)
+	}
+	if want := ciphertext[:5] + " " + ciphertext[5:]; strings.Join(msg.Groups, " ") != want {
+		t.Errorf("groups = %v, want %q", msg.Groups, want)
+	}
+}
+
+func TestMessageErrors(t *testing.T) {
+	machine, _ := NewEnigmaM3()
+	if _, err := machine.EncryptMessageWithKeys("HELLO", "WZ", "SXT"); err == nil {
+		t.Error("expected an error for a short Grundstellung")
+	}
+	if _, err := machine.EncryptMessageWithKeys("HELLO", "WZA", "SX1"); err == nil {
+		t.Error("expected an error for a message key outside the alphabet")
+	}
+	if _, err := machine.DecryptMessage(&Message{Grundstellung: "WZA", Indicator: "UH"}); err == nil {
+		t.Error("expected an error for a short indicator")
+	}
+	if _, err := machine.DecryptMessage(nil); err == nil {
+		t.Error("expected an error for a nil message")
+	}
+	if _, err := ParseMessage("WZA"); err == nil {
+		t.Error("expected an error for a message without an indicator")
+	}
+
+	spaced, err := New(WithAlphabet([]rune("ABCD ")), WithoutReflector(), WithRandomSettings(Low))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := spaced.EncryptMessage("AB CD"); err == nil || !strings.Contains(err.Error(), "whitespace") {
+		t.Errorf("expected a whitespace alphabet error, got %v", err)
+	}
+}
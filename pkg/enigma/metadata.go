@@ -0,0 +1,84 @@
+// Package enigma provides metadata helpers such as key expiry.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import (
+	"fmt"
+	"time"
+)
+
+// GetMetadata returns a copy of the machine's metadata, or nil if none is set.
+func (e *Enigma) GetMetadata() *Metadata {
+	return e.metadata.clone()
+}
+
+// SetMetadata attaches descriptive metadata to the machine. It is included in
+// GetSettings and therefore in saved configurations. Passing nil clears it.
+func (e *Enigma) SetMetadata(m *Metadata) {
+	e.metadata = m.clone()
+}
+
+// WithMetadata attaches metadata to a newly constructed machine.
+func WithMetadata(m *Metadata) Option {
+	return func(e *Enigma) error {
+		if m != nil && m.ExpiresAt != "" {
+			if _, err := m.ExpiryTime(); err != nil {
+				return err
+			}
+		}
+		e.SetMetadata(m)
+		return nil
+	}
+}
+
+// clone returns a deep copy of the metadata.
+func (m *Metadata) clone() *Metadata {
+	if m == nil {
+		return nil
+	}
+	c := *m
+	if m.Tags != nil {
+		c.Tags = append([]string(nil), m.Tags...)
+	}
+	if m.Signature != nil {
+		sig := *m.Signature
+		c.Signature = &sig
+	}
+	return &c
+}
+
+// ExpiryTime parses ExpiresAt. The zero time is returned when no expiry is set.
+func (m *Metadata) ExpiryTime() (time.Time, error) {
+	if m == nil || m.ExpiresAt == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, m.ExpiresAt)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid expires_at %q (expected RFC 3339, e.g. 2025-12-31T00:00:00Z): %v", m.ExpiresAt, err)
+	}
+	return t, nil
+}
+
+// CreatedTime parses CreatedAt. The zero time is returned when it is not set.
+func (m *Metadata) CreatedTime() (time.Time, error) {
+	if m == nil || m.CreatedAt == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, m.CreatedAt)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid created_at %q: %v", m.CreatedAt, err)
+	}
+	return t, nil
+}
+
+// IsExpired reports whether the key has passed its expiry at the given time.
+// Keys without an expiry never expire.
+func (m *Metadata) IsExpired(now time.Time) (bool, error) {
+	expiry, err := m.ExpiryTime()
+	if err != nil || expiry.IsZero() {
+		return false, err
+	}
+	return !now.Before(expiry), nil
+}
@@ -0,0 +1,80 @@
+package enigma
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMetadataExpiry(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		meta      *Metadata
+		expired   bool
+		expectErr bool
+	}{
+		{"nil metadata", nil, false, false},
+		{"no expiry", &Metadata{Description: "key"}, false, false},
+		{"future expiry", &Metadata{ExpiresAt: "2025-12-31T00:00:00Z"}, false, false},
+		{"past expiry", &Metadata{ExpiresAt: "2025-01-01T00:00:00Z"}, true, false},
+		{"expires exactly now", &Metadata{ExpiresAt: "2025-06-01T00:00:00Z"}, true, false},
+		{"malformed expiry", &Metadata{ExpiresAt: "next tuesday"}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expired, err := tt.meta.IsExpired(now)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("expected error for %q", tt.meta.ExpiresAt)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if expired != tt.expired {
+				t.Errorf("IsExpired() = %v, want %v", expired, tt.expired)
+			}
+		})
+	}
+}
+
+func TestWithMetadataRejectsInvalidExpiry(t *testing.T) {
+	_, err := New(
+		WithAlphabet([]rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")),
+		WithRandomSettings(Low),
+		WithMetadata(&Metadata{ExpiresAt: "31/12/2025"}),
+	)
+	if err == nil {
+		t.Error("expected error for non-RFC 3339 expires_at")
+	}
+}
+
+func TestNewFromTextAlphabetOrdering(t *testing.T) {
+	machine, err := NewFromText("CABBAGE", Low, WithAlphabetOrdering(AsEncountered))
+	if err != nil {
+		t.Fatalf("NewFromText failed: %v", err)
+	}
+	settings, _ := machine.GetSettings()
+	if got := string(settings.Alphabet); got != "CABGE " {
+		t.Errorf("alphabet = %q, want %q", got, "CABGE ")
+	}
+	if got := machine.GetMetadata().AlphabetOrdering; got != "encountered" {
+		t.Errorf("metadata ordering = %q, want encountered", got)
+	}
+
+	// The default is recorded too
+	machine, err = NewFromText("CABBAGE", Low)
+	if err != nil {
+		t.Fatalf("NewFromText failed: %v", err)
+	}
+	if got := machine.GetMetadata().AlphabetOrdering; got != "codepoint" {
+		t.Errorf("metadata ordering = %q, want codepoint", got)
+	}
+
+	if o, err := ParseAlphabetOrdering("frequency"); err != nil || o != Frequency {
+		t.Errorf("ParseAlphabetOrdering(frequency) = %v, %v", o, err)
+	}
+}
@@ -0,0 +1,19 @@
+//go:build !tinygo
+
+package enigma
+
+import "testing"
+
+func TestWithoutPlugboardFingerprint(t *testing.T) {
+	machine := newNoPlugboardMachine(t)
+
+	// Disabling the plugboard changes the fingerprint
+	settings, _ := machine.GetSettings()
+	settings.PlugboardDisabled = false
+	empty, _ := NewFromSettings(settings)
+	with, _ := machine.Fingerprint()
+	without, _ := empty.Fingerprint()
+	if with == without {
+		t.Error("the disabled plugboard should change the fingerprint")
+	}
+}
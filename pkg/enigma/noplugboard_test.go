@@ -0,0 +1,82 @@
+package enigma
+
+import (
+	"testing"
+)
+
+func newNoPlugboardMachine(t *testing.T) *Enigma {
+	t.Helper()
+	machine, err := New(
+		WithAlphabet([]rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")),
+		WithoutPlugboard(),
+		WithRandomSettings(High),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return machine
+}
+
+func TestWithoutPlugboard(t *testing.T) {
+	machine := newNoPlugboardMachine(t)
+	if !machine.IsPlugboardDisabled() || !machine.Capabilities().NoPlugboard {
+		t.Error("the machine should report that it has no plugboard")
+	}
+	if n := machine.GetPlugboardPairCount(); n != 0 {
+		t.Errorf("WithRandomSettings generated %d pairs without a plugboard", n)
+	}
+
+	// The same machine with an empty plugboard enciphers identically
+	settings, err := machine.GetSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !settings.PlugboardDisabled {
+		t.Error("settings should record the disabled plugboard")
+	}
+	settings.PlugboardDisabled = false
+	empty, err := NewFromSettings(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := empty.Encrypt("WETTERVORHERSAGEBISKAYA")
+	if got, _ := machine.Encrypt("WETTERVORHERSAGEBISKAYA"); got != want {
+		t.Errorf("Encrypt = %s, want %s", got, want)
+	}
+
+	// The plugboard stage is skipped, not traced as a no-op
+	machine.Reset()
+	traces, _, err := machine.EncryptTraced("A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, stage := range traces[0].Stages {
+		if stage.Kind == TracePlugboard || stage.Kind == TracePlugboardReturn {
+			t.Errorf("unexpected plugboard stage %+v", stage)
+		}
+	}
+
+	clone, err := machine.Clone()
+	if err != nil || !clone.IsPlugboardDisabled() {
+		t.Errorf("Clone lost the disabled plugboard: %v", err)
+	}
+}
+
+func TestWithoutPlugboardErrors(t *testing.T) {
+	_, err := New(
+		WithAlphabet([]rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")),
+		WithoutPlugboard(),
+		WithRandomComponents(3, 0),
+		WithPlugboardConfiguration(map[rune]rune{'A': 'B', 'B': 'A'}),
+	)
+	if err == nil {
+		t.Error("expected an error for plugboard pairs without a plugboard")
+	}
+
+	machine := newNoPlugboardMachine(t)
+	settings, _ := machine.GetSettings()
+	settings.PlugboardPairs = map[rune]rune{'A': 'B', 'B': 'A'}
+	if err := machine.LoadSettings(settings); err == nil {
+		t.Error("expected LoadSettings to reject pairs on a disabled plugboard")
+	}
+}
@@ -0,0 +1,155 @@
+// Package enigma provides Unicode normalization of input text and alphabets.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/coredds/enigoma/internal/alphabet"
+	"github.com/coredds/enigoma/internal/normalize"
+)
+
+// Normalization selects the Unicode normalization form text and alphabets
+// are brought to, so that a character written precomposed ("é" as U+00E9)
+// and decomposed ("e" followed by the combining accent U+0301) are the same
+// character to the machine.
+type Normalization int
+
+const (
+	// NormalizationNone leaves text as written. It is the default.
+	NormalizationNone Normalization = iota
+	// NormalizationNFC composes characters wherever Unicode defines a
+	// precomposed form, so "é" is one rune. It suits alphabets of runes.
+	NormalizationNFC
+	// NormalizationNFD decomposes characters into a base and combining
+	// marks, so "é" is two runes: an alphabet of runes enciphers the letter
+	// and the mark separately, a grapheme alphabet keeps them as one symbol.
+	NormalizationNFD
+)
+
+// String returns the normalization's name as written in settings files.
+func (n Normalization) String() string {
+	switch n {
+	case NormalizationNone:
+		return "none"
+	case NormalizationNFC:
+		return "nfc"
+	case NormalizationNFD:
+		return "nfd"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseNormalization parses a normalization name as returned by
+// Normalization.String. An empty name is the default, NormalizationNone.
+func ParseNormalization(name string) (Normalization, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "none":
+		return NormalizationNone, nil
+	case "nfc":
+		return NormalizationNFC, nil
+	case "nfd":
+		return NormalizationNFD, nil
+	default:
+		return NormalizationNone, fmt.Errorf("unknown normalization: %s. Available: none, nfc, nfd", name)
+	}
+}
+
+// valid reports whether n is one of the defined forms.
+func (n Normalization) valid() bool {
+	return n >= NormalizationNone && n <= NormalizationNFD
+}
+
+// normalizer returns the function bringing text to the form, or nil for
+// NormalizationNone.
+func (n Normalization) normalizer() func(string) string {
+	switch n {
+	case NormalizationNFC:
+		return normalize.NFC
+	case NormalizationNFD:
+		return normalize.NFD
+	default:
+		return nil
+	}
+}
+
+// apply brings s to the form.
+func (n Normalization) apply(s string) string {
+	if fn := n.normalizer(); fn != nil {
+		return fn(s)
+	}
+	return s
+}
+
+// WithNormalization brings the alphabet and every text passed to Encrypt or
+// Decrypt to the given normalization form, so that text typed or pasted in
+// either form is accepted. Text is normalized before input policies run, and
+// characters passed through by UnknownCharPass come out normalized too.
+//
+// The alphabet set before or after this option is normalized too: the runes
+// of an alphabet of runes are normalized as one string, so "é" brings the
+// combining accent into the alphabet in NFD, and each symbol of a grapheme
+// alphabet is normalized on its own. Characters that become the same one are
+// rejected as duplicates. Components are wired to the normalized alphabet,
+// so the option must come before them. The form is saved with the settings
+// (schema version 2) but is not part of the fingerprint.
+func WithNormalization(n Normalization) Option {
+	return func(e *Enigma) error {
+		if !n.valid() {
+			return fmt.Errorf("invalid normalization: %d", n)
+		}
+		if e.alphabet != nil && (len(e.rotors) > 0 || e.reflector != nil || e.plugboard != nil) {
+			return fmt.Errorf("normalization must be set before the rotors, reflector and plugboard")
+		}
+		e.normalization = n
+		if e.alphabet == nil {
+			return nil
+		}
+		return e.setAlphabet(e.alphabet)
+	}
+}
+
+// GetNormalization returns the normalization form applied to text and the
+// alphabet.
+func (e *Enigma) GetNormalization() Normalization {
+	return e.normalization
+}
+
+// setAlphabet sets alph brought to the machine's normalization form.
+func (e *Enigma) setAlphabet(alph *alphabet.Alphabet) error {
+	normalized, err := normalizeAlphabet(alph, e.normalization)
+	if err != nil {
+		return err
+	}
+	e.alphabet = normalized
+	return nil
+}
+
+// normalizeAlphabet returns alph with every symbol brought to form n.
+func normalizeAlphabet(alph *alphabet.Alphabet, n Normalization) (*alphabet.Alphabet, error) {
+	if n == NormalizationNone {
+		return alph, nil
+	}
+	if alph.IsGrapheme() {
+		symbols := alph.Symbols()
+		for i, s := range symbols {
+			symbols[i] = n.apply(s)
+		}
+		normalized, err := alphabet.NewFromSymbols(symbols)
+		if err != nil {
+			return nil, fmt.Errorf("failed to normalize alphabet to %s: %v", strings.ToUpper(n.String()), err)
+		}
+		return normalized, nil
+	}
+	// Normalized as a whole, so that a letter listed with its combining mark
+	// composes in NFC and an accented letter brings its mark in NFD
+	normalized, err := alphabet.New([]rune(n.apply(string(alph.Runes()))))
+	if err != nil {
+		return nil, fmt.Errorf("failed to normalize alphabet to %s: %v", strings.ToUpper(n.String()), err)
+	}
+	return normalized, nil
+}
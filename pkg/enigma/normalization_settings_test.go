@@ -0,0 +1,74 @@
+//go:build !tinygo
+
+package enigma
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizationSettingsDefault(t *testing.T) {
+	// The default is left out, so existing settings files do not change
+	plain, err := New(WithAlphabet([]rune("ABCD")), WithRandomSettings(Low))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err := plain.SaveSettingsToJSON(); err != nil || strings.Contains(data, "normalization") {
+		t.Errorf("settings without normalization should not mention it, got %v:\n%s", err, data)
+	}
+}
+
+func TestNormalizationSettingsErrors(t *testing.T) {
+	machine, err := New(WithNormalization(NormalizationNFC), WithAlphabet([]rune("ABCD")), WithRandomSettings(Low))
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings, err := machine.GetSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A hand-edited alphabet in the other form
+	edited := *settings
+	edited.Alphabet = []rune("ABCE\u0301")
+	edited.RotorSpecs = nil
+	if _, err := NewFromSettings(&edited); err == nil || !strings.Contains(err.Error(), "not in NFC form") {
+		t.Errorf("an alphabet that is not normalized should be rejected, got %v", err)
+	}
+
+	v1 := *settings
+	v1.SchemaVersion = 1
+	if _, err := v1.MarshalProto(); err == nil {
+		t.Error("schema version 1 cannot record the normalization form")
+	}
+	if err := new(EnigmaSettings).UnmarshalJSON([]byte(`{"schema_version": 1, "alphabet": "AB", "normalization": "nfc"}`)); err == nil {
+		t.Error("normalization in schema version 1 settings should be rejected")
+	}
+}
+
+func TestNormalizationFingerprint(t *testing.T) {
+	machine, err := New(WithNormalization(NormalizationNFC), WithAlphabet([]rune("ABCD")), WithRandomSettings(Low))
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings, err := machine.GetSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings.Normalization = NormalizationNone
+	plain, err := NewFromSettings(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := machine.Fingerprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := plain.Fingerprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != b {
+		t.Error("the normalization form should not change the fingerprint")
+	}
+}
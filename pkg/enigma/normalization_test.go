@@ -0,0 +1,150 @@
+package enigma
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseNormalization(t *testing.T) {
+	for _, n := range []Normalization{NormalizationNone, NormalizationNFC, NormalizationNFD} {
+		parsed, err := ParseNormalization(strings.ToUpper(n.String()))
+		if err != nil || parsed != n {
+			t.Errorf("ParseNormalization(%q) = %v, %v; want %v", n.String(), parsed, err, n)
+		}
+	}
+	if n, err := ParseNormalization(""); err != nil || n != NormalizationNone {
+		t.Errorf("an empty name should be none, got %v, %v", n, err)
+	}
+	if _, err := ParseNormalization("nfkc"); err == nil {
+		t.Error("compatibility forms should not be accepted")
+	}
+}
+
+func TestNormalizationNFC(t *testing.T) {
+	// The alphabet lists the accent decomposed; as a rune alphabet it becomes U+00C9
+	runes := []rune("CAFE\u0301 D")
+	machine, err := New(WithNormalization(NormalizationNFC), WithAlphabet(runes), WithRandomSettings(Low))
+	if err != nil {
+		t.Fatal(err)
+	}
+	clone, err := machine.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ciphertext, err := machine.Encrypt("CAF\u00c9 CAFE\u0301")
+	if err != nil {
+		t.Fatalf("both forms of the accent should be accepted: %v", err)
+	}
+	plaintext, err := clone.Decrypt(ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "CAF\u00c9 CAF\u00c9"; plaintext != want {
+		t.Errorf("Decrypt = %+q, want %+q", plaintext, want)
+	}
+
+	// Without normalization the decomposed accent is not in the alphabet
+	plain, err := New(WithAlphabet([]rune("CAF\u00c9 D")), WithRandomSettings(Low))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := plain.Encrypt("CAFE\u0301"); err == nil {
+		t.Error("the combining accent should be rejected without normalization")
+	}
+}
+
+func TestNormalizationAlphabet(t *testing.T) {
+	// The option may come after the alphabet, as long as it precedes the components
+	machine, err := New(WithAlphabet([]rune("AB\u212bC")), WithNormalization(NormalizationNFC), WithRandomSettings(Low))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := machine.alphabet.Runes(), []rune("AB\u00c5C"); !slices.Equal(got, want) {
+		t.Errorf("alphabet = %+q, want %+q", string(got), string(want))
+	}
+	if got := machine.GetNormalization(); got != NormalizationNFC {
+		t.Errorf("normalization = %v, want nfc", got)
+	}
+
+	// In NFD an accented letter brings its combining mark
+	nfd, err := New(WithNormalization(NormalizationNFD), WithAlphabet([]rune("AB\u00e9")), WithRandomSettings(Low))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := nfd.alphabet.Runes(), []rune("ABe\u0301"); !slices.Equal(got, want) {
+		t.Errorf("alphabet = %+q, want %+q", string(got), string(want))
+	}
+	if _, err := nfd.Encrypt("\u00e9BA"); err != nil {
+		t.Errorf("the precomposed letter should be accepted: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr string
+	}{
+		{"after the components", []Option{WithAlphabet([]rune("AB")), WithRandomSettings(Low), WithNormalization(NormalizationNFC)}, "must be set before"},
+		{"marks that collide", []Option{WithNormalization(NormalizationNFD), WithAlphabet([]rune("e\u00e9"))}, "duplicate"},
+		{"forms that collide", []Option{WithNormalization(NormalizationNFC), WithAlphabet([]rune("\u00c5\u212b"))}, "duplicate"},
+		{"invalid", []Option{WithNormalization(Normalization(7))}, "invalid normalization"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.opts...); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNormalizationNFDGrapheme(t *testing.T) {
+	machine, err := New(
+		WithNormalization(NormalizationNFD),
+		WithGraphemeAlphabet([]string{"A", "B", "\u00e9", "n\u0303"}),
+		WithRandomSettings(Low),
+		WithRotorPositionSymbols([]string{"\u00e9", "A", "e\u0301"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := machine.GetSymbols(), []string{"A", "B", "e\u0301", "n\u0303"}; !slices.Equal(got, want) {
+		t.Errorf("symbols = %+q, want %+q", got, want)
+	}
+	clone, err := machine.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ciphertext, err := machine.Encrypt("\u00e9Ae\u0301\u00f1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext, err := clone.Decrypt(ciphertext)
+	if want := "e\u0301Ae\u0301n\u0303"; err != nil || plaintext != want {
+		t.Errorf("Decrypt = %+q, %v; want %+q", plaintext, err, want)
+	}
+}
+
+func TestNewFromTextNormalization(t *testing.T) {
+	const text = "Caf\u00e9 cafe\u0301"
+	machine, err := NewFromText(text, Low, WithTextNormalization(NormalizationNFC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	alphabet := string(machine.alphabet.Runes())
+	if strings.ContainsRune(alphabet, '\u0301') || !strings.ContainsRune(alphabet, '\u00e9') {
+		t.Errorf("the accent should be detected once, precomposed: %+q", alphabet)
+	}
+	if _, err := machine.Encrypt(text); err != nil {
+		t.Errorf("the sample text should encrypt: %v", err)
+	}
+
+	// WithDetectedAlphabet follows an earlier WithNormalization
+	detected, err := New(WithNormalization(NormalizationNFC), WithDetectedAlphabet(text), WithRandomSettings(Low))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(detected.alphabet.Runes()); got != alphabet {
+		t.Errorf("alphabet = %+q, want %+q", got, alphabet)
+	}
+}
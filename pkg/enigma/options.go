@@ -5,13 +5,13 @@
 package enigma
 
 import (
-	"crypto/rand"
 	"fmt"
-	"math/big"
 	mrand "math/rand"
+	"strconv"
 
 	"github.com/coredds/enigoma/internal/alphabet"
 	"github.com/coredds/enigoma/internal/plugboard"
+	"github.com/coredds/enigoma/internal/random"
 	"github.com/coredds/enigoma/internal/reflector"
 	"github.com/coredds/enigoma/internal/rotor"
 )
@@ -37,8 +37,7 @@ func WithAlphabet(runes []rune) Option {
 		if err != nil {
 			return fmt.Errorf("failed to create alphabet: %v", err)
 		}
-		e.alphabet = alph
-		return nil
+		return e.setAlphabet(alph)
 	}
 }
 
@@ -74,45 +73,91 @@ func WithCustomComponents(rotors []rotor.Rotor, refl reflector.Reflector, pb *pl
 }
 
 // WithRandomSettings configures the Enigma with random components based on a security level.
-// This is a convenience for quickly setting up a machine.
+// This is a convenience for quickly setting up a machine. Components are drawn
+// from crypto/rand, or from the reader of WithRandomSource.
 func WithRandomSettings(level SecurityLevel) Option {
+	return func(e *Enigma) error {
+		return withSettingsFrom(level, e.randomness())(e)
+	}
+}
+
+// WithDeterministicRandomSettings works like WithRandomSettings but derives
+// every component from seed: the rotor wirings, notches, positions and ring
+// settings, the reflector and the plugboard pairs. The same level, alphabet
+// and seed always produce the same machine, which makes keys reproducible in
+// tests and demonstrations. A 64-bit seed is easy to search, so a key made
+// this way is only as secret as its seed; NewFromSeed takes a longer one.
+func WithDeterministicRandomSettings(level SecurityLevel, seed int64) Option {
+	return func(e *Enigma) error {
+		src := random.NewDeterministic([]byte("deterministic-settings:" + strconv.FormatInt(seed, 10)))
+		return withSettingsFrom(level, src)(e)
+	}
+}
+
+// WithRandomComponents configures the Enigma with random components of an
+// exact shape: rotorCount rotors and plugboardPairs plugboard pairs. Use it
+// instead of WithRandomSettings when a security level's shape does not fit,
+// for example to rebuild a key over another alphabet with the same shape.
+func WithRandomComponents(rotorCount, plugboardPairs int) Option {
+	return func(e *Enigma) error {
+		if rotorCount < 1 {
+			return fmt.Errorf("rotor count must be at least 1, got %d", rotorCount)
+		}
+		if plugboardPairs < 0 {
+			return fmt.Errorf("plugboard pair count cannot be negative, got %d", plugboardPairs)
+		}
+		config := securityConfig{rotorCount: rotorCount, plugboardPairs: plugboardPairs}
+		return withComponentsFrom(config, e.randomness())(e)
+	}
+}
+
+// withSettingsFrom generates every component for the security level from src,
+// so a deterministic source always yields the same machine.
+func withSettingsFrom(level SecurityLevel, src random.Source) Option {
+	return withComponentsFrom(getSecurityConfig(level), src)
+}
+
+// withComponentsFrom generates rotors, reflector and plugboard of the shape
+// in config from src.
+func withComponentsFrom(config securityConfig, src random.Source) Option {
 	return func(e *Enigma) error {
 		if e.alphabet == nil {
 			return fmt.Errorf("alphabet must be set before applying random settings. Try: enigma.WithAlphabet(enigoma.AlphabetLatinUpper)")
 		}
 
-		config := getSecurityConfig(level)
-
 		// Generate random rotors
 		rotors := make([]rotor.Rotor, config.rotorCount)
 		for i := 0; i < config.rotorCount; i++ {
-			r, err := rotor.RandomRotor(fmt.Sprintf("R%d", i+1), e.alphabet)
+			r, err := rotor.RandomRotorFrom(fmt.Sprintf("R%d", i+1), e.alphabet, src)
 			if err != nil {
 				return fmt.Errorf("failed to generate random rotor %d: %v", i+1, err)
 			}
 
 			// Set random initial position
-			maxPos := big.NewInt(int64(e.alphabet.Size()))
-			posBig, err := rand.Int(rand.Reader, maxPos)
+			pos, err := src.Intn(e.alphabet.Size())
 			if err != nil {
 				return fmt.Errorf("failed to generate random position: %v", err)
 			}
-			r.SetPosition(int(posBig.Int64()))
+			r.SetPosition(pos)
 
 			// Set random ring setting
-			ringBig, err := rand.Int(rand.Reader, maxPos)
+			ring, err := src.Intn(e.alphabet.Size())
 			if err != nil {
 				return fmt.Errorf("failed to generate random ring setting: %v", err)
 			}
-			r.SetRingSetting(int(ringBig.Int64()))
+			r.SetRingSetting(ring)
 
 			rotors[i] = r
 		}
 
 		// Generate random reflector
-		refl, err := reflector.RandomReflector("UKW", e.alphabet)
-		if err != nil {
-			return fmt.Errorf("failed to generate random reflector: %v", err)
+		var refl reflector.Reflector
+		if !e.reflectorless {
+			var err error
+			refl, err = reflector.RandomReflectorWithFixedPointsFrom("UKW", e.alphabet, e.fixedPoints, src)
+			if err != nil {
+				return fmt.Errorf("failed to generate random reflector: %v", err)
+			}
 		}
 
 		// Generate random plugboard
@@ -121,15 +166,17 @@ func WithRandomSettings(level SecurityLevel) Option {
 			return fmt.Errorf("failed to create plugboard: %v", err)
 		}
 
-		if config.plugboardPairs > 0 {
+		if config.plugboardPairs > 0 && !e.plugboardDisabled {
 			// Cap plugboard pairs at the maximum possible for this alphabet
 			maxPairs := e.alphabet.Size() / 2
 			actualPairs := config.plugboardPairs
 			if actualPairs > maxPairs {
+				e.warn(WarnPlugboardCapped, "alphabet of %d characters fits only %d plugboard pairs, not %d",
+					e.alphabet.Size(), maxPairs, config.plugboardPairs)
 				actualPairs = maxPairs
 			}
 
-			err = pb.RandomPairs(actualPairs)
+			err = pb.RandomPairsFrom(actualPairs, src)
 			if err != nil {
 				return fmt.Errorf("failed to generate random plugboard pairs: %v", err)
 			}
@@ -185,6 +232,79 @@ func WithReflectorConfiguration(reflectorSpec reflector.ReflectorSpec) Option {
 	}
 }
 
+// WithReflectorPairs wires the reflector from explicit character pairs, such as
+// {'A': 'Y', 'B': 'R', ...}. Listing one direction of each pair is enough; the
+// pairs must cover the whole alphabet and may not pair a character with itself.
+// Apply it after WithRandomSettings, which would otherwise replace the reflector.
+func WithReflectorPairs(pairs map[rune]rune) Option {
+	return func(e *Enigma) error {
+		if e.alphabet == nil {
+			return fmt.Errorf("alphabet must be set before configuring reflector. Try: enigma.WithAlphabet(enigoma.AlphabetLatinUpper)")
+		}
+		if e.reflectorless {
+			return fmt.Errorf("reflector pairs cannot be used with WithoutReflector")
+		}
+
+		refl, err := reflector.NewReflectorFromPairs("CUSTOM", e.alphabet, pairs)
+		if err != nil {
+			return fmt.Errorf("invalid reflector pairs: %v", err)
+		}
+
+		e.reflector = refl
+		return nil
+	}
+}
+
+// WithRewirableReflector installs a reflector wired from pairs that can be
+// rewired later with RewireReflector, like the historical UKW-D. The pairs
+// follow the rules of WithReflectorPairs. Apply it after WithRandomSettings,
+// which would otherwise replace the reflector.
+func WithRewirableReflector(pairs map[rune]rune) Option {
+	return func(e *Enigma) error {
+		if e.alphabet == nil {
+			return fmt.Errorf("alphabet must be set before configuring reflector. Try: enigma.WithAlphabet(enigoma.AlphabetLatinUpper)")
+		}
+		if e.reflectorless {
+			return fmt.Errorf("a rewirable reflector cannot be used with WithoutReflector")
+		}
+
+		refl, err := reflector.NewRewirableReflector("UKW-D", e.alphabet, pairs)
+		if err != nil {
+			return fmt.Errorf("invalid reflector pairs: %v", err)
+		}
+
+		e.reflector = refl
+		return nil
+	}
+}
+
+// WithoutReflector enables an experimental, non-historical mode in which the
+// signal passes through the rotors only once: right to left when encrypting
+// and back again when decrypting. Letters can then encrypt to themselves,
+// removing the classic Enigma weakness, but the machine is no longer
+// reciprocal, so ciphertext must be processed with Decrypt rather than
+// Encrypt. Any configured reflector is discarded. Apply it before
+// WithRandomSettings to use alphabets of odd size.
+func WithoutReflector() Option {
+	return func(e *Enigma) error {
+		e.reflectorless = true
+		e.reflector = nil
+		return nil
+	}
+}
+
+// WithoutPlugboard removes the plugboard stage: the signal goes from the
+// keyboard straight to the entry wheel, as on the Enigma G and K, which had
+// no plugboard. Unlike a plugboard with no pairs, the stage is skipped
+// entirely, and the machine cannot be given plugboard pairs or an Uhr. Apply
+// it before WithRandomSettings, which then generates no pairs.
+func WithoutPlugboard() Option {
+	return func(e *Enigma) error {
+		e.plugboardDisabled = true
+		return nil
+	}
+}
+
 // WithPlugboardConfiguration sets specific plugboard pairs.
 func WithPlugboardConfiguration(pairs map[rune]rune) Option {
 	return func(e *Enigma) error {
@@ -209,6 +329,22 @@ func WithPlugboardConfiguration(pairs map[rune]rune) Option {
 	}
 }
 
+// WithPlugboardConfigurationAndRandom sets the given plugboard pairs and adds
+// additionalRandom random pairs between the characters left unplugged, so
+// that some pairs can be pinned (for example A-Z in a demonstration) while the
+// rest are random.
+func WithPlugboardConfigurationAndRandom(fixed map[rune]rune, additionalRandom int) Option {
+	return func(e *Enigma) error {
+		if err := WithPlugboardConfiguration(fixed)(e); err != nil {
+			return err
+		}
+		if err := e.plugboard.AddRandomPairsFrom(additionalRandom, e.randomness()); err != nil {
+			return fmt.Errorf("failed to add random plugboard pairs: %v", err)
+		}
+		return nil
+	}
+}
+
 // WithRandomRotorPositions sets random initial positions for all rotors.
 func WithRandomRotorPositions() Option {
 	return func(e *Enigma) error {
@@ -216,13 +352,12 @@ func WithRandomRotorPositions() Option {
 			return fmt.Errorf("alphabet must be set before setting random positions")
 		}
 
-		maxPos := big.NewInt(int64(e.alphabet.Size()))
 		for _, r := range e.rotors {
-			posBig, err := rand.Int(rand.Reader, maxPos)
+			pos, err := e.randomness().Intn(e.alphabet.Size())
 			if err != nil {
 				return fmt.Errorf("failed to generate random position: %v", err)
 			}
-			r.SetPosition(int(posBig.Int64()))
+			r.SetPosition(pos)
 		}
 
 		return nil
@@ -262,6 +397,23 @@ func WithRotorPositions(positions []int) Option {
 	}
 }
 
+// WithRotorPositionRunes sets the initial rotor positions from the characters
+// shown in the rotor windows, e.g. []rune("ADU"); see
+// SetRotorPositionsFromRunes.
+func WithRotorPositionRunes(positions []rune) Option {
+	return func(e *Enigma) error {
+		return e.SetRotorPositionsFromRunes(positions)
+	}
+}
+
+// WithRingSettings sets the zero-based ring settings of all rotors; see
+// SetRingSettings. Apply it after the rotors are configured.
+func WithRingSettings(rings []int) Option {
+	return func(e *Enigma) error {
+		return e.SetRingSettings(rings)
+	}
+}
+
 // securityConfig holds configuration parameters for different security levels.
 type securityConfig struct {
 	rotorCount     int
@@ -1,6 +1,7 @@
 package enigma
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/coredds/enigoma/internal/alphabet"
@@ -87,6 +88,28 @@ func TestWithRandomSettings_NoAlphabet(t *testing.T) {
 	}
 }
 
+func TestWithRandomComponents(t *testing.T) {
+	alph, _ := alphabet.New([]rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ"))
+
+	enigma := &Enigma{alphabet: alph}
+	if err := WithRandomComponents(4, 6)(enigma); err != nil {
+		t.Fatalf("WithRandomComponents() error: %v", err)
+	}
+	if got := enigma.GetRotorCount(); got != 4 {
+		t.Errorf("rotor count = %d, want 4", got)
+	}
+	if got := enigma.GetPlugboardPairCount(); got != 6 {
+		t.Errorf("plugboard pairs = %d, want 6", got)
+	}
+
+	if err := WithRandomComponents(0, 2)(&Enigma{alphabet: alph}); err == nil {
+		t.Errorf("WithRandomComponents() with no rotors should fail")
+	}
+	if err := WithRandomComponents(3, -1)(&Enigma{alphabet: alph}); err == nil {
+		t.Errorf("WithRandomComponents() with negative pairs should fail")
+	}
+}
+
 func TestWithRotorPositions(t *testing.T) {
 	alph, _ := alphabet.New([]rune{'A', 'B', 'C', 'D'})
 
@@ -131,6 +154,61 @@ func TestWithRotorPositions_WrongCount(t *testing.T) {
 	}
 }
 
+func TestRotorPositionRunes(t *testing.T) {
+	machine, err := New(
+		WithAlphabet([]rune("ABCDEF")),
+		WithRandomSettings(Low),
+		WithRotorPositionRunes([]rune("FAC")),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if got := machine.GetCurrentRotorPositions(); !reflect.DeepEqual(got, []int{5, 0, 2}) {
+		t.Errorf("positions = %v, want [5 0 2]", got)
+	}
+	if got := string(machine.GetRotorPositionsAsRunes()); got != "FAC" {
+		t.Errorf("GetRotorPositionsAsRunes() = %q, want FAC", got)
+	}
+
+	if _, err := machine.Encrypt("ABC"); err != nil {
+		t.Fatal(err)
+	}
+	if err := machine.SetRotorPositionsFromRunes([]rune("BED")); err != nil {
+		t.Fatalf("SetRotorPositionsFromRunes failed: %v", err)
+	}
+	if got := string(machine.GetRotorPositionsAsRunes()); got != "BED" {
+		t.Errorf("GetRotorPositionsAsRunes() = %q, want BED", got)
+	}
+
+	if err := machine.SetRotorPositionsFromRunes([]rune("AB")); err == nil {
+		t.Error("expected an error for too few positions")
+	}
+	if err := machine.SetRotorPositionsFromRunes([]rune("ABZ")); err == nil {
+		t.Error("expected an error for a character outside the alphabet")
+	}
+}
+
+func TestWithRingSettings(t *testing.T) {
+	machine, err := New(
+		WithAlphabet([]rune("ABCDEF")),
+		WithRandomSettings(Low),
+		WithRingSettings([]int{0, 5, 3}),
+	)
+	if err != nil {
+		t.Fatalf("New() with WithRingSettings error: %v", err)
+	}
+	if got := machine.GetRingSettings(); !reflect.DeepEqual(got, []int{0, 5, 3}) {
+		t.Errorf("GetRingSettings() = %v, want [0 5 3]", got)
+	}
+
+	if err := machine.SetRingSettings([]int{0, 1}); err == nil {
+		t.Error("SetRingSettings() should reject the wrong count")
+	}
+	if err := machine.SetRingSettings([]int{0, 6, 1}); err == nil {
+		t.Error("SetRingSettings() should reject a ring setting outside the alphabet")
+	}
+}
+
 func TestWithRandomRotorPositions(t *testing.T) {
 	alph, _ := alphabet.New([]rune{'A', 'B', 'C', 'D'})
 
@@ -207,6 +285,113 @@ func TestWithPlugboardConfiguration_NonReciprocal(t *testing.T) {
 	}
 }
 
+func TestWithPlugboardConfigurationAndRandom(t *testing.T) {
+	alph, _ := alphabet.New([]rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ"))
+
+	enigma := &Enigma{alphabet: alph}
+
+	fixed := map[rune]rune{'A': 'Z', 'Z': 'A'}
+	if err := WithPlugboardConfigurationAndRandom(fixed, 9)(enigma); err != nil {
+		t.Fatalf("WithPlugboardConfigurationAndRandom() error: %v", err)
+	}
+	if enigma.plugboard.PairCount() != 10 {
+		t.Errorf("Plugboard pair count = %d, want 10", enigma.plugboard.PairCount())
+	}
+	if out, _ := enigma.plugboard.ProcessRune('A'); out != 'Z' {
+		t.Errorf("fixed pair A-Z lost: A -> %c", out)
+	}
+
+	// 24 characters are left for at most 12 more pairs
+	if err := WithPlugboardConfigurationAndRandom(fixed, 13)(enigma); err == nil {
+		t.Error("expected an error when the random pairs do not fit")
+	}
+	if err := WithPlugboardConfigurationAndRandom(map[rune]rune{'A': 'B'}, 1)(enigma); err == nil {
+		t.Error("expected an error for non-reciprocal fixed pairs")
+	}
+}
+
+func TestWithReflectorPairs(t *testing.T) {
+	machine, err := New(
+		WithAlphabet([]rune("ABCD")),
+		WithRandomSettings(Low),
+		WithReflectorPairs(map[rune]rune{'A': 'D', 'B': 'C'}),
+	)
+	if err != nil {
+		t.Fatalf("New() with reflector pairs error: %v", err)
+	}
+
+	settings, err := machine.GetSettings()
+	if err != nil {
+		t.Fatalf("GetSettings() error: %v", err)
+	}
+	if settings.ReflectorSpec.Mapping != "DCBA" {
+		t.Errorf("Reflector mapping = %q, want %q", settings.ReflectorSpec.Mapping, "DCBA")
+	}
+}
+
+func TestWithReflectorPairs_Invalid(t *testing.T) {
+	alph, _ := alphabet.New([]rune{'A', 'B', 'C', 'D'})
+
+	// Incomplete wiring
+	if err := WithReflectorPairs(map[rune]rune{'A': 'D'})(&Enigma{alphabet: alph}); err == nil {
+		t.Errorf("WithReflectorPairs() with unpaired characters should fail")
+	}
+
+	// No alphabet
+	if err := WithReflectorPairs(map[rune]rune{'A': 'D', 'B': 'C'})(&Enigma{}); err == nil {
+		t.Errorf("WithReflectorPairs() without alphabet should fail")
+	}
+
+	// Reflector-less machines have nothing to wire
+	if err := WithReflectorPairs(map[rune]rune{'A': 'D', 'B': 'C'})(&Enigma{alphabet: alph, reflectorless: true}); err == nil {
+		t.Errorf("WithReflectorPairs() on a reflector-less machine should fail")
+	}
+}
+
+func TestWithRewirableReflector(t *testing.T) {
+	machine, err := New(
+		WithAlphabet([]rune("ABCD")),
+		WithRandomSettings(Low),
+		WithRewirableReflector(map[rune]rune{'A': 'D', 'B': 'C'}),
+	)
+	if err != nil {
+		t.Fatalf("New() with rewirable reflector error: %v", err)
+	}
+	positions := machine.GetCurrentRotorPositions()
+
+	if err := machine.RewireReflector(map[rune]rune{'A': 'B', 'C': 'D'}); err != nil {
+		t.Fatalf("RewireReflector() error: %v", err)
+	}
+	settings, _ := machine.GetSettings()
+	if settings.ReflectorSpec.Mapping != "BADC" || !settings.ReflectorSpec.Rewirable {
+		t.Errorf("reflector spec = %+v, want rewirable mapping BADC", settings.ReflectorSpec)
+	}
+	if got := machine.GetCurrentRotorPositions(); !reflect.DeepEqual(got, positions) {
+		t.Errorf("rewiring moved the rotors: %v -> %v", positions, got)
+	}
+
+	// Encryption stays reciprocal with the new wiring
+	encrypted, _ := machine.Encrypt("ABCDDCBA")
+	machine.Reset()
+	if decrypted, _ := machine.Decrypt(encrypted); decrypted != "ABCDDCBA" {
+		t.Errorf("round trip after rewiring = %q", decrypted)
+	}
+
+	if err := machine.RewireReflector(map[rune]rune{'A': 'A'}); err == nil {
+		t.Error("RewireReflector() with invalid pairs should fail")
+	}
+}
+
+func TestRewireFixedReflector(t *testing.T) {
+	machine, err := New(WithAlphabet([]rune("ABCD")), WithRandomSettings(Low))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := machine.RewireReflector(map[rune]rune{'A': 'B', 'C': 'D'}); err == nil {
+		t.Error("RewireReflector() on a fixed reflector should fail")
+	}
+}
+
 func TestGetSecurityConfig(t *testing.T) {
 	tests := []struct {
 		level             SecurityLevel
@@ -274,6 +459,48 @@ func TestSecurityLevels_Integration(t *testing.T) {
 	}
 }
 
+func TestWithDeterministicRandomSettings(t *testing.T) {
+	build := func(seed int64) *EnigmaSettings {
+		t.Helper()
+		machine, err := New(WithAlphabet([]rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")), WithDeterministicRandomSettings(High, seed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		settings, err := machine.GetSettings()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return settings
+	}
+
+	a, b := build(42), build(42)
+	if !reflect.DeepEqual(a, b) {
+		t.Error("the same seed should derive the same wiring, notches, reflector and plugboard")
+	}
+	if c := build(43); reflect.DeepEqual(a.RotorSpecs, c.RotorSpecs) || a.ReflectorSpec.Mapping == c.ReflectorSpec.Mapping {
+		t.Error("different seeds should derive different machines")
+	}
+	if want := getSecurityConfig(High).rotorCount; len(a.RotorSpecs) != want {
+		t.Errorf("seeded machine has %d rotors, want the %d of High", len(a.RotorSpecs), want)
+	}
+
+	// The option draws afresh each time it is applied
+	opt := WithDeterministicRandomSettings(Low, 7)
+	first, err := New(WithAlphabet([]rune("ABCDEF")), opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := New(WithAlphabet([]rune("ABCDEF")), opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s1, _ := first.GetSettings()
+	s2, _ := second.GetSettings()
+	if !reflect.DeepEqual(s1, s2) {
+		t.Error("reusing the option should rebuild the same machine")
+	}
+}
+
 // String method for SecurityLevel for better test output
 func (s SecurityLevel) String() string {
 	switch s {
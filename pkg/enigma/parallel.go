@@ -0,0 +1,89 @@
+// Package enigma provides chunked encryption on several cores.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// minParallelChunk is the fewest characters worth handing to a worker;
+// below it, copying the machine costs more than the worker saves.
+const minParallelChunk = 16 << 10
+
+// EncryptParallel encrypts text like Encrypt, splitting it into up to
+// workers chunks that are encrypted at the same time on copies of the
+// machine; workers of 0 or less uses GOMAXPROCS. Rotor stepping does not
+// depend on the text, so the state at each chunk boundary is found by
+// stepping alone, and both the output and the state the machine is left in
+// are exactly those of Encrypt. Short texts, and machines with a step or
+// rotor event callback, which must see characters in order, are encrypted
+// sequentially.
+func (e *Enigma) EncryptParallel(text string, workers int) (string, error) {
+	return e.transformText(text, func(indices []int) error {
+		return e.processParallel(indices, true, workers)
+	})
+}
+
+// DecryptParallel decrypts text like Decrypt, in chunks on several cores as
+// EncryptParallel does.
+func (e *Enigma) DecryptParallel(text string, workers int) (string, error) {
+	return e.transformText(text, func(indices []int) error {
+		return e.processParallel(indices, false, workers)
+	})
+}
+
+// processParallel is processIndices split across workers.
+func (e *Enigma) processParallel(indices []int, encrypt bool, workers int) error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	chunks := min(workers, len(indices)/minParallelChunk)
+	if chunks <= 1 || e.onStep != nil || e.onRotorEvent != nil {
+		e.processIndices(indices, encrypt, nil)
+		return nil
+	}
+
+	// Step a copy through the whole text, noting the state where each
+	// chunk starts; it ends where the machine would
+	probe, err := e.Clone()
+	if err != nil {
+		return fmt.Errorf("failed to copy the machine: %v", err)
+	}
+	size := (len(indices) + chunks - 1) / chunks
+	starts := make([]MachineState, 0, chunks)
+	for start := 0; start < len(indices); start += size {
+		starts = append(starts, probe.SaveState())
+		for i := start; i < min(start+size, len(indices)); i++ {
+			probe.stepRotors(i)
+		}
+	}
+
+	machines := make([]*Enigma, len(starts))
+	for k, state := range starts {
+		worker, err := e.Clone()
+		if err != nil {
+			return fmt.Errorf("failed to copy the machine: %v", err)
+		}
+		if err := worker.RestoreState(state); err != nil {
+			return err
+		}
+		machines[k] = worker
+	}
+
+	// Each chunk writes its own part of indices
+	var wg sync.WaitGroup
+	for k, worker := range machines {
+		chunk := indices[k*size : min((k+1)*size, len(indices))]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker.processIndices(chunk, encrypt, nil)
+		}()
+	}
+	wg.Wait()
+	return e.RestoreState(probe.SaveState())
+}
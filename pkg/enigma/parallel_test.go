@@ -0,0 +1,123 @@
+package enigma
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEncryptParallelMatchesEncrypt(t *testing.T) {
+	machines := map[string]func() (*Enigma, error){
+		"m4": NewEnigmaM4,
+		"g":  NewEnigmaG,
+		"extreme": func() (*Enigma, error) {
+			return New(WithAlphabet([]rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")), WithRandomSettings(Extreme))
+		},
+		"reflectorless": func() (*Enigma, error) {
+			return New(WithAlphabet([]rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")), WithoutReflector(), WithRandomSettings(High))
+		},
+		"passthrough": func() (*Enigma, error) {
+			return New(WithAlphabet([]rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")), WithRandomSettings(Medium), WithUnknownCharPolicy(UnknownCharPass))
+		},
+	}
+	text := strings.Repeat("THE QUICK BROWN FOX JUMPS OVER THE LAZY DOG ", 3*minParallelChunk/44)
+
+	for name, build := range machines {
+		t.Run(name, func(t *testing.T) {
+			sequential, err := build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := sequential.Encrypt("WARMUP"); err != nil {
+				t.Fatal(err)
+			}
+			parallel, err := sequential.Clone()
+			if err != nil {
+				t.Fatal(err)
+			}
+			input := text
+			if sequential.unknownChars == UnknownCharError {
+				input = strings.ReplaceAll(text, " ", "")
+			}
+
+			want, err := sequential.Encrypt(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := parallel.EncryptParallel(input, 4)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Fatal("EncryptParallel output differs from Encrypt")
+			}
+			if got, want := parallel.SaveState(), sequential.SaveState(); !reflect.DeepEqual(got, want) {
+				t.Errorf("state after EncryptParallel = %+v, want %+v", got, want)
+			}
+
+			// Decryption from the same starting point restores the text
+			if err := parallel.Reset(); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := parallel.Encrypt("WARMUP"); err != nil {
+				t.Fatal(err)
+			}
+			plain, err := parallel.DecryptParallel(want, 3)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if plain != input {
+				t.Error("DecryptParallel did not restore the plaintext")
+			}
+		})
+	}
+}
+
+func TestEncryptParallelSequentialFallback(t *testing.T) {
+	machine, err := NewEnigmaClassic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reference, _ := machine.Clone()
+
+	// Short texts and zero workers
+	got, err := machine.EncryptParallel("HELLOWORLD", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, _ := reference.Encrypt("HELLOWORLD"); got != want {
+		t.Errorf("EncryptParallel = %q, want %q", got, want)
+	}
+
+	// Step callbacks see every character in order
+	count := 0
+	machine.SetStepCallback(func(info StepInfo) {
+		if info.Index != count {
+			t.Fatalf("step %d reported as %d", count, info.Index)
+		}
+		count++
+	})
+	text := strings.Repeat("A", 2*minParallelChunk)
+	if _, err := machine.EncryptParallel(text, 4); err != nil {
+		t.Fatal(err)
+	}
+	if count != len(text) {
+		t.Errorf("step callback saw %d characters, want %d", count, len(text))
+	}
+}
+
+func BenchmarkEncryptParallel(b *testing.B) {
+	machine, err := New(WithAlphabet([]rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")), WithRandomSettings(High))
+	if err != nil {
+		b.Fatal(err)
+	}
+	text := strings.Repeat("ABCDEFGHIJKLMNOPQRSTUVWXYZ", 40000)
+
+	b.SetBytes(int64(len(text)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := machine.EncryptParallel(text, 0); err != nil {
+			b.Fatalf("EncryptParallel failed: %v", err)
+		}
+	}
+}
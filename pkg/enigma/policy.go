@@ -0,0 +1,55 @@
+// Package enigma provides input policies that preprocess text before it is
+// validated against the alphabet.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import "fmt"
+
+// InputPolicy transforms or rejects one input character. It returns the
+// character to process in its place, a negative rune to drop it (as with
+// strings.Map), or an error to reject the whole input.
+type InputPolicy func(r rune) (rune, error)
+
+// WithInputPolicy adds a policy applied to every character passed to Encrypt
+// or Decrypt, before alphabet validation. Use it for preprocessing every
+// caller would otherwise repeat, such as mapping tabs to spaces or rejecting
+// digits. Policies run in the order they were added, each on the output of
+// the previous one.
+//
+// Policies apply to ciphertext too, so they should leave characters of the
+// alphabet unchanged unless both sides of the exchange use the same ones.
+func WithInputPolicy(policy InputPolicy) Option {
+	return func(e *Enigma) error {
+		if policy == nil {
+			return fmt.Errorf("input policy cannot be nil")
+		}
+		e.inputPolicies = append(e.inputPolicies, policy)
+		return nil
+	}
+}
+
+// applyInputPolicies runs the machine's policies over text.
+func (e *Enigma) applyInputPolicies(text string) (string, error) {
+	out := make([]rune, 0, len(text))
+	index := 0
+	for _, r := range text {
+		c := r
+		for _, policy := range e.inputPolicies {
+			mapped, err := policy(c)
+			if err != nil {
+				return "", fmt.Errorf("input policy rejected character %q at index %d: %v", r, index, err)
+			}
+			c = mapped
+			if c < 0 {
+				break
+			}
+		}
+		if c >= 0 {
+			out = append(out, c)
+		}
+		index++
+	}
+	return string(out), nil
+}
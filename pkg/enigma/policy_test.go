@@ -0,0 +1,94 @@
+package enigma
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestInputPolicyTransformsAndDrops(t *testing.T) {
+	tabsToSpaces := func(r rune) (rune, error) {
+		if r == '\t' {
+			return ' ', nil
+		}
+		return r, nil
+	}
+	dropNewlines := func(r rune) (rune, error) {
+		if r == '\n' {
+			return -1, nil
+		}
+		return r, nil
+	}
+
+	machine, err := New(
+		WithAlphabet([]rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ .")),
+		WithRandomSettings(Low),
+		WithInputPolicy(tabsToSpaces),
+		WithInputPolicy(dropNewlines),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	plain, err := machine.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain.inputPolicies = nil
+
+	got, err := machine.Encrypt("HELLO\tWORLD\n")
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	want, err := plain.Encrypt("HELLO WORLD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("Encrypt with policies = %q, want %q", got, want)
+	}
+
+	if out, err := machine.Encrypt("\n\n"); err != nil || out != "" {
+		t.Errorf("fully dropped input = %q, %v; want empty", out, err)
+	}
+}
+
+func TestInputPolicyRejects(t *testing.T) {
+	rejectDigits := func(r rune) (rune, error) {
+		if unicode.IsDigit(r) {
+			return 0, fmt.Errorf("digits are not allowed")
+		}
+		return r, nil
+	}
+	machine, err := New(
+		WithAlphabet([]rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789")),
+		WithRandomSettings(Low),
+		WithInputPolicy(rejectDigits),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	before := machine.GetCurrentRotorPositions()
+	_, err = machine.Encrypt("AB7C")
+	if err == nil || !strings.Contains(err.Error(), "index 2") || !strings.Contains(err.Error(), "digits are not allowed") {
+		t.Errorf("expected a rejection at index 2, got %v", err)
+	}
+	if after := machine.GetCurrentRotorPositions(); fmt.Sprint(after) != fmt.Sprint(before) {
+		t.Errorf("rejected input moved the rotors: %v -> %v", before, after)
+	}
+
+	clone, err := machine.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := clone.Decrypt("9"); err == nil {
+		t.Error("clone should keep the policy and reject digits in Decrypt")
+	}
+}
+
+func TestWithInputPolicyNil(t *testing.T) {
+	if _, err := New(WithAlphabet([]rune("ABCD")), WithRandomSettings(Low), WithInputPolicy(nil)); err == nil {
+		t.Error("expected an error for a nil policy")
+	}
+}
@@ -0,0 +1,118 @@
+// Package enigma provides the preset registry, named machine factories shared by all tooling.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// PresetFactory builds a new machine for a preset. Factories of presets with
+// random settings return a different machine on each call.
+type PresetFactory func() (*Enigma, error)
+
+var (
+	presetsMu sync.RWMutex
+	presets   = builtinPresets()
+)
+
+// builtinPresets returns the presets every program has: the historical
+// machines, classic and simple, and random machines at each security level.
+func builtinPresets() map[string]PresetFactory {
+	latin := []rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")
+	random := func(level SecurityLevel) PresetFactory {
+		return func() (*Enigma, error) {
+			return New(WithAlphabet(latin), WithRandomSettings(level))
+		}
+	}
+	return map[string]PresetFactory{
+		"classic": NewEnigmaClassic,
+		"m3":      NewEnigmaM3,
+		"m4":      NewEnigmaM4,
+		"g":       NewEnigmaG,
+		"k":       NewEnigmaK,
+		"swiss-k": NewEnigmaSwissK,
+		"railway": NewEnigmaRailway,
+		"norway":  NewEnigmaNorway,
+		"tirpitz": NewEnigmaTirpitz,
+		"simple":  func() (*Enigma, error) { return NewEnigmaSimple(latin) },
+		"low":     random(Low),
+		"medium":  random(Medium),
+		"high":    random(High),
+		"extreme": random(Extreme),
+	}
+}
+
+// RegisterPreset makes factory available as the preset name, to NewFromPreset
+// and to tools such as the enigoma CLI (--preset name) built into the same
+// program. Names are case-insensitive and cannot be registered twice, so a
+// program cannot replace a built-in preset. Register presets from an init
+// function, before any tooling looks them up:
+//
+//	func init() {
+//		if err := enigma.RegisterPreset("ops-drill", newDrillMachine); err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+func RegisterPreset(name string, factory func() (*Enigma, error)) error {
+	key := presetKey(name)
+	if key == "" {
+		return fmt.Errorf("preset name cannot be empty")
+	}
+	if strings.ContainsAny(key, " \t\r\n") {
+		return fmt.Errorf("preset name %q cannot contain whitespace", name)
+	}
+	if factory == nil {
+		return fmt.Errorf("preset %s has no factory", name)
+	}
+
+	presetsMu.Lock()
+	defer presetsMu.Unlock()
+	if _, ok := presets[key]; ok {
+		return fmt.Errorf("preset %s is already registered", key)
+	}
+	presets[key] = factory
+	return nil
+}
+
+// LookupPreset returns the factory registered as name.
+func LookupPreset(name string) (PresetFactory, bool) {
+	presetsMu.RLock()
+	defer presetsMu.RUnlock()
+	factory, ok := presets[presetKey(name)]
+	return factory, ok
+}
+
+// NewFromPreset builds a machine from the preset registered as name.
+func NewFromPreset(name string) (*Enigma, error) {
+	factory, ok := LookupPreset(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown preset: %s. Available: %s", name, strings.Join(Presets(), ", "))
+	}
+	machine, err := factory()
+	if err != nil {
+		return nil, fmt.Errorf("preset %s: %v", presetKey(name), err)
+	}
+	return machine, nil
+}
+
+// Presets returns the names of all registered presets, sorted.
+func Presets() []string {
+	presetsMu.RLock()
+	defer presetsMu.RUnlock()
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// presetKey normalizes a preset name for the registry.
+func presetKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
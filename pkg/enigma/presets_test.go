@@ -0,0 +1,75 @@
+package enigma
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBuiltinPresets(t *testing.T) {
+	for _, name := range Presets() {
+		machine, err := NewFromPreset(name)
+		if err != nil {
+			t.Errorf("preset %s: %v", name, err)
+			continue
+		}
+		if machine.GetRotorCount() == 0 {
+			t.Errorf("preset %s built a machine without rotors", name)
+		}
+	}
+
+	machine, err := NewFromPreset(" M3 ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := machine.Encrypt("AAAAA"); err != nil || got != "BDZGO" {
+		t.Errorf("preset m3 encrypted AAAAA as %q (%v), want BDZGO", got, err)
+	}
+	if _, err := NewFromPreset("enigma-z"); err == nil {
+		t.Error("expected an error for an unknown preset")
+	}
+}
+
+func TestRegisterPreset(t *testing.T) {
+	factory := func() (*Enigma, error) {
+		return New(
+			WithAlphabet([]rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")),
+			WithRotorsByName("V", "IV", "III"),
+			WithReflectorConfiguration(reflectorB()),
+		)
+	}
+	if err := RegisterPreset("Test-Drill", factory); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		presetsMu.Lock()
+		delete(presets, "test-drill")
+		presetsMu.Unlock()
+	})
+
+	if !slices.Contains(Presets(), "test-drill") {
+		t.Errorf("Presets() = %v, want test-drill listed", Presets())
+	}
+	machine, err := NewFromPreset("TEST-DRILL")
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings, err := machine.GetSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.RotorSpecs[0].ID != "V" {
+		t.Errorf("registered preset built rotors %+v", settings.RotorSpecs)
+	}
+
+	for name, f := range map[string]func() (*Enigma, error){
+		"":           factory,
+		"two words":  factory,
+		"no-factory": nil,
+		"test-drill": factory,
+		"M3":         factory,
+	} {
+		if err := RegisterPreset(name, f); err == nil {
+			t.Errorf("RegisterPreset(%q) should fail", name)
+		}
+	}
+}
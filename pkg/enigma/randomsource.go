@@ -0,0 +1,38 @@
+// Package enigma provides a pluggable source of randomness for generated components.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/coredds/enigoma/internal/random"
+)
+
+// WithRandomSource makes the machine draw its random components from r
+// instead of crypto/rand: the rotors, reflector and plugboard of
+// WithRandomSettings and WithRandomComponents, the positions of
+// WithRandomRotorPositions, the pairs of WithPlugboardConfigurationAndRandom
+// and the message keys of EncryptMessage. Supply a reader backed by a hardware
+// security module, or a fixed stream to make tests reproducible. Apply it
+// before the options that draw from it. The reader must yield uniform bytes;
+// a read error fails the option that hit it.
+func WithRandomSource(r io.Reader) Option {
+	return func(e *Enigma) error {
+		if r == nil {
+			return fmt.Errorf("random source cannot be nil")
+		}
+		e.randomSource = random.FromReader(r)
+		return nil
+	}
+}
+
+// randomness returns the source random components are drawn from.
+func (e *Enigma) randomness() random.Source {
+	if e.randomSource == nil {
+		return random.Crypto
+	}
+	return e.randomSource
+}
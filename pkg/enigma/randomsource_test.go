@@ -0,0 +1,67 @@
+package enigma
+
+import (
+	"errors"
+	mrand "math/rand"
+	"strings"
+	"testing"
+)
+
+// newSeededMachine builds a machine whose components come from a seeded stream.
+func newSeededMachine(t *testing.T, seed int64) *Enigma {
+	t.Helper()
+	machine, err := New(
+		WithAlphabet([]rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")),
+		WithRandomSource(mrand.New(mrand.NewSource(seed))), // #nosec G404 - reproducible test stream
+		WithRandomSettings(High),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return machine
+}
+
+func TestWithRandomSourceIsReproducible(t *testing.T) {
+	a, b, c := newSeededMachine(t, 42), newSeededMachine(t, 42), newSeededMachine(t, 7)
+
+	const text = "THEQUICKBROWNFOXJUMPSOVERTHELAZYDOG"
+	ca, err := a.Encrypt(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cb, _ := b.Encrypt(text)
+	cc, _ := c.Encrypt(text)
+	if ca != cb {
+		t.Errorf("the same stream should build the same machine: %q vs %q", ca, cb)
+	}
+	if ca == cc {
+		t.Error("different streams should build different machines")
+	}
+
+	// Message keys come from the same stream
+	ma, err := a.EncryptMessage(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mb, err := b.EncryptMessage(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ma.String() != mb.String() {
+		t.Errorf("message keys should follow the stream:\n%s\n%s", ma, mb)
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errors.New("device unplugged") }
+
+func TestWithRandomSourceErrors(t *testing.T) {
+	if _, err := New(WithAlphabet([]rune("ABCD")), WithRandomSource(nil), WithRandomSettings(Low)); err == nil {
+		t.Error("a nil random source should be rejected")
+	}
+	_, err := New(WithAlphabet([]rune("ABCD")), WithRandomSource(failingReader{}), WithRandomSettings(Low))
+	if err == nil || !strings.Contains(err.Error(), "device unplugged") {
+		t.Errorf("a failing reader should fail the machine, got %v", err)
+	}
+}
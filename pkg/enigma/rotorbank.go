@@ -0,0 +1,180 @@
+// Package enigma provides rotor banks, named rotor libraries to build machines from.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/coredds/enigoma/internal/provenance"
+	"github.com/coredds/enigoma/internal/rotor"
+)
+
+// BankRotor is a rotor kept in a RotorBank: its wiring and notches, without
+// the position and ring setting it gets once it is put in a machine.
+type BankRotor struct {
+	Name       string                 `json:"name"`
+	Wiring     string                 `json:"wiring"`
+	Notches    string                 `json:"notches,omitempty"`    // Positions at which the next rotor steps; empty for rotors that never turn it
+	Provenance *provenance.Provenance `json:"provenance,omitempty"` // Optional origin of the wiring
+}
+
+// RotorBank is a library of named rotors, like the box of rotors an operator
+// chose from. Machines are built from it by name with WithRotorsFromBank.
+// A bank holds no machine state, so it can be saved and shared on its own.
+type RotorBank struct {
+	rotors map[string]BankRotor
+	names  []string // Registration order
+}
+
+// NewRotorBank returns an empty rotor bank.
+func NewRotorBank() *RotorBank {
+	return &RotorBank{rotors: make(map[string]BankRotor)}
+}
+
+// HistoricalRotorBank returns a bank holding the rotors of the M4 rotor box:
+// I to VIII, which also fit the Enigma I and M3, and the thin rotors Beta and
+// Gamma. Each call returns a new bank, so rotors registered in one do not
+// appear in another.
+func HistoricalRotorBank() *RotorBank {
+	bank := NewRotorBank()
+	for _, r := range []struct{ name, wiring, notches string }{
+		{"I", RotorI, string(NotchI)},
+		{"II", RotorII, string(NotchII)},
+		{"III", RotorIII, string(NotchIII)},
+		{"IV", RotorIV, string(NotchIV)},
+		{"V", RotorV, string(NotchV)},
+		{"VI", RotorVI, string(NotchVI)},
+		{"VII", RotorVII, string(NotchVII)},
+		{"VIII", RotorVIII, string(NotchVIII)},
+		{"Beta", RotorBeta, ""},
+		{"Gamma", RotorGamma, ""},
+	} {
+		// The historical wirings are valid, so registering cannot fail
+		_ = bank.Register(BankRotor{
+			Name:       r.name,
+			Wiring:     r.wiring,
+			Notches:    r.notches,
+			Provenance: provenance.Historical("M4", r.name),
+		})
+	}
+	return bank
+}
+
+// Register adds a rotor to the bank. Its name must be new to the bank and its
+// wiring may not repeat a character; the notches must be characters of the
+// wiring. Whether the wiring fits a machine's alphabet is checked when a
+// machine is built from it.
+func (b *RotorBank) Register(r BankRotor) error {
+	if strings.TrimSpace(r.Name) == "" {
+		return fmt.Errorf("rotor name cannot be empty")
+	}
+	if _, ok := b.rotors[r.Name]; ok {
+		return fmt.Errorf("rotor %s is already in the bank", r.Name)
+	}
+	if r.Wiring == "" {
+		return fmt.Errorf("rotor %s has no wiring", r.Name)
+	}
+	seen := make(map[rune]bool)
+	for _, c := range r.Wiring {
+		if seen[c] {
+			return fmt.Errorf("rotor %s wires %q twice", r.Name, c)
+		}
+		seen[c] = true
+	}
+	for _, n := range r.Notches {
+		if !seen[n] {
+			return fmt.Errorf("rotor %s has a notch at %q, which is not in its wiring", r.Name, n)
+		}
+	}
+
+	r.Provenance = r.Provenance.Copy()
+	b.rotors[r.Name] = r
+	b.names = append(b.names, r.Name)
+	return nil
+}
+
+// Remove takes a rotor out of the bank and reports whether it was there.
+func (b *RotorBank) Remove(name string) bool {
+	if _, ok := b.rotors[name]; !ok {
+		return false
+	}
+	delete(b.rotors, name)
+	for i, n := range b.names {
+		if n == name {
+			b.names = append(b.names[:i:i], b.names[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// Get returns the rotor called name.
+func (b *RotorBank) Get(name string) (BankRotor, bool) {
+	r, ok := b.rotors[name]
+	r.Provenance = r.Provenance.Copy()
+	return r, ok
+}
+
+// Names returns the names of the rotors in the order they were registered.
+func (b *RotorBank) Names() []string {
+	names := make([]string, len(b.names))
+	copy(names, b.names)
+	return names
+}
+
+// Len returns the number of rotors in the bank.
+func (b *RotorBank) Len() int {
+	return len(b.names)
+}
+
+// Specs returns rotor specs for the named rotors, in the order given (left to
+// right), at position 0 with ring setting 0. As with a physical rotor box, a
+// rotor can be used only once.
+func (b *RotorBank) Specs(names ...string) ([]rotor.RotorSpec, error) {
+	if len(names) == 0 {
+		return nil, fmt.Errorf("at least one rotor name must be provided")
+	}
+	specs := make([]rotor.RotorSpec, len(names))
+	used := make(map[string]bool)
+	for i, name := range names {
+		r, ok := b.rotors[name]
+		if !ok {
+			return nil, fmt.Errorf("no rotor %q in the bank (have %s)", name, strings.Join(b.names, ", "))
+		}
+		if used[name] {
+			return nil, fmt.Errorf("rotor %s is used twice", name)
+		}
+		used[name] = true
+		specs[i] = rotor.RotorSpec{
+			ID:             r.Name,
+			ForwardMapping: r.Wiring,
+			Notches:        []rune(r.Notches),
+			Provenance:     r.Provenance.Copy(),
+		}
+	}
+	return specs, nil
+}
+
+// WithRotorsByName sets the rotors from HistoricalRotorBank, named from left
+// to right, e.g. WithRotorsByName("III", "I", "II"). Use WithRotorPositions
+// and WithRingSettings afterwards to set them.
+func WithRotorsByName(names ...string) Option {
+	return WithRotorsFromBank(HistoricalRotorBank(), names...)
+}
+
+// WithRotorsFromBank sets the rotors from bank, named from left to right.
+func WithRotorsFromBank(bank *RotorBank, names ...string) Option {
+	return func(e *Enigma) error {
+		if bank == nil {
+			return fmt.Errorf("rotor bank cannot be nil")
+		}
+		specs, err := bank.Specs(names...)
+		if err != nil {
+			return err
+		}
+		return WithRotorConfiguration(specs)(e)
+	}
+}
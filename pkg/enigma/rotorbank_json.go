@@ -0,0 +1,68 @@
+//go:build !tinygo
+
+// Package enigma provides JSON serialization of rotor banks.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// rotorBankVersion is the version of the rotor bank file layout.
+const rotorBankVersion = 1
+
+// rotorBankJSON is the saved form of a RotorBank.
+type rotorBankJSON struct {
+	Version int         `json:"rotor_bank_version"`
+	Rotors  []BankRotor `json:"rotors"`
+}
+
+// MarshalJSON writes the bank's rotors in registration order.
+func (b *RotorBank) MarshalJSON() ([]byte, error) {
+	out := rotorBankJSON{Version: rotorBankVersion, Rotors: make([]BankRotor, 0, len(b.names))}
+	for _, name := range b.names {
+		out.Rotors = append(out.Rotors, b.rotors[name])
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON replaces the bank's rotors with the saved ones, checking each
+// as Register does.
+func (b *RotorBank) UnmarshalJSON(data []byte) error {
+	var in rotorBankJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	if in.Version != rotorBankVersion {
+		return fmt.Errorf("unsupported rotor bank version %d (want %d)", in.Version, rotorBankVersion)
+	}
+	bank := NewRotorBank()
+	for _, r := range in.Rotors {
+		if err := bank.Register(r); err != nil {
+			return err
+		}
+	}
+	*b = *bank
+	return nil
+}
+
+// SaveToJSON returns the bank as indented JSON, to share it on its own.
+func (b *RotorBank) SaveToJSON() (string, error) {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal rotor bank: %v", err)
+	}
+	return string(data), nil
+}
+
+// NewRotorBankFromJSON reads a bank saved by SaveToJSON.
+func NewRotorBankFromJSON(jsonData string) (*RotorBank, error) {
+	bank := NewRotorBank()
+	if err := json.Unmarshal([]byte(jsonData), bank); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal rotor bank: %v", err)
+	}
+	return bank, nil
+}
@@ -0,0 +1,51 @@
+//go:build !tinygo
+
+package enigma
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRotorBankJSONRoundTrip(t *testing.T) {
+	bank := HistoricalRotorBank()
+	if err := bank.Register(BankRotor{Name: "Mine", Wiring: "QWERTZUIOASDFGHJKPYXCVBNML", Notches: "AN"}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := bank.SaveToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(data, `"rotor_bank_version": 1`) || !strings.Contains(data, `"notches": "ZM"`) {
+		t.Errorf("unexpected bank JSON:\n%s", data)
+	}
+
+	loaded, err := NewRotorBankFromJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(loaded.Names(), bank.Names()) {
+		t.Errorf("names = %v, want %v", loaded.Names(), bank.Names())
+	}
+	for _, name := range bank.Names() {
+		want, _ := bank.Get(name)
+		got, _ := loaded.Get(name)
+		if got.Wiring != want.Wiring || got.Notches != want.Notches || got.Provenance.String() != want.Provenance.String() {
+			t.Errorf("rotor %s = %+v, want %+v", name, got, want)
+		}
+	}
+}
+
+func TestRotorBankJSONErrors(t *testing.T) {
+	for name, data := range map[string]string{
+		"not JSON":        `{`,
+		"wrong version":   `{"rotor_bank_version": 2, "rotors": []}`,
+		"duplicate rotor": `{"rotor_bank_version": 1, "rotors": [{"name": "A", "wiring": "BA"}, {"name": "A", "wiring": "AB"}]}`,
+		"bad wiring":      `{"rotor_bank_version": 1, "rotors": [{"name": "A", "wiring": "AA"}]}`,
+	} {
+		if _, err := NewRotorBankFromJSON(data); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package enigma
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/coredds/enigoma/internal/reflector"
+)
+
+// reflectorB returns the spec of the historical reflector B.
+func reflectorB() reflector.ReflectorSpec {
+	return reflector.ReflectorSpec{ID: "B", Mapping: ReflectorB}
+}
+
+func TestWithRotorsByName(t *testing.T) {
+	machine, err := New(
+		WithAlphabet([]rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")),
+		WithRotorsByName("I", "II", "III"),
+		WithReflectorConfiguration(reflectorB()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := machine.Encrypt("AAAAA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "BDZGO" {
+		t.Errorf("rotors I-II-III encrypted AAAAA as %q, want BDZGO", got)
+	}
+
+	// III-I-II is the M3 with its rotors rearranged
+	reordered, err := New(
+		WithAlphabet([]rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")),
+		WithRotorsByName("III", "I", "II"),
+		WithReflectorConfiguration(reflectorB()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m3, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m3.SetRotorOrder([]int{2, 0, 1}); err != nil {
+		t.Fatal(err)
+	}
+	want, err := m3.Encrypt("ROTORBANKROTORBANK")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := reordered.Encrypt("ROTORBANKROTORBANK"); err != nil || got != want {
+		t.Errorf("III-I-II encrypted as %q (%v), want %q", got, err, want)
+	}
+}
+
+func TestHistoricalRotorBank(t *testing.T) {
+	bank := HistoricalRotorBank()
+	want := []string{"I", "II", "III", "IV", "V", "VI", "VII", "VIII", "Beta", "Gamma"}
+	if got := bank.Names(); !slices.Equal(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+	vi, ok := bank.Get("VI")
+	if !ok || vi.Wiring != RotorVI || vi.Notches != "ZM" {
+		t.Errorf("Get(VI) = %+v, %v", vi, ok)
+	}
+	if vi.Provenance == nil || vi.Provenance.Source != "historical:M4/VI" {
+		t.Errorf("VI provenance = %v", vi.Provenance)
+	}
+
+	// Banks are independent of each other
+	if err := bank.Register(BankRotor{Name: "X", Wiring: RotorIII}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := HistoricalRotorBank().Get("X"); ok {
+		t.Error("a rotor registered in one bank appeared in a new one")
+	}
+}
+
+func TestRotorBankUserRotors(t *testing.T) {
+	bank := HistoricalRotorBank()
+	if err := bank.Register(BankRotor{Name: "Mine", Wiring: "QWERTZUIOASDFGHJKPYXCVBNML", Notches: "AN"}); err != nil {
+		t.Fatal(err)
+	}
+	machine, err := New(
+		WithAlphabet([]rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")),
+		WithRotorsFromBank(bank, "Mine", "IV", "V"),
+		WithReflectorConfiguration(reflectorB()),
+		WithRingSettings([]int{1, 2, 3}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings, err := machine.GetSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.RotorSpecs[0].ID != "Mine" || string(settings.RotorSpecs[0].Notches) != "AN" {
+		t.Errorf("first rotor = %+v", settings.RotorSpecs[0])
+	}
+	if settings.RotorSpecs[1].ForwardMapping != RotorIV || settings.RotorSpecs[2].RingSetting != 3 {
+		t.Errorf("rotors = %+v", settings.RotorSpecs)
+	}
+
+	if !bank.Remove("Mine") || bank.Remove("Mine") {
+		t.Error("Remove should report whether the rotor was there")
+	}
+	if _, err := bank.Specs("Mine"); err == nil {
+		t.Error("a removed rotor should not be usable")
+	}
+	if bank.Len() != 10 {
+		t.Errorf("Len() = %d after removing the user rotor, want 10", bank.Len())
+	}
+}
+
+func TestRotorBankErrors(t *testing.T) {
+	bank := HistoricalRotorBank()
+	for name, r := range map[string]BankRotor{
+		"empty name":       {Name: " ", Wiring: RotorI},
+		"duplicate name":   {Name: "I", Wiring: RotorII},
+		"no wiring":        {Name: "X"},
+		"repeated wiring":  {Name: "X", Wiring: "AAB"},
+		"notch off wiring": {Name: "X", Wiring: "BCA", Notches: "D"},
+	} {
+		if err := bank.Register(r); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	for name, names := range map[string][]string{
+		"no names":      nil,
+		"unknown rotor": {"I", "IX"},
+		"rotor twice":   {"I", "I", "II"},
+	} {
+		if _, err := bank.Specs(names...); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	// The wiring must fit the machine's alphabet
+	if err := bank.Register(BankRotor{Name: "Short", Wiring: "BCA"}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := New(
+		WithAlphabet([]rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")),
+		WithRotorsFromBank(bank, "Short"),
+		WithReflectorConfiguration(reflectorB()),
+	)
+	if err == nil {
+		t.Error("a rotor that does not fit the alphabet should be rejected")
+	}
+	if _, err := New(WithAlphabet([]rune("AB")), WithRotorsFromBank(nil, "I")); err == nil {
+		t.Error("a nil bank should be rejected")
+	}
+}
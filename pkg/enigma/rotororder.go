@@ -0,0 +1,130 @@
+// Package enigma provides changes to the rotor order (Walzenlage) of a built machine.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import (
+	"fmt"
+
+	"github.com/coredds/enigoma/internal/rotor"
+)
+
+// SwapRotors exchanges the rotors in slots i and j, counted from the left
+// from 0. Each rotor keeps its wiring, ring setting and position.
+func (e *Enigma) SwapRotors(i, j int) error {
+	if err := e.checkRotorSlot(i); err != nil {
+		return err
+	}
+	if err := e.checkRotorSlot(j); err != nil {
+		return err
+	}
+	order := make([]int, len(e.rotors))
+	for k := range order {
+		order[k] = k
+	}
+	order[i], order[j] = j, i
+	return e.SetRotorOrder(order)
+}
+
+// SetRotorOrder rearranges the rotors: order[k] is the slot, counted from the
+// left from 0, of the rotor that moves to slot k, so {2, 0, 1} turns I-II-III
+// into III-I-II. order must list every slot once. Each rotor keeps its wiring,
+// ring setting and position, and Reset returns it to its own initial
+// position. Rotor step counters move with their rotors.
+func (e *Enigma) SetRotorOrder(order []int) error {
+	n := len(e.rotors)
+	if len(order) != n {
+		return fmt.Errorf("rotor order has %d entries, but the machine has %d rotors", len(order), n)
+	}
+	seen := make([]bool, n)
+	for _, slot := range order {
+		if err := e.checkRotorSlot(slot); err != nil {
+			return err
+		}
+		if seen[slot] {
+			return fmt.Errorf("rotor order lists slot %d twice", slot)
+		}
+		seen[slot] = true
+	}
+
+	rotors := make([]rotor.Rotor, n)
+	for k, slot := range order {
+		rotors[k] = e.rotors[slot]
+	}
+	e.rotors = rotors
+	e.stepCounts = permuteInts(e.stepCounts, order)
+
+	// Initial settings may be shared with clones, so build new slices
+	if len(e.initialSettings.RotorSpecs) == n {
+		specs := make([]rotor.RotorSpec, n)
+		for k, slot := range order {
+			specs[k] = e.initialSettings.RotorSpecs[slot]
+		}
+		e.initialSettings.RotorSpecs = specs
+	}
+	e.initialSettings.CurrentRotorPositions = permuteInts(e.initialSettings.CurrentRotorPositions, order)
+	return nil
+}
+
+// ReplaceRotor puts a rotor built from spec in slot i, counted from the left
+// from 0, at the spec's position and ring setting, as when an operator swaps
+// in a rotor from the box. Reset returns it to that position. The wiring must
+// fit the machine's alphabet.
+func (e *Enigma) ReplaceRotor(i int, spec rotor.RotorSpec) error {
+	if err := e.checkRotorSlot(i); err != nil {
+		return err
+	}
+	size := e.alphabet.Size()
+	if spec.Position < 0 || spec.Position >= size {
+		return fmt.Errorf("rotor position %d is outside 0-%d", spec.Position, size-1)
+	}
+	if spec.RingSetting < 0 || spec.RingSetting >= size {
+		return fmt.Errorf("ring setting %d is outside 0-%d", spec.RingSetting, size-1)
+	}
+	r, err := rotor.CreateFromSpec(spec, e.alphabet)
+	if err != nil {
+		return fmt.Errorf("invalid rotor %s: %v", spec.ID, err)
+	}
+
+	e.rotors[i] = r
+	if i < len(e.stepCounts) {
+		e.stepCounts[i] = 0
+	}
+	if len(e.initialSettings.RotorSpecs) == len(e.rotors) {
+		specs := append([]rotor.RotorSpec(nil), e.initialSettings.RotorSpecs...)
+		specs[i], err = rotor.ToSpec(r, e.alphabet)
+		if err != nil {
+			return err
+		}
+		e.initialSettings.RotorSpecs = specs
+	}
+	if len(e.initialSettings.CurrentRotorPositions) == len(e.rotors) {
+		positions := append([]int(nil), e.initialSettings.CurrentRotorPositions...)
+		positions[i] = spec.Position
+		e.initialSettings.CurrentRotorPositions = positions
+	}
+	e.checkRotorWiring(i)
+	return nil
+}
+
+// checkRotorSlot rejects a rotor slot the machine does not have.
+func (e *Enigma) checkRotorSlot(i int) error {
+	if i < 0 || i >= len(e.rotors) {
+		return fmt.Errorf("rotor slot %d is outside 0-%d", i, len(e.rotors)-1)
+	}
+	return nil
+}
+
+// permuteInts returns values rearranged by order, as in SetRotorOrder, or
+// values itself when it does not have one entry per slot.
+func permuteInts(values, order []int) []int {
+	if len(values) != len(order) {
+		return values
+	}
+	out := make([]int, len(values))
+	for k, slot := range order {
+		out[k] = values[slot]
+	}
+	return out
+}
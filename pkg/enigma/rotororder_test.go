@@ -0,0 +1,270 @@
+package enigma
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/coredds/enigoma/internal/rotor"
+)
+
+// newReorderedM3 loads an M3 with its rotors rearranged as SetRotorOrder(order) would.
+func newReorderedM3(t *testing.T, order []int) *Enigma {
+	t.Helper()
+	machine, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings, err := machine.GetSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	specs := make([]rotor.RotorSpec, len(order))
+	positions := make([]int, len(order))
+	for k, slot := range order {
+		specs[k] = settings.RotorSpecs[slot]
+		positions[k] = settings.CurrentRotorPositions[slot]
+	}
+	settings.RotorSpecs = specs
+	settings.CurrentRotorPositions = positions
+	reordered, err := NewFromSettings(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reordered
+}
+
+func TestSetRotorOrder(t *testing.T) {
+	const plaintext = "WALZENLAGEWALZENLAGE"
+	for _, order := range [][]int{{0, 1, 2}, {2, 0, 1}, {1, 2, 0}, {2, 1, 0}} {
+		machine, err := NewEnigmaM3()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := machine.SetRotorPositions([]int{0, 3, 7}); err != nil {
+			t.Fatal(err)
+		}
+		if err := machine.SetRotorOrder(order); err != nil {
+			t.Fatalf("SetRotorOrder(%v): %v", order, err)
+		}
+
+		want := newReorderedM3(t, order)
+		positions := []int{0, 3, 7}
+		reordered := make([]int, len(order))
+		for k, slot := range order {
+			reordered[k] = positions[slot]
+		}
+		if err := want.SetRotorPositions(reordered); err != nil {
+			t.Fatal(err)
+		}
+		if got := machine.GetCurrentRotorPositions(); !slices.Equal(got, reordered) {
+			t.Errorf("order %v: positions = %v, want %v", order, got, reordered)
+		}
+
+		got, err := machine.Encrypt(plaintext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, err := want.Encrypt(plaintext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != expected {
+			t.Errorf("order %v: got %q, want %q", order, got, expected)
+		}
+	}
+}
+
+func TestSetRotorOrder_ResetFollowsRotors(t *testing.T) {
+	machine, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings, err := machine.GetSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings.RotorSpecs[0].Position = 1
+	settings.RotorSpecs[2].Position = 5
+	settings.CurrentRotorPositions = nil
+	if machine, err = NewFromSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := machine.SwapRotors(0, 2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := machine.Encrypt("HELLOWORLD"); err != nil {
+		t.Fatal(err)
+	}
+	if err := machine.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if got := machine.GetCurrentRotorPositions(); !slices.Equal(got, []int{5, 0, 1}) {
+		t.Errorf("positions after Reset = %v, want [5 0 1]", got)
+	}
+
+	ids := []string{settings.RotorSpecs[2].ID, settings.RotorSpecs[1].ID, settings.RotorSpecs[0].ID}
+	after, err := machine.GetSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, spec := range after.RotorSpecs {
+		if spec.ID != ids[i] {
+			t.Errorf("slot %d holds rotor %s, want %s", i, spec.ID, ids[i])
+		}
+	}
+}
+
+func TestSetRotorOrder_StepCountsFollowRotors(t *testing.T) {
+	machine, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := machine.Encrypt("AAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"); err != nil {
+		t.Fatal(err)
+	}
+	before := machine.GetRotorStepCounts()
+	if err := machine.SwapRotors(0, 2); err != nil {
+		t.Fatal(err)
+	}
+	after := machine.GetRotorStepCounts()
+	if after[0] != before[2] || after[1] != before[1] || after[2] != before[0] {
+		t.Errorf("step counts after swap = %v, before = %v", after, before)
+	}
+}
+
+func TestSetRotorOrder_LeavesClonesAlone(t *testing.T) {
+	machine, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatal(err)
+	}
+	clone, err := machine.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := machine.SetRotorOrder([]int{2, 0, 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := machine.ReplaceRotor(1, rotor.RotorSpec{
+		ID:             "X",
+		ForwardMapping: "BDFHJLCPRTXVZNYEIWGAKMUSQO",
+		Notches:        []rune{'V'},
+		Position:       4,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := clone.Encrypt("AAAAA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "BDZGO" {
+		t.Errorf("clone encrypted AAAAA as %q, want BDZGO", got)
+	}
+}
+
+func TestReplaceRotor(t *testing.T) {
+	machine, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings, err := machine.GetSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := rotor.RotorSpec{
+		ID:             "X",
+		ForwardMapping: "BDFHJLCPRTXVZNYEIWGAKMUSQO",
+		Notches:        []rune{'V'},
+		Position:       4,
+		RingSetting:    2,
+	}
+	if err := machine.ReplaceRotor(1, spec); err != nil {
+		t.Fatal(err)
+	}
+
+	settings.RotorSpecs[1] = spec
+	settings.CurrentRotorPositions[1] = spec.Position
+	want, err := NewFromSettings(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for round := 0; round < 2; round++ {
+		got, err := machine.Encrypt("ROTORBOXROTORBOX")
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, err := want.Encrypt("ROTORBOXROTORBOX")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != expected {
+			t.Errorf("round %d: got %q, want %q", round, got, expected)
+		}
+		// Reset must return the new rotor to its own position
+		if err := machine.Reset(); err != nil {
+			t.Fatal(err)
+		}
+		if err := want.Reset(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReplaceRotor_WarnsAboutIdentity(t *testing.T) {
+	machine, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatal(err)
+	}
+	machine.ClearWarnings()
+	if err := machine.ReplaceRotor(0, rotor.RotorSpec{
+		ID:             "ID",
+		ForwardMapping: "ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+		Notches:        []rune{'Q'},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	warnings := machine.Warnings()
+	if len(warnings) != 1 || warnings[0].Code != WarnDegenerateRotor {
+		t.Errorf("warnings = %v, want one %s", warnings, WarnDegenerateRotor)
+	}
+}
+
+func TestRotorOrderErrors(t *testing.T) {
+	machine, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"swap out of range", func() error { return machine.SwapRotors(0, 3) }},
+		{"swap negative", func() error { return machine.SwapRotors(-1, 0) }},
+		{"order too short", func() error { return machine.SetRotorOrder([]int{0, 1}) }},
+		{"order repeats a slot", func() error { return machine.SetRotorOrder([]int{0, 1, 1}) }},
+		{"order out of range", func() error { return machine.SetRotorOrder([]int{0, 1, 3}) }},
+		{"replace out of range", func() error {
+			return machine.ReplaceRotor(3, rotor.RotorSpec{ID: "X", ForwardMapping: "BDFHJLCPRTXVZNYEIWGAKMUSQO"})
+		}},
+		{"replace with bad wiring", func() error {
+			return machine.ReplaceRotor(0, rotor.RotorSpec{ID: "X", ForwardMapping: "ABC"})
+		}},
+		{"replace with bad position", func() error {
+			return machine.ReplaceRotor(0, rotor.RotorSpec{ID: "X", ForwardMapping: "BDFHJLCPRTXVZNYEIWGAKMUSQO", Position: 26})
+		}},
+	}
+	for _, tt := range tests {
+		if err := tt.call(); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+
+	got, err := machine.Encrypt("AAAAA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "BDZGO" {
+		t.Errorf("failed calls changed the machine: AAAAA encrypted as %q", got)
+	}
+}
@@ -5,8 +5,9 @@
 package enigma
 
 import (
-	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/coredds/enigoma/internal/alphabet"
 	"github.com/coredds/enigoma/internal/plugboard"
@@ -14,15 +15,31 @@ import (
 	"github.com/coredds/enigoma/internal/rotor"
 )
 
+// CurrentSchemaVersion is the version of the settings schema this package
+// writes. Older versions are still read and upgraded; see migrateSettings.
+const CurrentSchemaVersion = 2
+
 // EnigmaSettings represents the serializable configuration and state of an Enigma machine.
 type EnigmaSettings struct {
 	SchemaVersion         int                     `json:"schema_version"`
 	Alphabet              []rune                  `json:"alphabet"`
+	Symbols               []string                `json:"symbols,omitempty"` // Grapheme clusters the Alphabet runes stand for; see WithGraphemeAlphabet
 	RotorSpecs            []rotor.RotorSpec       `json:"rotor_specs"`
 	ReflectorSpec         reflector.ReflectorSpec `json:"reflector_spec"`
+	Reflectorless         bool                    `json:"reflectorless,omitempty"`      // Experimental: no reflector, ReflectorSpec unused
+	SteppingMode          SteppingMode            `json:"stepping_mode,omitempty"`      // Zero is the default lever mechanism
+	EntryWheel            string                  `json:"entry_wheel,omitempty"`        // Empty is the identity; see WithEntryWheel
+	Uhr                   bool                    `json:"-"`                            // The plugboard pairs go through the Uhr; see WithUhr
+	UhrPosition           int                     `json:"uhr_position,omitempty"`       // Position of the Uhr, written only with Uhr set
+	PlugboardDisabled     bool                    `json:"plugboard_disabled,omitempty"` // No plugboard stage; PlugboardPairs must be empty
 	PlugboardPairs        map[rune]rune           `json:"plugboard_pairs"`
+	UnknownCharPolicy     UnknownCharPolicy       `json:"unknown_char_policy,omitempty"` // Zero rejects characters outside the alphabet; needs schema 2
+	Normalization         Normalization           `json:"normalization,omitempty"`       // Zero leaves text as written; needs schema 2
+	CaseFolding           bool                    `json:"case_folding,omitempty"`        // Lowercase input folds onto the alphabet; needs schema 2
 	CurrentRotorPositions []int                   `json:"current_rotor_positions"`
 	Metadata              *Metadata               `json:"metadata,omitempty"`
+
+	migratedFrom int // Schema version the settings were decoded from, when older
 }
 
 // Metadata contains optional information about the configuration.
@@ -32,6 +49,19 @@ type Metadata struct {
 	Description string   `json:"description,omitempty"`
 	Preset      string   `json:"preset,omitempty"`
 	Tags        []string `json:"tags,omitempty"`
+	ExpiresAt   string   `json:"expires_at,omitempty"` // RFC 3339 timestamp after which the key should be retired
+
+	AlphabetOrdering string `json:"alphabet_ordering,omitempty"` // How an auto-detected alphabet was ordered (codepoint, frequency, encountered)
+
+	Signature *Signature `json:"signature,omitempty"` // Set by Sign; covers the settings and the rest of the metadata
+}
+
+// Signature authenticates saved settings; see Sign and VerifySignature.
+type Signature struct {
+	Algorithm string `json:"algorithm"`            // hmac-sha256 or ed25519
+	KeyID     string `json:"key_id"`               // Identifies the signing key without revealing it
+	PublicKey string `json:"public_key,omitempty"` // Base64 Ed25519 public key, for checking integrity without the key file
+	Value     string `json:"value"`                // Base64 signature
 }
 
 // GetSettings returns the current configuration and state of the Enigma machine.
@@ -54,9 +84,13 @@ func (e *Enigma) GetSettings() (*EnigmaSettings, error) {
 	}
 
 	// Get reflector specification
-	reflectorSpec, err := reflector.ToSpec(e.reflector, e.alphabet)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get reflector spec: %v", err)
+	var reflectorSpec reflector.ReflectorSpec
+	if !e.reflectorless {
+		var err error
+		reflectorSpec, err = reflector.ToSpec(e.reflector, e.alphabet)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get reflector spec: %v", err)
+		}
 	}
 
 	// Get plugboard pairs
@@ -68,14 +102,34 @@ func (e *Enigma) GetSettings() (*EnigmaSettings, error) {
 	// Get current rotor positions
 	currentPositions := e.GetCurrentRotorPositions()
 
+	var uhrPosition int
+	if e.uhr != nil {
+		uhrPosition = e.uhr.Position()
+	}
+
+	var symbols []string
+	if e.alphabet.IsGrapheme() {
+		symbols = e.alphabet.Symbols()
+	}
+
 	return &EnigmaSettings{
-		SchemaVersion:         1, // Current schema version
+		SchemaVersion:         CurrentSchemaVersion,
 		Alphabet:              alphabetRunes,
+		Symbols:               symbols,
 		RotorSpecs:            rotorSpecs,
 		ReflectorSpec:         reflectorSpec,
+		Reflectorless:         e.reflectorless,
+		SteppingMode:          e.stepping,
+		EntryWheel:            e.GetEntryWheel(),
+		Uhr:                   e.uhr != nil,
+		UhrPosition:           uhrPosition,
+		PlugboardDisabled:     e.plugboardDisabled,
 		PlugboardPairs:        plugboardPairs,
+		UnknownCharPolicy:     e.unknownChars,
+		Normalization:         e.normalization,
+		CaseFolding:           e.caseFolding,
 		CurrentRotorPositions: currentPositions,
-		Metadata:              nil, // Default to no metadata
+		Metadata:              e.GetMetadata(),
 	}, nil
 }
 
@@ -86,12 +140,21 @@ func (e *Enigma) LoadSettings(settings *EnigmaSettings) error {
 	}
 
 	// Create alphabet
-	alph, err := alphabet.New(settings.Alphabet)
+	alph, err := settingsAlphabet(settings)
 	if err != nil {
 		return fmt.Errorf("failed to create alphabet: %v", err)
 	}
 	e.alphabet = alph
 
+	// The alphabet was saved normalized; one that is not was edited by hand
+	if !settings.Normalization.valid() {
+		return fmt.Errorf("invalid normalization: %d", settings.Normalization)
+	}
+	if normalized, err := normalizeAlphabet(alph, settings.Normalization); err != nil || !slices.Equal(normalized.Symbols(), alph.Symbols()) {
+		return fmt.Errorf("alphabet is not in %s form", strings.ToUpper(settings.Normalization.String()))
+	}
+	e.normalization = settings.Normalization
+
 	// Create rotors
 	rotors := make([]rotor.Rotor, len(settings.RotorSpecs))
 	for i, spec := range settings.RotorSpecs {
@@ -104,11 +167,33 @@ func (e *Enigma) LoadSettings(settings *EnigmaSettings) error {
 	e.rotors = rotors
 
 	// Create reflector
-	refl, err := reflector.CreateFromSpec(settings.ReflectorSpec, e.alphabet)
+	e.reflectorless = settings.Reflectorless
+	e.reflector = nil
+	if !settings.Reflectorless {
+		refl, err := reflector.CreateFromSpec(settings.ReflectorSpec, e.alphabet)
+		if err != nil {
+			return fmt.Errorf("failed to create reflector: %v", err)
+		}
+		e.reflector = refl
+	}
+
+	if !settings.SteppingMode.valid() {
+		return fmt.Errorf("invalid stepping mode: %d", settings.SteppingMode)
+	}
+	e.stepping = settings.SteppingMode
+
+	if !settings.UnknownCharPolicy.valid() {
+		return fmt.Errorf("invalid unknown character policy: %d", settings.UnknownCharPolicy)
+	}
+	e.unknownChars = settings.UnknownCharPolicy
+	e.caseFolding = settings.CaseFolding
+	e.migratedFrom = settings.migratedFrom
+
+	ew, err := newEntryWheel(settings.EntryWheel, e.alphabet)
 	if err != nil {
-		return fmt.Errorf("failed to create reflector: %v", err)
+		return err
 	}
-	e.reflector = refl
+	e.entryWheel = ew
 
 	// Create plugboard
 	pb, err := plugboard.New(e.alphabet)
@@ -124,6 +209,20 @@ func (e *Enigma) LoadSettings(settings *EnigmaSettings) error {
 	}
 	e.plugboard = pb
 
+	e.uhr = nil
+	if settings.Uhr {
+		u, err := newUhr(pb, e.alphabet, settings.UhrPosition)
+		if err != nil {
+			return fmt.Errorf("failed to attach the Uhr: %v", err)
+		}
+		e.uhr = u
+	}
+	e.plugboardDisabled = settings.PlugboardDisabled
+	if err := e.checkPlugboardDisabled(); err != nil {
+		return err
+	}
+	e.SetMetadata(settings.Metadata)
+
 	// Set current rotor positions if provided
 	if len(settings.CurrentRotorPositions) > 0 {
 		if len(settings.CurrentRotorPositions) != len(e.rotors) {
@@ -144,107 +243,9 @@ func (e *Enigma) LoadSettings(settings *EnigmaSettings) error {
 		initialSettings.CurrentRotorPositions[i] = spec.Position
 	}
 	e.initialSettings = initialSettings
+	e.checkComponents()
 
-	return nil
-}
-
-// MarshalJSON marshals the EnigmaSettings to JSON.
-func (s *EnigmaSettings) MarshalJSON() ([]byte, error) {
-	// Convert runes to strings for JSON compatibility
-	type jsonSettings struct {
-		SchemaVersion         int                     `json:"schema_version"`
-		Alphabet              string                  `json:"alphabet"`
-		RotorSpecs            []rotor.RotorSpec       `json:"rotor_specs"`
-		ReflectorSpec         reflector.ReflectorSpec `json:"reflector_spec"`
-		PlugboardPairs        map[string]string       `json:"plugboard_pairs"`
-		CurrentRotorPositions []int                   `json:"current_rotor_positions"`
-		Metadata              *Metadata               `json:"metadata,omitempty"`
-	}
-
-	js := jsonSettings{
-		SchemaVersion:         s.SchemaVersion,
-		Alphabet:              string(s.Alphabet),
-		RotorSpecs:            s.RotorSpecs,
-		ReflectorSpec:         s.ReflectorSpec,
-		CurrentRotorPositions: s.CurrentRotorPositions,
-		PlugboardPairs:        make(map[string]string),
-		Metadata:              s.Metadata,
-	}
-
-	// Convert rune pairs to string pairs
-	for k, v := range s.PlugboardPairs {
-		js.PlugboardPairs[string(k)] = string(v)
-	}
-
-	return json.Marshal(js)
-}
-
-// UnmarshalJSON unmarshals JSON to EnigmaSettings.
-func (s *EnigmaSettings) UnmarshalJSON(data []byte) error {
-	type jsonSettings struct {
-		SchemaVersion         int                     `json:"schema_version"`
-		Alphabet              string                  `json:"alphabet"`
-		RotorSpecs            []rotor.RotorSpec       `json:"rotor_specs"`
-		ReflectorSpec         reflector.ReflectorSpec `json:"reflector_spec"`
-		PlugboardPairs        map[string]string       `json:"plugboard_pairs"`
-		CurrentRotorPositions []int                   `json:"current_rotor_positions"`
-		Metadata              *Metadata               `json:"metadata,omitempty"`
-	}
-
-	var js jsonSettings
-	if err := json.Unmarshal(data, &js); err != nil {
-		return err
-	}
-
-	// Check schema version
-	if js.SchemaVersion != 1 {
-		return fmt.Errorf("unsupported schema version: %d (expected 1)", js.SchemaVersion)
-	}
-
-	s.SchemaVersion = js.SchemaVersion
-	s.Alphabet = []rune(js.Alphabet)
-	s.RotorSpecs = js.RotorSpecs
-	s.ReflectorSpec = js.ReflectorSpec
-	s.CurrentRotorPositions = js.CurrentRotorPositions
-	s.Metadata = js.Metadata
-	s.PlugboardPairs = make(map[rune]rune)
-
-	// Convert string pairs back to rune pairs
-	for k, v := range js.PlugboardPairs {
-		if len(k) != 1 || len(v) != 1 {
-			return fmt.Errorf("invalid plugboard pair: %s->%s", k, v)
-		}
-		kRune := []rune(k)[0]
-		vRune := []rune(v)[0]
-		s.PlugboardPairs[kRune] = vRune
-	}
-
-	return nil
-}
-
-// SaveSettingsToJSON saves the current Enigma settings to a JSON string.
-func (e *Enigma) SaveSettingsToJSON() (string, error) {
-	settings, err := e.GetSettings()
-	if err != nil {
-		return "", fmt.Errorf("failed to get settings: %v", err)
-	}
-
-	data, err := json.MarshalIndent(settings, "", "  ")
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal settings: %v", err)
-	}
-
-	return string(data), nil
-}
-
-// LoadSettingsFromJSON loads Enigma settings from a JSON string.
-func (e *Enigma) LoadSettingsFromJSON(jsonData string) error {
-	var settings EnigmaSettings
-	if err := json.Unmarshal([]byte(jsonData), &settings); err != nil {
-		return fmt.Errorf("failed to unmarshal settings: %v", err)
-	}
-
-	return e.LoadSettings(&settings)
+	return e.checkCaseFolding()
 }
 
 // NewFromSettings creates a new Enigma machine from the provided settings.
@@ -256,12 +257,18 @@ func NewFromSettings(settings *EnigmaSettings) (*Enigma, error) {
 	return e, nil
 }
 
-// NewFromJSON creates a new Enigma machine from JSON settings.
-func NewFromJSON(jsonData string) (*Enigma, error) {
-	var settings EnigmaSettings
-	if err := json.Unmarshal([]byte(jsonData), &settings); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal settings: %v", err)
+// settingsAlphabet creates the alphabet of settings: of grapheme clusters
+// when they list symbols, whose runes must then be the Alphabet.
+func settingsAlphabet(settings *EnigmaSettings) (*alphabet.Alphabet, error) {
+	if len(settings.Symbols) == 0 {
+		return alphabet.New(settings.Alphabet)
 	}
-
-	return NewFromSettings(&settings)
+	alph, err := alphabet.NewFromSymbols(settings.Symbols)
+	if err != nil {
+		return nil, err
+	}
+	if string(alph.Runes()) != string(settings.Alphabet) {
+		return nil, fmt.Errorf("alphabet does not match the runes of the %d symbols", len(settings.Symbols))
+	}
+	return alph, nil
 }
@@ -0,0 +1,188 @@
+//go:build !tinygo
+
+// Package enigma provides password-protected configuration files.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// EncryptedConfigFormat identifies a password-protected configuration in its
+// "format" field.
+const EncryptedConfigFormat = "enigoma-encrypted-config"
+
+// EncryptedConfigVersion is the current encrypted configuration version.
+const EncryptedConfigVersion = 1
+
+const (
+	// encryptedConfigIterations is the PBKDF2 work factor for new files.
+	// Files record their own count, so raising it keeps old files readable.
+	encryptedConfigIterations = 200000
+	// maxEncryptedConfigIterations bounds the work a crafted file can demand.
+	maxEncryptedConfigIterations = 10000000
+	encryptedConfigSaltSize      = 16
+)
+
+// encryptedConfig is the JSON envelope of a password-protected configuration.
+// The settings JSON is sealed with AES-256-GCM under a key derived from the
+// password with PBKDF2-HMAC-SHA256; the header fields are authenticated too.
+type encryptedConfig struct {
+	Format     string `json:"format"`
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       string `json:"salt"`       // Base64-encoded random salt
+	Nonce      string `json:"nonce"`      // Base64-encoded GCM nonce
+	Ciphertext string `json:"ciphertext"` // Base64-encoded sealed settings JSON
+}
+
+// additionalData binds the header to the ciphertext, so the iteration count
+// or format cannot be altered without failing authentication.
+func (c *encryptedConfig) additionalData() []byte {
+	return []byte(fmt.Sprintf("%s/v%d/%s/%d", c.Format, c.Version, c.KDF, c.Iterations))
+}
+
+// SaveSettingsEncrypted saves the current settings as JSON sealed with a
+// password, so the key is not readable at rest. Each call uses a fresh salt
+// and nonce. Load the result with NewFromEncryptedJSON.
+func (e *Enigma) SaveSettingsEncrypted(password string) (string, error) {
+	if password == "" {
+		return "", fmt.Errorf("password cannot be empty")
+	}
+	plaintext, err := e.SaveSettingsToJSON()
+	if err != nil {
+		return "", err
+	}
+
+	salt := make([]byte, encryptedConfigSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %v", err)
+	}
+	envelope := &encryptedConfig{
+		Format:     EncryptedConfigFormat,
+		Version:    EncryptedConfigVersion,
+		KDF:        "pbkdf2-sha256",
+		Iterations: encryptedConfigIterations,
+		Salt:       base64.StdEncoding.EncodeToString(salt),
+	}
+	aead, err := encryptedConfigAEAD(password, salt, envelope.Iterations)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %v", err)
+	}
+	envelope.Nonce = base64.StdEncoding.EncodeToString(nonce)
+	envelope.Ciphertext = base64.StdEncoding.EncodeToString(aead.Seal(nil, nonce, []byte(plaintext), envelope.additionalData()))
+
+	data, err := json.MarshalIndent(envelope, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal encrypted config: %v", err)
+	}
+	return string(data), nil
+}
+
+// NewFromEncryptedJSON creates a machine from a configuration saved by
+// SaveSettingsEncrypted. A wrong password and a tampered file fail the same way.
+func NewFromEncryptedJSON(data, password string) (*Enigma, error) {
+	return NewFromEncryptedJSONWithLimits(data, password, DefaultLimits)
+}
+
+// NewFromEncryptedJSONWithLimits is NewFromEncryptedJSON with the decrypted
+// settings checked against limits, as in NewFromJSONWithLimits.
+func NewFromEncryptedJSONWithLimits(data, password string, limits Limits) (*Enigma, error) {
+	limits = limits.withDefaults()
+	if max := limits.MaxConfigBytes; max > 0 {
+		// Base64 makes the sealed settings a third larger than the plain JSON,
+		// which is checked against the limit itself once decrypted.
+		if err := exceeds("encrypted config size", len(data), max/3*4+1024); err != nil {
+			return nil, err
+		}
+	}
+
+	var envelope encryptedConfig
+	if err := json.Unmarshal([]byte(data), &envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse encrypted config: %v", err)
+	}
+	if envelope.Format != EncryptedConfigFormat {
+		return nil, fmt.Errorf("not an encrypted enigoma config (format %q)", envelope.Format)
+	}
+	if envelope.Version < 1 || envelope.Version > EncryptedConfigVersion {
+		return nil, fmt.Errorf("unsupported encrypted config version: %d", envelope.Version)
+	}
+	if envelope.KDF != "pbkdf2-sha256" {
+		return nil, fmt.Errorf("unsupported key derivation: %s", envelope.KDF)
+	}
+	if envelope.Iterations < 1 || envelope.Iterations > maxEncryptedConfigIterations {
+		return nil, fmt.Errorf("invalid iteration count: %d", envelope.Iterations)
+	}
+	salt, err := base64.StdEncoding.DecodeString(envelope.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid salt: %v", err)
+	}
+	nonce, err := base64.StdEncoding.DecodeString(envelope.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nonce: %v", err)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(envelope.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ciphertext: %v", err)
+	}
+
+	aead, err := encryptedConfigAEAD(password, salt, envelope.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("invalid nonce length: %d", len(nonce))
+	}
+	plaintext, err := aead.Open(nil, nonce, sealed, envelope.additionalData())
+	if err != nil {
+		return nil, fmt.Errorf("wrong password or corrupted encrypted config")
+	}
+
+	settings, err := decodeSettingsJSON(plaintext, limits)
+	if err != nil {
+		return nil, err
+	}
+	return NewFromSettings(settings)
+}
+
+// IsEncryptedConfig reports whether data looks like a configuration saved by
+// SaveSettingsEncrypted.
+func IsEncryptedConfig(data string) bool {
+	trimmed := strings.TrimSpace(data)
+	if !strings.HasPrefix(trimmed, "{") {
+		return false
+	}
+	var probe struct {
+		Format string `json:"format"`
+	}
+	return json.Unmarshal([]byte(trimmed), &probe) == nil && probe.Format == EncryptedConfigFormat
+}
+
+// encryptedConfigAEAD derives the AES-256-GCM cipher for a password and salt.
+func encryptedConfigAEAD(password string, salt []byte, iterations int) (cipher.AEAD, error) {
+	if password == "" {
+		return nil, fmt.Errorf("password cannot be empty")
+	}
+	block, err := aes.NewCipher(pbkdf2SHA256([]byte(password), salt, iterations))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %v", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %v", err)
+	}
+	return aead, nil
+}
@@ -0,0 +1,95 @@
+//go:build !tinygo
+
+package enigma
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSettingsEncryptedRoundTrip(t *testing.T) {
+	machine, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := machine.SaveSettingsEncrypted("hunter2")
+	if err != nil {
+		t.Fatalf("SaveSettingsEncrypted failed: %v", err)
+	}
+	if !IsEncryptedConfig(data) {
+		t.Fatal("IsEncryptedConfig should recognize the output")
+	}
+	if strings.Contains(data, "rotor_specs") || strings.Contains(data, "EKMFLGDQVZNTOWYHXUSPAIBRCJ") {
+		t.Fatal("encrypted config must not contain the settings in the clear")
+	}
+
+	loaded, err := NewFromEncryptedJSON(data, "hunter2")
+	if err != nil {
+		t.Fatalf("NewFromEncryptedJSON failed: %v", err)
+	}
+	want, _ := machine.Fingerprint()
+	if got, _ := loaded.Fingerprint(); got != want {
+		t.Error("loaded machine differs from the saved one")
+	}
+
+	again, err := machine.SaveSettingsEncrypted("hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again == data {
+		t.Error("each save should use a fresh salt and nonce")
+	}
+}
+
+func TestSettingsEncryptedErrors(t *testing.T) {
+	machine, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := machine.SaveSettingsEncrypted(""); err == nil {
+		t.Error("an empty password should be rejected")
+	}
+	data, err := machine.SaveSettingsEncrypted("hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewFromEncryptedJSON(data, "hunter3"); err == nil || !strings.Contains(err.Error(), "wrong password") {
+		t.Errorf("wrong password: got %v", err)
+	}
+
+	tamper := func(edit func(map[string]any)) string {
+		var fields map[string]any
+		if err := json.Unmarshal([]byte(data), &fields); err != nil {
+			t.Fatal(err)
+		}
+		edit(fields)
+		out, _ := json.Marshal(fields)
+		return string(out)
+	}
+	for name, bad := range map[string]string{
+		"not JSON":         "key",
+		"plain settings":   `{"alphabet":["A","B"]}`,
+		"future version":   tamper(func(f map[string]any) { f["version"] = EncryptedConfigVersion + 1 }),
+		"unknown kdf":      tamper(func(f map[string]any) { f["kdf"] = "md5" }),
+		"huge iterations":  tamper(func(f map[string]any) { f["iterations"] = maxEncryptedConfigIterations + 1 }),
+		"fewer iterations": tamper(func(f map[string]any) { f["iterations"] = 1000 }),
+		"bad nonce":        tamper(func(f map[string]any) { f["nonce"] = "AAAA" }),
+	} {
+		if _, err := NewFromEncryptedJSON(bad, "hunter2"); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	if IsEncryptedConfig(`{"format":"enigoma-container"}`) {
+		t.Error("a container is not an encrypted config")
+	}
+
+	_, err = NewFromEncryptedJSONWithLimits(data, "hunter2", Limits{MaxConfigBytes: 64})
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) {
+		t.Errorf("expected a *LimitError, got %v", err)
+	}
+}
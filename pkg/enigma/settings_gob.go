@@ -0,0 +1,41 @@
+//go:build !tinygo
+
+// Package enigma provides gob serialization of Enigma settings.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+)
+
+// SaveSettingsToGob saves the current Enigma settings in gob format, for Go
+// services that exchange settings with each other. Use Protocol Buffers
+// (SaveSettingsToProto) when other languages are involved.
+func (e *Enigma) SaveSettingsToGob() ([]byte, error) {
+	settings, err := e.GetSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settings: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(settings); err != nil {
+		return nil, fmt.Errorf("failed to encode settings: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// NewFromGob creates a new Enigma machine from gob-encoded settings.
+func NewFromGob(data []byte) (*Enigma, error) {
+	var settings EnigmaSettings
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&settings); err != nil {
+		return nil, fmt.Errorf("failed to decode settings: %v", err)
+	}
+	if err := migrateSettings(&settings); err != nil {
+		return nil, err
+	}
+	return NewFromSettings(&settings)
+}
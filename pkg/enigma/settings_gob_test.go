@@ -0,0 +1,104 @@
+//go:build !tinygo
+
+package enigma
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSettingsGobRoundTrip(t *testing.T) {
+	machine, err := New(WithAlphabet([]rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")), WithRandomSettings(High))
+	if err != nil {
+		t.Fatalf("failed to create machine: %v", err)
+	}
+	data, err := machine.SaveSettingsToGob()
+	if err != nil {
+		t.Fatalf("SaveSettingsToGob failed: %v", err)
+	}
+	restored, err := NewFromGob(data)
+	if err != nil {
+		t.Fatalf("NewFromGob failed: %v", err)
+	}
+	a, _ := machine.Encrypt("ATTACKATDAWN")
+	b, _ := restored.Encrypt("ATTACKATDAWN")
+	if a != b {
+		t.Errorf("restored machine encrypts differently: %s vs %s", a, b)
+	}
+
+	if _, err := NewFromGob([]byte("not gob")); err == nil {
+		t.Error("expected an error for invalid gob data")
+	}
+}
+
+// TestSettingsFormatEquivalence checks that JSON, gob and protobuf carry the
+// same settings: each decoded form re-encodes to identical JSON and yields
+// the same fingerprint.
+func TestSettingsFormatEquivalence(t *testing.T) {
+	machines := map[string]func() (*Enigma, error){
+		"classic":       NewEnigmaClassic,
+		"m4 provenance": NewEnigmaM4,
+		"greek metadata": func() (*Enigma, error) {
+			return New(
+				WithAlphabet([]rune("ΑΒΓΔΕΖΗΘΙΚΛΜΝΞΟΠΡΣΤΥΦΧΨΩ")),
+				WithRandomSettings(Extreme),
+				WithMetadata(&Metadata{Description: "Ελληνικά", Tags: []string{"x"}}),
+			)
+		},
+		"reflectorless": func() (*Enigma, error) {
+			return New(WithAlphabet([]rune("ABCDEFG")), WithoutReflector(), WithRandomSettings(Medium))
+		},
+		"rewirable reflector": func() (*Enigma, error) {
+			return New(WithAlphabet([]rune("ABCDEF")), WithRandomSettings(Low),
+				WithRewirableReflector(map[rune]rune{'A': 'F', 'B': 'E', 'C': 'D'}))
+		},
+	}
+
+	for name, build := range machines {
+		machine, err := build()
+		if err != nil {
+			t.Fatalf("%s: failed to create machine: %v", name, err)
+		}
+		wantJSON, _ := machine.SaveSettingsToJSON()
+		wantFP, _ := machine.Fingerprint()
+
+		fromJSON, err := NewFromJSON(wantJSON)
+		if err != nil {
+			t.Fatalf("%s: NewFromJSON failed: %v", name, err)
+		}
+		gobData, _ := machine.SaveSettingsToGob()
+		fromGob, err := NewFromGob(gobData)
+		if err != nil {
+			t.Fatalf("%s: NewFromGob failed: %v", name, err)
+		}
+		protoData, _ := machine.SaveSettingsToProto()
+		fromProto, err := NewFromProto(protoData)
+		if err != nil {
+			t.Fatalf("%s: NewFromProto failed: %v", name, err)
+		}
+
+		for format, m := range map[string]*Enigma{"json": fromJSON, "gob": fromGob, "proto": fromProto} {
+			got, _ := m.SaveSettingsToJSON()
+			if !jsonEqual(t, got, wantJSON) {
+				t.Errorf("%s via %s: settings differ\n got %s\nwant %s", name, format, got, wantJSON)
+			}
+			if fp, _ := m.Fingerprint(); fp != wantFP {
+				t.Errorf("%s via %s: fingerprint %s, want %s", name, format, fp, wantFP)
+			}
+		}
+	}
+}
+
+func jsonEqual(t *testing.T, a, b string) bool {
+	t.Helper()
+	var va, vb interface{}
+	if err := json.Unmarshal([]byte(a), &va); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if err := json.Unmarshal([]byte(b), &vb); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	ja, _ := json.Marshal(va)
+	jb, _ := json.Marshal(vb)
+	return string(ja) == string(jb)
+}
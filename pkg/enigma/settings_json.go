@@ -0,0 +1,398 @@
+//go:build !tinygo
+
+// Package enigma provides JSON serialization of Enigma settings.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/coredds/enigoma/internal/reflector"
+	"github.com/coredds/enigoma/internal/rotor"
+)
+
+// MarshalJSON marshals the EnigmaSettings to JSON. Settings at schema version
+// 2 write every field explicitly; older versions use the version 1 layout,
+// which leaves fields at their defaults out.
+func (s *EnigmaSettings) MarshalJSON() ([]byte, error) {
+	if s.SchemaVersion < 2 {
+		return s.marshalJSONV1()
+	}
+
+	type jsonSettings struct {
+		SchemaVersion         int                      `json:"schema_version"`
+		Alphabet              string                   `json:"alphabet"`
+		Symbols               []string                 `json:"symbols,omitempty"`
+		RotorSpecs            []rotor.RotorSpec        `json:"rotor_specs"`
+		ReflectorSpec         *reflector.ReflectorSpec `json:"reflector_spec,omitempty"`
+		Reflectorless         bool                     `json:"reflectorless"`
+		SteppingMode          string                   `json:"stepping_mode"`
+		EntryWheel            string                   `json:"entry_wheel"`
+		PlugboardPairs        map[string]string        `json:"plugboard_pairs"`
+		PlugboardDisabled     bool                     `json:"plugboard_disabled"`
+		UhrEnabled            bool                     `json:"uhr_enabled"`
+		UhrPosition           int                      `json:"uhr_position"`
+		UnknownCharPolicy     string                   `json:"unknown_char_policy"`
+		Normalization         string                   `json:"normalization,omitempty"`
+		CaseFolding           bool                     `json:"case_folding,omitempty"`
+		CurrentRotorPositions []int                    `json:"current_rotor_positions"`
+		Metadata              *Metadata                `json:"metadata,omitempty"`
+	}
+
+	js := jsonSettings{
+		SchemaVersion:         s.SchemaVersion,
+		Alphabet:              string(s.Alphabet),
+		Symbols:               s.Symbols,
+		RotorSpecs:            s.RotorSpecs,
+		Reflectorless:         s.Reflectorless,
+		SteppingMode:          s.SteppingMode.String(),
+		EntryWheel:            s.EntryWheel,
+		PlugboardPairs:        plugboardPairsToJSON(s.PlugboardPairs),
+		PlugboardDisabled:     s.PlugboardDisabled,
+		UhrEnabled:            s.Uhr,
+		UnknownCharPolicy:     s.UnknownCharPolicy.String(),
+		CaseFolding:           s.CaseFolding,
+		CurrentRotorPositions: s.CurrentRotorPositions,
+		Metadata:              s.Metadata,
+	}
+	if s.Uhr {
+		js.UhrPosition = s.UhrPosition
+	}
+	if s.Normalization != NormalizationNone {
+		js.Normalization = s.Normalization.String()
+	}
+
+	// A reflector-less machine has no reflector to describe
+	if !s.Reflectorless {
+		spec := s.ReflectorSpec
+		js.ReflectorSpec = &spec
+	}
+
+	return json.Marshal(js)
+}
+
+// marshalJSONV1 writes the schema version 1 layout, which fingerprints keep
+// using so that they do not change with the schema.
+func (s *EnigmaSettings) marshalJSONV1() ([]byte, error) {
+	if s.UnknownCharPolicy != UnknownCharError {
+		return nil, fmt.Errorf("the unknown character policy needs schema version 2")
+	}
+	if s.Normalization != NormalizationNone {
+		return nil, fmt.Errorf("the normalization form needs schema version 2")
+	}
+	if s.CaseFolding {
+		return nil, fmt.Errorf("case folding needs schema version 2")
+	}
+
+	// Convert runes to strings for JSON compatibility
+	type jsonSettings struct {
+		SchemaVersion         int                      `json:"schema_version"`
+		Alphabet              string                   `json:"alphabet"`
+		Symbols               []string                 `json:"symbols,omitempty"`
+		RotorSpecs            []rotor.RotorSpec        `json:"rotor_specs"`
+		ReflectorSpec         *reflector.ReflectorSpec `json:"reflector_spec,omitempty"`
+		Reflectorless         bool                     `json:"reflectorless,omitempty"`
+		SteppingMode          string                   `json:"stepping_mode,omitempty"`
+		EntryWheel            string                   `json:"entry_wheel,omitempty"`
+		PlugboardPairs        map[string]string        `json:"plugboard_pairs"`
+		UhrPosition           *int                     `json:"uhr_position,omitempty"`
+		PlugboardDisabled     bool                     `json:"plugboard_disabled,omitempty"`
+		CurrentRotorPositions []int                    `json:"current_rotor_positions"`
+		Metadata              *Metadata                `json:"metadata,omitempty"`
+	}
+
+	js := jsonSettings{
+		SchemaVersion:         s.SchemaVersion,
+		Alphabet:              string(s.Alphabet),
+		Symbols:               s.Symbols,
+		RotorSpecs:            s.RotorSpecs,
+		Reflectorless:         s.Reflectorless,
+		EntryWheel:            s.EntryWheel,
+		PlugboardDisabled:     s.PlugboardDisabled,
+		CurrentRotorPositions: s.CurrentRotorPositions,
+		PlugboardPairs:        plugboardPairsToJSON(s.PlugboardPairs),
+		Metadata:              s.Metadata,
+	}
+
+	// Position 0 is written too: the field's presence attaches the Uhr
+	if s.Uhr {
+		position := s.UhrPosition
+		js.UhrPosition = &position
+	}
+
+	// The default lever mechanism is left out, so older keys are unchanged
+	if s.SteppingMode != SteppingLever {
+		js.SteppingMode = s.SteppingMode.String()
+	}
+
+	// A reflector-less machine has no reflector to describe
+	if !s.Reflectorless {
+		spec := s.ReflectorSpec
+		js.ReflectorSpec = &spec
+	}
+
+	return json.Marshal(js)
+}
+
+// plugboardPairsToJSON converts rune pairs to string pairs.
+func plugboardPairsToJSON(pairs map[rune]rune) map[string]string {
+	out := make(map[string]string, len(pairs))
+	for k, v := range pairs {
+		out[string(k)] = string(v)
+	}
+	return out
+}
+
+// UnmarshalJSON unmarshals JSON to EnigmaSettings. Settings written with an
+// older schema version are upgraded to CurrentSchemaVersion.
+func (s *EnigmaSettings) UnmarshalJSON(data []byte) error {
+	// The union of all schema versions; later fields are checked below
+	type jsonSettings struct {
+		SchemaVersion         int                      `json:"schema_version"`
+		Alphabet              string                   `json:"alphabet"`
+		Symbols               []string                 `json:"symbols,omitempty"`
+		RotorSpecs            []rotor.RotorSpec        `json:"rotor_specs"`
+		ReflectorSpec         *reflector.ReflectorSpec `json:"reflector_spec,omitempty"`
+		Reflectorless         bool                     `json:"reflectorless,omitempty"`
+		SteppingMode          string                   `json:"stepping_mode,omitempty"`
+		EntryWheel            string                   `json:"entry_wheel,omitempty"`
+		PlugboardPairs        map[string]string        `json:"plugboard_pairs"`
+		UhrEnabled            *bool                    `json:"uhr_enabled,omitempty"`
+		UhrPosition           *int                     `json:"uhr_position,omitempty"`
+		PlugboardDisabled     bool                     `json:"plugboard_disabled,omitempty"`
+		UnknownCharPolicy     *string                  `json:"unknown_char_policy,omitempty"`
+		Normalization         *string                  `json:"normalization,omitempty"`
+		CaseFolding           *bool                    `json:"case_folding,omitempty"`
+		CurrentRotorPositions []int                    `json:"current_rotor_positions"`
+		Metadata              *Metadata                `json:"metadata,omitempty"`
+	}
+
+	var js jsonSettings
+	if err := json.Unmarshal(data, &js); err != nil {
+		return err
+	}
+
+	// Check schema version
+	if js.SchemaVersion < 1 || js.SchemaVersion > CurrentSchemaVersion {
+		return fmt.Errorf("unsupported schema version: %d (expected 1 to %d)", js.SchemaVersion, CurrentSchemaVersion)
+	}
+	if js.SchemaVersion < 2 && (js.UhrEnabled != nil || js.UnknownCharPolicy != nil || js.Normalization != nil || js.CaseFolding != nil) {
+		return fmt.Errorf("uhr_enabled, unknown_char_policy, normalization and case_folding need schema version 2")
+	}
+
+	decoded := EnigmaSettings{
+		SchemaVersion:         js.SchemaVersion,
+		Alphabet:              []rune(js.Alphabet),
+		Symbols:               js.Symbols,
+		RotorSpecs:            js.RotorSpecs,
+		Reflectorless:         js.Reflectorless,
+		EntryWheel:            js.EntryWheel,
+		PlugboardDisabled:     js.PlugboardDisabled,
+		CurrentRotorPositions: js.CurrentRotorPositions,
+		Metadata:              js.Metadata,
+		PlugboardPairs:        make(map[rune]rune),
+	}
+	if js.ReflectorSpec != nil {
+		decoded.ReflectorSpec = *js.ReflectorSpec
+	}
+	mode, err := ParseSteppingMode(js.SteppingMode)
+	if err != nil {
+		return err
+	}
+	decoded.SteppingMode = mode
+
+	// Version 1 attaches the Uhr by the position's presence, version 2 by uhr_enabled
+	if js.UhrEnabled != nil {
+		decoded.Uhr = *js.UhrEnabled
+	} else {
+		decoded.Uhr = js.UhrPosition != nil
+	}
+	if decoded.Uhr && js.UhrPosition != nil {
+		decoded.UhrPosition = *js.UhrPosition
+	}
+	if js.UnknownCharPolicy != nil {
+		policy, err := ParseUnknownCharPolicy(*js.UnknownCharPolicy)
+		if err != nil {
+			return err
+		}
+		decoded.UnknownCharPolicy = policy
+	}
+	if js.Normalization != nil {
+		n, err := ParseNormalization(*js.Normalization)
+		if err != nil {
+			return err
+		}
+		decoded.Normalization = n
+	}
+	if js.CaseFolding != nil {
+		decoded.CaseFolding = *js.CaseFolding
+	}
+
+	// Convert string pairs back to rune pairs
+	for k, v := range js.PlugboardPairs {
+		if utf8.RuneCountInString(k) != 1 || utf8.RuneCountInString(v) != 1 {
+			return fmt.Errorf("invalid plugboard pair: %s->%s", k, v)
+		}
+		kRune := []rune(k)[0]
+		vRune := []rune(v)[0]
+		decoded.PlugboardPairs[kRune] = vRune
+	}
+
+	if err := migrateSettings(&decoded); err != nil {
+		return err
+	}
+	*s = decoded
+	return nil
+}
+
+// SaveSettingsToJSON saves the current Enigma settings to a JSON string.
+func (e *Enigma) SaveSettingsToJSON() (string, error) {
+	settings, err := e.GetSettings()
+	if err != nil {
+		return "", fmt.Errorf("failed to get settings: %v", err)
+	}
+
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal settings: %v", err)
+	}
+
+	return string(data), nil
+}
+
+// LoadSettingsFromJSON loads Enigma settings from a JSON string, within
+// DefaultLimits.
+func (e *Enigma) LoadSettingsFromJSON(jsonData string) error {
+	settings, err := decodeSettingsJSON([]byte(jsonData), DefaultLimits.withDefaults())
+	if err != nil {
+		return err
+	}
+
+	return e.LoadSettings(settings)
+}
+
+// NewFromJSON creates a new Enigma machine from JSON settings, within
+// DefaultLimits.
+func NewFromJSON(jsonData string) (*Enigma, error) {
+	return NewFromJSONWithLimits(jsonData, DefaultLimits)
+}
+
+// NewFromJSONWithLimits creates a new Enigma machine from JSON settings,
+// rejecting configurations beyond limits with a *LimitError before any
+// component is built.
+func NewFromJSONWithLimits(jsonData string, limits Limits) (*Enigma, error) {
+	settings, err := decodeSettingsJSON([]byte(jsonData), limits.withDefaults())
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFromSettings(settings)
+}
+
+// NewFromJSONReader reads JSON settings from r and creates a machine, for
+// configurations fetched over the network or from object storage. At most
+// limits.MaxConfigBytes are read. When ctx is done first the call returns
+// ctx's error; r is left to finish in the background, so give network
+// readers their own deadline too.
+func NewFromJSONReader(ctx context.Context, r io.Reader, limits Limits) (*Enigma, error) {
+	limits = limits.withDefaults()
+
+	type result struct {
+		data []byte
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		data, err := readLimited(r, limits.MaxConfigBytes)
+		done <- result{data, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("failed to read settings: %w", ctx.Err())
+	case res := <-done:
+		if res.err != nil {
+			return nil, res.err
+		}
+		settings, err := decodeSettingsJSON(res.data, limits)
+		if err != nil {
+			return nil, err
+		}
+		return NewFromSettings(settings)
+	}
+}
+
+// readLimited reads r to the end, failing with a *LimitError once more than
+// max bytes arrive. A negative max reads everything.
+func readLimited(r io.Reader, max int) ([]byte, error) {
+	if max < 0 {
+		return io.ReadAll(r)
+	}
+	data, err := io.ReadAll(io.LimitReader(r, int64(max)+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read settings: %v", err)
+	}
+	if len(data) > max {
+		return nil, &LimitError{Limit: "config size", Value: len(data), Max: max}
+	}
+	return data, nil
+}
+
+// decodeSettingsJSON checks the size and nesting of data against limits,
+// decodes it and checks the decoded settings. Limit violations are returned
+// unwrapped so callers can match them with errors.As.
+func decodeSettingsJSON(data []byte, limits Limits) (*EnigmaSettings, error) {
+	if err := exceeds("config size", len(data), limits.MaxConfigBytes); err != nil {
+		return nil, err
+	}
+	if err := checkJSONDepth(data, limits.MaxDepth); err != nil {
+		return nil, err
+	}
+
+	var settings EnigmaSettings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal settings: %v", err)
+	}
+	if err := limits.checkSettings(&settings); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// checkJSONDepth scans data for objects and arrays nested deeper than max,
+// without decoding it. Brackets inside strings are ignored.
+func checkJSONDepth(data []byte, max int) error {
+	if max < 0 {
+		return nil
+	}
+	depth := 0
+	inString, escaped := false, false
+	for _, c := range data {
+		switch {
+		case escaped:
+			escaped = false
+		case inString:
+			if c == '\\' {
+				escaped = true
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '{' || c == '[':
+			depth++
+			if depth > max {
+				return &LimitError{Limit: "nesting depth", Value: depth, Max: max}
+			}
+		case c == '}' || c == ']':
+			depth--
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+// Package enigma provides upgrades of settings written with older schemas.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import "fmt"
+
+// settingsMigrations upgrade decoded settings one schema version at a time:
+// the entry for version v turns v into v+1.
+var settingsMigrations = map[int]func(*EnigmaSettings) error{
+	1: migrateSettingsV1,
+}
+
+// migrateSettingsV1 upgrades schema 1 to 2. Version 2 writes the stepping
+// mode, entry wheel, Uhr and plugboard fields explicitly and adds the
+// unknown character policy; version 1 left them out at their defaults, which
+// decode to the same values, so only the policy needs its default.
+func migrateSettingsV1(s *EnigmaSettings) error {
+	s.UnknownCharPolicy = UnknownCharError
+	return nil
+}
+
+// migrateSettings upgrades s to CurrentSchemaVersion, remembering the version
+// it was written with.
+func migrateSettings(s *EnigmaSettings) error {
+	if s.SchemaVersion < 1 || s.SchemaVersion > CurrentSchemaVersion {
+		return fmt.Errorf("unsupported schema version: %d (expected 1 to %d)", s.SchemaVersion, CurrentSchemaVersion)
+	}
+	from := s.SchemaVersion
+	for s.SchemaVersion < CurrentSchemaVersion {
+		if err := settingsMigrations[s.SchemaVersion](s); err != nil {
+			return fmt.Errorf("failed to upgrade settings from schema version %d: %v", s.SchemaVersion, err)
+		}
+		s.SchemaVersion++
+	}
+	if from < CurrentSchemaVersion {
+		s.migratedFrom = from
+	}
+	return nil
+}
+
+// MigratedFromSchema returns the schema version the machine's settings were
+// upgraded from when they were loaded, or 0 when they were already current.
+// Saving the settings writes CurrentSchemaVersion.
+func (e *Enigma) MigratedFromSchema() int {
+	return e.migratedFrom
+}
@@ -0,0 +1,130 @@
+//go:build !tinygo
+
+package enigma
+
+import (
+	"strings"
+	"testing"
+)
+
+// v1JSON returns machine's settings in the schema version 1 layout.
+func v1JSON(t *testing.T, machine *Enigma) string {
+	t.Helper()
+	settings, err := machine.GetSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings.SchemaVersion = 1
+	data, err := settings.marshalJSONV1()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestSettingsMigrationFromV1(t *testing.T) {
+	original, err := NewEnigmaM4()
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy := v1JSON(t, original)
+	if strings.Contains(legacy, "stepping_mode") || strings.Contains(legacy, "unknown_char_policy") {
+		t.Fatalf("v1 JSON should leave the defaults out:\n%s", legacy)
+	}
+
+	migrated, err := NewFromJSON(legacy)
+	if err != nil {
+		t.Fatalf("loading v1 settings failed: %v", err)
+	}
+	if got := migrated.MigratedFromSchema(); got != 1 {
+		t.Errorf("MigratedFromSchema() = %d, want 1", got)
+	}
+	if migrated.GetUnknownCharPolicy() != UnknownCharError {
+		t.Error("v1 settings should get the default unknown character policy")
+	}
+	want, _ := original.Encrypt("MIGRATIONKEEPSTHEKEY")
+	if got, _ := migrated.Encrypt("MIGRATIONKEEPSTHEKEY"); got != want {
+		t.Errorf("migrated machine encrypts to %q, want %q", got, want)
+	}
+	if a, b := original.Capabilities(), migrated.Capabilities(); a.SchemaVersion != b.SchemaVersion {
+		t.Errorf("migrated schema version %d, want %d", b.SchemaVersion, a.SchemaVersion)
+	}
+
+	// Saving writes the current version, which loads without a migration
+	upgraded, err := migrated.SaveSettingsToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(upgraded, `"schema_version": 2`) || !strings.Contains(upgraded, `"unknown_char_policy": "error"`) {
+		t.Errorf("saved settings should use schema version 2:\n%s", upgraded)
+	}
+	current, err := NewFromJSON(upgraded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.MigratedFromSchema() != 0 {
+		t.Error("current settings should not report a migration")
+	}
+}
+
+func TestSettingsMigrationRejects(t *testing.T) {
+	machine, err := NewEnigmaClassic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy := v1JSON(t, machine)
+	for name, doc := range map[string]string{
+		"future version":    strings.Replace(legacy, `"schema_version":1`, `"schema_version":3`, 1),
+		"v2 field in v1":    strings.Replace(legacy, `"schema_version":1`, `"schema_version":1,"unknown_char_policy":"pass"`, 1),
+		"uhr_enabled in v1": strings.Replace(legacy, `"schema_version":1`, `"schema_version":1,"uhr_enabled":true`, 1),
+	} {
+		if doc == legacy {
+			t.Fatalf("%s: replacement did not apply", name)
+		}
+		if _, err := NewFromJSON(doc); err == nil {
+			t.Errorf("%s should be rejected", name)
+		}
+	}
+}
+
+func TestFingerprintStableAcrossSchemas(t *testing.T) {
+	machine, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatal(err)
+	}
+	migrated, err := NewFromJSON(v1JSON(t, machine))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := machine.Fingerprint()
+	if got, _ := migrated.Fingerprint(); got != want {
+		t.Error("the schema upgrade should not change the fingerprint")
+	}
+	if err := WithUnknownCharPolicy(UnknownCharPass)(migrated); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := migrated.Fingerprint(); got != want {
+		t.Error("the unknown character policy should not change the fingerprint")
+	}
+}
+
+func TestSettingsProtoUnknownCharPolicy(t *testing.T) {
+	machine, err := NewEnigmaClassic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := WithUnknownCharPolicy(UnknownCharSkip)(machine); err != nil {
+		t.Fatal(err)
+	}
+	data, err := machine.SaveSettingsToProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored, err := NewFromProto(data)
+	if err != nil {
+		t.Fatalf("NewFromProto failed: %v", err)
+	}
+	if restored.GetUnknownCharPolicy() != UnknownCharSkip {
+		t.Errorf("policy = %s, want skip", restored.GetUnknownCharPolicy())
+	}
+}
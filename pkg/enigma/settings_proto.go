@@ -0,0 +1,517 @@
+// Package enigma provides Protocol Buffers serialization of Enigma settings.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/coredds/enigoma/internal/provenance"
+	"github.com/coredds/enigoma/internal/reflector"
+	"github.com/coredds/enigoma/internal/rotor"
+)
+
+// Protocol Buffers wire types.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// MarshalProto encodes the settings as the EnigmaSettings message defined in
+// schemas/settings.v1.proto. Plugboard pairs are written in sorted order, so
+// equal settings always encode to the same bytes.
+func (s *EnigmaSettings) MarshalProto() ([]byte, error) {
+	var b []byte
+	b = appendVarintField(b, 1, uint64(int64(s.SchemaVersion)))
+	b = appendStringField(b, 2, string(s.Alphabet))
+	for _, spec := range s.RotorSpecs {
+		b = appendBytesField(b, 3, marshalRotorSpec(spec))
+	}
+	if s.Reflectorless {
+		b = appendVarintField(b, 5, 1)
+	} else {
+		b = appendBytesField(b, 4, marshalReflectorSpec(s.ReflectorSpec))
+	}
+
+	keys := make([]rune, 0, len(s.PlugboardPairs))
+	for k := range s.PlugboardPairs {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	for _, k := range keys {
+		var entry []byte
+		entry = appendStringField(entry, 1, string(k))
+		entry = appendStringField(entry, 2, string(s.PlugboardPairs[k]))
+		b = appendBytesField(b, 6, entry)
+	}
+
+	if len(s.CurrentRotorPositions) > 0 {
+		var packed []byte
+		for _, p := range s.CurrentRotorPositions {
+			packed = binary.AppendUvarint(packed, uint64(int64(p)))
+		}
+		b = appendBytesField(b, 7, packed)
+	}
+	if s.Metadata != nil {
+		b = appendBytesField(b, 8, marshalMetadata(s.Metadata))
+	}
+	if s.SteppingMode != SteppingLever {
+		b = appendStringField(b, 9, s.SteppingMode.String())
+	}
+	if s.EntryWheel != "" {
+		b = appendStringField(b, 10, s.EntryWheel)
+	}
+	if s.Uhr {
+		// Written even at position 0, where appendVarintField would omit it:
+		// presence means an Uhr is attached
+		b = binary.AppendUvarint(b, 11<<3|wireVarint)
+		b = binary.AppendUvarint(b, uint64(int64(s.UhrPosition)))
+	}
+	if s.PlugboardDisabled {
+		b = appendVarintField(b, 12, 1)
+	}
+	if s.UnknownCharPolicy != UnknownCharError {
+		if s.SchemaVersion < 2 {
+			return nil, fmt.Errorf("the unknown character policy needs schema version 2")
+		}
+		b = appendStringField(b, 13, s.UnknownCharPolicy.String())
+	}
+	for _, symbol := range s.Symbols {
+		b = appendStringField(b, 14, symbol)
+	}
+	if s.Normalization != NormalizationNone {
+		if s.SchemaVersion < 2 {
+			return nil, fmt.Errorf("the normalization form needs schema version 2")
+		}
+		b = appendStringField(b, 15, s.Normalization.String())
+	}
+	if s.CaseFolding {
+		if s.SchemaVersion < 2 {
+			return nil, fmt.Errorf("case folding needs schema version 2")
+		}
+		b = appendVarintField(b, 16, 1)
+	}
+	return b, nil
+}
+
+// UnmarshalProto decodes an EnigmaSettings message. Unknown fields are
+// skipped so newer writers stay readable, and settings written with an older
+// schema version are upgraded to CurrentSchemaVersion.
+func (s *EnigmaSettings) UnmarshalProto(data []byte) error {
+	decoded := EnigmaSettings{PlugboardPairs: make(map[rune]rune)}
+	var reflectorSet, policySet, normalizationSet, caseFoldingSet bool
+
+	err := walkProto(data, func(field, wire int, v uint64, raw []byte) error {
+		switch field {
+		case 1:
+			decoded.SchemaVersion = int(int32(v))
+		case 2:
+			decoded.Alphabet = []rune(string(raw))
+		case 3:
+			spec, err := unmarshalRotorSpec(raw)
+			if err != nil {
+				return fmt.Errorf("rotor spec %d: %v", len(decoded.RotorSpecs)+1, err)
+			}
+			decoded.RotorSpecs = append(decoded.RotorSpecs, spec)
+		case 4:
+			spec, err := unmarshalReflectorSpec(raw)
+			if err != nil {
+				return fmt.Errorf("reflector spec: %v", err)
+			}
+			decoded.ReflectorSpec = spec
+			reflectorSet = true
+		case 5:
+			decoded.Reflectorless = v != 0
+		case 6:
+			k, val, err := unmarshalPlugboardEntry(raw)
+			if err != nil {
+				return err
+			}
+			decoded.PlugboardPairs[k] = val
+		case 7:
+			if wire == wireVarint {
+				decoded.CurrentRotorPositions = append(decoded.CurrentRotorPositions, int(int32(v)))
+				return nil
+			}
+			for len(raw) > 0 {
+				p, n := binary.Uvarint(raw)
+				if n <= 0 {
+					return fmt.Errorf("malformed rotor positions")
+				}
+				decoded.CurrentRotorPositions = append(decoded.CurrentRotorPositions, int(int32(p)))
+				raw = raw[n:]
+			}
+		case 8:
+			m, err := unmarshalMetadata(raw)
+			if err != nil {
+				return fmt.Errorf("metadata: %v", err)
+			}
+			decoded.Metadata = m
+		case 9:
+			mode, err := ParseSteppingMode(string(raw))
+			if err != nil {
+				return err
+			}
+			decoded.SteppingMode = mode
+		case 10:
+			decoded.EntryWheel = string(raw)
+		case 11:
+			decoded.Uhr = true
+			decoded.UhrPosition = int(int32(v))
+		case 12:
+			decoded.PlugboardDisabled = v != 0
+		case 13:
+			policy, err := ParseUnknownCharPolicy(string(raw))
+			if err != nil {
+				return err
+			}
+			decoded.UnknownCharPolicy = policy
+			policySet = true
+		case 14:
+			decoded.Symbols = append(decoded.Symbols, string(raw))
+		case 15:
+			n, err := ParseNormalization(string(raw))
+			if err != nil {
+				return err
+			}
+			decoded.Normalization = n
+			normalizationSet = true
+		case 16:
+			decoded.CaseFolding = v != 0
+			caseFoldingSet = true
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("invalid settings message: %v", err)
+	}
+
+	if policySet && decoded.SchemaVersion < 2 {
+		return fmt.Errorf("invalid settings message: unknown_char_policy needs schema version 2")
+	}
+	if normalizationSet && decoded.SchemaVersion < 2 {
+		return fmt.Errorf("invalid settings message: normalization needs schema version 2")
+	}
+	if caseFoldingSet && decoded.SchemaVersion < 2 {
+		return fmt.Errorf("invalid settings message: case_folding needs schema version 2")
+	}
+	if err := migrateSettings(&decoded); err != nil {
+		return err
+	}
+	if decoded.Reflectorless && reflectorSet {
+		return fmt.Errorf("invalid settings message: reflectorless settings cannot have a reflector spec")
+	}
+
+	*s = decoded
+	return nil
+}
+
+// SaveSettingsToProto saves the current Enigma settings as a Protocol Buffers message.
+func (e *Enigma) SaveSettingsToProto() ([]byte, error) {
+	settings, err := e.GetSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settings: %v", err)
+	}
+	return settings.MarshalProto()
+}
+
+// NewFromProto creates a new Enigma machine from a Protocol Buffers settings message.
+func NewFromProto(data []byte) (*Enigma, error) {
+	var settings EnigmaSettings
+	if err := settings.UnmarshalProto(data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal settings: %v", err)
+	}
+	return NewFromSettings(&settings)
+}
+
+func marshalRotorSpec(spec rotor.RotorSpec) []byte {
+	var b []byte
+	b = appendStringField(b, 1, spec.ID)
+	b = appendStringField(b, 2, spec.ForwardMapping)
+	b = appendStringField(b, 3, string(spec.Notches))
+	b = appendVarintField(b, 4, uint64(int64(spec.Position)))
+	b = appendVarintField(b, 5, uint64(int64(spec.RingSetting)))
+	if !spec.Provenance.IsZero() {
+		b = appendBytesField(b, 6, marshalProvenance(spec.Provenance))
+	}
+	return b
+}
+
+func unmarshalRotorSpec(data []byte) (rotor.RotorSpec, error) {
+	var spec rotor.RotorSpec
+	err := walkProto(data, func(field, wire int, v uint64, raw []byte) error {
+		switch field {
+		case 1:
+			spec.ID = string(raw)
+		case 2:
+			spec.ForwardMapping = string(raw)
+		case 3:
+			spec.Notches = []rune(string(raw))
+		case 4:
+			spec.Position = int(int32(v))
+		case 5:
+			spec.RingSetting = int(int32(v))
+		case 6:
+			p, err := unmarshalProvenance(raw)
+			if err != nil {
+				return fmt.Errorf("provenance: %v", err)
+			}
+			spec.Provenance = p
+		}
+		return nil
+	})
+	return spec, err
+}
+
+func marshalReflectorSpec(spec reflector.ReflectorSpec) []byte {
+	var b []byte
+	b = appendStringField(b, 1, spec.ID)
+	b = appendStringField(b, 2, spec.Mapping)
+	if !spec.Provenance.IsZero() {
+		b = appendBytesField(b, 3, marshalProvenance(spec.Provenance))
+	}
+	if spec.Rewirable {
+		b = appendVarintField(b, 4, 1)
+	}
+	if spec.Rotating {
+		b = appendVarintField(b, 5, 1)
+	}
+	if spec.Position != 0 {
+		b = appendVarintField(b, 6, uint64(int64(spec.Position)))
+	}
+	if spec.Settable {
+		b = appendVarintField(b, 7, 1)
+	}
+	if spec.FixedPoints != 0 {
+		b = appendVarintField(b, 8, uint64(int64(spec.FixedPoints)))
+	}
+	return b
+}
+
+func unmarshalReflectorSpec(data []byte) (reflector.ReflectorSpec, error) {
+	var spec reflector.ReflectorSpec
+	err := walkProto(data, func(field, wire int, v uint64, raw []byte) error {
+		switch field {
+		case 1:
+			spec.ID = string(raw)
+		case 2:
+			spec.Mapping = string(raw)
+		case 3:
+			p, err := unmarshalProvenance(raw)
+			if err != nil {
+				return fmt.Errorf("provenance: %v", err)
+			}
+			spec.Provenance = p
+		case 4:
+			spec.Rewirable = v != 0
+		case 5:
+			spec.Rotating = v != 0
+		case 6:
+			spec.Position = int(int32(v))
+		case 7:
+			spec.Settable = v != 0
+		case 8:
+			spec.FixedPoints = int(int32(v))
+		}
+		return nil
+	})
+	return spec, err
+}
+
+func marshalProvenance(p *provenance.Provenance) []byte {
+	var b []byte
+	b = appendStringField(b, 1, p.Source)
+	b = appendStringField(b, 2, p.Creator)
+	b = appendStringField(b, 3, p.CreatedAt)
+	return b
+}
+
+func unmarshalProvenance(data []byte) (*provenance.Provenance, error) {
+	p := &provenance.Provenance{}
+	err := walkProto(data, func(field, wire int, v uint64, raw []byte) error {
+		switch field {
+		case 1:
+			p.Source = string(raw)
+		case 2:
+			p.Creator = string(raw)
+		case 3:
+			p.CreatedAt = string(raw)
+		}
+		return nil
+	})
+	return p, err
+}
+
+func unmarshalPlugboardEntry(data []byte) (rune, rune, error) {
+	var key, value string
+	err := walkProto(data, func(field, wire int, v uint64, raw []byte) error {
+		switch field {
+		case 1:
+			key = string(raw)
+		case 2:
+			value = string(raw)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	if utf8.RuneCountInString(key) != 1 || utf8.RuneCountInString(value) != 1 {
+		return 0, 0, fmt.Errorf("invalid plugboard pair: %s->%s", key, value)
+	}
+	k, _ := utf8.DecodeRuneInString(key)
+	val, _ := utf8.DecodeRuneInString(value)
+	return k, val, nil
+}
+
+func marshalMetadata(m *Metadata) []byte {
+	var b []byte
+	b = appendStringField(b, 1, m.CreatedAt)
+	b = appendStringField(b, 2, m.CreatedBy)
+	b = appendStringField(b, 3, m.Description)
+	b = appendStringField(b, 4, m.Preset)
+	for _, tag := range m.Tags {
+		b = appendBytesField(b, 5, []byte(tag))
+	}
+	b = appendStringField(b, 6, m.ExpiresAt)
+	b = appendStringField(b, 7, m.AlphabetOrdering)
+	if sig := m.Signature; sig != nil {
+		var sb []byte
+		sb = appendStringField(sb, 1, sig.Algorithm)
+		sb = appendStringField(sb, 2, sig.KeyID)
+		sb = appendStringField(sb, 3, sig.PublicKey)
+		sb = appendStringField(sb, 4, sig.Value)
+		b = appendBytesField(b, 8, sb)
+	}
+	return b
+}
+
+func unmarshalMetadata(data []byte) (*Metadata, error) {
+	m := &Metadata{}
+	err := walkProto(data, func(field, wire int, v uint64, raw []byte) error {
+		switch field {
+		case 1:
+			m.CreatedAt = string(raw)
+		case 2:
+			m.CreatedBy = string(raw)
+		case 3:
+			m.Description = string(raw)
+		case 4:
+			m.Preset = string(raw)
+		case 5:
+			m.Tags = append(m.Tags, string(raw))
+		case 6:
+			m.ExpiresAt = string(raw)
+		case 7:
+			m.AlphabetOrdering = string(raw)
+		case 8:
+			sig, err := unmarshalSignature(raw)
+			if err != nil {
+				return fmt.Errorf("signature: %v", err)
+			}
+			m.Signature = sig
+		}
+		return nil
+	})
+	return m, err
+}
+
+func unmarshalSignature(data []byte) (*Signature, error) {
+	sig := &Signature{}
+	err := walkProto(data, func(field, wire int, v uint64, raw []byte) error {
+		switch field {
+		case 1:
+			sig.Algorithm = string(raw)
+		case 2:
+			sig.KeyID = string(raw)
+		case 3:
+			sig.PublicKey = string(raw)
+		case 4:
+			sig.Value = string(raw)
+		}
+		return nil
+	})
+	return sig, err
+}
+
+// appendVarintField appends a varint field, omitting the proto3 default of zero.
+func appendVarintField(b []byte, field int, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = binary.AppendUvarint(b, uint64(field)<<3|wireVarint)
+	return binary.AppendUvarint(b, v)
+}
+
+// appendStringField appends a string field, omitting the proto3 default of "".
+func appendStringField(b []byte, field int, s string) []byte {
+	if s == "" {
+		return b
+	}
+	return appendBytesField(b, field, []byte(s))
+}
+
+// appendBytesField appends a length-delimited field, even when empty.
+func appendBytesField(b []byte, field int, data []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(field)<<3|wireBytes)
+	b = binary.AppendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+// walkProto calls fn for each field in a message. Varint fields carry their
+// value in v, length-delimited fields their payload in raw; fixed-width
+// fields are skipped.
+func walkProto(data []byte, fn func(field, wire int, v uint64, raw []byte) error) error {
+	for len(data) > 0 {
+		tag, n := binary.Uvarint(data)
+		if n <= 0 {
+			return fmt.Errorf("malformed field tag")
+		}
+		data = data[n:]
+		field, wire := int(tag>>3), int(tag&7)
+		if field == 0 {
+			return fmt.Errorf("invalid field number 0")
+		}
+
+		switch wire {
+		case wireVarint:
+			v, n := binary.Uvarint(data)
+			if n <= 0 {
+				return fmt.Errorf("malformed varint in field %d", field)
+			}
+			data = data[n:]
+			if err := fn(field, wire, v, nil); err != nil {
+				return err
+			}
+		case wireBytes:
+			size, n := binary.Uvarint(data)
+			if n <= 0 || size > uint64(len(data)-n) {
+				return fmt.Errorf("truncated field %d", field)
+			}
+			raw := data[n : n+int(size)]
+			data = data[n+int(size):]
+			if err := fn(field, wire, 0, raw); err != nil {
+				return err
+			}
+		case wireFixed64:
+			if len(data) < 8 {
+				return fmt.Errorf("truncated field %d", field)
+			}
+			data = data[8:]
+		case wireFixed32:
+			if len(data) < 4 {
+				return fmt.Errorf("truncated field %d", field)
+			}
+			data = data[4:]
+		default:
+			return fmt.Errorf("unsupported wire type %d in field %d", wire, field)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package enigma
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/coredds/enigoma/internal/reflector"
+	"github.com/coredds/enigoma/internal/rotor"
+)
+
+func TestSettingsProtoWireFormat(t *testing.T) {
+	settings := &EnigmaSettings{
+		SchemaVersion:         1,
+		Alphabet:              []rune("AB"),
+		RotorSpecs:            []rotor.RotorSpec{{ID: "I", ForwardMapping: "BA", Notches: []rune("A"), Position: 1}},
+		ReflectorSpec:         reflector.ReflectorSpec{ID: "R", Mapping: "BA"},
+		PlugboardPairs:        map[rune]rune{},
+		CurrentRotorPositions: []int{1},
+	}
+	data, err := settings.MarshalProto()
+	if err != nil {
+		t.Fatalf("MarshalProto failed: %v", err)
+	}
+
+	// Bytes as protoc-generated code would write them for schemas/settings.v1.proto
+	want := []byte{
+		0x08, 0x01, // schema_version = 1
+		0x12, 0x02, 'A', 'B', // alphabet = "AB"
+		0x1a, 0x0c, // rotor_specs, 12 bytes
+		0x0a, 0x01, 'I', // id
+		0x12, 0x02, 'B', 'A', // forward_mapping
+		0x1a, 0x01, 'A', // notches
+		0x20, 0x01, // position = 1
+		0x22, 0x07, // reflector_spec, 7 bytes
+		0x0a, 0x01, 'R',
+		0x12, 0x02, 'B', 'A',
+		0x3a, 0x01, 0x01, // current_rotor_positions, packed
+	}
+	if !bytes.Equal(data, want) {
+		t.Errorf("unexpected encoding\n got % x\nwant % x", data, want)
+	}
+}
+
+func TestSettingsProtoRoundTrip(t *testing.T) {
+	machine, err := New(
+		WithAlphabet([]rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")),
+		WithRandomSettings(Medium),
+		WithMetadata(&Metadata{CreatedBy: "test", Tags: []string{"a", "b"}, ExpiresAt: "2030-01-01T00:00:00Z"}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create machine: %v", err)
+	}
+	original, _ := machine.GetSettings()
+
+	data, err := machine.SaveSettingsToProto()
+	if err != nil {
+		t.Fatalf("SaveSettingsToProto failed: %v", err)
+	}
+	again, _ := original.MarshalProto()
+	if !bytes.Equal(data, again) {
+		t.Error("encoding is not deterministic")
+	}
+
+	var decoded EnigmaSettings
+	if err := decoded.UnmarshalProto(data); err != nil {
+		t.Fatalf("UnmarshalProto failed: %v", err)
+	}
+	if !reflect.DeepEqual(&decoded, original) {
+		t.Errorf("round trip changed settings\n got %+v\nwant %+v", decoded, *original)
+	}
+
+	restored, err := NewFromProto(data)
+	if err != nil {
+		t.Fatalf("NewFromProto failed: %v", err)
+	}
+	a, _ := machine.Encrypt("HELLOWORLD")
+	b, _ := restored.Encrypt("HELLOWORLD")
+	if a != b {
+		t.Errorf("restored machine encrypts differently: %s vs %s", a, b)
+	}
+}
+
+func TestSettingsProtoReflectorless(t *testing.T) {
+	machine, err := New(WithAlphabet([]rune("ABCDE")), WithoutReflector(), WithRandomSettings(Low))
+	if err != nil {
+		t.Fatalf("failed to create machine: %v", err)
+	}
+	data, _ := machine.SaveSettingsToProto()
+	restored, err := NewFromProto(data)
+	if err != nil {
+		t.Fatalf("NewFromProto failed: %v", err)
+	}
+	if !restored.IsReflectorless() {
+		t.Error("reflectorless flag was lost")
+	}
+}
+
+func TestSettingsProtoRejectsInvalid(t *testing.T) {
+	for name, data := range map[string][]byte{
+		"wrong schema":    {0x08, 0x03},
+		"missing schema":  {0x12, 0x01, 'A'},
+		"truncated":       {0x08, 0x01, 0x12, 0x05, 'A'},
+		"bad plugboard":   {0x08, 0x01, 0x32, 0x06, 0x0a, 0x02, 'A', 'B', 0x12, 0x00},
+		"zero field":      {0x00, 0x01},
+		"bad wire type":   {0x0b},
+		"reflector clash": {0x08, 0x01, 0x22, 0x00, 0x28, 0x01},
+	} {
+		var s EnigmaSettings
+		if err := s.UnmarshalProto(data); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	// Unknown fields are skipped
+	var s EnigmaSettings
+	if err := s.UnmarshalProto([]byte{0x08, 0x01, 0xf8, 0x01, 0x05, 0x85, 0x01, 1, 2, 3, 4}); err != nil {
+		t.Errorf("unknown fields should be skipped: %v", err)
+	}
+}
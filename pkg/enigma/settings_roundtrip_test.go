@@ -1,6 +1,10 @@
+//go:build !tinygo
+
 package enigma
 
 import (
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -35,3 +39,399 @@ func TestSettingsJSONRoundTrip(t *testing.T) {
 		t.Fatalf("rotor count mismatch: %d vs %d", machine2.GetRotorCount(), machine.GetRotorCount())
 	}
 }
+
+func TestMetadataRoundTrip(t *testing.T) {
+	meta := &Metadata{
+		Description: "quarterly key",
+		CreatedAt:   "2025-01-01T00:00:00Z",
+		ExpiresAt:   "2025-04-01T00:00:00Z",
+		Tags:        []string{"ops"},
+	}
+
+	machine, err := New(WithAlphabet([]rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")), WithRandomSettings(Low), WithMetadata(meta))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	// Mutating the caller's copy must not affect the machine
+	meta.Tags[0] = "changed"
+
+	data, err := machine.SaveSettingsToJSON()
+	if err != nil {
+		t.Fatalf("SaveSettingsToJSON failed: %v", err)
+	}
+
+	restored, err := NewFromJSON(data)
+	if err != nil {
+		t.Fatalf("NewFromJSON failed: %v", err)
+	}
+
+	got := restored.GetMetadata()
+	if got == nil {
+		t.Fatal("metadata lost in round trip")
+	}
+	if got.ExpiresAt != "2025-04-01T00:00:00Z" || got.Description != "quarterly key" {
+		t.Errorf("unexpected metadata after round trip: %+v", got)
+	}
+	if len(got.Tags) != 1 || got.Tags[0] != "ops" {
+		t.Errorf("tags should be copied defensively, got %v", got.Tags)
+	}
+}
+
+func TestReflectorlessJSONRoundTrip(t *testing.T) {
+	machine, err := New(WithAlphabet([]rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")), WithoutReflector(), WithRandomSettings(Medium))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	data, err := machine.SaveSettingsToJSON()
+	if err != nil {
+		t.Fatalf("SaveSettingsToJSON failed: %v", err)
+	}
+	if !strings.Contains(data, `"reflectorless": true`) {
+		t.Errorf("JSON should mark the machine reflector-less:\n%s", data)
+	}
+	if strings.Contains(data, "reflector_spec") {
+		t.Errorf("JSON should not carry an unused reflector spec:\n%s", data)
+	}
+
+	restored, err := NewFromJSON(data)
+	if err != nil {
+		t.Fatalf("NewFromJSON failed: %v", err)
+	}
+	if !restored.IsReflectorless() {
+		t.Fatal("restored machine lost the reflector-less mode")
+	}
+
+	encrypted, err := machine.Encrypt("ROUNDTRIPWITHOUTREFLECTOR")
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	decrypted, err := restored.Decrypt(encrypted)
+	if err != nil || decrypted != "ROUNDTRIPWITHOUTREFLECTOR" {
+		t.Errorf("restored machine decrypted to %q, %v", decrypted, err)
+	}
+
+	// Classic machines keep their reflector and write the flag as false
+	classic, _ := NewEnigmaClassic()
+	classicJSON, _ := classic.SaveSettingsToJSON()
+	if !strings.Contains(classicJSON, `"reflectorless": false`) || !strings.Contains(classicJSON, "reflector_spec") {
+		t.Errorf("classic JSON should keep its reflector:\n%s", classicJSON)
+	}
+}
+
+func TestSettingsJSONSteppingMode(t *testing.T) {
+	machine, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatal(err)
+	}
+	leverJSON, _ := machine.SaveSettingsToJSON()
+	if !strings.Contains(leverJSON, `"stepping_mode": "lever"`) {
+		t.Error("schema version 2 should write the default lever mode")
+	}
+	leverPrint, _ := machine.Fingerprint()
+
+	if err := WithSteppingMode(SteppingGear)(machine); err != nil {
+		t.Fatal(err)
+	}
+	gearJSON, err := machine.SaveSettingsToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(gearJSON, `"stepping_mode": "gear"`) {
+		t.Errorf("gear mode missing from JSON: %s", gearJSON)
+	}
+	if gearPrint, _ := machine.Fingerprint(); gearPrint == leverPrint {
+		t.Error("the stepping mode should change the fingerprint")
+	}
+
+	restored, err := NewFromJSON(gearJSON)
+	if err != nil {
+		t.Fatalf("NewFromJSON failed: %v", err)
+	}
+	if restored.GetSteppingMode() != SteppingGear {
+		t.Errorf("JSON round trip lost the stepping mode: %s", restored.GetSteppingMode())
+	}
+
+	if _, err := NewFromJSON(strings.Replace(gearJSON, `"gear"`, `"cog"`, 1)); err == nil {
+		t.Error("expected an error for an unknown stepping mode")
+	}
+}
+
+func TestSettingsJSONEnigmaG(t *testing.T) {
+	machine, err := NewEnigmaG()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := machine.Encrypt(strings.Repeat("X", 40)); err != nil {
+		t.Fatal(err)
+	}
+	jsonData, err := machine.SaveSettingsToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{`"entry_wheel": "QWERTZUIOASDFGHJKPYXCVBNML"`, `"rotating": true`, `"stepping_mode": "gear"`} {
+		if !strings.Contains(jsonData, field) {
+			t.Errorf("JSON is missing %s", field)
+		}
+	}
+
+	restored, err := NewFromJSON(jsonData)
+	if err != nil {
+		t.Fatalf("NewFromJSON failed: %v", err)
+	}
+	want, _ := machine.GetReflectorPosition()
+	if got, _ := restored.GetReflectorPosition(); got != want {
+		t.Errorf("reflector position = %d, want %d", got, want)
+	}
+	a, _ := machine.Encrypt("ABWEHR")
+	b, _ := restored.Encrypt("ABWEHR")
+	if a != b {
+		t.Errorf("restored machine encrypts to %s, want %s", b, a)
+	}
+}
+
+// settingsFormats are the encodings settings can be saved in, each as a save
+// followed by a load.
+var settingsFormats = []struct {
+	name      string
+	roundTrip func(*Enigma) (*Enigma, error)
+}{
+	{"json", func(m *Enigma) (*Enigma, error) {
+		data, err := m.SaveSettingsToJSON()
+		if err != nil {
+			return nil, err
+		}
+		return NewFromJSON(data)
+	}},
+	{"proto", func(m *Enigma) (*Enigma, error) {
+		data, err := m.SaveSettingsToProto()
+		if err != nil {
+			return nil, err
+		}
+		return NewFromProto(data)
+	}},
+	{"gob", func(m *Enigma) (*Enigma, error) {
+		data, err := m.SaveSettingsToGob()
+		if err != nil {
+			return nil, err
+		}
+		return NewFromGob(data)
+	}},
+	{"yaml", func(m *Enigma) (*Enigma, error) {
+		data, err := m.SaveSettingsToYAML()
+		if err != nil {
+			return nil, err
+		}
+		return NewFromYAML(data)
+	}},
+	{"toml", func(m *Enigma) (*Enigma, error) {
+		data, err := m.SaveSettingsToTOML()
+		if err != nil {
+			return nil, err
+		}
+		return NewFromTOML(data)
+	}},
+	{"encrypted", func(m *Enigma) (*Enigma, error) {
+		data, err := m.SaveSettingsEncrypted("round trip")
+		if err != nil {
+			return nil, err
+		}
+		return NewFromEncryptedJSON(data, "round trip")
+	}},
+}
+
+// TestOptionSettingsRoundTrip saves a machine built with each option in
+// every format and checks that the loaded machine has the option and
+// encrypts exactly like the original.
+func TestOptionSettingsRoundTrip(t *testing.T) {
+	tests := []struct {
+		name      string
+		build     func(t *testing.T) *Enigma
+		jsonField string                             // Recorded in the JSON settings
+		text      string                             // Must encrypt the same after loading
+		check     func(t *testing.T, loaded *Enigma) // Option-specific state, if any
+	}{
+		{
+			name: "byte alphabet",
+			build: func(t *testing.T) *Enigma {
+				machine, err := NewByteEnigma(Low)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return machine
+			},
+			text: "\x00\u00ffPNG\r\n\x1a\n",
+			check: func(t *testing.T, loaded *Enigma) {
+				if !loaded.IsByteMode() {
+					t.Error("the loaded machine should be in byte mode")
+				}
+			},
+		},
+		{
+			name: "case folding",
+			build: func(t *testing.T) *Enigma {
+				machine, err := New(WithAlphabet([]rune("ABCDEFGH")), WithRandomSettings(Low), WithCaseFolding())
+				if err != nil {
+					t.Fatal(err)
+				}
+				return machine
+			},
+			jsonField: `"case_folding": true`,
+			text:      "abcDEF",
+			check: func(t *testing.T, loaded *Enigma) {
+				if !loaded.IsCaseFolding() {
+					t.Error("case folding should survive")
+				}
+			},
+		},
+		{
+			name: "reflector fixed points",
+			build: func(t *testing.T) *Enigma {
+				machine, err := New(WithAlphabet([]rune("ABCDEFGHI")), WithReflectorFixedPoints(3), WithRandomSettings(Low))
+				if err != nil {
+					t.Fatal(err)
+				}
+				return machine
+			},
+			jsonField: `"fixed_points": 3`,
+			text:      "HIDEABCFG",
+			check: func(t *testing.T, loaded *Enigma) {
+				if n := loaded.ReflectorFixedPoints(); n != 3 {
+					t.Errorf("ReflectorFixedPoints() = %d, want 3", n)
+				}
+			},
+		},
+		{
+			name: "grapheme alphabet",
+			build: func(t *testing.T) *Enigma {
+				return newGraphemeMachine(t, WithUnknownCharPolicy(UnknownCharPass))
+			},
+			jsonField: `"symbols"`,
+			text:      "BAD \U0001f1e9\U0001f1ea n\u0303 \u2764\ufe0f!",
+			check: func(t *testing.T, loaded *Enigma) {
+				if got := loaded.GetSymbols(); !slices.Equal(got, graphemeSymbols) {
+					t.Errorf("symbols = %q, want %q", got, graphemeSymbols)
+				}
+			},
+		},
+		{
+			name:      "without plugboard",
+			build:     newNoPlugboardMachine,
+			jsonField: `"plugboard_disabled": true`,
+			text:      "NOPLUGBOARD",
+			check: func(t *testing.T, loaded *Enigma) {
+				if !loaded.IsPlugboardDisabled() {
+					t.Error("the plugboard should stay disabled")
+				}
+			},
+		},
+		{
+			name: "normalization",
+			build: func(t *testing.T) *Enigma {
+				machine, err := New(WithNormalization(NormalizationNFC), WithAlphabet([]rune("ABCDE\u0301F")), WithRandomSettings(Low))
+				if err != nil {
+					t.Fatal(err)
+				}
+				return machine
+			},
+			jsonField: `"normalization": "nfc"`,
+			text:      "CAFE\u0301",
+			check: func(t *testing.T, loaded *Enigma) {
+				if got := loaded.GetNormalization(); got != NormalizationNFC {
+					t.Errorf("normalization = %v, want nfc", got)
+				}
+			},
+		},
+		{
+			name:      "uhr",
+			build:     func(t *testing.T) *Enigma { return newUhrM3(t, 0) },
+			jsonField: `"uhr_position"`,
+			text:      "ANXKAPITAENLEUTNANT",
+			check: func(t *testing.T, loaded *Enigma) {
+				if !loaded.HasUhr() {
+					t.Error("the Uhr should survive")
+				}
+			},
+		},
+		{
+			name:      "uhr turned",
+			build:     func(t *testing.T) *Enigma { return newUhrM3(t, 27) },
+			jsonField: `"uhr_position": 27`,
+			text:      "ANXKAPITAENLEUTNANT",
+		},
+		{
+			name: "reflectorless",
+			build: func(t *testing.T) *Enigma {
+				machine, err := New(WithAlphabet([]rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")), WithoutReflector(), WithRandomSettings(Medium))
+				if err != nil {
+					t.Fatal(err)
+				}
+				return machine
+			},
+			jsonField: `"reflectorless": true`,
+			text:      "ROUNDTRIPWITHOUTREFLECTOR",
+			check: func(t *testing.T, loaded *Enigma) {
+				if !loaded.IsReflectorless() {
+					t.Error("the machine should stay reflector-less")
+				}
+			},
+		},
+		{
+			name: "enigma g",
+			build: func(t *testing.T) *Enigma {
+				machine, err := NewEnigmaG()
+				if err != nil {
+					t.Fatal(err)
+				}
+				return machine
+			},
+			jsonField: `"stepping_mode": "gear"`,
+			text:      strings.Repeat("ABWEHR", 10),
+		},
+	}
+	for _, tt := range tests {
+		machine := tt.build(t)
+		if tt.jsonField != "" {
+			data, err := machine.SaveSettingsToJSON()
+			if err != nil {
+				t.Fatalf("%s: %v", tt.name, err)
+			}
+			if !strings.Contains(data, tt.jsonField) {
+				t.Errorf("%s: the JSON settings should contain %s:\n%s", tt.name, tt.jsonField, data)
+			}
+		}
+		fingerprint, err := machine.Fingerprint()
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		for _, format := range settingsFormats {
+			t.Run(tt.name+"/"+format.name, func(t *testing.T) {
+				loaded, err := format.roundTrip(machine)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got, err := loaded.Fingerprint(); err != nil || got != fingerprint {
+					t.Errorf("fingerprint = %s, %v; want %s", got, err, fingerprint)
+				}
+				if tt.text != "" {
+					original, err := machine.Clone()
+					if err != nil {
+						t.Fatal(err)
+					}
+					want, err := original.Encrypt(tt.text)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if got, err := loaded.Encrypt(tt.text); err != nil || got != want {
+						t.Errorf("Encrypt = %q, %v; want %q", got, err, want)
+					}
+				}
+				if tt.check != nil {
+					tt.check(t, loaded)
+				}
+			})
+		}
+	}
+}
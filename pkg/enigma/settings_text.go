@@ -0,0 +1,132 @@
+//go:build !tinygo
+
+// Package enigma provides YAML and TOML serialization of Enigma settings.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/coredds/enigoma/internal/textconfig"
+)
+
+// The methods below are named Encode and Decode rather than MarshalYAML or
+// MarshalTOML so that they do not collide with the Marshaler interfaces of
+// third-party YAML and TOML packages, which expect different signatures.
+// Both formats carry exactly the fields of the JSON encoding, under the same
+// names and in the same order.
+
+// EncodeYAML encodes the settings as a YAML document.
+func (s *EnigmaSettings) EncodeYAML() ([]byte, error) {
+	n, err := s.textNode()
+	if err != nil {
+		return nil, err
+	}
+	return n.YAML(), nil
+}
+
+// DecodeYAML decodes settings from a YAML document.
+func (s *EnigmaSettings) DecodeYAML(data []byte) error {
+	decoded, err := decodeSettingsText(data, "YAML", textconfig.FromYAML, DefaultLimits)
+	if err != nil {
+		return err
+	}
+	*s = *decoded
+	return nil
+}
+
+// EncodeTOML encodes the settings as a TOML document. Rotors become an array
+// of [[rotor_specs]] tables.
+func (s *EnigmaSettings) EncodeTOML() ([]byte, error) {
+	n, err := s.textNode()
+	if err != nil {
+		return nil, err
+	}
+	return n.TOML()
+}
+
+// DecodeTOML decodes settings from a TOML document.
+func (s *EnigmaSettings) DecodeTOML(data []byte) error {
+	decoded, err := decodeSettingsText(data, "TOML", textconfig.FromTOML, DefaultLimits)
+	if err != nil {
+		return err
+	}
+	*s = *decoded
+	return nil
+}
+
+// SaveSettingsToYAML saves the current Enigma settings as YAML.
+func (e *Enigma) SaveSettingsToYAML() (string, error) {
+	settings, err := e.GetSettings()
+	if err != nil {
+		return "", fmt.Errorf("failed to get settings: %v", err)
+	}
+	data, err := settings.EncodeYAML()
+	return string(data), err
+}
+
+// SaveSettingsToTOML saves the current Enigma settings as TOML.
+func (e *Enigma) SaveSettingsToTOML() (string, error) {
+	settings, err := e.GetSettings()
+	if err != nil {
+		return "", fmt.Errorf("failed to get settings: %v", err)
+	}
+	data, err := settings.EncodeTOML()
+	return string(data), err
+}
+
+// NewFromYAML creates a new Enigma machine from YAML settings.
+func NewFromYAML(data string) (*Enigma, error) {
+	return NewFromYAMLWithLimits(data, DefaultLimits)
+}
+
+// NewFromYAMLWithLimits creates a new Enigma machine from YAML settings
+// within limits, as NewFromJSONWithLimits does for JSON.
+func NewFromYAMLWithLimits(data string, limits Limits) (*Enigma, error) {
+	settings, err := decodeSettingsText([]byte(data), "YAML", textconfig.FromYAML, limits)
+	if err != nil {
+		return nil, err
+	}
+	return NewFromSettings(settings)
+}
+
+// NewFromTOML creates a new Enigma machine from TOML settings.
+func NewFromTOML(data string) (*Enigma, error) {
+	return NewFromTOMLWithLimits(data, DefaultLimits)
+}
+
+// NewFromTOMLWithLimits creates a new Enigma machine from TOML settings
+// within limits, as NewFromJSONWithLimits does for JSON.
+func NewFromTOMLWithLimits(data string, limits Limits) (*Enigma, error) {
+	settings, err := decodeSettingsText([]byte(data), "TOML", textconfig.FromTOML, limits)
+	if err != nil {
+		return nil, err
+	}
+	return NewFromSettings(settings)
+}
+
+// textNode returns the JSON encoding of the settings as a document tree.
+func (s *EnigmaSettings) textNode() (*textconfig.Node, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal settings: %v", err)
+	}
+	return textconfig.FromJSON(data)
+}
+
+// decodeSettingsText parses a YAML or TOML document within limits and decodes
+// it through the JSON decoder, so both formats get the same validation.
+func decodeSettingsText(data []byte, format string, parse func([]byte, int) (*textconfig.Node, error), limits Limits) (*EnigmaSettings, error) {
+	limits = limits.withDefaults()
+	if err := exceeds("config size", len(data), limits.MaxConfigBytes); err != nil {
+		return nil, err
+	}
+	n, err := parse(data, limits.MaxDepth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s settings: %v", format, err)
+	}
+	return decodeSettingsJSON(n.JSON(), limits)
+}
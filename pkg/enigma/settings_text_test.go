@@ -0,0 +1,115 @@
+//go:build !tinygo
+
+package enigma
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// textFormatMachines covers the optional parts of the settings.
+func textFormatMachines(t *testing.T) map[string]*Enigma {
+	t.Helper()
+	machines := map[string]*Enigma{}
+	add := func(name string, m *Enigma, err error) {
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		machines[name] = m
+	}
+	m, err := NewEnigmaM3()
+	add("m3", m, err)
+	m, err = NewEnigmaM4()
+	add("m4", m, err)
+	m, err = NewEnigmaG()
+	add("g", m, err)
+	machines["uhr"] = newUhrM3(t, 7)
+	m, err = New(WithAlphabet([]rune("αβγδεζ \"#:")), WithoutReflector(), WithoutPlugboard(), WithRandomSettings(Medium),
+		WithMetadata(&Metadata{Description: "key: #1", Tags: []string{"yes", "a b"}}))
+	add("unicode", m, err)
+	return machines
+}
+
+func TestSettingsYAMLRoundTrip(t *testing.T) {
+	for name, machine := range textFormatMachines(t) {
+		doc, err := machine.SaveSettingsToYAML()
+		if err != nil {
+			t.Fatalf("%s: SaveSettingsToYAML failed: %v", name, err)
+		}
+		loaded, err := NewFromYAML(doc)
+		if err != nil {
+			t.Fatalf("%s: NewFromYAML failed: %v\n%s", name, err, doc)
+		}
+		want, _ := machine.Fingerprint()
+		if got, _ := loaded.Fingerprint(); got != want {
+			t.Errorf("%s: YAML round trip changed the key:\n%s", name, doc)
+		}
+	}
+}
+
+func TestSettingsTOMLRoundTrip(t *testing.T) {
+	for name, machine := range textFormatMachines(t) {
+		doc, err := machine.SaveSettingsToTOML()
+		if err != nil {
+			t.Fatalf("%s: SaveSettingsToTOML failed: %v", name, err)
+		}
+		loaded, err := NewFromTOML(doc)
+		if err != nil {
+			t.Fatalf("%s: NewFromTOML failed: %v\n%s", name, err, doc)
+		}
+		want, _ := machine.Fingerprint()
+		if got, _ := loaded.Fingerprint(); got != want {
+			t.Errorf("%s: TOML round trip changed the key:\n%s", name, doc)
+		}
+	}
+}
+
+func TestSettingsTextDecode(t *testing.T) {
+	machine, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings, err := machine.GetSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	yamlDoc, err := settings.EncodeYAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(yamlDoc), "schema_version: 2\nalphabet: ABCDEFGHIJKLMNOPQRSTUVWXYZ\n") {
+		t.Errorf("YAML should follow the JSON field order:\n%s", yamlDoc)
+	}
+	var fromYAML EnigmaSettings
+	if err := fromYAML.DecodeYAML(yamlDoc); err != nil {
+		t.Fatalf("DecodeYAML failed: %v", err)
+	}
+
+	tomlDoc, err := settings.EncodeTOML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(tomlDoc), "[[rotor_specs]]") {
+		t.Errorf("rotors should be an array of tables:\n%s", tomlDoc)
+	}
+	var fromTOML EnigmaSettings
+	if err := fromTOML.DecodeTOML(tomlDoc); err != nil {
+		t.Fatalf("DecodeTOML failed: %v", err)
+	}
+	if string(fromYAML.Alphabet) != string(settings.Alphabet) || len(fromTOML.RotorSpecs) != len(settings.RotorSpecs) {
+		t.Error("decoded settings differ from the original")
+	}
+
+	if err := fromYAML.DecodeYAML([]byte("alphabet: [1, 2")); err == nil {
+		t.Error("malformed YAML should fail")
+	}
+	if _, err := NewFromTOML("schema_version = 1\nalphabet = \"AB\"\nrotor_specs = []\n"); err == nil {
+		t.Error("settings without rotors should be rejected like JSON ones")
+	}
+	_, err = NewFromYAMLWithLimits(string(yamlDoc), Limits{MaxConfigBytes: 16})
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) {
+		t.Errorf("expected a *LimitError, got %v", err)
+	}
+}
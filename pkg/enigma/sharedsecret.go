@@ -0,0 +1,104 @@
+// Package enigma provides deterministic key derivation from seeds and shared secrets.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/coredds/enigoma/internal/random"
+)
+
+// sharedSecretIterations is the PBKDF2 work factor for NewFromSharedSecret
+// and NewFromPassphrase. Changing it changes every derived machine.
+const sharedSecretIterations = 100000
+
+// NewFromSharedSecret derives a machine from a shared secret and a date, so two
+// parties who know the secret get the same machine each day without exchanging
+// key files. Only the UTC calendar day of date is used. The secret and day are
+// stretched with PBKDF2-HMAC-SHA256 and the result seeds a deterministic
+// generator that builds every component for the security level.
+//
+// The machine uses the uppercase Latin alphabet unless opts override it
+// (for example with WithAlphabet); both parties must pass the same options.
+func NewFromSharedSecret(secret string, date time.Time, level SecurityLevel, opts ...Option) (*Enigma, error) {
+	if secret == "" {
+		return nil, fmt.Errorf("shared secret cannot be empty")
+	}
+
+	day := date.UTC().Format("2006-01-02")
+	seed := pbkdf2SHA256([]byte(secret), []byte("enigoma/shared-secret/v1/"+day), sharedSecretIterations)
+
+	return NewFromSeed(seed, level, opts...)
+}
+
+// NewFromPassphrase derives a machine from a passphrase, so two parties who
+// share it can build identical machines without exchanging a key file. Every
+// random choice (rotor wirings, ring settings and positions, reflector and
+// plugboard) comes from a generator seeded by PBKDF2-HMAC-SHA256 over the
+// passphrase and the alphabet; the same passphrase, alphabet and level always
+// yield the same machine. A nil alphabet means uppercase Latin.
+//
+// Unlike NewFromSharedSecret the machine does not change from day to day, so
+// the passphrase is the key itself and should be as long as one.
+func NewFromPassphrase(passphrase string, alphabet []rune, level SecurityLevel) (*Enigma, error) {
+	if passphrase == "" {
+		return nil, fmt.Errorf("passphrase cannot be empty")
+	}
+
+	if alphabet == nil {
+		alphabet = latinUpper
+	}
+	seed := pbkdf2SHA256([]byte(passphrase), []byte("enigoma/passphrase/v1/"+string(alphabet)), sharedSecretIterations)
+
+	return NewFromSeed(seed, level, WithAlphabet(alphabet))
+}
+
+// NewFromSeed builds a machine for the security level from a deterministic
+// generator seeded with seed: the same seed, level and options always yield
+// the same machine. The seed should carry enough entropy to be a key on its
+// own (for example 32 bytes from a KDF or from mixed contributions).
+//
+// The machine uses the uppercase Latin alphabet unless opts override it.
+func NewFromSeed(seed []byte, level SecurityLevel, opts ...Option) (*Enigma, error) {
+	if len(seed) == 0 {
+		return nil, fmt.Errorf("seed cannot be empty")
+	}
+
+	all := append([]Option{WithAlphabet(latinUpper)}, opts...)
+	all = append(all, withSettingsFrom(level, random.NewDeterministic(seed)))
+	return New(all...)
+}
+
+// latinUpper is the default alphabet of derived machines.
+var latinUpper = []rune{
+	'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M',
+	'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z',
+}
+
+// pbkdf2SHA256 derives a single 32-byte PBKDF2 block (RFC 8018) using HMAC-SHA256.
+func pbkdf2SHA256(password, salt []byte, iterations int) []byte {
+	prf := hmac.New(sha256.New, password)
+	var block [4]byte
+	binary.BigEndian.PutUint32(block[:], 1)
+	prf.Write(salt)
+	prf.Write(block[:])
+	u := prf.Sum(nil)
+
+	key := make([]byte, len(u))
+	copy(key, u)
+	for i := 1; i < iterations; i++ {
+		prf.Reset()
+		prf.Write(u)
+		u = prf.Sum(u[:0])
+		for j := range key {
+			key[j] ^= u[j]
+		}
+	}
+	return key
+}
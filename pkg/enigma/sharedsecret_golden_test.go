@@ -0,0 +1,75 @@
+//go:build !tinygo
+
+package enigma
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// goldenPlaintext is long enough to step the middle rotors of every level.
+const goldenPlaintext = "THEQUICKBROWNFOXJUMPSOVERTHELAZYDOGATTACKATDAWNHOLDTHELINE"
+
+// TestDerivedMachinesAreStable pins the machines derived from a fixed secret
+// and passphrase. Other implementations, and keys derived by earlier
+// versions, depend on the exact derivation: any change to the KDF salt, the
+// generator or the order components are drawn in fails here. If a change is
+// intended, it needs a new salt version, not new golden values.
+func TestDerivedMachinesAreStable(t *testing.T) {
+	day := time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		build       func() (*Enigma, error)
+		fingerprint string
+		positions   []int
+		ciphertext  string
+	}{
+		{
+			"shared secret low",
+			func() (*Enigma, error) { return NewFromSharedSecret("correct horse", day, Low) },
+			"31aaa45a3619f1919cd978fc18846a9f3726a83c954b1d5d31ffcdf6b2b06d03",
+			[]int{1, 15, 7},
+			"BRANMLQSYBMUXQNNMNIYEKZPLKXBQKQNEDPZKMEMAIUOZATVCHQOGFDXPB",
+		},
+		{
+			"shared secret high",
+			func() (*Enigma, error) { return NewFromSharedSecret("correct horse", day, High) },
+			"f428d5b1abc542150eb37da6f7253b1908fcfd544064b1af7611f1a51866ee3c",
+			[]int{1, 15, 7, 25, 22, 4, 9, 7},
+			"MNTZAYJCIWTGQJBJVXWLWKSHWYRNNRSWGNJGYKNKHVVYFVRNLBXFDLBAHY",
+		},
+		{
+			"passphrase low",
+			func() (*Enigma, error) { return NewFromPassphrase("correct horse battery staple", nil, Low) },
+			"2dd29133c7784ddca79677666c7456d5f3f44931e36a0ec096fbb7d5ce8768cb",
+			[]int{12, 2, 19},
+			"LBXBBJBZUVXHZBPNPCWFLETCEUYLIXLIXXIBGNPLFXYKCKUMLEPFKPTCWM",
+		},
+		{
+			"passphrase high",
+			func() (*Enigma, error) { return NewFromPassphrase("correct horse battery staple", nil, High) },
+			"d4e32d699f6c9ccf713a21542a78106b08ee4956f2ae6047eb3a9c0c7fff6cf0",
+			[]int{12, 2, 19, 16, 20, 17, 12, 16},
+			"EEOEBNFSYBIZTLPKWHYFJWTHTENAMVGMUIETESFVBJHGHOERFJNPATIAED",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			machine, err := tt.build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			// The fingerprint covers the whole derived configuration
+			if got, err := machine.Fingerprint(); err != nil || got != tt.fingerprint {
+				t.Errorf("fingerprint = %s, %v; want %s", got, err, tt.fingerprint)
+			}
+			if got := machine.GetCurrentRotorPositions(); !slices.Equal(got, tt.positions) {
+				t.Errorf("positions = %v, want %v", got, tt.positions)
+			}
+			if got, err := machine.Encrypt(goldenPlaintext); err != nil || got != tt.ciphertext {
+				t.Errorf("ciphertext = %q, %v; want %q", got, err, tt.ciphertext)
+			}
+		})
+	}
+}
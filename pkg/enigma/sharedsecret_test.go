@@ -0,0 +1,150 @@
+package enigma
+
+import (
+	"encoding/hex"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPBKDF2SHA256Vectors(t *testing.T) {
+	tests := []struct {
+		password, salt string
+		iterations     int
+		want           string
+	}{
+		{"passwd", "salt", 1, "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc"},
+		{"password", "salt", 4096, "c5e478d59288c841aa530db6845c4c8d962893a001ce4e11a4963873aa98134a"},
+	}
+	for _, tt := range tests {
+		got := hex.EncodeToString(pbkdf2SHA256([]byte(tt.password), []byte(tt.salt), tt.iterations))
+		if got != tt.want {
+			t.Errorf("pbkdf2(%q, %q, %d) = %s, want %s", tt.password, tt.salt, tt.iterations, got, tt.want)
+		}
+	}
+}
+
+func TestNewFromSharedSecret(t *testing.T) {
+	morning := time.Date(2025, 3, 14, 6, 0, 0, 0, time.UTC)
+	evening := time.Date(2025, 3, 14, 22, 30, 0, 0, time.UTC)
+
+	alice, err := NewFromSharedSecret("correct horse", morning, Medium)
+	if err != nil {
+		t.Fatalf("NewFromSharedSecret failed: %v", err)
+	}
+	bob, err := NewFromSharedSecret("correct horse", evening, Medium)
+	if err != nil {
+		t.Fatalf("NewFromSharedSecret failed: %v", err)
+	}
+
+	aliceSettings, _ := alice.GetSettings()
+	bobSettings, _ := bob.GetSettings()
+	if !reflect.DeepEqual(aliceSettings, bobSettings) {
+		t.Fatal("same secret and UTC day should derive identical machines")
+	}
+	if alice.GetRotorCount() != 5 {
+		t.Errorf("medium level should have 5 rotors, got %d", alice.GetRotorCount())
+	}
+
+	ciphertext, err := alice.Encrypt("ATTACKATDAWN")
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	plaintext, err := bob.Decrypt(ciphertext)
+	if err != nil || plaintext != "ATTACKATDAWN" {
+		t.Errorf("Decrypt = %q, %v", plaintext, err)
+	}
+
+	// The UTC day counts, not the local one
+	local := time.Date(2025, 3, 14, 23, 0, 0, 0, time.FixedZone("UTC-5", -5*3600))
+	nextDay, _ := NewFromSharedSecret("correct horse", local, Medium)
+	otherSecret, _ := NewFromSharedSecret("battery staple", morning, Medium)
+	for name, m := range map[string]*Enigma{"next UTC day": nextDay, "other secret": otherSecret} {
+		settings, _ := m.GetSettings()
+		if reflect.DeepEqual(settings, aliceSettings) {
+			t.Errorf("%s should derive a different machine", name)
+		}
+	}
+
+	if _, err := NewFromSharedSecret("", morning, Low); err == nil {
+		t.Error("empty secret should be rejected")
+	}
+}
+
+func TestNewFromSharedSecretWithAlphabet(t *testing.T) {
+	day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	m, err := NewFromSharedSecret("s3cret", day, Low, WithAlphabet([]rune("abcdefghij")))
+	if err != nil {
+		t.Fatalf("NewFromSharedSecret failed: %v", err)
+	}
+	if m.GetAlphabetSize() != 10 {
+		t.Errorf("alphabet option should override the default, got size %d", m.GetAlphabetSize())
+	}
+}
+
+func TestNewFromSeed(t *testing.T) {
+	a, err := NewFromSeed([]byte("0123456789abcdef0123456789abcdef"), High)
+	if err != nil {
+		t.Fatalf("NewFromSeed failed: %v", err)
+	}
+	b, _ := NewFromSeed([]byte("0123456789abcdef0123456789abcdef"), High)
+	c, _ := NewFromSeed([]byte("fedcba9876543210fedcba9876543210"), High)
+
+	sa, _ := a.GetSettings()
+	sb, _ := b.GetSettings()
+	sc, _ := c.GetSettings()
+	if !reflect.DeepEqual(sa, sb) {
+		t.Error("identical seeds should build identical machines")
+	}
+	if reflect.DeepEqual(sa, sc) {
+		t.Error("different seeds should build different machines")
+	}
+
+	if _, err := NewFromSeed(nil, Low); err == nil {
+		t.Error("empty seed should be rejected")
+	}
+}
+
+func TestNewFromPassphrase(t *testing.T) {
+	latin := []rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")
+	alice, err := NewFromPassphrase("correct horse battery staple", nil, High)
+	if err != nil {
+		t.Fatalf("NewFromPassphrase failed: %v", err)
+	}
+	bob, err := NewFromPassphrase("correct horse battery staple", latin, High)
+	if err != nil {
+		t.Fatalf("NewFromPassphrase failed: %v", err)
+	}
+
+	// A nil alphabet is the uppercase Latin one
+	aliceSettings, _ := alice.GetSettings()
+	bobSettings, _ := bob.GetSettings()
+	if !reflect.DeepEqual(aliceSettings, bobSettings) {
+		t.Fatal("the same passphrase should derive identical machines")
+	}
+	ciphertext, _ := alice.Encrypt("ATTACKATDAWN")
+	if plaintext, err := bob.Decrypt(ciphertext); err != nil || plaintext != "ATTACKATDAWN" {
+		t.Errorf("Decrypt = %q, %v", plaintext, err)
+	}
+
+	otherPassphrase, _ := NewFromPassphrase("correct horse battery stapler", latin, High)
+	otherLevel, _ := NewFromPassphrase("correct horse battery staple", latin, Low)
+	otherAlphabet, _ := NewFromPassphrase("correct horse battery staple", []rune("ZYXWVUTSRQPONMLKJIHGFEDCBA"), High)
+	// A shared secret with the same text is a different kind of key
+	daily, _ := NewFromSharedSecret("correct horse battery staple", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), High)
+	for name, m := range map[string]*Enigma{
+		"other passphrase": otherPassphrase,
+		"other level":      otherLevel,
+		"other alphabet":   otherAlphabet,
+		"shared secret":    daily,
+	} {
+		settings, _ := m.GetSettings()
+		if reflect.DeepEqual(settings.RotorSpecs, aliceSettings.RotorSpecs) {
+			t.Errorf("%s should derive a different machine", name)
+		}
+	}
+
+	if _, err := NewFromPassphrase("", nil, Low); err == nil {
+		t.Error("empty passphrase should be rejected")
+	}
+}
@@ -0,0 +1,295 @@
+//go:build !tinygo
+
+// Package enigma provides signatures that detect tampered configuration files.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// Signature algorithms.
+const (
+	SignatureHMACSHA256 = "hmac-sha256"
+	SignatureEd25519    = "ed25519"
+)
+
+// minHMACKeySize is the shortest HMAC secret accepted, in bytes.
+const minHMACKeySize = 16
+
+// signaturePrefix separates settings signatures from anything else the same
+// key might sign.
+const signaturePrefix = "enigoma settings signature v1\n"
+
+var (
+	// ErrUnsigned is returned by VerifySignature for settings without a signature.
+	ErrUnsigned = errors.New("the settings are not signed")
+	// ErrBadSignature is returned by VerifySignature when the settings or the
+	// signature were changed after signing, or another key signed them.
+	ErrBadSignature = errors.New("the settings signature does not match; the configuration was modified after signing")
+)
+
+// SigningKey signs and verifies settings. An HMAC key is a shared secret that
+// both signs and verifies; an Ed25519 key signs with its private half and
+// verifies with its public half alone.
+type SigningKey struct {
+	algorithm string
+	secret    []byte             // HMAC secret
+	private   ed25519.PrivateKey // Nil for an Ed25519 key that only verifies
+	public    ed25519.PublicKey
+}
+
+// NewHMACSigningKey returns an HMAC-SHA256 key for a shared secret of at
+// least 16 bytes.
+func NewHMACSigningKey(secret []byte) (*SigningKey, error) {
+	if len(secret) < minHMACKeySize {
+		return nil, fmt.Errorf("HMAC key too short: %d bytes (need at least %d)", len(secret), minHMACKeySize)
+	}
+	return &SigningKey{algorithm: SignatureHMACSHA256, secret: bytes.Clone(secret)}, nil
+}
+
+// NewEd25519SigningKey returns a key that signs with private.
+func NewEd25519SigningKey(private ed25519.PrivateKey) (*SigningKey, error) {
+	if len(private) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid Ed25519 private key size: %d", len(private))
+	}
+	return &SigningKey{
+		algorithm: SignatureEd25519,
+		private:   bytes.Clone(private),
+		public:    bytes.Clone(private.Public().(ed25519.PublicKey)),
+	}, nil
+}
+
+// NewEd25519VerifyingKey returns a key that only verifies, with public.
+func NewEd25519VerifyingKey(public ed25519.PublicKey) (*SigningKey, error) {
+	if len(public) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid Ed25519 public key size: %d", len(public))
+	}
+	return &SigningKey{algorithm: SignatureEd25519, public: bytes.Clone(public)}, nil
+}
+
+// GenerateEd25519SigningKey returns a new random Ed25519 key.
+func GenerateEd25519SigningKey() (*SigningKey, error) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate Ed25519 key: %v", err)
+	}
+	return NewEd25519SigningKey(private)
+}
+
+// ParseSigningKey reads a key file: a PEM "PRIVATE KEY" (PKCS #8) or
+// "PUBLIC KEY" (PKIX) block holding an Ed25519 key, or else the raw bytes of
+// an HMAC secret with surrounding whitespace removed.
+func ParseSigningKey(data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return NewHMACSigningKey(bytes.TrimSpace(data))
+	}
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid private key: %v", err)
+		}
+		private, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key type %T (want Ed25519)", key)
+		}
+		return NewEd25519SigningKey(private)
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key: %v", err)
+		}
+		public, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("unsupported public key type %T (want Ed25519)", key)
+		}
+		return NewEd25519VerifyingKey(public)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q (want PRIVATE KEY or PUBLIC KEY)", block.Type)
+	}
+}
+
+// Algorithm returns SignatureHMACSHA256 or SignatureEd25519.
+func (k *SigningKey) Algorithm() string {
+	return k.algorithm
+}
+
+// CanSign reports whether the key can sign; Ed25519 public keys only verify.
+func (k *SigningKey) CanSign() bool {
+	return k.algorithm == SignatureHMACSHA256 || k.private != nil
+}
+
+// ID returns a short identifier of the key that reveals nothing about an
+// HMAC secret. Signatures record it to tell keys apart.
+func (k *SigningKey) ID() string {
+	var sum []byte
+	if k.algorithm == SignatureHMACSHA256 {
+		mac := hmac.New(sha256.New, k.secret)
+		mac.Write([]byte("enigoma key id"))
+		sum = mac.Sum(nil)
+	} else {
+		digest := sha256.Sum256(k.public)
+		sum = digest[:]
+	}
+	return hex.EncodeToString(sum[:8])
+}
+
+// MarshalPrivatePEM encodes an Ed25519 signing key as a PEM "PRIVATE KEY" block.
+func (k *SigningKey) MarshalPrivatePEM() ([]byte, error) {
+	if k.private == nil {
+		return nil, fmt.Errorf("not an Ed25519 private key")
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(k.private)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// MarshalPublicPEM encodes the public half of an Ed25519 key as a PEM
+// "PUBLIC KEY" block.
+func (k *SigningKey) MarshalPublicPEM() ([]byte, error) {
+	if k.public == nil {
+		return nil, fmt.Errorf("not an Ed25519 key")
+	}
+	der, err := x509.MarshalPKIXPublicKey(k.public)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// Sign signs the machine's settings with key and stores the signature in its
+// metadata, replacing any earlier one. The signature covers everything that
+// is saved: the settings, including the current rotor positions, and the
+// metadata. Sign before processing text (or after Reset), and sign again
+// after changing the machine.
+func (e *Enigma) Sign(key *SigningKey) error {
+	if key == nil || !key.CanSign() {
+		return fmt.Errorf("the key cannot sign (an Ed25519 public key only verifies)")
+	}
+	payload, err := e.signaturePayload()
+	if err != nil {
+		return err
+	}
+	sig := &Signature{Algorithm: key.algorithm, KeyID: key.ID()}
+	var value []byte
+	if key.algorithm == SignatureHMACSHA256 {
+		mac := hmac.New(sha256.New, key.secret)
+		mac.Write(payload)
+		value = mac.Sum(nil)
+	} else {
+		value = ed25519.Sign(key.private, payload)
+		sig.PublicKey = base64.StdEncoding.EncodeToString(key.public)
+	}
+	sig.Value = base64.StdEncoding.EncodeToString(value)
+
+	m := e.metadata.clone()
+	if m == nil {
+		m = &Metadata{}
+	}
+	m.Signature = sig
+	e.metadata = m
+	return nil
+}
+
+// IsSigned reports whether the machine's settings carry a signature.
+func (e *Enigma) IsSigned() bool {
+	return e.metadata != nil && e.metadata.Signature != nil
+}
+
+// RemoveSignature drops the signature, as after changing a signed machine.
+func (e *Enigma) RemoveSignature() {
+	if e.IsSigned() {
+		e.metadata = e.metadata.clone()
+		e.metadata.Signature = nil
+	}
+}
+
+// VerifySignature checks the signature of the machine's settings, as loaded
+// and before any text is processed. It returns ErrUnsigned without a
+// signature and ErrBadSignature when it does not match.
+//
+// With a key, the settings must have been signed by that key. Without one,
+// an Ed25519 signature is checked against the public key it carries: this
+// detects corrupted or edited files, but not a file re-signed by someone
+// else. HMAC signatures always need the key.
+func (e *Enigma) VerifySignature(key *SigningKey) error {
+	if !e.IsSigned() {
+		return ErrUnsigned
+	}
+	sig := e.metadata.Signature
+	value, err := base64.StdEncoding.DecodeString(sig.Value)
+	if err != nil {
+		return ErrBadSignature
+	}
+	if key == nil {
+		if sig.Algorithm != SignatureEd25519 {
+			return fmt.Errorf("a %s signature needs the signing key to verify", sig.Algorithm)
+		}
+		public, err := base64.StdEncoding.DecodeString(sig.PublicKey)
+		if err != nil {
+			return ErrBadSignature
+		}
+		if key, err = NewEd25519VerifyingKey(public); err != nil {
+			return ErrBadSignature
+		}
+	}
+	if sig.Algorithm != key.algorithm || sig.KeyID != key.ID() {
+		return fmt.Errorf("the settings were signed with another key (%s %s, not %s %s)", sig.Algorithm, sig.KeyID, key.algorithm, key.ID())
+	}
+
+	payload, err := e.signaturePayload()
+	if err != nil {
+		return err
+	}
+	if key.algorithm == SignatureHMACSHA256 {
+		mac := hmac.New(sha256.New, key.secret)
+		mac.Write(payload)
+		if !hmac.Equal(mac.Sum(nil), value) {
+			return ErrBadSignature
+		}
+		return nil
+	}
+	if !ed25519.Verify(key.public, payload, value) {
+		return ErrBadSignature
+	}
+	return nil
+}
+
+// signaturePayload returns the bytes a signature covers: the JSON settings,
+// whose encoding is canonical, without the signature itself. It is the same
+// whichever format the settings were saved in.
+func (e *Enigma) signaturePayload() ([]byte, error) {
+	settings, err := e.GetSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settings: %v", err)
+	}
+	if m := settings.Metadata; m != nil {
+		// Signing an unannotated machine adds the metadata holding the signature
+		m.Signature = nil
+		if empty, _ := json.Marshal(m); string(empty) == "{}" {
+			settings.Metadata = nil
+		}
+	}
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal settings: %v", err)
+	}
+	return append([]byte(signaturePrefix), data...), nil
+}
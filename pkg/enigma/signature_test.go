@@ -0,0 +1,159 @@
+//go:build !tinygo
+
+package enigma
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	hmacKey, err := NewHMACSigningKey([]byte("a shared secret of some length"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	edKey, err := GenerateEd25519SigningKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []*SigningKey{hmacKey, edKey} {
+		t.Run(key.Algorithm(), func(t *testing.T) {
+			machine, err := NewEnigmaM3()
+			if err != nil {
+				t.Fatal(err)
+			}
+			before, _ := machine.Fingerprint()
+			if err := machine.VerifySignature(key); !errors.Is(err, ErrUnsigned) {
+				t.Errorf("an unsigned machine should report ErrUnsigned, got %v", err)
+			}
+			if err := machine.Sign(key); err != nil {
+				t.Fatalf("Sign failed: %v", err)
+			}
+			if after, _ := machine.Fingerprint(); after != before {
+				t.Error("signing should not change the fingerprint")
+			}
+
+			jsonData, err := machine.SaveSettingsToJSON()
+			if err != nil {
+				t.Fatal(err)
+			}
+			loaded, err := NewFromJSON(jsonData)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := loaded.VerifySignature(key); err != nil {
+				t.Errorf("the saved settings should verify: %v", err)
+			}
+
+			// The signature survives the other formats
+			protoData, _ := machine.SaveSettingsToProto()
+			fromProto, err := NewFromProto(protoData)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := fromProto.VerifySignature(key); err != nil {
+				t.Errorf("protobuf settings should verify: %v", err)
+			}
+			yamlData, _ := machine.SaveSettingsToYAML()
+			fromYAML, err := NewFromYAML(yamlData)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := fromYAML.VerifySignature(key); err != nil {
+				t.Errorf("YAML settings should verify: %v", err)
+			}
+
+			// Any change to the settings breaks it
+			var doc map[string]any
+			if err := json.Unmarshal([]byte(jsonData), &doc); err != nil {
+				t.Fatal(err)
+			}
+			positions := doc["current_rotor_positions"].([]any)
+			positions[0] = int(positions[0].(float64)+1) % 26
+			edited, _ := json.Marshal(doc)
+			tampered, err := NewFromJSON(string(edited))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := tampered.VerifySignature(key); !errors.Is(err, ErrBadSignature) {
+				t.Errorf("a changed rotor position should fail verification, got %v", err)
+			}
+			if _, err := loaded.Encrypt("HELLO"); err != nil {
+				t.Fatal(err)
+			}
+			if err := loaded.VerifySignature(key); !errors.Is(err, ErrBadSignature) {
+				t.Errorf("moved rotors should fail verification, got %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifySignatureKeys(t *testing.T) {
+	machine, err := NewEnigmaClassic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	edKey, _ := GenerateEd25519SigningKey()
+	if err := machine.Sign(edKey); err != nil {
+		t.Fatal(err)
+	}
+
+	// The public key alone verifies, and so does the embedded one
+	public, _ := edKey.MarshalPublicPEM()
+	verifier, err := ParseSigningKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verifier.CanSign() {
+		t.Error("a public key should not sign")
+	}
+	if err := machine.VerifySignature(verifier); err != nil {
+		t.Errorf("the public key should verify: %v", err)
+	}
+	if err := machine.VerifySignature(nil); err != nil {
+		t.Errorf("the embedded public key should verify: %v", err)
+	}
+
+	other, _ := GenerateEd25519SigningKey()
+	if err := machine.VerifySignature(other); err == nil || !strings.Contains(err.Error(), "another key") {
+		t.Errorf("another key should be reported, got %v", err)
+	}
+
+	hmacKey, _ := NewHMACSigningKey([]byte("0123456789abcdef"))
+	if err := machine.Sign(hmacKey); err != nil {
+		t.Fatal(err)
+	}
+	if err := machine.VerifySignature(nil); err == nil {
+		t.Error("an HMAC signature should need the key")
+	}
+	machine.RemoveSignature()
+	if machine.IsSigned() {
+		t.Error("RemoveSignature should drop the signature")
+	}
+}
+
+func TestParseSigningKey(t *testing.T) {
+	edKey, _ := GenerateEd25519SigningKey()
+	private, _ := edKey.MarshalPrivatePEM()
+	parsed, err := ParseSigningKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.ID() != edKey.ID() || !parsed.CanSign() {
+		t.Error("the private key should round-trip")
+	}
+
+	secret, err := ParseSigningKey([]byte("  0123456789abcdef0123\n"))
+	if err != nil || secret.Algorithm() != SignatureHMACSHA256 {
+		t.Errorf("raw bytes should be an HMAC secret, got %v", err)
+	}
+	if _, err := ParseSigningKey([]byte("short")); err == nil {
+		t.Error("a short HMAC secret should be rejected")
+	}
+	if _, err := ParseSigningKey([]byte("-----BEGIN CERTIFICATE-----\nAAAA\n-----END CERTIFICATE-----\n")); err == nil {
+		t.Error("an unsupported PEM block should be rejected")
+	}
+}
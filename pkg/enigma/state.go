@@ -0,0 +1,63 @@
+// Package enigma provides lightweight snapshots of the machine's moving parts.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import "fmt"
+
+// MachineState is the part of a machine that changes while it encrypts: the
+// rotor positions, the position of a rotating or settable reflector and the
+// rotor step counters. It is small enough to checkpoint after every message
+// of a long stream; the wiring, plugboard and everything else fixed by the
+// key stay in EnigmaSettings.
+type MachineState struct {
+	Positions         []int `json:"positions"`
+	ReflectorPosition int   `json:"reflector_position,omitempty"` // Only used by machines with a settable reflector
+	StepCounts        []int `json:"step_counts,omitempty"`
+}
+
+// SaveState captures the machine's current state.
+func (e *Enigma) SaveState() MachineState {
+	state := MachineState{
+		Positions:  e.GetCurrentRotorPositions(),
+		StepCounts: e.GetRotorStepCounts(),
+	}
+	if rr, ok := e.rotatingReflector(); ok {
+		state.ReflectorPosition = rr.Position()
+	}
+	return state
+}
+
+// RestoreState puts the machine back into a state saved by SaveState, on
+// this machine or on another one loaded from the same key. The state is
+// checked against the machine before anything changes.
+func (e *Enigma) RestoreState(state MachineState) error {
+	if len(state.Positions) != len(e.rotors) {
+		return fmt.Errorf("state has %d rotor positions, the machine has %d rotors", len(state.Positions), len(e.rotors))
+	}
+	for i, pos := range state.Positions {
+		if pos < 0 || pos >= e.alphabet.Size() {
+			return fmt.Errorf("position %d of rotor %d is outside 0-%d", pos, i+1, e.alphabet.Size()-1)
+		}
+	}
+	if len(state.StepCounts) != 0 && len(state.StepCounts) != len(e.rotors) {
+		return fmt.Errorf("state has %d step counts, the machine has %d rotors", len(state.StepCounts), len(e.rotors))
+	}
+	rr, settable := e.rotatingReflector()
+	if state.ReflectorPosition != 0 && !settable {
+		return fmt.Errorf("state has a reflector position, but the reflector cannot be set")
+	}
+	if state.ReflectorPosition < 0 || state.ReflectorPosition >= e.alphabet.Size() {
+		return fmt.Errorf("reflector position %d is outside 0-%d", state.ReflectorPosition, e.alphabet.Size()-1)
+	}
+
+	if err := e.SetRotorPositions(state.Positions); err != nil {
+		return err
+	}
+	if settable {
+		rr.SetPosition(state.ReflectorPosition)
+	}
+	e.stepCounts = append([]int(nil), state.StepCounts...)
+	return nil
+}
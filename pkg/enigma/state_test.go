@@ -0,0 +1,68 @@
+package enigma
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSaveAndRestoreState(t *testing.T) {
+	for name, build := range map[string]func() (*Enigma, error){
+		"m3": NewEnigmaM3,
+		"g":  NewEnigmaG,
+	} {
+		machine, err := build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := machine.Encrypt("ANXKAPITAENLEUTNANTDERRESERVEXX"); err != nil {
+			t.Fatal(err)
+		}
+		state := machine.SaveState()
+		want, err := machine.Encrypt("KURSSUEDWESTQUADRATAA")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The same machine, rewound
+		if err := machine.RestoreState(state); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := machine.Encrypt("KURSSUEDWESTQUADRATAA"); got != want {
+			t.Errorf("%s: after restore = %s, want %s", name, got, want)
+		}
+
+		// A fresh machine from the same key, as after a restart
+		resumed, _ := build()
+		if err := resumed.RestoreState(state); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(resumed.GetRotorStepCounts(), state.StepCounts) {
+			t.Errorf("%s: step counts = %v, want %v", name, resumed.GetRotorStepCounts(), state.StepCounts)
+		}
+		if got, _ := resumed.Encrypt("KURSSUEDWESTQUADRATAA"); got != want {
+			t.Errorf("%s: resumed = %s, want %s", name, got, want)
+		}
+	}
+}
+
+func TestRestoreStateErrors(t *testing.T) {
+	machine, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := machine.SaveState()
+	for name, state := range map[string]MachineState{
+		"too few positions":  {Positions: []int{0, 0}},
+		"position too large": {Positions: []int{0, 26, 0}},
+		"negative position":  {Positions: []int{0, -1, 0}},
+		"step counts":        {Positions: []int{1, 2, 3}, StepCounts: []int{1}},
+		"reflector position": {Positions: []int{1, 2, 3}, ReflectorPosition: 4},
+	} {
+		if err := machine.RestoreState(state); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if !reflect.DeepEqual(machine.SaveState(), before) {
+		t.Error("a failed restore must leave the machine unchanged")
+	}
+}
@@ -0,0 +1,77 @@
+// Package enigma provides the rotor stepping mechanisms.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SteppingMode selects the mechanism that advances the rotors.
+type SteppingMode int
+
+const (
+	// SteppingLever is the pawl-and-ratchet mechanism of the military
+	// machines, including the double-step of the middle rotor. It is the
+	// default.
+	SteppingLever SteppingMode = iota
+	// SteppingGear is the gear drive of the Enigma G and other commercial
+	// variants: rotors turn over like an odometer, without double-stepping.
+	SteppingGear
+	// SteppingNone keeps the rotors still, so every character is enciphered
+	// with the same substitution. It is for teaching, not for secrecy.
+	SteppingNone
+)
+
+// String returns the mode's name as written in settings files.
+func (m SteppingMode) String() string {
+	switch m {
+	case SteppingLever:
+		return "lever"
+	case SteppingGear:
+		return "gear"
+	case SteppingNone:
+		return "none"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseSteppingMode parses a mode name as returned by SteppingMode.String.
+// An empty name is the default, SteppingLever.
+func ParseSteppingMode(name string) (SteppingMode, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "lever":
+		return SteppingLever, nil
+	case "gear":
+		return SteppingGear, nil
+	case "none":
+		return SteppingNone, nil
+	default:
+		return SteppingLever, fmt.Errorf("unknown stepping mode: %s. Available: lever, gear, none", name)
+	}
+}
+
+// valid reports whether m is one of the defined modes.
+func (m SteppingMode) valid() bool {
+	return m >= SteppingLever && m <= SteppingNone
+}
+
+// WithSteppingMode selects how the rotors advance. The mode is part of the
+// key: it is saved with the settings and changes the fingerprint.
+func WithSteppingMode(mode SteppingMode) Option {
+	return func(e *Enigma) error {
+		if !mode.valid() {
+			return fmt.Errorf("invalid stepping mode: %d", mode)
+		}
+		e.stepping = mode
+		return nil
+	}
+}
+
+// GetSteppingMode returns the mechanism that advances the rotors.
+func (e *Enigma) GetSteppingMode() SteppingMode {
+	return e.stepping
+}
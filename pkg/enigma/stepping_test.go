@@ -0,0 +1,125 @@
+package enigma
+
+import "testing"
+
+func TestSteppingModes(t *testing.T) {
+	// Rotors I II III at AEA: the middle rotor sits on its notch (E), so
+	// the lever mechanism double-steps it on the next key press while the
+	// gear drive leaves it alone.
+	tests := []struct {
+		mode SteppingMode
+		want []string
+	}{
+		{SteppingLever, []string{"AFB", "AFC"}},
+		{SteppingGear, []string{"AEB", "AEC"}},
+		{SteppingNone, []string{"AEA", "AEA"}},
+	}
+	for _, tt := range tests {
+		machine, err := NewEnigmaM3()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := WithSteppingMode(tt.mode)(machine); err != nil {
+			t.Fatalf("WithSteppingMode(%s) failed: %v", tt.mode, err)
+		}
+		if err := machine.SetRotorPositionsFromRunes([]rune("AEA")); err != nil {
+			t.Fatal(err)
+		}
+		for i, want := range tt.want {
+			if _, err := machine.Encrypt("A"); err != nil {
+				t.Fatal(err)
+			}
+			if got := string(machine.GetRotorPositionsAsRunes()); got != want {
+				t.Errorf("%s: after %d key presses positions = %s, want %s", tt.mode, i+1, got, want)
+			}
+		}
+	}
+}
+
+func TestSteppingNoneIsFixedSubstitution(t *testing.T) {
+	machine, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := WithSteppingMode(SteppingNone)(machine); err != nil {
+		t.Fatal(err)
+	}
+	ciphertext, err := machine.Encrypt("AAAA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range ciphertext {
+		if c != rune(ciphertext[0]) {
+			t.Fatalf("Encrypt(AAAA) = %s, want one repeated letter", ciphertext)
+		}
+	}
+	if err := machine.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if plaintext, _ := machine.Decrypt(ciphertext); plaintext != "AAAA" {
+		t.Errorf("Decrypt = %s, want AAAA", plaintext)
+	}
+}
+
+func TestParseSteppingMode(t *testing.T) {
+	for _, mode := range []SteppingMode{SteppingLever, SteppingGear, SteppingNone} {
+		if got, err := ParseSteppingMode(mode.String()); err != nil || got != mode {
+			t.Errorf("ParseSteppingMode(%q) = %v, %v", mode.String(), got, err)
+		}
+	}
+	if got, err := ParseSteppingMode(""); err != nil || got != SteppingLever {
+		t.Errorf("ParseSteppingMode(\"\") = %v, %v; want lever", got, err)
+	}
+	if _, err := ParseSteppingMode("cog"); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+	if err := WithSteppingMode(SteppingMode(7))(&Enigma{}); err == nil {
+		t.Error("expected an error for an invalid mode")
+	}
+}
+
+func TestSteppingModeSettings(t *testing.T) {
+	machine, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := WithSteppingMode(SteppingGear)(machine); err != nil {
+		t.Fatal(err)
+	}
+	settings, err := machine.GetSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.SteppingMode != SteppingGear {
+		t.Errorf("settings stepping mode = %s, want gear", settings.SteppingMode)
+	}
+
+	data, err := settings.MarshalProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded EnigmaSettings
+	if err := decoded.UnmarshalProto(data); err != nil {
+		t.Fatalf("UnmarshalProto failed: %v", err)
+	}
+	restored, err := NewFromSettings(&decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.GetSteppingMode() != SteppingGear {
+		t.Errorf("proto round trip lost the stepping mode: %s", restored.GetSteppingMode())
+	}
+
+	clone, err := machine.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clone.GetSteppingMode() != SteppingGear {
+		t.Errorf("Clone lost the stepping mode: %s", clone.GetSteppingMode())
+	}
+
+	settings.SteppingMode = SteppingMode(9)
+	if _, err := NewFromSettings(settings); err == nil {
+		t.Error("expected an error for an invalid stepping mode in settings")
+	}
+}
@@ -0,0 +1,188 @@
+// Package enigma provides io.Writer and io.Reader wrappers that encrypt and
+// decrypt on the fly.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"unicode"
+	"unicode/utf8"
+)
+
+// streamReadSize is the number of bytes a Reader reads from its source at a
+// time.
+const streamReadSize = 4096
+
+// Writer encrypts everything written to it and writes the ciphertext to an
+// underlying io.Writer. The rotors advance with every character, so the
+// ciphertext is the same as encrypting all the text with one Encrypt call,
+// however the writes split it.
+//
+// A write may end in the middle of a character, or of a symbol that the next
+// write could extend, so a Writer holds that tail back until more text
+// arrives. Close writes it out.
+type Writer struct {
+	w       io.Writer
+	machine *Enigma
+	pending []byte
+	err     error
+	closed  bool
+}
+
+// NewWriter returns a Writer that encrypts with machine and writes to w.
+// The machine is used in place, from its current rotor positions; clone it
+// first to keep it at its starting positions. It must not be used elsewhere
+// until the Writer is closed.
+func NewWriter(w io.Writer, machine *Enigma) *Writer {
+	return &Writer{w: w, machine: machine}
+}
+
+// Write encrypts the complete characters of p, together with any held back
+// by the previous write, and writes them to the underlying writer. When the
+// encryption or the write fails, Write reports that none of p was consumed,
+// and the Writer is done: every later Write and Close returns the same error.
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	if w.closed {
+		return 0, errors.New("write to a closed Writer")
+	}
+	w.pending = append(w.pending, p...)
+	if err := w.flush(false); err != nil {
+		w.pending = w.pending[:len(w.pending)-len(p)]
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close encrypts and writes the text held back by earlier writes. It does
+// not close the underlying writer. Text that ends in an incomplete UTF-8
+// sequence fails to encrypt. Closing again does nothing.
+func (w *Writer) Close() error {
+	if w.closed || w.err != nil {
+		return w.err
+	}
+	w.closed = true
+	return w.flush(true)
+}
+
+// flush encrypts as much pending text as may be encrypted now, or all of it
+// when final.
+func (w *Writer) flush(final bool) error {
+	n := w.machine.streamBoundary(w.pending, final)
+	if n == 0 {
+		return nil
+	}
+	ciphertext, err := w.machine.Encrypt(string(w.pending[:n]))
+	if err != nil {
+		w.err = fmt.Errorf("encryption failed: %v", err)
+		return w.err
+	}
+	if _, err := io.WriteString(w.w, ciphertext); err != nil {
+		w.err = fmt.Errorf("failed to write ciphertext: %w", err)
+		return w.err
+	}
+	w.pending = append(w.pending[:0], w.pending[n:]...)
+	return nil
+}
+
+// Reader decrypts the ciphertext read from an underlying io.Reader. Like
+// Writer, it decrypts the stream as one text however the reads split it,
+// holding back a character or symbol that may continue in the next read.
+type Reader struct {
+	r       io.Reader
+	machine *Enigma
+	pending []byte // Ciphertext read but not yet decrypted
+	out     []byte // Plaintext decrypted but not yet returned
+	err     error
+}
+
+// NewReader returns a Reader that decrypts what it reads from r with
+// machine. As with NewWriter, the machine is used in place from its current
+// rotor positions and must not be used elsewhere while reading.
+func NewReader(r io.Reader, machine *Enigma) *Reader {
+	return &Reader{r: r, machine: machine}
+}
+
+// Read fills p with decrypted text. It returns io.EOF once the underlying
+// reader is exhausted and all its text has been returned, and a decryption
+// error as soon as it reaches ciphertext that does not decrypt.
+func (r *Reader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		r.fill()
+	}
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	return n, nil
+}
+
+// fill reads more ciphertext and decrypts what can be decrypted so far.
+// Errors from the underlying reader, including io.EOF, are kept for Read to
+// return once the text before them has been read.
+func (r *Reader) fill() {
+	start := len(r.pending)
+	r.pending = append(r.pending, make([]byte, streamReadSize)...)
+	read, err := r.r.Read(r.pending[start:])
+	r.pending = r.pending[:start+read]
+
+	n := r.machine.streamBoundary(r.pending, err != nil)
+	if n > 0 {
+		plaintext, decErr := r.machine.Decrypt(string(r.pending[:n]))
+		if decErr != nil {
+			r.err = fmt.Errorf("decryption failed: %v", decErr)
+			return
+		}
+		r.out = append(r.out[:0], plaintext...)
+		r.pending = append(r.pending[:0], r.pending[n:]...)
+	}
+	if err != nil {
+		r.err = err
+	}
+}
+
+// streamBoundary returns how many leading bytes of buf a stream may process
+// now. The rest could change meaning when more text follows: a rune cut off
+// mid-encoding, the last cluster of a grapheme alphabet, or, when text is
+// normalized, a character that combining marks may still follow. When final,
+// nothing follows and all of buf may be processed.
+func (e *Enigma) streamBoundary(buf []byte, final bool) int {
+	if final {
+		return len(buf)
+	}
+
+	// Leave out an incomplete trailing rune
+	n := len(buf)
+	for i := 1; i <= utf8.UTFMax && i <= len(buf); i++ {
+		if utf8.RuneStart(buf[len(buf)-i]) {
+			if !utf8.FullRune(buf[len(buf)-i:]) {
+				n = len(buf) - i
+			}
+			break
+		}
+	}
+
+	switch {
+	case e.alphabet.IsGrapheme():
+		if clusters := e.alphabet.Segment(string(buf[:n])); len(clusters) > 0 {
+			n -= len(clusters[len(clusters)-1])
+		}
+	case e.normalization != NormalizationNone:
+		// Back up over trailing marks and the character they follow
+		for n > 0 {
+			r, size := utf8.DecodeLastRune(buf[:n])
+			n -= size
+			if !unicode.Is(unicode.M, r) {
+				break
+			}
+		}
+	}
+	return n
+}
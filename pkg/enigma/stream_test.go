@@ -0,0 +1,230 @@
+package enigma
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// writeInPieces writes text to w size bytes at a time, cutting through
+// multi-byte characters.
+func writeInPieces(t *testing.T, w io.Writer, text string, size int) {
+	t.Helper()
+	for len(text) > 0 {
+		n := min(size, len(text))
+		if _, err := io.WriteString(w, text[:n]); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		text = text[n:]
+	}
+}
+
+func TestStreamMatchesEncrypt(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+		text    string
+	}{
+		{"latin", []Option{WithAlphabet([]rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")), WithRandomSettings(Medium)}, strings.Repeat("THEQUICKBROWNFOX", 700)},
+		{"unicode", []Option{WithAlphabet([]rune("AB\u00e9\u4f60\u597d\U0001f600 C")), WithRandomSettings(Low)}, strings.Repeat("A\u00e9 \u4f60\u597d\U0001f600B", 300)},
+		{"grapheme", []Option{WithGraphemeAlphabet(graphemeSymbols), WithRandomSettings(Low)}, strings.Repeat("ABe\u0301 \U0001f1eb\U0001f1f7\U0001f44d\U0001f3fdn\u0303", 200)},
+		// Each accent arrives apart from its letter when written byte by byte
+		{"normalized", []Option{WithNormalization(NormalizationNFC), WithAlphabet([]rune("ABCD\u00c9F")), WithRandomSettings(Low)}, strings.Repeat("ABE\u0301CDE\u0301F", 300)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			machine, err := New(tt.options...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			reference, err := machine.Clone()
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := reference.Encrypt(tt.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, size := range []int{1, 3, 4096} {
+				encrypter, err := machine.Clone()
+				if err != nil {
+					t.Fatal(err)
+				}
+				var buf bytes.Buffer
+				w := NewWriter(&buf, encrypter)
+				writeInPieces(t, w, tt.text, size)
+				if err := w.Close(); err != nil {
+					t.Fatalf("Close failed: %v", err)
+				}
+				if buf.String() != want {
+					t.Fatalf("writes of %d bytes: ciphertext differs from Encrypt", size)
+				}
+				if got := encrypter.GetCurrentRotorPositions(); !slices.Equal(got, reference.GetCurrentRotorPositions()) {
+					t.Errorf("writes of %d bytes: positions %v, want %v", size, got, reference.GetCurrentRotorPositions())
+				}
+			}
+
+			decrypter, err := machine.Clone()
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantPlain, err := decrypter.Clone()
+			if err != nil {
+				t.Fatal(err)
+			}
+			plaintext, err := wantPlain.Decrypt(want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := io.ReadAll(NewReader(iotest.OneByteReader(strings.NewReader(want)), decrypter))
+			if err != nil {
+				t.Fatalf("ReadAll failed: %v", err)
+			}
+			if string(got) != plaintext {
+				t.Error("Reader output differs from Decrypt")
+			}
+		})
+	}
+}
+
+func TestStreamThroughGzip(t *testing.T) {
+	machine, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext := strings.Repeat("ATTACKATDAWN", 1000)
+
+	encrypter, _ := machine.Clone()
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	w := NewWriter(zw, encrypter)
+	if _, err := io.Copy(w, strings.NewReader(plaintext)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := gzip.NewReader(&compressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decrypter, _ := machine.Clone()
+	got, err := io.ReadAll(NewReader(zr, decrypter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != plaintext {
+		t.Error("text did not survive encryption and compression")
+	}
+}
+
+func TestStreamErrors(t *testing.T) {
+	machine, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Text before a bad character is returned, then the error
+	decrypter, _ := machine.Clone()
+	r := NewReader(strings.NewReader("ABC"+strings.Repeat("D", streamReadSize)+"?"), decrypter)
+	got, err := io.ReadAll(r)
+	if err == nil || !strings.Contains(err.Error(), "decryption failed") {
+		t.Errorf("ReadAll error = %v, want a decryption error", err)
+	}
+	// The first read decrypts; the second holds the bad character
+	if len(got) != streamReadSize {
+		t.Errorf("read %d bytes before the error, want %d", len(got), streamReadSize)
+	}
+	if _, err := r.Read(make([]byte, 1)); err == nil {
+		t.Error("the error should be repeated")
+	}
+
+	encrypter, _ := machine.Clone()
+	var buf bytes.Buffer
+	w := NewWriter(&buf, encrypter)
+	if _, err := w.Write([]byte("AB\xc3")); err != nil {
+		t.Fatalf("a partial character should be held back, got %v", err)
+	}
+	if buf.String() == "" || strings.ContainsRune(buf.String(), '\ufffd') {
+		t.Errorf("complete characters should be written, got %q", buf.String())
+	}
+	if err := w.Close(); err == nil {
+		t.Error("Close should fail on an incomplete character")
+	}
+
+	w = NewWriter(io.Discard, encrypter)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("a second Close = %v, want nil", err)
+	}
+	if _, err := w.Write([]byte("A")); err == nil {
+		t.Error("Write after Close should fail")
+	}
+}
+
+// limitedWriter accepts limit bytes, then fails every write.
+type limitedWriter struct {
+	limit int
+}
+
+var errWriteFailed = errors.New("disk full")
+
+func (f *limitedWriter) Write(p []byte) (int, error) {
+	if len(p) > f.limit {
+		return 0, errWriteFailed
+	}
+	f.limit -= len(p)
+	return len(p), nil
+}
+
+func TestWriterFailureConsumesNothing(t *testing.T) {
+	machine, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewWriter(&limitedWriter{limit: 3}, machine)
+	if n, err := w.Write([]byte("ABC")); n != 3 || err != nil {
+		t.Fatalf("Write = %d, %v; want 3, nil", n, err)
+	}
+	n, err := w.Write([]byte("DEF"))
+	if n != 0 || !errors.Is(err, errWriteFailed) {
+		t.Fatalf("Write = %d, %v; want 0 and the write error", n, err)
+	}
+	// The error is final
+	if _, again := w.Write([]byte("G")); again != err {
+		t.Errorf("later Write = %v, want %v", again, err)
+	}
+	if closeErr := w.Close(); closeErr != err {
+		t.Errorf("Close = %v, want %v", closeErr, err)
+	}
+
+	// A character the machine rejects fails the whole write as well
+	encrypter, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	w = NewWriter(&buf, encrypter)
+	if n, err := w.Write([]byte("AB?")); n != 0 || err == nil {
+		t.Fatalf("Write = %d, %v; want 0 and an encryption error", n, err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("nothing should be written, got %q", buf.String())
+	}
+	if _, err := w.Write([]byte("C")); err == nil {
+		t.Error("the encryption error should be final")
+	}
+}
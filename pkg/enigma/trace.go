@@ -0,0 +1,90 @@
+// Package enigma provides tracing of the signal path through the machine.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+// TraceStageKind identifies a component the signal passes through.
+type TraceStageKind int
+
+const (
+	// TracePlugboard is the plugboard (or Uhr) on the way in.
+	TracePlugboard TraceStageKind = iota
+	// TraceEntryWheel is the entry wheel on the way in. Machines whose entry
+	// wheel is the identity skip it.
+	TraceEntryWheel
+	// TraceRotorForward is a rotor on the way to the reflector.
+	TraceRotorForward
+	// TraceReflector is the reflector.
+	TraceReflector
+	// TraceRotorBackward is a rotor on the way back from the reflector.
+	TraceRotorBackward
+	// TraceEntryWheelReturn is the entry wheel on the way out.
+	TraceEntryWheelReturn
+	// TracePlugboardReturn is the plugboard (or Uhr) on the way out to the
+	// lampboard.
+	TracePlugboardReturn
+)
+
+// String returns a short lowercase name for the stage kind.
+func (k TraceStageKind) String() string {
+	switch k {
+	case TracePlugboard:
+		return "plugboard"
+	case TraceEntryWheel:
+		return "entry wheel"
+	case TraceRotorForward:
+		return "rotor forward"
+	case TraceReflector:
+		return "reflector"
+	case TraceRotorBackward:
+		return "rotor backward"
+	case TraceEntryWheelReturn:
+		return "entry wheel return"
+	case TracePlugboardReturn:
+		return "plugboard return"
+	default:
+		return "unknown"
+	}
+}
+
+// TraceStage is one component on a character's signal path. Input and Output
+// are alphabet indices of the contacts the signal enters and leaves by.
+type TraceStage struct {
+	Kind   TraceStageKind
+	Rotor  int // Rotor slot for rotor stages, 0 is the leftmost; -1 otherwise
+	Input  int
+	Output int
+}
+
+// TraceStep is the signal path of one character, from keyboard to lamp.
+type TraceStep struct {
+	Index     int   // Zero-based character index within the call
+	Input     rune  // Key pressed (for grapheme alphabets, the rune standing for the symbol; see RuneSymbol)
+	Output    rune  // Lamp lit
+	Positions []int // Rotor positions after stepping for this character
+	Stages    []TraceStage
+}
+
+// record appends a stage to the trace. It does nothing on a nil trace, so
+// the untraced path needs no checks.
+func (t *TraceStep) record(kind TraceStageKind, rotor, input, output int) {
+	if t == nil {
+		return
+	}
+	t.Stages = append(t.Stages, TraceStage{Kind: kind, Rotor: rotor, Input: input, Output: output})
+}
+
+// EncryptTraced encrypts text like Encrypt and also returns the signal path
+// of every character: the index after the plugboard, each rotor forward, the
+// reflector, each rotor backward and the plugboard again. It is meant for
+// visualizations and for debugging custom components; the machine advances
+// exactly as it would with Encrypt.
+func (e *Enigma) EncryptTraced(text string) ([]TraceStep, string, error) {
+	var traces []TraceStep
+	ciphertext, err := e.processText(text, true, &traces)
+	if err != nil {
+		return nil, "", err
+	}
+	return traces, ciphertext, nil
+}
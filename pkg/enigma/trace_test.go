@@ -0,0 +1,97 @@
+package enigma
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEncryptTraced(t *testing.T) {
+	machine, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatal(err)
+	}
+	traces, ciphertext, err := machine.EncryptTraced("AAAAA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ciphertext != "BDZGO" || len(traces) != 5 {
+		t.Fatalf("EncryptTraced = %s with %d steps, want BDZGO with 5", ciphertext, len(traces))
+	}
+
+	// The textbook path of the first A with rotors I II III at AAB:
+	// A -> III C -> II D -> I F -> UKW-B S -> I S -> II E -> III B
+	want := []TraceStage{
+		{TracePlugboard, -1, 0, 0},
+		{TraceRotorForward, 2, 0, 2},
+		{TraceRotorForward, 1, 2, 3},
+		{TraceRotorForward, 0, 3, 5},
+		{TraceReflector, -1, 5, 18},
+		{TraceRotorBackward, 0, 18, 18},
+		{TraceRotorBackward, 1, 18, 4},
+		{TraceRotorBackward, 2, 4, 1},
+		{TracePlugboardReturn, -1, 1, 1},
+	}
+	first := traces[0]
+	if first.Input != 'A' || first.Output != 'B' || !reflect.DeepEqual(first.Positions, []int{0, 0, 1}) {
+		t.Errorf("first step = %c -> %c at %v", first.Input, first.Output, first.Positions)
+	}
+	if !reflect.DeepEqual(first.Stages, want) {
+		t.Errorf("stages = %+v\nwant %+v", first.Stages, want)
+	}
+}
+
+func TestEncryptTracedMatchesEncrypt(t *testing.T) {
+	const text = "ANXKAPITAENLEUTNANTDERRESERVE"
+	for name, build := range map[string]func() (*Enigma, error){
+		"m4": NewEnigmaM4,
+		"g":  NewEnigmaG,
+	} {
+		plain, err := build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		traced, _ := build()
+		want, _ := plain.Encrypt(text)
+		traces, got, err := traced.EncryptTraced(text)
+		if err != nil || got != want {
+			t.Fatalf("%s: EncryptTraced = %q, %v; want %q", name, got, err, want)
+		}
+		if !reflect.DeepEqual(traced.GetCurrentRotorPositions(), plain.GetCurrentRotorPositions()) {
+			t.Errorf("%s: tracing moved the rotors differently", name)
+		}
+		for _, step := range traces {
+			// Each stage starts where the previous one ended
+			for i := 1; i < len(step.Stages); i++ {
+				if step.Stages[i].Input != step.Stages[i-1].Output {
+					t.Fatalf("%s: step %d breaks at stage %d: %+v", name, step.Index, i, step.Stages)
+				}
+			}
+			last := step.Stages[len(step.Stages)-1]
+			if out, _ := plain.alphabet.IndexToRune(last.Output); out != step.Output {
+				t.Errorf("%s: step %d ends at %c, want %c", name, step.Index, out, step.Output)
+			}
+		}
+		// Plugboard, entry wheel, rotors, reflector, rotors, entry wheel, plugboard
+		stages := 1 + 2*traced.GetRotorCount() + 2
+		if traced.GetEntryWheel() != "" {
+			stages += 2
+		}
+		if len(traces[0].Stages) != stages {
+			t.Errorf("%s: %d stages, want %d", name, len(traces[0].Stages), stages)
+		}
+	}
+
+	machine, _ := NewEnigmaM3()
+	if traces, _, err := machine.EncryptTraced("abc"); err == nil || traces != nil {
+		t.Errorf("expected an error for lowercase input, got %v", err)
+	}
+}
+
+func TestTraceStageKindString(t *testing.T) {
+	if got := TraceEntryWheelReturn.String(); got != "entry wheel return" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := TraceStageKind(99).String(); got != "unknown" {
+		t.Errorf("String() = %q", got)
+	}
+}
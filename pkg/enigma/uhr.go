@@ -0,0 +1,119 @@
+// Package enigma provides the Uhr plugboard attachment.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/coredds/enigoma/internal/alphabet"
+	"github.com/coredds/enigoma/internal/plugboard"
+	"github.com/coredds/enigoma/internal/uhr"
+)
+
+// UhrPositions is the number of positions of the Uhr's knob.
+const UhrPositions = uhr.Positions
+
+// WithUhr routes the plugboard pairs through the Uhr, a 40-position switch
+// that the Luftwaffe attached to the plugboard from 1944. Instead of
+// swapping the two characters of a pair, the Uhr connects them through a
+// rotating disc, so the plugboard is no longer reciprocal except at position
+// 0, where it works like ordinary cables. The machine as a whole stays
+// reciprocal.
+//
+// The Uhr needs exactly ten plugboard pairs. The character of each pair that
+// comes first in the alphabet goes in the a-plug, and pairs are numbered in
+// alphabet order of their a-plug. Apply it after the plugboard pairs are
+// configured.
+func WithUhr(position int) Option {
+	return func(e *Enigma) error {
+		if e.plugboard == nil {
+			return fmt.Errorf("plugboard pairs must be set before attaching the Uhr")
+		}
+		u, err := newUhr(e.plugboard, e.alphabet, position)
+		if err != nil {
+			return err
+		}
+		e.uhr = u
+		return nil
+	}
+}
+
+// newUhr plugs the plugboard's pairs into an Uhr set to position.
+func newUhr(pb *plugboard.Plugboard, alph *alphabet.Alphabet, position int) (*uhr.Uhr, error) {
+	runePairs, err := pb.GetPairs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plugboard pairs: %v", err)
+	}
+	if len(runePairs) != uhr.Pairs {
+		return nil, fmt.Errorf("the Uhr needs exactly %d plugboard pairs, got %d", uhr.Pairs, len(runePairs))
+	}
+	pairs := make([][2]int, len(runePairs))
+	for i, pair := range runePairs {
+		for j, r := range pair {
+			if pairs[i][j], err = alph.RuneToIndex(r); err != nil {
+				return nil, err
+			}
+		}
+		if pairs[i][0] > pairs[i][1] {
+			pairs[i][0], pairs[i][1] = pairs[i][1], pairs[i][0]
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i][0] < pairs[j][0] })
+	return uhr.New(alph.Size(), pairs, position)
+}
+
+// HasUhr reports whether the plugboard pairs go through the Uhr.
+func (e *Enigma) HasUhr() bool {
+	return e.uhr != nil
+}
+
+// GetUhrPosition returns the position of the Uhr.
+func (e *Enigma) GetUhrPosition() (int, error) {
+	if e.uhr == nil {
+		return 0, fmt.Errorf("the machine has no Uhr")
+	}
+	return e.uhr.Position(), nil
+}
+
+// SetUhrPosition turns the Uhr to a position from 0 to 39. Like the
+// plugboard pairs, the position is part of the daily key.
+func (e *Enigma) SetUhrPosition(position int) error {
+	if e.uhr == nil {
+		return fmt.Errorf("the machine has no Uhr")
+	}
+	return e.uhr.SetPosition(position)
+}
+
+// plugIn passes a character from the keyboard through the plugboard, or the
+// Uhr when one is attached. Without a plugboard it passes straight on.
+func (e *Enigma) plugIn(idx int, trace *TraceStep) int {
+	if e.plugboardDisabled {
+		return idx
+	}
+	var out int
+	if e.uhr != nil {
+		out = e.uhr.Forward(idx)
+	} else {
+		out = e.plugboard.Process(idx)
+	}
+	trace.record(TracePlugboard, -1, idx, out)
+	return out
+}
+
+// plugOut passes a character from the entry wheel back to the lamps.
+func (e *Enigma) plugOut(idx int, trace *TraceStep) int {
+	if e.plugboardDisabled {
+		return idx
+	}
+	var out int
+	if e.uhr != nil {
+		out = e.uhr.Backward(idx)
+	} else {
+		out = e.plugboard.Process(idx)
+	}
+	trace.record(TracePlugboardReturn, -1, idx, out)
+	return out
+}
@@ -0,0 +1,14 @@
+//go:build !tinygo
+
+package enigma
+
+import "testing"
+
+func TestUhrFingerprint(t *testing.T) {
+	// The Uhr changes the fingerprint; keys without one keep theirs
+	withUhr, _ := newUhrM3(t, 0).Fingerprint()
+	without, _ := newUhrM3(t, -1).Fingerprint()
+	if withUhr == without {
+		t.Error("the Uhr should change the fingerprint")
+	}
+}
@@ -0,0 +1,106 @@
+package enigma
+
+import (
+	"strings"
+	"testing"
+)
+
+// uhrPairs are ten plugboard pairs, as the Uhr requires.
+var uhrPairs = map[rune]rune{
+	'A': 'D', 'C': 'N', 'E': 'T', 'F': 'L', 'G': 'I',
+	'J': 'V', 'K': 'Z', 'P': 'U', 'Q': 'Y', 'W': 'X',
+}
+
+// newUhrM3 returns an M3 with uhrPairs, wired through an Uhr at position
+// unless position is negative.
+func newUhrM3(t *testing.T, position int) *Enigma {
+	t.Helper()
+	machine, err := NewEnigmaM3()
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings, err := machine.GetSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings.PlugboardPairs = make(map[rune]rune)
+	for a, b := range uhrPairs {
+		settings.PlugboardPairs[a] = b
+		settings.PlugboardPairs[b] = a
+	}
+	settings.Uhr = position >= 0
+	settings.UhrPosition = max(position, 0)
+	if err := machine.LoadSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+	return machine
+}
+
+func TestUhr(t *testing.T) {
+	const plaintext = "DERFUEHRERISTTOTDERKAMPFGEHTWEITER"
+	encrypt := func(m *Enigma) string {
+		t.Helper()
+		ciphertext, err := m.Encrypt(plaintext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ciphertext
+	}
+
+	// At position 0 the Uhr works like the plugboard cables
+	cables := encrypt(newUhrM3(t, -1))
+	if got := encrypt(newUhrM3(t, 0)); got != cables {
+		t.Errorf("Uhr at 0 = %s, want %s", got, cables)
+	}
+
+	for _, position := range []int{1, 7, 39} {
+		ciphertext := encrypt(newUhrM3(t, position))
+		if ciphertext == cables {
+			t.Errorf("Uhr at %d did not change the ciphertext", position)
+		}
+		if got, err := newUhrM3(t, position).Decrypt(ciphertext); err != nil || got != plaintext {
+			t.Errorf("Uhr at %d: decrypt = %q, %v", position, got, err)
+		}
+	}
+
+	machine := newUhrM3(t, 5)
+	if !machine.HasUhr() || !machine.Capabilities().Uhr {
+		t.Error("the machine should report its Uhr")
+	}
+	if err := machine.SetUhrPosition(12); err != nil {
+		t.Fatal(err)
+	}
+	if position, err := machine.GetUhrPosition(); err != nil || position != 12 {
+		t.Errorf("GetUhrPosition() = %d, %v; want 12", position, err)
+	}
+	if err := machine.SetUhrPosition(UhrPositions); err == nil {
+		t.Error("expected an error for position 40")
+	}
+	clone, err := machine.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if encrypt(clone) != encrypt(machine) {
+		t.Error("the clone enciphers differently")
+	}
+
+	plain := newUhrM3(t, -1)
+	if _, err := plain.GetUhrPosition(); err == nil {
+		t.Error("expected an error without an Uhr")
+	}
+}
+
+func TestWithUhrErrors(t *testing.T) {
+	if _, err := New(WithUhr(0)); err == nil || !strings.Contains(err.Error(), "plugboard") {
+		t.Errorf("expected an error before the plugboard is set, got %v", err)
+	}
+	_, err := New(
+		WithAlphabet([]rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")),
+		WithRandomComponents(3, 0),
+		WithPlugboardConfiguration(map[rune]rune{'A': 'B', 'B': 'A'}),
+		WithUhr(0),
+	)
+	if err == nil || !strings.Contains(err.Error(), "exactly 10") {
+		t.Errorf("expected an error for one pair, got %v", err)
+	}
+}
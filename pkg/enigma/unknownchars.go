@@ -0,0 +1,115 @@
+// Package enigma provides the handling of characters outside the alphabet.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UnknownCharPolicy selects what Encrypt and Decrypt do with characters
+// outside the machine's alphabet.
+type UnknownCharPolicy int
+
+const (
+	// UnknownCharError rejects text containing such characters. It is the
+	// default.
+	UnknownCharError UnknownCharPolicy = iota
+	// UnknownCharPass copies them to the output unchanged without stepping
+	// the rotors, as operators left spaces and punctuation alone.
+	UnknownCharPass
+	// UnknownCharSkip drops them from the output.
+	UnknownCharSkip
+)
+
+// String returns the policy's name as written in settings files.
+func (p UnknownCharPolicy) String() string {
+	switch p {
+	case UnknownCharError:
+		return "error"
+	case UnknownCharPass:
+		return "pass"
+	case UnknownCharSkip:
+		return "skip"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseUnknownCharPolicy parses a policy name as returned by
+// UnknownCharPolicy.String. An empty name is the default, UnknownCharError.
+func ParseUnknownCharPolicy(name string) (UnknownCharPolicy, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "error":
+		return UnknownCharError, nil
+	case "pass":
+		return UnknownCharPass, nil
+	case "skip":
+		return UnknownCharSkip, nil
+	default:
+		return UnknownCharError, fmt.Errorf("unknown character policy: %s. Available: error, pass, skip", name)
+	}
+}
+
+// valid reports whether p is one of the defined policies.
+func (p UnknownCharPolicy) valid() bool {
+	return p >= UnknownCharError && p <= UnknownCharSkip
+}
+
+// WithUnknownCharPolicy selects what happens to characters outside the
+// alphabet. The policy is saved with the settings (schema version 2) but is
+// not part of the fingerprint: it changes how text is handled, not the key.
+func WithUnknownCharPolicy(policy UnknownCharPolicy) Option {
+	return func(e *Enigma) error {
+		if !policy.valid() {
+			return fmt.Errorf("invalid unknown character policy: %d", policy)
+		}
+		e.unknownChars = policy
+		return nil
+	}
+}
+
+// GetUnknownCharPolicy returns what happens to characters outside the alphabet.
+func (e *Enigma) GetUnknownCharPolicy() UnknownCharPolicy {
+	return e.unknownChars
+}
+
+// splitUnknown removes the characters outside the alphabet from text. With
+// UnknownCharPass it also returns the layout of the input, one entry per
+// character (grapheme cluster, in grapheme mode): the character itself where
+// it passes through, "" where an enciphered character goes.
+func (e *Enigma) splitUnknown(text string) (string, []string) {
+	var known strings.Builder
+	var layout []string
+	for _, c := range e.alphabet.Segment(text) {
+		if e.alphabet.ContainsSymbol(c) {
+			known.WriteString(c)
+			if e.unknownChars == UnknownCharPass {
+				layout = append(layout, "")
+			}
+			continue
+		}
+		if e.unknownChars == UnknownCharPass {
+			layout = append(layout, c)
+		}
+	}
+	return known.String(), layout
+}
+
+// mergeUnknown puts the passed-through characters of layout back around the
+// enciphered characters.
+func mergeUnknown(enciphered []string, layout []string) string {
+	var out strings.Builder
+	k := 0
+	for _, c := range layout {
+		if c == "" {
+			out.WriteString(enciphered[k])
+			k++
+			continue
+		}
+		out.WriteString(c)
+	}
+	return out.String()
+}
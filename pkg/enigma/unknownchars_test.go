@@ -0,0 +1,73 @@
+package enigma
+
+import "testing"
+
+func TestUnknownCharPolicy(t *testing.T) {
+	const text = "HELLO, WORLD!"
+	strict, err := NewEnigmaClassic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := strict.Encrypt(text); err == nil {
+		t.Error("the default policy should reject characters outside the alphabet")
+	}
+	base, err := strict.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	letters, err := strict.Encrypt("HELLOWORLD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		policy UnknownCharPolicy
+		want   string
+	}{
+		{UnknownCharPass, letters[:5] + ", " + letters[5:] + "!"},
+		{UnknownCharSkip, letters},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			machine, err := base.Clone()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := WithUnknownCharPolicy(tt.policy)(machine); err != nil {
+				t.Fatal(err)
+			}
+			got, err := machine.Encrypt(text)
+			if err != nil {
+				t.Fatalf("Encrypt failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Encrypt(%q) = %q, want %q", text, got, tt.want)
+			}
+			if err := machine.Reset(); err != nil {
+				t.Fatal(err)
+			}
+			if back, _ := machine.Decrypt(got); tt.policy == UnknownCharPass && back != text {
+				t.Errorf("Decrypt = %q, want %q", back, text)
+			}
+		})
+	}
+}
+
+func TestParseUnknownCharPolicy(t *testing.T) {
+	for _, p := range []UnknownCharPolicy{UnknownCharError, UnknownCharPass, UnknownCharSkip} {
+		got, err := ParseUnknownCharPolicy(p.String())
+		if err != nil || got != p {
+			t.Errorf("ParseUnknownCharPolicy(%q) = %v, %v", p.String(), got, err)
+		}
+	}
+	if _, err := ParseUnknownCharPolicy("drop"); err == nil {
+		t.Error("an unknown policy name should be rejected")
+	}
+	machine, err := NewEnigmaClassic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := WithUnknownCharPolicy(UnknownCharPolicy(7))(machine); err == nil {
+		t.Error("an invalid policy should be rejected")
+	}
+}
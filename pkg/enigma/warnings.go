@@ -0,0 +1,107 @@
+// Package enigma provides structured warnings for non-fatal conditions.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package enigma
+
+import "fmt"
+
+// WarningCode identifies a kind of non-fatal condition. Codes are stable and
+// safe to match on; messages are for humans and may change.
+type WarningCode string
+
+const (
+	// WarnAlphabetPadded means a character was added to make the alphabet even.
+	WarnAlphabetPadded WarningCode = "alphabet_padded"
+	// WarnAlphabetDropped means a character was left out to make the alphabet
+	// even and is dropped from the input.
+	WarnAlphabetDropped WarningCode = "alphabet_character_dropped"
+	// WarnAlphabetMerged means a character was left out to make the alphabet
+	// even and is written as a variant already in it.
+	WarnAlphabetMerged WarningCode = "alphabet_characters_merged"
+	// WarnInputNormalized means line endings or surrounding whitespace were folded.
+	WarnInputNormalized WarningCode = "input_normalized"
+	// WarnControlSkipped means control characters were left out of the alphabet.
+	WarnControlSkipped WarningCode = "control_characters_skipped"
+	// WarnAlphabetTruncated means the alphabet hit its size limit.
+	WarnAlphabetTruncated WarningCode = "alphabet_truncated"
+	// WarnPlugboardCapped means fewer plugboard pairs fit than the level asks for.
+	WarnPlugboardCapped WarningCode = "plugboard_capped"
+	// WarnDegenerateRotor means a rotor is wired as the identity and adds nothing.
+	WarnDegenerateRotor WarningCode = "degenerate_rotor"
+	// WarnKeyExpired means the key's metadata marks it as expired.
+	WarnKeyExpired WarningCode = "key_expired"
+	// WarnCaseLost means case folding cannot write some alphabet characters
+	// in lowercase, so letters enciphered to them lose their case.
+	WarnCaseLost WarningCode = "case_lost"
+)
+
+// Warning describes a non-fatal condition noticed while building or using a machine.
+type Warning struct {
+	Code    WarningCode `json:"code"`
+	Message string      `json:"message"`
+}
+
+// String returns the warning as "code: message".
+func (w Warning) String() string {
+	return fmt.Sprintf("%s: %s", w.Code, w.Message)
+}
+
+// WarningHandler is invoked for each warning as it is raised.
+type WarningHandler func(w Warning)
+
+// WithWarningHandler registers a handler for warnings. Warnings raised by
+// options applied before this one are still available from Warnings, so pass
+// it first to see everything as it happens.
+func WithWarningHandler(h WarningHandler) Option {
+	return func(e *Enigma) error {
+		e.onWarning = h
+		return nil
+	}
+}
+
+// SetWarningHandler replaces the warning handler. Passing nil disables it.
+func (e *Enigma) SetWarningHandler(h WarningHandler) {
+	e.onWarning = h
+}
+
+// Warnings returns the warnings raised since the machine was created or
+// ClearWarnings was last called.
+func (e *Enigma) Warnings() []Warning {
+	warnings := make([]Warning, len(e.warnings))
+	copy(warnings, e.warnings)
+	return warnings
+}
+
+// ClearWarnings discards the collected warnings.
+func (e *Enigma) ClearWarnings() {
+	e.warnings = nil
+}
+
+// warn records a warning and passes it to the handler, if any.
+func (e *Enigma) warn(code WarningCode, format string, args ...interface{}) {
+	w := Warning{Code: code, Message: fmt.Sprintf(format, args...)}
+	e.warnings = append(e.warnings, w)
+	if e.onWarning != nil {
+		e.onWarning(w)
+	}
+}
+
+// checkComponents warns about components that weaken the machine without
+// making it invalid.
+func (e *Enigma) checkComponents() {
+	for i := range e.rotors {
+		e.checkRotorWiring(i)
+	}
+}
+
+// checkRotorWiring warns when the rotor in slot i is wired as the identity.
+func (e *Enigma) checkRotorWiring(i int) {
+	r := e.rotors[i]
+	for idx := 0; idx < e.alphabet.Size(); idx++ {
+		if r.Forward(idx) != idx {
+			return
+		}
+	}
+	e.warn(WarnDegenerateRotor, "rotor %d (%s) maps every character to itself", i+1, r.ID())
+}
@@ -0,0 +1,123 @@
+package enigma
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/coredds/enigoma/internal/reflector"
+	"github.com/coredds/enigoma/internal/rotor"
+)
+
+func hasWarning(warnings []Warning, code WarningCode) bool {
+	for _, w := range warnings {
+		if w.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+func TestWarningsFromAutoDetection(t *testing.T) {
+	machine, err := NewFromText("ABC\r\n", Low)
+	if err != nil {
+		t.Fatalf("NewFromText failed: %v", err)
+	}
+	warnings := machine.Warnings()
+	if !hasWarning(warnings, WarnAlphabetPadded) || !hasWarning(warnings, WarnInputNormalized) {
+		t.Errorf("expected padding and normalization warnings, got %v", warnings)
+	}
+
+	// Clones carry the warnings; clearing only affects the original
+	clone, _ := machine.Clone()
+	machine.ClearWarnings()
+	if len(machine.Warnings()) != 0 || len(clone.Warnings()) != len(warnings) {
+		t.Errorf("unexpected warnings after clear: %v / %v", machine.Warnings(), clone.Warnings())
+	}
+
+	clean, _ := NewFromText("THEQUICKBROWNFOXJUMPSOVERLAZYDG", Low)
+	if w := clean.Warnings(); len(w) != 0 {
+		t.Errorf("clean input should not warn, got %v", w)
+	}
+}
+
+func TestAutoDetectPaddingStrategies(t *testing.T) {
+	const text = "Hello, hello"
+	merged, err := NewFromText(text, Low, WithPaddingStrategy(PadMerge))
+	if err != nil {
+		t.Fatalf("NewFromText failed: %v", err)
+	}
+	if !hasWarning(merged.Warnings(), WarnAlphabetMerged) || merged.GetAlphabetSize() != 6 {
+		t.Errorf("size %d, warnings %v", merged.GetAlphabetSize(), merged.Warnings())
+	}
+	ciphertext, err := merged.Encrypt(text)
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	merged.Reset()
+	if got, _ := merged.Decrypt(ciphertext); got != "Hello, Hello" {
+		t.Errorf("merged round trip = %q, want %q", got, "Hello, Hello")
+	}
+
+	dropped, err := NewFromText("AAABBC", Low, WithPaddingStrategy(PadDropLeastFrequent))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasWarning(dropped.Warnings(), WarnAlphabetDropped) {
+		t.Errorf("expected a dropped-character warning, got %v", dropped.Warnings())
+	}
+	if ciphertext, _ := dropped.Encrypt("AAABBC"); len(ciphertext) != 5 {
+		t.Errorf("ciphertext %q should leave out the dropped C", ciphertext)
+	}
+
+	listed, err := NewFromText("ABC", Low, WithPaddingCandidates([]rune("_")))
+	if err != nil || listed.GetAlphabetSize() != 4 {
+		t.Fatalf("list padding: %v", err)
+	}
+	if _, err := NewFromText("ABC", Low, WithPaddingStrategy(PadMerge)); err == nil {
+		t.Error("expected an error when nothing can be merged")
+	}
+
+	withOption, err := New(WithDetectedAlphabet("ABC", WithPaddingCandidates([]rune("_"))), WithRandomSettings(Low))
+	if err != nil || !hasWarning(withOption.Warnings(), WarnAlphabetPadded) {
+		t.Errorf("WithDetectedAlphabet: %v, warnings %v", err, withOption.Warnings())
+	}
+}
+
+func TestWarningHandler(t *testing.T) {
+	var got []Warning
+	machine, err := New(
+		WithWarningHandler(func(w Warning) { got = append(got, w) }),
+		WithAlphabet([]rune("ABCDEFGHIJ")),
+		WithRandomSettings(Extreme),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if !hasWarning(got, WarnPlugboardCapped) {
+		t.Errorf("handler should receive the plugboard cap warning, got %v", got)
+	}
+	if len(got) != len(machine.Warnings()) {
+		t.Errorf("handler saw %d warnings, machine recorded %d", len(got), len(machine.Warnings()))
+	}
+	if s := got[0].String(); !strings.HasPrefix(s, string(WarnPlugboardCapped)+": ") {
+		t.Errorf("String() should start with the code, got %q", s)
+	}
+}
+
+func TestDegenerateRotorWarning(t *testing.T) {
+	machine, err := New(
+		WithAlphabet([]rune("ABCD")),
+		WithRotorConfiguration([]rotor.RotorSpec{
+			{ID: "FLAT", ForwardMapping: "ABCD", Notches: []rune{'A'}},
+			{ID: "OK", ForwardMapping: "BCDA", Notches: []rune{'A'}},
+		}),
+		WithReflectorConfiguration(reflector.ReflectorSpec{ID: "R", Mapping: "BADC"}),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	warnings := machine.Warnings()
+	if len(warnings) != 1 || warnings[0].Code != WarnDegenerateRotor {
+		t.Errorf("expected one degenerate rotor warning, got %v", warnings)
+	}
+}
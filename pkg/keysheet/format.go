@@ -0,0 +1,173 @@
+// Package keysheet provides the text format of key sheets.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package keysheet
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// header is the column heading line of a printed sheet.
+const header = "Datum | Walzenlage   | Ringstellung | Steckerverbindungen           | Kenngruppen"
+
+// Format renders the sheet as text. Like the historical sheets, days are
+// listed last to first so the used lines could be cut off and destroyed.
+//
+//	# Network: Heer
+//	# Month: 2025-03
+//	# Reflector: B
+//	Datum | Walzenlage   | Ringstellung | Steckerverbindungen           | Kenngruppen
+//	31    | IV II V      | 06 21 14     | AT BL CV DQ EJ FR GN HS IK MZ | wxm ejh ryq lpa
+func (s *Sheet) Format() string {
+	var b strings.Builder
+	if s.Network != "" {
+		fmt.Fprintf(&b, "# Network: %s\n", s.Network)
+	}
+	fmt.Fprintf(&b, "# Month: %04d-%02d\n", s.Year, s.Month)
+	fmt.Fprintf(&b, "# Reflector: %s\n", s.Reflector)
+	b.WriteString(header + "\n")
+
+	days := append([]DayKey(nil), s.Days...)
+	sort.Slice(days, func(i, j int) bool { return days[i].Day > days[j].Day })
+	for _, key := range days {
+		fmt.Fprintf(&b, "%-5d | %-12s | %02d %02d %02d     | %-29s | %s\n",
+			key.Day, strings.Join(key.Rotors[:], " "),
+			key.Rings[0], key.Rings[1], key.Rings[2],
+			strings.Join(key.Plugboard, " "),
+			strings.Join(key.Kenngruppen[:], " "))
+	}
+	return b.String()
+}
+
+// Parse reads a sheet in the format written by Format. Comment lines other
+// than the Network, Month and Reflector fields are ignored, as are blank lines
+// and the column heading; days may appear in any order. A sheet without a
+// Reflector line uses reflector B.
+func Parse(r io.Reader) (*Sheet, error) {
+	sheet := &Sheet{Reflector: "B"}
+	seen := map[int]bool{}
+	haveMonth := false
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "Datum") {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			name, value, ok := strings.Cut(strings.TrimSpace(line[1:]), ":")
+			if !ok {
+				continue
+			}
+			value = strings.TrimSpace(value)
+			switch strings.ToLower(strings.TrimSpace(name)) {
+			case "network":
+				sheet.Network = value
+			case "month":
+				month, err := time.Parse("2006-01", value)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: invalid month %q (want YYYY-MM)", lineNo, value)
+				}
+				sheet.Year, sheet.Month = month.Year(), month.Month()
+				haveMonth = true
+			case "reflector":
+				if _, ok := reflectors[value]; !ok {
+					return nil, fmt.Errorf("line %d: unknown reflector %q", lineNo, value)
+				}
+				sheet.Reflector = value
+			}
+			continue
+		}
+
+		key, err := parseDay(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNo, err)
+		}
+		if seen[key.Day] {
+			return nil, fmt.Errorf("line %d: day %d is listed twice", lineNo, key.Day)
+		}
+		seen[key.Day] = true
+		sheet.Days = append(sheet.Days, key)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read key sheet: %v", err)
+	}
+
+	if !haveMonth {
+		return nil, fmt.Errorf("key sheet has no '# Month: YYYY-MM' line")
+	}
+	if len(sheet.Days) == 0 {
+		return nil, fmt.Errorf("key sheet lists no days")
+	}
+	last := daysIn(sheet.Year, sheet.Month)
+	for _, key := range sheet.Days {
+		if key.Day > last {
+			return nil, fmt.Errorf("day %d does not exist in %04d-%02d", key.Day, sheet.Year, sheet.Month)
+		}
+	}
+	sort.Slice(sheet.Days, func(i, j int) bool { return sheet.Days[i].Day < sheet.Days[j].Day })
+	return sheet, nil
+}
+
+// parseDay parses one "Datum | Walzenlage | Ringstellung | Stecker | Kenngruppen" line.
+func parseDay(line string) (DayKey, error) {
+	var key DayKey
+	fields := strings.Split(line, "|")
+	if len(fields) != 5 {
+		return key, fmt.Errorf("expected 5 columns separated by '|', found %d", len(fields))
+	}
+
+	day, err := strconv.Atoi(strings.TrimSpace(fields[0]))
+	if err != nil {
+		return key, fmt.Errorf("invalid day %q", strings.TrimSpace(fields[0]))
+	}
+	key.Day = day
+
+	rotors := strings.Fields(strings.ToUpper(fields[1]))
+	if len(rotors) != len(key.Rotors) {
+		return key, fmt.Errorf("day %d: expected %d rotors, found %d", day, len(key.Rotors), len(rotors))
+	}
+	copy(key.Rotors[:], rotors)
+
+	rings := strings.Fields(fields[2])
+	if len(rings) != len(key.Rings) {
+		return key, fmt.Errorf("day %d: expected %d ring settings, found %d", day, len(key.Rings), len(rings))
+	}
+	for i, ring := range rings {
+		if key.Rings[i], err = parseRing(ring); err != nil {
+			return key, fmt.Errorf("day %d: %v", day, err)
+		}
+	}
+
+	key.Plugboard = strings.Fields(strings.ToUpper(fields[3]))
+
+	groups := strings.Fields(strings.ToLower(fields[4]))
+	if len(groups) != len(key.Kenngruppen) {
+		return key, fmt.Errorf("day %d: expected %d Kenngruppen, found %d", day, len(key.Kenngruppen), len(groups))
+	}
+	copy(key.Kenngruppen[:], groups)
+
+	return key, key.Validate()
+}
+
+// parseRing reads a ring setting written as a number ("06") or a letter ("F").
+func parseRing(s string) (int, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n, nil
+	}
+	if len(s) == 1 {
+		if i := strings.IndexByte(letters, strings.ToUpper(s)[0]); i >= 0 {
+			return i + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid ring setting %q", s)
+}
@@ -0,0 +1,278 @@
+// Package keysheet provides monthly key sheets (Tagesschlüssel) in the style
+// of the historical Enigma networks: for every day of the month a rotor
+// order, ring settings, plugboard connections and Kenngruppen (the
+// three-letter groups that identified which key a message used).
+//
+// A sheet selects a historical Enigma M3 setup, so the machines it configures
+// are compatible with other M3 simulators but offer no modern security.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package keysheet
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/coredds/enigoma/internal/provenance"
+	"github.com/coredds/enigoma/internal/random"
+	"github.com/coredds/enigoma/internal/reflector"
+	"github.com/coredds/enigoma/internal/rotor"
+	"github.com/coredds/enigoma/pkg/enigma"
+)
+
+const (
+	// PlugboardPairs is the number of plugboard connections on a generated
+	// sheet, the standard practice from 1939 onwards.
+	PlugboardPairs = 10
+
+	// Kenngruppen is the number of identification groups listed per day.
+	Kenngruppen = 4
+
+	letters = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+)
+
+// wheel is a historical rotor that can appear in a Walzenlage.
+type wheel struct {
+	wiring  string
+	notches []rune
+}
+
+// wheels holds the rotors a sheet may name. Generated sheets draw from the
+// five Army rotors I–V; VI–VIII are accepted when parsing naval sheets.
+var wheels = map[string]wheel{
+	"I":    {enigma.RotorI, enigma.NotchI},
+	"II":   {enigma.RotorII, enigma.NotchII},
+	"III":  {enigma.RotorIII, enigma.NotchIII},
+	"IV":   {enigma.RotorIV, enigma.NotchIV},
+	"V":    {enigma.RotorV, enigma.NotchV},
+	"VI":   {enigma.RotorVI, enigma.NotchVI},
+	"VII":  {enigma.RotorVII, enigma.NotchVII},
+	"VIII": {enigma.RotorVIII, enigma.NotchVIII},
+}
+
+// armyWheels are the rotors generated sheets choose from.
+var armyWheels = []string{"I", "II", "III", "IV", "V"}
+
+// reflectors holds the reflectors a sheet may name.
+var reflectors = map[string]string{
+	"A": enigma.ReflectorA,
+	"B": enigma.ReflectorB,
+	"C": enigma.ReflectorC,
+}
+
+// Sheet is the key sheet for one month of one network.
+type Sheet struct {
+	Network   string     // Name of the key net, e.g. "Heer"; optional
+	Year      int        // Year the sheet is valid for
+	Month     time.Month // Month the sheet is valid for
+	Reflector string     // Reflector used all month: "A", "B" or "C"
+	Days      []DayKey   // One entry per day, in ascending order
+}
+
+// DayKey is the daily key from one line of a sheet.
+type DayKey struct {
+	Day         int                 // Day of the month
+	Rotors      [3]string           // Walzenlage, left to right, e.g. {"IV", "II", "V"}
+	Rings       [3]int              // Ringstellung, 1–26, left to right
+	Plugboard   []string            // Steckerverbindungen, e.g. "AT"
+	Kenngruppen [Kenngruppen]string // Three-letter identification groups, lowercase
+}
+
+// Generate creates a key sheet for the given month with cryptographically
+// random daily keys and reflector B.
+func Generate(network string, year int, month time.Month) (*Sheet, error) {
+	return generate(network, year, month, random.Crypto)
+}
+
+// GenerateSeeded creates a key sheet deterministically from seed, so every
+// holder of the seed can print the same sheet.
+func GenerateSeeded(network string, year int, month time.Month, seed []byte) (*Sheet, error) {
+	if len(seed) == 0 {
+		return nil, fmt.Errorf("seed cannot be empty")
+	}
+	return generate(network, year, month, random.NewDeterministic(seed))
+}
+
+func generate(network string, year int, month time.Month, src random.Source) (*Sheet, error) {
+	if month < time.January || month > time.December {
+		return nil, fmt.Errorf("invalid month: %d", month)
+	}
+	sheet := &Sheet{Network: network, Year: year, Month: month, Reflector: "B"}
+	for day := 1; day <= daysIn(year, month); day++ {
+		key, err := generateDay(day, src)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate key for day %d: %v", day, err)
+		}
+		sheet.Days = append(sheet.Days, key)
+	}
+	return sheet, nil
+}
+
+func generateDay(day int, src random.Source) (DayKey, error) {
+	key := DayKey{Day: day}
+
+	order, err := shuffled(len(armyWheels), src)
+	if err != nil {
+		return key, err
+	}
+	for i := range key.Rotors {
+		key.Rotors[i] = armyWheels[order[i]]
+		ring, err := src.Intn(26)
+		if err != nil {
+			return key, err
+		}
+		key.Rings[i] = ring + 1
+	}
+
+	plugs, err := shuffled(26, src)
+	if err != nil {
+		return key, err
+	}
+	for i := 0; i < PlugboardPairs; i++ {
+		a, b := letters[plugs[2*i]], letters[plugs[2*i+1]]
+		if a > b {
+			a, b = b, a
+		}
+		key.Plugboard = append(key.Plugboard, string([]byte{a, b}))
+	}
+
+	for i := range key.Kenngruppen {
+		group := make([]byte, 3)
+		for j := range group {
+			n, err := src.Intn(26)
+			if err != nil {
+				return key, err
+			}
+			group[j] = 'a' + byte(n)
+		}
+		key.Kenngruppen[i] = string(group)
+	}
+	return key, nil
+}
+
+// shuffled returns a random permutation of 0..n-1 (Fisher–Yates).
+func shuffled(n int, src random.Source) ([]int, error) {
+	perm := make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+	for i := n - 1; i > 0; i-- {
+		j, err := src.Intn(i + 1)
+		if err != nil {
+			return nil, err
+		}
+		perm[i], perm[j] = perm[j], perm[i]
+	}
+	return perm, nil
+}
+
+// daysIn returns the number of days in the given month.
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// KeyFor returns the daily key for date, which must fall in the sheet's month.
+func (s *Sheet) KeyFor(date time.Time) (DayKey, error) {
+	if date.Year() != s.Year || date.Month() != s.Month {
+		return DayKey{}, fmt.Errorf("%s is not covered by the sheet for %04d-%02d",
+			date.Format("2006-01-02"), s.Year, s.Month)
+	}
+	for _, key := range s.Days {
+		if key.Day == date.Day() {
+			return key, nil
+		}
+	}
+	return DayKey{}, fmt.Errorf("the sheet has no key for %s", date.Format("2006-01-02"))
+}
+
+// Machine returns an Enigma M3 set up with the daily key for date. The rotors
+// start at AAA; set the message key with enigma.WithRotorPositions.
+func (s *Sheet) Machine(date time.Time, opts ...enigma.Option) (*enigma.Enigma, error) {
+	key, err := s.KeyFor(date)
+	if err != nil {
+		return nil, err
+	}
+	return key.Machine(s.Reflector, opts...)
+}
+
+// Machine returns an Enigma M3 with this daily key and the named reflector.
+// Additional options are applied after the key.
+func (k DayKey) Machine(reflectorID string, opts ...enigma.Option) (*enigma.Enigma, error) {
+	if err := k.Validate(); err != nil {
+		return nil, err
+	}
+	mapping, ok := reflectors[reflectorID]
+	if !ok {
+		return nil, fmt.Errorf("unknown reflector: %q", reflectorID)
+	}
+
+	specs := make([]rotor.RotorSpec, len(k.Rotors))
+	for i, id := range k.Rotors {
+		w := wheels[id]
+		specs[i] = rotor.RotorSpec{
+			ID:             id,
+			ForwardMapping: w.wiring,
+			Notches:        w.notches,
+			RingSetting:    k.Rings[i] - 1,
+			Provenance:     provenance.Historical("M3", id),
+		}
+	}
+	pairs := make(map[rune]rune, 2*len(k.Plugboard))
+	for _, pair := range k.Plugboard {
+		pairs[rune(pair[0])] = rune(pair[1])
+		pairs[rune(pair[1])] = rune(pair[0])
+	}
+
+	return enigma.New(append([]enigma.Option{
+		enigma.WithAlphabet([]rune(letters)),
+		enigma.WithRotorConfiguration(specs),
+		enigma.WithReflectorConfiguration(reflector.ReflectorSpec{
+			ID:         reflectorID,
+			Mapping:    mapping,
+			Provenance: provenance.Historical("M3", reflectorID),
+		}),
+		enigma.WithPlugboardConfiguration(pairs),
+	}, opts...)...)
+}
+
+// Validate checks that the key names known, distinct rotors, ring settings in
+// 1–26, disjoint plugboard pairs and three-letter Kenngruppen.
+func (k DayKey) Validate() error {
+	if k.Day < 1 || k.Day > 31 {
+		return fmt.Errorf("invalid day: %d", k.Day)
+	}
+	for i, id := range k.Rotors {
+		if _, ok := wheels[id]; !ok {
+			return fmt.Errorf("day %d: unknown rotor %q", k.Day, id)
+		}
+		for _, other := range k.Rotors[:i] {
+			if other == id {
+				return fmt.Errorf("day %d: rotor %s is used twice", k.Day, id)
+			}
+		}
+		if k.Rings[i] < 1 || k.Rings[i] > 26 {
+			return fmt.Errorf("day %d: ring setting %d is not between 1 and 26", k.Day, k.Rings[i])
+		}
+	}
+	used := map[rune]bool{}
+	for _, pair := range k.Plugboard {
+		if len(pair) != 2 || !strings.ContainsRune(letters, rune(pair[0])) ||
+			!strings.ContainsRune(letters, rune(pair[1])) || pair[0] == pair[1] {
+			return fmt.Errorf("day %d: invalid plugboard pair %q", k.Day, pair)
+		}
+		for _, c := range pair {
+			if used[c] {
+				return fmt.Errorf("day %d: letter %c is plugged twice", k.Day, c)
+			}
+			used[c] = true
+		}
+	}
+	for _, group := range k.Kenngruppen {
+		if len(group) != 3 || strings.Trim(group, "abcdefghijklmnopqrstuvwxyz") != "" {
+			return fmt.Errorf("day %d: invalid Kenngruppe %q", k.Day, group)
+		}
+	}
+	return nil
+}
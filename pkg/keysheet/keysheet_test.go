@@ -0,0 +1,178 @@
+package keysheet
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coredds/enigoma/pkg/enigma"
+)
+
+func TestGenerate(t *testing.T) {
+	sheet, err := Generate("Heer", 2024, time.February)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if len(sheet.Days) != 29 {
+		t.Fatalf("leap February has %d days, want 29", len(sheet.Days))
+	}
+	for i, key := range sheet.Days {
+		if key.Day != i+1 {
+			t.Errorf("Days[%d].Day = %d, want %d", i, key.Day, i+1)
+		}
+		if err := key.Validate(); err != nil {
+			t.Errorf("generated key is invalid: %v", err)
+		}
+		if len(key.Plugboard) != PlugboardPairs {
+			t.Errorf("day %d has %d plugboard pairs, want %d", key.Day, len(key.Plugboard), PlugboardPairs)
+		}
+	}
+	if _, err := Generate("", 2024, 13); err == nil {
+		t.Error("expected an error for month 13")
+	}
+}
+
+func TestGenerateSeeded(t *testing.T) {
+	a, err := GenerateSeeded("Heer", 2025, time.March, []byte("shared"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := GenerateSeeded("Heer", 2025, time.March, []byte("shared"))
+	if !reflect.DeepEqual(a, b) {
+		t.Error("the same seed produced different sheets")
+	}
+	c, _ := GenerateSeeded("Heer", 2025, time.March, []byte("other"))
+	if reflect.DeepEqual(a.Days, c.Days) {
+		t.Error("different seeds produced the same sheet")
+	}
+	if _, err := GenerateSeeded("Heer", 2025, time.March, nil); err == nil {
+		t.Error("expected an error for an empty seed")
+	}
+}
+
+func TestFormatParseRoundTrip(t *testing.T) {
+	sheet, err := GenerateSeeded("Luftwaffe", 2025, time.April, []byte("round trip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := sheet.Format()
+	lines := strings.Split(text, "\n")
+	if !strings.HasPrefix(lines[4], "30 ") {
+		t.Errorf("first day line = %q, want the last day of the month first", lines[4])
+	}
+
+	parsed, err := Parse(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("Parse failed: %v\n%s", err, text)
+	}
+	if !reflect.DeepEqual(parsed, sheet) {
+		t.Errorf("round trip changed the sheet:\n got %+v\nwant %+v", parsed, sheet)
+	}
+}
+
+func TestParse(t *testing.T) {
+	sheet, err := Parse(strings.NewReader(`# Month: 1944-07
+# Reflector: C
+Datum | Walzenlage | Ringstellung | Steckerverbindungen | Kenngruppen
+2 | v i iii | 01 Z 13 | at bl | abc def ghi jkl
+1 | II IV I | 26 02 03 |  | ABC DEF GHI JKL
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if sheet.Year != 1944 || sheet.Month != time.July || sheet.Reflector != "C" || sheet.Network != "" {
+		t.Errorf("header = %d-%d %s %q", sheet.Year, sheet.Month, sheet.Reflector, sheet.Network)
+	}
+	want := DayKey{
+		Day:         2,
+		Rotors:      [3]string{"V", "I", "III"},
+		Rings:       [3]int{1, 26, 13},
+		Plugboard:   []string{"AT", "BL"},
+		Kenngruppen: [4]string{"abc", "def", "ghi", "jkl"},
+	}
+	if len(sheet.Days) != 2 || !reflect.DeepEqual(sheet.Days[1], want) {
+		t.Errorf("Days = %+v, want day 2 = %+v", sheet.Days, want)
+	}
+	if sheet.Days[0].Day != 1 || len(sheet.Days[0].Plugboard) != 0 {
+		t.Errorf("day 1 = %+v", sheet.Days[0])
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	const head = "# Month: 2025-02\n"
+	tests := map[string]string{
+		"no month":         "1 | I II III | 01 01 01 | AB | abc def ghi jkl\n",
+		"no days":          head,
+		"columns":          head + "1 | I II III | 01 01 01 | AB\n",
+		"unknown rotor":    head + "1 | I II IX | 01 01 01 | AB | abc def ghi jkl\n",
+		"repeated rotor":   head + "1 | I II I | 01 01 01 | AB | abc def ghi jkl\n",
+		"ring range":       head + "1 | I II III | 01 27 01 | AB | abc def ghi jkl\n",
+		"plugged twice":    head + "1 | I II III | 01 01 01 | AB BC | abc def ghi jkl\n",
+		"kenngruppe":       head + "1 | I II III | 01 01 01 | AB | abcd def ghi jkl\n",
+		"duplicate day":    head + "1 | I II III | 01 01 01 | AB | abc def ghi jkl\n1 | I II III | 01 01 01 | AB | abc def ghi jkl\n",
+		"day out of month": head + "30 | I II III | 01 01 01 | AB | abc def ghi jkl\n",
+		"reflector":        "# Reflector: Z\n" + head,
+	}
+	for name, text := range tests {
+		if _, err := Parse(strings.NewReader(text)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestMachineForDate(t *testing.T) {
+	sheet, err := GenerateSeeded("Heer", 2025, time.March, []byte("machine"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	date := time.Date(2025, time.March, 14, 0, 0, 0, 0, time.UTC)
+
+	sender, err := sheet.Machine(date)
+	if err != nil {
+		t.Fatalf("Machine failed: %v", err)
+	}
+	ciphertext, err := sender.Encrypt("ANGRIFFIMMORGENGRAUEN")
+	if err != nil {
+		t.Fatal(err)
+	}
+	receiver, _ := sheet.Machine(date)
+	plaintext, err := receiver.Decrypt(ciphertext)
+	if err != nil || plaintext != "ANGRIFFIMMORGENGRAUEN" {
+		t.Errorf("Decrypt = %q, %v", plaintext, err)
+	}
+
+	otherDay, _ := sheet.Machine(date.AddDate(0, 0, 1))
+	if other, _ := otherDay.Encrypt("ANGRIFFIMMORGENGRAUEN"); other == ciphertext {
+		t.Error("consecutive days produced the same ciphertext")
+	}
+
+	if _, err := sheet.Machine(time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)); err == nil {
+		t.Error("expected an error for a date outside the sheet")
+	}
+}
+
+func TestDayKeyMachineMatchesM3(t *testing.T) {
+	key := DayKey{
+		Day:         1,
+		Rotors:      [3]string{"I", "II", "III"},
+		Rings:       [3]int{1, 1, 1},
+		Kenngruppen: [4]string{"aaa", "bbb", "ccc", "ddd"},
+	}
+	machine, err := key.Machine("B")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Rotors I II III at AAA with rings 01 01 01 are the default M3 setup.
+	m3, err := enigma.NewEnigmaM3()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := m3.Encrypt("HELLOWORLD")
+	if got, _ := machine.Encrypt("HELLOWORLD"); got != want {
+		t.Errorf("Encrypt = %q, want %q as from NewEnigmaM3", got, want)
+	}
+	if _, err := key.Machine("Z"); err == nil {
+		t.Error("expected an error for an unknown reflector")
+	}
+}
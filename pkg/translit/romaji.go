@@ -0,0 +1,135 @@
+// Package translit provides Hepburn romanization of Japanese kana.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package translit
+
+import (
+	"strings"
+)
+
+// katakanaOffset maps katakana onto the matching hiragana code points.
+const katakanaOffset = 0x60
+
+// hiragana holds the Hepburn reading of each hiragana character.
+var hiragana = map[rune]string{
+	'あ': "a", 'い': "i", 'う': "u", 'え': "e", 'お': "o",
+	'か': "ka", 'き': "ki", 'く': "ku", 'け': "ke", 'こ': "ko",
+	'が': "ga", 'ぎ': "gi", 'ぐ': "gu", 'げ': "ge", 'ご': "go",
+	'さ': "sa", 'し': "shi", 'す': "su", 'せ': "se", 'そ': "so",
+	'ざ': "za", 'じ': "ji", 'ず': "zu", 'ぜ': "ze", 'ぞ': "zo",
+	'た': "ta", 'ち': "chi", 'つ': "tsu", 'て': "te", 'と': "to",
+	'だ': "da", 'ぢ': "ji", 'づ': "zu", 'で': "de", 'ど': "do",
+	'な': "na", 'に': "ni", 'ぬ': "nu", 'ね': "ne", 'の': "no",
+	'は': "ha", 'ひ': "hi", 'ふ': "fu", 'へ': "he", 'ほ': "ho",
+	'ば': "ba", 'び': "bi", 'ぶ': "bu", 'べ': "be", 'ぼ': "bo",
+	'ぱ': "pa", 'ぴ': "pi", 'ぷ': "pu", 'ぺ': "pe", 'ぽ': "po",
+	'ま': "ma", 'み': "mi", 'む': "mu", 'め': "me", 'も': "mo",
+	'や': "ya", 'ゆ': "yu", 'よ': "yo",
+	'ら': "ra", 'り': "ri", 'る': "ru", 'れ': "re", 'ろ': "ro",
+	'わ': "wa", 'ゐ': "i", 'ゑ': "e", 'を': "o", 'ん': "n",
+	'ぁ': "a", 'ぃ': "i", 'ぅ': "u", 'ぇ': "e", 'ぉ': "o", 'ゔ': "vu",
+}
+
+// smallY holds the vowels of the small ya, yu and yo used in contracted sounds.
+var smallY = map[rune]string{'ゃ': "a", 'ゅ': "u", 'ょ': "o"}
+
+// japanesePunctuation maps Japanese punctuation onto ASCII.
+var japanesePunctuation = map[rune]string{
+	'。': ".", '、': ",", '「': "\"", '」': "\"", '『': "\"", '』': "\"",
+	'・': " ", '　': " ", '！': "!", '？': "?",
+}
+
+type romaji struct{}
+
+// Romaji returns a transliterator that turns hiragana and katakana into
+// Hepburn romaji, e.g. "とうきょう" into "toukyou". Kanji have no reading without
+// a dictionary and are left unchanged.
+func Romaji() Transliterator {
+	return romaji{}
+}
+
+// Name implements Transliterator.
+func (romaji) Name() string {
+	return "romaji"
+}
+
+// Transliterate implements Transliterator.
+func (romaji) Transliterate(text string) (string, error) {
+	runes := []rune(text)
+	var b strings.Builder
+	geminate := false // a small tsu doubles the next consonant
+
+	for i := 0; i < len(runes); i++ {
+		r := toHiragana(runes[i])
+
+		if r == 'っ' {
+			geminate = true
+			continue
+		}
+		if r == 'ー' {
+			b.WriteString(lastVowel(b.String()))
+			continue
+		}
+
+		reading, ok := hiragana[r]
+		if !ok {
+			geminate = false
+			if p, ok := japanesePunctuation[r]; ok {
+				b.WriteString(p)
+			} else {
+				b.WriteRune(runes[i])
+			}
+			continue
+		}
+
+		// Contracted sounds: き + ゃ is "kya", し + ゃ is "sha"
+		if i+1 < len(runes) && strings.HasSuffix(reading, "i") && reading != "i" {
+			if vowel, ok := smallY[toHiragana(runes[i+1])]; ok {
+				stem := strings.TrimSuffix(reading, "i")
+				if stem == "sh" || stem == "ch" || stem == "j" {
+					reading = stem + vowel
+				} else {
+					reading = stem + "y" + vowel
+				}
+				i++
+			}
+		}
+
+		// ん is written n' before a vowel or y so the syllables stay apart
+		if r == 'ん' && i+1 < len(runes) {
+			if next, ok := hiragana[toHiragana(runes[i+1])]; ok && strings.ContainsAny(next[:1], "aiueoy") {
+				reading = "n'"
+			}
+		}
+
+		if geminate {
+			if strings.HasPrefix(reading, "ch") {
+				b.WriteByte('t')
+			} else if c := reading[0]; !strings.ContainsRune("aiueon", rune(c)) {
+				b.WriteByte(c)
+			}
+			geminate = false
+		}
+		b.WriteString(reading)
+	}
+	return b.String(), nil
+}
+
+// toHiragana maps a katakana character onto its hiragana counterpart.
+func toHiragana(r rune) rune {
+	if r >= 'ァ' && r <= 'ヶ' {
+		return r - katakanaOffset
+	}
+	return r
+}
+
+// lastVowel returns the final vowel written so far, for the long vowel mark.
+func lastVowel(s string) string {
+	for i := len(s) - 1; i >= 0; i-- {
+		if strings.IndexByte("aiueo", s[i]) >= 0 {
+			return s[i : i+1]
+		}
+	}
+	return ""
+}
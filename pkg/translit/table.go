@@ -0,0 +1,127 @@
+// Package translit provides dictionary-driven transliteration such as pinyin.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package translit
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Table transliterates characters by looking up their readings, one syllable
+// per character. Consecutive readings are separated by spaces, which also
+// segments text written without spaces (such as Chinese) into words.
+type Table struct {
+	name     string
+	readings map[rune]string
+}
+
+// NewTable returns a transliterator that replaces each character in readings
+// with its reading.
+func NewTable(name string, readings map[rune]string) *Table {
+	copied := make(map[rune]string, len(readings))
+	for r, reading := range readings {
+		copied[r] = reading
+	}
+	return &Table{name: name, readings: copied}
+}
+
+// ParseTable reads a reading table with one character per line: the character
+// (or its code point as U+XXXX) followed by whitespace and its reading, e.g.
+// "中 zhong" or "U+4E2D\tzhōng". Only the first reading on a line is used, so
+// tables generated from Unihan's kMandarin field work as they are. Blank lines
+// and lines starting with '#' are ignored.
+func ParseTable(name string, r io.Reader) (*Table, error) {
+	readings := make(map[rune]string)
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("line %d: expected a character and its reading", line)
+		}
+		char, err := parseTableRune(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		readings[char] = fields[1]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read table: %v", err)
+	}
+	if len(readings) == 0 {
+		return nil, fmt.Errorf("table %s has no readings", name)
+	}
+	return &Table{name: name, readings: readings}, nil
+}
+
+// parseTableRune accepts a single character or a U+XXXX code point.
+func parseTableRune(field string) (rune, error) {
+	if hex, ok := strings.CutPrefix(strings.ToUpper(field), "U+"); ok {
+		n, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil || !utf8.ValidRune(rune(n)) {
+			return 0, fmt.Errorf("invalid code point %q", field)
+		}
+		return rune(n), nil
+	}
+	r, size := utf8.DecodeRuneInString(field)
+	if size != len(field) {
+		return 0, fmt.Errorf("expected one character, got %q", field)
+	}
+	return r, nil
+}
+
+// Name implements Transliterator.
+func (t *Table) Name() string {
+	return t.name
+}
+
+// Len returns the number of characters the table can read.
+func (t *Table) Len() int {
+	return len(t.readings)
+}
+
+// Transliterate implements Transliterator.
+func (t *Table) Transliterate(text string) (string, error) {
+	var b strings.Builder
+	prevReading := false // the last thing written was a reading
+	prevWord := false    // the last thing written was a letter or digit
+
+	for _, r := range text {
+		if reading, ok := t.readings[r]; ok {
+			if prevReading || prevWord {
+				b.WriteByte(' ')
+			}
+			b.WriteString(reading)
+			prevReading, prevWord = true, false
+			continue
+		}
+
+		word := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if word && prevReading {
+			b.WriteByte(' ')
+		}
+		if p, ok := chinesePunctuation[r]; ok {
+			b.WriteString(p)
+		} else {
+			b.WriteRune(r)
+		}
+		prevReading, prevWord = false, word
+	}
+	return b.String(), nil
+}
+
+// chinesePunctuation maps full-width punctuation onto ASCII.
+var chinesePunctuation = map[rune]string{
+	'。': ".", '，': ",", '、': ",", '！': "!", '？': "?", '：': ":", '；': ";",
+	'“': "\"", '”': "\"", '（': "(", '）': ")", '　': " ",
+}
@@ -0,0 +1,78 @@
+// Package translit provides pluggable transliteration of CJK text into small
+// alphabets, so Chinese and Japanese content can be encrypted without an
+// alphabet of thousands of characters.
+//
+// Transliteration is one-way: decrypting yields the romanized text, not the
+// original script. Homophones merge (several characters share a reading) and
+// characters a transliterator cannot read are passed through unchanged.
+//
+// Copyright (c) 2025 David Duarte
+// Licensed under the MIT License
+package translit
+
+import (
+	"sort"
+	"sync"
+	"unicode"
+)
+
+// Transliterator converts text into a romanized form.
+type Transliterator interface {
+	// Name identifies the transliterator, e.g. "romaji".
+	Name() string
+	// Transliterate romanizes the characters it knows and leaves the rest unchanged.
+	Transliterate(text string) (string, error)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Transliterator{}
+)
+
+func init() {
+	Register(Romaji())
+}
+
+// Register makes a transliterator available by name, replacing any previous
+// one with the same name.
+func Register(t Transliterator) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[t.Name()] = t
+}
+
+// Lookup returns the registered transliterator with the given name.
+func Lookup(name string) (Transliterator, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	t, ok := registry[name]
+	return t, ok
+}
+
+// Names returns the names of all registered transliterators, sorted.
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Remaining counts the Han, Hiragana and Katakana characters left in text,
+// which a transliterator could not read.
+func Remaining(text string) int {
+	n := 0
+	for _, r := range text {
+		if isCJK(r) {
+			n++
+		}
+	}
+	return n
+}
+
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r)
+}
@@ -0,0 +1,82 @@
+package translit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRomaji(t *testing.T) {
+	tests := map[string]string{
+		"こんにちは":   "konnichiha",
+		"とうきょう":   "toukyou",
+		"カタカナ":    "katakana",
+		"しゃしん":    "shashin",
+		"ちゃ":      "cha",
+		"じゅう":     "juu",
+		"がっこう":    "gakkou",
+		"まっちゃ":    "matcha",
+		"きんえん":    "kin'en",
+		"コーヒー":    "koohii",
+		"すし、さしみ。": "sushi,sashimi.",
+		"日本語です":   "日本語desu",
+		"Go言語":    "Go言語",
+	}
+	r := Romaji()
+	for in, want := range tests {
+		got, err := r.Transliterate(in)
+		if err != nil {
+			t.Fatalf("Transliterate(%q) failed: %v", in, err)
+		}
+		if got != want {
+			t.Errorf("Transliterate(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParseTable(t *testing.T) {
+	table, err := ParseTable("pinyin", strings.NewReader(`# sample readings
+中 zhong
+U+56FD	guo
+人 ren rén
+`))
+	if err != nil {
+		t.Fatalf("ParseTable failed: %v", err)
+	}
+	if table.Name() != "pinyin" || table.Len() != 3 {
+		t.Errorf("unexpected table %s with %d readings", table.Name(), table.Len())
+	}
+
+	got, _ := table.Transliterate("中国人，ok中国")
+	if want := "zhong guo ren,ok zhong guo"; got != want {
+		t.Errorf("Transliterate = %q, want %q", got, want)
+	}
+
+	for _, bad := range []string{"中", "中国 zhongguo", "U+ZZZZ x", "# only comments"} {
+		if _, err := ParseTable("bad", strings.NewReader(bad)); err == nil {
+			t.Errorf("ParseTable(%q) should fail", bad)
+		}
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	if r, ok := Lookup("romaji"); !ok || r.Name() != "romaji" {
+		t.Fatal("romaji should be registered by default")
+	}
+	Register(NewTable("test-table", map[rune]string{'字': "zi"}))
+	if _, ok := Lookup("test-table"); !ok {
+		t.Error("registered table not found")
+	}
+	names := strings.Join(Names(), ",")
+	if !strings.Contains(names, "romaji") || !strings.Contains(names, "test-table") {
+		t.Errorf("unexpected names %s", names)
+	}
+}
+
+func TestRemaining(t *testing.T) {
+	if n := Remaining("日本語desu"); n != 3 {
+		t.Errorf("Remaining = %d, want 3", n)
+	}
+	if n := Remaining("konnichiha"); n != 0 {
+		t.Errorf("Remaining = %d, want 0", n)
+	}
+}